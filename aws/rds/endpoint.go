@@ -0,0 +1,277 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	"gocloud.dev/internal/retry"
+)
+
+// EndpointOptions configures connections to RDS Proxy and Aurora cluster
+// endpoints.
+type EndpointOptions struct {
+	// Proxy reports whether the endpoint is an RDS Proxy. RDS Proxy presents
+	// certificates issued by AWS Certificate Manager, which are verified
+	// with the system root CAs instead of the RDS CA bundle. Endpoints with
+	// a "proxy-" label in their hostname, such as
+	// "myproxy.proxy-borkxyzzy.us-west-1.rds.amazonaws.com", are proxies
+	// even if Proxy is false.
+	Proxy bool
+
+	// AuroraEndpoint selects the endpoint of an Aurora cluster to connect
+	// to: "writer" for the cluster endpoint, or "reader" for the reader
+	// endpoint. The host must then be one of the two, and is replaced by
+	// the selected one. If empty, the host is used as is.
+	AuroraEndpoint string
+
+	// FailoverTimeout, if positive, is how long a new connection is retried
+	// for while the database fails over. See Failover.
+	FailoverTimeout time.Duration
+}
+
+// EndpointOptionsFromURLParams returns the options set by the URL
+// parameters in q, and the parameters in q that it did not use. The
+// parameters are:
+//   - rds_proxy: "true" if the endpoint is an RDS Proxy.
+//   - aurora_endpoint: "writer" or "reader".
+//   - failover_timeout: a duration such as "30s".
+func EndpointOptionsFromURLParams(q url.Values) (*EndpointOptions, url.Values, error) {
+	opts := new(EndpointOptions)
+	rest := url.Values{}
+	for param, values := range q {
+		var err error
+		switch param {
+		case "rds_proxy":
+			if opts.Proxy, err = strconv.ParseBool(values[0]); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for rds_proxy: %w", err)
+			}
+		case "aurora_endpoint":
+			opts.AuroraEndpoint = values[0]
+			if opts.AuroraEndpoint != "writer" && opts.AuroraEndpoint != "reader" {
+				return nil, nil, fmt.Errorf("invalid value for aurora_endpoint: %q; want writer or reader", opts.AuroraEndpoint)
+			}
+		case "failover_timeout":
+			if opts.FailoverTimeout, err = time.ParseDuration(values[0]); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for failover_timeout: %w", err)
+			}
+		default:
+			rest[param] = values
+		}
+	}
+	return opts, rest, nil
+}
+
+// Host returns the host to connect to for the URL host h, which may
+// include a port, selecting the Aurora endpoint if o.AuroraEndpoint is set.
+func (o *EndpointOptions) Host(h string) (string, error) {
+	if o.AuroraEndpoint == "" {
+		return h, nil
+	}
+	host, port, err := net.SplitHostPort(h)
+	if err != nil {
+		host, port = h, ""
+	}
+	// For example, mycluster.cluster-ro-borkxyzzy.us-west-1.rds.amazonaws.com.
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 || !strings.HasPrefix(labels[1], "cluster-") || strings.HasPrefix(labels[1], "cluster-custom-") {
+		return "", fmt.Errorf("aurora_endpoint: %q is not an Aurora cluster or reader endpoint", host)
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(labels[1], "cluster-"), "ro-")
+	if o.AuroraEndpoint == "reader" {
+		labels[1] = "cluster-ro-" + id
+	} else {
+		labels[1] = "cluster-" + id
+	}
+	host = strings.Join(labels, ".")
+	if port == "" {
+		return host, nil
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// IsProxy reports whether the host h, which may include a port, is an RDS
+// Proxy endpoint.
+func (o *EndpointOptions) IsProxy(h string) bool {
+	if o.Proxy {
+		return true
+	}
+	host, _, err := net.SplitHostPort(h)
+	if err != nil {
+		host = h
+	}
+	for _, label := range strings.Split(host, ".") {
+		if strings.HasPrefix(label, "proxy-") {
+			return true
+		}
+	}
+	return false
+}
+
+// CertPoolProvider returns the provider of the CA certificates that verify
+// connections to the host h: source, or if h is an RDS Proxy, a provider of
+// a nil pool, so that the system root CAs are used.
+func (o *EndpointOptions) CertPoolProvider(h string, source CertPoolProvider) CertPoolProvider {
+	if o.IsProxy(h) {
+		return systemRoots{}
+	}
+	return source
+}
+
+// systemRoots provides a nil certificate pool, which crypto/tls replaces
+// with the system root CAs.
+type systemRoots struct{}
+
+func (systemRoots) RDSCertPool(context.Context) (*x509.CertPool, error) { return nil, nil }
+
+// isWriter reports whether connections to the host h are meant to reach
+// the writer instance of an Aurora cluster.
+func (o *EndpointOptions) isWriter(h string) bool {
+	if o.AuroraEndpoint != "" {
+		return o.AuroraEndpoint == "writer"
+	}
+	host, _, err := net.SplitHostPort(h)
+	if err != nil {
+		host = h
+	}
+	labels := strings.Split(host, ".")
+	return len(labels) > 1 && strings.HasPrefix(labels[1], "cluster-") &&
+		!strings.HasPrefix(labels[1], "cluster-ro-") && !strings.HasPrefix(labels[1], "cluster-custom-")
+}
+
+// Failover returns a Failover for connections to the host h, or nil if
+// o.FailoverTimeout is not positive. readOnlyQuery is a query that returns
+// whether the database instance is read-only; it is used if h is a writer
+// endpoint.
+func (o *EndpointOptions) Failover(h, readOnlyQuery string) *Failover {
+	if o.FailoverTimeout <= 0 {
+		return nil
+	}
+	if !o.isWriter(h) {
+		readOnlyQuery = ""
+	}
+	return NewFailover(o.FailoverTimeout, readOnlyQuery)
+}
+
+// maxConcurrentConnects is how many connections a Failover opens at once.
+const maxConcurrentConnects = 4
+
+// errReadOnly is the error for a connection to a writer endpoint that
+// reached a read-only instance.
+var errReadOnly = errors.New("connected to a read-only instance through a writer endpoint")
+
+// A Failover opens new connections to a database that may be failing over.
+// It retries failed connections with backoff, and limits how many are
+// opened at once, so that a failover does not cause a storm of connection
+// attempts. During an Aurora failover the writer endpoint briefly resolves
+// to the old writer, which restarts as a reader; connections that reach a
+// read-only instance through a writer endpoint are closed and retried.
+//
+// It is safe to call from multiple goroutines.
+type Failover struct {
+	timeout       time.Duration
+	readOnlyQuery string
+	sem           chan struct{}
+}
+
+// NewFailover returns a Failover that retries a new connection for up to
+// timeout. If readOnlyQuery is not empty, it is run on each new connection,
+// which is retried if the query returns true.
+func NewFailover(timeout time.Duration, readOnlyQuery string) *Failover {
+	return &Failover{
+		timeout:       timeout,
+		readOnlyQuery: readOnlyQuery,
+		sem:           make(chan struct{}, maxConcurrentConnects),
+	}
+}
+
+// Connect opens a new connection by calling connect until it succeeds or
+// the failover timeout elapses.
+func (f *Failover) Connect(ctx context.Context, connect func(context.Context) (driver.Conn, error)) (driver.Conn, error) {
+	select {
+	case f.sem <- struct{}{}:
+		defer func() { <-f.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	var conn driver.Conn
+	err := retry.Call(ctx, gax.Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second}, func(error) bool { return true }, func() error {
+		var err error
+		if conn, err = connect(ctx); err != nil {
+			return err
+		}
+		if f.readOnlyQuery == "" {
+			return nil
+		}
+		readOnly, err := queryBool(ctx, conn, f.readOnlyQuery)
+		if err == nil && readOnly {
+			err = errReadOnly
+		}
+		if err != nil {
+			conn.Close()
+			conn = nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connect RDS: %v", err)
+	}
+	return conn, nil
+}
+
+// queryBool runs query on conn and returns its first column of its first
+// row as a boolean.
+func queryBool(ctx context.Context, conn driver.Conn, query string) (bool, error) {
+	q, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, fmt.Errorf("driver connection %T does not support queries", conn)
+	}
+	rows, err := q.QueryContext(ctx, query, nil)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(dest); err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("%q returned no rows", query)
+		}
+		return false, err
+	}
+	switch v := dest[0].(type) {
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case []byte:
+		return strconv.ParseBool(string(v))
+	case string:
+		return strconv.ParseBool(v)
+	}
+	return false, fmt.Errorf("%q returned %T, want a boolean", query, dest[0])
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestEndpointOptionsFromURLParams(t *testing.T) {
+	q := url.Values{
+		"rds_proxy":        {"true"},
+		"aurora_endpoint":  {"reader"},
+		"failover_timeout": {"30s"},
+		"parseTime":        {"true"},
+	}
+	opts, rest, err := EndpointOptionsFromURLParams(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := EndpointOptions{Proxy: true, AuroraEndpoint: "reader", FailoverTimeout: 30 * time.Second}
+	if *opts != want {
+		t.Errorf("got options %+v, want %+v", *opts, want)
+	}
+	if rest.Encode() != "parseTime=true" {
+		t.Errorf("got remaining params %v, want only parseTime", rest)
+	}
+
+	for _, bad := range []url.Values{
+		{"rds_proxy": {"maybe"}},
+		{"aurora_endpoint": {"primary"}},
+		{"failover_timeout": {"30"}},
+	} {
+		if _, _, err := EndpointOptionsFromURLParams(bad); err == nil {
+			t.Errorf("%v: got nil error", bad)
+		}
+	}
+}
+
+func TestEndpointOptionsHost(t *testing.T) {
+	const (
+		writer = "mycluster.cluster-borkxyzzy.us-west-1.rds.amazonaws.com"
+		reader = "mycluster.cluster-ro-borkxyzzy.us-west-1.rds.amazonaws.com"
+	)
+	tests := []struct {
+		endpoint, host, want string
+		wantErr              bool
+	}{
+		{endpoint: "", host: "localhost", want: "localhost"},
+		{endpoint: "reader", host: writer, want: reader},
+		{endpoint: "reader", host: reader + ":3306", want: reader + ":3306"},
+		{endpoint: "writer", host: reader, want: writer},
+		{endpoint: "writer", host: "myinstance.borkxyzzy.us-west-1.rds.amazonaws.com", wantErr: true},
+		{endpoint: "reader", host: "myendpoint.cluster-custom-borkxyzzy.us-west-1.rds.amazonaws.com", wantErr: true},
+	}
+	for _, test := range tests {
+		o := &EndpointOptions{AuroraEndpoint: test.endpoint}
+		got, err := o.Host(test.host)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s endpoint of %q: got error %v, want error %t", test.endpoint, test.host, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s endpoint of %q: got %q, want %q", test.endpoint, test.host, got, test.want)
+		}
+	}
+
+	o := new(EndpointOptions)
+	if !o.isWriter(writer+":5432") || o.isWriter(reader) {
+		t.Error("isWriter does not recognize the cluster endpoints")
+	}
+}
+
+func TestEndpointOptionsProxy(t *testing.T) {
+	source := new(CertFetcher)
+	o := new(EndpointOptions)
+	if o.IsProxy(testEndpoint) || o.CertPoolProvider(testEndpoint, source) != source {
+		t.Errorf("%s is treated as a proxy", testEndpoint)
+	}
+	const proxy = "myproxy.proxy-borkxyzzy.us-west-1.rds.amazonaws.com:3306"
+	if !o.IsProxy(proxy) {
+		t.Errorf("%s is not treated as a proxy", proxy)
+	}
+	o.Proxy = true
+	pool, err := o.CertPoolProvider(testEndpoint, source).RDSCertPool(context.Background())
+	if err != nil || pool != nil {
+		t.Errorf("proxy cert pool: got %v, %v; want the system roots", pool, err)
+	}
+}
+
+// fakeConn is a driver connection whose read-only query returns readOnly.
+type fakeConn struct {
+	readOnly bool
+	closed   bool
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (c *fakeConn) Close() error                        { c.closed = true; return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{value: c.readOnly}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"read_only"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestFailover(t *testing.T) {
+	ctx := context.Background()
+
+	// Connections are retried until one succeeds and reaches a writer.
+	var attempts int
+	var rejected []*fakeConn
+	f := NewFailover(time.Minute, "SELECT read_only")
+	conn, err := f.Connect(ctx, func(context.Context) (driver.Conn, error) {
+		attempts++
+		switch attempts {
+		case 1:
+			return nil, errors.New("connection refused")
+		case 2:
+			c := &fakeConn{readOnly: true}
+			rejected = append(rejected, c)
+			return c, nil
+		}
+		return &fakeConn{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || conn.(*fakeConn).readOnly {
+		t.Errorf("got a connection after %d attempts, want a writable one after 3", attempts)
+	}
+	if len(rejected) != 1 || !rejected[0].closed {
+		t.Error("the connection to a read-only instance was not closed")
+	}
+
+	// Without a read-only query, any connection is accepted.
+	f = NewFailover(time.Minute, "")
+	if _, err := f.Connect(ctx, func(context.Context) (driver.Conn, error) { return &fakeConn{readOnly: true}, nil }); err != nil {
+		t.Error(err)
+	}
+
+	// Retries stop after the timeout.
+	f = NewFailover(50*time.Millisecond, "")
+	if _, err := f.Connect(ctx, func(context.Context) (driver.Conn, error) { return nil, errors.New("connection refused") }); err == nil {
+		t.Error("got nil error after the timeout")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	gcaws "gocloud.dev/aws"
+)
+
+const (
+	// authTokenLifetime is how long RDS accepts an IAM authentication token
+	// for after it is generated.
+	authTokenLifetime = 15 * time.Minute
+	// authTokenRefresh is how long before a cached token expires it is
+	// replaced, so that a connection never starts with a token that is
+	// about to expire.
+	authTokenRefresh = 5 * time.Minute
+
+	// emptyPayloadHash is the SHA-256 hash of an empty request body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// An AuthTokenGenerator generates IAM database authentication tokens, which
+// RDS accepts instead of passwords for database users that have IAM
+// authentication enabled. Tokens expire after 15 minutes, or when the
+// temporary credentials that signed them expire if that is sooner; the
+// generator caches each token and generates a new one 5 minutes before it
+// expires, so call AuthToken for every new connection.
+//
+// It is safe to call from multiple goroutines.
+type AuthTokenGenerator struct {
+	// Credentials signs the tokens. It is required.
+	Credentials awsv2.CredentialsProvider
+	// Region is the AWS region of the database. If empty, it is taken from
+	// the endpoint's hostname, for example "us-west-1" for
+	// "myinstance.borkxyzzy.us-west-1.rds.amazonaws.com".
+	Region string
+
+	mu     sync.Mutex
+	tokens map[string]cachedAuthToken // keyed by endpoint and user
+	now    func() time.Time           // for testing; time.Now if nil
+}
+
+type cachedAuthToken struct {
+	token   string
+	expires time.Time
+}
+
+// NewAuthTokenGenerator returns a generator that signs tokens with the
+// credentials of cfg, for databases in cfg.Region.
+func NewAuthTokenGenerator(cfg awsv2.Config) *AuthTokenGenerator {
+	return &AuthTokenGenerator{Credentials: cfg.Credentials, Region: cfg.Region}
+}
+
+// AuthTokenGeneratorFromURLParams returns a generator if the "iam_auth" URL
+// parameter in q is true, and nil otherwise. The generator's credentials and
+// region are configured by the "region" and "profile" parameters, as
+// described in gocloud.dev/aws.V2ConfigFromURLParams. It also returns the
+// parameters in q that it did not use; if it returns a nil generator, that
+// is q itself.
+func AuthTokenGeneratorFromURLParams(ctx context.Context, q url.Values) (*AuthTokenGenerator, url.Values, error) {
+	iamAuth := false
+	awsParams := url.Values{}
+	rest := url.Values{}
+	for param, values := range q {
+		switch param {
+		case "iam_auth":
+			var err error
+			if iamAuth, err = strconv.ParseBool(values[0]); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for iam_auth: %w", err)
+			}
+		case "region", "profile":
+			awsParams[param] = values
+		default:
+			rest[param] = values
+		}
+	}
+	if !iamAuth {
+		return nil, q, nil
+	}
+	cfg, err := gcaws.V2ConfigFromURLParams(ctx, awsParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewAuthTokenGenerator(cfg), rest, nil
+}
+
+// AuthToken returns a token for user to connect to the database at
+// endpoint, which is a "host:port" address. It returns a cached token if one
+// is valid for long enough.
+func (g *AuthTokenGenerator) AuthToken(ctx context.Context, endpoint, user string) (string, error) {
+	now := time.Now
+	if g.now != nil {
+		now = g.now
+	}
+	key := endpoint + "\x00" + user
+	t := now()
+	g.mu.Lock()
+	cached, ok := g.tokens[key]
+	g.mu.Unlock()
+	if ok && t.Before(cached.expires.Add(-authTokenRefresh)) {
+		return cached.token, nil
+	}
+
+	region := g.Region
+	if region == "" {
+		var err error
+		if region, err = regionFromEndpoint(endpoint); err != nil {
+			return "", err
+		}
+	}
+	token, expires, err := buildAuthToken(ctx, endpoint, region, user, g.Credentials, t)
+	if err != nil {
+		return "", err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.tokens == nil {
+		g.tokens = make(map[string]cachedAuthToken)
+	}
+	g.tokens[key] = cachedAuthToken{token: token, expires: expires}
+	return token, nil
+}
+
+// BuildAuthToken returns a new IAM authentication token for user to connect
+// to the database at endpoint, a "host:port" address, in region, signed with
+// creds. Most callers should use an AuthTokenGenerator, which reuses tokens.
+func BuildAuthToken(ctx context.Context, endpoint, region, user string, creds awsv2.CredentialsProvider) (string, error) {
+	token, _, err := buildAuthToken(ctx, endpoint, region, user, creds, time.Now())
+	return token, err
+}
+
+// buildAuthToken returns a new token signed at signingTime, and the time it
+// stops being accepted: after authTokenLifetime, or when the credentials
+// that signed it expire, whichever is sooner.
+func buildAuthToken(ctx context.Context, endpoint, region, user string, creds awsv2.CredentialsProvider, signingTime time.Time) (string, time.Time, error) {
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return "", time.Time{}, fmt.Errorf("build RDS auth token: endpoint must be host:port: %v", err)
+	}
+	if creds == nil {
+		return "", time.Time{}, fmt.Errorf("build RDS auth token: no credentials")
+	}
+	c, err := creds.Retrieve(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build RDS auth token: retrieve credentials: %v", err)
+	}
+	expires := signingTime.Add(authTokenLifetime)
+	if c.CanExpire && c.Expires.Before(expires) {
+		expires = c.Expires
+	}
+	q := url.Values{
+		"Action":        {"connect"},
+		"DBUser":        {user},
+		"X-Amz-Expires": {strconv.Itoa(int(authTokenLifetime.Seconds()))},
+	}
+	req, err := http.NewRequest(http.MethodGet, "https://"+endpoint+"/?"+q.Encode(), nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build RDS auth token: %v", err)
+	}
+	signed, _, err := v4.NewSigner().PresignHTTP(ctx, c, req, emptyPayloadHash, "rds-db", region, signingTime)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build RDS auth token: %v", err)
+	}
+	return strings.TrimPrefix(signed, "https://"), expires, nil
+}
+
+// regionFromEndpoint returns the region in an RDS endpoint's hostname.
+func regionFromEndpoint(endpoint string) (string, error) {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = endpoint
+	}
+	// For example, myinstance.borkxyzzy.us-west-1.rds.amazonaws.com.
+	parts := strings.Split(host, ".")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "rds" {
+			return parts[i-1], nil
+		}
+	}
+	return "", fmt.Errorf("build RDS auth token: no region configured, and none in endpoint %q", endpoint)
+}
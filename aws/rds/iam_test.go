@@ -0,0 +1,154 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+const testEndpoint = "myinstance.borkxyzzy.us-west-1.rds.amazonaws.com:3306"
+
+func TestBuildAuthToken(t *testing.T) {
+	creds := credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")
+	token, err := BuildAuthToken(context.Background(), testEndpoint, "us-west-1", "alice", creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, testEndpoint+"/?") {
+		t.Fatalf("token %q does not start with the endpoint", token)
+	}
+	u, err := url.Parse("https://" + token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	for param, want := range map[string]string{
+		"Action":        "connect",
+		"DBUser":        "alice",
+		"X-Amz-Expires": "900",
+	} {
+		if got := q.Get(param); got != want {
+			t.Errorf("%s = %q, want %q", param, got, want)
+		}
+	}
+	if got := q.Get("X-Amz-Credential"); !strings.HasSuffix(got, "/us-west-1/rds-db/aws4_request") {
+		t.Errorf("X-Amz-Credential = %q, want the us-west-1 rds-db scope", got)
+	}
+	if q.Get("X-Amz-Signature") == "" {
+		t.Error("token is not signed")
+	}
+
+	if _, err := BuildAuthToken(context.Background(), "no-port", "us-west-1", "alice", creds); err == nil {
+		t.Error("endpoint without a port: got nil error")
+	}
+}
+
+func TestAuthTokenGenerator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	g := &AuthTokenGenerator{
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		now:         func() time.Time { return now },
+	}
+	first, err := g.AuthToken(ctx, testEndpoint, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The region comes from the endpoint.
+	if !strings.Contains(first, "%2Fus-west-1%2Frds-db%2F") {
+		t.Errorf("token %q is not scoped to us-west-1", first)
+	}
+
+	now = now.Add(9 * time.Minute)
+	if got, err := g.AuthToken(ctx, testEndpoint, "alice"); err != nil || got != first {
+		t.Errorf("after 9 minutes: got a new token (err %v), want the cached one", err)
+	}
+	if got, err := g.AuthToken(ctx, testEndpoint, "bob"); err != nil || got == first {
+		t.Errorf("other user: got the cached token (err %v), want a new one", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if got, err := g.AuthToken(ctx, testEndpoint, "alice"); err != nil || got == first {
+		t.Errorf("after 11 minutes: got the cached token (err %v), want a new one", err)
+	}
+
+	g.Region = ""
+	if _, err := g.AuthToken(ctx, "localhost:3306", "alice"); err == nil {
+		t.Error("no region: got nil error")
+	}
+}
+
+func TestAuthTokenGeneratorExpiringCredentials(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	g := &AuthTokenGenerator{
+		Credentials: awsv2.CredentialsProviderFunc(func(context.Context) (awsv2.Credentials, error) {
+			return awsv2.Credentials{
+				AccessKeyID:     "AKIDEXAMPLE",
+				SecretAccessKey: "secret",
+				SessionToken:    "session",
+				CanExpire:       true,
+				Expires:         now.Add(8 * time.Minute),
+			}, nil
+		}),
+		now: func() time.Time { return now },
+	}
+	first, err := g.AuthToken(ctx, testEndpoint, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if got, err := g.AuthToken(ctx, testEndpoint, "alice"); err != nil || got != first {
+		t.Errorf("after 2 minutes: got a new token (err %v), want the cached one", err)
+	}
+	// The credentials expire in less than 5 minutes, so the token must be
+	// replaced even though it was signed only 4 minutes ago.
+	now = now.Add(2 * time.Minute)
+	if got, err := g.AuthToken(ctx, testEndpoint, "alice"); err != nil || got == first {
+		t.Errorf("after 4 minutes: got the cached token (err %v), want a new one", err)
+	}
+}
+
+func TestAuthTokenGeneratorFromURLParams(t *testing.T) {
+	ctx := context.Background()
+	q := url.Values{"region": {"us-east-2"}, "parseTime": {"true"}}
+	g, rest, err := AuthTokenGeneratorFromURLParams(ctx, q)
+	if err != nil || g != nil || len(rest) != 2 {
+		t.Errorf("without iam_auth: got %v, %v, %v; want nil generator and all params", g, rest, err)
+	}
+
+	q.Set("iam_auth", "true")
+	g, rest, err = AuthTokenGeneratorFromURLParams(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g == nil || g.Region != "us-east-2" {
+		t.Errorf("got generator %+v, want one for us-east-2", g)
+	}
+	if want := (url.Values{"parseTime": {"true"}}); rest.Encode() != want.Encode() {
+		t.Errorf("got remaining params %v, want %v", rest, want)
+	}
+
+	q.Set("iam_auth", "maybe")
+	if _, _, err := AuthTokenGeneratorFromURLParams(ctx, q); err == nil {
+		t.Error("invalid iam_auth: got nil error")
+	}
+}
@@ -26,7 +26,14 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 )
 
-const caBundleURL = "https://www.digicert.com/CACerts/BaltimoreCyberTrustRoot.crt.pem"
+// caBundleURLs are the URLs of the root certificates that Azure Database
+// servers' certificates chain to. Servers are migrating from the Baltimore
+// root to the DigiCert Global roots.
+var caBundleURLs = []string{
+	"https://www.digicert.com/CACerts/BaltimoreCyberTrustRoot.crt.pem",
+	"https://cacerts.digicert.com/DigiCertGlobalRootCA.crt.pem",
+	"https://cacerts.digicert.com/DigiCertGlobalRootG2.crt.pem",
+}
 
 // A CertPoolProvider returns a certificate pool that contains the Azure CA certificate.
 type CertPoolProvider interface {
@@ -61,7 +68,20 @@ func (cf *CertFetcher) Fetch(ctx context.Context) ([]*x509.Certificate, error) {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	resp, err := ctxhttp.Get(ctx, client, caBundleURL)
+	var certs []*x509.Certificate
+	for _, u := range caBundleURLs {
+		c, err := fetchCerts(ctx, client, u)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c...)
+	}
+	return certs, nil
+}
+
+// fetchCerts fetches the PEM-encoded certificates at u.
+func fetchCerts(ctx context.Context, client *http.Client, u string) ([]*x509.Certificate, error) {
+	resp, err := ctxhttp.Get(ctx, client, u)
 	if err != nil {
 		return nil, fmt.Errorf("fetch Azure certificates: %v", err)
 	}
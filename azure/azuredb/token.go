@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuredb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const (
+	// tokenScope is the scope of Microsoft Entra ID (formerly Azure AD)
+	// access tokens for Azure Database for MySQL and PostgreSQL.
+	tokenScope = "https://ossrdbms-aad.database.windows.net/.default"
+	// tokenRefresh is how long before a cached token expires it is
+	// replaced, so that a connection never starts with a token that is
+	// about to expire.
+	tokenRefresh = 5 * time.Minute
+)
+
+// A TokenSource provides Microsoft Entra ID (formerly Azure AD) access
+// tokens, which Azure Database for MySQL and PostgreSQL accept instead of
+// passwords for users that are Entra ID principals. It caches each token
+// and requests a new one 5 minutes before it expires, so call Token for
+// every new connection.
+//
+// It is safe to call from multiple goroutines.
+type TokenSource struct {
+	cred azcore.TokenCredential
+
+	mu    sync.Mutex
+	token azcore.AccessToken
+	now   func() time.Time // for testing; time.Now if nil
+}
+
+// NewTokenSource returns a token source that gets tokens from cred.
+func NewTokenSource(cred azcore.TokenCredential) *TokenSource {
+	return &TokenSource{cred: cred}
+}
+
+// TokenSourceFromURLParams returns a token source using the default Azure
+// credential, as returned by azidentity.NewDefaultAzureCredential, if the
+// "aad_auth" URL parameter in q is true, and nil otherwise. It also returns
+// the parameters in q that it did not use.
+func TokenSourceFromURLParams(q url.Values) (*TokenSource, url.Values, error) {
+	values, ok := q["aad_auth"]
+	if !ok {
+		return nil, q, nil
+	}
+	rest := url.Values{}
+	for param, v := range q {
+		if param != "aad_auth" {
+			rest[param] = v
+		}
+	}
+	aadAuth, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for aad_auth: %w", err)
+	}
+	if !aadAuth {
+		return nil, rest, nil
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create default Azure credential: %w", err)
+	}
+	return NewTokenSource(cred), rest, nil
+}
+
+// Token returns an access token to use as a database password. It returns
+// a cached token if one is valid for long enough.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	now := time.Now
+	if ts.now != nil {
+		now = ts.now
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token.Token != "" && now().Before(ts.token.ExpiresOn.Add(-tokenRefresh)) {
+		return ts.token.Token, nil
+	}
+	tok, err := ts.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{tokenScope}})
+	if err != nil {
+		return "", fmt.Errorf("get Azure database access token: %w", err)
+	}
+	ts.token = tok
+	return tok.Token, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuredb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// fakeCredential returns a new token valid for an hour on each call.
+type fakeCredential struct {
+	now    func() time.Time
+	calls  int
+	scopes []string
+}
+
+func (c *fakeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.calls++
+	c.scopes = opts.Scopes
+	return azcore.AccessToken{Token: fmt.Sprintf("token%d", c.calls), ExpiresOn: c.now().Add(time.Hour)}, nil
+}
+
+func TestTokenSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+	cred := &fakeCredential{now: clock}
+	ts := NewTokenSource(cred)
+	ts.now = clock
+
+	for _, test := range []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, "token1"},
+		{50 * time.Minute, "token1"}, // cached
+		{6 * time.Minute, "token2"},  // within 5 minutes of expiry
+	} {
+		now = now.Add(test.after)
+		got, err := ts.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("after another %v: got %q, want %q", test.after, got, test.want)
+		}
+	}
+	if len(cred.scopes) != 1 || cred.scopes[0] != tokenScope {
+		t.Errorf("got scopes %v, want [%s]", cred.scopes, tokenScope)
+	}
+}
+
+func TestTokenSourceFromURLParams(t *testing.T) {
+	q := url.Values{"foo": {"bar"}}
+	ts, rest, err := TokenSourceFromURLParams(q)
+	if err != nil || ts != nil || rest.Encode() != q.Encode() {
+		t.Errorf("without aad_auth: got %v, %v, %v; want nil token source and all params", ts, rest, err)
+	}
+	q.Set("aad_auth", "false")
+	ts, rest, err = TokenSourceFromURLParams(q)
+	if err != nil || ts != nil || rest.Encode() != "foo=bar" {
+		t.Errorf("aad_auth=false: got %v, %v, %v; want nil token source and the other params", ts, rest, err)
+	}
+	q.Set("aad_auth", "maybe")
+	if _, _, err := TokenSourceFromURLParams(q); err == nil {
+		t.Error("invalid aad_auth: got nil error")
+	}
+}
@@ -457,12 +457,76 @@ func (b *bucket) Close() error {
 	return nil
 }
 
+// escapeMetadata returns md with its keys and values escaped.
+func escapeMetadata(md map[string]string) (map[string]*string, error) {
+	escaped := make(map[string]*string, len(md))
+	for k, v := range md {
+		// See the package comments for more details on escaping of metadata
+		// keys & values.
+		e := escape.HexEscape(k, func(runes []rune, i int) bool {
+			c := runes[i]
+			switch {
+			case i == 0 && c >= '0' && c <= '9':
+				return true
+			case escape.IsASCIIAlphanumeric(c):
+				return false
+			case c == '_':
+				return false
+			}
+			return true
+		})
+		if _, ok := escaped[e]; ok {
+			return nil, fmt.Errorf("duplicate keys after escaping: %q => %q", k, e)
+		}
+		ev := escape.URLEscape(v)
+		escaped[e] = &ev
+	}
+	return escaped, nil
+}
+
 // Copy implements driver.Copy.
 func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	srcBlobClient := b.client.NewBlobClient(escapeKey(srcKey, false))
+	return b.copy(ctx, dstKey, srcBlobClient.URL(), opts)
+}
+
+// copySourceExpiry is the expiry of the signed URLs of the source blobs
+// copied from other storage accounts.
+const copySourceExpiry = time.Hour
+
+// CopyFrom implements driver.BucketCopier.CopyFrom.
+func (b *bucket) CopyFrom(ctx context.Context, dstKey string, src driver.Bucket, srcKey string, opts *driver.CopyOptions) error {
+	srcBucket, ok := src.(*bucket)
+	if !ok {
+		return gcerr.Newf(gcerr.Unimplemented, nil, "azureblob: CopyFrom is only supported from another azureblob bucket")
+	}
+	srcURL := srcBucket.client.NewBlobClient(escapeKey(srcKey, false)).URL()
+	// The service reads blobs of the same storage account with the
+	// credentials of the copy request. Blobs of other storage accounts must
+	// be public, or read with a SAS.
+	if u, err := url.Parse(srcURL); err == nil && !strings.EqualFold(u.Host, b.serviceHost()) {
+		signed, err := srcBucket.SignedURL(ctx, srcKey, &driver.SignedURLOptions{Method: http.MethodGet, Expiry: copySourceExpiry})
+		if err == nil {
+			srcURL = signed
+		}
+	}
+	return b.copy(ctx, dstKey, srcURL, opts)
+}
+
+// serviceHost returns the host of the storage account of b.
+func (b *bucket) serviceHost() string {
+	u, err := url.Parse(b.client.URL())
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// copy copies the blob at srcURL to dstKey in b, and waits until the copy is
+// complete.
+func (b *bucket) copy(ctx context.Context, dstKey, srcURL string, opts *driver.CopyOptions) error {
 	dstKey = escapeKey(dstKey, false)
 	dstBlobClient := b.client.NewBlobClient(dstKey)
-	srcKey = escapeKey(srcKey, false)
-	srcBlobClient := b.client.NewBlobClient(srcKey)
 	copyOptions := &azblobblob.StartCopyFromURLOptions{}
 	if opts.BeforeCopy != nil {
 		asFunc := func(i interface{}) bool {
@@ -477,7 +541,7 @@ func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.C
 			return err
 		}
 	}
-	resp, err := dstBlobClient.StartCopyFromURL(ctx, srcBlobClient.URL(), copyOptions)
+	resp, err := dstBlobClient.StartCopyFromURL(ctx, srcURL, copyOptions)
 	if err != nil {
 		return err
 	}
@@ -511,6 +575,53 @@ func (b *bucket) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+// maxBatchSize is the largest number of sub-requests of a blob batch.
+const maxBatchSize = 256
+
+// DeleteBatch implements driver.BatchDeleter.DeleteBatch.
+func (b *bucket) DeleteBatch(ctx context.Context, keys []string) ([]error, error) {
+	errs := make([]error, len(keys))
+	deleted := false
+	for start := 0; start < len(keys); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		resp, err := b.deleteBatch(ctx, keys[start:end])
+		if err != nil {
+			if !deleted {
+				return nil, err
+			}
+			for i := start; i < end; i++ {
+				errs[i] = err
+			}
+			continue
+		}
+		deleted = true
+		for _, item := range resp.Responses {
+			// The ContentID of a response is the index of its sub-request.
+			if item.ContentID != nil && *item.ContentID < end-start {
+				errs[start+*item.ContentID] = item.Error
+			}
+		}
+	}
+	return errs, nil
+}
+
+// deleteBatch deletes keys with one blob batch.
+func (b *bucket) deleteBatch(ctx context.Context, keys []string) (container.SubmitBatchResponse, error) {
+	bb, err := b.client.NewBatchBuilder()
+	if err != nil {
+		return container.SubmitBatchResponse{}, gcerr.Newf(gcerr.Unimplemented, err, "azureblob: blob batches are not supported with these credentials")
+	}
+	for _, key := range keys {
+		if err := bb.Delete(escapeKey(key, false), nil); err != nil {
+			return container.SubmitBatchResponse{}, err
+		}
+	}
+	return b.client.SubmitBatch(ctx, bb, nil)
+}
+
 // reader reads an azblob. It implements io.ReadCloser.
 type reader struct {
 	body  io.ReadCloser
@@ -875,27 +986,9 @@ func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, op
 		opts.MaxConcurrency = defaultUploadBuffers
 	}
 
-	md := make(map[string]*string, len(opts.Metadata))
-	for k, v := range opts.Metadata {
-		// See the package comments for more details on escaping of metadata
-		// keys & values.
-		e := escape.HexEscape(k, func(runes []rune, i int) bool {
-			c := runes[i]
-			switch {
-			case i == 0 && c >= '0' && c <= '9':
-				return true
-			case escape.IsASCIIAlphanumeric(c):
-				return false
-			case c == '_':
-				return false
-			}
-			return true
-		})
-		if _, ok := md[e]; ok {
-			return nil, fmt.Errorf("duplicate keys after escaping: %q => %q", k, e)
-		}
-		escaped := escape.URLEscape(v)
-		md[e] = &escaped
+	md, err := escapeMetadata(opts.Metadata)
+	if err != nil {
+		return nil, err
 	}
 	uploadOpts := &azblob.UploadStreamOptions{
 		BlockSize:   int64(opts.BufferSize),
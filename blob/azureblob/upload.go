@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureblob
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	azblobblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// maxBlocks is the largest number of blocks of a block blob.
+const maxBlocks = 50000
+
+// uploadState is the state of a resumable upload. Azure keeps the blocks
+// staged for a blob without an upload to begin, so the state holds the
+// prefix of the IDs of the blocks, and the attributes of the blob to set
+// when the blocks are committed.
+type uploadState struct {
+	ID                 string             `json:"id"`
+	CacheControl       string             `json:"cache_control,omitempty"`
+	ContentDisposition string             `json:"content_disposition,omitempty"`
+	ContentEncoding    string             `json:"content_encoding,omitempty"`
+	ContentLanguage    string             `json:"content_language,omitempty"`
+	ContentType        string             `json:"content_type"`
+	Metadata           map[string]*string `json:"metadata,omitempty"`
+}
+
+// blockID returns the ID of the block with index part. All the IDs of the
+// blocks of a blob must have the same length.
+func (s *uploadState) blockID(part int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s-%05d", s.ID, part)))
+}
+
+func parseUploadState(state []byte) (*uploadState, error) {
+	var s uploadState
+	if err := json.Unmarshal(state, &s); err != nil || s.ID == "" {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "azureblob: invalid upload state")
+	}
+	return &s, nil
+}
+
+// UploadPartSize implements driver.ResumableUploader.UploadPartSize.
+func (b *bucket) UploadPartSize() int64 { return 1 }
+
+// BeginUpload implements driver.ResumableUploader.BeginUpload.
+func (b *bucket) BeginUpload(ctx context.Context, key, contentType string, opts *driver.WriterOptions) ([]byte, error) {
+	if opts.BeforeWrite != nil {
+		if err := opts.BeforeWrite(func(interface{}) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+	md, err := escapeMetadata(opts.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&uploadState{
+		ID:                 hex.EncodeToString(id),
+		CacheControl:       opts.CacheControl,
+		ContentDisposition: opts.ContentDisposition,
+		ContentEncoding:    opts.ContentEncoding,
+		ContentLanguage:    opts.ContentLanguage,
+		ContentType:        contentType,
+		Metadata:           md,
+	})
+}
+
+// UploadPart implements driver.ResumableUploader.UploadPart.
+func (b *bucket) UploadPart(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	s, err := parseUploadState(state)
+	if err != nil {
+		return err
+	}
+	return b.stageBlock(ctx, key, s, part, p)
+}
+
+func (b *bucket) stageBlock(ctx context.Context, key string, s *uploadState, part int, p []byte) error {
+	if part >= maxBlocks {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "azureblob: uploads cannot have more than %d parts", maxBlocks)
+	}
+	blobClient := b.client.NewBlockBlobClient(escapeKey(key, false))
+	_, err := blobClient.StageBlock(ctx, s.blockID(part), streaming.NopCloser(bytes.NewReader(p)), nil)
+	return err
+}
+
+// CompleteUpload implements driver.ResumableUploader.CompleteUpload.
+func (b *bucket) CompleteUpload(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	s, err := parseUploadState(state)
+	if err != nil {
+		return err
+	}
+	if len(p) > 0 {
+		if err := b.stageBlock(ctx, key, s, part, p); err != nil {
+			return err
+		}
+		part++
+	}
+	ids := make([]string, part)
+	for i := range ids {
+		ids[i] = s.blockID(i)
+	}
+	blobClient := b.client.NewBlockBlobClient(escapeKey(key, false))
+	_, err = blobClient.CommitBlockList(ctx, ids, &blockblob.CommitBlockListOptions{
+		Metadata: s.Metadata,
+		HTTPHeaders: &azblobblob.HTTPHeaders{
+			BlobCacheControl:       &s.CacheControl,
+			BlobContentDisposition: &s.ContentDisposition,
+			BlobContentEncoding:    &s.ContentEncoding,
+			BlobContentLanguage:    &s.ContentLanguage,
+			BlobContentType:        &s.ContentType,
+		},
+	})
+	return err
+}
+
+// AbortUpload implements driver.ResumableUploader.AbortUpload. Azure has no
+// request to delete staged blocks; they are deleted by the service if they
+// are not committed within a week.
+func (b *bucket) AbortUpload(ctx context.Context, key string, state []byte) error {
+	_, err := parseUploadState(state)
+	return err
+}
@@ -31,6 +31,13 @@
 // The Bucket.ErrorAs method can retrieve the driver error underlying the returned
 // error.
 //
+// # OpenTelemetry Integration
+//
+// This API reports the method calls below as OpenTelemetry spans, and as the
+// metric "gocdk.blob.duration", to the global tracer and meter providers.
+// See https://pkg.go.dev/gocloud.dev/telemetry for the span and metric
+// names, and for configuring exporters.
+//
 // # OpenCensus Integration
 //
 // OpenCensus supports tracing and metric collection for multiple languages and
@@ -83,11 +90,14 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/metric"
 	"gocloud.dev/blob/driver"
+	"gocloud.dev/callopt"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/gcerr"
 	"gocloud.dev/internal/oc"
 	"gocloud.dev/internal/openurl"
+	"gocloud.dev/internal/telemetry"
 )
 
 // Ensure that Reader implements io.ReadSeekCloser.
@@ -109,6 +119,7 @@ type Reader struct {
 	end            func(error)           // Called at Close to finish trace and metric collection.
 	// for metric collection;
 	statsTagMutators []tag.Mutator
+	provider         string
 	bytesRead        int
 	closed           bool
 }
@@ -204,6 +215,8 @@ func (r *Reader) Close() error {
 		context.Background(),
 		r.statsTagMutators,
 		bytesReadMeasure.M(int64(r.bytesRead)))
+	bytesReadCounter.Add(context.Background(), int64(r.bytesRead),
+		metric.WithAttributes(telemetry.ProviderKey.String(r.provider)))
 	return err
 }
 
@@ -366,6 +379,7 @@ type Writer struct {
 	contentMD5       []byte
 	md5hash          hash.Hash
 	statsTagMutators []tag.Mutator // for metric collection
+	provider         string        // for metric collection
 	bytesWritten     int
 	closed           bool
 
@@ -438,6 +452,8 @@ func (w *Writer) Close() (err error) {
 			context.Background(),
 			w.statsTagMutators,
 			bytesWrittenMeasure.M(int64(w.bytesWritten)))
+		bytesWrittenCounter.Add(context.Background(), int64(w.bytesWritten),
+			metric.WithAttributes(telemetry.ProviderKey.String(w.provider)))
 	}()
 	if len(w.contentMD5) > 0 {
 		// Verify the MD5 hash of what was written matches the ContentMD5 provided
@@ -642,7 +658,7 @@ func (o *ListObject) As(i interface{}) bool {
 // To create a Bucket, use constructors found in driver subpackages.
 type Bucket struct {
 	b      driver.Bucket
-	tracer *oc.Tracer
+	tracer *telemetry.Tracer
 
 	// ioFSCallback is set via SetIOFSCallback, which must be
 	// called before calling various functions implementing interfaces
@@ -683,6 +699,9 @@ var (
 			TagKeys:     []tag.Key{oc.ProviderKey},
 			Aggregation: view.Sum(),
 		})
+
+	bytesReadCounter    = telemetry.Int64Counter(pkgName, "bytes_read", "Total bytes read from the service.", "By")
+	bytesWrittenCounter = telemetry.Int64Counter(pkgName, "bytes_written", "Total bytes written to the service.", "By")
 )
 
 // NewBucket is intended for use by drivers only. Do not use in application code.
@@ -695,11 +714,7 @@ func newBucket(b driver.Bucket) *Bucket {
 	return &Bucket{
 		b:            b,
 		ioFSCallback: func() (context.Context, *ReaderOptions) { return context.Background(), nil },
-		tracer: &oc.Tracer{
-			Package:        pkgName,
-			Provider:       oc.ProviderName(b),
-			LatencyMeasure: latencyMeasure,
-		},
+		tracer:       telemetry.NewTracer(pkgName, telemetry.ProviderName(b), latencyMeasure, telemetry.DriverAttributes(b)...),
 	}
 }
 
@@ -827,6 +842,8 @@ func (b *Bucket) ListPage(ctx context.Context, pageToken []byte, pageSize int, o
 		return nil, nil, errClosed
 	}
 
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = b.tracer.Start(ctx, "ListPage")
 	defer func() { b.tracer.End(ctx, err) }()
 
@@ -911,6 +928,8 @@ func (b *Bucket) Attributes(ctx context.Context, key string) (_ *Attributes, err
 	if b.closed {
 		return nil, errClosed
 	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = b.tracer.Start(ctx, "Attributes")
 	defer func() { b.tracer.End(ctx, err) }()
 
@@ -987,12 +1006,14 @@ func (b *Bucket) newRangeReader(ctx context.Context, key string, offset, length
 	dopts := &driver.ReaderOptions{
 		BeforeRead: opts.BeforeRead,
 	}
+	ctx, cancel := callopt.Context(ctx)
 	tctx := b.tracer.Start(ctx, "NewRangeReader")
 	defer func() {
 		// If err == nil, we handed the end closure off to the returned *Reader; it
 		// will be called when the Reader is Closed.
 		if err != nil {
 			b.tracer.End(tctx, err)
+			cancel()
 		}
 	}()
 	var dr driver.Reader
@@ -1000,7 +1021,10 @@ func (b *Bucket) newRangeReader(ctx context.Context, key string, offset, length
 	if err != nil {
 		return nil, wrapError(b.b, err, key)
 	}
-	end := func(err error) { b.tracer.End(tctx, err) }
+	end := func(err error) {
+		b.tracer.End(tctx, err)
+		cancel()
+	}
 	r := &Reader{
 		b:                b.b,
 		r:                dr,
@@ -1012,6 +1036,7 @@ func (b *Bucket) newRangeReader(ctx context.Context, key string, offset, length
 		savedOffset:      -1,
 		end:              end,
 		statsTagMutators: []tag.Mutator{tag.Upsert(oc.ProviderKey, b.tracer.Provider)},
+		provider:         b.tracer.Provider,
 	}
 	_, file, lineno, ok := runtime.Caller(2)
 	runtime.SetFinalizer(r, func(r *Reader) {
@@ -1088,46 +1113,21 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 	if opts == nil {
 		opts = &WriterOptions{}
 	}
-	dopts := &driver.WriterOptions{
-		CacheControl:                opts.CacheControl,
-		ContentDisposition:          opts.ContentDisposition,
-		ContentEncoding:             opts.ContentEncoding,
-		ContentLanguage:             opts.ContentLanguage,
-		ContentMD5:                  opts.ContentMD5,
-		BufferSize:                  opts.BufferSize,
-		MaxConcurrency:              opts.MaxConcurrency,
-		BeforeWrite:                 opts.BeforeWrite,
-		DisableContentTypeDetection: opts.DisableContentTypeDetection,
-	}
-	if len(opts.Metadata) > 0 {
-		// Services are inconsistent, but at least some treat keys
-		// as case-insensitive. To make the behavior consistent, we
-		// force-lowercase them when writing and reading.
-		md := make(map[string]string, len(opts.Metadata))
-		for k, v := range opts.Metadata {
-			if k == "" {
-				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata keys may not be empty strings")
-			}
-			if !utf8.ValidString(k) {
-				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata keys must be valid UTF-8 strings: %q", k)
-			}
-			if !utf8.ValidString(v) {
-				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata values must be valid UTF-8 strings: %q", v)
-			}
-			lowerK := strings.ToLower(k)
-			if _, found := md[lowerK]; found {
-				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata has a duplicate case-insensitive metadata key: %q", lowerK)
-			}
-			md[lowerK] = v
-		}
-		dopts.Metadata = md
+	dopts, err := driverWriterOptions(opts)
+	if err != nil {
+		return nil, err
 	}
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 	if b.closed {
 		return nil, errClosed
 	}
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancelTimeout := callopt.Context(ctx)
+	ctx, cancelWrite := context.WithCancel(ctx)
+	cancel := func() {
+		cancelWrite()
+		cancelTimeout()
+	}
 	tctx := b.tracer.Start(ctx, "NewWriter")
 	end := func(err error) { b.tracer.End(tctx, err) }
 	defer func() {
@@ -1144,6 +1144,7 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 		contentMD5:       opts.ContentMD5,
 		md5hash:          md5.New(),
 		statsTagMutators: []tag.Mutator{tag.Upsert(oc.ProviderKey, b.tracer.Provider)},
+		provider:         b.tracer.Provider,
 	}
 	if opts.ContentType != "" || opts.DisableContentTypeDetection {
 		var ct string
@@ -1181,6 +1182,46 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 	return w, nil
 }
 
+// driverWriterOptions returns the driver.WriterOptions for opts, with the
+// metadata keys lowercased.
+func driverWriterOptions(opts *WriterOptions) (*driver.WriterOptions, error) {
+	dopts := &driver.WriterOptions{
+		CacheControl:                opts.CacheControl,
+		ContentDisposition:          opts.ContentDisposition,
+		ContentEncoding:             opts.ContentEncoding,
+		ContentLanguage:             opts.ContentLanguage,
+		ContentMD5:                  opts.ContentMD5,
+		BufferSize:                  opts.BufferSize,
+		MaxConcurrency:              opts.MaxConcurrency,
+		BeforeWrite:                 opts.BeforeWrite,
+		DisableContentTypeDetection: opts.DisableContentTypeDetection,
+	}
+	if len(opts.Metadata) > 0 {
+		// Services are inconsistent, but at least some treat keys
+		// as case-insensitive. To make the behavior consistent, we
+		// force-lowercase them when writing and reading.
+		md := make(map[string]string, len(opts.Metadata))
+		for k, v := range opts.Metadata {
+			if k == "" {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata keys may not be empty strings")
+			}
+			if !utf8.ValidString(k) {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata keys must be valid UTF-8 strings: %q", k)
+			}
+			if !utf8.ValidString(v) {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata values must be valid UTF-8 strings: %q", v)
+			}
+			lowerK := strings.ToLower(k)
+			if _, found := md[lowerK]; found {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: WriterOptions.Metadata has a duplicate case-insensitive metadata key: %q", lowerK)
+			}
+			md[lowerK] = v
+		}
+		dopts.Metadata = md
+	}
+	return dopts, nil
+}
+
 // Copy the blob stored at srcKey to dstKey.
 // A nil CopyOptions is treated the same as the zero value.
 //
@@ -1206,6 +1247,8 @@ func (b *Bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *CopyOpti
 	if b.closed {
 		return errClosed
 	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = b.tracer.Start(ctx, "Copy")
 	defer func() { b.tracer.End(ctx, err) }()
 	return wrapError(b.b, b.b.Copy(ctx, dstKey, srcKey, dopts), fmt.Sprintf("%s -> %s", srcKey, dstKey))
@@ -1224,11 +1267,203 @@ func (b *Bucket) Delete(ctx context.Context, key string) (err error) {
 	if b.closed {
 		return errClosed
 	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = b.tracer.Start(ctx, "Delete")
 	defer func() { b.tracer.End(ctx, err) }()
 	return wrapError(b.b, b.b.Delete(ctx, key), key)
 }
 
+// CopyFrom copies the blob stored at srcKey in src to dstKey in b.
+// A nil CopyOptions is treated the same as the zero value.
+//
+// If both buckets use the same driver, and the service supports it, the
+// blob is copied on the service without being downloaded, including between
+// buckets of different accounts or projects if b's credentials can read from
+// src. Otherwise, CopyFrom reads the blob from src and writes it to b with
+// the same content type, metadata and other attributes; opts.BeforeCopy is
+// not called.
+//
+// If the source blob does not exist, CopyFrom returns an error for which
+// gcerrors.Code will return gcerrors.NotFound.
+//
+// If the destination blob already exists, it is overwritten.
+func (b *Bucket) CopyFrom(ctx context.Context, dstKey string, src *Bucket, srcKey string, opts *CopyOptions) (err error) {
+	if src == b {
+		return b.Copy(ctx, dstKey, srcKey, opts)
+	}
+	if !utf8.ValidString(srcKey) {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "blob: CopyFrom srcKey must be a valid UTF-8 string: %q", srcKey)
+	}
+	if !utf8.ValidString(dstKey) {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "blob: CopyFrom dstKey must be a valid UTF-8 string: %q", dstKey)
+	}
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = b.tracer.Start(ctx, "CopyFrom")
+	defer func() { b.tracer.End(ctx, err) }()
+	copied, err := b.copyFromOnService(ctx, dstKey, src, srcKey, opts)
+	if copied || err != nil {
+		return err
+	}
+
+	attrs, err := src.Attributes(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	r, err := src.NewReader(ctx, srcKey, nil)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := b.NewWriter(ctx, dstKey, &WriterOptions{
+		CacheControl:       attrs.CacheControl,
+		ContentDisposition: attrs.ContentDisposition,
+		ContentEncoding:    attrs.ContentEncoding,
+		ContentLanguage:    attrs.ContentLanguage,
+		ContentType:        attrs.ContentType,
+		Metadata:           attrs.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// copyFromOnService copies the blob with the driver of b, if it supports
+// copying from src. It reports whether it did.
+func (b *Bucket) copyFromOnService(ctx context.Context, dstKey string, src *Bucket, srcKey string, opts *CopyOptions) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return false, errClosed
+	}
+	src.mu.RLock()
+	defer src.mu.RUnlock()
+	if src.closed {
+		return false, errClosed
+	}
+	c, ok := b.b.(driver.BucketCopier)
+	if !ok {
+		return false, nil
+	}
+	err := wrapError(b.b, c.CopyFrom(ctx, dstKey, src.b, srcKey, &driver.CopyOptions{BeforeCopy: opts.BeforeCopy}), fmt.Sprintf("%s -> %s", srcKey, dstKey))
+	if gcerrors.Code(err) == gcerrors.Unimplemented {
+		return false, nil
+	}
+	return true, err
+}
+
+// A DeleteBatchError is returned by Bucket.DeleteBatch. It contains the
+// errors of the blobs that could not be deleted, and their keys.
+type DeleteBatchError []struct {
+	Key string
+	Err error
+}
+
+func (e DeleteBatchError) Error() string {
+	var s []string
+	for _, x := range e {
+		s = append(s, fmt.Sprintf("%q: %v", x.Key, x.Err))
+	}
+	return strings.Join(s, "; ")
+}
+
+// Unwrap returns the error in e, if there is exactly one. If there is more
+// than one error, Unwrap returns nil, since there is no way to determine
+// which should be returned.
+func (e DeleteBatchError) Unwrap() error {
+	if len(e) == 1 {
+		return e[0].Err
+	}
+	return nil
+}
+
+// deleteBatchConcurrency is the number of blobs deleted concurrently by
+// DeleteBatch when the driver cannot delete them in batches.
+const deleteBatchConcurrency = 10
+
+// DeleteBatch deletes the blobs stored at keys. Keys whose blobs do not
+// exist are ignored.
+//
+// If the service supports it, the blobs are deleted with bulk requests, such
+// as S3 DeleteObjects or Azure blob batches; otherwise they are deleted
+// concurrently, one request per blob.
+//
+// If some blobs cannot be deleted, DeleteBatch returns a DeleteBatchError
+// with the error of each of them. It may also return another error if the
+// batch failed as a whole.
+func (b *Bucket) DeleteBatch(ctx context.Context, keys []string) (err error) {
+	for _, key := range keys {
+		if !utf8.ValidString(key) {
+			return gcerr.Newf(gcerr.InvalidArgument, nil, "blob: DeleteBatch key must be a valid UTF-8 string: %q", key)
+		}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return errClosed
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = b.tracer.Start(ctx, "DeleteBatch")
+	defer func() { b.tracer.End(ctx, err) }()
+
+	var errs []error
+	if d, ok := b.b.(driver.BatchDeleter); ok {
+		var err error
+		errs, err = d.DeleteBatch(ctx, keys)
+		if err = wrapError(b.b, err, ""); gcerrors.Code(err) == gcerrors.Unimplemented {
+			errs = nil
+		} else if err != nil {
+			return err
+		} else if len(errs) != len(keys) {
+			return gcerr.Newf(gcerr.Internal, nil, "blob: DeleteBatch got %d errors for %d keys", len(errs), len(keys))
+		}
+	}
+	if errs == nil {
+		errs = make([]error, len(keys))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, deleteBatchConcurrency)
+		for i, key := range keys {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, key string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				errs[i] = b.b.Delete(ctx, key)
+			}(i, key)
+		}
+		wg.Wait()
+	}
+	var dbe DeleteBatchError
+	for i, err := range errs {
+		err = wrapError(b.b, err, keys[i])
+		if err == nil || gcerrors.Code(err) == gcerrors.NotFound {
+			continue
+		}
+		dbe = append(dbe, struct {
+			Key string
+			Err error
+		}{keys[i], err})
+	}
+	if dbe != nil {
+		return dbe
+	}
+	return nil
+}
+
 // SignedURL returns a URL that can be used to GET (default), PUT or DELETE
 // the blob for the duration specified in opts.Expiry.
 //
@@ -1551,6 +1786,9 @@ func wrapError(b driver.Bucket, err error, key string) error {
 	if code == gcerrors.Unknown {
 		code = b.ErrorCode(err)
 	}
+	if key != "" {
+		err = gcerr.WithDetails(err, gcerr.Details{Key: key})
+	}
 	return gcerr.New(code, err, 2, msg)
 }
 
@@ -433,6 +433,10 @@ func TestErrorsAreWrapped(t *testing.T) {
 
 	_, err := b.Attributes(ctx, "")
 	verifyWrap("Attributes", err)
+	_, err = b.Attributes(ctx, "work")
+	if d, _ := gcerrors.DetailsOf(err); d.Key != "work" {
+		t.Errorf("Attributes: got details %+v, want key %q", d, "work")
+	}
 
 	iter := b.List(nil)
 	_, err = iter.Next(ctx)
@@ -475,9 +479,21 @@ func TestErrorsAreWrapped(t *testing.T) {
 	err = b.Copy(ctx, "", "", nil)
 	verifyWrap("Copy", err)
 
+	err = b.CopyFrom(ctx, "", NewBucket(&erroringBucket{}), "", nil)
+	verifyWrap("CopyFrom", err)
+
 	err = b.Delete(ctx, "")
 	verifyWrap("Delete", err)
 
+	err = b.DeleteBatch(ctx, []string{"a", "b"})
+	var dbe DeleteBatchError
+	if !errors.As(err, &dbe) || len(dbe) != 2 {
+		t.Errorf("DeleteBatch: got error %v, want a DeleteBatchError with 2 errors", err)
+	}
+	for _, e := range dbe {
+		verifyWrap("DeleteBatch", e.Err)
+	}
+
 	_, err = b.SignedURL(ctx, "", nil)
 	verifyWrap("SignedURL", err)
 
@@ -525,9 +541,15 @@ func TestBucketIsClosed(t *testing.T) {
 	if err := bucket.Copy(ctx, "", "", nil); err != errClosed {
 		t.Error(err)
 	}
+	if err := bucket.CopyFrom(ctx, "", NewBucket(&erroringBucket{}), "", nil); err != errClosed {
+		t.Error(err)
+	}
 	if err := bucket.Delete(ctx, ""); err != errClosed {
 		t.Error(err)
 	}
+	if err := bucket.DeleteBatch(ctx, []string{""}); err != errClosed {
+		t.Error(err)
+	}
 	if _, err := bucket.SignedURL(ctx, "", nil); err != errClosed {
 		t.Error(err)
 	}
@@ -0,0 +1,321 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaosblob provides a *blob.Bucket that injects faults into the
+// calls made to another Bucket, so that retries and other resilience logic
+// can be tested. Use NewBucket to construct one.
+//
+// Each call that reaches the service (Attributes, List, NewReader,
+// NewRangeReader, NewWriter, Copy and Delete) is delayed by Options.Latency,
+// and fails with probability Options.ErrorRate. A Writer fails part way
+// with probability Options.PartialWriteRate: its first Write writes only
+// part of the data and returns an error, and its Close returns the error
+// without creating the blob.
+//
+// Faults are chosen by a pseudo-random source seeded with Options.Seed, so
+// a test that makes the same calls in the same order sees the same faults.
+//
+//	bucket := chaosblob.NewBucket(memblob.OpenBucket(nil), &chaosblob.Options{
+//		Seed:      1,
+//		ErrorRate: 0.2,
+//	})
+//
+// Injected errors have the code Options.ErrorCode, and
+// gcerrors.IsRetryable reports true for them unless the code says
+// otherwise, such as gcerrors.NotFound.
+//
+// # As
+//
+// chaosblob exposes the types of the underlying Bucket for As.
+package chaosblob // import "gocloud.dev/blob/chaosblob"
+
+import (
+	"context"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/chaos"
+)
+
+// defaultPageSize is the page size used when the driver asks for the
+// default.
+const defaultPageSize = 1000
+
+// Options sets the faults injected by the Bucket returned by NewBucket.
+type Options struct {
+	// Seed seeds the pseudo-random source that chooses the faults.
+	Seed int64
+
+	// Latency delays each call by a fixed amount.
+	Latency time.Duration
+	// LatencyJitter adds a random delay of up to LatencyJitter to each
+	// call.
+	LatencyJitter time.Duration
+
+	// ErrorRate is the probability, from 0 to 1, that a call fails.
+	ErrorRate float64
+	// ErrorCode is the code of injected errors. It defaults to
+	// gcerrors.Internal.
+	ErrorCode gcerrors.ErrorCode
+
+	// PartialWriteRate is the probability, from 0 to 1, that a Writer
+	// fails part way.
+	PartialWriteRate float64
+}
+
+// NewBucket returns a *blob.Bucket that injects faults into the calls made
+// to b. Closing the returned Bucket does not close b.
+func NewBucket(b *blob.Bucket, opts *Options) *blob.Bucket {
+	return blob.NewBucket(newBucket(b, opts))
+}
+
+func newBucket(b *blob.Bucket, opts *Options) *bucket {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &bucket{
+		b: b,
+		in: chaos.New(chaos.Faults{
+			Seed:          opts.Seed,
+			Latency:       opts.Latency,
+			LatencyJitter: opts.LatencyJitter,
+			ErrorRate:     opts.ErrorRate,
+			ErrorCode:     opts.ErrorCode,
+		}),
+		partialWriteRate: opts.PartialWriteRate,
+	}
+}
+
+// bucket implements driver.Bucket.
+type bucket struct {
+	b                *blob.Bucket
+	in               *chaos.Injector
+	partialWriteRate float64
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// As implements driver.As.
+func (b *bucket) As(i interface{}) bool {
+	return b.b.As(i)
+}
+
+// ErrorAs implements driver.ErrorAs.
+func (b *bucket) ErrorAs(err error, i interface{}) bool {
+	return b.b.ErrorAs(err, i)
+}
+
+// Attributes implements driver.Attributes.
+func (b *bucket) Attributes(ctx context.Context, key string) (*driver.Attributes, error) {
+	if err := b.in.Before(ctx, "Attributes"); err != nil {
+		return nil, err
+	}
+	a, err := b.b.Attributes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.Attributes{
+		CacheControl:       a.CacheControl,
+		ContentDisposition: a.ContentDisposition,
+		ContentEncoding:    a.ContentEncoding,
+		ContentLanguage:    a.ContentLanguage,
+		ContentType:        a.ContentType,
+		Metadata:           a.Metadata,
+		CreateTime:         a.CreateTime,
+		ModTime:            a.ModTime,
+		Size:               a.Size,
+		MD5:                a.MD5,
+		ETag:               a.ETag,
+		AsFunc:             a.As,
+	}, nil
+}
+
+// ListPaged implements driver.ListPaged.
+func (b *bucket) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	if err := b.in.Before(ctx, "ListPaged"); err != nil {
+		return nil, err
+	}
+	pageToken := opts.PageToken
+	if pageToken == nil {
+		pageToken = blob.FirstPageToken
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	objs, next, err := b.b.ListPage(ctx, pageToken, pageSize, &blob.ListOptions{
+		Prefix:     opts.Prefix,
+		Delimiter:  opts.Delimiter,
+		BeforeList: opts.BeforeList,
+	})
+	if err != nil {
+		return nil, err
+	}
+	page := &driver.ListPage{NextPageToken: next}
+	for _, obj := range objs {
+		page.Objects = append(page.Objects, &driver.ListObject{
+			Key:     obj.Key,
+			ModTime: obj.ModTime,
+			Size:    obj.Size,
+			MD5:     obj.MD5,
+			IsDir:   obj.IsDir,
+			AsFunc:  obj.As,
+		})
+	}
+	return page, nil
+}
+
+// NewRangeReader implements driver.NewRangeReader.
+func (b *bucket) NewRangeReader(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+	if err := b.in.Before(ctx, "NewRangeReader"); err != nil {
+		return nil, err
+	}
+	r, err := b.b.NewRangeReader(ctx, key, offset, length, &blob.ReaderOptions{BeforeRead: opts.BeforeRead})
+	if err != nil {
+		return nil, err
+	}
+	return &reader{r: r}, nil
+}
+
+// reader implements driver.Reader.
+type reader struct {
+	r *blob.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+func (r *reader) Close() error {
+	return r.r.Close()
+}
+
+func (r *reader) Attributes() *driver.ReaderAttributes {
+	return &driver.ReaderAttributes{
+		ContentType: r.r.ContentType(),
+		ModTime:     r.r.ModTime(),
+		Size:        r.r.Size(),
+	}
+}
+
+func (r *reader) As(i interface{}) bool {
+	return r.r.As(i)
+}
+
+// NewTypedWriter implements driver.NewTypedWriter.
+func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
+	if err := b.in.Before(ctx, "NewTypedWriter"); err != nil {
+		return nil, err
+	}
+	// Canceling the context aborts the write of a Writer that fails.
+	ctx, cancel := context.WithCancel(ctx)
+	w, err := b.b.NewWriter(ctx, key, &blob.WriterOptions{
+		BufferSize:                  opts.BufferSize,
+		MaxConcurrency:              opts.MaxConcurrency,
+		CacheControl:                opts.CacheControl,
+		ContentDisposition:          opts.ContentDisposition,
+		ContentEncoding:             opts.ContentEncoding,
+		ContentLanguage:             opts.ContentLanguage,
+		ContentType:                 contentType,
+		DisableContentTypeDetection: opts.DisableContentTypeDetection,
+		ContentMD5:                  opts.ContentMD5,
+		Metadata:                    opts.Metadata,
+		BeforeWrite:                 opts.BeforeWrite,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	wr := &writer{w: w, cancel: cancel}
+	if b.in.Chance(b.partialWriteRate) {
+		wr.in = b.in
+	}
+	return wr, nil
+}
+
+// writer implements driver.Writer.
+type writer struct {
+	w      *blob.Writer
+	cancel func()
+	// in is set if the writer is to fail part way.
+	in  *chaos.Injector
+	err error
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.in == nil {
+		return w.w.Write(p)
+	}
+	// Write part of p, then fail.
+	n, err := w.w.Write(p[:w.in.Intn(len(p)+1)])
+	if err == nil {
+		err = w.in.Fault("Writer.Write")
+	}
+	w.err = err
+	return n, err
+}
+
+func (w *writer) Close() error {
+	if w.in == nil {
+		defer w.cancel()
+		return w.w.Close()
+	}
+	// Abort the write.
+	w.cancel()
+	w.w.Close()
+	if w.err == nil {
+		w.err = w.in.Fault("Writer.Close")
+	}
+	return w.err
+}
+
+// Copy implements driver.Copy.
+func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	if err := b.in.Before(ctx, "Copy"); err != nil {
+		return err
+	}
+	return b.b.Copy(ctx, dstKey, srcKey, &blob.CopyOptions{BeforeCopy: opts.BeforeCopy})
+}
+
+// Delete implements driver.Delete.
+func (b *bucket) Delete(ctx context.Context, key string) error {
+	if err := b.in.Before(ctx, "Delete"); err != nil {
+		return err
+	}
+	return b.b.Delete(ctx, key)
+}
+
+// SignedURL implements driver.SignedURL. It does not inject faults.
+func (b *bucket) SignedURL(ctx context.Context, key string, opts *driver.SignedURLOptions) (string, error) {
+	return b.b.SignedURL(ctx, key, &blob.SignedURLOptions{
+		Expiry:                   opts.Expiry,
+		Method:                   opts.Method,
+		ContentType:              opts.ContentType,
+		EnforceAbsentContentType: opts.EnforceAbsentContentType,
+		BeforeSign:               opts.BeforeSign,
+	})
+}
+
+// Close implements driver.Close. It does not close the underlying Bucket.
+func (b *bucket) Close() error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosblob
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/blob/drivertest"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/gcerrors"
+)
+
+type harness struct {
+	b *blob.Bucket
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{b: memblob.OpenBucket(nil)}, nil
+}
+
+func (h *harness) HTTPClient() *http.Client {
+	return nil
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Bucket, error) {
+	return newBucket(h.b, nil), nil
+}
+
+func (h *harness) MakeDriverForNonexistentBucket(ctx context.Context) (driver.Bucket, error) {
+	return nil, nil
+}
+
+func (h *harness) Close() {
+	h.b.Close()
+}
+
+// TestConformance checks that a Bucket that injects no faults behaves like
+// the Bucket it wraps.
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, nil)
+}
+
+func TestErrorRate(t *testing.T) {
+	ctx := context.Background()
+	mem := memblob.OpenBucket(nil)
+	defer mem.Close()
+	if err := mem.WriteAll(ctx, "key", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	failures := func(seed int64) []bool {
+		b := NewBucket(mem, &Options{Seed: seed, ErrorRate: 0.5, ErrorCode: gcerrors.ResourceExhausted})
+		defer b.Close()
+		var got []bool
+		for i := 0; i < 20; i++ {
+			_, err := b.ReadAll(ctx, "key")
+			if err != nil {
+				if code := gcerrors.Code(err); code != gcerrors.ResourceExhausted {
+					t.Fatalf("got code %v, want ResourceExhausted", code)
+				}
+				if !gcerrors.IsRetryable(err) {
+					t.Fatalf("IsRetryable(%v) = false, want true", err)
+				}
+			}
+			got = append(got, err != nil)
+		}
+		return got
+	}
+	first, second := failures(7), failures(7)
+	var n int
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("failures differ with the same seed: %v and %v", first, second)
+		}
+		if first[i] {
+			n++
+		}
+	}
+	if n == 0 || n == len(first) {
+		t.Errorf("got %d failures of %d calls, want some", n, len(first))
+	}
+}
+
+func TestLatency(t *testing.T) {
+	ctx := context.Background()
+	mem := memblob.OpenBucket(nil)
+	defer mem.Close()
+	b := NewBucket(mem, &Options{Latency: time.Hour})
+	defer b.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.Exists(ctx, "key"); gcerrors.Code(err) != gcerrors.DeadlineExceeded {
+		t.Errorf("got %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestPartialWrite(t *testing.T) {
+	ctx := context.Background()
+	mem := memblob.OpenBucket(nil)
+	defer mem.Close()
+	b := NewBucket(mem, &Options{PartialWriteRate: 1})
+	defer b.Close()
+
+	w, err := b.NewWriter(ctx, "key", &blob.WriterOptions{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello world")
+	if n, err := w.Write(data); err == nil || n >= len(data) {
+		t.Errorf("Write: got %d, %v; want a short write and an error", n, err)
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close: got nil error")
+	}
+	if ok, err := mem.Exists(ctx, "key"); err != nil || ok {
+		t.Errorf("Exists: got %t, %v; want the blob not to be created", ok, err)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/gcerrors"
+)
+
+func openFileTestBucket(t *testing.T) *blob.Bucket {
+	t.Helper()
+	b, err := fileblob.OpenBucket(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestCopyFrom(t *testing.T) {
+	ctx := context.Background()
+	src := memblob.OpenBucket(nil)
+	defer src.Close()
+	content := []byte("hello world")
+	wopts := &blob.WriterOptions{
+		ContentType:     "text/plain",
+		ContentLanguage: "en",
+		CacheControl:    "no-cache",
+		Metadata:        map[string]string{"color": "blue"},
+	}
+	if err := src.WriteAll(ctx, "src", content, wopts); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		dst         *blob.Bucket
+		// onService is true if the blob is copied by the driver, which calls
+		// BeforeCopy.
+		onService bool
+	}{
+		{"same bucket", src, true},
+		{"same driver", memblob.OpenBucket(nil), true},
+		{"other driver", openFileTestBucket(t), false},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			calledBeforeCopy := false
+			opts := &blob.CopyOptions{
+				BeforeCopy: func(asFunc func(interface{}) bool) error {
+					calledBeforeCopy = true
+					return nil
+				},
+			}
+			if err := test.dst.CopyFrom(ctx, "dst", src, "src", opts); err != nil {
+				t.Fatal(err)
+			}
+			if calledBeforeCopy != test.onService {
+				t.Errorf("got BeforeCopy called %v, want %v", calledBeforeCopy, test.onService)
+			}
+			got, err := test.dst.ReadAll(ctx, "dst")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, content) {
+				t.Errorf("got content %q, want %q", got, content)
+			}
+			attrs, err := test.dst.Attributes(ctx, "dst")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if attrs.ContentType != wopts.ContentType || attrs.ContentLanguage != wopts.ContentLanguage || attrs.CacheControl != wopts.CacheControl || !cmp.Equal(attrs.Metadata, wopts.Metadata) {
+				t.Errorf("got attributes %+v, want those of %+v", attrs, wopts)
+			}
+
+			err = test.dst.CopyFrom(ctx, "dst", src, "missing", nil)
+			if gcerrors.Code(err) != gcerrors.NotFound {
+				t.Errorf("got error %v copying a missing blob, want NotFound", err)
+			}
+		})
+	}
+}
+
+func TestDeleteBatch(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		description string
+		bucket      *blob.Bucket
+	}{
+		{"batches", memblob.OpenBucket(nil)},
+		{"one by one", openFileTestBucket(t)},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			b := test.bucket
+			var keys []string
+			for _, key := range []string{"a", "b", "c/d", "e"} {
+				if err := b.WriteAll(ctx, key, []byte(key), nil); err != nil {
+					t.Fatal(err)
+				}
+				keys = append(keys, key)
+			}
+			// Missing blobs are ignored.
+			keys = append(keys, "missing")
+			if err := b.DeleteBatch(ctx, keys); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range keys {
+				if ok, err := b.Exists(ctx, key); err != nil || ok {
+					t.Errorf("%s: got exists %v, %v after DeleteBatch, want false", key, ok, err)
+				}
+			}
+			if err := b.DeleteBatch(ctx, nil); err != nil {
+				t.Errorf("got error %v deleting no blobs, want nil", err)
+			}
+		})
+	}
+}
+
+func TestDeleteBatchError(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	one := blob.DeleteBatchError{{Key: "a", Err: errA}}
+	if !errors.Is(one, errA) {
+		t.Errorf("got errors.Is(%v, errA) false, want true", one)
+	}
+	two := append(one, blob.DeleteBatchError{{Key: "b", Err: errB}}...)
+	if errors.Is(two, errA) {
+		t.Errorf("got errors.Is(%v, errA) true, want false", two)
+	}
+	if got, want := two.Error(), `"a": a; "b": b`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
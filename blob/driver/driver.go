@@ -335,6 +335,67 @@ type Bucket interface {
 	Close() error
 }
 
+// BucketCopier has an optional extra method for buckets that can copy objects
+// from other buckets on the service, without reading and writing them.
+type BucketCopier interface {
+	// CopyFrom copies the object associated with srcKey in src to dstKey in
+	// this bucket. src may be a Bucket of another driver.
+	//
+	// If the object cannot be copied on the service, for example because src
+	// is of another driver, CopyFrom must return an Unimplemented error
+	// before calling opts.BeforeCopy; the portable type then copies the
+	// object by reading and writing it.
+	//
+	// Otherwise, CopyFrom behaves like Copy.
+	// opts is guaranteed to be non-nil.
+	CopyFrom(ctx context.Context, dstKey string, src Bucket, srcKey string, opts *CopyOptions) error
+}
+
+// BatchDeleter has an optional extra method for buckets that can delete
+// several objects in a request.
+type BatchDeleter interface {
+	// DeleteBatch deletes the objects associated with keys, splitting them
+	// into as many requests as the service needs. Objects that do not exist
+	// are not an error.
+	//
+	// It returns one error per key, in the order of keys, which is nil if the
+	// object was deleted; or a non-nil error if no objects could be deleted.
+	// If it returns an Unimplemented error, for example because the
+	// credentials of the bucket do not support batches, the portable type
+	// deletes the objects one by one.
+	DeleteBatch(ctx context.Context, keys []string) ([]error, error)
+}
+
+// ResumableUploader has optional extra methods for buckets that can upload
+// objects in parts, in an upload that can be resumed by another process.
+//
+// The state of an upload is returned by BeginUpload and passed to the other
+// methods; it must not change during the upload. The portable type keeps the
+// key of the upload, and the number and size of the parts uploaded so far.
+type ResumableUploader interface {
+	// UploadPartSize returns the size that parts uploaded with UploadPart
+	// must be a multiple of.
+	UploadPartSize() int64
+
+	// BeginUpload starts an upload to key, with the attributes of contentType
+	// and opts like NewTypedWriter. It returns the state of the upload.
+	// opts.ContentMD5 is always nil.
+	BeginUpload(ctx context.Context, key, contentType string, opts *WriterOptions) ([]byte, error)
+
+	// UploadPart uploads p as the part with index part, at offset in the
+	// object. The size of p is a non-zero multiple of UploadPartSize.
+	// Uploading the same part again replaces it.
+	UploadPart(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error
+
+	// CompleteUpload uploads p, which may be empty, as the last part with
+	// index part, at offset in the object, and creates the object from the
+	// parts.
+	CompleteUpload(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error
+
+	// AbortUpload cancels the upload and deletes its parts.
+	AbortUpload(ctx context.Context, key string, state []byte) error
+}
+
 // SignedURLOptions sets options for SignedURL.
 type SignedURLOptions struct {
 	// Expiry sets how long the returned URL is valid for. It is guaranteed to be > 0.
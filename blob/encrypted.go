@@ -0,0 +1,479 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+// The metadata keys of an encrypted blob. They are valid Azure metadata keys.
+const (
+	// encVersionKey holds the version of the encryption format, currently 1.
+	encVersionKey = "gocdk_encryption"
+	// encKeyIDKey holds the EncryptionOptions.KeyID of the Keeper that
+	// encrypted the data key, if it is not empty.
+	encKeyIDKey = "gocdk_encryption_key_id"
+	// encDataKeyKey holds the encrypted data key, base64-encoded.
+	encDataKeyKey = "gocdk_encryption_data_key"
+	// encMetadataKey is "1" if the values of the user metadata are
+	// encrypted.
+	encMetadataKey = "gocdk_encryption_metadata"
+)
+
+const (
+	encVersion = "1"
+	// encChunkSize is the size of the plaintext chunks that are encrypted
+	// separately. It is fixed so that the plaintext size of a blob can be
+	// computed from its size alone, for example when listing.
+	encChunkSize = 64 << 10
+	// encOverhead is the size of the AES-GCM tag added to each chunk.
+	encOverhead = 16
+	// encSealedChunkSize is the size of an encrypted chunk, other than the
+	// last one.
+	encSealedChunkSize = encChunkSize + encOverhead
+)
+
+// EncryptionOptions sets options for EncryptedBucket.
+type EncryptionOptions struct {
+	// KeyID identifies the Keeper passed to EncryptedBucket. It is stored in
+	// the metadata of the blobs it writes, to find the Keeper that can
+	// decrypt them after a key rotation.
+	KeyID string
+
+	// Keepers holds the Keepers that decrypt blobs written with other key
+	// IDs, such as the Keepers used before a key rotation. Blobs are always
+	// written with the Keeper passed to EncryptedBucket.
+	Keepers map[string]*secrets.Keeper
+
+	// EncryptMetadata encrypts the values of the blob metadata as well as
+	// its content. The metadata keys are not encrypted.
+	EncryptMetadata bool
+}
+
+// EncryptedBucket returns a *Bucket based on bucket that encrypts the
+// content of the blobs it writes with keeper, and decrypts them when they
+// are read.
+//
+// Each blob is encrypted with its own data key, which is generated by
+// keeper (see secrets.Keeper.GenerateDataKey) and stored, encrypted, in the
+// blob's metadata together with opts.KeyID. The content is encrypted in
+// chunks of 64 KiB with AES-256-GCM, so that blobs can be written and read
+// as streams, and ranges of them can be read without reading the whole blob.
+// Only the data keys are sent to the key service, once per blob written or
+// read.
+//
+// To rotate keys, pass the new Keeper with a new KeyID, and the previous
+// Keepers in opts.Keepers by their key IDs; blobs are written with the new
+// Keeper, and read with the Keeper of the key ID they were written with.
+//
+// Attributes and List report the size of the decrypted content, and no MD5
+// hash; the metadata keys used for encryption are hidden. All the blobs of
+// the bucket must be written through an encrypted bucket: reading other
+// blobs fails, and List reports a wrong size for them. Copy keeps blobs
+// encrypted with the same data key. SignedURL only supports the DELETE
+// method, since the content of the signed URLs would not be decrypted.
+//
+// bucket will be closed and no longer usable after this function returns.
+// Closing the returned Bucket does not close keeper or opts.Keepers.
+func EncryptedBucket(bucket *Bucket, keeper *secrets.Keeper, opts *EncryptionOptions) *Bucket {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	bucket.closed = true
+	if opts == nil {
+		opts = &EncryptionOptions{}
+	}
+	return NewBucket(&encryptedBucket{base: bucket.b, keeper: keeper, opts: *opts})
+}
+
+// encryptedBucket implements driver.Bucket by encrypting the blobs of base.
+type encryptedBucket struct {
+	base   driver.Bucket
+	keeper *secrets.Keeper
+	opts   EncryptionOptions
+}
+
+// decryptedSize returns the size of the content of a blob whose encrypted
+// content has size n.
+func decryptedSize(n int64) int64 {
+	chunks := (n + encSealedChunkSize - 1) / encSealedChunkSize
+	if chunks == 0 {
+		return 0
+	}
+	return n - chunks*encOverhead
+}
+
+// chunkNonce returns the nonce of the chunk with index i, which is the last
+// chunk of its blob if last is true. Each blob has its own data key, so the
+// nonces only need to be unique within a blob.
+func chunkNonce(nonce []byte, i uint64, last bool) []byte {
+	for j := range nonce {
+		nonce[j] = 0
+	}
+	binary.BigEndian.PutUint64(nonce[3:11], i)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func newEncryptionAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "blob: invalid data key")
+	}
+	return cipher.NewGCM(block)
+}
+
+var errDecrypt = gcerr.Newf(gcerr.InvalidArgument, nil, "blob: failed to decrypt blob")
+
+// lowerKeys returns md with lowercased keys, as the portable type returns
+// them.
+func lowerKeys(md map[string]string) map[string]string {
+	lower := make(map[string]string, len(md))
+	for k, v := range md {
+		lower[strings.ToLower(k)] = v
+	}
+	return lower
+}
+
+// open returns the AEAD of the data key of a blob with metadata md, which
+// must have lowercased keys.
+func (b *encryptedBucket) open(ctx context.Context, md map[string]string) (cipher.AEAD, error) {
+	if v, ok := md[encVersionKey]; !ok {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "blob: blob is not encrypted")
+	} else if v != encVersion {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "blob: unsupported encryption version %q", v)
+	}
+	keeper := b.keeper
+	if id := md[encKeyIDKey]; id != b.opts.KeyID {
+		keeper = b.opts.Keepers[id]
+		if keeper == nil {
+			return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "blob: no Keeper for key ID %q", id)
+		}
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(md[encDataKeyKey])
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "blob: malformed data key")
+	}
+	dataKey, err := keeper.Decrypt(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptionAEAD(dataKey)
+}
+
+// userMetadata returns the metadata of a blob with metadata md, which must
+// have lowercased keys, without the keys used for encryption. The values
+// are decrypted with aead if they are encrypted.
+func (b *encryptedBucket) userMetadata(aead cipher.AEAD, md map[string]string) (map[string]string, error) {
+	encrypted := md[encMetadataKey] == "1"
+	user := map[string]string{}
+	for k, v := range md {
+		switch k {
+		case encVersionKey, encKeyIDKey, encDataKeyKey, encMetadataKey:
+			continue
+		}
+		if encrypted {
+			sealed, err := base64.StdEncoding.DecodeString(v)
+			if err != nil || len(sealed) < aead.NonceSize() {
+				return nil, errDecrypt
+			}
+			plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(k))
+			if err != nil {
+				return nil, errDecrypt
+			}
+			v = string(plain)
+		}
+		user[k] = v
+	}
+	return user, nil
+}
+
+func (b *encryptedBucket) ErrorCode(err error) gcerrors.ErrorCode { return b.base.ErrorCode(err) }
+func (b *encryptedBucket) As(i interface{}) bool                  { return b.base.As(i) }
+func (b *encryptedBucket) ErrorAs(err error, i interface{}) bool  { return b.base.ErrorAs(err, i) }
+
+func (b *encryptedBucket) Attributes(ctx context.Context, key string) (*driver.Attributes, error) {
+	attrs, err := b.base.Attributes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	md := lowerKeys(attrs.Metadata)
+	var aead cipher.AEAD
+	if md[encMetadataKey] == "1" {
+		if aead, err = b.open(ctx, md); err != nil {
+			return nil, err
+		}
+	}
+	a := *attrs
+	if a.Metadata, err = b.userMetadata(aead, md); err != nil {
+		return nil, err
+	}
+	a.Size = decryptedSize(attrs.Size)
+	a.MD5 = nil
+	return &a, nil
+}
+
+func (b *encryptedBucket) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	page, err := b.base.ListPaged(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range page.Objects {
+		if !obj.IsDir {
+			obj.Size = decryptedSize(obj.Size)
+			obj.MD5 = nil
+		}
+	}
+	return page, nil
+}
+
+func (b *encryptedBucket) NewRangeReader(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
+	attrs, err := b.base.Attributes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := b.open(ctx, lowerKeys(attrs.Metadata))
+	if err != nil {
+		return nil, err
+	}
+	size := decryptedSize(attrs.Size)
+	if offset > size {
+		offset = size
+	}
+	if length < 0 || offset+length > size {
+		length = size - offset
+	}
+	// Read the encrypted chunks that hold the range.
+	first := offset / encChunkSize
+	var r driver.Reader
+	if length == 0 {
+		r, err = b.base.NewRangeReader(ctx, key, 0, 0, opts)
+	} else {
+		start := first * encSealedChunkSize
+		end := ((offset+length-1)/encChunkSize + 1) * encSealedChunkSize
+		if end > attrs.Size {
+			end = attrs.Size
+		}
+		r, err = b.base.NewRangeReader(ctx, key, start, end-start, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ra := *r.Attributes()
+	ra.Size = size
+	return &encryptedReader{
+		r:         r,
+		aead:      aead,
+		attrs:     &ra,
+		chunk:     uint64(first),
+		lastChunk: uint64((attrs.Size - 1) / encSealedChunkSize),
+		lastSize:  attrs.Size - (attrs.Size-1)/encSealedChunkSize*encSealedChunkSize,
+		skip:      offset - first*encChunkSize,
+		remaining: length,
+		nonce:     make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// encryptedReader implements driver.Reader by decrypting the chunks read
+// from r.
+type encryptedReader struct {
+	r         driver.Reader
+	aead      cipher.AEAD
+	attrs     *driver.ReaderAttributes
+	chunk     uint64 // the index of the next chunk to read
+	lastChunk uint64 // the index of the last chunk of the blob
+	lastSize  int64  // the encrypted size of the last chunk
+	skip      int64  // the bytes to skip at the start of the next chunk
+	remaining int64  // the bytes left to return
+	nonce     []byte
+	buf       []byte // the encrypted chunk
+	plain     []byte // the decrypted bytes left to return
+}
+
+func (r *encryptedReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if len(r.plain) == 0 {
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := len(p)
+	if int64(n) > r.remaining {
+		n = int(r.remaining)
+	}
+	n = copy(p[:n], r.plain)
+	r.plain = r.plain[n:]
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+// readChunk reads and decrypts the next chunk into r.plain.
+func (r *encryptedReader) readChunk() error {
+	size := int64(encSealedChunkSize)
+	last := r.chunk == r.lastChunk
+	if last {
+		size = r.lastSize
+	}
+	if r.buf == nil {
+		r.buf = make([]byte, encSealedChunkSize)
+	}
+	sealed := r.buf[:size]
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	plain, err := r.aead.Open(sealed[:0], chunkNonce(r.nonce, r.chunk, last), sealed, nil)
+	if err != nil {
+		return errDecrypt
+	}
+	r.chunk++
+	r.plain = plain[r.skip:]
+	r.skip = 0
+	return nil
+}
+
+func (r *encryptedReader) Close() error                         { return r.r.Close() }
+func (r *encryptedReader) Attributes() *driver.ReaderAttributes { return r.attrs }
+func (r *encryptedReader) As(i interface{}) bool                { return r.r.As(i) }
+
+func (b *encryptedBucket) NewTypedWriter(ctx context.Context, key, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
+	dataKey, encryptedKey, err := b.keeper.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newEncryptionAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	md := map[string]string{}
+	for k, v := range opts.Metadata {
+		switch k {
+		case encVersionKey, encKeyIDKey, encDataKeyKey, encMetadataKey:
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: metadata key %q is reserved for encryption", k)
+		}
+		if b.opts.EncryptMetadata {
+			sealed := make([]byte, aead.NonceSize(), aead.NonceSize()+len(v)+aead.Overhead())
+			if _, err := rand.Read(sealed); err != nil {
+				return nil, gcerr.Newf(gcerr.Internal, err, "blob: failed to generate nonce")
+			}
+			v = base64.StdEncoding.EncodeToString(aead.Seal(sealed, sealed, []byte(v), []byte(k)))
+		}
+		md[k] = v
+	}
+	md[encVersionKey] = encVersion
+	md[encDataKeyKey] = base64.StdEncoding.EncodeToString(encryptedKey)
+	if b.opts.KeyID != "" {
+		md[encKeyIDKey] = b.opts.KeyID
+	}
+	if b.opts.EncryptMetadata {
+		md[encMetadataKey] = "1"
+	}
+	dopts := *opts
+	dopts.Metadata = md
+	// The portable type checks ContentMD5 against the content before it is
+	// encrypted; the service would check it against the encrypted content.
+	dopts.ContentMD5 = nil
+	w, err := b.base.NewTypedWriter(ctx, key, contentType, &dopts)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedWriter{
+		w:     w,
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+		buf:   make([]byte, 0, encSealedChunkSize),
+	}, nil
+}
+
+// encryptedWriter implements driver.Writer by encrypting the content
+// written to w in chunks.
+type encryptedWriter struct {
+	w     driver.Writer
+	aead  cipher.AEAD
+	nonce []byte
+	chunk uint64 // the index of the next chunk to write
+	// buf holds the content of the next chunk. It is only encrypted when
+	// more content is written, or on Close, to know whether it is the last.
+	buf []byte
+}
+
+func (w *encryptedWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(w.buf) == encChunkSize {
+			if err := w.writeChunk(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		c := encChunkSize - len(w.buf)
+		if c > len(p) {
+			c = len(p)
+		}
+		w.buf = append(w.buf, p[:c]...)
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// writeChunk encrypts and writes the content of w.buf.
+func (w *encryptedWriter) writeChunk(last bool) error {
+	sealed := w.aead.Seal(w.buf[:0], chunkNonce(w.nonce, w.chunk, last), w.buf, nil)
+	w.chunk++
+	w.buf = w.buf[:0]
+	_, err := w.w.Write(sealed)
+	return err
+}
+
+func (w *encryptedWriter) Close() error {
+	err := w.writeChunk(true)
+	if cerr := w.w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (b *encryptedBucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	return b.base.Copy(ctx, dstKey, srcKey, opts)
+}
+
+func (b *encryptedBucket) Delete(ctx context.Context, key string) error {
+	return b.base.Delete(ctx, key)
+}
+
+func (b *encryptedBucket) SignedURL(ctx context.Context, key string, opts *driver.SignedURLOptions) (string, error) {
+	if opts.Method != http.MethodDelete {
+		return "", gcerr.Newf(gcerr.Unimplemented, nil, "blob: SignedURL with method %s is not supported for encrypted buckets", opts.Method)
+	}
+	return b.base.SignedURL(ctx, key, opts)
+}
+func (b *encryptedBucket) Close() error { return b.base.Close() }
@@ -0,0 +1,267 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+func newTestKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := localsecrets.NewKeeper(sk)
+	t.Cleanup(func() { k.Close() })
+	return k
+}
+
+// openEncryptedTestBuckets returns an encrypted bucket and a plain bucket
+// on the same directory, to inspect what the encrypted bucket stores.
+func openEncryptedTestBuckets(t *testing.T, keeper *secrets.Keeper, opts *blob.EncryptionOptions) (encrypted, plain *blob.Bucket) {
+	t.Helper()
+	dir := t.TempDir()
+	b, err := fileblob.OpenBucket(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err = fileblob.OpenBucket(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted = blob.EncryptedBucket(b, keeper, opts)
+	t.Cleanup(func() {
+		encrypted.Close()
+		plain.Close()
+	})
+	return encrypted, plain
+}
+
+func TestEncryptedBucket(t *testing.T) {
+	ctx := context.Background()
+	bucket, plain := openEncryptedTestBuckets(t, newTestKeeper(t), nil)
+
+	// The sizes cover an empty blob, a partial chunk, whole chunks, and
+	// chunks with a partial last one.
+	for _, size := range []int{0, 10, 64 << 10, 128 << 10, 150000} {
+		content := make([]byte, size)
+		rand.Read(content)
+		md := map[string]string{"color": "blue"}
+		if err := bucket.WriteAll(ctx, "b", content, &blob.WriterOptions{ContentType: "application/octet-stream", Metadata: md}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := bucket.ReadAll(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("size %d: got different content", size)
+		}
+		raw, err := plain.ReadAll(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size > 0 && bytes.Contains(raw, content) {
+			t.Errorf("size %d: the stored content is not encrypted", size)
+		}
+
+		attrs, err := bucket.Attributes(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attrs.Size != int64(size) || attrs.MD5 != nil || !cmp.Equal(attrs.Metadata, md) {
+			t.Errorf("size %d: got attributes size %d, MD5 %x, metadata %v; want %d, none, %v", size, attrs.Size, attrs.MD5, attrs.Metadata, size, md)
+		}
+		objs, _, err := bucket.ListPage(ctx, blob.FirstPageToken, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(objs) != 1 || objs[0].Size != int64(size) {
+			t.Errorf("size %d: got listed objects %+v", size, objs)
+		}
+
+		// Ranges are read from the chunks that hold them.
+		for _, rng := range [][2]int64{{0, 5}, {3, -1}, {65530, 20}, {65536, 65536}, {int64(size) - 1, 1}, {int64(size), 10}} {
+			if rng[0] < 0 {
+				continue
+			}
+			r, err := bucket.NewRangeReader(ctx, "b", rng[0], rng[1], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if _, err := buf.ReadFrom(r); err != nil {
+				t.Fatalf("size %d, range %v: %v", size, rng, err)
+			}
+			r.Close()
+			start, end := rng[0], rng[0]+rng[1]
+			if start > int64(size) {
+				start = int64(size)
+			}
+			if rng[1] < 0 || end > int64(size) {
+				end = int64(size)
+			}
+			if !bytes.Equal(buf.Bytes(), content[start:end]) {
+				t.Errorf("size %d, range %v: got %d bytes, want content[%d:%d]", size, rng, buf.Len(), start, end)
+			}
+			if r.Size() != int64(size) {
+				t.Errorf("size %d, range %v: got Reader size %d", size, rng, r.Size())
+			}
+		}
+	}
+
+	// The metadata keys used for encryption are reserved.
+	err := bucket.WriteAll(ctx, "b", nil, &blob.WriterOptions{Metadata: map[string]string{"gocdk_encryption": "x"}})
+	if gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v writing reserved metadata, want InvalidArgument", err)
+	}
+
+	// Copies are encrypted with the same data key.
+	if err := bucket.Copy(ctx, "c", "b", nil); err != nil {
+		t.Fatal(err)
+	}
+	want, err := bucket.ReadAll(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := bucket.ReadAll(ctx, "c"); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("reading a copy: got error %v, equal content %v", err, bytes.Equal(got, want))
+	}
+
+	// The content of signed URLs would not be decrypted.
+	if _, err := bucket.SignedURL(ctx, "b", &blob.SignedURLOptions{Expiry: time.Minute}); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("got error %v for a GET signed URL, want Unimplemented", err)
+	}
+	if _, err := bucket.SignedURL(ctx, "b", &blob.SignedURLOptions{Expiry: time.Minute, Method: http.MethodPut}); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("got error %v for a PUT signed URL, want Unimplemented", err)
+	}
+}
+
+func TestEncryptedBucketMetadata(t *testing.T) {
+	ctx := context.Background()
+	bucket, plain := openEncryptedTestBuckets(t, newTestKeeper(t), &blob.EncryptionOptions{EncryptMetadata: true})
+	md := map[string]string{"owner": "alice", "empty": ""}
+	if err := bucket.WriteAll(ctx, "b", []byte("hello"), &blob.WriterOptions{Metadata: md}); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := bucket.Attributes(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(attrs.Metadata, md) {
+		t.Errorf("got metadata %v, want %v", attrs.Metadata, md)
+	}
+	raw, err := plain.Attributes(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := raw.Metadata["owner"]; v == "" || v == "alice" {
+		t.Errorf("got stored metadata value %q, want an encrypted value", v)
+	}
+}
+
+func TestEncryptedBucketKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKeeper, newKeeper := newTestKeeper(t), newTestKeeper(t)
+	dir := t.TempDir()
+	open := func(keeper *secrets.Keeper, opts *blob.EncryptionOptions) *blob.Bucket {
+		b, err := fileblob.OpenBucket(dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bucket := blob.EncryptedBucket(b, keeper, opts)
+		t.Cleanup(func() { bucket.Close() })
+		return bucket
+	}
+
+	bucket := open(oldKeeper, &blob.EncryptionOptions{KeyID: "v1"})
+	if err := bucket.WriteAll(ctx, "old", []byte("old"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// After the rotation, blobs are written with the new Keeper, and the old
+	// blobs are read with the old one.
+	bucket = open(newKeeper, &blob.EncryptionOptions{
+		KeyID:   "v2",
+		Keepers: map[string]*secrets.Keeper{"v1": oldKeeper},
+	})
+	if err := bucket.WriteAll(ctx, "new", []byte("new"), nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"old", "new"} {
+		if got, err := bucket.ReadAll(ctx, key); err != nil || string(got) != key {
+			t.Errorf("%s: got %q, %v, want %q", key, got, err, key)
+		}
+	}
+
+	// Without the old Keeper, the old blobs cannot be read.
+	bucket = open(newKeeper, &blob.EncryptionOptions{KeyID: "v2"})
+	if _, err := bucket.ReadAll(ctx, "old"); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got error %v without the old Keeper, want FailedPrecondition", err)
+	}
+	if got, err := bucket.ReadAll(ctx, "new"); err != nil || string(got) != "new" {
+		t.Errorf("got %q, %v, want %q", got, err, "new")
+	}
+}
+
+func TestEncryptedBucketErrors(t *testing.T) {
+	ctx := context.Background()
+	bucket, plain := openEncryptedTestBuckets(t, newTestKeeper(t), nil)
+
+	// Blobs that were not written by an encrypted bucket cannot be read.
+	if err := plain.WriteAll(ctx, "plain", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bucket.ReadAll(ctx, "plain"); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got error %v reading a plain blob, want FailedPrecondition", err)
+	}
+	if _, err := bucket.ReadAll(ctx, "missing"); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got error %v reading a missing blob, want NotFound", err)
+	}
+
+	// Modified content fails to decrypt.
+	if err := bucket.WriteAll(ctx, "b", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := plain.Attributes(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := plain.ReadAll(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw[0] ^= 1
+	if err := plain.WriteAll(ctx, "b", raw, &blob.WriterOptions{Metadata: attrs.Metadata}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bucket.ReadAll(ctx, "b"); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v reading modified content, want InvalidArgument", err)
+	}
+}
@@ -16,6 +16,7 @@ package blob_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -27,6 +28,7 @@ import (
 	"gocloud.dev/blob/fileblob"
 	_ "gocloud.dev/blob/gcsblob"
 	_ "gocloud.dev/blob/s3blob"
+	"gocloud.dev/secrets"
 )
 
 func ExampleBucket_NewReader() {
@@ -128,6 +130,79 @@ func ExampleBucket_Delete() {
 	}
 }
 
+func ExampleBucket_CopyFrom() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var bucket, srcBucket *blob.Bucket
+
+	// Copy foo.txt from srcBucket to bar.txt in bucket. If both buckets use
+	// the same service, the blob is copied by the service without being
+	// downloaded.
+	if err := bucket.CopyFrom(ctx, "bar.txt", srcBucket, "foo.txt", nil); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleBucket_DeleteBatch() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var bucket *blob.Bucket
+
+	keys := []string{"foo.txt", "bar.txt", "baz.txt"}
+	if err := bucket.DeleteBatch(ctx, keys); err != nil {
+		// The keys of the blobs that could not be deleted are in the error.
+		var batchErr blob.DeleteBatchError
+		if errors.As(err, &batchErr) {
+			for _, e := range batchErr {
+				log.Printf("deleting %s: %v", e.Key, e.Err)
+			}
+		}
+		log.Fatal(err)
+	}
+}
+
+func ExampleBucket_BeginUpload() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var bucket *blob.Bucket
+	var f *os.File
+	saveToken := func([]byte) {}
+
+	// Start an upload, and write the file in parts of 5 MiB, which are valid
+	// parts for all services.
+	u, err := bucket.BeginUpload(ctx, "big.bin", &blob.WriterOptions{ContentType: "application/octet-stream"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	buf := make([]byte, 5<<20)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// The last part can have any size.
+			if err := u.Complete(ctx, buf[:n]); err != nil {
+				log.Fatal(err)
+			}
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := u.Append(ctx, buf); err != nil {
+			log.Fatal(err)
+		}
+		// Save the token after each part. If the process fails, the upload
+		// can be resumed with bucket.ResumeUpload, from the offset u.Size().
+		token, err := u.Token()
+		if err != nil {
+			log.Fatal(err)
+		}
+		saveToken(token)
+	}
+}
+
 func Example() {
 	// Connect to a bucket when your program starts up.
 	// This example uses the file-based implementation in fileblob, and creates
@@ -517,6 +592,27 @@ func ExampleSingleKeyBucket() {
 	// Bucket operations will ignore the passed-in key and always reference foo.txt.
 }
 
+func ExampleEncryptedBucket() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	var bucket *blob.Bucket
+	var keeper, oldKeeper *secrets.Keeper
+
+	// Wrap the bucket using blob.EncryptedBucket.
+	// Blobs are encrypted with data keys protected by keeper, and the blobs
+	// written with oldKeeper before a key rotation can still be read.
+	bucket = blob.EncryptedBucket(bucket, keeper, &blob.EncryptionOptions{
+		KeyID:   "v2",
+		Keepers: map[string]*secrets.Keeper{"v1": oldKeeper},
+	})
+
+	// The original bucket is no longer usable; it has been closed.
+	// The wrapped bucket should be closed when done.
+	defer bucket.Close()
+
+	// Content written to the bucket is encrypted, and decrypted when read.
+}
+
 func ExampleReader_As() {
 	// This example is specific to the gcsblob implementation; it demonstrates
 	// access to the underlying cloud.google.com/go/storage.Reader type.
@@ -42,6 +42,13 @@
 //   - Blob keys: ASCII characters 10 and 13 are escaped to "__0x<hex>__".
 //     Additionally, the "/" in "../" is escaped in the same way.
 //
+// # Per-call options
+//
+// gcsblob supports the following per-call options of gocloud.dev/callopt:
+//   - the retry policy, for all requests;
+//   - the callopt.StorageClass hint, for the storage class of objects
+//     written, such as "NEARLINE".
+//
 // # As
 //
 // gcsblob exposes the following types for As:
@@ -74,6 +81,7 @@ import (
 	"cloud.google.com/go/compute/metadata"
 	"cloud.google.com/go/storage"
 	"github.com/google/wire"
+	"github.com/googleapis/gax-go/v2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
@@ -81,6 +89,7 @@ import (
 
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/driver"
+	"gocloud.dev/callopt"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/gcp"
 	"gocloud.dev/internal/escape"
@@ -306,12 +315,16 @@ func openBucket(ctx context.Context, client *gcp.HTTPClient, bucketName string,
 		return nil, errors.New("gcsblob.OpenBucket: bucketName is required")
 	}
 
-	clientOpts := []option.ClientOption{option.WithHTTPClient(useragent.HTTPClient(&client.Client, "blob"))}
+	httpClient := useragent.HTTPClient(&client.Client, "blob")
+	uploadURL := defaultUploadURL
+	clientOpts := []option.ClientOption{option.WithHTTPClient(httpClient)}
 	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		httpClient = http.DefaultClient
+		uploadURL = "http://" + host + "/upload/storage/v1/"
 		clientOpts = []option.ClientOption{
 			option.WithoutAuthentication(),
 			option.WithEndpoint("http://" + host + "/storage/v1/"),
-			option.WithHTTPClient(http.DefaultClient),
+			option.WithHTTPClient(httpClient),
 		}
 	}
 
@@ -323,7 +336,7 @@ func openBucket(ctx context.Context, client *gcp.HTTPClient, bucketName string,
 	if opts == nil {
 		opts = &Options{}
 	}
-	return &bucket{name: bucketName, client: c, opts: opts}, nil
+	return &bucket{name: bucketName, client: c, opts: opts, httpClient: httpClient, uploadURL: uploadURL}, nil
 }
 
 // OpenBucket returns a *blob.Bucket backed by an existing GCS bucket. See the
@@ -342,6 +355,11 @@ type bucket struct {
 	name   string
 	client *storage.Client
 	opts   *Options
+
+	// httpClient and uploadURL are used for resumable uploads, which the
+	// storage package does not expose; see upload.go.
+	httpClient *http.Client
+	uploadURL  string
 }
 
 var emptyBody = io.NopCloser(strings.NewReader(""))
@@ -385,7 +403,8 @@ func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
 			// 'Permission 'storage.objects.list' denied on resource (or it may not exist)'
 			// So we have to pick one.
 			return gcerrors.NotFound
-		case http.StatusNotFound:
+		case http.StatusNotFound, http.StatusGone:
+			// Gone is returned for expired or canceled upload sessions.
 			return gcerrors.NotFound
 		case http.StatusPreconditionFailed:
 			return gcerrors.FailedPrecondition
@@ -400,9 +419,36 @@ func (b *bucket) Close() error {
 	return nil
 }
 
+// bucketHandle returns the handle of the bucket. If ctx has a callopt
+// retry policy, requests made with the handle are retried with it.
+func (b *bucket) bucketHandle(ctx context.Context) *storage.BucketHandle {
+	return b.namedBucketHandle(ctx, b.name)
+}
+
+// namedBucketHandle is like bucketHandle, for the bucket with the given name
+// accessed with the client of b.
+func (b *bucket) namedBucketHandle(ctx context.Context, name string) *storage.BucketHandle {
+	bkt := b.client.Bucket(name)
+	p := callopt.FromContext(ctx).RetryPolicy
+	if p == nil {
+		return bkt
+	}
+	d := p.WithDefaults()
+	opts := []storage.RetryOption{
+		storage.WithBackoff(gax.Backoff{Initial: d.InitialBackoff, Max: d.MaxBackoff, Multiplier: d.Multiplier}),
+	}
+	if d.MaxAttempts > 0 {
+		opts = append(opts, storage.WithMaxAttempts(d.MaxAttempts))
+	}
+	if p.IsRetryable != nil {
+		opts = append(opts, storage.WithErrorFunc(p.IsRetryable))
+	}
+	return bkt.Retryer(opts...)
+}
+
 // ListPaged implements driver.ListPaged.
 func (b *bucket) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
-	bkt := b.client.Bucket(b.name)
+	bkt := b.bucketHandle(ctx)
 	query := &storage.Query{
 		Prefix:    escapeKey(opts.Prefix),
 		Delimiter: escapeKey(opts.Delimiter),
@@ -495,7 +541,7 @@ func (b *bucket) ErrorAs(err error, i interface{}) bool {
 // Attributes implements driver.Attributes.
 func (b *bucket) Attributes(ctx context.Context, key string) (*driver.Attributes, error) {
 	key = escapeKey(key)
-	bkt := b.client.Bucket(b.name)
+	bkt := b.bucketHandle(ctx)
 	obj := bkt.Object(key)
 	attrs, err := obj.Attrs(ctx)
 	if err != nil {
@@ -533,7 +579,7 @@ func (b *bucket) Attributes(ctx context.Context, key string) (*driver.Attributes
 // NewRangeReader implements driver.NewRangeReader.
 func (b *bucket) NewRangeReader(ctx context.Context, key string, offset, length int64, opts *driver.ReaderOptions) (driver.Reader, error) {
 	key = escapeKey(key)
-	bkt := b.client.Bucket(b.name)
+	bkt := b.bucketHandle(ctx)
 	obj := bkt.Object(key)
 
 	// Add an extra level of indirection so that BeforeRead can replace obj
@@ -610,7 +656,7 @@ func unescapeKey(key string) string {
 // NewTypedWriter implements driver.NewTypedWriter.
 func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, opts *driver.WriterOptions) (driver.Writer, error) {
 	key = escapeKey(key)
-	bkt := b.client.Bucket(b.name)
+	bkt := b.bucketHandle(ctx)
 	obj := bkt.Object(key)
 
 	// Add an extra level of indirection so that BeforeWrite can replace obj
@@ -628,6 +674,7 @@ func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, op
 		w.Metadata = opts.Metadata
 		w.MD5 = opts.ContentMD5
 		w.ForceEmptyContentType = opts.DisableContentTypeDetection
+		w.StorageClass = callopt.Hint(ctx, callopt.StorageClass)
 		return w
 	}
 
@@ -665,16 +712,32 @@ type CopyObjectHandles struct {
 
 // Copy implements driver.Copy.
 func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	return b.copy(ctx, dstKey, b.name, srcKey, opts)
+}
+
+// CopyFrom implements driver.BucketCopier.CopyFrom. The object is copied
+// with the client of b, which must be able to read from src.
+func (b *bucket) CopyFrom(ctx context.Context, dstKey string, src driver.Bucket, srcKey string, opts *driver.CopyOptions) error {
+	srcBucket, ok := src.(*bucket)
+	if !ok {
+		return gcerr.Newf(gcerr.Unimplemented, nil, "gcsblob: CopyFrom is only supported from another gcsblob bucket")
+	}
+	return b.copy(ctx, dstKey, srcBucket.name, srcKey, opts)
+}
+
+// copy copies srcKey in the bucket named srcBucket to dstKey in b. The
+// Copier rewrites the object until it is copied, so it supports objects of
+// any size, in any location and storage class.
+func (b *bucket) copy(ctx context.Context, dstKey, srcBucket, srcKey string, opts *driver.CopyOptions) error {
 	dstKey = escapeKey(dstKey)
 	srcKey = escapeKey(srcKey)
-	bkt := b.client.Bucket(b.name)
 
 	// Add an extra level of indirection so that BeforeCopy can replace the
 	// dst or src ObjectHandles if needed.
 	// Also, make the Copier lazily in case this replacement happens.
 	handles := CopyObjectHandles{
-		Dst: bkt.Object(dstKey),
-		Src: bkt.Object(srcKey),
+		Dst: b.bucketHandle(ctx).Object(dstKey),
+		Src: b.namedBucketHandle(ctx, srcBucket).Object(srcKey),
 	}
 	makeCopier := func() *storage.Copier {
 		return handles.Dst.CopierFrom(handles.Src)
@@ -710,7 +773,7 @@ func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.C
 // Delete implements driver.Delete.
 func (b *bucket) Delete(ctx context.Context, key string) error {
 	key = escapeKey(key)
-	bkt := b.client.Bucket(b.name)
+	bkt := b.bucketHandle(ctx)
 	obj := bkt.Object(key)
 	return obj.Delete(ctx)
 }
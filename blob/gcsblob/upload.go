@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsblob
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/callopt"
+)
+
+// Resumable uploads use the JSON API directly, since the storage package
+// does not expose the sessions of its resumable uploads. See
+// https://cloud.google.com/storage/docs/performing-resumable-uploads.
+
+// defaultUploadURL is the base URL of the upload endpoint of the JSON API.
+const defaultUploadURL = "https://storage.googleapis.com/upload/storage/v1/"
+
+// uploadPartSize is the size that the chunks of resumable uploads must be a
+// multiple of, other than the last one.
+const uploadPartSize = 256 << 10
+
+// statusResumeIncomplete is the status of the responses to the chunks of a
+// resumable upload that is not complete.
+const statusResumeIncomplete = 308
+
+// UploadPartSize implements driver.ResumableUploader.UploadPartSize.
+func (b *bucket) UploadPartSize() int64 { return uploadPartSize }
+
+// BeginUpload implements driver.ResumableUploader.BeginUpload. The state of
+// the upload is the URI of a resumable upload session.
+func (b *bucket) BeginUpload(ctx context.Context, key, contentType string, opts *driver.WriterOptions) ([]byte, error) {
+	key = escapeKey(key)
+	if opts.BeforeWrite != nil {
+		if err := opts.BeforeWrite(func(interface{}) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+	attrs := map[string]interface{}{
+		"name":        key,
+		"contentType": contentType,
+	}
+	for k, v := range map[string]string{
+		"cacheControl":       opts.CacheControl,
+		"contentDisposition": opts.ContentDisposition,
+		"contentEncoding":    opts.ContentEncoding,
+		"contentLanguage":    opts.ContentLanguage,
+		"storageClass":       callopt.Hint(ctx, callopt.StorageClass),
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+	if len(opts.Metadata) > 0 {
+		attrs["metadata"] = opts.Metadata
+	}
+	body, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+	u := b.uploadURL + "b/" + url.PathEscape(b.name) + "/o?uploadType=resumable&name=" + url.QueryEscape(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", contentType)
+	resp, err := b.doUpload(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return nil, fmt.Errorf("gcsblob: no resumable upload session in the response")
+	}
+	return []byte(session), nil
+}
+
+// UploadPart implements driver.ResumableUploader.UploadPart.
+func (b *bucket) UploadPart(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	end := offset + int64(len(p))
+	resp, err := b.putChunk(ctx, state, fmt.Sprintf("bytes %d-%d/*", offset, end-1), p, statusResumeIncomplete)
+	if err != nil {
+		return err
+	}
+	// The Range header holds the bytes persisted so far, which may be fewer
+	// than the bytes sent.
+	if persisted := persistedSize(resp.Header.Get("Range")); persisted < end {
+		return fmt.Errorf("gcsblob: only %d bytes of the upload were persisted, want %d", persisted, end)
+	}
+	return nil
+}
+
+// CompleteUpload implements driver.ResumableUploader.CompleteUpload.
+func (b *bucket) CompleteUpload(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	size := offset + int64(len(p))
+	contentRange := fmt.Sprintf("bytes */%d", size)
+	if len(p) > 0 {
+		contentRange = fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size)
+	}
+	_, err := b.putChunk(ctx, state, contentRange, p, http.StatusOK, http.StatusCreated)
+	return err
+}
+
+// AbortUpload implements driver.ResumableUploader.AbortUpload.
+func (b *bucket) AbortUpload(ctx context.Context, key string, state []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, string(state), nil)
+	if err != nil {
+		return err
+	}
+	// A successful cancellation returns 499 Client Closed Request.
+	_, err = b.doUpload(req, 499, http.StatusNoContent)
+	return err
+}
+
+// putChunk sends p with contentRange to the upload session.
+func (b *bucket) putChunk(ctx context.Context, session []byte, contentRange string, p []byte, statuses ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, string(session), bytes.NewReader(p))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Range", contentRange)
+	return b.doUpload(req, statuses...)
+}
+
+// doUpload sends req, and returns a *googleapi.Error if the status of the
+// response is not one of statuses. The body of the response is closed.
+func (b *bucket) doUpload(req *http.Request, statuses ...int) (*http.Response, error) {
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	for _, s := range statuses {
+		if resp.StatusCode == s {
+			io.Copy(io.Discard, resp.Body)
+			return resp, nil
+		}
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return nil, &googleapi.Error{Code: resp.StatusCode, Message: string(body), Header: resp.Header}
+}
+
+// persistedSize returns the number of bytes persisted according to the
+// Range header of a response to a chunk, like "bytes=0-1048575".
+func persistedSize(rng string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(rng, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsblob
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/gcp"
+)
+
+// fakeUploadServer implements the resumable upload sessions of the JSON API.
+type fakeUploadServer struct {
+	mu       sync.Mutex
+	url      string
+	attrs    map[string]interface{}
+	content  []byte
+	done     bool
+	canceled bool
+}
+
+func (s *fakeUploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bkt/o" && r.URL.Query().Get("uploadType") == "resumable":
+		json.Unmarshal(body, &s.attrs)
+		w.Header().Set("Location", s.url+"/session")
+	case r.Method == http.MethodPut && r.URL.Path == "/session":
+		if s.canceled {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		var start, end int64
+		var total string
+		if n, _ := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%s", &start, &end, &total); n == 3 {
+			if start != int64(len(s.content)) || end-start+1 != int64(len(body)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.content = append(s.content, body...)
+		} else if _, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes */%s", &total); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if total == "*" {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(s.content)-1))
+			w.WriteHeader(statusResumeIncomplete)
+			return
+		}
+		if total != fmt.Sprint(len(s.content)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.done = true
+	case r.Method == http.MethodDelete && r.URL.Path == "/session":
+		s.canceled = true
+		w.WriteHeader(499)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestResumableUpload(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeUploadServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	fake.url = srv.URL
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	drv, err := openBucket(ctx, &gcp.HTTPClient{}, "bkt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket := blob.NewBucket(drv)
+	defer bucket.Close()
+
+	u, err := bucket.BeginUpload(ctx, "my-key", &blob.WriterOptions{ContentType: "text/plain", Metadata: map[string]string{"a": "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.attrs["name"]; got != "my-key" {
+		t.Errorf("got object name %v, want %q", got, "my-key")
+	}
+	if got := fake.attrs["contentType"]; got != "text/plain" {
+		t.Errorf("got content type %v, want %q", got, "text/plain")
+	}
+	part := bytes.Repeat([]byte("x"), uploadPartSize)
+	if err := u.Append(ctx, part[:1]); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v appending a part of 1 byte, want InvalidArgument", err)
+	}
+	if err := u.Append(ctx, part); err != nil {
+		t.Fatal(err)
+	}
+	token, err := u.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err = bucket.ResumeUpload(token); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Append(ctx, part); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Complete(ctx, []byte("end")); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.done || len(fake.content) != 2*uploadPartSize+3 {
+		t.Errorf("got upload done %v with %d bytes, want true with %d bytes", fake.done, len(fake.content), 2*uploadPartSize+3)
+	}
+
+	// An aborted upload cannot be completed.
+	u, err = bucket.BeginUpload(ctx, "aborted", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err = u.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Abort(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = bucket.ResumeUpload(token); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Complete(ctx, nil); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got error %v completing an aborted upload, want NotFound", err)
+	}
+}
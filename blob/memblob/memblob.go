@@ -37,6 +37,7 @@ import (
 	"io"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -83,14 +84,26 @@ type blobEntry struct {
 }
 
 type bucket struct {
-	mu    sync.Mutex
-	blobs map[string]*blobEntry
+	mu      sync.Mutex
+	blobs   map[string]*blobEntry
+	uploads map[string]*upload
+	// nextUpload is the ID of the next upload.
+	nextUpload int
+}
+
+// upload is a resumable upload in progress.
+type upload struct {
+	key         string
+	contentType string
+	opts        *driver.WriterOptions
+	parts       map[int][]byte
 }
 
 // openBucket creates a driver.Bucket backed by memory.
 func openBucket(_ *Options) driver.Bucket {
 	return &bucket{
-		blobs: map[string]*blobEntry{},
+		blobs:   map[string]*blobEntry{},
+		uploads: map[string]*upload{},
 	}
 }
 
@@ -361,6 +374,96 @@ func (w *writer) Close() error {
 	return nil
 }
 
+// UploadPartSize implements driver.ResumableUploader.UploadPartSize.
+func (b *bucket) UploadPartSize() int64 { return 1 }
+
+// BeginUpload implements driver.ResumableUploader.BeginUpload.
+func (b *bucket) BeginUpload(ctx context.Context, key, contentType string, opts *driver.WriterOptions) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("invalid key (empty string)")
+	}
+	if opts.BeforeWrite != nil {
+		if err := opts.BeforeWrite(func(interface{}) bool { return false }); err != nil {
+			return nil, err
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := strconv.Itoa(b.nextUpload)
+	b.nextUpload++
+	b.uploads[id] = &upload{key: key, contentType: contentType, opts: opts, parts: map[int][]byte{}}
+	return []byte(id), nil
+}
+
+// pendingUpload returns the upload with state, which must be locked.
+func (b *bucket) pendingUpload(key string, state []byte) (*upload, error) {
+	u := b.uploads[string(state)]
+	if u == nil || u.key != key {
+		return nil, errNotFound
+	}
+	return u, nil
+}
+
+// UploadPart implements driver.ResumableUploader.UploadPart.
+func (b *bucket) UploadPart(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, err := b.pendingUpload(key, state)
+	if err != nil {
+		return err
+	}
+	u.parts[part] = bytes.Clone(p)
+	return nil
+}
+
+// CompleteUpload implements driver.ResumableUploader.CompleteUpload.
+func (b *bucket) CompleteUpload(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	b.mu.Lock()
+	u, err := b.pendingUpload(key, state)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	w := &writer{
+		ctx:         ctx,
+		b:           b,
+		key:         key,
+		contentType: u.contentType,
+		metadata:    map[string]string{},
+		opts:        u.opts,
+		md5hash:     md5.New(),
+	}
+	for k, v := range u.opts.Metadata {
+		w.metadata[k] = v
+	}
+	for i := 0; i < part; i++ {
+		data, ok := u.parts[i]
+		if !ok {
+			b.mu.Unlock()
+			return fmt.Errorf("part %d of the upload is missing", i)
+		}
+		w.Write(data)
+	}
+	w.Write(p)
+	delete(b.uploads, string(state))
+	b.mu.Unlock()
+	return w.Close()
+}
+
+// AbortUpload implements driver.ResumableUploader.AbortUpload.
+func (b *bucket) AbortUpload(ctx context.Context, key string, state []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.pendingUpload(key, state); err != nil {
+		return err
+	}
+	delete(b.uploads, string(state))
+	return nil
+}
+
 // Copy implements driver.Copy.
 func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
 	b.mu.Lock()
@@ -379,6 +482,31 @@ func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.C
 	return nil
 }
 
+// CopyFrom implements driver.BucketCopier.CopyFrom.
+func (b *bucket) CopyFrom(ctx context.Context, dstKey string, src driver.Bucket, srcKey string, opts *driver.CopyOptions) error {
+	srcBucket, ok := src.(*bucket)
+	if !ok {
+		return errNotImplemented
+	}
+	if opts.BeforeCopy != nil {
+		if err := opts.BeforeCopy(func(interface{}) bool { return false }); err != nil {
+			return err
+		}
+	}
+	// The buckets are not locked together, so that copies in opposite
+	// directions cannot deadlock.
+	srcBucket.mu.Lock()
+	v := srcBucket.blobs[srcKey]
+	srcBucket.mu.Unlock()
+	if v == nil {
+		return errNotFound
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blobs[dstKey] = v
+	return nil
+}
+
 // Delete implements driver.Delete.
 func (b *bucket) Delete(ctx context.Context, key string) error {
 	b.mu.Lock()
@@ -391,6 +519,22 @@ func (b *bucket) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeleteBatch implements driver.BatchDeleter.DeleteBatch.
+func (b *bucket) DeleteBatch(ctx context.Context, keys []string) ([]error, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		if b.blobs[key] == nil {
+			errs[i] = errNotFound
+			continue
+		}
+		delete(b.blobs, key)
+	}
+	return errs, nil
+}
+
 func (b *bucket) SignedURL(ctx context.Context, key string, opts *driver.SignedURLOptions) (string, error) {
 	return "", errNotImplemented
 }
@@ -38,6 +38,11 @@
 //     experimentation.
 //   - Metadata values: Escaped using URL encoding.
 //
+// # Per-call options
+//
+// s3blob supports the callopt.StorageClass hint of gocloud.dev/callopt, for
+// the storage class of objects written, such as "STANDARD_IA".
+//
 // # As
 //
 // s3blob exposes the following types for As:
@@ -50,8 +55,11 @@
 //   - Reader: (V1) s3.GetObjectOutput; (V2) s3v2.GetObjectInput
 //   - ReaderOptions.BeforeRead: (V1) *s3.GetObjectInput; (V2) *s3v2.GetObjectInput or *[]func(*s3v2.Options)
 //   - Attributes: (V1) s3.HeadObjectOutput; (V2)s3v2.HeadObjectOutput
-//   - CopyOptions.BeforeCopy: *(V1) s3.CopyObjectInput; (V2) s3v2.CopyObjectInput
-//   - WriterOptions.BeforeWrite: (V1) *s3manager.UploadInput, *s3manager.Uploader; (V2) *s3v2.PutObjectInput, *s3v2manager.Uploader
+//   - CopyOptions.BeforeCopy: *(V1) s3.CopyObjectInput; (V2) s3v2.CopyObjectInput.
+//     Objects larger than 5 GiB are copied with a multipart upload created
+//     from the fields of the CopyObjectInput.
+//   - WriterOptions.BeforeWrite: (V1) *s3manager.UploadInput, *s3manager.Uploader; (V2) *s3v2.PutObjectInput, *s3v2manager.Uploader;
+//     for Bucket.BeginUpload, (V1) *s3.CreateMultipartUploadInput; (V2) *s3v2.CreateMultipartUploadInput
 //   - SignedURLOptions.BeforeSign:
 //     (V1) *s3.GetObjectInput; (V2) *s3v2.GetObjectInput, when Options.Method == http.MethodGet, or
 //     (V1) *s3.PutObjectInput; (V2) *s3v2.PutObjectInput, when Options.Method == http.MethodPut, or
@@ -59,6 +67,7 @@
 package s3blob // import "gocloud.dev/blob/s3blob"
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
@@ -85,9 +94,11 @@ import (
 	gcaws "gocloud.dev/aws"
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/driver"
+	"gocloud.dev/callopt"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/escape"
 	"gocloud.dev/internal/gcerr"
+	"golang.org/x/sync/errgroup"
 )
 
 const defaultPageSize = 1000
@@ -442,8 +453,10 @@ func (b *bucket) ErrorCode(err error) gcerrors.ErrorCode {
 		code = e.Code()
 	}
 	switch {
-	case code == "NoSuchBucket" || code == "NoSuchKey" || code == "NotFound" || code == s3.ErrCodeObjectNotInActiveTierError:
+	case code == "NoSuchBucket" || code == "NoSuchKey" || code == "NotFound" || code == s3.ErrCodeObjectNotInActiveTierError || code == s3.ErrCodeNoSuchUpload:
 		return gcerrors.NotFound
+	case code == "PreconditionFailed" || code == "ConditionalRequestConflict":
+		return gcerrors.FailedPrecondition
 	default:
 		return gcerrors.Unknown
 	}
@@ -1029,6 +1042,9 @@ func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, op
 		if b.kmsKeyId != "" {
 			reqV2.SSEKMSKeyId = aws.String(b.kmsKeyId)
 		}
+		if sc := callopt.Hint(ctx, callopt.StorageClass); sc != "" {
+			reqV2.StorageClass = typesv2.StorageClass(sc)
+		}
 		if opts.BeforeWrite != nil {
 			asFunc := func(i interface{}) bool {
 				// Note that since the Go CDK Blob
@@ -1108,6 +1124,9 @@ func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, op
 		if b.kmsKeyId != "" {
 			req.SSEKMSKeyId = aws.String(b.kmsKeyId)
 		}
+		if sc := callopt.Hint(ctx, callopt.StorageClass); sc != "" {
+			req.StorageClass = aws.String(sc)
+		}
 		if opts.BeforeWrite != nil {
 			asFunc := func(i interface{}) bool {
 				pu, ok := i.(**s3manager.Uploader)
@@ -1135,11 +1154,37 @@ func (b *bucket) NewTypedWriter(ctx context.Context, key, contentType string, op
 	}
 }
 
+// maxCopyObjectSize is the size of the largest object that CopyObject can
+// copy. Larger objects are copied in parts with UploadPartCopy.
+const maxCopyObjectSize = 5 << 30
+
+const (
+	// copyPartSize is the size of the parts of a multipart copy.
+	copyPartSize = 512 << 20
+	// copyPartConcurrency is the number of parts copied concurrently.
+	copyPartConcurrency = 8
+)
+
 // Copy implements driver.Copy.
 func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.CopyOptions) error {
+	return b.copy(ctx, dstKey, b.name, srcKey, opts)
+}
+
+// CopyFrom implements driver.BucketCopier.CopyFrom.
+func (b *bucket) CopyFrom(ctx context.Context, dstKey string, src driver.Bucket, srcKey string, opts *driver.CopyOptions) error {
+	srcBucket, ok := src.(*bucket)
+	if !ok {
+		return gcerr.Newf(gcerr.Unimplemented, nil, "s3blob: CopyFrom is only supported from another s3blob bucket")
+	}
+	return b.copy(ctx, dstKey, srcBucket.name, srcKey, opts)
+}
+
+// copy copies srcKey in the bucket named srcBucket to dstKey in b. If the
+// object is too large for CopyObject, it is copied in parts.
+func (b *bucket) copy(ctx context.Context, dstKey, srcBucket, srcKey string, opts *driver.CopyOptions) error {
 	dstKey = escapeKey(dstKey)
 	srcKey = escapeKey(srcKey)
-	srcKeyWithBucketEscaped := url.QueryEscape(b.name + "/" + srcKey)
+	srcKeyWithBucketEscaped := url.QueryEscape(srcBucket + "/" + srcKey)
 	if b.useV2 {
 		input := &s3v2.CopyObjectInput{
 			Bucket:     aws.String(b.name),
@@ -1166,6 +1211,15 @@ func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.C
 			}
 		}
 		_, err := b.clientV2.CopyObject(ctx, input)
+		if err != nil && b.isCopySourceTooLarge(err) {
+			head, herr := b.clientV2.HeadObject(ctx, &s3v2.HeadObjectInput{
+				Bucket: aws.String(srcBucket),
+				Key:    aws.String(srcKey),
+			})
+			if herr == nil && aws.Int64Value(head.ContentLength) > maxCopyObjectSize {
+				return b.multipartCopyV2(ctx, input, head)
+			}
+		}
 		return err
 	} else {
 		input := &s3.CopyObjectInput{
@@ -1193,8 +1247,178 @@ func (b *bucket) Copy(ctx context.Context, dstKey, srcKey string, opts *driver.C
 			}
 		}
 		_, err := b.client.CopyObjectWithContext(ctx, input)
+		if err != nil && b.isCopySourceTooLarge(err) {
+			head, herr := b.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(srcBucket),
+				Key:    aws.String(srcKey),
+			})
+			if herr == nil && aws.Int64Value(head.ContentLength) > maxCopyObjectSize {
+				return b.multipartCopy(ctx, input, head)
+			}
+		}
+		return err
+	}
+}
+
+// isCopySourceTooLarge reports whether err may have been returned by
+// CopyObject because the source object is larger than maxCopyObjectSize.
+// S3 does not have a specific error code for it.
+func (b *bucket) isCopySourceTooLarge(err error) bool {
+	if b.useV2 {
+		var ae smithy.APIError
+		return errors.As(err, &ae) && ae.ErrorCode() == "InvalidRequest"
+	}
+	e, ok := err.(awserr.Error)
+	return ok && e.Code() == "InvalidRequest"
+}
+
+// copyPartRange returns the CopySourceRange of the part with index i of a
+// multipart copy of an object of the given size.
+func copyPartRange(i int, size int64) string {
+	start := int64(i) * copyPartSize
+	end := start + copyPartSize - 1
+	if end >= size {
+		end = size - 1
+	}
+	return fmt.Sprintf("bytes=%d-%d", start, end)
+}
+
+// multipartCopyV2 copies the object described by in, whose source has the
+// attributes head, in parts. The attributes of the copy are taken from the
+// source unless in.MetadataDirective is REPLACE.
+func (b *bucket) multipartCopyV2(ctx context.Context, in *s3v2.CopyObjectInput, head *s3v2.HeadObjectOutput) error {
+	create := &s3v2.CreateMultipartUploadInput{
+		Bucket:               in.Bucket,
+		Key:                  in.Key,
+		ACL:                  in.ACL,
+		CacheControl:         head.CacheControl,
+		ContentDisposition:   head.ContentDisposition,
+		ContentEncoding:      head.ContentEncoding,
+		ContentLanguage:      head.ContentLanguage,
+		ContentType:          head.ContentType,
+		Metadata:             head.Metadata,
+		ServerSideEncryption: in.ServerSideEncryption,
+		SSEKMSKeyId:          in.SSEKMSKeyId,
+		StorageClass:         in.StorageClass,
+		Tagging:              in.Tagging,
+	}
+	if in.MetadataDirective == typesv2.MetadataDirectiveReplace {
+		create.CacheControl = in.CacheControl
+		create.ContentDisposition = in.ContentDisposition
+		create.ContentEncoding = in.ContentEncoding
+		create.ContentLanguage = in.ContentLanguage
+		create.ContentType = in.ContentType
+		create.Metadata = in.Metadata
+	}
+	upload, err := b.clientV2.CreateMultipartUpload(ctx, create)
+	if err != nil {
+		return err
+	}
+	size := aws.Int64Value(head.ContentLength)
+	parts := make([]typesv2.CompletedPart, (size+copyPartSize-1)/copyPartSize)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(copyPartConcurrency)
+	for i := range parts {
+		i := i
+		g.Go(func() error {
+			out, err := b.clientV2.UploadPartCopy(gctx, &s3v2.UploadPartCopyInput{
+				Bucket:          in.Bucket,
+				Key:             in.Key,
+				UploadId:        upload.UploadId,
+				PartNumber:      aws.Int32(int32(i + 1)),
+				CopySource:      in.CopySource,
+				CopySourceRange: aws.String(copyPartRange(i, size)),
+			})
+			if err != nil {
+				return err
+			}
+			parts[i] = typesv2.CompletedPart{ETag: out.CopyPartResult.ETag, PartNumber: aws.Int32(int32(i + 1))}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		b.clientV2.AbortMultipartUpload(ctx, &s3v2.AbortMultipartUploadInput{
+			Bucket:   in.Bucket,
+			Key:      in.Key,
+			UploadId: upload.UploadId,
+		})
+		return err
+	}
+	_, err = b.clientV2.CompleteMultipartUpload(ctx, &s3v2.CompleteMultipartUploadInput{
+		Bucket:          in.Bucket,
+		Key:             in.Key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &typesv2.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
+// multipartCopy is the V1 version of multipartCopyV2.
+func (b *bucket) multipartCopy(ctx context.Context, in *s3.CopyObjectInput, head *s3.HeadObjectOutput) error {
+	create := &s3.CreateMultipartUploadInput{
+		Bucket:               in.Bucket,
+		Key:                  in.Key,
+		ACL:                  in.ACL,
+		CacheControl:         head.CacheControl,
+		ContentDisposition:   head.ContentDisposition,
+		ContentEncoding:      head.ContentEncoding,
+		ContentLanguage:      head.ContentLanguage,
+		ContentType:          head.ContentType,
+		Metadata:             head.Metadata,
+		ServerSideEncryption: in.ServerSideEncryption,
+		SSEKMSKeyId:          in.SSEKMSKeyId,
+		StorageClass:         in.StorageClass,
+		Tagging:              in.Tagging,
+	}
+	if aws.StringValue(in.MetadataDirective) == s3.MetadataDirectiveReplace {
+		create.CacheControl = in.CacheControl
+		create.ContentDisposition = in.ContentDisposition
+		create.ContentEncoding = in.ContentEncoding
+		create.ContentLanguage = in.ContentLanguage
+		create.ContentType = in.ContentType
+		create.Metadata = in.Metadata
+	}
+	upload, err := b.client.CreateMultipartUploadWithContext(ctx, create)
+	if err != nil {
+		return err
+	}
+	size := aws.Int64Value(head.ContentLength)
+	parts := make([]*s3.CompletedPart, (size+copyPartSize-1)/copyPartSize)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(copyPartConcurrency)
+	for i := range parts {
+		i := i
+		g.Go(func() error {
+			out, err := b.client.UploadPartCopyWithContext(gctx, &s3.UploadPartCopyInput{
+				Bucket:          in.Bucket,
+				Key:             in.Key,
+				UploadId:        upload.UploadId,
+				PartNumber:      aws.Int64(int64(i + 1)),
+				CopySource:      in.CopySource,
+				CopySourceRange: aws.String(copyPartRange(i, size)),
+			})
+			if err != nil {
+				return err
+			}
+			parts[i] = &s3.CompletedPart{ETag: out.CopyPartResult.ETag, PartNumber: aws.Int64(int64(i + 1))}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		b.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   in.Bucket,
+			Key:      in.Key,
+			UploadId: upload.UploadId,
+		})
 		return err
 	}
+	_, err = b.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          in.Bucket,
+		Key:             in.Key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
 }
 
 // Delete implements driver.Delete.
@@ -1220,6 +1444,320 @@ func (b *bucket) Delete(ctx context.Context, key string) error {
 	}
 }
 
+const (
+	// uploadPartSize is the size that the parts of resumable uploads must be
+	// a multiple of. It is the minimum size of the parts of multipart
+	// uploads, other than the last one.
+	uploadPartSize = 5 << 20
+	// maxUploadParts is the largest number of parts of a multipart upload.
+	maxUploadParts = 10000
+)
+
+// UploadPartSize implements driver.ResumableUploader.UploadPartSize.
+func (b *bucket) UploadPartSize() int64 { return uploadPartSize }
+
+// BeginUpload implements driver.ResumableUploader.BeginUpload. The state of
+// the upload is the ID of a multipart upload.
+func (b *bucket) BeginUpload(ctx context.Context, key, contentType string, opts *driver.WriterOptions) ([]byte, error) {
+	key = escapeKey(key)
+	md := make(map[string]string, len(opts.Metadata))
+	for k, v := range opts.Metadata {
+		// See the package comments for more details on escaping of metadata
+		// keys & values.
+		k = escape.HexEscape(url.PathEscape(k), func(runes []rune, i int) bool {
+			c := runes[i]
+			return c == '@' || c == ':' || c == '='
+		})
+		md[k] = url.PathEscape(v)
+	}
+	if b.useV2 {
+		in := &s3v2.CreateMultipartUploadInput{
+			Bucket:      aws.String(b.name),
+			ContentType: aws.String(contentType),
+			Key:         aws.String(key),
+			Metadata:    md,
+		}
+		if opts.CacheControl != "" {
+			in.CacheControl = aws.String(opts.CacheControl)
+		}
+		if opts.ContentDisposition != "" {
+			in.ContentDisposition = aws.String(opts.ContentDisposition)
+		}
+		if opts.ContentEncoding != "" {
+			in.ContentEncoding = aws.String(opts.ContentEncoding)
+		}
+		if opts.ContentLanguage != "" {
+			in.ContentLanguage = aws.String(opts.ContentLanguage)
+		}
+		if b.encryptionType != "" {
+			in.ServerSideEncryption = b.encryptionType
+		}
+		if b.kmsKeyId != "" {
+			in.SSEKMSKeyId = aws.String(b.kmsKeyId)
+		}
+		if sc := callopt.Hint(ctx, callopt.StorageClass); sc != "" {
+			in.StorageClass = typesv2.StorageClass(sc)
+		}
+		if opts.BeforeWrite != nil {
+			asFunc := func(i interface{}) bool {
+				if p, ok := i.(**s3v2.CreateMultipartUploadInput); ok {
+					*p = in
+					return true
+				}
+				return false
+			}
+			if err := opts.BeforeWrite(asFunc); err != nil {
+				return nil, err
+			}
+		}
+		out, err := b.clientV2.CreateMultipartUpload(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(aws.StringValue(out.UploadId)), nil
+	} else {
+		in := &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(b.name),
+			ContentType: aws.String(contentType),
+			Key:         aws.String(key),
+			Metadata:    aws.StringMap(md),
+		}
+		if opts.CacheControl != "" {
+			in.CacheControl = aws.String(opts.CacheControl)
+		}
+		if opts.ContentDisposition != "" {
+			in.ContentDisposition = aws.String(opts.ContentDisposition)
+		}
+		if opts.ContentEncoding != "" {
+			in.ContentEncoding = aws.String(opts.ContentEncoding)
+		}
+		if opts.ContentLanguage != "" {
+			in.ContentLanguage = aws.String(opts.ContentLanguage)
+		}
+		if b.encryptionType != "" {
+			in.ServerSideEncryption = aws.String(string(b.encryptionType))
+		}
+		if b.kmsKeyId != "" {
+			in.SSEKMSKeyId = aws.String(b.kmsKeyId)
+		}
+		if sc := callopt.Hint(ctx, callopt.StorageClass); sc != "" {
+			in.StorageClass = aws.String(sc)
+		}
+		if opts.BeforeWrite != nil {
+			asFunc := func(i interface{}) bool {
+				if p, ok := i.(**s3.CreateMultipartUploadInput); ok {
+					*p = in
+					return true
+				}
+				return false
+			}
+			if err := opts.BeforeWrite(asFunc); err != nil {
+				return nil, err
+			}
+		}
+		out, err := b.client.CreateMultipartUploadWithContext(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(aws.StringValue(out.UploadId)), nil
+	}
+}
+
+// UploadPart implements driver.ResumableUploader.UploadPart.
+func (b *bucket) UploadPart(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	if part >= maxUploadParts {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "s3blob: uploads cannot have more than %d parts", maxUploadParts)
+	}
+	key = escapeKey(key)
+	if b.useV2 {
+		_, err := b.clientV2.UploadPart(ctx, &s3v2.UploadPartInput{
+			Bucket:        aws.String(b.name),
+			Key:           aws.String(key),
+			UploadId:      aws.String(string(state)),
+			PartNumber:    aws.Int32(int32(part + 1)),
+			Body:          bytes.NewReader(p),
+			ContentLength: aws.Int64(int64(len(p))),
+		})
+		return err
+	} else {
+		_, err := b.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(b.name),
+			Key:        aws.String(key),
+			UploadId:   aws.String(string(state)),
+			PartNumber: aws.Int64(int64(part + 1)),
+			Body:       bytes.NewReader(p),
+		})
+		return err
+	}
+}
+
+// CompleteUpload implements driver.ResumableUploader.CompleteUpload. The
+// ETags of the parts are listed, so that they need not be kept in the state.
+func (b *bucket) CompleteUpload(ctx context.Context, key string, state []byte, part int, offset int64, p []byte) error {
+	// A multipart upload has at least one part, which may be empty if it is
+	// the only one.
+	if len(p) > 0 || part == 0 {
+		if err := b.UploadPart(ctx, key, state, part, offset, p); err != nil {
+			return err
+		}
+		part++
+	}
+	key = escapeKey(key)
+	if b.useV2 {
+		var parts []typesv2.CompletedPart
+		in := &s3v2.ListPartsInput{
+			Bucket:   aws.String(b.name),
+			Key:      aws.String(key),
+			UploadId: aws.String(string(state)),
+		}
+		for {
+			out, err := b.clientV2.ListParts(ctx, in)
+			if err != nil {
+				return err
+			}
+			for _, pt := range out.Parts {
+				if n := aws.Int32Value(pt.PartNumber); n >= 1 && int(n) <= part {
+					parts = append(parts, typesv2.CompletedPart{ETag: pt.ETag, PartNumber: pt.PartNumber})
+				}
+			}
+			if !aws.BoolValue(out.IsTruncated) {
+				break
+			}
+			in.PartNumberMarker = out.NextPartNumberMarker
+		}
+		if len(parts) != part {
+			return gcerr.Newf(gcerr.FailedPrecondition, nil, "s3blob: found %d parts of the upload, want %d", len(parts), part)
+		}
+		_, err := b.clientV2.CompleteMultipartUpload(ctx, &s3v2.CompleteMultipartUploadInput{
+			Bucket:          aws.String(b.name),
+			Key:             aws.String(key),
+			UploadId:        aws.String(string(state)),
+			MultipartUpload: &typesv2.CompletedMultipartUpload{Parts: parts},
+		})
+		return err
+	} else {
+		var parts []*s3.CompletedPart
+		in := &s3.ListPartsInput{
+			Bucket:   aws.String(b.name),
+			Key:      aws.String(key),
+			UploadId: aws.String(string(state)),
+		}
+		for {
+			out, err := b.client.ListPartsWithContext(ctx, in)
+			if err != nil {
+				return err
+			}
+			for _, pt := range out.Parts {
+				if n := aws.Int64Value(pt.PartNumber); n >= 1 && int(n) <= part {
+					parts = append(parts, &s3.CompletedPart{ETag: pt.ETag, PartNumber: pt.PartNumber})
+				}
+			}
+			if !aws.BoolValue(out.IsTruncated) {
+				break
+			}
+			in.PartNumberMarker = out.NextPartNumberMarker
+		}
+		if len(parts) != part {
+			return gcerr.Newf(gcerr.FailedPrecondition, nil, "s3blob: found %d parts of the upload, want %d", len(parts), part)
+		}
+		_, err := b.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(b.name),
+			Key:             aws.String(key),
+			UploadId:        aws.String(string(state)),
+			MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		})
+		return err
+	}
+}
+
+// AbortUpload implements driver.ResumableUploader.AbortUpload.
+func (b *bucket) AbortUpload(ctx context.Context, key string, state []byte) error {
+	key = escapeKey(key)
+	if b.useV2 {
+		_, err := b.clientV2.AbortMultipartUpload(ctx, &s3v2.AbortMultipartUploadInput{
+			Bucket:   aws.String(b.name),
+			Key:      aws.String(key),
+			UploadId: aws.String(string(state)),
+		})
+		return err
+	} else {
+		_, err := b.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(b.name),
+			Key:      aws.String(key),
+			UploadId: aws.String(string(state)),
+		})
+		return err
+	}
+}
+
+// maxDeleteObjects is the largest number of objects that DeleteObjects can
+// delete in one request.
+const maxDeleteObjects = 1000
+
+// DeleteBatch implements driver.BatchDeleter.DeleteBatch.
+func (b *bucket) DeleteBatch(ctx context.Context, keys []string) ([]error, error) {
+	errs := make([]error, len(keys))
+	deleted := false
+	for start := 0; start < len(keys); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(keys) {
+			end = len(keys)
+		}
+		// indexes maps the escaped keys of the request to their indexes in
+		// keys, to match the errors that S3 returns by key.
+		indexes := map[string][]int{}
+		for i := start; i < end; i++ {
+			key := escapeKey(keys[i])
+			indexes[key] = append(indexes[key], i)
+		}
+		var err error
+		if b.useV2 {
+			input := &s3v2.DeleteObjectsInput{
+				Bucket: aws.String(b.name),
+				Delete: &typesv2.Delete{Quiet: aws.Bool(true)},
+			}
+			for key := range indexes {
+				input.Delete.Objects = append(input.Delete.Objects, typesv2.ObjectIdentifier{Key: aws.String(key)})
+			}
+			var out *s3v2.DeleteObjectsOutput
+			if out, err = b.clientV2.DeleteObjects(ctx, input); err == nil {
+				for _, e := range out.Errors {
+					for _, i := range indexes[aws.StringValue(e.Key)] {
+						errs[i] = &smithy.GenericAPIError{Code: aws.StringValue(e.Code), Message: aws.StringValue(e.Message)}
+					}
+				}
+			}
+		} else {
+			input := &s3.DeleteObjectsInput{
+				Bucket: aws.String(b.name),
+				Delete: &s3.Delete{Quiet: aws.Bool(true)},
+			}
+			for key := range indexes {
+				input.Delete.Objects = append(input.Delete.Objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+			}
+			var out *s3.DeleteObjectsOutput
+			if out, err = b.client.DeleteObjectsWithContext(ctx, input); err == nil {
+				for _, e := range out.Errors {
+					for _, i := range indexes[aws.StringValue(e.Key)] {
+						errs[i] = awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil)
+					}
+				}
+			}
+		}
+		if err != nil {
+			if !deleted {
+				return nil, err
+			}
+			for i := start; i < end; i++ {
+				errs[i] = err
+			}
+			continue
+		}
+		deleted = true
+	}
+	return errs, nil
+}
+
 func (b *bucket) SignedURL(ctx context.Context, key string, opts *driver.SignedURLOptions) (string, error) {
 	key = escapeKey(key)
 	var req *request.Request
@@ -35,8 +35,12 @@ import (
 	"gocloud.dev/blob/driver"
 	"gocloud.dev/blob/drivertest"
 	"gocloud.dev/internal/testing/setup"
+	"gocloud.dev/testing/emulators"
 )
 
+// Run the conformance tests with -emulators to run them against a MinIO
+// server in Docker instead of replaying the recorded interactions.
+
 // These constants record the region & bucket used for the last --record.
 // If you want to use --record mode,
 // 1. Create a bucket in your AWS project from the S3 management console.
@@ -105,20 +109,46 @@ func (h *harness) Close() {
 	h.closer()
 }
 
+// withEmulator returns newHarness, unless the tests are run with
+// -emulators; then it returns a HarnessMaker for SDK V2 if useV2 is true, and
+// V1 otherwise, with opts, whose harnesses use a MinIO server started for t
+// instead of replaying recorded interactions.
+func withEmulator(t *testing.T, newHarness drivertest.HarnessMaker, useV2 bool, opts *Options) drivertest.HarnessMaker {
+	if !*setup.Emulators {
+		return newHarness
+	}
+	ctx := context.Background()
+	minio := emulators.StartMinIO(ctx, t)
+	minio.CreateBucket(ctx, t, bucketName)
+	return func(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+		h := &harness{useV2: useV2, opts: opts, rt: http.DefaultTransport, closer: func() {}}
+		if useV2 {
+			h.clientV2 = s3v2.NewFromConfig(minio.Config(), func(o *s3v2.Options) { o.UsePathStyle = true })
+			return h, nil
+		}
+		sess, err := minio.Session()
+		if err != nil {
+			return nil, err
+		}
+		h.session = sess
+		return h, nil
+	}
+}
+
 func TestConformance(t *testing.T) {
-	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyContentLanguage{useV2: false, usingLegacyList: false}})
+	drivertest.RunConformanceTests(t, withEmulator(t, newHarness, false, nil), []drivertest.AsTest{verifyContentLanguage{useV2: false, usingLegacyList: false}})
 }
 
 func TestConformanceUsingLegacyList(t *testing.T) {
-	drivertest.RunConformanceTests(t, newHarnessUsingLegacyList, []drivertest.AsTest{verifyContentLanguage{useV2: false, usingLegacyList: true}})
+	drivertest.RunConformanceTests(t, withEmulator(t, newHarnessUsingLegacyList, false, &Options{UseLegacyList: true}), []drivertest.AsTest{verifyContentLanguage{useV2: false, usingLegacyList: true}})
 }
 
 func TestConformanceV2(t *testing.T) {
-	drivertest.RunConformanceTests(t, newHarnessV2, []drivertest.AsTest{verifyContentLanguage{useV2: true, usingLegacyList: false}})
+	drivertest.RunConformanceTests(t, withEmulator(t, newHarnessV2, true, nil), []drivertest.AsTest{verifyContentLanguage{useV2: true, usingLegacyList: false}})
 }
 
 func TestConformanceUsingLegacyListV2(t *testing.T) {
-	drivertest.RunConformanceTests(t, newHarnessUsingLegacyListV2, []drivertest.AsTest{verifyContentLanguage{useV2: true, usingLegacyList: true}})
+	drivertest.RunConformanceTests(t, withEmulator(t, newHarnessUsingLegacyListV2, true, &Options{UseLegacyList: true}), []drivertest.AsTest{verifyContentLanguage{useV2: true, usingLegacyList: true}})
 }
 
 func BenchmarkS3blob(b *testing.B) {
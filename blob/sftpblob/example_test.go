@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpblob_test
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/sftpblob"
+)
+
+func ExampleOpenBucket() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// Authenticate with a private key, and verify the host key of the server
+	// with a known_hosts file.
+	pem, err := os.ReadFile("/path/to/id_ed25519")
+	if err != nil {
+		log.Fatal(err)
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hostKeyCallback, err := knownhosts.New("/path/to/known_hosts")
+	if err != nil {
+		log.Fatal(err)
+	}
+	config := &ssh.ClientConfig{
+		User:            "partner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	// Create a bucket for the directory "/outgoing" of the server, without
+	// metadata files, and with up to 8 connections to the server.
+	bucket, err := sftpblob.OpenBucket(ctx, "sftp.example.com:22", "/outgoing", config, &sftpblob.Options{
+		MaxConns: 8,
+		Metadata: sftpblob.MetadataDontWrite,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bucket.Close()
+}
+
+func Example_openBucketFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/blob/sftpblob"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// blob.OpenBucket creates a *blob.Bucket from a URL.
+	// This URL will open the directory "/outgoing" of the server
+	// sftp.example.com, authenticating as "partner" with a private key, and
+	// verifying the host key with ~/.ssh/known_hosts.
+	bucket, err := blob.OpenBucket(ctx, "sftp://partner@sftp.example.com/outgoing?private_key_path=/path/to/id_ed25519")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bucket.Close()
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpblob
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// conn is an SFTP session over its own SSH connection.
+type conn struct {
+	ssh  *ssh.Client
+	sftp *sftp.Client
+	// dead is set when the session is shut down, for example because the
+	// server closed the connection.
+	dead atomic.Bool
+}
+
+func (c *conn) close() {
+	c.sftp.Close()
+	c.ssh.Close()
+}
+
+// dial opens an SFTP session to addr. Dialing, and the SSH handshake, are
+// aborted if ctx is done.
+func dial(ctx context.Context, addr string, config *ssh.ClientConfig, opts []sftp.ClientOption) (*conn, error) {
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { nc.Close() })
+	sc, chans, reqs, err := ssh.NewClientConn(nc, addr, config)
+	if !stop() {
+		if err == nil {
+			sc.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	sshClient := ssh.NewClient(sc, chans, reqs)
+	sftpClient, err := sftp.NewClient(sshClient, opts...)
+	if err != nil {
+		sshClient.Close()
+		return nil, err
+	}
+	c := &conn{ssh: sshClient, sftp: sftpClient}
+	go func() {
+		c.sftp.Wait()
+		c.dead.Store(true)
+	}()
+	return c, nil
+}
+
+// pool limits the number of SFTP sessions open to a server at once, and
+// keeps the idle ones open for reuse.
+type pool struct {
+	dial func(context.Context) (*conn, error)
+	// sem holds a value for each session in use, or being dialed.
+	sem chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+func newPool(maxConns int, dial func(context.Context) (*conn, error)) *pool {
+	return &pool{dial: dial, sem: make(chan struct{}, maxConns)}
+}
+
+// get returns an idle session, or dials a new one. It blocks while the pool
+// has as many sessions in use as it allows. The session must be returned
+// with put.
+func (p *pool) get(ctx context.Context) (*conn, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		<-p.sem
+		return nil, errPoolClosed
+	}
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if !c.dead.Load() {
+			p.mu.Unlock()
+			return c, nil
+		}
+		c.close()
+	}
+	p.mu.Unlock()
+	c, err := p.dial(ctx)
+	if err != nil {
+		<-p.sem
+		return nil, err
+	}
+	return c, nil
+}
+
+// put returns a session obtained from get, after an operation that failed
+// with err, or nil. The session is closed instead of being kept for reuse if
+// err may have been caused by a broken connection.
+func (p *pool) put(c *conn, err error) {
+	defer func() { <-p.sem }()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || c.dead.Load() || !isRequestError(err) {
+		c.close()
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+// close closes the idle sessions. Sessions in use are closed when they are
+// returned.
+func (p *pool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, c := range p.idle {
+		c.close()
+	}
+	p.idle = nil
+}
+
+var errPoolClosed = errors.New("sftpblob: bucket is closed")
+
+// isRequestError reports whether err is nil, or an error returned by the
+// server for a request, after which the session can still be used.
+func isRequestError(err error) bool {
+	var serr *sftp.StatusError
+	return err == nil ||
+		errors.Is(err, fs.ErrNotExist) ||
+		errors.Is(err, fs.ErrPermission) ||
+		errors.Is(err, fs.ErrExist) ||
+		errors.As(err, &serr)
+}
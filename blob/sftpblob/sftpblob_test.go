@@ -0,0 +1,488 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftpblob
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/blob/drivertest"
+	"gocloud.dev/gcerrors"
+)
+
+const (
+	testUser     = "gopher"
+	testPassword = "secret"
+)
+
+// server is an SSH server on localhost that serves the local filesystem
+// with SFTP.
+type server struct {
+	addr    string
+	hostKey ssh.PublicKey
+	// conns is the number of connections accepted.
+	conns atomic.Int32
+
+	ln  net.Listener
+	mu  sync.Mutex
+	ncs []net.Conn
+	wg  sync.WaitGroup
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() == testUser && string(password) == testPassword {
+				return nil, nil
+			}
+			return nil, errors.New("wrong user or password")
+		},
+	}
+	config.AddHostKey(signer)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{addr: ln.Addr().String(), hostKey: signer.PublicKey(), ln: ln}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			s.mu.Lock()
+			s.ncs = append(s.ncs, nc)
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(nc, config)
+			}()
+		}
+	}()
+	t.Cleanup(s.close)
+	return s
+}
+
+// serve serves the SFTP subsystem on the sessions of nc.
+func (s *server) serve(nc net.Conn, config *ssh.ServerConfig) {
+	defer nc.Close()
+	_, chans, reqs, err := ssh.NewServerConn(nc, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			newCh.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, reqs, err := newCh.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer ch.Close()
+			for req := range reqs {
+				// The payload of subsystem requests is the length-prefixed name
+				// of the subsystem.
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 &&
+					int(binary.BigEndian.Uint32(req.Payload)) == len(req.Payload)-4 &&
+					string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if !ok {
+					continue
+				}
+				srv, err := sftp.NewServer(ch)
+				if err != nil {
+					return
+				}
+				srv.Serve()
+				return
+			}
+		}()
+	}
+}
+
+// dropConns closes the connections accepted so far.
+func (s *server) dropConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, nc := range s.ncs {
+		nc.Close()
+	}
+	s.ncs = nil
+}
+
+func (s *server) close() {
+	s.ln.Close()
+	s.dropConns()
+	s.wg.Wait()
+}
+
+func (s *server) clientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            testUser,
+		Auth:            []ssh.AuthMethod{ssh.Password(testPassword)},
+		HostKeyCallback: ssh.FixedHostKey(s.hostKey),
+	}
+}
+
+type harness struct {
+	srv         *server
+	dir         string
+	metadataHow metadataOption
+}
+
+func newHarness(ctx context.Context, t *testing.T, metadataHow metadataOption) (drivertest.Harness, error) {
+	t.Helper()
+	if metadataHow == MetadataDontWrite {
+		// Skip tests for if no metadata gets written.
+		// For these it is currently undefined whether any gets read (back).
+		switch name := t.Name(); {
+		case strings.Contains(name, "ContentType"), strings.HasSuffix(name, "TestAttributes"), strings.Contains(name, "TestMetadata/"):
+			t.SkipNow()
+			return nil, nil
+		}
+	}
+	return &harness{srv: newServer(t), dir: t.TempDir(), metadataHow: metadataHow}, nil
+}
+
+func (h *harness) HTTPClient() *http.Client {
+	return nil
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Bucket, error) {
+	return openBucket(ctx, h.srv.addr, h.dir, h.srv.clientConfig(), &Options{Metadata: h.metadataHow})
+}
+
+func (h *harness) MakeDriverForNonexistentBucket(ctx context.Context) (driver.Bucket, error) {
+	// Does not make sense for this driver, as it verifies
+	// that the directory exists in OpenBucket.
+	return nil, nil
+}
+
+func (h *harness) Close() {}
+
+func TestConformance(t *testing.T) {
+	newHarnessSidecar := func(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+		return newHarness(ctx, t, MetadataInSidecar)
+	}
+	drivertest.RunConformanceTests(t, newHarnessSidecar, []drivertest.AsTest{verifyAs{}})
+}
+
+func TestConformanceSkipMetadata(t *testing.T) {
+	newHarnessSkipMetadata := func(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+		return newHarness(ctx, t, MetadataDontWrite)
+	}
+	drivertest.RunConformanceTests(t, newHarnessSkipMetadata, []drivertest.AsTest{verifyAs{}})
+}
+
+// SFTP-specific unit tests.
+func TestOpenBucket(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	dir := t.TempDir()
+
+	t.Run("DirMissing", func(t *testing.T) {
+		if _, err := OpenBucket(ctx, srv.addr, filepath.Join(dir, "notfound"), srv.clientConfig(), nil); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("NoHostKeyCallback", func(t *testing.T) {
+		config := srv.clientConfig()
+		config.HostKeyCallback = nil
+		if _, err := OpenBucket(ctx, srv.addr, dir, config, nil); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("WrongHostKey", func(t *testing.T) {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := ssh.NewPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := srv.clientConfig()
+		config.HostKeyCallback = ssh.FixedHostKey(key)
+		if _, err := OpenBucket(ctx, srv.addr, dir, config, nil); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("WrongPassword", func(t *testing.T) {
+		config := srv.clientConfig()
+		config.Auth = []ssh.AuthMethod{ssh.Password("wrong")}
+		if _, err := OpenBucket(ctx, srv.addr, dir, config, nil); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+}
+
+func TestSignedURLReturnsUnimplemented(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	b, err := OpenBucket(ctx, srv.addr, t.TempDir(), srv.clientConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	_, gotErr := b.SignedURL(ctx, "key", nil)
+	if gcerrors.Code(gotErr) != gcerrors.Unimplemented {
+		t.Errorf("want Unimplemented error, got %v", gotErr)
+	}
+}
+
+func TestKeyOutsideBucket(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	dir := t.TempDir()
+	b, err := OpenBucket(ctx, srv.addr, dir, srv.clientConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	for _, key := range []string{"..", "a/.."} {
+		if err := b.WriteAll(ctx, key, []byte("x"), nil); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("got error %v writing %q, want InvalidArgument", err, key)
+		}
+	}
+}
+
+func TestConnectionPool(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	b, err := OpenBucket(ctx, srv.addr, t.TempDir(), srv.clientConfig(), &Options{MaxConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if err := b.WriteAll(ctx, "a", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The only connection is in use by the Reader until it is closed.
+	r, err := b.NewReader(ctx, "a", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := b.Attributes(shortCtx, "a"); gcerrors.Code(err) != gcerrors.DeadlineExceeded {
+		t.Errorf("got error %v while the connection is in use, want DeadlineExceeded", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Attributes(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	// Errors returned by the server for a request don't close the connection.
+	if _, err := b.Attributes(ctx, "missing"); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+	if got := srv.conns.Load(); got != 1 {
+		t.Errorf("got %d connections, want 1", got)
+	}
+
+	// Broken connections are replaced.
+	srv.dropConns()
+	var got []byte
+	for i := 0; i < 3; i++ {
+		if got, err = b.ReadAll(ctx, "a"); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("got error %v after the connection was dropped", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q, want %q", got, "hello")
+	}
+	if got := srv.conns.Load(); got != 2 {
+		t.Errorf("got %d connections, want 2", got)
+	}
+}
+
+func TestOpenBucketFromURL(t *testing.T) {
+	srv := newServer(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "myfile.txt"), []byte("hello world"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(srv.hostKey)))
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	knownHosts := fmt.Sprintf("[127.0.0.1]:%d %s\n", srv.ln.Addr().(*net.TCPAddr).Port, authorizedKey)
+	if err := os.WriteFile(knownHostsPath, []byte(knownHosts), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := ssh.FingerprintSHA256(srv.hostKey)
+	base := fmt.Sprintf("sftp://%s:%s@%s%s", testUser, testPassword, srv.addr, filepath.ToSlash(dir))
+
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		{"?host_key=" + url.QueryEscape(fingerprint), false},
+		{"?host_key=" + url.QueryEscape(authorizedKey), false},
+		{"?known_hosts=" + url.QueryEscape(knownHostsPath), false},
+		{"?insecure_ignore_host_key=true&max_conns=2&metadata=skip", false},
+		// Wrong host key.
+		{"?host_key=SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s", true},
+		// Conflicting host key verifications.
+		{"?host_key=" + url.QueryEscape(fingerprint) + "&insecure_ignore_host_key=true", true},
+		// Invalid host key.
+		{"?host_key=invalid", true},
+		// Invalid max_conns.
+		{"?insecure_ignore_host_key=true&max_conns=0", true},
+		// Invalid metadata.
+		{"?insecure_ignore_host_key=true&metadata=other", true},
+		// Missing private key.
+		{"?insecure_ignore_host_key=true&private_key_path=" + url.QueryEscape(filepath.Join(dir, "missing")), true},
+		// Invalid parameter.
+		{"?insecure_ignore_host_key=true&param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			b, err := blob.OpenBucket(ctx, base+test.query)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got err %v, want error %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer b.Close()
+			got, err := b.ReadAll(ctx, "myfile.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "hello world" {
+				t.Errorf("got %q want %q", got, "hello world")
+			}
+		})
+	}
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string { return "verify As types for sftpblob" }
+
+func (verifyAs) BucketCheck(b *blob.Bucket) error {
+	var fi os.FileInfo
+	if !b.As(&fi) {
+		return errors.New("Bucket.As failed")
+	}
+	if !fi.IsDir() {
+		return errors.New("Bucket.As returned a file")
+	}
+	return nil
+}
+
+func (verifyAs) BeforeRead(as func(interface{}) bool) error {
+	var f *sftp.File
+	if !as(&f) {
+		return errors.New("BeforeRead.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) BeforeWrite(as func(interface{}) bool) error {
+	var f *sftp.File
+	if !as(&f) {
+		return errors.New("BeforeWrite.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) BeforeCopy(as func(interface{}) bool) error {
+	var f *sftp.File
+	if !as(&f) {
+		return errors.New("BeforeCopy.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) BeforeList(as func(interface{}) bool) error { return nil }
+
+func (verifyAs) BeforeSign(as func(interface{}) bool) error { return nil }
+
+func (verifyAs) AttributesCheck(attrs *blob.Attributes) error {
+	var fi os.FileInfo
+	if !attrs.As(&fi) {
+		return errors.New("Attributes.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ReaderCheck(r *blob.Reader) error {
+	var f *sftp.File
+	if !r.As(&f) {
+		return errors.New("Reader.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ListObjectCheck(o *blob.ListObject) error {
+	var fi os.FileInfo
+	if !o.As(&fi) {
+		return errors.New("ListObject.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(b *blob.Bucket, err error) error {
+	var perr *os.PathError
+	if !b.ErrorAs(err, &perr) {
+		return errors.New("want ErrorAs to succeed for PathError")
+	}
+	if got := perr.Path; !strings.HasSuffix(got, "/key-does-not-exist") {
+		return fmt.Errorf("got path %q, want suffix %q", got, "/key-does-not-exist")
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"unicode/utf8"
+
+	"gocloud.dev/blob/driver"
+	"gocloud.dev/callopt"
+	"gocloud.dev/internal/gcerr"
+)
+
+// An Upload writes a blob in parts, in an upload that can be paused and
+// resumed, possibly by another process, with its Token. Unlike a Writer, an
+// Upload that fails while writing a part can be resumed from the last part
+// written, instead of from the start.
+//
+// The blob is only created when Complete is called. Uploads that are never
+// completed should be aborted, since services may keep, and charge for,
+// their parts until they are.
+//
+// An Upload is not safe for concurrent use.
+type Upload struct {
+	b     *Bucket
+	u     driver.ResumableUploader
+	key   string
+	state []byte
+	parts int
+	size  int64
+	done  bool
+}
+
+// uploadToken is the serialized form of an Upload.
+type uploadToken struct {
+	Version int    `json:"v"`
+	Key     string `json:"key"`
+	Parts   int    `json:"parts"`
+	Size    int64  `json:"size"`
+	State   []byte `json:"state"`
+}
+
+// uploadTokenVersion is the version of the format of the upload tokens.
+const uploadTokenVersion = 1
+
+// BeginUpload starts an upload of a blob to key, with the attributes of
+// opts. A nil WriterOptions is treated the same as the zero value.
+//
+// The content type is not detected from the content; if opts.ContentType is
+// empty, "application/octet-stream" is used. opts.ContentMD5 is not
+// supported.
+//
+// BeginUpload returns an error for which gcerrors.Code will return
+// gcerrors.Unimplemented if the driver does not support resumable uploads.
+// S3 uses multipart uploads, GCS resumable uploads and Azure block lists.
+func (b *Bucket) BeginUpload(ctx context.Context, key string, opts *WriterOptions) (_ *Upload, err error) {
+	if !utf8.ValidString(key) {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: BeginUpload key must be a valid UTF-8 string: %q", key)
+	}
+	if opts == nil {
+		opts = &WriterOptions{}
+	}
+	if opts.ContentMD5 != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: BeginUpload does not support WriterOptions.ContentMD5")
+	}
+	dopts, err := driverWriterOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	ct := "application/octet-stream"
+	if opts.ContentType != "" {
+		t, p, err := mime.ParseMediaType(opts.ContentType)
+		if err != nil {
+			return nil, err
+		}
+		ct = mime.FormatMediaType(t, p)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, errClosed
+	}
+	u, ok := b.b.(driver.ResumableUploader)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "blob: resumable uploads are not supported by this driver")
+	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = b.tracer.Start(ctx, "BeginUpload")
+	defer func() { b.tracer.End(ctx, err) }()
+	state, err := u.BeginUpload(ctx, key, ct, dopts)
+	if err != nil {
+		return nil, wrapError(b.b, err, key)
+	}
+	return &Upload{b: b, u: u, key: key, state: state}, nil
+}
+
+// ResumeUpload returns the Upload that token was returned for by
+// Upload.Token. The upload continues after the parts that were written
+// before the token was returned.
+//
+// The token must have been returned for an upload of a bucket of the same
+// driver and location as b.
+func (b *Bucket) ResumeUpload(token []byte) (*Upload, error) {
+	var t uploadToken
+	if err := json.Unmarshal(token, &t); err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "blob: invalid upload token")
+	}
+	if t.Version != uploadTokenVersion {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "blob: unsupported upload token version %d", t.Version)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, errClosed
+	}
+	u, ok := b.b.(driver.ResumableUploader)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "blob: resumable uploads are not supported by this driver")
+	}
+	return &Upload{b: b, u: u, key: t.Key, state: t.State, parts: t.Parts, size: t.Size}, nil
+}
+
+// Key returns the key of the blob being uploaded.
+func (u *Upload) Key() string { return u.key }
+
+// Size returns the number of bytes written by Append so far.
+func (u *Upload) Size() int64 { return u.size }
+
+// PartSize returns the size that the parts written with Append must be a
+// multiple of: 5 MiB for S3, 256 KiB for GCS and 1 byte for Azure. Parts
+// whose size is a multiple of 5 MiB are valid for all of them.
+func (u *Upload) PartSize() int64 { return u.u.UploadPartSize() }
+
+// Token returns a token that resumes the upload with Bucket.ResumeUpload,
+// after the parts written so far. It should be saved after each Append.
+func (u *Upload) Token() ([]byte, error) {
+	if u.done {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "blob: upload is already completed or aborted")
+	}
+	return json.Marshal(&uploadToken{
+		Version: uploadTokenVersion,
+		Key:     u.key,
+		Parts:   u.parts,
+		Size:    u.size,
+		State:   u.state,
+	})
+}
+
+// Append writes p as the next part of the blob. The size of p must be a
+// non-zero multiple of PartSize; the last part of the blob, which can have
+// any size, is written by Complete.
+//
+// If Append fails, it can be called again with the same part.
+func (u *Upload) Append(ctx context.Context, p []byte) (err error) {
+	if n := u.u.UploadPartSize(); len(p) == 0 || int64(len(p))%n != 0 {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "blob: Upload.Append size %d is not a non-zero multiple of the part size %d", len(p), n)
+	}
+	if err := u.check(); err != nil {
+		return err
+	}
+	defer u.b.mu.RUnlock()
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = u.b.tracer.Start(ctx, "Upload.Append")
+	defer func() { u.b.tracer.End(ctx, err) }()
+	if err := u.u.UploadPart(ctx, u.key, u.state, u.parts, u.size, p); err != nil {
+		return wrapError(u.b.b, err, u.key)
+	}
+	u.parts++
+	u.size += int64(len(p))
+	return nil
+}
+
+// Complete writes p, which can have any size including zero, as the last
+// part of the blob, and creates the blob from the parts. If the blob already
+// exists, it is overwritten.
+func (u *Upload) Complete(ctx context.Context, p []byte) (err error) {
+	if err := u.check(); err != nil {
+		return err
+	}
+	defer u.b.mu.RUnlock()
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = u.b.tracer.Start(ctx, "Upload.Complete")
+	defer func() { u.b.tracer.End(ctx, err) }()
+	if err := u.u.CompleteUpload(ctx, u.key, u.state, u.parts, u.size, p); err != nil {
+		return wrapError(u.b.b, err, u.key)
+	}
+	u.done = true
+	return nil
+}
+
+// Abort cancels the upload, and deletes the parts written so far. Azure
+// cannot delete the parts; it deletes them if they are not used within a
+// week.
+func (u *Upload) Abort(ctx context.Context) (err error) {
+	if err := u.check(); err != nil {
+		return err
+	}
+	defer u.b.mu.RUnlock()
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = u.b.tracer.Start(ctx, "Upload.Abort")
+	defer func() { u.b.tracer.End(ctx, err) }()
+	if err := u.u.AbortUpload(ctx, u.key, u.state); err != nil {
+		return wrapError(u.b.b, err, u.key)
+	}
+	u.done = true
+	return nil
+}
+
+// check returns an error if u cannot be used. Otherwise, it returns with
+// the bucket read-locked.
+func (u *Upload) check() error {
+	if u.done {
+		return gcerr.Newf(gcerr.FailedPrecondition, nil, "blob: upload is already completed or aborted")
+	}
+	u.b.mu.RLock()
+	if u.b.closed {
+		u.b.mu.RUnlock()
+		return errClosed
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/gcerrors"
+)
+
+func TestUpload(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	md := map[string]string{"color": "blue"}
+	u, err := bucket.BeginUpload(ctx, "big", &blob.WriterOptions{ContentType: "text/plain", Metadata: md})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Append(ctx, []byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	token, err := u.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process resumes the upload from the token.
+	u, err = bucket.ResumeUpload(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Key() != "big" || u.Size() != 6 {
+		t.Errorf("got resumed upload of %q with size %d, want %q with size 6", u.Key(), u.Size(), "big")
+	}
+	if err := u.Append(ctx, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := bucket.Exists(ctx, "big"); err != nil || exists {
+		t.Errorf("got exists %v, %v before Complete, want false", exists, err)
+	}
+	if err := u.Complete(ctx, []byte("!")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := bucket.ReadAll(ctx, "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte("hello world!"); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	attrs, err := bucket.Attributes(ctx, "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.ContentType != "text/plain" || !cmp.Equal(attrs.Metadata, md) {
+		t.Errorf("got content type %q and metadata %v, want %q and %v", attrs.ContentType, attrs.Metadata, "text/plain", md)
+	}
+
+	// A completed upload cannot be used anymore.
+	if err := u.Append(ctx, []byte("x")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got error %v appending to a completed upload, want FailedPrecondition", err)
+	}
+	if _, err := u.Token(); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got error %v getting the token of a completed upload, want FailedPrecondition", err)
+	}
+}
+
+func TestUploadEmpty(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	u, err := bucket.BeginUpload(ctx, "empty", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Append(ctx, nil); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v appending an empty part, want InvalidArgument", err)
+	}
+	if err := u.Complete(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := bucket.Attributes(ctx, "empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.Size != 0 || attrs.ContentType != "application/octet-stream" {
+		t.Errorf("got size %d and content type %q, want 0 and %q", attrs.Size, attrs.ContentType, "application/octet-stream")
+	}
+}
+
+func TestUploadAbort(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	u, err := bucket.BeginUpload(ctx, "aborted", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Append(ctx, []byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	token, err := u.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Abort(ctx); err != nil {
+		t.Fatal(err)
+	}
+	u, err = bucket.ResumeUpload(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Complete(ctx, nil); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got error %v completing an aborted upload, want NotFound", err)
+	}
+	if exists, err := bucket.Exists(ctx, "aborted"); err != nil || exists {
+		t.Errorf("got exists %v, %v after Abort, want false", exists, err)
+	}
+}
+
+func TestUploadErrors(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	if _, err := bucket.BeginUpload(ctx, "b", &blob.WriterOptions{ContentMD5: []byte("x")}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v with ContentMD5, want InvalidArgument", err)
+	}
+	if _, err := bucket.ResumeUpload([]byte("not a token")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v resuming an invalid token, want InvalidArgument", err)
+	}
+
+	// fileblob does not support resumable uploads.
+	fb := openFileTestBucket(t)
+	if _, err := fb.BeginUpload(ctx, "b", nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("got error %v from fileblob, want Unimplemented", err)
+	}
+}
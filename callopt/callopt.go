@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package callopt attaches per-call options to a context, for the calls of
+// the portable types made with it: a timeout, a retry policy, and provider
+// hints such as the read consistency or the storage class.
+//
+// Options are added with the With functions, which keep the options already
+// in the context unless they set them again, so middleware and call sites
+// can each add the options they care about:
+//
+//	ctx = callopt.WithTimeout(ctx, 2*time.Second)
+//	ctx = callopt.WithHint(ctx, callopt.StorageClass, "STANDARD_IA")
+//	err := bucket.WriteAll(ctx, key, data, nil)
+//
+// # Timeouts
+//
+// The portable types blob, docstore, secrets and secretstore apply the
+// timeout to each call that reaches the driver. For blob's NewReader,
+// NewRangeReader and NewWriter, the timeout covers the call and the use of
+// the returned Reader or Writer, until it is closed. A timeout that passes
+// fails the call with gcerrors.DeadlineExceeded.
+//
+// # Retries and hints
+//
+// Drivers that support the retry policy or a hint document it. Drivers
+// ignore hints they do not support, so the same code runs with every
+// provider. Hints whose values are provider-specific, such as storage
+// classes, should be set where the provider is known.
+package callopt // import "gocloud.dev/callopt"
+
+import (
+	"context"
+	"time"
+
+	"gocloud.dev/gcerrors"
+)
+
+// Standard hint keys.
+const (
+	// Consistency is the read consistency for document stores: Strong or
+	// Eventual.
+	Consistency = "consistency"
+	// StorageClass is the storage class of blobs written, in the provider's
+	// terms, such as "STANDARD_IA" for S3 or "NEARLINE" for GCS.
+	StorageClass = "storage_class"
+)
+
+// Values of the Consistency hint.
+const (
+	Strong   = "strong"
+	Eventual = "eventual"
+)
+
+// Options are the per-call options attached to a context.
+type Options struct {
+	// Timeout limits how long each call takes. Zero means no limit other
+	// than the context's deadline.
+	Timeout time.Duration
+	// RetryPolicy controls how drivers that support it retry failed
+	// requests. Nil means the driver's default.
+	RetryPolicy *gcerrors.RetryPolicy
+	// Hints holds provider hints, by key, such as Consistency and
+	// StorageClass. It must not be modified.
+	Hints map[string]string
+}
+
+type optionsKey struct{}
+
+// FromContext returns the options attached to ctx. It returns the zero
+// Options if there are none.
+func FromContext(ctx context.Context) Options {
+	o, _ := ctx.Value(optionsKey{}).(Options)
+	return o
+}
+
+// With returns a copy of ctx carrying opts. The non-zero fields of opts
+// replace those already in ctx, and its hints are merged with those
+// already in ctx.
+func With(ctx context.Context, opts Options) context.Context {
+	o := FromContext(ctx)
+	if opts.Timeout != 0 {
+		o.Timeout = opts.Timeout
+	}
+	if opts.RetryPolicy != nil {
+		o.RetryPolicy = opts.RetryPolicy
+	}
+	if len(opts.Hints) > 0 {
+		hints := make(map[string]string, len(o.Hints)+len(opts.Hints))
+		for k, v := range o.Hints {
+			hints[k] = v
+		}
+		for k, v := range opts.Hints {
+			hints[k] = v
+		}
+		o.Hints = hints
+	}
+	return context.WithValue(ctx, optionsKey{}, o)
+}
+
+// WithTimeout returns a copy of ctx whose calls time out after d.
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return With(ctx, Options{Timeout: d})
+}
+
+// WithRetryPolicy returns a copy of ctx whose calls are retried with p by
+// drivers that support it.
+func WithRetryPolicy(ctx context.Context, p *gcerrors.RetryPolicy) context.Context {
+	return With(ctx, Options{RetryPolicy: p})
+}
+
+// WithHint returns a copy of ctx carrying the provider hint key with the
+// given value.
+func WithHint(ctx context.Context, key, value string) context.Context {
+	return With(ctx, Options{Hints: map[string]string{key: value}})
+}
+
+// Hint returns the value of the provider hint key in ctx, or "" if there
+// is none.
+func Hint(ctx context.Context, key string) string {
+	return FromContext(ctx).Hints[key]
+}
+
+// Context is for use by portable types and drivers. If ctx has a timeout,
+// it returns a copy of ctx that is done when the timeout passes, and a
+// function that cancels it, which must be called when the call is done.
+// Otherwise, it returns ctx and a function that does nothing.
+func Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := FromContext(ctx).Timeout; d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package callopt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gocloud.dev/gcerrors"
+)
+
+func TestWith(t *testing.T) {
+	ctx := context.Background()
+	if got := FromContext(ctx); got.Timeout != 0 || got.RetryPolicy != nil || got.Hints != nil {
+		t.Errorf("empty context: got %+v, want zero Options", got)
+	}
+
+	policy := &gcerrors.RetryPolicy{MaxAttempts: 3}
+	ctx = WithTimeout(ctx, time.Second)
+	ctx = WithRetryPolicy(ctx, policy)
+	ctx = WithHint(ctx, Consistency, Strong)
+	outer := WithHint(WithTimeout(ctx, time.Minute), StorageClass, "NEARLINE")
+
+	got := FromContext(outer)
+	if got.Timeout != time.Minute || got.RetryPolicy != policy {
+		t.Errorf("got %+v, want a one-minute timeout and the policy", got)
+	}
+	if Hint(outer, Consistency) != Strong || Hint(outer, StorageClass) != "NEARLINE" {
+		t.Errorf("got hints %v, want both hints", got.Hints)
+	}
+	// The inner context is unchanged.
+	if got := FromContext(ctx); got.Timeout != time.Second || Hint(ctx, StorageClass) != "" {
+		t.Errorf("inner context: got %+v, want it unchanged", got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	got, cancel := Context(ctx)
+	cancel()
+	if got != ctx {
+		t.Error("without a timeout: got a new context, want ctx")
+	}
+
+	got, cancel = Context(WithTimeout(ctx, time.Millisecond))
+	defer cancel()
+	select {
+	case <-got.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("context not done after its timeout")
+	}
+	if got.Err() != context.DeadlineExceeded {
+		t.Errorf("got %v, want context.DeadlineExceeded", got.Err())
+	}
+}
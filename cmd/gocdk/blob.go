@@ -0,0 +1,238 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+)
+
+const blobHelp = `A blob is named by the URL of its bucket, followed by # and its key,
+such as "s3://mybucket?region=us-east-2#dir/file.txt". Quote it in the
+shell.`
+
+func init() {
+	register(
+		&command{
+			group:    "blob",
+			name:     "ls",
+			args:     "[-prefix <prefix>] [-delimiter <delimiter>] [-l] <bucket URL>",
+			synopsis: "List the blobs in a bucket",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				prefix := f.String("prefix", "", "list only the blobs whose keys start with `prefix`")
+				delimiter := f.String("delimiter", "/", "directory `delimiter`; empty lists all blobs under the prefix")
+				long := f.Bool("l", false, "also show the size and modification time of each blob")
+				return func(ctx context.Context, e *env, args []string) error {
+					return blobList(ctx, e, args, &blob.ListOptions{Prefix: *prefix, Delimiter: *delimiter}, *long)
+				}
+			},
+		},
+		&command{
+			group:    "blob",
+			name:     "cp",
+			args:     "[-content-type <type>] <source> <destination>",
+			synopsis: "Copy a blob or local file to a blob or local file",
+			help: blobHelp + `
+
+A source or destination that is not a blob is a local file, or - for
+stdin or stdout. For example, to upload a file:
+
+  gocdk blob cp report.csv 'gs://mybucket#reports/today.csv'`,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				contentType := f.String("content-type", "", "the `MIME type` of a blob written; detected from the data if empty")
+				return func(ctx context.Context, e *env, args []string) error {
+					return blobCopy(ctx, e, args, *contentType)
+				}
+			},
+		},
+		&command{
+			group:    "blob",
+			name:     "rm",
+			args:     "<blob>...",
+			synopsis: "Delete blobs",
+			help:     blobHelp,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				return blobDelete
+			},
+		},
+		&command{
+			group:    "blob",
+			name:     "sign",
+			args:     "[-method <method>] [-expiry <duration>] [-content-type <type>] <blob>",
+			synopsis: "Print a signed URL for a blob",
+			help:     blobHelp,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				opts := &blob.SignedURLOptions{}
+				f.StringVar(&opts.Method, "method", "GET", "the HTTP `method` the URL allows: GET, PUT or DELETE")
+				f.DurationVar(&opts.Expiry, "expiry", time.Hour, "how long the URL is valid")
+				f.StringVar(&opts.ContentType, "content-type", "", "the `MIME type` that a PUT must use")
+				return func(ctx context.Context, e *env, args []string) error {
+					return blobSign(ctx, e, args, opts)
+				}
+			},
+		},
+	)
+}
+
+// splitBlob splits a blob name into a bucket URL and a key. ok is false if
+// s does not name a blob.
+func splitBlob(s string) (bucketURL, key string, ok bool) {
+	if !strings.Contains(s, "://") {
+		return "", "", false
+	}
+	return strings.Cut(s, "#")
+}
+
+// openBlob opens the bucket of the blob s, and returns it with the key.
+func openBlob(ctx context.Context, s string) (*blob.Bucket, string, error) {
+	bucketURL, key, ok := splitBlob(s)
+	if !ok || key == "" {
+		return nil, "", fmt.Errorf("%q is not a blob: want <bucket URL>#<key>", s)
+	}
+	b, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, key, nil
+}
+
+func blobList(ctx context.Context, e *env, args []string, opts *blob.ListOptions, long bool) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	b, err := blob.OpenBucket(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	iter := b.List(opts)
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if long && !obj.IsDir {
+			fmt.Fprintf(e.stdout, "%12d  %s  %s\n", obj.Size, obj.ModTime.UTC().Format(time.RFC3339), obj.Key)
+		} else {
+			fmt.Fprintln(e.stdout, obj.Key)
+		}
+	}
+}
+
+func blobCopy(ctx context.Context, e *env, args []string, contentType string) (err error) {
+	if len(args) != 2 {
+		return errUsage
+	}
+	var r io.Reader
+	if _, _, ok := splitBlob(args[0]); ok {
+		b, key, err := openBlob(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+		br, err := b.NewReader(ctx, key, nil)
+		if err != nil {
+			return err
+		}
+		defer br.Close()
+		r = br
+	} else if args[0] == "-" {
+		r = e.stdin
+	} else {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if _, _, ok := splitBlob(args[1]); ok {
+		b, key, err := openBlob(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+		w, err := b.NewWriter(ctx, key, &blob.WriterOptions{ContentType: contentType})
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	}
+	if args[1] == "-" {
+		_, err := io.Copy(e.stdout, r)
+		return err
+	}
+	f, err := os.Create(args[1])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func blobDelete(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	for _, arg := range args {
+		b, key, err := openBlob(ctx, arg)
+		if err != nil {
+			return err
+		}
+		err = b.Delete(ctx, key)
+		b.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func blobSign(ctx context.Context, e *env, args []string, opts *blob.SignedURLOptions) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	b, key, err := openBlob(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	u, err := b.SignedURL(ctx, key, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, u)
+	return nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"gocloud.dev/docstore"
+)
+
+const docstoreHelp = `Documents are JSON objects. Integers are stored as integers, and other
+numbers as floating point. Omit the revision field of documents put, unless
+the put should fail if the document has changed.`
+
+func init() {
+	register(
+		&command{
+			group:    "docstore",
+			name:     "get",
+			args:     "[-fields <field paths>] <collection URL> <key document>",
+			synopsis: "Print a document",
+			help: `The key document is a JSON object with the key fields of the document,
+such as '{"ID": "abc"}'. The document is printed as a JSON object.`,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				fields := f.String("fields", "", "comma-separated `field paths` to get; all if empty")
+				return func(ctx context.Context, e *env, args []string) error {
+					return docstoreGet(ctx, e, args, fieldPaths(*fields))
+				}
+			},
+		},
+		&command{
+			group:    "docstore",
+			name:     "put",
+			args:     "<collection URL> [<document>]",
+			synopsis: "Write documents, replacing any with the same key",
+			help:     docstoreHelp + "\n\nWithout a document argument, the documents are read from stdin.",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				return docstorePut
+			},
+		},
+		&command{
+			group:    "docstore",
+			name:     "query",
+			args:     "[-where <filter>]... [-order-by <field>] [-desc] [-limit <n>] [-fields <field paths>] <collection URL>",
+			synopsis: "Print the documents that match a query",
+			help: `Each filter is a field path, an operator (=, >, >=, <, <=, in or not-in)
+and a JSON value, or a string, separated by spaces, such as "Age >= 21" or
+'Status in ["new", "open"]'. The documents are printed as JSON objects,
+one per line.`,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				var where stringsFlag
+				f.Var(&where, "where", "a `filter`; may be repeated")
+				orderBy := f.String("order-by", "", "the `field` to sort by")
+				desc := f.Bool("desc", false, "sort in descending order")
+				limit := f.Int("limit", 0, "the maximum `number` of documents; no limit if 0")
+				fields := f.String("fields", "", "comma-separated `field paths` to get; all if empty")
+				return func(ctx context.Context, e *env, args []string) error {
+					if len(args) != 1 {
+						return errUsage
+					}
+					coll, err := docstore.OpenCollection(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					defer coll.Close()
+					q := coll.Query()
+					for _, w := range where {
+						fp, op, value, err := parseFilter(w)
+						if err != nil {
+							return err
+						}
+						q = q.Where(fp, op, value)
+					}
+					if *orderBy != "" {
+						dir := docstore.Ascending
+						if *desc {
+							dir = docstore.Descending
+						}
+						q = q.OrderBy(*orderBy, dir)
+					}
+					if *limit > 0 {
+						q = q.Limit(*limit)
+					}
+					return docstoreQuery(ctx, e, q, fieldPaths(*fields))
+				}
+			},
+		},
+	)
+}
+
+func fieldPaths(s string) []docstore.FieldPath {
+	var fps []docstore.FieldPath
+	for _, fp := range strings.Split(s, ",") {
+		if fp = strings.TrimSpace(fp); fp != "" {
+			fps = append(fps, docstore.FieldPath(fp))
+		}
+	}
+	return fps
+}
+
+// parseFilter parses a query filter such as "Age >= 21".
+func parseFilter(s string) (docstore.FieldPath, string, interface{}, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), " ", 3)
+	if len(parts) != 3 {
+		return "", "", nil, fmt.Errorf("bad filter %q: want <field path> <operator> <value>", s)
+	}
+	value, err := decodeValue(strings.TrimSpace(parts[2]))
+	if err != nil {
+		return "", "", nil, err
+	}
+	return docstore.FieldPath(parts[0]), parts[1], value, nil
+}
+
+// decodeValue decodes a JSON value, or returns s if it is not JSON.
+func decodeValue(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return s, nil
+	}
+	return convertNumbers(v)
+}
+
+// decodeDocs decodes the JSON objects in data.
+func decodeDocs(data []byte) ([]map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var docs []map[string]interface{}
+	for {
+		var doc map[string]interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bad document: %v", err)
+		}
+		if _, err := convertNumbers(doc); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// convertNumbers replaces the json.Numbers in v with int64s if they are
+// integers, and float64s otherwise.
+func convertNumbers(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case map[string]interface{}:
+		for k, x := range v {
+			x, err := convertNumbers(x)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = x
+		}
+	case []interface{}:
+		for i, x := range v {
+			x, err := convertNumbers(x)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = x
+		}
+	}
+	return v, nil
+}
+
+func docstoreGet(ctx context.Context, e *env, args []string, fps []docstore.FieldPath) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	docs, err := decodeDocs([]byte(args[1]))
+	if err != nil {
+		return err
+	}
+	if len(docs) != 1 {
+		return fmt.Errorf("want one key document, got %d", len(docs))
+	}
+	coll, err := docstore.OpenCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer coll.Close()
+	if err := coll.Get(ctx, docs[0], fps...); err != nil {
+		return err
+	}
+	return json.NewEncoder(e.stdout).Encode(docs[0])
+}
+
+func docstorePut(ctx context.Context, e *env, args []string) (err error) {
+	if len(args) == 0 || len(args) > 2 {
+		return errUsage
+	}
+	data, err := readInput(e, args[1:])
+	if err != nil {
+		return err
+	}
+	docs, err := decodeDocs(data)
+	if err != nil {
+		return err
+	}
+	coll, err := docstore.OpenCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	// Some collections, such as those of memdocstore with a file, save
+	// their documents when closed.
+	defer func() {
+		if cerr := coll.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	l := coll.Actions()
+	for _, doc := range docs {
+		l.Put(doc)
+	}
+	return l.Do(ctx)
+}
+
+func docstoreQuery(ctx context.Context, e *env, q *docstore.Query, fps []docstore.FieldPath) error {
+	iter := q.Get(ctx, fps...)
+	defer iter.Stop()
+	enc := json.NewEncoder(e.stdout)
+	for {
+		doc := map[string]interface{}{}
+		err := iter.Next(ctx, doc)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+}
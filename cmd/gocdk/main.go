@@ -0,0 +1,222 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// gocdk opens the resources of the Go CDK portable types by URL and
+// performs common operations on them, for use from the command line and
+// in scripts:
+//
+//	gocdk blob ls|cp|rm|sign
+//	gocdk docstore get|put|query
+//	gocdk pubsub pub|tail
+//	gocdk runtimevar cat|watch
+//	gocdk secrets encrypt|decrypt
+//
+// Run "gocdk help" for the list of commands, and "gocdk help <group>
+// <command>" for the usage of a command.
+//
+// Resources are named by the same URLs as the portable types' Open
+// functions; see https://gocloud.dev/concepts/urls/. gocdk supports the
+// drivers of the gocloud.dev module. Credentials are found as the drivers
+// find them, such as from the environment.
+//
+// gocdk exits with status 0 on success, 1 if a command fails, and 2 if it
+// is used incorrectly.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	// Import the driver packages that gocdk can open.
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/blob/s3blob"
+	_ "gocloud.dev/docstore/awsdynamodb"
+	_ "gocloud.dev/docstore/gcpfirestore"
+	_ "gocloud.dev/docstore/memdocstore"
+	_ "gocloud.dev/pubsub/awssnssqs"
+	_ "gocloud.dev/pubsub/azuresb"
+	_ "gocloud.dev/pubsub/gcppubsub"
+	_ "gocloud.dev/pubsub/mempubsub"
+	_ "gocloud.dev/runtimevar/awsparamstore"
+	_ "gocloud.dev/runtimevar/awssecretsmanager"
+	_ "gocloud.dev/runtimevar/blobvar"
+	_ "gocloud.dev/runtimevar/constantvar"
+	_ "gocloud.dev/runtimevar/filevar"
+	_ "gocloud.dev/runtimevar/gcpruntimeconfig"
+	_ "gocloud.dev/runtimevar/gcpsecretmanager"
+	_ "gocloud.dev/runtimevar/httpvar"
+	_ "gocloud.dev/secrets/agesecrets"
+	_ "gocloud.dev/secrets/awskms"
+	_ "gocloud.dev/secrets/azurekeyvault"
+	_ "gocloud.dev/secrets/gcpkms"
+	_ "gocloud.dev/secrets/localsecrets"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+// env holds the standard streams of a command.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+// A command is a gocdk command, such as "blob ls".
+type command struct {
+	group, name string
+	args        string // the arguments, for the usage message
+	synopsis    string
+	help        string // more about the command, if anything
+	// flags defines the flags of the command on f, and returns a function
+	// that runs the command with the remaining arguments.
+	flags func(f *flag.FlagSet) func(ctx context.Context, e *env, args []string) error
+}
+
+// commands holds all the commands, by group and name.
+var commands = map[string]map[string]*command{}
+
+func register(cmds ...*command) {
+	for _, c := range cmds {
+		if commands[c.group] == nil {
+			commands[c.group] = map[string]*command{}
+		}
+		commands[c.group][c.name] = c
+	}
+}
+
+// errUsage is returned by commands called with the wrong arguments.
+var errUsage = errors.New("usage error")
+
+// run runs the command given by args and returns the exit status.
+func run(ctx context.Context, args []string, e *env) int {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+		return help(e, args[1:])
+	}
+	if len(args) < 2 {
+		printUsage(e.stderr)
+		return 2
+	}
+	c := commands[args[0]][args[1]]
+	if c == nil {
+		fmt.Fprintf(e.stderr, "gocdk: unknown command %q\n", strings.Join(args[:2], " "))
+		printUsage(e.stderr)
+		return 2
+	}
+	f := flag.NewFlagSet("gocdk "+c.group+" "+c.name, flag.ContinueOnError)
+	f.SetOutput(e.stderr)
+	f.Usage = func() { c.printUsage(e.stderr, f) }
+	runFunc := c.flags(f)
+	if err := f.Parse(args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if err := runFunc(ctx, e, f.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			f.Usage()
+			return 2
+		}
+		fmt.Fprintf(e.stderr, "gocdk %s %s: %v\n", c.group, c.name, err)
+		return 1
+	}
+	return 0
+}
+
+func help(e *env, args []string) int {
+	if len(args) < 2 {
+		printUsage(e.stdout)
+		return 0
+	}
+	c := commands[args[0]][args[1]]
+	if c == nil {
+		fmt.Fprintf(e.stderr, "gocdk: unknown command %q\n", strings.Join(args[:2], " "))
+		return 2
+	}
+	f := flag.NewFlagSet("gocdk "+c.group+" "+c.name, flag.ContinueOnError)
+	c.flags(f)
+	c.printUsage(e.stdout, f)
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gocdk <group> <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	var groups []string
+	for g := range commands {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	for _, g := range groups {
+		var names []string
+		for n := range commands[g] {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(w, "  %-20s %s\n", g+" "+n, commands[g][n].synopsis)
+		}
+	}
+	fmt.Fprintln(w, "\nRun \"gocdk help <group> <command>\" for the usage of a command.")
+	fmt.Fprintln(w, "See https://gocloud.dev/concepts/urls/ for the URL formats.")
+}
+
+func (c *command) printUsage(w io.Writer, f *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: gocdk %s %s %s\n\n%s.\n", c.group, c.name, c.args, c.synopsis)
+	if c.help != "" {
+		fmt.Fprintf(w, "\n%s\n", c.help)
+	}
+	var hasFlags bool
+	f.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(w, "\nFlags:")
+		f.SetOutput(w)
+		f.PrintDefaults()
+	}
+}
+
+// stringsFlag is a flag that may be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// readInput returns the single argument in args, or else all of stdin.
+func readInput(e *env, args []string) ([]byte, error) {
+	switch len(args) {
+	case 0:
+		return io.ReadAll(e.stdin)
+	case 1:
+		return []byte(args[0]), nil
+	default:
+		return nil, errUsage
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gocloud.dev/pubsub"
+)
+
+// gocdk runs gocdk with args and stdin, and returns its output and exit
+// status.
+func gocdk(t *testing.T, stdin string, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr})
+	if code != 0 {
+		t.Logf("gocdk %s: exit %d: %s", strings.Join(args, " "), code, stderr.String())
+	}
+	return stdout.String(), code
+}
+
+// mustRun runs gocdk with args and stdin, fails the test if it fails, and
+// returns its output.
+func mustRun(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	out, code := gocdk(t, stdin, args...)
+	if code != 0 {
+		t.Fatalf("gocdk %s: exit %d", strings.Join(args, " "), code)
+	}
+	return out
+}
+
+func TestUsage(t *testing.T) {
+	if out, code := gocdk(t, "", "help"); code != 0 || !strings.Contains(out, "blob ls") {
+		t.Errorf("help: got %d, %q; want the commands", code, out)
+	}
+	if _, code := gocdk(t, "", "blob", "nosuchcommand"); code != 2 {
+		t.Errorf("unknown command: got exit %d, want 2", code)
+	}
+	if _, code := gocdk(t, "", "blob", "ls"); code != 2 {
+		t.Errorf("missing argument: got exit %d, want 2", code)
+	}
+	if _, code := gocdk(t, "", "blob", "ls", "nosuchscheme://"); code != 1 {
+		t.Errorf("bad URL: got exit %d, want 1", code)
+	}
+}
+
+func TestBlob(t *testing.T) {
+	dir := t.TempDir()
+	bucket := "file://" + filepath.ToSlash(dir) + "/bucket?create_dir=true"
+	local := filepath.Join(dir, "local.txt")
+	if err := os.WriteFile(local, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mustRun(t, "", "blob", "cp", local, bucket+"#a/b.txt")
+	mustRun(t, "world", "blob", "cp", "-", bucket+"#c.txt")
+	if got := mustRun(t, "", "blob", "ls", "-delimiter", "", bucket); got != "a/b.txt\nc.txt\n" {
+		t.Errorf("ls: got %q", got)
+	}
+	if got := mustRun(t, "", "blob", "ls", bucket); got != "a/\nc.txt\n" {
+		t.Errorf("ls with delimiter: got %q", got)
+	}
+	if got := mustRun(t, "", "blob", "cp", bucket+"#a/b.txt", "-"); got != "hello" {
+		t.Errorf("cp to stdout: got %q, want hello", got)
+	}
+	mustRun(t, "", "blob", "rm", bucket+"#a/b.txt", bucket+"#c.txt")
+	if got := mustRun(t, "", "blob", "ls", bucket); got != "" {
+		t.Errorf("ls after rm: got %q, want nothing", got)
+	}
+	if _, code := gocdk(t, "", "blob", "rm", bucket); code != 1 {
+		t.Errorf("rm without a key: got exit %d, want 1", code)
+	}
+}
+
+func TestDocstore(t *testing.T) {
+	coll := "mem://people/ID?filename=" + filepath.Join(t.TempDir(), "people")
+
+	mustRun(t, "", "docstore", "put", coll, `{"ID": "a", "Age": 30}`)
+	mustRun(t, `{"ID": "b", "Age": 20} {"ID": "c", "Age": 40}`, "docstore", "put", coll)
+	if got := mustRun(t, "", "docstore", "get", "-fields", "Age", coll, `{"ID": "c"}`); got != `{"Age":40,"ID":"c"}`+"\n" {
+		t.Errorf("get: got %q", got)
+	}
+	got := mustRun(t, "", "docstore", "query", "-where", "Age >= 30", "-order-by", "Age", "-desc", "-fields", "ID", coll)
+	if got != `{"ID":"c"}`+"\n"+`{"ID":"a"}`+"\n" {
+		t.Errorf("query: got %q", got)
+	}
+	if _, code := gocdk(t, "", "docstore", "get", coll, `{"ID": "missing"}`); code != 1 {
+		t.Errorf("get of missing document: got exit %d, want 1", code)
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	ctx := context.Background()
+
+	// The mem driver shares topics by name within a process, so the test
+	// can receive what gocdk sends, and send what it receives.
+	topic, err := pubsub.OpenTopic(ctx, "mem://gocdk-pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := pubsub.OpenSubscription(ctx, "mem://gocdk-pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Shutdown(ctx)
+	mustRun(t, "", "pubsub", "pub", "-m", "k=v", "mem://gocdk-pub", "hello")
+	m, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Ack()
+	if string(m.Body) != "hello" || m.Metadata["k"] != "v" {
+		t.Errorf("pub: got %q %v", m.Body, m.Metadata)
+	}
+	topic.Shutdown(ctx)
+
+	topic, err = pubsub.OpenTopic(ctx, "mem://gocdk-tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Shutdown(ctx)
+	done := make(chan string)
+	go func() {
+		done <- mustRun(t, "", "pubsub", "tail", "-n", "1", "-json", "mem://gocdk-tail")
+	}()
+	// Messages sent before tail subscribes are not delivered, so send
+	// until one is.
+	for {
+		if err := topic.Send(ctx, &pubsub.Message{Body: []byte("hi")}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-done:
+			if got != `{"body":"hi"}`+"\n" {
+				t.Errorf("tail: got %q", got)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRuntimevar(t *testing.T) {
+	if got := mustRun(t, "", "runtimevar", "cat", "constant://?decoder=string&val=hello"); got != "hello\n" {
+		t.Errorf("cat: got %q", got)
+	}
+	if got := mustRun(t, "", "runtimevar", "watch", "-n", "1", `constant://?decoder=jsonmap&val={"a":1}`); got != `{"a":1}`+"\n" {
+		t.Errorf("watch: got %q", got)
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	const keeper = "base64key://smGbjm71Nxd1Ig5FS0wj9SlbzAIrnolCz9bQQ6uAhl4="
+	ciphertext := mustRun(t, "", "secrets", "encrypt", keeper, "hello")
+	if got := mustRun(t, ciphertext, "secrets", "decrypt", keeper); got != "hello" {
+		t.Errorf("decrypt: got %q, want hello", got)
+	}
+}
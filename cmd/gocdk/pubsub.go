@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"gocloud.dev/pubsub"
+)
+
+func init() {
+	register(
+		&command{
+			group:    "pubsub",
+			name:     "pub",
+			args:     "[-m <key>=<value>]... [-lines] <topic URL> [<message>]",
+			synopsis: "Send messages to a topic",
+			help:     "Without a message argument, the message is read from stdin.",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				var meta stringsFlag
+				f.Var(&meta, "m", "a metadata `key=value` pair; may be repeated")
+				lines := f.Bool("lines", false, "send each line of stdin as a message")
+				return func(ctx context.Context, e *env, args []string) error {
+					md := map[string]string{}
+					for _, kv := range meta {
+						k, v, ok := strings.Cut(kv, "=")
+						if !ok {
+							return fmt.Errorf("bad metadata %q: want key=value", kv)
+						}
+						md[k] = v
+					}
+					return pubsubPublish(ctx, e, args, md, *lines)
+				}
+			},
+		},
+		&command{
+			group:    "pubsub",
+			name:     "tail",
+			args:     "[-n <count>] [-json] [-nack] <subscription URL>",
+			synopsis: "Receive messages from a subscription and print them",
+			help: `Each message is printed on a line, and acked. tail runs until it has
+received -n messages, or until it is interrupted.`,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				n := f.Int("n", 0, "the `number` of messages to receive; no limit if 0")
+				asJSON := f.Bool("json", false, "print each message as a JSON object with its body and metadata")
+				nack := f.Bool("nack", false, "nack the messages instead of acking them, if the service supports it, so they are redelivered")
+				return func(ctx context.Context, e *env, args []string) error {
+					return pubsubTail(ctx, e, args, *n, *asJSON, *nack)
+				}
+			},
+		},
+	)
+}
+
+func pubsubPublish(ctx context.Context, e *env, args []string, md map[string]string, lines bool) error {
+	if len(args) == 0 || len(args) > 2 || (lines && len(args) != 1) {
+		return errUsage
+	}
+	var bodies [][]byte
+	if lines {
+		s := bufio.NewScanner(e.stdin)
+		for s.Scan() {
+			bodies = append(bodies, append([]byte(nil), s.Bytes()...))
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	} else {
+		body, err := readInput(e, args[1:])
+		if err != nil {
+			return err
+		}
+		bodies = append(bodies, body)
+	}
+
+	topic, err := pubsub.OpenTopic(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer topic.Shutdown(ctx)
+	for _, body := range bodies {
+		if err := topic.Send(ctx, &pubsub.Message{Body: body, Metadata: md}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pubsubTail(ctx context.Context, e *env, args []string, n int, asJSON, nack bool) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	sub, err := pubsub.OpenSubscription(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer sub.Shutdown(context.Background())
+	enc := json.NewEncoder(e.stdout)
+	for i := 0; n <= 0 || i < n; i++ {
+		m, err := sub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Interrupted.
+				return nil
+			}
+			return err
+		}
+		if nack && m.Nackable() {
+			m.Nack()
+		} else {
+			m.Ack()
+		}
+		if asJSON {
+			err = enc.Encode(struct {
+				Body     string            `json:"body"`
+				Metadata map[string]string `json:"metadata,omitempty"`
+			}{string(m.Body), m.Metadata})
+		} else {
+			_, err = fmt.Fprintf(e.stdout, "%s\n", m.Body)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
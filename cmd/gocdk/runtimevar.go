@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"gocloud.dev/runtimevar"
+)
+
+const runtimevarHelp = `The value is decoded as the URL's decoder parameter says, and printed as it
+is if it is a string or bytes, or as JSON otherwise.`
+
+func init() {
+	register(
+		&command{
+			group:    "runtimevar",
+			name:     "cat",
+			args:     "<variable URL>",
+			synopsis: "Print the value of a variable",
+			help:     runtimevarHelp,
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				return func(ctx context.Context, e *env, args []string) error {
+					return runtimevarWatch(ctx, e, args, 1)
+				}
+			},
+		},
+		&command{
+			group:    "runtimevar",
+			name:     "watch",
+			args:     "[-n <count>] <variable URL>",
+			synopsis: "Print the value of a variable each time it changes",
+			help:     runtimevarHelp + "\n\nwatch runs until it has printed -n values, or until it is interrupted.",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				n := f.Int("n", 0, "the `number` of values to print; no limit if 0")
+				return func(ctx context.Context, e *env, args []string) error {
+					return runtimevarWatch(ctx, e, args, *n)
+				}
+			},
+		},
+	)
+}
+
+// runtimevarWatch prints n values of the variable, or all of them if n is
+// 0.
+func runtimevarWatch(ctx context.Context, e *env, args []string, n int) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	v, err := runtimevar.OpenVariable(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer v.Close()
+	for i := 0; n <= 0 || i < n; i++ {
+		snap, err := v.Watch(ctx)
+		if err != nil {
+			if ctx.Err() != nil && n != 1 {
+				// Interrupted.
+				return nil
+			}
+			return err
+		}
+		if err := printValue(e.stdout, snap.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printValue(w io.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		_, err := fmt.Fprintln(w, v)
+		return err
+	case []byte:
+		_, err := fmt.Fprintf(w, "%s\n", v)
+		return err
+	default:
+		return json.NewEncoder(w).Encode(v)
+	}
+}
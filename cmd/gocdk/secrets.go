@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+
+	"gocloud.dev/secrets"
+)
+
+func init() {
+	register(
+		&command{
+			group:    "secrets",
+			name:     "encrypt",
+			args:     "<keeper URL> [<plaintext>]",
+			synopsis: "Encrypt data and print the ciphertext in base64",
+			help:     "Without a plaintext argument, the plaintext is read from stdin.",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				return secretsEncrypt
+			},
+		},
+		&command{
+			group:    "secrets",
+			name:     "decrypt",
+			args:     "<keeper URL> [<ciphertext>]",
+			synopsis: "Decrypt base64 ciphertext and print the plaintext",
+			help:     "Without a ciphertext argument, the ciphertext is read from stdin.",
+			flags: func(f *flag.FlagSet) func(context.Context, *env, []string) error {
+				return secretsDecrypt
+			},
+		},
+	)
+}
+
+func secretsEncrypt(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errUsage
+	}
+	plaintext, err := readInput(e, args[1:])
+	if err != nil {
+		return err
+	}
+	k, err := secrets.OpenKeeper(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	ciphertext, err := k.Encrypt(ctx, plaintext)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(e.stdout, base64.StdEncoding.EncodeToString(ciphertext))
+	return err
+}
+
+func secretsDecrypt(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errUsage
+	}
+	data, err := readInput(e, args[1:])
+	if err != nil {
+		return err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return fmt.Errorf("ciphertext is not base64: %v", err)
+	}
+	k, err := secrets.OpenKeeper(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	plaintext, err := k.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return err
+	}
+	_, err = e.stdout.Write(plaintext)
+	return err
+}
@@ -0,0 +1,317 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config opens the buckets, collections, topics, subscriptions,
+// keepers and variables of a program from one declarative Config, read
+// from a JSON file or built in code, instead of assembling URL strings by
+// hand.
+//
+// A Config names each resource and gives its URL, as for the portable
+// types' Open functions:
+//
+//	{
+//	  "secret_store": "awssecretsmanager://?region=us-east-2",
+//	  "buckets": {
+//	    "uploads": "s3://${UPLOADS_BUCKET}?region=us-east-2"
+//	  },
+//	  "collections": {
+//	    "users": "dynamodb://users?partition_key=ID"
+//	  },
+//	  "keepers": {
+//	    "tokens": "awskms://${TOKENS_KEY_ID}?region=us-east-2"
+//	  },
+//	  "variables": {
+//	    "flags": "file:///etc/app/flags.json?decoder=json&wait=1m"
+//	  }
+//	}
+//
+// # Expansion
+//
+// Before a URL is opened, references in it are replaced:
+//   - ${NAME} is replaced by the environment variable NAME, which must be
+//     set; ${NAME:-default} is replaced by default if NAME is unset or
+//     empty.
+//   - ${secret:NAME} is replaced by the value of the secret NAME in the
+//     secret store, which is opened from the "secret_store" URL, or is
+//     Options.SecretStore.
+//   - $$ is replaced by a single $.
+//
+// Values are inserted as they are; a value that may contain characters
+// that are special in URLs, such as a password, should be kept in a part
+// of the URL that the driver does not parse, or be stored URL-escaped.
+//
+// The URL of the secret store itself may refer to environment variables,
+// but not to secrets.
+package config // import "gocloud.dev/config"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/docstore"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/runtimevar"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/secretstore"
+)
+
+// Config declares the resources of a program, each by name and URL.
+type Config struct {
+	// SecretStore is the URL of the secretstore.Store that ${secret:NAME}
+	// references are read from.
+	SecretStore string `json:"secret_store,omitempty"`
+
+	Buckets       map[string]string `json:"buckets,omitempty"`
+	Collections   map[string]string `json:"collections,omitempty"`
+	Topics        map[string]string `json:"topics,omitempty"`
+	Subscriptions map[string]string `json:"subscriptions,omitempty"`
+	Keepers       map[string]string `json:"keepers,omitempty"`
+	Variables     map[string]string `json:"variables,omitempty"`
+}
+
+// Parse parses a JSON Config. Unknown fields are an error.
+func Parse(data []byte) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var c Config
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("config: parse: %v", err)
+	}
+	return &c, nil
+}
+
+// ReadFile reads a JSON Config from the file at path.
+func ReadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	return Parse(data)
+}
+
+// Options configures Config.Open.
+type Options struct {
+	// LookupEnv looks up environment variables. If nil, os.LookupEnv is
+	// used.
+	LookupEnv func(name string) (string, bool)
+
+	// SecretStore is the store that ${secret:NAME} references are read
+	// from. If nil, the store is opened from Config.SecretStore, and closed
+	// when Open returns.
+	SecretStore *secretstore.Store
+}
+
+// Resources are the resources opened from a Config, by name.
+type Resources struct {
+	Buckets       map[string]*blob.Bucket
+	Collections   map[string]*docstore.Collection
+	Topics        map[string]*pubsub.Topic
+	Subscriptions map[string]*pubsub.Subscription
+	Keepers       map[string]*secrets.Keeper
+	Variables     map[string]*runtimevar.Variable
+}
+
+// Open expands the URLs of c and opens the resources they refer to, with
+// the default URL muxes of the portable types. Topics are opened before
+// subscriptions, since some drivers, such as mempubsub, require it. If any
+// resource fails to open, Open closes those already opened and returns an
+// error naming the resource.
+func (c *Config) Open(ctx context.Context, opts *Options) (_ *Resources, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	x := &expander{lookupEnv: opts.LookupEnv, store: opts.SecretStore}
+	if x.lookupEnv == nil {
+		x.lookupEnv = os.LookupEnv
+	}
+	if x.store == nil && c.SecretStore != "" {
+		u, err := x.expand(ctx, c.SecretStore, false)
+		if err != nil {
+			return nil, fmt.Errorf("config: secret store: %v", err)
+		}
+		if x.store, err = secretstore.OpenStore(ctx, u); err != nil {
+			return nil, fmt.Errorf("config: secret store: %v", err)
+		}
+		defer x.store.Close()
+	}
+
+	r := &Resources{
+		Buckets:       map[string]*blob.Bucket{},
+		Collections:   map[string]*docstore.Collection{},
+		Topics:        map[string]*pubsub.Topic{},
+		Subscriptions: map[string]*pubsub.Subscription{},
+		Keepers:       map[string]*secrets.Keeper{},
+		Variables:     map[string]*runtimevar.Variable{},
+	}
+	defer func() {
+		if err != nil {
+			r.Close(ctx)
+		}
+	}()
+	if err := open(ctx, x, "bucket", c.Buckets, r.Buckets, blob.OpenBucket); err != nil {
+		return nil, err
+	}
+	if err := open(ctx, x, "collection", c.Collections, r.Collections, docstore.OpenCollection); err != nil {
+		return nil, err
+	}
+	if err := open(ctx, x, "topic", c.Topics, r.Topics, pubsub.OpenTopic); err != nil {
+		return nil, err
+	}
+	if err := open(ctx, x, "subscription", c.Subscriptions, r.Subscriptions, pubsub.OpenSubscription); err != nil {
+		return nil, err
+	}
+	if err := open(ctx, x, "keeper", c.Keepers, r.Keepers, secrets.OpenKeeper); err != nil {
+		return nil, err
+	}
+	if err := open(ctx, x, "variable", c.Variables, r.Variables, runtimevar.OpenVariable); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the resources of one kind in urls, in order of name, into
+// opened.
+func open[T any](ctx context.Context, x *expander, kind string, urls map[string]string, opened map[string]T, openFunc func(context.Context, string) (T, error)) error {
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		u, err := x.expand(ctx, urls[name], true)
+		if err != nil {
+			return fmt.Errorf("config: %s %q: %v", kind, name, err)
+		}
+		v, err := openFunc(ctx, u)
+		if err != nil {
+			return fmt.Errorf("config: %s %q: %v", kind, name, err)
+		}
+		opened[name] = v
+	}
+	return nil
+}
+
+// Close closes all the resources, shutting down topics and subscriptions
+// with ctx, and returns the first error.
+func (r *Resources) Close(ctx context.Context) error {
+	var errs []error
+	for _, s := range r.Subscriptions {
+		errs = append(errs, s.Shutdown(ctx))
+	}
+	for _, t := range r.Topics {
+		errs = append(errs, t.Shutdown(ctx))
+	}
+	for _, b := range r.Buckets {
+		errs = append(errs, b.Close())
+	}
+	for _, c := range r.Collections {
+		errs = append(errs, c.Close())
+	}
+	for _, k := range r.Keepers {
+		errs = append(errs, k.Close())
+	}
+	for _, v := range r.Variables {
+		errs = append(errs, v.Close())
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expand replaces the ${NAME}, ${NAME:-default} and $$ references in s, as
+// Config.Open does, looking up environment variables with lookupEnv, or
+// os.LookupEnv if it is nil. If store is nil, ${secret:NAME} references
+// are an error.
+func Expand(ctx context.Context, s string, lookupEnv func(string) (string, bool), store *secretstore.Store) (string, error) {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	return (&expander{lookupEnv: lookupEnv, store: store}).expand(ctx, s, store != nil)
+}
+
+const secretPrefix = "secret:"
+
+type expander struct {
+	lookupEnv func(string) (string, bool)
+	store     *secretstore.Store
+}
+
+// expand replaces the references in s. If secretsOK is false, secret
+// references are an error.
+func (x *expander) expand(ctx context.Context, s string, secretsOK bool) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		s = s[i+1:]
+		switch {
+		case strings.HasPrefix(s, "$"):
+			b.WriteByte('$')
+			s = s[1:]
+		case strings.HasPrefix(s, "{"):
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return "", errors.New("unterminated ${ reference")
+			}
+			v, err := x.resolve(ctx, s[1:end], secretsOK)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			s = s[end+1:]
+		default:
+			return "", errors.New("$ must be followed by {NAME} or $")
+		}
+	}
+}
+
+// resolve returns the value of the reference ref, the text between "${"
+// and "}".
+func (x *expander) resolve(ctx context.Context, ref string, secretsOK bool) (string, error) {
+	if name, ok := strings.CutPrefix(ref, secretPrefix); ok {
+		if !secretsOK || x.store == nil {
+			return "", fmt.Errorf("secret %q: no secret store", name)
+		}
+		v, err := x.store.Get(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("secret %q: %v", name, err)
+		}
+		return string(v), nil
+	}
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty reference ${%s}", ref)
+	}
+	if v, ok := x.lookupEnv(name); ok && (v != "" || !hasDefault) {
+		return v, nil
+	}
+	if hasDefault {
+		return def, nil
+	}
+	return "", fmt.Errorf("environment variable %s is not set", name)
+}
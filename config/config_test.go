@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/docstore/memdocstore"
+	_ "gocloud.dev/pubsub/mempubsub"
+	_ "gocloud.dev/runtimevar/constantvar"
+	_ "gocloud.dev/secrets/localsecrets"
+	"gocloud.dev/secrets/secretstore/memstore"
+)
+
+func testEnv(name string) (string, bool) {
+	v, ok := map[string]string{"KEY_FIELD": "ID", "EMPTY": ""}[name]
+	return v, ok
+}
+
+func TestExpand(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.OpenStore(nil)
+	defer store.Close()
+	if err := store.Set(ctx, "flag", []byte("on")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "mem://coll/${KEY_FIELD}", want: "mem://coll/ID"},
+		{in: "${EMPTY}x", want: "x"},
+		{in: "${EMPTY:-d}|${UNSET:-}|${KEY_FIELD:-d}", want: "d||ID"},
+		{in: "price$$5", want: "price$5"},
+		{in: "constant://?val=${secret:flag}", want: "constant://?val=on"},
+		{in: "${UNSET}", wantErr: true},
+		{in: "${secret:missing}", wantErr: true},
+		{in: "${KEY_FIELD", wantErr: true},
+		{in: "$KEY_FIELD", wantErr: true},
+		{in: "${}", wantErr: true},
+	} {
+		got, err := Expand(ctx, test.in, testEnv, store)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q, error %t", test.in, got, err, test.want, test.wantErr)
+		}
+	}
+	if _, err := Expand(ctx, "${secret:flag}", testEnv, nil); err == nil {
+		t.Error("secret without a store: got nil error")
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{"buckets": {"b": "mem://"}, "keepers": {"k": "base64key://"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Buckets["b"] != "mem://" || c.Keepers["k"] != "base64key://" {
+		t.Errorf("got %+v, want the bucket and keeper", c)
+	}
+	if _, err := Parse([]byte(`{"bucket": {"b": "mem://"}}`)); err == nil {
+		t.Error("unknown field: got nil error")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.OpenStore(nil)
+	defer store.Close()
+	if err := store.Set(ctx, "greeting", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{
+		Buckets:       map[string]string{"uploads": "mem://"},
+		Collections:   map[string]string{"users": "mem://users/${KEY_FIELD}"},
+		Topics:        map[string]string{"events": "mem://events"},
+		Subscriptions: map[string]string{"events": "mem://events"},
+		Keepers:       map[string]string{"tokens": "base64key://"},
+		Variables:     map[string]string{"greeting": "constant://?decoder=string&val=${secret:greeting}"},
+	}
+	r, err := c.Open(ctx, &Options{LookupEnv: testEnv, SecretStore: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := r.Close(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+	if len(r.Buckets) != 1 || len(r.Collections) != 1 || len(r.Topics) != 1 ||
+		len(r.Subscriptions) != 1 || len(r.Keepers) != 1 || len(r.Variables) != 1 {
+		t.Fatalf("got %+v, want one of each resource", r)
+	}
+	snap, err := r.Variables["greeting"].Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Value != "hello" {
+		t.Errorf("got variable %v, want the secret value", snap.Value)
+	}
+}
+
+func TestOpenError(t *testing.T) {
+	ctx := context.Background()
+	c := &Config{
+		Buckets:     map[string]string{"ok": "mem://"},
+		Collections: map[string]string{"users": "mem://users/${UNSET}"},
+	}
+	_, err := c.Open(ctx, &Options{LookupEnv: testEnv})
+	if err == nil || !strings.Contains(err.Error(), `collection "users"`) {
+		t.Errorf("got %v, want an error naming the collection", err)
+	}
+
+	c = &Config{SecretStore: "mem://", Keepers: map[string]string{"k": "nosuchscheme://"}}
+	if _, err := c.Open(ctx, nil); err == nil || !strings.Contains(err.Error(), `keeper "k"`) {
+		t.Errorf("got %v, want an error naming the keeper", err)
+	}
+}
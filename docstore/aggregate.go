@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docstore
+
+import (
+	"context"
+	"io"
+
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+)
+
+// Count returns the number of documents that the query matches, after its
+// Offset and Limit. Drivers that can count the documents in the service do
+// so; otherwise Count reads all the documents.
+func (q *Query) Count(ctx context.Context) (int64, error) {
+	v, err := q.aggregate(ctx, "Query.Count", driver.Aggregation{Op: "count"})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+// Sum returns the sum of the numeric values of the field fp in the documents
+// that the query matches, as a float64. Documents without the field, or with
+// a non-numeric value, are skipped.
+func (q *Query) Sum(ctx context.Context, fp FieldPath) (float64, error) {
+	v, err := q.aggregate(ctx, "Query.Sum", driver.Aggregation{Op: "sum"}, fp)
+	if err != nil {
+		return 0, err
+	}
+	return v.(float64), nil
+}
+
+// Min returns the least value of the field fp in the documents that the
+// query matches, or nil if none has a value. Numbers, strings and times are
+// compared; numbers are less than strings, and strings less than times.
+// Other values are skipped.
+//
+// The value is of the type that the driver decodes the field into in an
+// interface{}.
+func (q *Query) Min(ctx context.Context, fp FieldPath) (interface{}, error) {
+	return q.aggregate(ctx, "Query.Min", driver.Aggregation{Op: "min"}, fp)
+}
+
+// Max returns the greatest value of the field fp in the documents that the
+// query matches, or nil if none has a value. See Min for how values are
+// compared.
+func (q *Query) Max(ctx context.Context, fp FieldPath) (interface{}, error) {
+	return q.aggregate(ctx, "Query.Max", driver.Aggregation{Op: "max"}, fp)
+}
+
+// aggregate computes agg, on the field fps[0] if any, with the driver if it
+// is an Aggregator, and otherwise from the documents that the query returns.
+func (q *Query) aggregate(ctx context.Context, method string, agg driver.Aggregation, fps ...FieldPath) (_ interface{}, err error) {
+	dcoll := q.coll.driver
+	if err := q.initGet(fps); err != nil {
+		return nil, wrapError(dcoll, err)
+	}
+	if len(fps) > 0 {
+		agg.FieldPath = q.dq.FieldPaths[0]
+	}
+	acc, err := driver.NewAccumulator(agg)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "%s", method)
+	}
+
+	ctx = q.coll.tracer.Start(ctx, method)
+	defer func() { q.coll.tracer.End(ctx, err) }()
+
+	if a, ok := dcoll.(driver.Aggregator); ok {
+		v, err := a.RunAggregateQuery(ctx, q.dq, agg)
+		if err == nil {
+			return v, nil
+		}
+		if gcerrors.Code(err) != gcerrors.Unimplemented {
+			return nil, wrapError(dcoll, err)
+		}
+	}
+	// Read only the aggregated field, if any.
+	it := q.get(ctx, false, fps...)
+	defer it.Stop()
+	for {
+		doc := map[string]interface{}{}
+		err := it.Next(ctx, doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ddoc, err := driver.NewDocument(doc)
+		if err != nil {
+			return nil, wrapError(dcoll, err)
+		}
+		acc.Add(ddoc)
+	}
+	return acc.Value(), nil
+}
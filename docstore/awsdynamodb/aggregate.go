@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// RunAggregateQuery implements driver.Aggregator. It counts documents in
+// DynamoDB, with a query or scan whose Select is COUNT. It computes the other
+// aggregations from a query that reads only the aggregated field and the
+// keys, since DynamoDB cannot compute them.
+func (c *collection) RunAggregateQuery(ctx context.Context, q *driver.Query, agg driver.Aggregation) (interface{}, error) {
+	if agg.Op != "count" {
+		return c.accumulate(ctx, q, agg)
+	}
+	qr, err := c.planQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkPlan(qr); err != nil {
+		if c.opts.PartiQL {
+			// The PartiQL query may not need a scan.
+			return nil, gcerr.Newf(gcerr.Unimplemented, err, "count requires a scan")
+		}
+		return nil, err
+	}
+	if len(qr.keyFilters) > 0 {
+		// Only the items can be checked against these filters.
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "count with filters on keys that DynamoDB cannot apply")
+	}
+	c.setReadOptions(ctx, qr)
+	n, err := qr.count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n -= int64(q.Offset)
+	if n < 0 {
+		n = 0
+	}
+	if q.Limit > 0 && n > int64(q.Limit) {
+		n = int64(q.Limit)
+	}
+	return n, nil
+}
+
+// count returns the number of items that the query or scan of qr matches.
+func (qr *queryRunner) count(ctx context.Context) (int64, error) {
+	if qr.scanIn != nil {
+		qr.scanIn.Select = aws.String(dyn.SelectCount)
+	} else {
+		qr.queryIn.Select = aws.String(dyn.SelectCount)
+	}
+	var (
+		n    int64
+		last avmap
+	)
+	for {
+		_, l, asFunc, err := qr.run(ctx, last)
+		if err != nil {
+			return 0, err
+		}
+		var (
+			so *dyn.ScanOutput
+			qo *dyn.QueryOutput
+		)
+		switch {
+		case asFunc(&so):
+			n += aws.Int64Value(so.Count)
+		case asFunc(&qo):
+			n += aws.Int64Value(qo.Count)
+		}
+		if l == nil {
+			return n, nil
+		}
+		last = l
+	}
+}
+
+// accumulate computes agg from the documents of q, reading only the
+// aggregated field and the keys.
+func (c *collection) accumulate(ctx context.Context, q *driver.Query, agg driver.Aggregation) (interface{}, error) {
+	acc, err := driver.NewAccumulator(agg)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "invalid aggregation")
+	}
+	q.FieldPaths = [][]string{agg.FieldPath}
+	it, err := c.RunGetQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Stop()
+	for {
+		ddoc, err := driver.NewDocument(map[string]interface{}{})
+		if err != nil {
+			return nil, err
+		}
+		err = it.Next(ctx, ddoc)
+		if err == io.EOF {
+			return acc.Value(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		acc.Add(ddoc)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore"
+)
+
+func TestAggregate(t *testing.T) {
+	ctx := context.Background()
+	// The fake service scans five items in two pages: a and b, with N
+	// attributes 1 and 2 (only those are returned, as projected), then c, d
+	// and e.
+	var (
+		mu          sync.Mutex
+		selects     []string
+		projections []map[string]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Select                   string
+			ExclusiveStartKey        map[string]interface{}
+			ExpressionAttributeNames map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		selects = append(selects, in.Select)
+		projections = append(projections, in.ExpressionAttributeNames)
+		mu.Unlock()
+		out := map[string]interface{}{}
+		switch {
+		case in.ExclusiveStartKey != nil:
+			out["Count"] = 3
+		case in.Select == dyn.SelectCount:
+			out["Count"] = 2
+			out["LastEvaluatedKey"] = map[string]interface{}{"ID": map[string]string{"S": "b"}}
+		default:
+			out["Items"] = []interface{}{
+				map[string]interface{}{"ID": map[string]string{"S": "a"}, "N": map[string]string{"N": "1"}},
+				map[string]interface{}{"ID": map[string]string{"S": "b"}, "N": map[string]string{"N": "2"}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(&collection{
+		db:           dyn.New(sess),
+		table:        "t",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{AllowScans: true, RevisionField: docstore.DefaultRevisionField},
+	})
+	defer coll.Close()
+
+	n, err := coll.Query().Count(ctx)
+	if err != nil || n != 5 {
+		t.Errorf("Count: got %d, %v; want 5", n, err)
+	}
+	n, err = coll.Query().Offset(1).Limit(3).Count(ctx)
+	if err != nil || n != 3 {
+		t.Errorf("Count with offset and limit: got %d, %v; want 3", n, err)
+	}
+	if want := []string{"COUNT", "COUNT", "COUNT", "COUNT"}; !cmp.Equal(selects, want) {
+		t.Errorf("Count: got selects %q, want %q", selects, want)
+	}
+
+	selects, projections = nil, nil
+	sum, err := coll.Query().Sum(ctx, "N")
+	if err != nil || sum != 3 {
+		t.Errorf("Sum: got %v, %v; want 3", sum, err)
+	}
+	if want := []map[string]string{{"#0": "N", "#1": "ID"}}; !cmp.Equal(projections, want) || selects[0] != "" {
+		t.Errorf("Sum: got projection %v and select %q, want %v", projections, selects[0], want)
+	}
+}
@@ -18,17 +18,36 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
 	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
 )
 
 var nullValue = new(dyn.AttributeValue).SetNULL(true)
 
 type encoder struct {
 	av *dyn.AttributeValue
+	// If true, encode Go sets as DynamoDB sets. See Options.EncodeSets.
+	encodeSets bool
+	timeFormat TimeFormat
+	// If true, encode empty strings as NULL. See Options.NullEmptyStrings.
+	nullEmptyStrings bool
+}
+
+// newEncoder returns an encoder that encodes as opts says.
+func newEncoder(opts *Options) encoder {
+	return encoder{encodeSets: opts.EncodeSets, timeFormat: opts.TimeFormat, nullEmptyStrings: opts.NullEmptyStrings}
+}
+
+// nested returns an encoder for the elements of a list or map, with the
+// options of e.
+func (e *encoder) nested() encoder {
+	return encoder{encodeSets: e.encodeSets, timeFormat: e.timeFormat, nullEmptyStrings: e.nullEmptyStrings}
 }
 
 func (e *encoder) EncodeNil()        { e.av = nullValue }
@@ -44,7 +63,7 @@ func (e *encoder) ListIndex(int) { panic("impossible") }
 func (e *encoder) MapKey(string) { panic("impossible") }
 
 func (e *encoder) EncodeString(x string) {
-	if len(x) == 0 {
+	if len(x) == 0 && e.nullEmptyStrings {
 		e.av = nullValue
 	} else {
 		e.av = new(dyn.AttributeValue).SetS(x)
@@ -58,29 +77,109 @@ func (e *encoder) EncodeComplex(x complex128) {
 func (e *encoder) EncodeList(n int) driver.Encoder {
 	s := make([]*dyn.AttributeValue, n)
 	e.av = new(dyn.AttributeValue).SetL(s)
-	return &listEncoder{s: s}
+	return &listEncoder{s: s, encoder: e.nested()}
 }
 
 func (e *encoder) EncodeMap(n int) driver.Encoder {
 	m := make(map[string]*dyn.AttributeValue, n)
 	e.av = new(dyn.AttributeValue).SetM(m)
-	return &mapEncoder{m: m}
+	return &mapEncoder{m: m, encoder: e.nested()}
 }
 
 var typeOfGoTime = reflect.TypeOf(time.Time{})
 
-// EncodeSpecial encodes time.Time specially.
+// EncodeSpecial encodes time.Time in e.timeFormat, Numbers, big.Ints and
+// big.Floats as numbers, and Go sets if e.encodeSets is true.
 func (e *encoder) EncodeSpecial(v reflect.Value) (bool, error) {
-	switch v.Type() {
-	case typeOfGoTime:
-		ts := v.Interface().(time.Time).Format(time.RFC3339Nano)
-		e.EncodeString(ts)
+	switch {
+	case v.Type() == typeOfGoTime:
+		e.av = encodeTime(v.Interface().(time.Time), e.timeFormat)
+	case isBigNumber(v.Type()):
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return false, nil
+		}
+		av, err := encodeBigNumber(v)
+		if err != nil {
+			return true, err
+		}
+		e.av = av
+	case e.encodeSets && isGoSet(v.Type()):
+		e.av = encodeGoSet(v)
 	default:
 		return false, nil
 	}
 	return true, nil
 }
 
+// encodeTime encodes t as a string or number, as tf says.
+func encodeTime(t time.Time, tf TimeFormat) *dyn.AttributeValue {
+	switch tf {
+	case TimeUnixSeconds:
+		return new(dyn.AttributeValue).SetN(strconv.FormatInt(t.Unix(), 10))
+	case TimeUnixMillis:
+		return new(dyn.AttributeValue).SetN(strconv.FormatInt(t.UnixMilli(), 10))
+	case TimeUnixNano:
+		return new(dyn.AttributeValue).SetN(strconv.FormatInt(t.UnixNano(), 10))
+	default:
+		return new(dyn.AttributeValue).SetS(t.Format(time.RFC3339Nano))
+	}
+}
+
+// isGoSet reports whether t is a map type with string or numeric keys and
+// struct{} elements, like map[string]struct{}.
+func isGoSet(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Elem().Kind() != reflect.Struct || t.Elem().NumField() != 0 {
+		return false
+	}
+	return t.Key().Kind() == reflect.String || isNumberKind(t.Key().Kind())
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// encodeGoSet encodes the Go set v as a string set if its keys are strings,
+// and a number set otherwise. DynamoDB sets cannot be empty, so an empty Go set
+// is encoded as null.
+func encodeGoSet(v reflect.Value) *dyn.AttributeValue {
+	if v.Len() == 0 {
+		return nullValue
+	}
+	elems := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		switch {
+		case k.Kind() == reflect.String:
+			elems = append(elems, k.String())
+		case k.CanInt():
+			elems = append(elems, strconv.FormatInt(k.Int(), 10))
+		case k.CanUint():
+			elems = append(elems, strconv.FormatUint(k.Uint(), 10))
+		default:
+			elems = append(elems, strconv.FormatFloat(k.Float(), 'f', -1, 64))
+		}
+	}
+	// Sort for a deterministic encoding.
+	sort.Strings(elems)
+	if v.Type().Key().Kind() == reflect.String {
+		return new(dyn.AttributeValue).SetSS(stringPtrs(elems))
+	}
+	return new(dyn.AttributeValue).SetNS(stringPtrs(elems))
+}
+
+func stringPtrs(ss []string) []*string {
+	ps := make([]*string, len(ss))
+	for i := range ss {
+		ps[i] = &ss[i]
+	}
+	return ps
+}
+
 type listEncoder struct {
 	s []*dyn.AttributeValue
 	encoder
@@ -95,18 +194,131 @@ type mapEncoder struct {
 
 func (e *mapEncoder) MapKey(k string) { e.m[k] = e.av }
 
-func encodeDoc(doc driver.Document) (*dyn.AttributeValue, error) {
-	var e encoder
+// encodeDoc encodes doc, encoding Go sets and the lists in opts.SetFields as
+// DynamoDB sets, and the TTL field, as opts says.
+func encodeDoc(doc driver.Document, opts *Options) (*dyn.AttributeValue, error) {
+	e := newEncoder(opts)
 	if err := doc.Encode(&e); err != nil {
 		return nil, err
 	}
+	for _, sf := range opts.SetFields {
+		if err := listToSet(e.av, strings.Split(sf, "."), sf); err != nil {
+			return nil, err
+		}
+	}
+	if opts.TTLField != "" && doc.HasField(opts.TTLField) {
+		v, err := doc.GetField(opts.TTLField)
+		if err != nil {
+			return nil, err
+		}
+		ttl, err := encodeTTL(v)
+		if err != nil {
+			return nil, err
+		}
+		if ttl == nil {
+			delete(e.av.M, opts.TTLField)
+		} else {
+			e.av.M[opts.TTLField] = ttl
+		}
+	}
+	return e.av, nil
+}
+
+// listToSet replaces the list at the field path fp in av with a DynamoDB
+// set. It does nothing if there is no list at fp. name is the name of the
+// field for errors.
+func listToSet(av *dyn.AttributeValue, fp []string, name string) error {
+	for _, f := range fp {
+		if av.M == nil {
+			return nil
+		}
+		if av = av.M[f]; av == nil {
+			return nil
+		}
+	}
+	if av.L == nil {
+		return nil
+	}
+	set, err := listAsSet(av.L)
+	if err != nil {
+		return gcerr.Newf(gcerr.InvalidArgument, err, "field %q", name)
+	}
+	*av = *set
+	return nil
+}
+
+// listAsSet returns the elements of l as a string, number or binary set,
+// without duplicates. DynamoDB sets cannot be empty, so an empty list becomes
+// null.
+func listAsSet(l []*dyn.AttributeValue) (*dyn.AttributeValue, error) {
+	if len(l) == 0 {
+		return nullValue, nil
+	}
+	set := &dyn.AttributeValue{}
+	seen := map[string]bool{}
+	for _, el := range l {
+		switch {
+		case el.S != nil && set.NS == nil && set.BS == nil:
+			if !seen[*el.S] {
+				set.SS = append(set.SS, el.S)
+			}
+			seen[*el.S] = true
+		case el.N != nil && set.SS == nil && set.BS == nil:
+			if !seen[*el.N] {
+				set.NS = append(set.NS, el.N)
+			}
+			seen[*el.N] = true
+		case el.B != nil && set.SS == nil && set.NS == nil:
+			if !seen[string(el.B)] {
+				set.BS = append(set.BS, el.B)
+			}
+			seen[string(el.B)] = true
+		default:
+			return nil, errors.New("only a list of non-empty strings, of numbers or of byte slices can be encoded as a set")
+		}
+	}
+	return set, nil
+}
+
+// encodeUpdateValue encodes v, the new value of the field at fp in an update,
+// as Put would encode it in a document: in particular, the fields in
+// opts.SetFields that fp is or contains are encoded as sets.
+func encodeUpdateValue(fp []string, v interface{}, opts *Options) (*dyn.AttributeValue, error) {
+	e := newEncoder(opts)
+	if err := driver.Encode(reflect.ValueOf(v), &e); err != nil {
+		return nil, err
+	}
+	path := strings.Join(fp, ".")
+	for _, sf := range opts.SetFields {
+		var rest []string
+		if sf != path {
+			if !strings.HasPrefix(sf, path+".") {
+				continue
+			}
+			rest = strings.Split(strings.TrimPrefix(sf, path+"."), ".")
+		}
+		if err := listToSet(e.av, rest, sf); err != nil {
+			return nil, err
+		}
+	}
 	return e.av, nil
 }
 
+// encodedValue is a value that has already been encoded. It lets values
+// encoded by this package be used in expressions.
+type encodedValue struct {
+	av *dyn.AttributeValue
+}
+
+func (v encodedValue) MarshalDynamoDBAttributeValue(av *dyn.AttributeValue) error {
+	*av = *v.av
+	return nil
+}
+
 // Encode the key fields of the given document into a map AttributeValue.
 // pkey and skey are the names of the partition key field and the sort key field.
 // pkey must always be non-empty, but skey may be empty if the collection has no sort key.
-func encodeDocKeyFields(doc driver.Document, pkey, skey string) (*dyn.AttributeValue, error) {
+func encodeDocKeyFields(doc driver.Document, pkey, skey string, opts *Options) (*dyn.AttributeValue, error) {
 	m := map[string]*dyn.AttributeValue{}
 
 	set := func(fieldName string) error {
@@ -114,7 +326,7 @@ func encodeDocKeyFields(doc driver.Document, pkey, skey string) (*dyn.AttributeV
 		if err != nil {
 			return err
 		}
-		attrVal, err := encodeValue(fieldVal)
+		attrVal, err := encodeValue(fieldVal, opts)
 		if err != nil {
 			return err
 		}
@@ -133,8 +345,8 @@ func encodeDocKeyFields(doc driver.Document, pkey, skey string) (*dyn.AttributeV
 	return new(dyn.AttributeValue).SetM(m), nil
 }
 
-func encodeValue(v interface{}) (*dyn.AttributeValue, error) {
-	var e encoder
+func encodeValue(v interface{}, opts *Options) (*dyn.AttributeValue, error) {
+	e := newEncoder(opts)
 	if err := driver.Encode(reflect.ValueOf(v), &e); err != nil {
 		return nil, err
 	}
@@ -147,12 +359,17 @@ func encodeFloat(f float64) *dyn.AttributeValue {
 
 ////////////////////////////////////////////////////////////////
 
-func decodeDoc(item *dyn.AttributeValue, doc driver.Document) error {
-	return doc.Decode(decoder{av: item})
+func decodeDoc(item *dyn.AttributeValue, doc driver.Document, opts *Options) error {
+	return doc.Decode(decoder{av: item, timeFormat: opts.TimeFormat, ttlField: opts.TTLField})
 }
 
 type decoder struct {
 	av *dyn.AttributeValue
+	// The format of numeric times. See AsSpecial.
+	timeFormat TimeFormat
+	// The name of the TTL field, which holds epoch seconds whatever the
+	// time format. Set only in the decoder of a document.
+	ttlField string
 }
 
 func (d decoder) String() string {
@@ -171,7 +388,8 @@ func (d decoder) AsNull() bool {
 }
 
 func (d decoder) AsString() (string, bool) {
-	// Empty string is represented by NULL.
+	// Empty strings are stored as NULL with Options.NullEmptyStrings, as they
+	// were by earlier versions of this package.
 	if d.av.NULL != nil {
 		return "", true
 	}
@@ -185,22 +403,14 @@ func (d decoder) AsInt() (int64, bool) {
 	if d.av.N == nil {
 		return 0, false
 	}
-	i, err := strconv.ParseInt(*d.av.N, 10, 64)
-	if err != nil {
-		return 0, false
-	}
-	return i, true
+	return parseInt(*d.av.N)
 }
 
 func (d decoder) AsUint() (uint64, bool) {
 	if d.av.N == nil {
 		return 0, false
 	}
-	u, err := strconv.ParseUint(*d.av.N, 10, 64)
-	if err != nil {
-		return 0, false
-	}
-	return u, true
+	return parseUint(*d.av.N)
 }
 
 func (d decoder) AsFloat() (float64, bool) {
@@ -221,11 +431,11 @@ func (d decoder) AsComplex() (complex128, bool) {
 	if len(d.av.L) != 2 {
 		return 0, false
 	}
-	r, ok := decoder{d.av.L[0]}.AsFloat()
+	r, ok := decoder{av: d.av.L[0]}.AsFloat()
 	if !ok {
 		return 0, false
 	}
-	i, ok := decoder{d.av.L[1]}.AsFloat()
+	i, ok := decoder{av: d.av.L[1]}.AsFloat()
 	if !ok {
 		return 0, false
 	}
@@ -239,21 +449,54 @@ func (d decoder) AsBytes() ([]byte, bool) {
 	return d.av.B, true
 }
 
+// ListLen returns the length of a list or set. Sets decode like lists.
 func (d decoder) ListLen() (int, bool) {
-	if d.av.L == nil {
-		return 0, false
+	switch {
+	case d.av.L != nil:
+		return len(d.av.L), true
+	case d.av.SS != nil:
+		return len(d.av.SS), true
+	case d.av.NS != nil:
+		return len(d.av.NS), true
+	case d.av.BS != nil:
+		return len(d.av.BS), true
 	}
-	return len(d.av.L), true
+	return 0, false
 }
 
 func (d decoder) DecodeList(f func(i int, vd driver.Decoder) bool) {
-	for i, el := range d.av.L {
-		if !f(i, decoder{el}) {
+	for i, el := range listElems(d.av) {
+		if !f(i, decoder{av: el, timeFormat: d.timeFormat}) {
 			break
 		}
 	}
 }
 
+// listElems returns the elements of the list or set av.
+func listElems(av *dyn.AttributeValue) []*dyn.AttributeValue {
+	switch {
+	case av.SS != nil:
+		l := make([]*dyn.AttributeValue, len(av.SS))
+		for i, s := range av.SS {
+			l[i] = &dyn.AttributeValue{S: s}
+		}
+		return l
+	case av.NS != nil:
+		l := make([]*dyn.AttributeValue, len(av.NS))
+		for i, n := range av.NS {
+			l[i] = &dyn.AttributeValue{N: n}
+		}
+		return l
+	case av.BS != nil:
+		l := make([]*dyn.AttributeValue, len(av.BS))
+		for i, b := range av.BS {
+			l[i] = &dyn.AttributeValue{B: b}
+		}
+		return l
+	}
+	return av.L
+}
+
 func (d decoder) MapLen() (int, bool) {
 	if d.av.M == nil {
 		return 0, false
@@ -263,7 +506,11 @@ func (d decoder) MapLen() (int, bool) {
 
 func (d decoder) DecodeMap(f func(key string, vd driver.Decoder, exactMatch bool) bool) {
 	for k, av := range d.av.M {
-		if !f(k, decoder{av}, true) {
+		vd := decoder{av: av, timeFormat: d.timeFormat}
+		if k == d.ttlField {
+			vd.timeFormat = TimeUnixSeconds
+		}
+		if !f(k, vd, true) {
 			break
 		}
 	}
@@ -280,28 +527,25 @@ func toGoValue(av *dyn.AttributeValue) (interface{}, error) {
 	case av.BOOL != nil:
 		return *av.BOOL, nil
 	case av.N != nil:
-		f, err := strconv.ParseFloat(*av.N, 64)
-		if err != nil {
-			return nil, err
-		}
-		i := int64(f)
-		if float64(i) == f {
+		// Parse integers without going through a float64, which would lose
+		// the precision of large ones.
+		if i, ok := parseInt(*av.N); ok {
 			return i, nil
 		}
-		u := uint64(f)
-		if float64(u) == f {
+		if u, ok := parseUint(*av.N); ok {
 			return u, nil
 		}
-		return f, nil
+		return strconv.ParseFloat(*av.N, 64)
 
 	case av.B != nil:
 		return av.B, nil
 	case av.S != nil:
 		return *av.S, nil
 
-	case av.L != nil:
-		s := make([]interface{}, len(av.L))
-		for i, v := range av.L {
+	case av.L != nil, av.SS != nil, av.NS != nil, av.BS != nil:
+		l := listElems(av)
+		s := make([]interface{}, len(l))
+		for i, v := range l {
 			x, err := toGoValue(v)
 			if err != nil {
 				return nil, err
@@ -326,19 +570,88 @@ func toGoValue(av *dyn.AttributeValue) (interface{}, error) {
 	}
 }
 
+// AsSpecial decodes time.Time specially, decodes numbers into Numbers,
+// big.Ints and big.Floats without loss, and decodes string and number sets into
+// Go sets.
+//
+// A time.Time is decoded from an RFC 3339 string whatever the time format, so
+// that times written before the format changed can still be read. It is
+// decoded from a number only if the time format is numeric.
 func (d decoder) AsSpecial(v reflect.Value) (bool, interface{}, error) {
-	unsupportedTypes := `unsupported type, the docstore driver for DynamoDB does
-	not decode DynamoDB set types, such as string set, number set and binary set`
-	if d.av.SS != nil || d.av.NS != nil || d.av.BS != nil {
-		return true, nil, errors.New(unsupportedTypes)
-	}
-	switch v.Type() {
-	case typeOfGoTime:
-		if d.av.S == nil {
+	switch {
+	case v.Type() == typeOfGoTime:
+		if d.av.S == nil && (d.av.N == nil || d.timeFormat == TimeRFC3339) {
 			return false, nil, errors.New("expected string field for time.Time")
 		}
-		t, err := time.Parse(time.RFC3339Nano, *d.av.S)
+		t, err := decodeTime(d.av, d.timeFormat)
 		return true, t, err
+	case d.av.N != nil && isBigNumber(v.Type()):
+		x, err := decodeBigNumber(*d.av.N, v.Type())
+		return true, x, err
+	case (d.av.SS != nil || d.av.NS != nil) && isGoSet(v.Type()):
+		return decodeGoSet(d.av, v.Type())
 	}
 	return false, nil, nil
 }
+
+// decodeTime decodes a time.Time from a string, or a number in the numeric
+// format tf.
+func decodeTime(av *dyn.AttributeValue, tf TimeFormat) (time.Time, error) {
+	if av.S != nil {
+		return time.Parse(time.RFC3339Nano, *av.S)
+	}
+	n, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected integer field for time.Time: %v", err)
+	}
+	switch tf {
+	case TimeUnixSeconds:
+		return time.Unix(n, 0), nil
+	case TimeUnixMillis:
+		return time.UnixMilli(n), nil
+	default:
+		return time.Unix(0, n), nil
+	}
+}
+
+// decodeGoSet decodes the string or number set av into a new Go set of type
+// t.
+func decodeGoSet(av *dyn.AttributeValue, t reflect.Type) (bool, interface{}, error) {
+	elems := av.NS
+	if av.SS != nil {
+		if t.Key().Kind() != reflect.String {
+			return true, nil, fmt.Errorf("cannot decode a string set into %s", t)
+		}
+		elems = av.SS
+	}
+	m := reflect.MakeMapWithSize(t, len(elems))
+	k := reflect.New(t.Key()).Elem()
+	for _, e := range elems {
+		var err error
+		switch kind := k.Kind(); {
+		case kind == reflect.String:
+			k.SetString(*e)
+		case k.CanInt():
+			var i int64
+			if i, err = strconv.ParseInt(*e, 10, 64); err == nil && k.OverflowInt(i) {
+				err = fmt.Errorf("%s overflows %s", *e, t.Key())
+			}
+			k.SetInt(i)
+		case k.CanUint():
+			var u uint64
+			if u, err = strconv.ParseUint(*e, 10, 64); err == nil && k.OverflowUint(u) {
+				err = fmt.Errorf("%s overflows %s", *e, t.Key())
+			}
+			k.SetUint(u)
+		default:
+			var f float64
+			f, err = strconv.ParseFloat(*e, 64)
+			k.SetFloat(f)
+		}
+		if err != nil {
+			return true, nil, fmt.Errorf("decoding set into %s: %v", t, err)
+		}
+		m.SetMapIndex(k, reflect.New(t.Elem()).Elem())
+	}
+	return true, m.Interface(), nil
+}
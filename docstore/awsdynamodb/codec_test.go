@@ -16,7 +16,9 @@ package awsdynamodb
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
 	dynattr "github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -42,7 +44,7 @@ func TestEncodeValue(t *testing.T) {
 		{0, avn("0")},
 		{uint64(999), avn("999")},
 		{3.5, avn("3.5")},
-		{"", nullValue},
+		{"", av().SetS("")},
 		{"x", av().SetS("x")},
 		{true, av().SetBOOL(true)},
 		{nullptr, nullValue},
@@ -74,24 +76,265 @@ func TestEncodeValue(t *testing.T) {
 	}
 }
 
-func TestDecodeErrorOnUnsupported(t *testing.T) {
+func TestEmptyStrings(t *testing.T) {
+	type doc struct {
+		S    string
+		Tags []string
+	}
+	in := &doc{Tags: []string{""}}
+	for _, test := range []struct {
+		opts *Options
+		want *dyn.AttributeValue
+	}{
+		{&Options{}, new(dyn.AttributeValue).SetS("")},
+		{&Options{NullEmptyStrings: true}, nullValue},
+	} {
+		av, err := encodeDoc(drivertest.MustDocument(in), test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []*dyn.AttributeValue{av.M["S"], av.M["Tags"].L[0]}
+		want := []*dyn.AttributeValue{test.want, test.want}
+		if !cmp.Equal(got, want, cmpopts.IgnoreUnexported(dyn.AttributeValue{})) {
+			t.Errorf("%+v: got %v, want %v", test.opts, got, want)
+		}
+		// Both encodings decode to the empty string.
+		out := &doc{S: "x", Tags: []string{"x"}}
+		if err := decodeDoc(av, drivertest.MustDocument(out), test.opts); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(out, in) {
+			t.Errorf("%+v: got %+v, want %+v", test.opts, out, in)
+		}
+
+		// Filters compare with the same encoding.
+		q := &driver.Query{Filters: []driver.Filter{{FieldPath: []string{"S"}, Op: "=", Value: ""}}}
+		c := &collection{opts: test.opts}
+		c.encodeFilterValues(q)
+		if test.opts.NullEmptyStrings {
+			if q.Filters[0].Value != "" {
+				t.Errorf("%+v: got filter value %#v, want \"\"", test.opts, q.Filters[0].Value)
+			}
+		} else if ev, ok := q.Filters[0].Value.(encodedValue); !ok || ev.av.S == nil || *ev.av.S != "" {
+			t.Errorf("%+v: got filter value %#v, want an encoded empty string", test.opts, q.Filters[0].Value)
+		}
+	}
+}
+
+func TestEncodeSets(t *testing.T) {
+	av := func() *dyn.AttributeValue { return &dyn.AttributeValue{} }
+	sptr := func(s string) *string { return &s }
+	avss := func(ss ...string) *dyn.AttributeValue {
+		var ps []*string
+		for _, s := range ss {
+			ps = append(ps, sptr(s))
+		}
+		return av().SetSS(ps)
+	}
+	avns := func(ns ...string) *dyn.AttributeValue {
+		var ps []*string
+		for _, n := range ns {
+			ps = append(ps, sptr(n))
+		}
+		return av().SetNS(ps)
+	}
+
+	type doc struct {
+		Strings  []string
+		Numbers  []float64
+		Bytes    [][]byte
+		GoSet    map[string]struct{}
+		IntSet   map[int]struct{}
+		List     []string
+		Empty    []string
+		Info     map[string]interface{}
+		NotASet  map[string]bool
+		Optional []int
+	}
+	in := &doc{
+		Strings: []string{"b", "a", "b"},
+		Numbers: []float64{1.5, 2},
+		Bytes:   [][]byte{{1}, {2}},
+		GoSet:   map[string]struct{}{"y": {}, "x": {}},
+		IntSet:  map[int]struct{}{3: {}, 1: {}},
+		List:    []string{"a"},
+		Empty:   []string{},
+		Info:    map[string]interface{}{"Aliases": []string{"al"}},
+		NotASet: map[string]bool{"k": true},
+	}
+	opts := &Options{
+		EncodeSets: true,
+		SetFields:  []string{"Strings", "Numbers", "Bytes", "Empty", "Info.Aliases", "Optional", "Missing.Field"},
+	}
+	got, err := encodeDoc(drivertest.MustDocument(in), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*dyn.AttributeValue{
+		"Strings":  avss("b", "a"),
+		"Numbers":  avns("1.5", "2"),
+		"Bytes":    av().SetBS([][]byte{{1}, {2}}),
+		"GoSet":    avss("x", "y"),
+		"IntSet":   avns("1", "3"),
+		"List":     av().SetL([]*dyn.AttributeValue{av().SetS("a")}),
+		"Empty":    nullValue,
+		"Info":     av().SetM(map[string]*dyn.AttributeValue{"Aliases": avss("al")}),
+		"NotASet":  av().SetM(map[string]*dyn.AttributeValue{"k": av().SetBOOL(true)}),
+		"Optional": nullValue,
+	}
+	if diff := cmp.Diff(want, got.M, cmpopts.IgnoreUnexported(dyn.AttributeValue{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without the options, nothing is encoded as a set.
+	got, err = encodeDoc(drivertest.MustDocument(in), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasSet(got) {
+		t.Errorf("got sets without set options: %v", got)
+	}
+
+	// A list of mixed types cannot be a set.
+	mixed := map[string]interface{}{"Strings": []interface{}{"a", 1}}
+	if _, err := encodeDoc(drivertest.MustDocument(mixed), opts); err == nil {
+		t.Error("mixed list: got nil error, want error")
+	}
+}
+
+func TestEncodeUpdateValue(t *testing.T) {
+	av := func() *dyn.AttributeValue { return &dyn.AttributeValue{} }
+	ss := func(s string) *dyn.AttributeValue { return av().SetSS([]*string{&s}) }
+	opts := &Options{EncodeSets: true, SetFields: []string{"Tags", "Info.Aliases"}, TimeFormat: TimeUnixSeconds}
+	for _, test := range []struct {
+		fp   string
+		in   interface{}
+		want *dyn.AttributeValue
+	}{
+		{"Tags", []string{"a"}, ss("a")},
+		{"Tags", []string{}, nullValue},
+		{"Info", map[string]interface{}{"Aliases": []string{"a"}}, av().SetM(map[string]*dyn.AttributeValue{"Aliases": ss("a")})},
+		{"Other", map[string]struct{}{"a": {}}, ss("a")},
+		{"Other", []string{"a"}, av().SetL([]*dyn.AttributeValue{av().SetS("a")})},
+		{"Other", "a", av().SetS("a")},
+		{"Other", time.Unix(1767323045, 0), av().SetN("1767323045")},
+		{"Other", Number("1.5"), av().SetN("1.5")},
+	} {
+		got, err := encodeUpdateValue(strings.Split(test.fp, "."), test.in, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(got, test.want, cmpopts.IgnoreUnexported(dyn.AttributeValue{})) {
+			t.Errorf("%s = %v: got %v, want %v", test.fp, test.in, got, test.want)
+		}
+	}
+}
+
+func TestDecodeSets(t *testing.T) {
 	av := func() *dyn.AttributeValue { return &dyn.AttributeValue{} }
 	sptr := func(s string) *string { return &s }
-	for _, tc := range []struct {
+	ss := av().SetSS([]*string{sptr("foo"), sptr("bar")})
+	ns := av().SetNS([]*string{sptr("1"), sptr("-2"), sptr("3")})
+	bs := av().SetBS([][]byte{{4}, {5}, {6}})
+	for _, test := range []struct {
+		in   *dyn.AttributeValue
+		out  interface{} // pointer to decode into
+		want interface{}
+	}{
+		{ss, new([]string), []string{"foo", "bar"}},
+		{ss, new([2]string), [2]string{"foo", "bar"}},
+		{ss, new(map[string]struct{}), map[string]struct{}{"foo": {}, "bar": {}}},
+		{ss, new(interface{}), []interface{}{"foo", "bar"}},
+		{ns, new([]float64), []float64{1, -2, 3}},
+		{ns, new([]int), []int{1, -2, 3}},
+		{ns, new(map[int8]struct{}), map[int8]struct{}{1: {}, -2: {}, 3: {}}},
+		{ns, new(map[string]struct{}), map[string]struct{}{"1": {}, "-2": {}, "3": {}}},
+		{ns, new(interface{}), []interface{}{int64(1), int64(-2), int64(3)}},
+		{bs, new([][]byte), [][]byte{{4}, {5}, {6}}},
+		{bs, new(interface{}), []interface{}{[]byte{4}, []byte{5}, []byte{6}}},
+	} {
+		if err := driver.Decode(reflect.ValueOf(test.out).Elem(), &decoder{av: test.in}); err != nil {
+			t.Errorf("%v into %T: %v", test.in, test.out, err)
+			continue
+		}
+		if got := reflect.ValueOf(test.out).Elem().Interface(); !cmp.Equal(got, test.want) {
+			t.Errorf("%v into %T: got %v, want %v", test.in, test.out, got, test.want)
+		}
+	}
+
+	for _, test := range []struct {
 		in  *dyn.AttributeValue
 		out interface{}
 	}{
-		{av().SetSS([]*string{sptr("foo"), sptr("bar")}), []string{}},
-		{av().SetNS([]*string{sptr("1.1"), sptr("-2.2"), sptr("3.3")}), []float64{}},
-		{av().SetBS([][]byte{{4}, {5}, {6}}), [][]byte{}},
+		{ss, new(map[int]struct{})},
+		{ns, new(map[uint8]struct{})},
+		{bs, new(map[string]struct{})},
+		{ss, new([]int)},
 	} {
-		d := decoder{av: tc.in}
-		if err := driver.Decode(reflect.ValueOf(tc.out), &d); err == nil {
-			t.Error("got nil error, want unsupported error")
+		if err := driver.Decode(reflect.ValueOf(test.out).Elem(), &decoder{av: test.in}); err == nil {
+			t.Errorf("%v into %T: got nil error, want error", test.in, test.out)
 		}
 	}
 }
 
+func TestTimeFormats(t *testing.T) {
+	tm := time.Date(2026, 1, 2, 3, 4, 5, 6007008, time.UTC)
+	type doc struct {
+		T time.Time
+	}
+	for _, test := range []struct {
+		tf   TimeFormat
+		want *dyn.AttributeValue
+		// The time decoded, after truncation.
+		wantTime time.Time
+	}{
+		{TimeRFC3339, new(dyn.AttributeValue).SetS("2026-01-02T03:04:05.006007008Z"), tm},
+		{TimeUnixSeconds, new(dyn.AttributeValue).SetN("1767323045"), tm.Truncate(time.Second)},
+		{TimeUnixMillis, new(dyn.AttributeValue).SetN("1767323045006"), tm.Truncate(time.Millisecond)},
+		{TimeUnixNano, new(dyn.AttributeValue).SetN("1767323045006007008"), tm},
+	} {
+		opts := &Options{TimeFormat: test.tf}
+		av, err := encodeDoc(drivertest.MustDocument(&doc{tm}), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := av.M["T"]; !cmp.Equal(got, test.want, cmpopts.IgnoreUnexported(dyn.AttributeValue{})) {
+			t.Errorf("format %d: got %v, want %v", test.tf, got, test.want)
+		}
+		var got doc
+		if err := decodeDoc(av, drivertest.MustDocument(&got), opts); err != nil {
+			t.Fatal(err)
+		}
+		if !got.T.Equal(test.wantTime) {
+			t.Errorf("format %d: decoded %v, want %v", test.tf, got.T, test.wantTime)
+		}
+
+		// Times stored as strings can be read in any format.
+		rfc := new(dyn.AttributeValue).SetM(map[string]*dyn.AttributeValue{"T": new(dyn.AttributeValue).SetS(tm.Format(time.RFC3339Nano))})
+		if err := decodeDoc(rfc, drivertest.MustDocument(&got), opts); err != nil {
+			t.Fatal(err)
+		}
+		if !got.T.Equal(tm) {
+			t.Errorf("format %d: decoded string as %v, want %v", test.tf, got.T, tm)
+		}
+	}
+
+	// Numbers are not times in the default format, and strings are not
+	// times when decoded into interface{}.
+	num := new(dyn.AttributeValue).SetM(map[string]*dyn.AttributeValue{"T": new(dyn.AttributeValue).SetN("1")})
+	if err := decodeDoc(num, drivertest.MustDocument(&doc{}), &Options{}); err == nil {
+		t.Error("number as RFC 3339 time: got nil error, want error")
+	}
+	str := new(dyn.AttributeValue).SetM(map[string]*dyn.AttributeValue{"T": new(dyn.AttributeValue).SetS(tm.Format(time.RFC3339Nano))})
+	m := map[string]interface{}{}
+	if err := decodeDoc(str, drivertest.MustDocument(m), &Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["T"].(string); !ok {
+		t.Errorf("got %T, want string", m["T"])
+	}
+}
+
 type codecTester struct{}
 
 func (ct *codecTester) UnsupportedTypes() []drivertest.UnsupportedType {
@@ -107,9 +350,27 @@ func (ct *codecTester) NativeDecode(value, dest interface{}) error {
 }
 
 func (ct *codecTester) DocstoreEncode(obj interface{}) (interface{}, error) {
-	return encodeDoc(drivertest.MustDocument(obj))
+	return encodeDoc(drivertest.MustDocument(obj), &Options{})
 }
 
 func (ct *codecTester) DocstoreDecode(value, dest interface{}) error {
-	return decodeDoc(value.(*dyn.AttributeValue), drivertest.MustDocument(dest))
+	return decodeDoc(value.(*dyn.AttributeValue), drivertest.MustDocument(dest), &Options{})
+}
+
+// hasSet reports whether av is or contains a DynamoDB set.
+func hasSet(av *dyn.AttributeValue) bool {
+	if av.SS != nil || av.NS != nil || av.BS != nil {
+		return true
+	}
+	for _, el := range av.L {
+		if hasSet(el) {
+			return true
+		}
+	}
+	for _, el := range av.M {
+		if hasSet(el) {
+			return true
+		}
+	}
+	return false
 }
@@ -16,6 +16,9 @@
 // DynamoDB.
 // Use OpenCollection to construct a *docstore.Collection.
 //
+// awsdynamodb is built on the AWS SDK for Go V1 (github.com/aws/aws-sdk-go)
+// only; it has no AWS SDK V2 client, codec or types.
+//
 // # URLs
 //
 // For docstore.OpenCollection, awsdynamodb registers for the scheme
@@ -26,32 +29,176 @@
 // URLOpener.
 // See https://gocloud.dev/concepts/urls/ for background information.
 //
+// # Per-call options
+//
+// awsdynamodb supports the callopt.Consistency hint of gocloud.dev/callopt,
+// which overrides Options.ConsistentRead for the reads made with a context;
+// ScanSegmentsHint, which overrides Options.ScanSegments for a query; and
+// ReturnConsumedCapacityHint, which overrides Options.ReturnConsumedCapacity.
+// For instance, to make the Gets of one action list strongly consistent:
+//
+//	ctx = callopt.WithHint(ctx, callopt.Consistency, callopt.Strong)
+//	err := coll.Actions().Get(doc1).Get(doc2).Do(ctx)
+//
+// # Consumed capacity
+//
+// If Options.ReturnConsumedCapacity or the ReturnConsumedCapacityHint hint is
+// set, DynamoDB reports the capacity that each request consumes. awsdynamodb
+// passes it to the Options.ConsumedCapacity callback, together with the
+// context of the action list or query, which can identify the caller for cost
+// attribution. The capacity of a query's requests is also available from
+// DocumentIterator.As, in the *dynamodb.QueryOutput or *dynamodb.ScanOutput.
+//
+// # Retries
+//
+// Besides the retries of the AWS SDK, awsdynamodb retries the requests that
+// DynamoDB throttles, with exponential backoff and jitter, as
+// Options.RetryPolicy or the callopt.RetryPolicy of the context allows. The
+// keys that a BatchGetItem request leaves unprocessed, and the statements of
+// a batch that are throttled, are retried by themselves. With
+// Options.AdaptiveRetry, all the requests of the collection slow down while
+// DynamoDB throttles them. An action list therefore needs no retry loop of
+// its own for throttling.
+//
+// The throttled requests and the retries are counted by the
+// gocdk.docstore.awsdynamodb.throttles and gocdk.docstore.awsdynamodb.retries
+// OpenTelemetry metrics, with the DynamoDB operation in the rpc.method
+// attribute, and each retry is recorded as an event of the span of the call.
+//
+// # Queries
+//
+// A query with an equality filter on the partition key of the table or of an
+// index runs as a DynamoDB Query; other queries scan the table, if
+// Options.AllowScans is set. Filters on the sort key become its key condition:
+// an equality, a single comparison, BETWEEN for a pair of filters ">=" and
+// "<=", or begins_with for a pair of filters selecting a string prefix, such as
+//
+//	q.Where("SortKey", ">=", "user#").Where("SortKey", "<", "user$")
+//
+// An "in" filter on the sort key becomes BETWEEN its least and greatest values.
+// DynamoDB does not allow filters on keys in a Query's filter expression, so
+// the other filters on keys are applied to the items that the Query returns.
+//
+// A query can be ordered by the sort key of its table or index, which
+// DynamoDB reads in either direction. If the query has a Limit and no filters
+// besides its key condition, the Limit is passed to DynamoDB, so that only
+// the items returned are read. To order by other fields, set
+// Options.RunQueryFallback to InMemorySortFallback, or to
+// BoundedInMemorySortFallback to cap the number of documents held in memory.
+//
+// Query.Count counts the items in DynamoDB, with a Query or Scan whose Select
+// is COUNT, unless the query has filters on keys that must be applied to the
+// items. Query.Sum, Query.Min and Query.Max read only the aggregated field and
+// the keys of the items.
+//
+// # PartiQL
+//
+// If Options.PartiQL is set, queries are run as PartiQL SELECT statements.
+// RunPartiQL runs any PartiQL statement and decodes the items it returns as
+// the collection decodes documents, and RunPartiQLBatch runs a batch of
+// statements with BatchExecuteStatement.
+//
+// # Updates
+//
+// The field paths of an Update's mods may name nested attributes, as in
+// "Profile.Settings.Theme", and index into lists, as in "Addresses[0].City".
+// A nil value removes the attribute. DynamoDB does not create the maps and
+// lists on a path, so they must exist. Besides docstore.Increment,
+// docstore.ListAppend appends elements to a list with list_append, and
+// ListPrepend inserts them at its beginning. docstore.SetAdd and
+// docstore.SetRemove become ADD and DELETE actions, so the field must hold a
+// DynamoDB set, such as a field of Options.SetFields, or be absent, and the
+// values must be all strings, all numbers or all byte slices; a set left
+// empty is removed. The paths of an Update cannot overlap: "Tags" and
+// "Tags[0]" cannot both be modified.
+//
+// # Revisions
+//
+// Revisions are unique strings, stored in the field named by
+// Options.RevisionField. For a table whose optimistic locking uses a
+// numeric version attribute, such as one written by other applications, set
+// Options.VersionRevisions and Options.RevisionField to the attribute: writes
+// then increment the version, with conditions on the version of the
+// document, as in
+//
+//	SET #version = #version + :one
+//
+// with the condition #version = :v.
+//
+// # Transactions
+//
+// awsdynamodb runs atomic action lists (see ActionList.Atomic) as DynamoDB
+// transactions: the writes with one TransactWriteItems call, and the Gets
+// before and after them with a TransactGetItems call each. Each call is
+// limited to 100 actions. Writes of documents with revisions are conditioned
+// on the revision, as they are outside of a transaction; if one write's
+// condition fails, the other writes fail with FailedPrecondition.
+//
+// The three calls are not isolated from each other: another client can write
+// a document between the Gets before the writes and the writes, or between
+// the writes and the Gets after them. Condition the writes on revisions to
+// detect such changes. If the Gets before the writes fail, the writes and the
+// Gets after them are not run, and fail with the same error.
+//
+// # Changes
+//
+// Collection.Watch reads the changes to the documents from the table's
+// DynamoDB stream, which must be enabled; see TableSchema.StreamView. The
+// stream's view type determines which versions of a changed document
+// Change.Old and Change.New can decode: both with NEW_AND_OLD_IMAGES, and only
+// the keys with KEYS_ONLY. DynamoDB keeps the changes for 24 hours. The
+// iterator reads the shards of the stream concurrently, so only the changes
+// to the same document are in order.
+//
+// # DAX
+//
+// OpenCollection accepts any dynamodbiface.DynamoDBAPI, so a DynamoDB
+// Accelerator (DAX) client can serve the collection's reads from its cache
+// and pass its writes through to the table. DAX does not serve calls about
+// the table itself, so pass a regular client in Options.TableClient, and a
+// streams client in Options.StreamsClient to use Collection.Watch.
+//
 // # As
 //
 // awsdynamodb exposes the following types for As:
-//   - Collection.As: *dynamodb.DynamoDB
+//   - Collection.As: *dynamodb.DynamoDB, if that is the client passed to
+//     OpenCollection, or dynamodbiface.DynamoDBAPI
 //   - ActionList.BeforeDo: *dynamodb.BatchGetItemInput or *dynamodb.PutItemInput or *dynamodb.DeleteItemInput
-//     or *dynamodb.UpdateItemInput
-//   - Query.BeforeQuery: *dynamodb.QueryInput or *dynamodb.ScanInput
-//   - DocumentIterator: *dynamodb.QueryOutput or *dynamodb.ScanOutput
-//   - ErrorAs: awserr.Error
+//     or *dynamodb.UpdateItemInput; for atomic action lists, *dynamodb.TransactGetItemsInput
+//     or *dynamodb.TransactWriteItemsInput
+//   - Query.BeforeQuery: *dynamodb.QueryInput or *dynamodb.ScanInput; with
+//     Options.PartiQL, *dynamodb.ExecuteStatementInput
+//   - DocumentIterator: *dynamodb.QueryOutput or *dynamodb.ScanOutput; with
+//     Options.PartiQL, *dynamodb.ExecuteStatementOutput
+//   - Change: *dynamodbstreams.Record
+//   - ErrorAs: awserr.Error, or an exception of package dynamodb such as
+//     *dynamodb.ConditionalCheckFailedException or
+//     *dynamodb.TransactionCanceledException
 package awsdynamodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	streams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/attribute"
+	"gocloud.dev/callopt"
 	"gocloud.dev/docstore"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/retry"
+	"gocloud.dev/internal/telemetry"
 )
 
 // Set holds Wire providers for this package.
@@ -60,12 +207,13 @@ var Set = wire.NewSet(
 )
 
 type collection struct {
-	db           *dyn.DynamoDB
+	db           dynamodbiface.DynamoDBAPI
 	table        string // DynamoDB table name
 	partitionKey string
 	sortKey      string
 	description  *dyn.TableDescription
 	opts         *Options
+	throttle     throttle // with Options.AdaptiveRetry
 }
 
 // FallbackFunc is a function for executing queries that cannot be run by the built-in
@@ -82,6 +230,20 @@ type Options struct {
 	// Defaults to docstore.DefaultRevisionField.
 	RevisionField string
 
+	// If true, revisions are version numbers rather than unique strings, for
+	// tables whose optimistic locking uses a numeric version attribute. A
+	// created document has version 1, and each write of a document with a
+	// revision field increments its version: a put stores the version of the
+	// document plus one, and an update sets the field to its stored value
+	// plus one. The condition of a write compares the stored version to the
+	// version of the document, if it is not nil or zero. Versions are decoded
+	// as int64s.
+	//
+	// A Put of a document with a nil revision is not checked, so it stores
+	// version 1. An Update of such a document reads the new version back,
+	// except in an atomic action list, where its revision is left nil.
+	VersionRevisions bool
+
 	// If set, call this function on queries that we cannot execute at all (for
 	// example, a query with an OrderBy clause that lacks an equality filter on a
 	// partition key). The function should execute the query however it wishes, and
@@ -106,17 +268,149 @@ type Options struct {
 	// in such case, please check the official DynamoDB documentation for more
 	// details.
 	//
-	// The native client for DynamoDB uses this option in a per-action basis, if
-	// you need the flexibility to run both modes on the same collection, create
-	// two collections with different mode.
+	// The callopt.Consistency hint overrides it for the reads made with a
+	// context, such as the Gets of one action list.
 	ConsistentRead bool
+
+	// If true, Go sets, which are maps with string or numeric keys and
+	// struct{} elements like map[string]struct{}, are encoded as DynamoDB
+	// string and number sets. Otherwise they are encoded as DynamoDB maps.
+	EncodeSets bool
+
+	// The dot-separated paths of fields, like "Tags" or "Info.Aliases", whose
+	// list values are encoded as DynamoDB sets instead of lists. A list of
+	// strings is encoded as a string set, of numbers as a number set and of
+	// byte slices as a binary set; any other list is an error. Duplicate
+	// elements are dropped.
+	//
+	// DynamoDB sets cannot be empty, so empty Go sets and lists are encoded as
+	// null. Whatever the options, DynamoDB sets are decoded into Go slices,
+	// arrays and sets, and into interface{} values as []interface{}.
+	SetFields []string
+
+	// How time.Time values are stored. The default is TimeRFC3339. Numeric
+	// formats let times be compared in range queries like other numbers, and
+	// TimeUnixSeconds is the format DynamoDB requires of TTL attributes.
+	//
+	// Times are decoded from RFC 3339 strings whatever the format, so a
+	// collection can switch to a numeric format and still read existing
+	// documents. Strings are decoded as times only into time.Time values,
+	// never into interface{} values.
+	TimeFormat TimeFormat
+
+	// The name of a top-level field that holds the time at which a document
+	// expires, for DynamoDB Time to Live. Whatever the TimeFormat, a time.Time
+	// value of the field is stored as a number of seconds since the Unix
+	// epoch, as DynamoDB requires, and read back as a time.Time. A number is
+	// stored as it is, and must be in seconds. A nil value or the zero time
+	// means that the document does not expire, and is not stored.
+	//
+	// Use EnableTTL to turn on Time to Live for the table.
+	TTLField string
+
+	// The number of segments that queries that scan the table are split into,
+	// to be scanned in parallel. The iterator returns the documents of the
+	// segments interleaved, as they arrive. Zero or one means a single
+	// segment. The ScanSegmentsHint hint overrides it for a query.
+	//
+	// The Query.BeforeQuery callback is called concurrently for the segments.
+	ScanSegments int
+
+	// If true, empty strings are stored as NULL, as earlier versions of this
+	// package did, rather than as empty strings. Set it for collections whose
+	// existing documents or queries depend on the NULLs. Either way, NULL is
+	// decoded as the empty string into a string field.
+	NullEmptyStrings bool
+
+	// The consumed capacity that DynamoDB returns for each request:
+	// dynamodb.ReturnConsumedCapacityTotal, dynamodb.ReturnConsumedCapacityIndexes
+	// or, the default, dynamodb.ReturnConsumedCapacityNone. The
+	// ReturnConsumedCapacityHint hint overrides it for a call.
+	ReturnConsumedCapacity string
+
+	// If true, queries are run as PartiQL SELECT statements with
+	// ExecuteStatement, on the table or index that they would otherwise query
+	// or scan. A query with an "in" filter on the partition key, which would
+	// otherwise scan the table, reads the items of each value of the key.
+	// Pagination tokens and Options.ScanSegments are not supported for
+	// PartiQL queries, and Query.BeforeQuery is passed a
+	// *dynamodb.ExecuteStatementInput.
+	PartiQL bool
+
+	// The client that Collection.Watch reads the table's stream with. If nil,
+	// Watch makes one with the configuration of the table client, which must
+	// then be a *dynamodb.DynamoDB.
+	StreamsClient *streams.DynamoDBStreams
+
+	// The client for the calls about the table rather than its items:
+	// describing the table when the collection is opened, and before
+	// Collection.Watch. If nil, the client passed to OpenCollection is used.
+	// DAX does not serve these calls, so set it to a *dynamodb.DynamoDB when
+	// that client is a DAX client.
+	TableClient dynamodbiface.DynamoDBAPI
+
+	// How requests that fail are retried, in addition to the retries of the
+	// AWS SDK. Requests that DynamoDB throttles, such as with
+	// ProvisionedThroughputExceededException or ThrottlingException, are
+	// retried up to MaxAttempts times with exponential backoff and jitter,
+	// as are the other errors that IsRetryable accepts. If nil, the defaults
+	// of gcerrors.RetryPolicy are used. The callopt.RetryPolicy of a context
+	// overrides it for the requests made with the context. Set MaxAttempts
+	// to 1 to have only the AWS SDK retry.
+	RetryPolicy *gcerrors.RetryPolicy
+
+	// If true, the requests of the collection slow down together while
+	// DynamoDB throttles them: each throttled request lengthens a delay that
+	// all requests wait before they are sent, and each successful request
+	// shortens it, within the backoff bounds of the retry policy.
+	AdaptiveRetry bool
+
+	// If set, ConsumedCapacity is called with the capacity that DynamoDB
+	// returns for each request of an action list or query, and the context
+	// of the action list or query. It may be called concurrently.
+	ConsumedCapacity func(ctx context.Context, cc []*dyn.ConsumedCapacity)
 }
 
+// ScanSegmentsHint is the key of a gocloud.dev/callopt hint that sets the
+// number of segments of a query that scans the table, overriding
+// Options.ScanSegments. Its value is a decimal number, such as "8".
+const ScanSegmentsHint = "dynamodb_scan_segments"
+
+// ReturnConsumedCapacityHint is the key of a gocloud.dev/callopt hint that
+// sets the consumed capacity that DynamoDB returns for the requests of a call,
+// overriding Options.ReturnConsumedCapacity. Its value is one of
+// dynamodb.ReturnConsumedCapacityTotal, dynamodb.ReturnConsumedCapacityIndexes
+// or dynamodb.ReturnConsumedCapacityNone.
+const ReturnConsumedCapacityHint = "dynamodb_return_consumed_capacity"
+
+// TimeFormat is the format of the time.Time values stored in a collection.
+type TimeFormat int
+
+const (
+	// TimeRFC3339 stores times as strings in RFC 3339 format, with
+	// nanoseconds.
+	TimeRFC3339 TimeFormat = iota
+	// TimeUnixSeconds stores times as numbers of seconds since the Unix
+	// epoch, truncating any fraction of a second.
+	TimeUnixSeconds
+	// TimeUnixMillis stores times as numbers of milliseconds since the Unix
+	// epoch, truncating any fraction of a millisecond.
+	TimeUnixMillis
+	// TimeUnixNano stores times as numbers of nanoseconds since the Unix
+	// epoch.
+	TimeUnixNano
+)
+
 // RunQueryFunc is the type of the function passed to RunQueryFallback.
 type RunQueryFunc func(context.Context, *driver.Query) (driver.DocumentIterator, error)
 
 // OpenCollection creates a *docstore.Collection representing a DynamoDB collection.
-func OpenCollection(db *dyn.DynamoDB, tableName, partitionKey, sortKey string, opts *Options) (*docstore.Collection, error) {
+//
+// db is usually a *dynamodb.DynamoDB, but it can be any implementation of
+// dynamodbiface.DynamoDBAPI, such as a DynamoDB Accelerator (DAX) client, so
+// that the collection's reads go through the DAX cache. See
+// Options.TableClient for the calls that DAX does not serve.
+func OpenCollection(db dynamodbiface.DynamoDBAPI, tableName, partitionKey, sortKey string, opts *Options) (*docstore.Collection, error) {
 	c, err := newCollection(db, tableName, partitionKey, sortKey, opts)
 	if err != nil {
 		return nil, err
@@ -124,25 +418,35 @@ func OpenCollection(db *dyn.DynamoDB, tableName, partitionKey, sortKey string, o
 	return docstore.NewCollection(c), nil
 }
 
-func newCollection(db *dyn.DynamoDB, tableName, partitionKey, sortKey string, opts *Options) (*collection, error) {
-	out, err := db.DescribeTable(&dyn.DescribeTableInput{TableName: &tableName})
-	if err != nil {
-		return nil, err
-	}
+func newCollection(db dynamodbiface.DynamoDBAPI, tableName, partitionKey, sortKey string, opts *Options) (*collection, error) {
 	if opts == nil {
 		opts = &Options{}
 	}
 	if opts.RevisionField == "" {
 		opts.RevisionField = docstore.DefaultRevisionField
 	}
-	return &collection{
+	c := &collection{
 		db:           db,
 		table:        tableName,
 		partitionKey: partitionKey,
 		sortKey:      sortKey,
-		description:  out.Table,
 		opts:         opts,
-	}, nil
+	}
+	out, err := c.tableClient().DescribeTable(&dyn.DescribeTableInput{TableName: &tableName})
+	if err != nil {
+		return nil, err
+	}
+	c.description = out.Table
+	return c, nil
+}
+
+// tableClient returns the client for the calls about the table itself rather
+// than its items.
+func (c *collection) tableClient() dynamodbiface.DynamoDBAPI {
+	if c.opts.TableClient != nil {
+		return c.opts.TableClient
+	}
+	return c.db
 }
 
 // Key returns a two-element array with the partition key and sort key, if any.
@@ -172,6 +476,45 @@ func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, o
 	return driver.NewActionListError(errs)
 }
 
+// maxTransactionItems is the maximum number of items in a DynamoDB
+// transaction.
+const maxTransactionItems = 100
+
+// RunActionsAtomically implements driver.AtomicActionRunner. The writes are
+// made with a single TransactWriteItems call, and the Gets before and after
+// them with a TransactGetItems call each, so each group is limited to 100
+// actions. The writes and the Gets after them are only run if the Gets before
+// them could be made.
+func (c *collection) RunActionsAtomically(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	errs := make([]error, len(actions))
+	beforeGets, gets, writes, afterGets := driver.GroupActions(actions)
+	// The Gets of documents that are not written can be read before or after
+	// the writes; read them before, with the other Gets.
+	beforeGets = append(beforeGets, gets...)
+	for _, group := range [][]*driver.Action{beforeGets, writes, afterGets} {
+		if len(group) > maxTransactionItems {
+			err := gcerr.Newf(gcerr.InvalidArgument, nil, "a transaction can have at most %d reads and %d writes, got %d", maxTransactionItems, maxTransactionItems, len(group))
+			for _, a := range actions {
+				errs[a.Index] = err
+			}
+			return driver.NewActionListError(errs)
+		}
+	}
+	if err := c.transactGet(ctx, beforeGets, errs, opts); err != nil {
+		for _, group := range [][]*driver.Action{writes, afterGets} {
+			for _, a := range group {
+				errs[a.Index] = err
+			}
+		}
+		return driver.NewActionListError(errs)
+	}
+	if len(writes) > 0 {
+		c.transactWrite(ctx, writes, errs, opts, 0, len(writes)-1)
+	}
+	c.transactGet(ctx, afterGets, errs, opts)
+	return driver.NewActionListError(errs)
+}
+
 func (c *collection) runGets(ctx context.Context, actions []*driver.Action, errs []error, opts *driver.RunActionsOptions) {
 	const batchSize = 100
 	t := driver.NewThrottle(c.opts.MaxOutstandingActionRPCs)
@@ -197,49 +540,37 @@ func (c *collection) runGets(ctx context.Context, actions []*driver.Action, errs
 }
 
 func (c *collection) batchGet(ctx context.Context, gets []*driver.Action, errs []error, opts *driver.RunActionsOptions, start, end int) {
+	// done[i-start] reports whether gets[i] has a result: its document, or an
+	// error of its own.
+	done := make([]bool, end-start+1)
 	// errors need to be mapped to the actions' indices.
 	setErr := func(err error) {
 		for i := start; i <= end; i++ {
-			errs[gets[i].Index] = err
+			if !done[i-start] {
+				errs[gets[i].Index] = err
+			}
 		}
 	}
 
 	keys := make([]map[string]*dyn.AttributeValue, 0, end-start+1)
 	for i := start; i <= end; i++ {
-		av, err := encodeDocKeyFields(gets[i].Doc, c.partitionKey, c.sortKey)
+		av, err := encodeDocKeyFields(gets[i].Doc, c.partitionKey, c.sortKey, c.opts)
 		if err != nil {
 			errs[gets[i].Index] = err
+			done[i-start] = true
+			continue
 		}
-
 		keys = append(keys, av.M)
 	}
+	if len(keys) == 0 {
+		return
+	}
 	ka := &dyn.KeysAndAttributes{
 		Keys:           keys,
-		ConsistentRead: aws.Bool(c.opts.ConsistentRead),
+		ConsistentRead: aws.Bool(c.consistentRead(ctx)),
 	}
 	if len(gets[start].FieldPaths) != 0 {
-		// We need to add the key fields if the user doesn't include them. The
-		// BatchGet API doesn't return them otherwise.
-		var hasP, hasS bool
-		var nbs []expression.NameBuilder
-		for _, fp := range gets[start].FieldPaths {
-			p := strings.Join(fp, ".")
-			nbs = append(nbs, expression.Name(p))
-			if p == c.partitionKey {
-				hasP = true
-			} else if p == c.sortKey {
-				hasS = true
-			}
-		}
-		if !hasP {
-			nbs = append(nbs, expression.Name(c.partitionKey))
-		}
-		if c.sortKey != "" && !hasS {
-			nbs = append(nbs, expression.Name(c.sortKey))
-		}
-		expr, err := expression.NewBuilder().
-			WithProjection(expression.AddNames(expression.ProjectionBuilder{}, nbs...)).
-			Build()
+		expr, err := c.projection(gets[start].FieldPaths)
 		if err != nil {
 			setErr(err)
 			return
@@ -247,22 +578,30 @@ func (c *collection) batchGet(ctx context.Context, gets []*driver.Action, errs [
 		ka.ProjectionExpression = expr.Projection()
 		ka.ExpressionAttributeNames = expr.Names()
 	}
-	in := &dyn.BatchGetItemInput{RequestItems: map[string]*dyn.KeysAndAttributes{c.table: ka}}
+	in := &dyn.BatchGetItemInput{
+		RequestItems:           map[string]*dyn.KeysAndAttributes{c.table: ka},
+		ReturnConsumedCapacity: c.returnConsumedCapacity(ctx),
+	}
 	if opts.BeforeDo != nil {
 		if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
 			setErr(err)
 			return
 		}
 	}
-	out, err := c.db.BatchGetItemWithContext(ctx, in)
-	if err != nil {
-		setErr(err)
-		return
-	}
-	found := make([]bool, end-start+1)
 	am := mapActionIndices(gets, start, end)
-	for _, item := range out.Responses[c.table] {
-		if item != nil {
+	// DynamoDB may leave some keys unprocessed, for instance when the response
+	// would exceed 16MB or the table's capacity is exceeded. Retry them until
+	// there are none left.
+	err := c.call(ctx, "BatchGetItem", func() error {
+		out, err := c.db.BatchGetItemWithContext(ctx, in)
+		if err != nil {
+			return err
+		}
+		c.reportCapacity(ctx, out.ConsumedCapacity...)
+		for _, item := range out.Responses[c.table] {
+			if item == nil {
+				continue
+			}
 			key := map[string]interface{}{c.partitionKey: nil}
 			if c.sortKey != "" {
 				key[c.sortKey] = nil
@@ -271,7 +610,7 @@ func (c *collection) batchGet(ctx context.Context, gets []*driver.Action, errs [
 			if err != nil {
 				panic(err)
 			}
-			err = decodeDoc(&dyn.AttributeValue{M: item}, keysOnly)
+			err = decodeDoc(&dyn.AttributeValue{M: item}, keysOnly, c.opts)
 			if err != nil {
 				continue
 			}
@@ -279,18 +618,119 @@ func (c *collection) batchGet(ctx context.Context, gets []*driver.Action, errs [
 			if err != nil {
 				continue
 			}
-			i := am[decKey]
-			errs[gets[i].Index] = decodeDoc(&dyn.AttributeValue{M: item}, gets[i].Doc)
-			found[i-start] = true
+			i, ok := am[decKey]
+			if !ok {
+				continue
+			}
+			errs[gets[i].Index] = decodeDoc(&dyn.AttributeValue{M: item}, gets[i].Doc, c.opts)
+			done[i-start] = true
+		}
+		if uk := out.UnprocessedKeys[c.table]; uk != nil && len(uk.Keys) > 0 {
+			in = &dyn.BatchGetItemInput{
+				RequestItems:           map[string]*dyn.KeysAndAttributes{c.table: uk},
+				ReturnConsumedCapacity: in.ReturnConsumedCapacity,
+			}
+			return errUnprocessedKeys
 		}
+		return nil
+	})
+	if err != nil {
+		setErr(err)
+		return
 	}
-	for delta, f := range found {
-		if !f {
+	for delta, d := range done {
+		if !d {
 			errs[gets[start+delta].Index] = gcerr.Newf(gcerr.NotFound, nil, "item %v not found", gets[start+delta].Doc)
 		}
 	}
 }
 
+// projection returns an expression that projects the field paths fps and the
+// key fields. We need to add the key fields if the user doesn't include them:
+// the BatchGet API doesn't return them otherwise.
+func (c *collection) projection(fps [][]string) (expression.Expression, error) {
+	var hasP, hasS bool
+	var nbs []expression.NameBuilder
+	for _, fp := range fps {
+		p := strings.Join(fp, ".")
+		nbs = append(nbs, expression.Name(p))
+		if p == c.partitionKey {
+			hasP = true
+		} else if p == c.sortKey {
+			hasS = true
+		}
+	}
+	if !hasP {
+		nbs = append(nbs, expression.Name(c.partitionKey))
+	}
+	if c.sortKey != "" && !hasS {
+		nbs = append(nbs, expression.Name(c.sortKey))
+	}
+	return expression.NewBuilder().
+		WithProjection(expression.AddNames(expression.ProjectionBuilder{}, nbs...)).
+		Build()
+}
+
+// transactGet reads the documents of gets in a single transaction.
+// transactGet reads the documents of gets with a TransactGetItems call. It
+// sets the errors of the actions in errs, and returns the error of the call,
+// if it could not be made or failed.
+func (c *collection) transactGet(ctx context.Context, gets []*driver.Action, errs []error, opts *driver.RunActionsOptions) error {
+	if len(gets) == 0 {
+		return nil
+	}
+	setErr := func(err error) error {
+		for _, a := range gets {
+			errs[a.Index] = err
+		}
+		return err
+	}
+	tgs := make([]*dyn.TransactGetItem, len(gets))
+	for i, a := range gets {
+		av, err := encodeDocKeyFields(a.Doc, c.partitionKey, c.sortKey, c.opts)
+		if err != nil {
+			return setErr(err)
+		}
+		get := &dyn.Get{TableName: &c.table, Key: av.M}
+		if len(a.FieldPaths) != 0 {
+			expr, err := c.projection(a.FieldPaths)
+			if err != nil {
+				return setErr(err)
+			}
+			get.ProjectionExpression = expr.Projection()
+			get.ExpressionAttributeNames = expr.Names()
+		}
+		tgs[i] = &dyn.TransactGetItem{Get: get}
+	}
+	in := &dyn.TransactGetItemsInput{
+		TransactItems:          tgs,
+		ReturnConsumedCapacity: c.returnConsumedCapacity(ctx),
+	}
+	if opts.BeforeDo != nil {
+		if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
+			return setErr(err)
+		}
+	}
+	var out *dyn.TransactGetItemsOutput
+	err := c.call(ctx, "TransactGetItems", func() (err error) {
+		out, err = c.db.TransactGetItemsWithContext(ctx, in)
+		return err
+	})
+	if err != nil {
+		return setErr(err)
+	}
+	c.reportCapacity(ctx, out.ConsumedCapacity...)
+	// The responses are in the order of the items.
+	for i, a := range gets {
+		if i >= len(out.Responses) || out.Responses[i] == nil || out.Responses[i].Item == nil {
+			errs[a.Index] = gcerr.Newf(gcerr.NotFound, nil, "item %v not found", a.Doc)
+			continue
+		}
+		errs[a.Index] = decodeDoc(&dyn.AttributeValue{M: out.Responses[i].Item}, a.Doc, c.opts)
+	}
+	return nil
+}
+
 func mapActionIndices(actions []*driver.Action, start, end int) map[interface{}]int {
 	m := make(map[interface{}]int)
 	for i := start; i <= end; i++ {
@@ -299,6 +739,60 @@ func mapActionIndices(actions []*driver.Action, start, end int) map[interface{}]
 	return m
 }
 
+// consistentRead reports whether reads made with ctx are strongly
+// consistent, from the callopt.Consistency hint in ctx if there is one, or
+// else from Options.ConsistentRead.
+func (c *collection) consistentRead(ctx context.Context) bool {
+	switch callopt.Hint(ctx, callopt.Consistency) {
+	case callopt.Strong:
+		return true
+	case callopt.Eventual:
+		return false
+	}
+	return c.opts.ConsistentRead
+}
+
+// scanSegments returns the number of segments of a scan made with ctx, from
+// the ScanSegmentsHint hint in ctx if there is a valid one, or else from
+// Options.ScanSegments.
+func (c *collection) scanSegments(ctx context.Context) int {
+	if n, err := strconv.Atoi(callopt.Hint(ctx, ScanSegmentsHint)); err == nil && n > 0 {
+		return n
+	}
+	return c.opts.ScanSegments
+}
+
+// returnConsumedCapacity returns the ReturnConsumedCapacity parameter of the
+// requests made with ctx, from the ReturnConsumedCapacityHint hint in ctx if
+// there is one, or else from Options.ReturnConsumedCapacity.
+func (c *collection) returnConsumedCapacity(ctx context.Context) *string {
+	rcc := callopt.Hint(ctx, ReturnConsumedCapacityHint)
+	if rcc == "" {
+		rcc = c.opts.ReturnConsumedCapacity
+	}
+	if rcc == "" {
+		return nil
+	}
+	return aws.String(rcc)
+}
+
+// reportCapacity passes the capacity consumed by a request made with ctx to
+// the Options.ConsumedCapacity callback, if any.
+func (c *collection) reportCapacity(ctx context.Context, ccs ...*dyn.ConsumedCapacity) {
+	if c.opts.ConsumedCapacity == nil {
+		return
+	}
+	var nonNil []*dyn.ConsumedCapacity
+	for _, cc := range ccs {
+		if cc != nil {
+			nonNil = append(nonNil, cc)
+		}
+	}
+	if len(nonNil) > 0 {
+		c.opts.ConsumedCapacity(ctx, nonNil)
+	}
+}
+
 // runWrites executes all the writes as separate RPCs, concurrently.
 func (c *collection) runWrites(ctx context.Context, writes []*driver.Action, errs []error, opts *driver.RunActionsOptions) {
 	var ops []*writeOp
@@ -333,9 +827,9 @@ func (c *collection) runWrites(ctx context.Context, writes []*driver.Action, err
 // on its own, or included as part of a transaction.
 type writeOp struct {
 	action          *driver.Action
-	writeItem       *dyn.TransactWriteItem // for inclusion in a transaction
-	newPartitionKey string                 // for a Create on a document without a partition key
-	newRevision     string
+	writeItem       *dyn.TransactWriteItem      // for inclusion in a transaction
+	newPartitionKey string                      // for a Create on a document without a partition key
+	newRevision     interface{}                 // nil if the write does not set one
 	run             func(context.Context) error // run as a single RPC
 }
 
@@ -353,7 +847,7 @@ func (c *collection) newWriteOp(a *driver.Action, opts *driver.RunActionsOptions
 }
 
 func (c *collection) newPut(a *driver.Action, opts *driver.RunActionsOptions) (*writeOp, error) {
-	av, err := encodeDoc(a.Doc)
+	av, err := encodeDoc(a.Doc, c.opts)
 	if err != nil {
 		return nil, err
 	}
@@ -370,10 +864,12 @@ func (c *collection) newPut(a *driver.Action, opts *driver.RunActionsOptions) (*
 		// It doesn't make sense to generate a random sort key.
 		return nil, fmt.Errorf("missing sort key %q", c.sortKey)
 	}
-	var rev string
+	var rev interface{}
 	if a.Doc.HasField(c.opts.RevisionField) {
-		rev = driver.UniqueString()
-		if av.M[c.opts.RevisionField], err = encodeValue(rev); err != nil {
+		if rev, err = c.nextRevision(a.Doc); err != nil {
+			return nil, err
+		}
+		if av.M[c.opts.RevisionField], err = encodeValue(rev, c.opts); err != nil {
 			return nil, err
 		}
 	}
@@ -412,26 +908,40 @@ func (c *collection) runPut(ctx context.Context, dput *dyn.Put, a *driver.Action
 		ConditionExpression:       dput.ConditionExpression,
 		ExpressionAttributeNames:  dput.ExpressionAttributeNames,
 		ExpressionAttributeValues: dput.ExpressionAttributeValues,
+		ReturnConsumedCapacity:    c.returnConsumedCapacity(ctx),
 	}
 	if opts.BeforeDo != nil {
 		if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
 			return err
 		}
 	}
-	_, err := c.db.PutItemWithContext(ctx, in)
+	var out *dyn.PutItemOutput
+	err := c.call(ctx, "PutItem", func() (err error) {
+		out, err = c.db.PutItemWithContext(ctx, in)
+		return err
+	})
+	if err == nil {
+		c.reportCapacity(ctx, out.ConsumedCapacity)
+	}
 	if ae, ok := err.(awserr.Error); ok && ae.Code() == dyn.ErrCodeConditionalCheckFailedException {
-		if a.Kind == driver.Create {
-			err = gcerr.Newf(gcerr.AlreadyExists, err, "document already exists")
-		}
-		if rev, _ := a.Doc.GetField(c.opts.RevisionField); rev == nil && a.Kind == driver.Replace {
-			err = gcerr.Newf(gcerr.NotFound, nil, "document not found")
-		}
+		err = c.conditionFailed(a, err)
+	}
+	return err
+}
+
+// conditionFailed returns the error for a, whose precondition failed with err.
+func (c *collection) conditionFailed(a *driver.Action, err error) error {
+	if a.Kind == driver.Create {
+		err = gcerr.Newf(gcerr.AlreadyExists, err, "document already exists")
+	}
+	if rev, _ := a.Doc.GetField(c.opts.RevisionField); rev == nil && a.Kind == driver.Replace {
+		err = gcerr.Newf(gcerr.NotFound, nil, "document not found")
 	}
 	return err
 }
 
 func (c *collection) newDelete(a *driver.Action, opts *driver.RunActionsOptions) (*writeOp, error) {
-	av, err := encodeDocKeyFields(a.Doc, c.partitionKey, c.sortKey)
+	av, err := encodeDocKeyFields(a.Doc, c.partitionKey, c.sortKey, c.opts)
 	if err != nil {
 		return nil, err
 	}
@@ -462,39 +972,59 @@ func (c *collection) newDelete(a *driver.Action, opts *driver.RunActionsOptions)
 				ConditionExpression:       del.ConditionExpression,
 				ExpressionAttributeNames:  del.ExpressionAttributeNames,
 				ExpressionAttributeValues: del.ExpressionAttributeValues,
+				ReturnConsumedCapacity:    c.returnConsumedCapacity(ctx),
 			}
 			if opts.BeforeDo != nil {
 				if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
 					return err
 				}
 			}
-			_, err := c.db.DeleteItemWithContext(ctx, in)
-			return err
+			var out *dyn.DeleteItemOutput
+			err := c.call(ctx, "DeleteItem", func() (err error) {
+				out, err = c.db.DeleteItemWithContext(ctx, in)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			c.reportCapacity(ctx, out.ConsumedCapacity)
+			return nil
 		},
 	}, nil
 }
 
 func (c *collection) newUpdate(a *driver.Action, opts *driver.RunActionsOptions) (*writeOp, error) {
-	av, err := encodeDocKeyFields(a.Doc, c.partitionKey, c.sortKey)
+	av, err := encodeDocKeyFields(a.Doc, c.partitionKey, c.sortKey, c.opts)
 	if err != nil {
 		return nil, err
 	}
-	var ub expression.UpdateBuilder
-	for _, m := range a.Mods {
-		// TODO(shantuo): check for invalid field paths
-		fp := expression.Name(strings.Join(m.FieldPath, "."))
-		if inc, ok := m.Value.(driver.IncOp); ok {
-			ub = ub.Add(fp, expression.Value(inc.Amount))
-		} else if m.Value == nil {
-			ub = ub.Remove(fp)
-		} else {
-			ub = ub.Set(fp, expression.Value(m.Value))
-		}
+	ub, err := c.updateBuilder(a.Mods)
+	if err != nil {
+		return nil, err
 	}
-	var rev string
+	var rev interface{}
+	// With version revisions, whether the new version must be read back from
+	// DynamoDB, because the document does not have the stored version.
+	var readVersion bool
 	if a.Doc.HasField(c.opts.RevisionField) {
-		rev = driver.UniqueString()
-		ub = ub.Set(expression.Name(c.opts.RevisionField), expression.Value(rev))
+		name := expression.Name(c.opts.RevisionField)
+		if c.opts.VersionRevisions {
+			n, err := docVersion(a.Doc, c.opts.RevisionField)
+			if err != nil {
+				return nil, err
+			}
+			if n > 0 {
+				// The precondition ensures that the stored version is n.
+				ub = ub.Set(name, name.Plus(expression.Value(1)))
+				rev = n + 1
+			} else {
+				ub = ub.Set(name, expression.Plus(name.IfNotExists(expression.Value(0)), expression.Value(1)))
+				readVersion = true
+			}
+		} else {
+			rev = driver.UniqueString()
+			ub = ub.Set(name, expression.Value(rev))
+		}
 	}
 	cb, err := c.precondition(a)
 	if err != nil {
@@ -512,28 +1042,50 @@ func (c *collection) newUpdate(a *driver.Action, opts *driver.RunActionsOptions)
 		ExpressionAttributeNames:  ce.Names(),
 		ExpressionAttributeValues: ce.Values(),
 	}
-	return &writeOp{
+	op := &writeOp{
 		action:      a,
 		writeItem:   &dyn.TransactWriteItem{Update: up},
 		newRevision: rev,
-		run: func(ctx context.Context) error {
-			in := &dyn.UpdateItemInput{
-				TableName:                 up.TableName,
-				Key:                       up.Key,
-				ConditionExpression:       up.ConditionExpression,
-				UpdateExpression:          up.UpdateExpression,
-				ExpressionAttributeNames:  up.ExpressionAttributeNames,
-				ExpressionAttributeValues: up.ExpressionAttributeValues,
+	}
+	op.run = func(ctx context.Context) error {
+		in := &dyn.UpdateItemInput{
+			TableName:                 up.TableName,
+			Key:                       up.Key,
+			ConditionExpression:       up.ConditionExpression,
+			UpdateExpression:          up.UpdateExpression,
+			ExpressionAttributeNames:  up.ExpressionAttributeNames,
+			ExpressionAttributeValues: up.ExpressionAttributeValues,
+			ReturnConsumedCapacity:    c.returnConsumedCapacity(ctx),
+		}
+		if readVersion {
+			in.ReturnValues = aws.String(dyn.ReturnValueUpdatedNew)
+		}
+		if opts.BeforeDo != nil {
+			if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
+				return err
 			}
-			if opts.BeforeDo != nil {
-				if err := opts.BeforeDo(driver.AsFunc(in)); err != nil {
+		}
+		var out *dyn.UpdateItemOutput
+		err := c.call(ctx, "UpdateItem", func() (err error) {
+			out, err = c.db.UpdateItemWithContext(ctx, in)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		c.reportCapacity(ctx, out.ConsumedCapacity)
+		if readVersion {
+			if av := out.Attributes[c.opts.RevisionField]; av != nil && av.N != nil {
+				n, err := strconv.ParseInt(*av.N, 10, 64)
+				if err != nil {
 					return err
 				}
+				op.newRevision = n
 			}
-			_, err := c.db.UpdateItemWithContext(ctx, in)
-			return err
-		},
-	}, nil
+		}
+		return nil
+	}
+	return op, nil
 }
 
 // Handle the effects of successful execution.
@@ -542,22 +1094,28 @@ func (c *collection) onSuccess(op *writeOp) error {
 	if op.newPartitionKey != "" {
 		_ = op.action.Doc.SetField(c.partitionKey, op.newPartitionKey) // cannot fail
 	}
-	if op.newRevision != "" {
+	if op.newRevision != nil {
 		return op.action.Doc.SetField(c.opts.RevisionField, op.newRevision)
 	}
 	return nil
 }
 
 func (c *collection) missingKeyField(m map[string]*dyn.AttributeValue) string {
-	if v, ok := m[c.partitionKey]; !ok || v.NULL != nil {
+	if v, ok := m[c.partitionKey]; !ok || isEmptyKey(v) {
 		return c.partitionKey
 	}
-	if v, ok := m[c.sortKey]; (!ok || v.NULL != nil) && c.sortKey != "" {
+	if v, ok := m[c.sortKey]; (!ok || isEmptyKey(v)) && c.sortKey != "" {
 		return c.sortKey
 	}
 	return ""
 }
 
+// isEmptyKey reports whether v is the encoding of an empty key: NULL, or an
+// empty string unless Options.NullEmptyStrings made that NULL already.
+func isEmptyKey(v *dyn.AttributeValue) bool {
+	return v.NULL != nil || (v.S != nil && *v.S == "")
+}
+
 // Construct the precondition for the action.
 func (c *collection) precondition(a *driver.Action) (*expression.ConditionBuilder, error) {
 	switch a.Kind {
@@ -569,7 +1127,7 @@ func (c *collection) precondition(a *driver.Action) (*expression.ConditionBuilde
 	case driver.Replace, driver.Update:
 		// Precondition: the revision matches, or if there is no revision, then
 		// the document exists.
-		cb, err := revisionPrecondition(a.Doc, c.opts.RevisionField)
+		cb, err := c.revisionPrecondition(a.Doc)
 		if err != nil {
 			return nil, err
 		}
@@ -580,7 +1138,7 @@ func (c *collection) precondition(a *driver.Action) (*expression.ConditionBuilde
 		return cb, nil
 	case driver.Put, driver.Delete:
 		// Precondition: the revision matches, if any.
-		return revisionPrecondition(a.Doc, c.opts.RevisionField)
+		return c.revisionPrecondition(a.Doc)
 	case driver.Get:
 		// No preconditions on a Get.
 		return nil, nil
@@ -591,7 +1149,16 @@ func (c *collection) precondition(a *driver.Action) (*expression.ConditionBuilde
 
 // revisionPrecondition returns a DynamoDB expression that asserts that the
 // stored document's revision matches the revision of doc.
-func revisionPrecondition(doc driver.Document, revField string) (*expression.ConditionBuilder, error) {
+func (c *collection) revisionPrecondition(doc driver.Document) (*expression.ConditionBuilder, error) {
+	revField := c.opts.RevisionField
+	if c.opts.VersionRevisions {
+		n, err := docVersion(doc, revField)
+		if err != nil || n == 0 {
+			return nil, err
+		}
+		cb := expression.Name(revField).Equal(expression.Value(n))
+		return &cb, nil
+	}
 	v, err := doc.GetField(revField)
 	if err != nil { // field not present
 		return nil, nil
@@ -613,7 +1180,52 @@ func revisionPrecondition(doc driver.Document, revField string) (*expression.Con
 	return &cb, nil
 }
 
-// TODO(jba): use this if/when we support atomic writes.
+// nextRevision returns the revision that a put of doc stores: a new unique
+// string, or with Options.VersionRevisions, the version of doc plus one.
+func (c *collection) nextRevision(doc driver.Document) (interface{}, error) {
+	if !c.opts.VersionRevisions {
+		return driver.UniqueString(), nil
+	}
+	n, err := docVersion(doc, c.opts.RevisionField)
+	if err != nil {
+		return nil, err
+	}
+	return n + 1, nil
+}
+
+// docVersion returns the version number in the revision field of doc, or 0
+// if the field is absent or nil.
+func docVersion(doc driver.Document, revField string) (int64, error) {
+	v, err := doc.GetField(revField)
+	if err != nil || v == nil {
+		return 0, nil
+	}
+	n, ok := versionNumber(v)
+	if !ok || n < 0 {
+		return 0, gcerr.Newf(gcerr.InvalidArgument, nil,
+			"%s field contains wrong type or value: got %v of type %[2]T, want a non-negative integer",
+			revField, v)
+	}
+	return n, nil
+}
+
+// versionNumber converts v, an integer or an integral float, to an int64.
+func versionNumber(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		return int64(f), f == float64(int64(f))
+	}
+	return 0, false
+}
+
+// transactWrite makes the writes of actions[start:end+1] in a single
+// transaction.
 func (c *collection) transactWrite(ctx context.Context, actions []*driver.Action, errs []error, opts *driver.RunActionsOptions, start, end int) {
 	setErr := func(err error) {
 		for i := start; i <= end; i++ {
@@ -635,8 +1247,9 @@ func (c *collection) transactWrite(ctx context.Context, actions []*driver.Action
 	}
 
 	in := &dyn.TransactWriteItemsInput{
-		ClientRequestToken: aws.String(driver.UniqueString()),
-		TransactItems:      tws,
+		ClientRequestToken:     aws.String(driver.UniqueString()),
+		TransactItems:          tws,
+		ReturnConsumedCapacity: c.returnConsumedCapacity(ctx),
 	}
 
 	if opts.BeforeDo != nil {
@@ -653,10 +1266,33 @@ func (c *collection) transactWrite(ctx context.Context, actions []*driver.Action
 			return
 		}
 	}
-	if _, err := c.db.TransactWriteItemsWithContext(ctx, in); err != nil {
-		setErr(err)
+	var out *dyn.TransactWriteItemsOutput
+	err := c.call(ctx, "TransactWriteItems", func() (err error) {
+		out, err = c.db.TransactWriteItemsWithContext(ctx, in)
+		return err
+	})
+	if err != nil {
+		tce, ok := err.(*dyn.TransactionCanceledException)
+		if !ok || len(tce.CancellationReasons) != len(ops) {
+			setErr(err)
+			return
+		}
+		// Attribute the cancellation to the actions that caused it.
+		for i, op := range ops {
+			var aerr error
+			switch aws.StringValue(tce.CancellationReasons[i].Code) {
+			case "None":
+				aerr = gcerr.Newf(gcerr.FailedPrecondition, err, "transaction canceled because another write failed")
+			case "ConditionalCheckFailed":
+				aerr = c.conditionFailed(op.action, err)
+			default:
+				aerr = err
+			}
+			errs[op.action.Index] = aerr
+		}
 		return
 	}
+	c.reportCapacity(ctx, out.ConsumedCapacity...)
 	for _, op := range ops {
 		errs[op.action.Index] = c.onSuccess(op)
 	}
@@ -664,6 +1300,13 @@ func (c *collection) transactWrite(ctx context.Context, actions []*driver.Action
 
 // RevisionToBytes implements driver.RevisionToBytes.
 func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	if c.opts.VersionRevisions {
+		n, ok := versionNumber(rev)
+		if !ok {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "revision %v of type %[1]T is not an integer", rev)
+		}
+		return strconv.AppendInt(nil, n, 10), nil
+	}
 	s, ok := rev.(string)
 	if !ok {
 		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "revision %v of type %[1]T is not a string", rev)
@@ -673,32 +1316,70 @@ func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
 
 // BytesToRevision implements driver.BytesToRevision.
 func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	if c.opts.VersionRevisions {
+		n, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, err, "invalid version revision %q", b)
+		}
+		return n, nil
+	}
 	return string(b), nil
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (c *collection) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.DBSystemKey.String("dynamodb"),
+		telemetry.DBCollectionNameKey.String(c.table),
+	}
+}
+
 func (c *collection) As(i interface{}) bool {
-	p, ok := i.(**dyn.DynamoDB)
-	if !ok {
+	switch p := i.(type) {
+	case **dyn.DynamoDB:
+		db, ok := c.db.(*dyn.DynamoDB)
+		if !ok {
+			return false
+		}
+		*p = db
+	case *dynamodbiface.DynamoDBAPI:
+		*p = c.db
+	case **collection:
+		// For RunPartiQL and RunPartiQLBatch.
+		*p = c
+	default:
 		return false
 	}
-	*p = c.db
 	return true
 }
 
-// ErrorAs implements driver.Collection.ErrorAs.
+// ErrorAs implements driver.Collection.ErrorAs. Besides awserr.Error, it
+// converts i to the exception types of package dynamodb, such as
+// *dynamodb.ConditionalCheckFailedException, including for the last error of
+// a request whose retries ended with the context.
 func (c *collection) ErrorAs(err error, i interface{}) bool {
-	e, ok := err.(awserr.Error)
-	if !ok {
+	if ce, ok := err.(*retry.ContextError); ok {
+		err = ce.FuncErr
+	}
+	if err == nil {
 		return false
 	}
-	p, ok := i.(*awserr.Error)
-	if !ok {
+	if p, ok := i.(*awserr.Error); ok {
+		e, ok := err.(awserr.Error)
+		if ok {
+			*p = e
+		}
+		return ok
+	}
+	// errors.As panics on targets that cannot hold an error.
+	if t := reflect.TypeOf(i).Elem(); t.Kind() != reflect.Interface && !t.Implements(errorType) {
 		return false
 	}
-	*p = e
-	return true
+	return errors.As(err, i)
 }
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
 	ae, ok := err.(awserr.Error)
 	if !ok {
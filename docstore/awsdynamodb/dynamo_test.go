@@ -16,25 +16,42 @@ package awsdynamodb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/callopt"
 	"gocloud.dev/docstore"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/docstore/drivertest"
 	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/retry"
 	"gocloud.dev/internal/testing/setup"
+	"gocloud.dev/testing/emulators"
 )
 
 // To create the tables and indexes needed for these tests, run create_tables.sh in
 // this directory.
 //
+// Run TestConformance with -emulators to run it against DynamoDB Local in
+// Docker, with the same tables, instead of replaying the recorded
+// interactions.
+//
 // The docstore-test-2 table is set up to work with queries on the drivertest.HighScore
 // struct like so:
 //   table:        "Game" partition key, "Player" sort key
@@ -182,11 +199,80 @@ func (v verifyAs) ErrorCheck(k *docstore.Collection, err error) error {
 	return nil
 }
 
+// newEmulatorHarness starts DynamoDB Local for t, creates the tables of
+// create_tables.sh in it, and returns a HarnessMaker whose harnesses use it.
+func newEmulatorHarness(t *testing.T) drivertest.HarnessMaker {
+	ctx := context.Background()
+	local := emulators.StartDynamoDB(ctx, t)
+	sess, err := local.Session()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := createTables(ctx, dyn.New(sess)); err != nil {
+		t.Fatal(err)
+	}
+	return func(context.Context, *testing.T) (drivertest.Harness, error) {
+		return &harness{sess: sess, closer: func() {}}, nil
+	}
+}
+
+// createTables creates the tables and indexes that create_tables.sh creates.
+func createTables(ctx context.Context, db *dyn.DynamoDB) error {
+	attr := func(name, typ string) *dyn.AttributeDefinition {
+		return &dyn.AttributeDefinition{AttributeName: aws.String(name), AttributeType: aws.String(typ)}
+	}
+	key := func(name, typ string) *dyn.KeySchemaElement {
+		return &dyn.KeySchemaElement{AttributeName: aws.String(name), KeyType: aws.String(typ)}
+	}
+	all := &dyn.Projection{ProjectionType: aws.String(dyn.ProjectionTypeAll)}
+	tables := []*dyn.CreateTableInput{
+		{
+			TableName:            aws.String(collectionName1),
+			AttributeDefinitions: []*dyn.AttributeDefinition{attr(drivertest.KeyField, dyn.ScalarAttributeTypeS)},
+			KeySchema:            []*dyn.KeySchemaElement{key(drivertest.KeyField, dyn.KeyTypeHash)},
+		},
+		{
+			TableName: aws.String(collectionName2),
+			AttributeDefinitions: []*dyn.AttributeDefinition{
+				attr("Game", dyn.ScalarAttributeTypeS),
+				attr("Player", dyn.ScalarAttributeTypeS),
+				attr("Score", dyn.ScalarAttributeTypeN),
+				attr("Time", dyn.ScalarAttributeTypeS),
+			},
+			KeySchema: []*dyn.KeySchemaElement{key("Game", dyn.KeyTypeHash), key("Player", dyn.KeyTypeRange)},
+			LocalSecondaryIndexes: []*dyn.LocalSecondaryIndex{{
+				IndexName:  aws.String("local"),
+				KeySchema:  []*dyn.KeySchemaElement{key("Game", dyn.KeyTypeHash), key("Score", dyn.KeyTypeRange)},
+				Projection: all,
+			}},
+			GlobalSecondaryIndexes: []*dyn.GlobalSecondaryIndex{{
+				IndexName:  aws.String("global"),
+				KeySchema:  []*dyn.KeySchemaElement{key("Player", dyn.KeyTypeHash), key("Time", dyn.KeyTypeRange)},
+				Projection: all,
+			}},
+		},
+	}
+	for _, in := range tables {
+		in.BillingMode = aws.String(dyn.BillingModePayPerRequest)
+		if _, err := db.CreateTableWithContext(ctx, in); err != nil {
+			return err
+		}
+		if err := db.WaitUntilTableExistsWithContext(ctx, &dyn.DescribeTableInput{TableName: in.TableName}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func TestConformance(t *testing.T) {
+	maker := newHarness
+	if *setup.Emulators {
+		maker = newEmulatorHarness(t)
+	}
 	// Note: when running -record repeatedly in a short time period, change the argument
 	// in the call below to generate unique transaction tokens.
 	drivertest.MakeUniqueStringDeterministicForTesting(1)
-	drivertest.RunConformanceTests(t, newHarness, &codecTester{}, []drivertest.AsTest{verifyAs{}})
+	drivertest.RunConformanceTests(t, maker, &codecTester{}, []drivertest.AsTest{verifyAs{}})
 }
 
 func BenchmarkConformance(b *testing.B) {
@@ -227,3 +313,657 @@ func TestQueryErrors(t *testing.T) {
 		t.Errorf("got %v (code %s, type %T), want InvalidArgument", err, c, err)
 	}
 }
+
+func TestConsistentRead(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		opt  bool
+		hint string
+		want bool
+	}{
+		{false, "", false},
+		{true, "", true},
+		{false, callopt.Strong, true},
+		{true, callopt.Eventual, false},
+		{true, "linearizable", true},
+	} {
+		c := &collection{opts: &Options{ConsistentRead: test.opt}}
+		hctx := ctx
+		if test.hint != "" {
+			hctx = callopt.WithHint(ctx, callopt.Consistency, test.hint)
+		}
+		if got := c.consistentRead(hctx); got != test.want {
+			t.Errorf("ConsistentRead %t, hint %q: got %t, want %t", test.opt, test.hint, got, test.want)
+		}
+	}
+}
+
+func TestAtomicActionsLimit(t *testing.T) {
+	type doc struct{ ID string }
+	c := &collection{partitionKey: "ID", opts: &Options{}}
+	var actions []*driver.Action
+	for i := 0; i <= maxTransactionItems; i++ {
+		actions = append(actions, &driver.Action{
+			Kind:  driver.Put,
+			Doc:   drivertest.MustDocument(&doc{ID: fmt.Sprint(i)}),
+			Key:   fmt.Sprint(i),
+			Index: i,
+		})
+	}
+	errs := c.RunActionsAtomically(context.Background(), actions, &driver.RunActionsOptions{})
+	if len(errs) != len(actions) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(actions))
+	}
+	if c := gcerrors.Code(errs[0].Err); c != gcerrors.InvalidArgument {
+		t.Errorf("got %v (code %s), want InvalidArgument", errs[0].Err, c)
+	}
+}
+
+func TestAtomicActionsGetsFail(t *testing.T) {
+	// The fake service fails TransactGetItems, and records the other calls.
+	var ops []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := r.Header.Get("X-Amz-Target")
+		ops = append(ops, op)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if strings.HasSuffix(op, ".TransactGetItems") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"__type":  "com.amazon.coral.validate#ValidationException",
+				"message": "fake failure",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collection{db: dyn.New(sess), table: "t", partitionKey: "ID", opts: &Options{}}
+
+	type doc struct{ ID, Name string }
+	var actions []*driver.Action
+	for i, k := range []driver.ActionKind{driver.Get, driver.Put, driver.Get} {
+		d := drivertest.MustDocument(&doc{ID: fmt.Sprint(i)})
+		key, err := c.Key(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actions = append(actions, &driver.Action{Kind: k, Doc: d, Key: key, Index: i})
+	}
+	errs := c.RunActionsAtomically(context.Background(), actions, &driver.RunActionsOptions{})
+	if len(errs) != len(actions) {
+		t.Fatalf("got %d errors, want %d: %v", len(errs), len(actions), errs)
+	}
+	for _, op := range ops {
+		if strings.HasSuffix(op, ".TransactWriteItems") {
+			t.Errorf("got a %s call after the Gets failed", op)
+		}
+	}
+	if len(ops) != 1 {
+		t.Errorf("got calls %v, want only the first TransactGetItems", ops)
+	}
+}
+
+func TestBatchGetUnprocessedKeys(t *testing.T) {
+	// The fake service returns one item per call, leaving the other keys
+	// unprocessed.
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var in struct {
+			RequestItems map[string]struct {
+				Keys []map[string]map[string]string
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		keys := in.RequestItems["t"].Keys
+		out := map[string]interface{}{
+			"Responses": map[string]interface{}{
+				"t": []interface{}{map[string]interface{}{
+					"ID":   keys[0]["ID"],
+					"Name": map[string]string{"S": "name-" + keys[0]["ID"]["S"]},
+				}},
+			},
+		}
+		if len(keys) > 1 {
+			out["UnprocessedKeys"] = map[string]interface{}{"t": map[string]interface{}{"Keys": keys[1:]}}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collection{db: dyn.New(sess), table: "t", partitionKey: "ID", opts: &Options{}}
+
+	type doc struct{ ID, Name string }
+	docs := []*doc{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	var actions []*driver.Action
+	for i, d := range docs {
+		doc := drivertest.MustDocument(d)
+		key, err := c.Key(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actions = append(actions, &driver.Action{Kind: driver.Get, Doc: doc, Key: key, Index: i})
+	}
+	errs := make([]error, len(actions))
+	c.batchGet(context.Background(), actions, errs, &driver.RunActionsOptions{}, 0, len(actions)-1)
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("%s: %v", docs[i].ID, err)
+		}
+	}
+	for _, d := range docs {
+		if want := "name-" + d.ID; d.Name != want {
+			t.Errorf("got name %q, want %q", d.Name, want)
+		}
+	}
+	if calls != len(docs) {
+		t.Errorf("got %d calls, want %d", calls, len(docs))
+	}
+}
+
+func TestParallelScan(t *testing.T) {
+	// The fake service returns two pages of one item for each segment.
+	var mu sync.Mutex
+	segments := map[int]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			Segment, TotalSegments int
+			ExclusiveStartKey      map[string]map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		segments[in.Segment] = true
+		mu.Unlock()
+		page := 0
+		if in.ExclusiveStartKey != nil {
+			page = 1
+		}
+		id := fmt.Sprintf("%d-%d", in.Segment, page)
+		out := map[string]interface{}{
+			"Items": []interface{}{map[string]interface{}{"ID": map[string]string{"S": id}}},
+		}
+		if page == 0 {
+			out["LastEvaluatedKey"] = map[string]interface{}{"ID": map[string]string{"S": id}}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collection{
+		db:           dyn.New(sess),
+		table:        "t",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{AllowScans: true, ScanSegments: 2},
+	}
+	ctx := callopt.WithHint(context.Background(), ScanSegmentsHint, "3")
+	iter, err := c.RunGetQuery(ctx, &driver.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iter.Stop()
+	var got []string
+	for {
+		m := map[string]interface{}{}
+		err := iter.Next(ctx, drivertest.MustDocument(m))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m["ID"].(string))
+	}
+	sort.Strings(got)
+	want := []string{"0-0", "0-1", "1-0", "1-1", "2-0", "2-1"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(segments) != 3 {
+		t.Errorf("got segments %v, want 3", segments)
+	}
+}
+
+func TestPaginationToken(t *testing.T) {
+	// The fake service scans the items "a" through "e", two per page.
+	ids := []string{"a", "b", "c", "d", "e"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			ExclusiveStartKey map[string]map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start := 0
+		if k := in.ExclusiveStartKey; k != nil {
+			start = sort.SearchStrings(ids, k["ID"]["S"]) + 1
+		}
+		end := start + 2
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var items []interface{}
+		for _, id := range ids[start:end] {
+			items = append(items, map[string]interface{}{"ID": map[string]string{"S": id}})
+		}
+		out := map[string]interface{}{"Items": items}
+		if end < len(ids) {
+			out["LastEvaluatedKey"] = map[string]interface{}{"ID": map[string]string{"S": ids[end-1]}}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &collection{
+		db:           dyn.New(sess),
+		table:        "t",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{AllowScans: true},
+	}
+	ctx := context.Background()
+
+	// read runs the query from token and returns the IDs of its first n
+	// documents (all if n is negative) and the token after them.
+	read := func(token []byte, n int) ([]string, []byte) {
+		t.Helper()
+		iter, err := c.RunGetQuery(ctx, &driver.Query{PaginationToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer iter.Stop()
+		var got []string
+		for n < 0 || len(got) < n {
+			m := map[string]interface{}{}
+			err := iter.Next(ctx, drivertest.MustDocument(m))
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, m["ID"].(string))
+		}
+		tok, err := iter.(driver.PaginatedIterator).PaginationToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got, tok
+	}
+
+	// Stop in the middle of the second page, and right after it.
+	got, tok := read(nil, 3)
+	if want := []string{"a", "b", "c"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got, tok = read(tok, 1)
+	if want := []string{"d"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// A token taken before reading anything resumes at the same place.
+	got, tok = read(tok, 0)
+	if len(got) != 0 {
+		t.Errorf("got %v, want none", got)
+	}
+	got, tok = read(tok, -1)
+	if want := []string{"e"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if tok != nil {
+		t.Errorf("got token %s at the end, want nil", tok)
+	}
+
+	if _, err := c.RunGetQuery(ctx, &driver.Query{PaginationToken: []byte("{")}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("invalid token: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestConsumedCapacity(t *testing.T) {
+	// The fake service returns the ReturnConsumedCapacity of each request as
+	// the table name of its consumed capacity.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct{ ReturnConsumedCapacity string }
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		out := map[string]interface{}{}
+		if in.ReturnConsumedCapacity != "" {
+			cc := map[string]interface{}{"TableName": in.ReturnConsumedCapacity, "CapacityUnits": 1}
+			if strings.HasSuffix(r.Header.Get("X-Amz-Target"), ".BatchGetItem") {
+				out["ConsumedCapacity"] = []interface{}{cc}
+			} else {
+				out["ConsumedCapacity"] = cc
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type ctxKey struct{}
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	c := &collection{
+		db:           dyn.New(sess),
+		table:        "t",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts: &Options{
+			AllowScans:             true,
+			RevisionField:          docstore.DefaultRevisionField,
+			ReturnConsumedCapacity: dyn.ReturnConsumedCapacityTotal,
+			ConsumedCapacity: func(ctx context.Context, ccs []*dyn.ConsumedCapacity) {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, cc := range ccs {
+					got = append(got, fmt.Sprintf("%v:%s", ctx.Value(ctxKey{}), aws.StringValue(cc.TableName)))
+				}
+			},
+		},
+	}
+	coll := docstore.NewCollection(c)
+	defer coll.Close()
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "put")
+	if err := coll.Put(ctx, map[string]interface{}{"ID": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx = callopt.WithHint(context.WithValue(context.Background(), ctxKey{}, "get"), ReturnConsumedCapacityHint, dyn.ReturnConsumedCapacityIndexes)
+	if err := coll.Get(ctx, map[string]interface{}{"ID": "a"}); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Fatalf("got %v, want NotFound", err)
+	}
+	ctx = context.WithValue(context.Background(), ctxKey{}, "query")
+	iter := coll.Query().Get(ctx)
+	if err := iter.Next(ctx, map[string]interface{}{}); err != io.EOF {
+		t.Fatalf("got %v, want io.EOF", err)
+	}
+	var out *dyn.ScanOutput
+	if !iter.As(&out) || out.ConsumedCapacity == nil {
+		t.Errorf("As: got %v, want a ScanOutput with consumed capacity", out)
+	}
+	iter.Stop()
+
+	want := []string{"put:TOTAL", "get:INDEXES", "query:TOTAL"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// cacheClient is a DAX-like client that serves item reads from a cache, and
+// no calls about the table.
+type cacheClient struct {
+	dynamodbiface.DynamoDBAPI // nil: other calls panic
+	items                     map[string]map[string]*dyn.AttributeValue
+}
+
+func (c *cacheClient) BatchGetItemWithContext(_ aws.Context, in *dyn.BatchGetItemInput, _ ...request.Option) (*dyn.BatchGetItemOutput, error) {
+	out := &dyn.BatchGetItemOutput{Responses: map[string][]map[string]*dyn.AttributeValue{}}
+	for table, ka := range in.RequestItems {
+		for _, k := range ka.Keys {
+			if item, ok := c.items[aws.StringValue(k["ID"].S)]; ok {
+				out.Responses[table] = append(out.Responses[table], item)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestDAXClient(t *testing.T) {
+	// The table client describes the table.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.Header.Get("X-Amz-Target"), ".DescribeTable") {
+			http.Error(w, "unexpected call", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(map[string]interface{}{"Table": map[string]interface{}{"TableName": "t"}})
+	}))
+	defer srv.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := &cacheClient{items: map[string]map[string]*dyn.AttributeValue{
+		"a": {"ID": new(dyn.AttributeValue).SetS("a"), "X": new(dyn.AttributeValue).SetN("1")},
+	}}
+	coll, err := OpenCollection(cache, "t", "ID", "", &Options{TableClient: dyn.New(sess)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coll.Close()
+
+	got := map[string]interface{}{"ID": "a"}
+	if err := coll.Get(context.Background(), got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"ID": "a", "X": int64(1)}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var db *dyn.DynamoDB
+	if coll.As(&db) {
+		t.Error("As(*dynamodb.DynamoDB): got true, want false")
+	}
+	var api dynamodbiface.DynamoDBAPI
+	if !coll.As(&api) || api != cache {
+		t.Errorf("As(dynamodbiface.DynamoDBAPI): got %v, want the cache client", api)
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	c := &collection{}
+	ccf := &dyn.ConditionalCheckFailedException{Message_: aws.String("failed")}
+	for _, err := range []error{ccf, &retry.ContextError{CtxErr: context.Canceled, FuncErr: ccf}} {
+		var ae awserr.Error
+		if !c.ErrorAs(err, &ae) || ae.Code() != dyn.ErrCodeConditionalCheckFailedException {
+			t.Errorf("%v: got awserr.Error %v, want the exception", err, ae)
+		}
+		var got *dyn.ConditionalCheckFailedException
+		if !c.ErrorAs(err, &got) || got != ccf {
+			t.Errorf("%v: got %v, want the exception", err, got)
+		}
+		var tce *dyn.TransactionCanceledException
+		if c.ErrorAs(err, &tce) {
+			t.Errorf("%v: got a TransactionCanceledException, want none", err)
+		}
+		var s string
+		if c.ErrorAs(err, &s) {
+			t.Errorf("%v: converted to a string", err)
+		}
+	}
+	if c.ErrorAs(&retry.ContextError{CtxErr: context.Canceled}, new(awserr.Error)) {
+		t.Error("got an awserr.Error from a context error without a request error")
+	}
+}
+
+// fakeVersions is a DynamoDB service that records the PutItem and UpdateItem
+// requests it serves, and returns version 8 from updates that ask for the
+// updated values.
+type fakeVersions struct {
+	mu   sync.Mutex
+	reqs []versionRequest
+}
+
+type versionRequest struct {
+	Item                      map[string]map[string]string
+	ConditionExpression       string
+	UpdateExpression          string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]map[string]string
+	ReturnValues              string
+}
+
+func (f *fakeVersions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var in versionRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.reqs = append(f.reqs, in)
+	out := map[string]interface{}{}
+	if in.ReturnValues == dyn.ReturnValueUpdatedNew {
+		out["Attributes"] = map[string]interface{}{"version": map[string]string{"N": "8"}}
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(out)
+}
+
+func TestVersionRevisions(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeVersions{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(&collection{
+		db:           dyn.New(sess),
+		table:        "T",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{RevisionField: "version", VersionRevisions: true},
+	})
+	defer coll.Close()
+
+	// last returns the last request, with the placeholders of its
+	// expressions replaced by names and values.
+	last := func() (item, cond, update string) {
+		t.Helper()
+		r := f.reqs[len(f.reqs)-1]
+		vals := map[string]string{}
+		for k, v := range r.ExpressionAttributeNames {
+			vals[k] = v
+		}
+		for k, v := range r.ExpressionAttributeValues {
+			vals[k] = v["N"] + v["S"]
+		}
+		// Replace longer placeholders first, such as ":10" before ":1".
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+		var pairs []string
+		for _, k := range keys {
+			pairs = append(pairs, k, vals[k])
+		}
+		rep := strings.NewReplacer(pairs...)
+		return r.Item["version"]["N"], rep.Replace(r.ConditionExpression), rep.Replace(r.UpdateExpression)
+	}
+
+	doc := map[string]interface{}{"ID": "a", "version": nil}
+	if err := coll.Create(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if item, cond, _ := last(); item != "1" || cond != "attribute_not_exists (ID)" || doc["version"] != int64(1) {
+		t.Errorf("Create: got version %q with %q, doc version %v; want 1 without a version condition", item, cond, doc["version"])
+	}
+
+	if err := coll.Replace(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if item, cond, _ := last(); item != "2" || cond != "version = 1" || doc["version"] != int64(2) {
+		t.Errorf("Replace: got version %q with %q, doc version %v; want 2 with version = 1", item, cond, doc["version"])
+	}
+
+	if err := coll.Update(ctx, doc, docstore.Mods{"X": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, cond, update := last(); cond != "version = 2" || !strings.Contains(update, "version = version + 1") ||
+		doc["version"] != int64(3) || f.reqs[len(f.reqs)-1].ReturnValues != "" {
+		t.Errorf("Update: got %q with %q, doc version %v; want version = version + 1 with version = 2", update, cond, doc["version"])
+	}
+
+	// The new version of a document without one is read back.
+	doc = map[string]interface{}{"ID": "b", "version": nil}
+	if err := coll.Update(ctx, doc, docstore.Mods{"X": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, update := last(); !strings.Contains(update, "version = if_not_exists(version, 0) + 1") || doc["version"] != int64(8) {
+		t.Errorf("Update without a version: got %q, doc version %v; want if_not_exists and version 8", update, doc["version"])
+	}
+
+	s, err := coll.RevisionToString(int64(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev, err := coll.StringToRevision(s); err != nil || rev != int64(5) {
+		t.Errorf("StringToRevision: got %v, %v; want 5", rev, err)
+	}
+	err = coll.Replace(ctx, map[string]interface{}{"ID": "a", "version": "x"})
+	if gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Replace with a string version: got %v, want InvalidArgument", err)
+	}
+}
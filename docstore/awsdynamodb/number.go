@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"gocloud.dev/internal/gcerr"
+)
+
+// A Number is a DynamoDB number in its decimal string form, such as "123" or
+// "-1.5E10". DynamoDB numbers have up to 38 significant digits, more than an
+// int64, uint64 or float64 holds; use a Number, *big.Int or *big.Float field
+// to read and write them without loss.
+//
+// A Number is written as a DynamoDB number, not a string, and can be used as
+// a query filter value.
+type Number string
+
+// String returns the number as a string.
+func (n Number) String() string { return string(n) }
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) {
+	if i, ok := parseInt(string(n)); ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("awsdynamodb: number %s is not an int64", n)
+}
+
+// Float64 returns the number as a float64, rounding it if necessary.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// numberSyntax matches the numbers that DynamoDB accepts.
+var numberSyntax = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// bigFloatPrec is the precision of decoded big.Floats, enough for the 38
+// significant digits of a DynamoDB number.
+const bigFloatPrec = 128
+
+var (
+	typeOfNumber   = reflect.TypeOf(Number(""))
+	typeOfBigInt   = reflect.TypeOf(big.Int{})
+	typeOfBigFloat = reflect.TypeOf(big.Float{})
+)
+
+// isBigNumber reports whether t is a Number, or a big.Int or big.Float or a
+// pointer to one.
+func isBigNumber(t reflect.Type) bool {
+	if t == typeOfNumber {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == typeOfBigInt || t == typeOfBigFloat
+}
+
+// encodeBigNumber encodes v, a Number, or a big.Int or big.Float or a non-nil
+// pointer to one, as a DynamoDB number, without converting it to a float64.
+func encodeBigNumber(v reflect.Value) (*dyn.AttributeValue, error) {
+	if v.Type() == typeOfNumber {
+		s := v.String()
+		if !numberSyntax.MatchString(s) {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "awsdynamodb.Number %q is not a number", s)
+		}
+		return new(dyn.AttributeValue).SetN(s), nil
+	}
+	if v.Kind() != reflect.Ptr {
+		// The methods of big.Int and big.Float have pointer receivers.
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return new(dyn.AttributeValue).SetN(x.String()), nil
+	case *big.Float:
+		if x.IsInf() {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot encode infinite big.Float")
+		}
+		// Like float64s, write big.Floats without an exponent.
+		return new(dyn.AttributeValue).SetN(x.Text('f', -1)), nil
+	}
+	panic("impossible")
+}
+
+// decodeBigNumber decodes the DynamoDB number s into a new value of type t,
+// for which isBigNumber is true.
+func decodeBigNumber(s string, t reflect.Type) (interface{}, error) {
+	if t == typeOfNumber {
+		return Number(s), nil
+	}
+	ptr := t.Kind() == reflect.Ptr
+	if ptr {
+		t = t.Elem()
+	}
+	if t == typeOfBigInt {
+		i, ok := parseBigInt(s)
+		if !ok {
+			return nil, fmt.Errorf("number %s is not an integer", s)
+		}
+		if ptr {
+			return i, nil
+		}
+		return *i, nil
+	}
+	f, _, err := big.ParseFloat(s, 10, bigFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	if ptr {
+		return f, nil
+	}
+	return *f, nil
+}
+
+// parseBigInt parses s as an integer, accepting a fraction or exponent as
+// long as the value is integral, as in "1.0" or "1E3".
+func parseBigInt(s string) (*big.Int, bool) {
+	if i, ok := new(big.Int).SetString(s, 10); ok {
+		return i, true
+	}
+	if !numberSyntax.MatchString(s) {
+		return nil, false
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() {
+		return nil, false
+	}
+	return r.Num(), true
+}
+
+// parseInt parses the DynamoDB number s as an int64 without going through a
+// float64, so that large values do not lose precision.
+func parseInt(s string) (int64, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
+	}
+	b, ok := parseBigInt(s)
+	if !ok || !b.IsInt64() {
+		return 0, false
+	}
+	return b.Int64(), true
+}
+
+// parseUint is like parseInt, for uint64s.
+func parseUint(s string) (uint64, bool) {
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u, true
+	}
+	b, ok := parseBigInt(s)
+	if !ok || !b.IsUint64() {
+		return 0, false
+	}
+	return b.Uint64(), true
+}
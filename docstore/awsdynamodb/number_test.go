@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
+)
+
+func TestNumbers(t *testing.T) {
+	type doc struct {
+		ID    string
+		I     int64
+		U     uint64
+		N     Number
+		BI    *big.Int
+		BF    *big.Float
+		BIVal big.Int
+	}
+	const huge = "123456789012345678901234567890123456"
+	bi, _ := new(big.Int).SetString(huge, 10)
+	bf, _, _ := big.ParseFloat("1234567890.123456789012345678901234", 10, bigFloatPrec, big.ToNearestEven)
+	in := &doc{
+		ID:    "a",
+		I:     math.MaxInt64 - 1,
+		U:     math.MaxUint64 - 1,
+		N:     "-1.5E10",
+		BI:    bi,
+		BF:    bf,
+		BIVal: *bi,
+	}
+	opts := &Options{}
+	av, err := encodeDoc(drivertest.MustDocument(in), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, want := range map[string]string{
+		"I":     "9223372036854775806",
+		"U":     "18446744073709551614",
+		"N":     "-1.5E10",
+		"BI":    huge,
+		"BF":    "1234567890.123456789012345678901234",
+		"BIVal": huge,
+	} {
+		if got := av.M[field]; got.N == nil || *got.N != want {
+			t.Errorf("%s: got %v, want N %s", field, got, want)
+		}
+	}
+
+	var got doc
+	if err := decodeDoc(av, drivertest.MustDocument(&got), opts); err != nil {
+		t.Fatal(err)
+	}
+	if got.I != in.I || got.U != in.U || got.N != in.N {
+		t.Errorf("got %d, %d, %s; want %d, %d, %s", got.I, got.U, got.N, in.I, in.U, in.N)
+	}
+	if got.BI.Cmp(bi) != 0 || got.BIVal.Cmp(bi) != 0 {
+		t.Errorf("got big.Ints %s and %s, want %s", got.BI, &got.BIVal, bi)
+	}
+	if got.BF.Cmp(bf) != 0 {
+		t.Errorf("got big.Float %s, want %s", got.BF.Text('f', -1), bf.Text('f', -1))
+	}
+
+	// Integers decode into interface{} without going through float64.
+	m := map[string]interface{}{}
+	if err := decodeDoc(av, drivertest.MustDocument(m), opts); err != nil {
+		t.Fatal(err)
+	}
+	if m["I"] != in.I || m["U"] != in.U {
+		t.Errorf("interface{}: got %v and %v, want %d and %d", m["I"], m["U"], in.I, in.U)
+	}
+
+	// Integral numbers with a fraction or exponent decode into integers.
+	for _, s := range []string{"1000", "1E3", "1000.0", "1.0e3"} {
+		var x struct{ I int64 }
+		item := &dyn.AttributeValue{M: map[string]*dyn.AttributeValue{"I": new(dyn.AttributeValue).SetN(s)}}
+		if err := decodeDoc(item, drivertest.MustDocument(&x), opts); err != nil || x.I != 1000 {
+			t.Errorf("%s: got %d, %v; want 1000", s, x.I, err)
+		}
+	}
+}
+
+func TestNumberErrors(t *testing.T) {
+	for _, v := range []interface{}{
+		&struct{ N Number }{"abc"},
+		&struct{ N Number }{""},
+		&struct{ N Number }{"Inf"},
+		&struct{ F *big.Float }{new(big.Float).SetInf(false)},
+	} {
+		if _, err := encodeDoc(drivertest.MustDocument(v), &Options{}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%+v: got %v, want InvalidArgument", v, err)
+		}
+	}
+
+	var x struct{ I *big.Int }
+	item := &dyn.AttributeValue{M: map[string]*dyn.AttributeValue{"I": new(dyn.AttributeValue).SetN("1.5")}}
+	if err := decodeDoc(item, drivertest.MustDocument(&x), &Options{}); err == nil {
+		t.Error("decoding 1.5 into a big.Int: got nil, want error")
+	}
+}
+
+func TestNumberFilter(t *testing.T) {
+	q := &driver.Query{Filters: []driver.Filter{
+		{FieldPath: []string{"N"}, Op: ">", Value: Number("12345678901234567890123")},
+		{FieldPath: []string{"N"}, Op: "in", Value: []interface{}{Number("1"), 2}},
+	}}
+	c := &collection{opts: &Options{}}
+	c.encodeFilterValues(q)
+	if ev, ok := q.Filters[0].Value.(encodedValue); !ok || ev.av.N == nil {
+		t.Errorf("got %#v, want an encoded number", q.Filters[0].Value)
+	}
+	elems := q.Filters[1].Value.([]interface{})
+	if ev, ok := elems[0].(encodedValue); !ok || ev.av.N == nil {
+		t.Errorf("got %#v, want an encoded number", elems[0])
+	}
+	if elems[1] != 2 {
+		t.Errorf("got %#v, want 2", elems[1])
+	}
+}
+
+// cents is an amount of money stored as a decimal number, such as 12.34.
+type cents int64
+
+func (c cents) MarshalDocstoreValue() (interface{}, error) {
+	return Number(fmt.Sprintf("%d.%02d", c/100, c%100)), nil
+}
+
+func (c *cents) UnmarshalDocstoreValue(decode func(interface{}) error) error {
+	var n Number
+	if err := decode(&n); err != nil {
+		return err
+	}
+	var units, hundredths int64
+	if _, err := fmt.Sscanf(string(n), "%d.%d", &units, &hundredths); err != nil {
+		return err
+	}
+	*c = cents(units*100 + hundredths)
+	return nil
+}
+
+func TestValueMarshaler(t *testing.T) {
+	type doc struct {
+		ID    string
+		Price cents
+	}
+	av, err := encodeDoc(drivertest.MustDocument(&doc{ID: "a", Price: 1234}), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := av.M["Price"]; got.N == nil || *got.N != "12.34" {
+		t.Fatalf("got %v, want the number 12.34", got)
+	}
+	var out doc
+	if err := decodeDoc(av, drivertest.MustDocument(&out), &Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Price != 1234 {
+		t.Errorf("got %d cents, want 1234", out.Price)
+	}
+}
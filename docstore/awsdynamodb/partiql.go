@@ -0,0 +1,495 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+)
+
+// maxBatchStatements is the maximum number of statements in a
+// BatchExecuteStatement call.
+const maxBatchStatements = 25
+
+// runPartiQLQuery runs q, as planned by qr, as a PartiQL SELECT statement.
+func (c *collection) runPartiQLQuery(ctx context.Context, q *driver.Query, qr *queryRunner) (driver.DocumentIterator, error) {
+	if q.PaginationToken != nil {
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "pagination tokens are not supported with Options.PartiQL")
+	}
+	var indexName *string
+	if qr.queryIn != nil {
+		indexName = qr.queryIn.IndexName
+	}
+	stmt, params, err := c.selectStatement(q, indexName)
+	if err != nil {
+		return nil, err
+	}
+	it := &statementIterator{
+		c: c,
+		in: &dyn.ExecuteStatementInput{
+			Statement:              aws.String(stmt),
+			Parameters:             params,
+			ConsistentRead:         aws.Bool(c.consistentRead(ctx)),
+			ReturnConsumedCapacity: c.returnConsumedCapacity(ctx),
+		},
+		beforeRun: q.BeforeQuery,
+		offset:    q.Offset,
+		limit:     q.Limit,
+	}
+	if err := it.run(ctx); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// checkPartiQLPlan is checkPlan for PartiQL queries. DynamoDB runs a statement
+// with an "in" filter on the partition key as a query for each value rather
+// than as a scan, so it is allowed without Options.AllowScans.
+func (c *collection) checkPartiQLPlan(q *driver.Query, qr *queryRunner) error {
+	for _, f := range q.Filters {
+		if f.Op == "in" && driver.FieldPathEqualsField(f.FieldPath, c.partitionKey) {
+			return nil
+		}
+	}
+	return c.checkPlan(qr)
+}
+
+// selectStatement returns the PartiQL SELECT statement equivalent to q, on the
+// index indexName or on the table if it is nil, and the statement's
+// parameters.
+func (c *collection) selectStatement(q *driver.Query, indexName *string) (string, []*dyn.AttributeValue, error) {
+	var (
+		b      strings.Builder
+		params []*dyn.AttributeValue
+	)
+	addParam := func(v interface{}) error {
+		av, err := c.partiQLValue(v)
+		if err != nil {
+			return err
+		}
+		params = append(params, av)
+		b.WriteString("?")
+		return nil
+	}
+	b.WriteString("SELECT ")
+	if len(q.FieldPaths) == 0 {
+		b.WriteString("*")
+	}
+	for i, fp := range q.FieldPaths {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quotePath(fp))
+	}
+	b.WriteString(" FROM ")
+	b.WriteString(quoteName(c.table))
+	if indexName != nil {
+		b.WriteString(".")
+		b.WriteString(quoteName(*indexName))
+	}
+	for i, f := range q.Filters {
+		if i == 0 {
+			b.WriteString(" WHERE ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		switch f.Op {
+		case "in", "not-in":
+			if f.Op == "not-in" {
+				b.WriteString("NOT ")
+			}
+			b.WriteString(quotePath(f.FieldPath))
+			b.WriteString(" IN [")
+			vs := reflect.ValueOf(f.Value)
+			for j := 0; j < vs.Len(); j++ {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				if err := addParam(vs.Index(j).Interface()); err != nil {
+					return "", nil, err
+				}
+			}
+			b.WriteString("]")
+		default:
+			b.WriteString(quotePath(f.FieldPath))
+			b.WriteString(" " + f.Op + " ")
+			if err := addParam(f.Value); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	if q.OrderByField != "" {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(quoteName(q.OrderByField))
+		if q.OrderAscending {
+			b.WriteString(" ASC")
+		} else {
+			b.WriteString(" DESC")
+		}
+	}
+	return b.String(), params, nil
+}
+
+// partiQLValue encodes v, a filter value or a statement parameter, as the
+// collection stores it.
+func (c *collection) partiQLValue(v interface{}) (*dyn.AttributeValue, error) {
+	if ev, ok := v.(encodedValue); ok {
+		return ev.av, nil
+	}
+	return encodeValue(v, c.opts)
+}
+
+// quoteName returns name as a PartiQL quoted identifier.
+func quoteName(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quotePath returns the field path fp as a PartiQL path.
+func quotePath(fp []string) string {
+	q := make([]string, len(fp))
+	for i, f := range fp {
+		q[i] = quoteName(f)
+	}
+	return strings.Join(q, ".")
+}
+
+// A statementIterator iterates over the items that a PartiQL statement
+// returns, reading its pages as needed.
+type statementIterator struct {
+	c         *collection
+	in        *dyn.ExecuteStatementInput
+	beforeRun func(asFunc func(i interface{}) bool) error
+	items     []avmap
+	curr      int  // index of the current item in items
+	more      bool // whether there are more pages
+	offset    int  // number of items to skip
+	limit     int  // number of items to return, if positive
+	count     int  // number of items consumed, including those skipped
+	asFunc    func(i interface{}) bool
+}
+
+// run reads the next page of items.
+func (it *statementIterator) run(ctx context.Context) error {
+	if it.beforeRun != nil {
+		asFunc := func(i interface{}) bool {
+			p, ok := i.(**dyn.ExecuteStatementInput)
+			if !ok {
+				return false
+			}
+			*p = it.in
+			return true
+		}
+		if err := it.beforeRun(asFunc); err != nil {
+			return err
+		}
+	}
+	var out *dyn.ExecuteStatementOutput
+	err := it.c.call(ctx, "ExecuteStatement", func() (err error) {
+		out, err = it.c.db.ExecuteStatementWithContext(ctx, it.in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	it.c.reportCapacity(ctx, out.ConsumedCapacity)
+	it.items, it.curr = out.Items, 0
+	it.in.NextToken = out.NextToken
+	it.more = out.NextToken != nil
+	it.asFunc = func(i interface{}) bool {
+		p, ok := i.(**dyn.ExecuteStatementOutput)
+		if !ok {
+			return false
+		}
+		*p = out
+		return true
+	}
+	return nil
+}
+
+func (it *statementIterator) Next(ctx context.Context, doc driver.Document) error {
+	for {
+		if it.limit > 0 && it.count >= it.offset+it.limit {
+			return io.EOF
+		}
+		// A page can be empty, and still be followed by others.
+		for it.curr >= len(it.items) {
+			if !it.more {
+				return io.EOF
+			}
+			if err := it.run(ctx); err != nil {
+				return err
+			}
+		}
+		item := it.items[it.curr]
+		it.curr++
+		it.count++
+		if it.count > it.offset {
+			return decodeDoc(&dyn.AttributeValue{M: item}, doc, it.c.opts)
+		}
+	}
+}
+
+func (it *statementIterator) Stop() {
+	it.items = nil
+	it.more = false
+}
+
+func (it *statementIterator) As(i interface{}) bool {
+	return it.asFunc != nil && it.asFunc(i)
+}
+
+// RunPartiQL runs a PartiQL statement, such as a SELECT, on the DynamoDB
+// client of coll, which must have been opened with this package. The
+// statement's "?" placeholders are replaced with params, which are encoded as
+// the collection encodes field values. The items that the statement returns
+// are decoded as the collection decodes documents.
+//
+// For example:
+//
+//	iter := awsdynamodb.RunPartiQL(ctx, coll,
+//	    `SELECT "Name", "Info"."Aliases" FROM "People" WHERE "ID" IN [?, ?]`, "a", "b")
+//	defer iter.Stop()
+//
+// RunPartiQL returns the statement's error, if any, from the iterator's Next.
+func RunPartiQL(ctx context.Context, coll *docstore.Collection, statement string, params ...interface{}) *PartiQLIterator {
+	var c *collection
+	if !coll.As(&c) {
+		return &PartiQLIterator{err: gcerr.Newf(gcerr.InvalidArgument, nil, "RunPartiQL: not a DynamoDB collection")}
+	}
+	avs, err := c.partiQLParams(params)
+	if err != nil {
+		return &PartiQLIterator{err: c.wrapError(err)}
+	}
+	it := &statementIterator{
+		c: c,
+		in: &dyn.ExecuteStatementInput{
+			Statement:              aws.String(statement),
+			Parameters:             avs,
+			ConsistentRead:         aws.Bool(c.consistentRead(ctx)),
+			ReturnConsumedCapacity: c.returnConsumedCapacity(ctx),
+		},
+	}
+	if err := it.run(ctx); err != nil {
+		return &PartiQLIterator{err: c.wrapError(err)}
+	}
+	return &PartiQLIterator{it: it}
+}
+
+// partiQLParams encodes the parameters of a statement. DynamoDB requires that
+// a statement without parameters have none rather than an empty list.
+func (c *collection) partiQLParams(params []interface{}) ([]*dyn.AttributeValue, error) {
+	var avs []*dyn.AttributeValue
+	for _, p := range params {
+		av, err := c.partiQLValue(encodeFilterValue(p, c.opts.TimeFormat, c.opts.NullEmptyStrings))
+		if err != nil {
+			return nil, err
+		}
+		avs = append(avs, av)
+	}
+	return avs, nil
+}
+
+// A PartiQLIterator iterates over the items that a PartiQL statement returns.
+// Always call Stop on the iterator.
+type PartiQLIterator struct {
+	it  *statementIterator
+	err error
+}
+
+// Next decodes the next item into doc. It returns io.EOF at the end of the
+// items. Once Next returns an error, it will always return the same error.
+func (it *PartiQLIterator) Next(ctx context.Context, doc docstore.Document) error {
+	if it.err != nil {
+		return it.err
+	}
+	ddoc, err := driver.NewDocument(doc)
+	if err != nil {
+		it.err = err
+		return err
+	}
+	if err := it.it.Next(ctx, ddoc); err != nil {
+		it.err = it.it.c.wrapError(err)
+		return it.err
+	}
+	return nil
+}
+
+// Stop stops the iterator. Calling Next on a stopped iterator returns io.EOF,
+// or the error that Next previously returned.
+func (it *PartiQLIterator) Stop() {
+	if it.err != nil {
+		return
+	}
+	it.err = io.EOF
+	it.it.Stop()
+}
+
+// As converts i to the *dynamodb.ExecuteStatementOutput of the last page of
+// items read.
+func (it *PartiQLIterator) As(i interface{}) bool {
+	return it.it != nil && it.it.As(i)
+}
+
+// A PartiQLStatement is a statement of a batch run by RunPartiQLBatch.
+type PartiQLStatement struct {
+	// The statement, with "?" placeholders for Parameters.
+	Statement  string
+	Parameters []interface{}
+	// The document that the item read by a SELECT statement is decoded into.
+	// A SELECT statement in a batch must name the full key of one item.
+	Doc docstore.Document
+}
+
+// RunPartiQLBatch runs PartiQL statements on the DynamoDB client of coll,
+// which must have been opened with this package, with as few
+// BatchExecuteStatement calls as possible. The statements of a batch must be
+// all reads or all writes.
+//
+// The error of each failed statement is returned in a docstore.ActionListError
+// with the statement's index. A SELECT statement whose item does not exist
+// fails with code NotFound. Statements that DynamoDB throttles are retried as
+// the retry policy allows; see Options.RetryPolicy.
+func RunPartiQLBatch(ctx context.Context, coll *docstore.Collection, stmts []*PartiQLStatement) error {
+	var c *collection
+	if !coll.As(&c) {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "RunPartiQLBatch: not a DynamoDB collection")
+	}
+	// stmtErrs[i] is the error of stmts[i], if it failed.
+	stmtErrs := make([]error, len(stmts))
+	for start := 0; start < len(stmts); start += maxBatchStatements {
+		end := start + maxBatchStatements
+		if end > len(stmts) {
+			end = len(stmts)
+		}
+		reqs := map[int]*dyn.BatchStatementRequest{}
+		var pending []int
+		for i := start; i < end; i++ {
+			avs, err := c.partiQLParams(stmts[i].Parameters)
+			if err != nil {
+				return c.wrapError(err)
+			}
+			reqs[i] = &dyn.BatchStatementRequest{
+				Statement:      aws.String(stmts[i].Statement),
+				Parameters:     avs,
+				ConsistentRead: aws.Bool(c.consistentRead(ctx)),
+			}
+			pending = append(pending, i)
+		}
+		// Retry the statements that DynamoDB throttles, keeping the results
+		// of the others.
+		err := c.call(ctx, "BatchExecuteStatement", func() error {
+			in := &dyn.BatchExecuteStatementInput{ReturnConsumedCapacity: c.returnConsumedCapacity(ctx)}
+			for _, i := range pending {
+				in.Statements = append(in.Statements, reqs[i])
+			}
+			out, err := c.db.BatchExecuteStatementWithContext(ctx, in)
+			if err != nil {
+				return err
+			}
+			c.reportCapacity(ctx, out.ConsumedCapacity...)
+			var throttled []int
+			for j, r := range out.Responses {
+				i := pending[j]
+				if r.Error != nil && throttlingCodes[aws.StringValue(r.Error.Code)] {
+					throttled = append(throttled, i)
+				}
+				stmtErrs[i] = c.batchStatementResult(r, stmts[i])
+			}
+			if len(throttled) > 0 {
+				pending = throttled
+				return errThrottledStatements
+			}
+			return nil
+		})
+		if err != nil && err != errThrottledStatements {
+			return c.wrapError(err)
+		}
+	}
+	var errs docstore.ActionListError
+	for i, err := range stmtErrs {
+		if err != nil {
+			errs = append(errs, struct {
+				Index int
+				Err   error
+			}{i, err})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// batchStatementResult decodes the item of the response r to the statement
+// s of a batch into s.Doc, and returns the error of s, if any.
+func (c *collection) batchStatementResult(r *dyn.BatchStatementResponse, s *PartiQLStatement) error {
+	switch {
+	case r.Error != nil:
+		code, ok := batchErrorCodes[aws.StringValue(r.Error.Code)]
+		if !ok {
+			code = gcerrors.Unknown
+		}
+		return gcerr.Newf(code, nil, "%s: %s", aws.StringValue(r.Error.Code), aws.StringValue(r.Error.Message))
+	case s.Doc == nil:
+		return nil
+	case r.Item == nil:
+		return gcerr.Newf(gcerr.NotFound, nil, "item not found")
+	}
+	ddoc, err := driver.NewDocument(s.Doc)
+	if err == nil {
+		err = decodeDoc(&dyn.AttributeValue{M: r.Item}, ddoc, c.opts)
+	}
+	if err != nil {
+		return c.wrapError(err)
+	}
+	return nil
+}
+
+// batchErrorCodes maps the error codes of BatchExecuteStatement responses to
+// error codes.
+var batchErrorCodes = map[string]gcerrors.ErrorCode{
+	dyn.BatchStatementErrorCodeEnumConditionalCheckFailed:          gcerrors.FailedPrecondition,
+	dyn.BatchStatementErrorCodeEnumItemCollectionSizeLimitExceeded: gcerrors.ResourceExhausted,
+	dyn.BatchStatementErrorCodeEnumRequestLimitExceeded:            gcerrors.ResourceExhausted,
+	dyn.BatchStatementErrorCodeEnumValidationError:                 gcerrors.InvalidArgument,
+	dyn.BatchStatementErrorCodeEnumProvisionedThroughputExceeded:   gcerrors.ResourceExhausted,
+	dyn.BatchStatementErrorCodeEnumTransactionConflict:             gcerrors.Internal,
+	dyn.BatchStatementErrorCodeEnumThrottlingError:                 gcerrors.ResourceExhausted,
+	dyn.BatchStatementErrorCodeEnumInternalServerError:             gcerrors.Internal,
+	dyn.BatchStatementErrorCodeEnumResourceNotFound:                gcerrors.NotFound,
+	dyn.BatchStatementErrorCodeEnumAccessDenied:                    gcerrors.PermissionDenied,
+	dyn.BatchStatementErrorCodeEnumDuplicateItem:                   gcerrors.AlreadyExists,
+}
+
+// wrapError wraps err, from a call made outside the docstore package, with
+// its error code.
+func (c *collection) wrapError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if _, ok := err.(*gcerr.Error); ok {
+		return err
+	}
+	return gcerr.New(c.ErrorCode(err), err, 2, "awsdynamodb")
+}
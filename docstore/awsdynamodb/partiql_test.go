@@ -0,0 +1,244 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+)
+
+func TestSelectStatement(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "ID",
+		sortKey:      "S",
+		description: &dyn.TableDescription{
+			GlobalSecondaryIndexes: []*dyn.GlobalSecondaryIndexDescription{{
+				IndexName:  aws.String("G"),
+				KeySchema:  keySchema("X", "Y"),
+				Projection: indexProjection(nil),
+			}},
+		},
+		opts: &Options{RevisionField: "rev"},
+	}
+	s := func(x string) *dyn.AttributeValue { return new(dyn.AttributeValue).SetS(x) }
+	n := func(x string) *dyn.AttributeValue { return new(dyn.AttributeValue).SetN(x) }
+	for _, test := range []struct {
+		desc       string
+		query      *driver.Query
+		want       string
+		wantParams []*dyn.AttributeValue
+	}{
+		{
+			desc: "keys, projection and order",
+			query: &driver.Query{
+				FieldPaths:   [][]string{{"Info", "Aliases"}},
+				Filters:      []driver.Filter{{FieldPath: []string{"ID"}, Op: "=", Value: "a"}, {FieldPath: []string{"S"}, Op: ">", Value: 1}},
+				OrderByField: "S",
+			},
+			want:       `SELECT "Info"."Aliases", "ID", "S" FROM "T" WHERE "ID" = ? AND "S" > ? ORDER BY "S" DESC`,
+			wantParams: []*dyn.AttributeValue{s("a"), n("1")},
+		},
+		{
+			desc:       "multiple keys",
+			query:      &driver.Query{Filters: []driver.Filter{{FieldPath: []string{"ID"}, Op: "in", Value: []string{"a", "b"}}}},
+			want:       `SELECT * FROM "T" WHERE "ID" IN [?, ?]`,
+			wantParams: []*dyn.AttributeValue{s("a"), s("b")},
+		},
+		{
+			desc:       "global index",
+			query:      &driver.Query{Filters: []driver.Filter{{FieldPath: []string{"X"}, Op: "=", Value: 1}, {FieldPath: []string{"Y"}, Op: "<", Value: 2}}},
+			want:       `SELECT * FROM "T"."G" WHERE "X" = ? AND "Y" < ?`,
+			wantParams: []*dyn.AttributeValue{n("1"), n("2")},
+		},
+		{
+			desc:       "not in, quoted name",
+			query:      &driver.Query{Filters: []driver.Filter{{FieldPath: []string{`a"b`}, Op: "not-in", Value: []int{1}}}},
+			want:       `SELECT * FROM "T" WHERE NOT "a""b" IN [?]`,
+			wantParams: []*dyn.AttributeValue{n("1")},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			qr, err := c.planQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var indexName *string
+			if qr.queryIn != nil {
+				indexName = qr.queryIn.IndexName
+			}
+			got, params, err := c.selectStatement(test.query, indexName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+			if diff := cmp.Diff(test.wantParams, params, cmpopts.IgnoreUnexported(dyn.AttributeValue{})); diff != "" {
+				t.Errorf("params diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakePartiQL is a DynamoDB service that runs any statement by returning
+// the items a and b in two pages, and any batch by returning an item, a
+// failed condition check and no item.
+type fakePartiQL struct {
+	mu         sync.Mutex
+	statements []string
+	params     [][]map[string]string
+}
+
+func (f *fakePartiQL) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var in struct {
+		Statement  string
+		Parameters []map[string]string
+		NextToken  string
+		Statements []struct{ Statement string }
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item := func(id string) map[string]interface{} {
+		return map[string]interface{}{"ID": map[string]string{"S": id}, "X": map[string]string{"N": "1"}}
+	}
+	var out interface{}
+	switch op := r.Header.Get("X-Amz-Target"); {
+	case strings.HasSuffix(op, ".ExecuteStatement"):
+		if in.NextToken == "" {
+			f.statements = append(f.statements, in.Statement)
+			f.params = append(f.params, in.Parameters)
+			out = map[string]interface{}{"Items": []interface{}{item("a")}, "NextToken": "next"}
+		} else {
+			out = map[string]interface{}{"Items": []interface{}{item("b")}}
+		}
+	case strings.HasSuffix(op, ".BatchExecuteStatement"):
+		for _, s := range in.Statements {
+			f.statements = append(f.statements, s.Statement)
+		}
+		out = map[string]interface{}{"Responses": []interface{}{
+			map[string]interface{}{"Item": item("a")},
+			map[string]interface{}{"Error": map[string]string{"Code": "ConditionalCheckFailed", "Message": "failed"}},
+			map[string]interface{}{},
+		}}
+	default:
+		http.Error(w, "unexpected "+op, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(out)
+}
+
+func TestPartiQL(t *testing.T) {
+	ctx := context.Background()
+	f := &fakePartiQL{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(&collection{
+		db:           dyn.New(sess),
+		table:        "T",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{PartiQL: true, RevisionField: docstore.DefaultRevisionField},
+	})
+	defer coll.Close()
+
+	readAll := func(next func(docstore.Document) error) []string {
+		t.Helper()
+		var ids []string
+		for {
+			m := map[string]interface{}{}
+			err := next(m)
+			if err == io.EOF {
+				return ids
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, m["ID"].(string))
+		}
+	}
+	want := []string{"a", "b"}
+
+	// An "in" filter on the partition key needs no scan.
+	iter := coll.Query().Where("ID", "in", []string{"a", "b"}).Get(ctx)
+	got := readAll(func(doc docstore.Document) error { return iter.Next(ctx, doc) })
+	iter.Stop()
+	if !cmp.Equal(got, want) {
+		t.Errorf("query: got %v, want %v", got, want)
+	}
+
+	piter := RunPartiQL(ctx, coll, `SELECT * FROM "T" WHERE "X" = ?`, 1)
+	got = readAll(func(doc docstore.Document) error { return piter.Next(ctx, doc) })
+	var out *dyn.ExecuteStatementOutput
+	if !piter.As(&out) || out.NextToken != nil {
+		t.Errorf("As: got %v, want the last page", out)
+	}
+	piter.Stop()
+	if !cmp.Equal(got, want) {
+		t.Errorf("RunPartiQL: got %v, want %v", got, want)
+	}
+	wantStmts := []string{`SELECT * FROM "T" WHERE "ID" IN [?, ?]`, `SELECT * FROM "T" WHERE "X" = ?`}
+	wantParams := [][]map[string]string{{{"S": "a"}, {"S": "b"}}, {{"N": "1"}}}
+	if !cmp.Equal(f.statements, wantStmts) || !cmp.Equal(f.params, wantParams) {
+		t.Errorf("got statements %q with %v, want %q with %v", f.statements, f.params, wantStmts, wantParams)
+	}
+
+	doc := map[string]interface{}{}
+	err = RunPartiQLBatch(ctx, coll, []*PartiQLStatement{
+		{Statement: `SELECT * FROM "T" WHERE "ID" = ?`, Parameters: []interface{}{"a"}, Doc: doc},
+		{Statement: `UPDATE "T" SET "X" = 2 WHERE "ID" = ?`, Parameters: []interface{}{"a"}},
+		{Statement: `SELECT * FROM "T" WHERE "ID" = ?`, Parameters: []interface{}{"c"}, Doc: map[string]interface{}{}},
+	})
+	if doc["ID"] != "a" {
+		t.Errorf("batch: got %v, want item a", doc)
+	}
+	var ale docstore.ActionListError
+	if !errors.As(err, &ale) || len(ale) != 2 ||
+		ale[0].Index != 1 || gcerrors.Code(ale[0].Err) != gcerrors.FailedPrecondition ||
+		ale[1].Index != 2 || gcerrors.Code(ale[1].Err) != gcerrors.NotFound {
+		t.Errorf("batch: got %v, want FailedPrecondition at 1 and NotFound at 2", err)
+	}
+}
@@ -16,13 +16,17 @@ package awsdynamodb
 
 import (
 	"bytes"
+	"container/heap"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -33,8 +37,6 @@ import (
 	"gocloud.dev/internal/gcerr"
 )
 
-// TODO: support parallel scans (http://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Scan.html#Scan.ParallelScan)
-
 // TODO(jba): support an empty item slice returned from an RPC: "A Query operation can
 // return an empty result set and a LastEvaluatedKey if all the items read for the
 // page of results are filtered out."
@@ -49,22 +51,80 @@ func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.D
 		}
 		return nil, err
 	}
+	if c.opts.PartiQL {
+		if err := c.checkPartiQLPlan(q, qr); err != nil {
+			return nil, err
+		}
+		return c.runPartiQLQuery(ctx, q, qr)
+	}
 	if err := c.checkPlan(qr); err != nil {
 		return nil, err
 	}
+	c.setReadOptions(ctx, qr)
+	if q.Limit > 0 {
+		qr.setLimit(int64(q.Offset + q.Limit))
+	}
 	it := &documentIterator{
 		qr:     qr,
 		offset: q.Offset,
 		limit:  q.Limit,
 		count:  0, // manually count limit since dynamodb uses "limit" as scan limit before filtering
 	}
-	it.items, it.last, it.asFunc, err = it.qr.run(ctx, nil)
+	if q.PaginationToken != nil {
+		if err := json.Unmarshal(q.PaginationToken, &it.start); err != nil {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, err, "invalid pagination token")
+		}
+		if len(it.start) == 0 {
+			it.start = nil
+		}
+	}
+	if n := c.scanSegments(ctx); qr.scanIn != nil && n > 1 {
+		if q.PaginationToken != nil {
+			return nil, gcerr.Newf(gcerr.Unimplemented, nil, "pagination tokens are not supported for parallel scans")
+		}
+		// The iterator reads the pages of the segments as Next needs them.
+		ctx, cancel := context.WithCancel(ctx)
+		it.pages = qr.runParallelScan(ctx, n)
+		it.stop = cancel
+		return it, nil
+	}
+	it.items, it.last, it.asFunc, err = it.qr.run(ctx, it.start)
 	if err != nil {
 		return nil, err
 	}
 	return it, nil
 }
 
+// setLimit sets the Limit of qr's request to n, if every item it reads is a
+// result. DynamoDB applies the Limit before the filter expression, and the
+// key filters apply after the request, so otherwise the results would be cut
+// short.
+func (qr *queryRunner) setLimit(n int64) {
+	if len(qr.keyFilters) > 0 {
+		return
+	}
+	if qr.scanIn != nil {
+		if qr.scanIn.FilterExpression == nil {
+			qr.scanIn.Limit = aws.Int64(n)
+		}
+		return
+	}
+	if qr.queryIn.FilterExpression == nil {
+		qr.queryIn.Limit = aws.Int64(n)
+	}
+}
+
+// setReadOptions sets the per-call options of the reads of qr made with ctx.
+func (c *collection) setReadOptions(ctx context.Context, qr *queryRunner) {
+	if qr.scanIn != nil {
+		qr.scanIn.ConsistentRead = aws.Bool(c.consistentRead(ctx))
+		qr.scanIn.ReturnConsumedCapacity = c.returnConsumedCapacity(ctx)
+	} else {
+		qr.queryIn.ConsistentRead = aws.Bool(c.consistentRead(ctx))
+		qr.queryIn.ReturnConsumedCapacity = c.returnConsumedCapacity(ctx)
+	}
+}
+
 func (c *collection) checkPlan(qr *queryRunner) error {
 	if qr.scanIn != nil && qr.scanIn.FilterExpression != nil && !c.opts.AllowScans {
 		return gcerr.Newf(gcerr.InvalidArgument, nil, "query requires a table scan; set Options.AllowScans to true to enable")
@@ -73,12 +133,24 @@ func (c *collection) checkPlan(qr *queryRunner) error {
 }
 
 func (c *collection) planQuery(q *driver.Query) (*queryRunner, error) {
+	c.encodeFilterValues(q)
 	var cb expression.Builder
 	cbUsed := false // It's an error to build an empty Builder.
 	// Set up the projection expression.
+	var pb expression.ProjectionBuilder
+	hasFields := map[string]bool{}
+	var hidden []string // index keys that are projected but not returned
+	addKeys := func(keys ...string) (added []string) {
+		for _, f := range keys {
+			if f != "" && !hasFields[f] {
+				pb = pb.AddNames(expression.Name(f))
+				hasFields[f] = true
+				added = append(added, f)
+			}
+		}
+		return added
+	}
 	if len(q.FieldPaths) > 0 {
-		var pb expression.ProjectionBuilder
-		hasFields := map[string]bool{}
 		for _, fp := range q.FieldPaths {
 			if len(fp) == 1 {
 				hasFields[fp[0]] = true
@@ -86,18 +158,22 @@ func (c *collection) planQuery(q *driver.Query) (*queryRunner, error) {
 			pb = pb.AddNames(expression.Name(strings.Join(fp, ".")))
 		}
 		// Always include the keys.
-		for _, f := range []string{c.partitionKey, c.sortKey} {
-			if f != "" && !hasFields[f] {
-				pb = pb.AddNames(expression.Name(f))
-				q.FieldPaths = append(q.FieldPaths, []string{f})
-			}
+		for _, f := range addKeys(c.partitionKey, c.sortKey) {
+			q.FieldPaths = append(q.FieldPaths, []string{f})
 		}
-		cb = cb.WithProjection(pb)
-		cbUsed = true
 	}
 
 	// Find the best thing to query (table or index).
 	indexName, pkey, skey := c.bestQueryable(q)
+	if len(q.FieldPaths) > 0 {
+		// Read the keys of an index too, which pagination tokens need, but
+		// leave them out of the documents unless they were asked for.
+		if indexName != nil {
+			hidden = addKeys(pkey, skey)
+		}
+		cb = cb.WithProjection(pb)
+		cbUsed = true
+	}
 	if indexName == nil && pkey == "" {
 		// No query can be done: fall back to scanning.
 		if q.OrderByField != "" {
@@ -127,11 +203,14 @@ func (c *collection) planQuery(q *driver.Query) (*queryRunner, error) {
 			in.FilterExpression = ce.Filter()
 			in.ProjectionExpression = ce.Projection()
 		}
-		return &queryRunner{c: c, scanIn: in, beforeRun: q.BeforeQuery}, nil
+		return &queryRunner{c: c, scanIn: in, keys: []string{c.partitionKey, c.sortKey}, beforeRun: q.BeforeQuery}, nil
 	}
 
 	// Do a query.
-	cb = processFilters(cb, q.Filters, pkey, skey)
+	cb, kfs, err := c.processFilters(cb, q.Filters, pkey, skey)
+	if err != nil {
+		return nil, err
+	}
 	ce, err := cb.Build()
 	if err != nil {
 		return nil, err
@@ -150,9 +229,12 @@ func (c *collection) planQuery(q *driver.Query) (*queryRunner, error) {
 		qIn.ScanIndexForward = &q.OrderAscending
 	}
 	return &queryRunner{
-		c:         c,
-		queryIn:   qIn,
-		beforeRun: q.BeforeQuery,
+		c:          c,
+		queryIn:    qIn,
+		keyFilters: kfs,
+		keys:       []string{c.partitionKey, c.sortKey, pkey, skey},
+		hidden:     hidden,
+		beforeRun:  q.BeforeQuery,
 	}, nil
 }
 
@@ -303,12 +385,46 @@ func hasEqualityFilter(q *driver.Query, field string) bool {
 }
 
 type queryRunner struct {
-	c         *collection
-	scanIn    *dyn.ScanInput
-	queryIn   *dyn.QueryInput
+	c       *collection
+	scanIn  *dyn.ScanInput
+	queryIn *dyn.QueryInput
+	// keyFilters are the filters on keys that the query could not apply; see
+	// processFilters.
+	keyFilters []keyFilter
+	// keys are the key attributes of the table and of the queried index, which
+	// make up the position of an item in the results.
+	keys []string
+	// hidden are the attributes that the projection reads only for the
+	// pagination tokens; they are not decoded into documents.
+	hidden    []string
 	beforeRun func(asFunc func(i interface{}) bool) error
 }
 
+// visible returns item without the query runner's hidden attributes.
+func (qr *queryRunner) visible(item avmap) avmap {
+	if len(qr.hidden) == 0 {
+		return item
+	}
+	m := make(avmap, len(item))
+	for k, v := range item {
+		m[k] = v
+	}
+	for _, k := range qr.hidden {
+		delete(m, k)
+	}
+	return m
+}
+
+// matches reports whether item satisfies the query runner's key filters.
+func (qr *queryRunner) matches(item avmap) bool {
+	for _, kf := range qr.keyFilters {
+		if !kf.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
 func (qr *queryRunner) run(ctx context.Context, startAfter avmap) (items []avmap, last avmap, asFunc func(i interface{}) bool, err error) {
 	if qr.scanIn != nil {
 		qr.scanIn.ExclusiveStartKey = startAfter
@@ -325,10 +441,15 @@ func (qr *queryRunner) run(ctx context.Context, startAfter avmap) (items []avmap
 				return nil, nil, nil, err
 			}
 		}
-		out, err := qr.c.db.ScanWithContext(ctx, qr.scanIn)
+		var out *dyn.ScanOutput
+		err = qr.c.call(ctx, "Scan", func() (err error) {
+			out, err = qr.c.db.ScanWithContext(ctx, qr.scanIn)
+			return err
+		})
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		qr.c.reportCapacity(ctx, out.ConsumedCapacity)
 		return out.Items, out.LastEvaluatedKey,
 			func(i interface{}) bool {
 				p, ok := i.(**dyn.ScanOutput)
@@ -353,10 +474,15 @@ func (qr *queryRunner) run(ctx context.Context, startAfter avmap) (items []avmap
 			return nil, nil, nil, err
 		}
 	}
-	out, err := qr.c.db.QueryWithContext(ctx, qr.queryIn)
+	var out *dyn.QueryOutput
+	err = qr.c.call(ctx, "Query", func() (err error) {
+		out, err = qr.c.db.QueryWithContext(ctx, qr.queryIn)
+		return err
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	qr.c.reportCapacity(ctx, out.ConsumedCapacity)
 	return out.Items, out.LastEvaluatedKey,
 		func(i interface{}) bool {
 			p, ok := i.(**dyn.QueryOutput)
@@ -368,25 +494,362 @@ func (qr *queryRunner) run(ctx context.Context, startAfter avmap) (items []avmap
 		}, nil
 }
 
-func processFilters(cb expression.Builder, fs []driver.Filter, pkey, skey string) expression.Builder {
-	var kbs []expression.KeyConditionBuilder
-	var cfs []driver.Filter
+// A scanPage is a page of items from one segment of a parallel scan.
+type scanPage struct {
+	items  []avmap
+	asFunc func(i interface{}) bool
+	err    error
+}
+
+// runParallelScan scans the table in n segments concurrently, until ctx is
+// done. It sends the pages of all the segments to the returned channel, which
+// is closed when all the segments have been scanned. A segment stops at its
+// first error.
+func (qr *queryRunner) runParallelScan(ctx context.Context, n int) <-chan scanPage {
+	ch := make(chan scanPage)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		in := *qr.scanIn
+		in.Segment = aws.Int64(int64(i))
+		in.TotalSegments = aws.Int64(int64(n))
+		sqr := &queryRunner{c: qr.c, scanIn: &in, beforeRun: qr.beforeRun}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last avmap
+			for {
+				items, l, asFunc, err := sqr.run(ctx, last)
+				select {
+				case ch <- scanPage{items, asFunc, err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil || l == nil {
+					return
+				}
+				last = l
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// processFilters adds the key condition and filter expression of a query on
+// the table or index with keys pkey and skey to cb. It returns the filters on
+// the keys that cannot be part of the key condition: DynamoDB does not allow
+// keys in the filter expression of a query, so the query runner checks them
+// on the items that the query returns.
+func (c *collection) processFilters(cb expression.Builder, fs []driver.Filter, pkey, skey string) (expression.Builder, []keyFilter, error) {
+	var (
+		kb       *expression.KeyConditionBuilder
+		sfs, cfs []driver.Filter
+		kfs      []keyFilter
+	)
 	for _, f := range fs {
-		if kb, ok := toKeyCondition(f, pkey, skey); ok {
-			kbs = append(kbs, kb)
-			continue
+		switch strings.Join(f.FieldPath, ".") {
+		case pkey:
+			if f.Op == driver.EqualOp && kb == nil {
+				k := expression.KeyEqual(expression.Key(pkey), expression.Value(f.Value))
+				kb = &k
+				continue
+			}
+			kf, err := c.newKeyFilter(f)
+			if err != nil {
+				return cb, nil, err
+			}
+			kfs = append(kfs, kf)
+		case skey:
+			sfs = append(sfs, f)
+		default:
+			cfs = append(cfs, f)
 		}
-		cfs = append(cfs, f)
 	}
-	keyBuilder := kbs[0]
-	for i := 1; i < len(kbs); i++ {
-		keyBuilder = keyBuilder.And(kbs[i])
+	if len(sfs) > 0 {
+		skb, rest, err := c.sortKeyCondition(sfs, skey)
+		if err != nil {
+			return cb, nil, err
+		}
+		if skb != nil {
+			k := kb.And(*skb)
+			kb = &k
+		}
+		kfs = append(kfs, rest...)
 	}
-	cb = cb.WithKeyCondition(keyBuilder)
+	cb = cb.WithKeyCondition(*kb)
 	if len(cfs) > 0 {
 		cb = cb.WithFilter(filtersToConditionBuilder(cfs))
 	}
-	return cb
+	return cb, kfs, nil
+}
+
+// sortKeyCondition returns the key condition on the sort key skey that
+// selects the fewest items satisfying the filters fs on it, or nil if there is
+// none, along with the filters that the condition does not imply.
+//
+// An equality filter becomes an equality condition. A pair of filters
+// ">= p" and "< q", where the strings with prefix p are exactly those from p to
+// q, becomes begins_with(skey, p). Filters ">= a" and "<= b" become
+// "skey BETWEEN a AND b". An "in" filter becomes BETWEEN its least and greatest
+// values, and remains to be checked.
+func (c *collection) sortKeyCondition(fs []driver.Filter, skey string) (*expression.KeyConditionBuilder, []keyFilter, error) {
+	kfs := make([]keyFilter, len(fs))
+	for i, f := range fs {
+		kf, err := c.newKeyFilter(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		kfs[i] = kf
+	}
+	key := expression.Key(skey)
+	value := func(av *dyn.AttributeValue) expression.ValueBuilder { return expression.Value(encodedValue{av}) }
+	// without returns the filters other than those at indexes is.
+	without := func(is ...int) []keyFilter {
+		var rest []keyFilter
+	filters:
+		for i, kf := range kfs {
+			for _, j := range is {
+				if i == j {
+					continue filters
+				}
+			}
+			rest = append(rest, kf)
+		}
+		return rest
+	}
+
+	lo, hi := -1, -1 // the indexes of the first lower and upper bounds
+	for i, kf := range kfs {
+		switch kf.op {
+		case driver.EqualOp:
+			k := expression.KeyEqual(key, value(kf.vals[0]))
+			return &k, without(i), nil
+		case ">", ">=":
+			if lo < 0 {
+				lo = i
+			}
+		case "<", "<=":
+			if hi < 0 {
+				hi = i
+			}
+		}
+	}
+	if lo >= 0 && hi >= 0 {
+		l, h := kfs[lo], kfs[hi]
+		if l.op == ">=" && h.op == "<" && l.vals[0].S != nil && h.vals[0].S != nil && prefixEnd(*l.vals[0].S) == *h.vals[0].S {
+			k := expression.KeyBeginsWith(key, *l.vals[0].S)
+			return &k, without(lo, hi), nil
+		}
+		if l.op == ">=" && h.op == "<=" {
+			k := expression.KeyBetween(key, value(l.vals[0]), value(h.vals[0]))
+			return &k, without(lo, hi), nil
+		}
+	}
+	i := lo
+	if i < 0 {
+		i = hi
+	}
+	if i >= 0 {
+		var k expression.KeyConditionBuilder
+		v := value(kfs[i].vals[0])
+		switch kfs[i].op {
+		case ">":
+			k = expression.KeyGreaterThan(key, v)
+		case ">=":
+			k = expression.KeyGreaterThanEqual(key, v)
+		case "<":
+			k = expression.KeyLessThan(key, v)
+		default:
+			k = expression.KeyLessThanEqual(key, v)
+		}
+		return &k, without(i), nil
+	}
+	for _, kf := range kfs {
+		if kf.op != "in" {
+			continue
+		}
+		if min, max, ok := bounds(kf.vals); ok {
+			k := expression.KeyBetween(key, value(min), value(max))
+			return &k, kfs, nil
+		}
+	}
+	return nil, kfs, nil
+}
+
+// prefixEnd returns the least string greater than all the strings with prefix
+// p, or "" if there is none.
+func prefixEnd(p string) string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i] < 0xff {
+			return p[:i] + string([]byte{p[i] + 1})
+		}
+	}
+	return ""
+}
+
+// bounds returns the least and greatest of avs, and false if they cannot all
+// be compared with each other.
+func bounds(avs []*dyn.AttributeValue) (min, max *dyn.AttributeValue, ok bool) {
+	min, max = avs[0], avs[0]
+	for _, av := range avs {
+		cmin, ok1 := compareAttributeValues(av, min)
+		cmax, ok2 := compareAttributeValues(av, max)
+		if !ok1 || !ok2 {
+			return nil, nil, false
+		}
+		if cmin < 0 {
+			min = av
+		}
+		if cmax > 0 {
+			max = av
+		}
+	}
+	return min, max, true
+}
+
+// A keyFilter is a filter on a key of the table or index of a query that the
+// query runner checks on the items that the query returns.
+type keyFilter struct {
+	field string
+	op    string
+	// vals is the filter's value, or the values of an "in" or "not-in" filter,
+	// encoded as they are stored.
+	vals []*dyn.AttributeValue
+}
+
+// newKeyFilter returns f as a keyFilter.
+func (c *collection) newKeyFilter(f driver.Filter) (keyFilter, error) {
+	kf := keyFilter{field: strings.Join(f.FieldPath, "."), op: f.Op}
+	encode := func(v interface{}) error {
+		if ev, ok := v.(encodedValue); ok {
+			kf.vals = append(kf.vals, ev.av)
+			return nil
+		}
+		av, err := encodeValue(v, c.opts)
+		if err != nil {
+			return err
+		}
+		kf.vals = append(kf.vals, av)
+		return nil
+	}
+	if f.Op == "in" || f.Op == "not-in" {
+		vs := reflect.ValueOf(f.Value)
+		for i := 0; i < vs.Len(); i++ {
+			if err := encode(vs.Index(i).Interface()); err != nil {
+				return keyFilter{}, err
+			}
+		}
+		return kf, nil
+	}
+	return kf, encode(f.Value)
+}
+
+// match reports whether item satisfies the filter. As in DynamoDB, a value
+// does not compare with a value of a different type.
+func (kf keyFilter) match(item avmap) bool {
+	v := item[kf.field]
+	if v == nil {
+		return kf.op == "not-in"
+	}
+	switch kf.op {
+	case "in", "not-in":
+		in := false
+		for _, fv := range kf.vals {
+			if cmp, ok := compareAttributeValues(v, fv); ok && cmp == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (kf.op == "in")
+	}
+	cmp, ok := compareAttributeValues(v, kf.vals[0])
+	if !ok {
+		return false
+	}
+	switch kf.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case driver.EqualOp:
+		return cmp == 0
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	default:
+		panic(fmt.Sprint("invalid filter operation:", kf.op))
+	}
+}
+
+// compareAttributeValues compares two strings, numbers or binary values as
+// DynamoDB does, returning -1, 0 or 1. It returns false if they are not of
+// the same one of those types.
+func compareAttributeValues(a, b *dyn.AttributeValue) (int, bool) {
+	switch {
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	case a.N != nil && b.N != nil:
+		x, ok1 := new(big.Rat).SetString(*a.N)
+		y, ok2 := new(big.Rat).SetString(*b.N)
+		if !ok1 || !ok2 {
+			return 0, false
+		}
+		return x.Cmp(y), true
+	}
+	return 0, false
+}
+
+// encodeFilterValues replaces the values in q's filters that the expression
+// package would encode differently from the collection: times, which are
+// encoded in the collection's time format, or as epoch seconds for the TTL
+// field; Numbers, which are encoded as numbers rather than strings; and empty
+// strings, which are encoded as NULL only if Options.NullEmptyStrings is set.
+func (c *collection) encodeFilterValues(q *driver.Query) {
+	for i, f := range q.Filters {
+		tf := c.opts.TimeFormat
+		if len(f.FieldPath) == 1 && f.FieldPath[0] == c.opts.TTLField {
+			tf = TimeUnixSeconds
+		}
+		if f.Op != "in" && f.Op != "not-in" {
+			q.Filters[i].Value = encodeFilterValue(f.Value, tf, c.opts.NullEmptyStrings)
+			continue
+		}
+		vs := reflect.ValueOf(f.Value)
+		if vs.Kind() != reflect.Slice && vs.Kind() != reflect.Array {
+			continue
+		}
+		elems := make([]interface{}, vs.Len())
+		for j := range elems {
+			elems[j] = encodeFilterValue(vs.Index(j).Interface(), tf, c.opts.NullEmptyStrings)
+		}
+		q.Filters[i].Value = elems
+	}
+}
+
+// encodeFilterValue returns the encoding of v if it is a time, a Number or an
+// empty string, and v otherwise. Times in the RFC 3339 format are left to the
+// expression package, which already encodes them that way, and so are empty
+// strings if nullEmptyStrings is true.
+func encodeFilterValue(v interface{}, tf TimeFormat, nullEmptyStrings bool) interface{} {
+	switch x := v.(type) {
+	case time.Time:
+		if tf != TimeRFC3339 {
+			return encodedValue{encodeTime(x, tf)}
+		}
+	case Number:
+		return encodedValue{new(dyn.AttributeValue).SetN(string(x))}
+	case string:
+		if x == "" && !nullEmptyStrings {
+			return encodedValue{new(dyn.AttributeValue).SetS("")}
+		}
+	}
+	return v
 }
 
 func filtersToConditionBuilder(fs []driver.Filter) expression.ConditionBuilder {
@@ -401,29 +864,6 @@ func filtersToConditionBuilder(fs []driver.Filter) expression.ConditionBuilder {
 	return cb
 }
 
-func toKeyCondition(f driver.Filter, pkey, skey string) (expression.KeyConditionBuilder, bool) {
-	kp := strings.Join(f.FieldPath, ".")
-	if kp == pkey || kp == skey {
-		key := expression.Key(kp)
-		val := expression.Value(f.Value)
-		switch f.Op {
-		case "<":
-			return expression.KeyLessThan(key, val), true
-		case "<=":
-			return expression.KeyLessThanEqual(key, val), true
-		case driver.EqualOp:
-			return expression.KeyEqual(key, val), true
-		case ">=":
-			return expression.KeyGreaterThanEqual(key, val), true
-		case ">":
-			return expression.KeyGreaterThan(key, val), true
-		default:
-			panic(fmt.Sprint("invalid filter operation:", f.Op))
-		}
-	}
-	return expression.KeyConditionBuilder{}, false
-}
-
 func toFilter(f driver.Filter) expression.ConditionBuilder {
 	name := expression.Name(strings.Join(f.FieldPath, "."))
 	val := expression.Value(f.Value)
@@ -467,6 +907,10 @@ type documentIterator struct {
 	count  int                              // number of items returned
 	last   map[string]*dyn.AttributeValue   // lastEvaluatedKey from the last query
 	asFunc func(i interface{}) bool         // for As
+	pages  <-chan scanPage                  // pages of a parallel scan; if set, last is unused
+	stop   func()                           // stops a parallel scan
+	start  map[string]*dyn.AttributeValue   // the key that the query started after, from a pagination token
+	pos    map[string]*dyn.AttributeValue   // the last item that next consumed
 }
 
 func (it *documentIterator) Next(ctx context.Context, doc driver.Document) error {
@@ -484,36 +928,108 @@ func (it *documentIterator) next(ctx context.Context, doc driver.Document, decod
 	if it.limit > 0 && it.count >= it.offset+it.limit {
 		return io.EOF
 	}
-	// it.items can be empty after a call to it.qr.run, but unless it.last is nil there may be more items.
-	for it.curr >= len(it.items) {
-		// Make a new query request at the end of this page.
-		if it.last == nil {
-			return io.EOF
+	for {
+		// it.items can be empty after a call to it.qr.run, but unless it.last is nil there may be more items.
+		for it.curr >= len(it.items) {
+			if err := it.nextPage(ctx); err != nil {
+				return err
+			}
 		}
-		var err error
-		it.items, it.last, it.asFunc, err = it.qr.run(ctx, it.last)
-		if err != nil {
-			return err
+		// Skip the items that the query could not filter out.
+		if it.qr.matches(it.items[it.curr]) {
+			break
 		}
-		it.curr = 0
+		it.curr++
 	}
 	if decode {
-		if err := decodeDoc(&dyn.AttributeValue{M: it.items[it.curr]}, doc); err != nil {
+		if err := decodeDoc(&dyn.AttributeValue{M: it.qr.visible(it.items[it.curr])}, doc, it.qr.c.opts); err != nil {
 			return err
 		}
 	}
+	it.pos = it.items[it.curr]
 	it.curr++
 	it.count++
 	return nil
 }
 
+// nextPage reads the next page of items. It returns io.EOF if there are none.
+func (it *documentIterator) nextPage(ctx context.Context) error {
+	if it.pages != nil {
+		select {
+		case p, ok := <-it.pages:
+			if !ok {
+				return io.EOF
+			}
+			if p.err != nil {
+				return p.err
+			}
+			it.items, it.asFunc = p.items, p.asFunc
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		it.curr = 0
+		return nil
+	}
+	// Make a new query request at the end of this page.
+	if it.last == nil {
+		return io.EOF
+	}
+	var err error
+	it.items, it.last, it.asFunc, err = it.qr.run(ctx, it.last)
+	if err != nil {
+		return err
+	}
+	it.curr = 0
+	return nil
+}
+
 func (it *documentIterator) Stop() {
 	it.items = nil
 	it.last = nil
+	it.pages = nil
+	if it.stop != nil {
+		it.stop()
+	}
+}
+
+// SupportsPaginationTokens implements driver.PaginatedQueryer. Queries run
+// with PartiQL cannot be resumed.
+func (c *collection) SupportsPaginationTokens() bool { return !c.opts.PartiQL }
+
+// PaginationToken implements driver.PaginatedIterator. The token holds the key
+// of the last item that the iterator consumed, to be the ExclusiveStartKey of
+// the query that resumes from it.
+func (it *documentIterator) PaginationToken() ([]byte, error) {
+	if it.pages != nil {
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "pagination tokens are not supported for parallel scans")
+	}
+	if it.curr >= len(it.items) && it.last == nil {
+		return nil, nil
+	}
+	key := avmap{}
+	if it.pos == nil {
+		// Nothing has been consumed: resume from where this query started.
+		if it.start != nil {
+			key = it.start
+		}
+	} else {
+		for _, k := range it.qr.keys {
+			if k == "" {
+				continue
+			}
+			v := it.pos[k]
+			if v == nil {
+				return nil, gcerr.Newf(gcerr.Internal, nil, "item has no key attribute %q", k)
+			}
+			key[k] = v
+		}
+	}
+	return json.Marshal(key)
 }
 
 func (it *documentIterator) As(i interface{}) bool {
-	return it.asFunc(i)
+	// Before the first page of a parallel scan, there is nothing to convert.
+	return it.asFunc != nil && it.asFunc(i)
 }
 
 func (c *collection) QueryPlan(q *driver.Query) (string, error) {
@@ -537,6 +1053,8 @@ func (qr *queryRunner) queryPlan() string {
 // InMemorySortFallback returns a query fallback function for Options.RunQueryFallback.
 // The function accepts a query with an OrderBy clause. It runs the query without that clause,
 // reading all documents into memory, then sorts the documents according to the OrderBy clause.
+// If the query has a Limit, only the first Offset+Limit documents in the sorted order are kept
+// in memory, though all are read.
 //
 // Only string, numeric, time and binary ([]byte) fields can be sorted.
 //
@@ -544,6 +1062,23 @@ func (qr *queryRunner) queryPlan() string {
 // The DocumentIterator returned by the FallbackFunc will also expect the same type of document.
 // If nil, then a map[string]interface{} will be used.
 func InMemorySortFallback(createDocument func() interface{}) FallbackFunc {
+	return inMemorySort(createDocument, 0)
+}
+
+// BoundedInMemorySortFallback is like InMemorySortFallback, but it keeps at most
+// maxDocs documents in memory. The function fails with code ResourceExhausted if
+// the query's Offset plus Limit is greater than maxDocs, or if the query has no
+// Limit and matches more than maxDocs documents. Use it to order by fields that
+// are not sort keys, such as in
+//
+//	Options{RunQueryFallback: awsdynamodb.BoundedInMemorySortFallback(nil, 1000)}
+func BoundedInMemorySortFallback(createDocument func() interface{}, maxDocs int) FallbackFunc {
+	return inMemorySort(createDocument, maxDocs)
+}
+
+// inMemorySort implements InMemorySortFallback, and BoundedInMemorySortFallback
+// if maxDocs is positive.
+func inMemorySort(createDocument func() interface{}, maxDocs int) FallbackFunc {
 	if createDocument == nil {
 		createDocument = func() interface{} { return map[string]interface{}{} }
 	}
@@ -551,16 +1086,28 @@ func InMemorySortFallback(createDocument func() interface{}) FallbackFunc {
 		if q.OrderByField == "" {
 			return nil, errors.New("InMemorySortFallback expects an OrderBy query")
 		}
-		// Run the query without the OrderBy.
-		orderByField := q.OrderByField
-		q.OrderByField = ""
+		// keep is the number of documents to keep, or 0 for all of them.
+		keep := 0
+		if q.Limit > 0 {
+			keep = q.Offset + q.Limit
+		}
+		if maxDocs > 0 && keep > maxDocs {
+			return nil, gcerr.Newf(gcerr.ResourceExhausted, nil, "query offset and limit of %d documents exceed the in-memory sort limit of %d", keep, maxDocs)
+		}
+		// Run the query without the OrderBy, Offset and Limit, which apply to
+		// the sorted documents.
+		orderByField, offset := q.OrderByField, q.Offset
+		q.OrderByField, q.Offset, q.Limit = "", 0, 0
 		iter, err := run(ctx, q)
 		if err != nil {
 			return nil, err
 		}
 		defer iter.Stop()
-		// Collect the results into a slice.
-		var docs []driver.Document
+		// Collect the results, along with the values to sort them by. With a
+		// limit, they are a heap whose root is the last document kept.
+		// OrderByField is a single field, not a field path.
+		ds := docsForSorting{ascending: q.OrderAscending}
+		h := sortHeap{&ds}
 		for {
 			doc, err := driver.NewDocument(createDocument())
 			if err != nil {
@@ -573,22 +1120,29 @@ func InMemorySortFallback(createDocument func() interface{}) FallbackFunc {
 			if err != nil {
 				return nil, err
 			}
-			docs = append(docs, doc)
-		}
-		// Sort the documents.
-		// OrderByField is a single field, not a field path.
-		// First, put the field values in another slice, so we can
-		// return on error.
-		sortValues := make([]interface{}, len(docs))
-		for i, doc := range docs {
 			v, err := doc.GetField(orderByField)
 			if err != nil {
 				return nil, err
 			}
-			sortValues[i] = v
+			switch {
+			case keep == 0:
+				if maxDocs > 0 && len(ds.docs) == maxDocs {
+					return nil, gcerr.Newf(gcerr.ResourceExhausted, nil, "query matches more than the in-memory sort limit of %d documents", maxDocs)
+				}
+				ds.docs = append(ds.docs, doc)
+				ds.vals = append(ds.vals, v)
+			case len(ds.docs) < keep:
+				heap.Push(h, sortEntry{doc, v})
+			case ds.before(v, ds.vals[0]):
+				ds.docs[0], ds.vals[0] = doc, v
+				heap.Fix(h, 0)
+			}
+		}
+		sort.Sort(ds)
+		if offset > len(ds.docs) {
+			offset = len(ds.docs)
 		}
-		sort.Sort(docsForSorting{docs, sortValues, q.OrderAscending})
-		return &sliceIterator{docs: docs}, nil
+		return &sliceIterator{docs: ds.docs[offset:]}, nil
 	}
 }
 
@@ -605,14 +1159,41 @@ func (d docsForSorting) Swap(i, j int) {
 	d.vals[i], d.vals[j] = d.vals[j], d.vals[i]
 }
 
-func (d docsForSorting) Less(i, j int) bool {
-	c := compare(d.vals[i], d.vals[j])
+func (d docsForSorting) Less(i, j int) bool { return d.before(d.vals[i], d.vals[j]) }
+
+// before reports whether a document with the value v1 comes before one with
+// the value v2.
+func (d docsForSorting) before(v1, v2 interface{}) bool {
+	c := compare(v1, v2)
 	if d.ascending {
 		return c < 0
 	}
 	return c > 0
 }
 
+// A sortHeap is a heap of documents whose root is the one that comes last.
+type sortHeap struct{ *docsForSorting }
+
+type sortEntry struct {
+	doc driver.Document
+	val interface{}
+}
+
+func (h sortHeap) Less(i, j int) bool { return h.docsForSorting.Less(j, i) }
+
+func (h sortHeap) Push(x interface{}) {
+	e := x.(sortEntry)
+	h.docs = append(h.docs, e.doc)
+	h.vals = append(h.vals, e.val)
+}
+
+func (h sortHeap) Pop() interface{} {
+	n := len(h.docs) - 1
+	e := sortEntry{h.docs[n], h.vals[n]}
+	h.docs, h.vals = h.docs[:n], h.vals[:n]
+	return e
+}
+
 // compare returns -1 if v1 < v2, 0 if v1 == v2 and 1 if v1 > v2.
 //
 // Arbitrarily decide that strings < times < []byte < numbers.
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
 )
 
 func TestPlanQuery(t *testing.T) {
@@ -80,6 +82,7 @@ func TestPlanQuery(t *testing.T) {
 		query                   *driver.Query
 		want                    interface{} // either a ScanInput or a QueryInput
 		wantPlan                string
+		wantHidden              []string // the projected attributes left out of documents
 	}{
 		{
 			desc: "empty query",
@@ -317,11 +320,12 @@ func TestPlanQuery(t *testing.T) {
 			want: &dynamodb.QueryInput{
 				IndexName:                 aws.String("global"),
 				KeyConditionExpression:    aws.String("(#0 = :0) AND (#1 <= :1)"),
-				ProjectionExpression:      aws.String("#2, #0"),
+				ProjectionExpression:      aws.String("#2, #0, #1"),
 				ExpressionAttributeNames:  eans("tableP", "globalS", "other"),
 				ExpressionAttributeValues: eavs(2),
 			},
-			wantPlan: `Index: "global"`,
+			wantPlan:   `Index: "global"`,
+			wantHidden: []string{"globalS"},
 		},
 	} {
 		t.Run(test.desc, func(t *testing.T) {
@@ -376,10 +380,117 @@ func TestPlanQuery(t *testing.T) {
 			if diff := cmp.Diff(gotPlan, test.wantPlan); diff != "" {
 				t.Error("plan:\n", diff)
 			}
+			if diff := cmp.Diff(gotRunner.hidden, test.wantHidden); diff != "" {
+				t.Error("hidden:\n", diff)
+			}
+		})
+	}
+}
+
+func TestSortKeyConditions(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "P",
+		sortKey:      "S",
+		description:  &dynamodb.TableDescription{},
+		opts:         &Options{},
+	}
+	p := driver.Filter{FieldPath: []string{"P"}, Op: "=", Value: "p"}
+	sf := func(op string, v interface{}) driver.Filter {
+		return driver.Filter{FieldPath: []string{"S"}, Op: op, Value: v}
+	}
+	for _, test := range []struct {
+		desc           string
+		filters        []driver.Filter
+		wantKey        string
+		wantKeyFilters int
+	}{
+		{"equality", []driver.Filter{p, sf("=", "a"), sf(">", "b")}, "(#0 = :0) AND (#1 = :1)", 1},
+		{"range", []driver.Filter{p, sf(">", "a")}, "(#0 = :0) AND (#1 > :1)", 0},
+		{"exclusive bounds", []driver.Filter{p, sf(">", "a"), sf("<", "b")}, "(#0 = :0) AND (#1 > :1)", 1},
+		{"between", []driver.Filter{p, sf(">=", 1), sf("<=", 5)}, "(#0 = :0) AND (#1 BETWEEN :1 AND :2)", 0},
+		{"prefix", []driver.Filter{p, sf(">=", "ab"), sf("<", "ac")}, "(#0 = :0) AND (begins_with (#1, :1))", 0},
+		{"not a prefix", []driver.Filter{p, sf(">=", "ab"), sf("<", "ad")}, "(#0 = :0) AND (#1 >= :1)", 1},
+		{"in", []driver.Filter{p, sf("in", []interface{}{"c", "a", "b"})}, "(#0 = :0) AND (#1 BETWEEN :1 AND :2)", 1},
+		{"in mixed types", []driver.Filter{p, sf("in", []interface{}{"a", 1})}, "#0 = :0", 1},
+		{"not-in", []driver.Filter{p, sf("not-in", []interface{}{"a"})}, "#0 = :0", 1},
+		{"in on partition key", []driver.Filter{p, {FieldPath: []string{"P"}, Op: "in", Value: []interface{}{"p", "q"}}}, "#0 = :0", 1},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			qr, err := c.planQuery(&driver.Query{Filters: test.filters})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if qr.queryIn == nil {
+				t.Fatal("got a scan, want a query")
+			}
+			if got := aws.StringValue(qr.queryIn.KeyConditionExpression); got != test.wantKey {
+				t.Errorf("key condition: got %q, want %q", got, test.wantKey)
+			}
+			if qr.queryIn.FilterExpression != nil {
+				t.Errorf("got filter expression %q, want none", *qr.queryIn.FilterExpression)
+			}
+			if got := len(qr.keyFilters); got != test.wantKeyFilters {
+				t.Errorf("got %d key filters, want %d", got, test.wantKeyFilters)
+			}
 		})
 	}
 }
 
+func TestKeyFilterMatch(t *testing.T) {
+	c := &collection{opts: &Options{}}
+	item := avmap{
+		"S": new(dynamodb.AttributeValue).SetS("b"),
+		"N": new(dynamodb.AttributeValue).SetN("10"),
+	}
+	for _, test := range []struct {
+		f    driver.Filter
+		want bool
+	}{
+		{driver.Filter{FieldPath: []string{"S"}, Op: ">", Value: "a"}, true},
+		{driver.Filter{FieldPath: []string{"S"}, Op: "<", Value: "b"}, false},
+		{driver.Filter{FieldPath: []string{"S"}, Op: "in", Value: []interface{}{"a", "b"}}, true},
+		{driver.Filter{FieldPath: []string{"S"}, Op: "not-in", Value: []interface{}{"a", "b"}}, false},
+		{driver.Filter{FieldPath: []string{"S"}, Op: "=", Value: 1}, false},
+		// Numbers compare numerically, not as strings.
+		{driver.Filter{FieldPath: []string{"N"}, Op: ">", Value: 9}, true},
+		{driver.Filter{FieldPath: []string{"N"}, Op: "=", Value: 10.0}, true},
+		{driver.Filter{FieldPath: []string{"missing"}, Op: "=", Value: 1}, false},
+		{driver.Filter{FieldPath: []string{"missing"}, Op: "not-in", Value: []interface{}{1}}, true},
+	} {
+		kf, err := c.newKeyFilter(test.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := kf.match(item); got != test.want {
+			t.Errorf("%v %s %v: got %t, want %t", test.f.FieldPath, test.f.Op, test.f.Value, got, test.want)
+		}
+	}
+
+	// The iterator skips the items that do not match.
+	qr := &queryRunner{c: c, keyFilters: []keyFilter{{field: "S", op: "=", vals: []*dynamodb.AttributeValue{new(dynamodb.AttributeValue).SetS("b")}}}}
+	it := &documentIterator{qr: qr, items: []avmap{
+		{"S": new(dynamodb.AttributeValue).SetS("a")},
+		{"S": new(dynamodb.AttributeValue).SetS("b")},
+		{"S": new(dynamodb.AttributeValue).SetS("c")},
+	}}
+	var got []string
+	for {
+		m := map[string]interface{}{}
+		err := it.Next(context.Background(), drivertest.MustDocument(m))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m["S"].(string))
+	}
+	if !cmp.Equal(got, []string{"b"}) {
+		t.Errorf("got %v, want [b]", got)
+	}
+}
+
 func TestQueryNoScans(t *testing.T) {
 	c := &collection{
 		table:        "T",
@@ -410,6 +521,36 @@ func TestQueryNoScans(t *testing.T) {
 	}
 }
 
+func TestQueryTimeFormat(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "tableP",
+		description:  &dynamodb.TableDescription{},
+		opts:         &Options{AllowScans: true, TimeFormat: TimeUnixMillis},
+	}
+	t1 := time.UnixMilli(1000)
+	t2 := time.UnixMilli(2000)
+	q := &driver.Query{Filters: []driver.Filter{
+		{FieldPath: []string{"a"}, Op: ">", Value: t1},
+		{FieldPath: []string{"b"}, Op: "in", Value: []time.Time{t1, t2}},
+	}}
+	qr, err := c.planQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, av := range qr.scanIn.ExpressionAttributeValues {
+		if av.N == nil {
+			t.Fatalf("got %v, want a number", av)
+		}
+		got = append(got, *av.N)
+	}
+	want := []string{"1000", "1000", "2000"}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("filter values mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // Make a key schema from the names of the partition and sort keys.
 func keySchema(pkey, skey string) []*dynamodb.KeySchemaElement {
 	return []*dynamodb.KeySchemaElement{
@@ -418,24 +559,6 @@ func keySchema(pkey, skey string) []*dynamodb.KeySchemaElement {
 	}
 }
 
-func indexProjection(fields []string) *dynamodb.Projection {
-	var ptype string
-	switch {
-	case fields == nil:
-		ptype = "ALL"
-	case len(fields) == 0:
-		ptype = "KEYS_ONLY"
-	default:
-		ptype = "INCLUDE"
-	}
-	proj := &dynamodb.Projection{ProjectionType: &ptype}
-	for _, f := range fields {
-		f := f
-		proj.NonKeyAttributes = append(proj.NonKeyAttributes, &f)
-	}
-	return proj
-}
-
 func TestGlobalFieldsIncluded(t *testing.T) {
 	c := &collection{partitionKey: "tableP", sortKey: "tableS"}
 	gi := &dynamodb.GlobalSecondaryIndexDescription{
@@ -604,7 +727,7 @@ func Test_documentIterator_Next(t *testing.T) {
 		{
 			name: "nextWithNoDecodeError",
 			fields: fields{
-				qr: &queryRunner{},
+				qr: &queryRunner{c: &collection{opts: &Options{}}},
 				items: []map[string]*dyn.AttributeValue{
 					{"key": {M: map[string]*dyn.AttributeValue{"key": {S: aws.String("value")}}}},
 				},
@@ -623,7 +746,7 @@ func Test_documentIterator_Next(t *testing.T) {
 		{
 			name: "nextWithDecodeError",
 			fields: fields{
-				qr: &queryRunner{},
+				qr: &queryRunner{c: &collection{opts: &Options{}}},
 				items: []map[string]*dyn.AttributeValue{
 					{"key": {M: nil}}, // set M to nil to trigger decode error
 				},
@@ -643,6 +766,7 @@ func Test_documentIterator_Next(t *testing.T) {
 			name: "nextWhereCurrIsGreaterThanOrEqualToItemsAndLastIsNotNil",
 			fields: fields{
 				qr: &queryRunner{
+					c:      &collection{opts: &Options{}},
 					scanIn: &dyn.ScanInput{},
 					// hack to return error from run
 					beforeRun: func(asFunc func(i interface{}) bool) error { return errors.New("invalid") },
@@ -679,3 +803,119 @@ func Test_documentIterator_Next(t *testing.T) {
 		})
 	}
 }
+
+func TestInMemorySortFallback(t *testing.T) {
+	ctx := context.Background()
+	// run returns documents with the values 5, 1, 4, 2 and 3 of field N, in
+	// that order.
+	run := func(_ context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+		if q.OrderByField != "" || q.Offset != 0 || q.Limit != 0 {
+			return nil, fmt.Errorf("got query %+v, want no order, offset or limit", q)
+		}
+		var docs []driver.Document
+		for _, n := range []int{5, 1, 4, 2, 3} {
+			doc, err := driver.NewDocument(map[string]interface{}{"N": n})
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+		return &sliceIterator{docs: docs}, nil
+	}
+	sorted := func(f FallbackFunc, q *driver.Query) ([]int, error) {
+		it, err := f(ctx, q, run)
+		if err != nil {
+			return nil, err
+		}
+		var got []int
+		for {
+			m := map[string]interface{}{}
+			doc, _ := driver.NewDocument(m)
+			if err := it.Next(ctx, doc); err == io.EOF {
+				return got, nil
+			} else if err != nil {
+				return nil, err
+			}
+			got = append(got, m["N"].(int))
+		}
+	}
+	for _, test := range []struct {
+		desc    string
+		maxDocs int
+		query   *driver.Query
+		want    []int
+		wantErr bool
+	}{
+		{"ascending", 0, &driver.Query{OrderByField: "N", OrderAscending: true}, []int{1, 2, 3, 4, 5}, false},
+		{"descending page", 0, &driver.Query{OrderByField: "N", Offset: 1, Limit: 2}, []int{4, 3}, false},
+		{"offset past end", 0, &driver.Query{OrderByField: "N", Offset: 6, Limit: 2}, nil, false},
+		{"bounded with limit", 3, &driver.Query{OrderByField: "N", OrderAscending: true, Offset: 1, Limit: 2}, []int{2, 3}, false},
+		{"bounded all", 5, &driver.Query{OrderByField: "N", OrderAscending: true}, []int{1, 2, 3, 4, 5}, false},
+		{"limit over bound", 2, &driver.Query{OrderByField: "N", Offset: 1, Limit: 2}, nil, true},
+		{"documents over bound", 4, &driver.Query{OrderByField: "N"}, nil, true},
+	} {
+		f := InMemorySortFallback(nil)
+		if test.maxDocs > 0 {
+			f = BoundedInMemorySortFallback(nil, test.maxDocs)
+		}
+		got, err := sorted(f, test.query)
+		if test.wantErr {
+			if gcerrors.Code(err) != gcerrors.ResourceExhausted {
+				t.Errorf("%s: got %v, want ResourceExhausted", test.desc, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "P",
+		sortKey:      "S",
+		description:  &dynamodb.TableDescription{},
+		opts:         &Options{AllowScans: true, RevisionField: "rev"},
+	}
+	f := func(name, op string, v interface{}) driver.Filter {
+		return driver.Filter{FieldPath: []string{name}, Op: op, Value: v}
+	}
+	for _, test := range []struct {
+		desc    string
+		filters []driver.Filter
+		want    *int64
+	}{
+		{"key condition only", []driver.Filter{f("P", "=", 1), f("S", ">", 1)}, aws.Int64(3)},
+		{"scan", nil, aws.Int64(3)},
+		{"filter expression", []driver.Filter{f("P", "=", 1), f("X", ">", 1)}, nil},
+		{"key filter", []driver.Filter{f("P", "=", 1), f("S", "in", []int{1, 2})}, nil},
+	} {
+		q := &driver.Query{Filters: test.filters}
+		if test.filters != nil {
+			// Scans are unordered.
+			q.OrderByField = "S"
+		}
+		qr, err := c.planQuery(q)
+		if err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		qr.setLimit(3)
+		var limit *int64
+		if qr.scanIn != nil {
+			limit = qr.scanIn.Limit
+		} else {
+			limit = qr.queryIn.Limit
+			if sif := qr.queryIn.ScanIndexForward; sif == nil || *sif {
+				t.Errorf("%s: got ScanIndexForward true, want descending", test.desc)
+			}
+		}
+		if !cmp.Equal(limit, test.want) {
+			t.Errorf("%s: got limit %v, want %v", test.desc, aws.Int64Value(limit), aws.Int64Value(test.want))
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/googleapis/gax-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"gocloud.dev/callopt"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/retry"
+	"gocloud.dev/internal/telemetry"
+)
+
+const pkgName = "gocloud.dev/docstore/awsdynamodb"
+
+var (
+	throttlesCounter = telemetry.Int64Counter(pkgName, "throttles",
+		"DynamoDB requests throttled, and batch requests that left items unprocessed.", "{request}")
+	retriesCounter = telemetry.Int64Counter(pkgName, "retries",
+		"DynamoDB requests retried.", "{request}")
+)
+
+// operationKey is the attribute of the metrics and span events of retries
+// that names the DynamoDB operation, such as "PutItem".
+var operationKey = attribute.Key("rpc.method")
+
+// throttlingCodes are the error codes with which DynamoDB throttles
+// requests, and the reasons with which it cancels throttled transactions.
+var throttlingCodes = map[string]bool{
+	dyn.ErrCodeProvisionedThroughputExceededException: true,
+	dyn.ErrCodeRequestLimitExceeded:                   true,
+	"ThrottlingException":                             true,
+	"ThrottlingError":                                 true,
+	"ProvisionedThroughputExceeded":                   true,
+}
+
+// errUnprocessedKeys is returned from a BatchGetItem attempt that left keys
+// unprocessed, to have them retried.
+var errUnprocessedKeys = errors.New("awsdynamodb: unprocessed keys")
+
+// errThrottledStatements is returned from a BatchExecuteStatement attempt
+// some of whose statements were throttled, to have them retried.
+var errThrottledStatements = errors.New("awsdynamodb: throttled statements")
+
+// isThrottle reports whether err is from a request, or a part of one, that
+// DynamoDB throttled.
+func isThrottle(err error) bool {
+	if err == errThrottledStatements {
+		return true
+	}
+	if tce, ok := err.(*dyn.TransactionCanceledException); ok {
+		for _, r := range tce.CancellationReasons {
+			if throttlingCodes[aws.StringValue(r.Code)] {
+				return true
+			}
+		}
+		return false
+	}
+	ae, ok := err.(awserr.Error)
+	return ok && throttlingCodes[ae.Code()]
+}
+
+// retryPolicy returns the policy that the requests made with ctx are retried
+// with: the callopt.RetryPolicy of ctx, or Options.RetryPolicy.
+func (c *collection) retryPolicy(ctx context.Context) gcerrors.RetryPolicy {
+	p := callopt.FromContext(ctx).RetryPolicy
+	if p == nil {
+		p = c.opts.RetryPolicy
+	}
+	return p.WithDefaults()
+}
+
+// call calls f, which makes a DynamoDB request for the operation op, until it
+// succeeds or the retry policy of ctx gives up. Throttled requests are
+// retried up to the policy's MaxAttempts, as are the other errors that its
+// IsRetryable accepts. Requests that f reports as having left keys
+// unprocessed with errUnprocessedKeys are retried until none are left, since
+// each makes progress.
+//
+// Each throttled request and retry is counted, and each retry is recorded as
+// an event of the span of ctx. With Options.AdaptiveRetry, the requests of
+// the collection are also slowed down while DynamoDB throttles them.
+func (c *collection) call(ctx context.Context, op string, f func() error) error {
+	p := c.retryPolicy(ctx)
+	bo := gax.Backoff{Initial: p.InitialBackoff, Max: p.MaxBackoff, Multiplier: p.Multiplier}
+	attrs := metric.WithAttributes(operationKey.String(op))
+	attempts := 0
+	isRetryable := func(err error) bool {
+		throttled := isThrottle(err)
+		if throttled || err == errUnprocessedKeys {
+			throttlesCounter.Add(ctx, 1, attrs)
+			if c.opts.AdaptiveRetry {
+				c.throttle.slowDown(p.InitialBackoff, p.MaxBackoff)
+			}
+		}
+		retryable := err == errUnprocessedKeys ||
+			((p.MaxAttempts < 0 || attempts < p.MaxAttempts) && (throttled || p.IsRetryable(err)))
+		if retryable {
+			retriesCounter.Add(ctx, 1, attrs)
+			trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+				operationKey.String(op),
+				attribute.Int("attempt", attempts),
+				attribute.String("error", err.Error())))
+		}
+		return retryable
+	}
+	return retry.Call(ctx, bo, isRetryable, func() error {
+		attempts++
+		if c.opts.AdaptiveRetry {
+			if err := c.throttle.wait(ctx); err != nil {
+				return err
+			}
+		}
+		err := f()
+		if err == nil && c.opts.AdaptiveRetry {
+			c.throttle.speedUp()
+		}
+		return err
+	})
+}
+
+// A throttle spreads out the requests of a collection while DynamoDB
+// throttles them. Every request waits for its delay before it is sent; each
+// throttled request doubles the delay, and each successful one halves it.
+// Unlike the backoff of a single call, the delay applies to all the
+// concurrent calls of the collection, so that they slow down together.
+type throttle struct {
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+// minThrottleDelay is the delay below which a throttle stops delaying
+// requests.
+const minThrottleDelay = time.Millisecond
+
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := t.delay
+	t.mu.Unlock()
+	if d == 0 {
+		return nil
+	}
+	return gax.Sleep(ctx, d)
+}
+
+func (t *throttle) slowDown(initial, max time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delay *= 2
+	if t.delay < initial {
+		t.delay = initial
+	}
+	if t.delay > max {
+		t.delay = max
+	}
+}
+
+func (t *throttle) speedUp() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delay /= 2
+	if t.delay < minThrottleDelay {
+		t.delay = 0
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gocloud.dev/callopt"
+	"gocloud.dev/docstore"
+	"gocloud.dev/gcerrors"
+)
+
+// fakeThrottling is a DynamoDB service that throttles the first f.throttles
+// PutItem calls, fails the items named "invalid" with a ValidationException,
+// and throttles the statements of a batch whose parameter is "b" the first
+// time they are sent.
+type fakeThrottling struct {
+	mu         sync.Mutex
+	throttles  int
+	puts       int
+	statements []int // the number of statements of each batch
+	throttledB bool
+}
+
+func (f *fakeThrottling) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var in struct {
+		Item       map[string]map[string]string
+		Statements []struct{ Parameters []map[string]string }
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	fail := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"__type":  "com.amazonaws.dynamodb.v20120810#" + code,
+			"message": code,
+		})
+	}
+	switch op := r.Header.Get("X-Amz-Target"); {
+	case strings.HasSuffix(op, ".PutItem"):
+		f.puts++
+		if in.Item["ID"]["S"] == "invalid" {
+			fail("ValidationException")
+			return
+		}
+		if f.puts <= f.throttles {
+			fail(dyn.ErrCodeProvisionedThroughputExceededException)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	case strings.HasSuffix(op, ".BatchExecuteStatement"):
+		f.statements = append(f.statements, len(in.Statements))
+		var responses []interface{}
+		for _, s := range in.Statements {
+			if s.Parameters[0]["S"] == "b" && !f.throttledB {
+				f.throttledB = true
+				responses = append(responses, map[string]interface{}{
+					"Error": map[string]string{"Code": "ThrottlingError", "Message": "throttled"},
+				})
+				continue
+			}
+			responses = append(responses, map[string]interface{}{})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"Responses": responses})
+	default:
+		http.Error(w, "unexpected "+op, http.StatusBadRequest)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	f := &fakeThrottling{throttles: 2}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(&collection{
+		db:           dyn.New(sess),
+		table:        "T",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts: &Options{
+			RevisionField: docstore.DefaultRevisionField,
+			RetryPolicy:   &gcerrors.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond},
+			AdaptiveRetry: true,
+		},
+	})
+	defer coll.Close()
+
+	// A put that is throttled twice succeeds on the third attempt.
+	if err := coll.Put(ctx, map[string]interface{}{"ID": "a"}); err != nil || f.puts != 3 {
+		t.Errorf("Put: got %v after %d calls, want success after 3", err, f.puts)
+	}
+
+	// A put that is throttled more often than the policy allows fails.
+	f.puts, f.throttles = 0, 5
+	pctx := callopt.WithRetryPolicy(ctx, &gcerrors.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	err = coll.Put(pctx, map[string]interface{}{"ID": "a"})
+	if gcerrors.Code(err) != gcerrors.ResourceExhausted || f.puts != 2 {
+		t.Errorf("Put: got %v after %d calls, want ResourceExhausted after 2", err, f.puts)
+	}
+
+	// Other errors are not retried.
+	f.puts, f.throttles = 0, 0
+	err = coll.Put(ctx, map[string]interface{}{"ID": "invalid"})
+	if gcerrors.Code(err) != gcerrors.InvalidArgument || f.puts != 1 {
+		t.Errorf("Put: got %v after %d calls, want InvalidArgument after 1", err, f.puts)
+	}
+
+	// Only the throttled statement of a batch is retried.
+	err = RunPartiQLBatch(ctx, coll, []*PartiQLStatement{
+		{Statement: `UPDATE "T" SET "X" = 1 WHERE "ID" = ?`, Parameters: []interface{}{"a"}},
+		{Statement: `UPDATE "T" SET "X" = 1 WHERE "ID" = ?`, Parameters: []interface{}{"b"}},
+	})
+	if err != nil {
+		t.Errorf("RunPartiQLBatch: %v", err)
+	}
+	if want := []int{2, 1}; !cmp.Equal(f.statements, want) {
+		t.Errorf("RunPartiQLBatch: got batches of %v statements, want %v", f.statements, want)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				op, _ := dp.Attributes.Value(operationKey)
+				got[m.Name+"/"+op.AsString()] = dp.Value
+			}
+		}
+	}
+	want := map[string]int64{
+		"gocdk.docstore.awsdynamodb.throttles/PutItem":               4,
+		"gocdk.docstore.awsdynamodb.retries/PutItem":                 3,
+		"gocdk.docstore.awsdynamodb.throttles/BatchExecuteStatement": 1,
+		"gocdk.docstore.awsdynamodb.retries/BatchExecuteStatement":   1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("metrics diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsThrottle(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("x"), false},
+		{errThrottledStatements, true},
+		{errUnprocessedKeys, false},
+		{&dyn.ProvisionedThroughputExceededException{}, true},
+		{&dyn.ConditionalCheckFailedException{}, false},
+		{&dyn.TransactionCanceledException{CancellationReasons: []*dyn.CancellationReason{
+			{Code: aws.String("None")}, {Code: aws.String("ThrottlingError")},
+		}}, true},
+		{&dyn.TransactionCanceledException{CancellationReasons: []*dyn.CancellationReason{
+			{Code: aws.String("ConditionalCheckFailed")},
+		}}, false},
+	} {
+		if got := isThrottle(test.err); got != test.want {
+			t.Errorf("isThrottle(%v) = %t, want %t", test.err, got, test.want)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var th throttle
+	th.slowDown(10*time.Millisecond, 25*time.Millisecond)
+	th.slowDown(10*time.Millisecond, 25*time.Millisecond)
+	if th.delay != 20*time.Millisecond {
+		t.Errorf("got delay %v, want 20ms", th.delay)
+	}
+	th.slowDown(10*time.Millisecond, 25*time.Millisecond)
+	if th.delay != 25*time.Millisecond {
+		t.Errorf("got delay %v, want the maximum of 25ms", th.delay)
+	}
+	for i := 0; i < 15; i++ {
+		th.speedUp()
+	}
+	if th.delay != 0 {
+		t.Errorf("got delay %v after successes, want 0", th.delay)
+	}
+}
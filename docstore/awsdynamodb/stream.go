@@ -0,0 +1,271 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	streams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+var (
+	// streamPollInterval is the time between the GetRecords calls on a shard
+	// that has no new records.
+	streamPollInterval = time.Second
+	// streamShardsInterval is the time between the checks for new shards.
+	streamShardsInterval = 10 * time.Second
+)
+
+// Watch implements driver.Watcher. It reads the table's DynamoDB stream.
+func (c *collection) Watch(ctx context.Context, opts *driver.WatchOptions) (driver.ChangeIterator, error) {
+	out, err := c.tableClient().DescribeTableWithContext(ctx, &dyn.DescribeTableInput{TableName: &c.table})
+	if err != nil {
+		return nil, err
+	}
+	spec := out.Table.StreamSpecification
+	if spec == nil || !aws.BoolValue(spec.StreamEnabled) || out.Table.LatestStreamArn == nil {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "table %s has no stream", c.table)
+	}
+	sc := c.opts.StreamsClient
+	if sc == nil {
+		// The stream is read with the configuration of the table client.
+		db, ok := c.tableClient().(*dyn.DynamoDB)
+		if !ok {
+			return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "Options.StreamsClient is required with a %T client", c.tableClient())
+		}
+		sess, err := session.NewSession(db.Config.Copy())
+		if err != nil {
+			return nil, err
+		}
+		sc = streams.New(sess)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	sr := &streamReader{
+		c:       c,
+		sc:      sc,
+		arn:     out.Table.LatestStreamArn,
+		latest:  !opts.FromOldest,
+		records: make(chan streamRecord),
+		done:    make(chan string),
+	}
+	go sr.run(ctx)
+	return &changeIterator{sr: sr, stop: cancel}, nil
+}
+
+// A streamRecord is a record read from a shard of a stream, or the error
+// that stopped the reading of the shard.
+type streamRecord struct {
+	rec *streams.Record
+	err error
+}
+
+// A streamReader reads the shards of a stream concurrently, each after its
+// parent, so that the records of an item are in order.
+type streamReader struct {
+	c       *collection
+	sc      *streams.DynamoDBStreams
+	arn     *string
+	latest  bool               // start with the shards' latest records, not their oldest
+	records chan streamRecord  // the records of all the shards
+	done    chan string        // the IDs of the shards that have been read entirely
+	started map[string]bool    // the IDs of the shards being read or read
+	ended   map[string]bool    // the IDs of the shards that have been read entirely
+	first   bool               // whether the first list of shards has been read
+	parents map[string]*string // the parent of each shard
+}
+
+// run starts reading the shards of the stream, and the shards that appear
+// later, until ctx is done.
+func (sr *streamReader) run(ctx context.Context) {
+	sr.started = map[string]bool{}
+	sr.ended = map[string]bool{}
+	sr.parents = map[string]*string{}
+	t := time.NewTicker(streamShardsInterval)
+	defer t.Stop()
+	for {
+		if err := sr.startShards(ctx); err != nil {
+			select {
+			case sr.records <- streamRecord{err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-sr.done:
+			sr.ended[id] = true
+		case <-t.C:
+		}
+	}
+}
+
+// startShards lists the shards of the stream and starts reading those whose
+// parent has been read.
+func (sr *streamReader) startShards(ctx context.Context) error {
+	var shards []*streams.Shard
+	in := &streams.DescribeStreamInput{StreamArn: sr.arn}
+	for {
+		out, err := sr.sc.DescribeStreamWithContext(ctx, in)
+		if err != nil {
+			return err
+		}
+		shards = append(shards, out.StreamDescription.Shards...)
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			break
+		}
+		in.ExclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+	for _, s := range shards {
+		sr.parents[aws.StringValue(s.ShardId)] = s.ParentShardId
+	}
+	for _, s := range shards {
+		id := aws.StringValue(s.ShardId)
+		if sr.started[id] {
+			continue
+		}
+		closed := s.SequenceNumberRange != nil && s.SequenceNumberRange.EndingSequenceNumber != nil
+		if !sr.first && sr.latest {
+			// Only the open shards have changes made after Watch.
+			sr.started[id] = true
+			if closed {
+				sr.ended[id] = true
+			} else {
+				go sr.readShard(ctx, id, streams.ShardIteratorTypeLatest)
+			}
+			continue
+		}
+		// A parent that has expired from the stream is not listed.
+		if p := sr.parents[id]; p != nil && !sr.ended[*p] {
+			if _, ok := sr.parents[*p]; ok {
+				continue
+			}
+		}
+		sr.started[id] = true
+		go sr.readShard(ctx, id, streams.ShardIteratorTypeTrimHorizon)
+	}
+	sr.first = true
+	return nil
+}
+
+// readShard sends the records of a shard, from the position that itype
+// describes, to sr.records, until the shard is closed or ctx is done.
+func (sr *streamReader) readShard(ctx context.Context, id, itype string) {
+	out, err := sr.sc.GetShardIteratorWithContext(ctx, &streams.GetShardIteratorInput{
+		StreamArn:         sr.arn,
+		ShardId:           aws.String(id),
+		ShardIteratorType: aws.String(itype),
+	})
+	if err != nil {
+		sr.send(ctx, streamRecord{err: err})
+		return
+	}
+	iter := out.ShardIterator
+	for iter != nil {
+		out, err := sr.sc.GetRecordsWithContext(ctx, &streams.GetRecordsInput{ShardIterator: iter})
+		if err != nil {
+			sr.send(ctx, streamRecord{err: err})
+			return
+		}
+		for _, rec := range out.Records {
+			if !sr.send(ctx, streamRecord{rec: rec}) {
+				return
+			}
+		}
+		iter = out.NextShardIterator
+		if iter != nil && len(out.Records) == 0 {
+			select {
+			case <-time.After(streamPollInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	select {
+	case sr.done <- id:
+	case <-ctx.Done():
+	}
+}
+
+// send sends r to sr.records. It returns false if ctx is done first.
+func (sr *streamReader) send(ctx context.Context, r streamRecord) bool {
+	select {
+	case sr.records <- r:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type changeIterator struct {
+	sr   *streamReader
+	stop func()
+}
+
+func (it *changeIterator) Next(ctx context.Context) (*driver.Change, error) {
+	select {
+	case r := <-it.sr.records:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return it.sr.c.change(r.rec), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (it *changeIterator) Stop() { it.stop() }
+
+// change returns the change that the stream record rec describes.
+func (c *collection) change(rec *streams.Record) *driver.Change {
+	decoder := func(item map[string]*dyn.AttributeValue) func(driver.Document) error {
+		if item == nil {
+			return nil
+		}
+		return func(doc driver.Document) error {
+			return decodeDoc(&dyn.AttributeValue{M: item}, doc, c.opts)
+		}
+	}
+	ch := &driver.Change{
+		AsFunc: func(i interface{}) bool {
+			p, ok := i.(**streams.Record)
+			if !ok {
+				return false
+			}
+			*p = rec
+			return true
+		},
+	}
+	switch aws.StringValue(rec.EventName) {
+	case streams.OperationTypeInsert:
+		ch.Kind = driver.ChangeCreate
+	case streams.OperationTypeModify:
+		ch.Kind = driver.ChangeUpdate
+	case streams.OperationTypeRemove:
+		ch.Kind = driver.ChangeDelete
+	}
+	if sr := rec.Dynamodb; sr != nil {
+		ch.DecodeKey = decoder(sr.Keys)
+		ch.DecodeOld = decoder(sr.OldImage)
+		ch.DecodeNew = decoder(sr.NewImage)
+	}
+	return ch
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	streams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"gocloud.dev/docstore"
+	"gocloud.dev/gcerrors"
+)
+
+const (
+	testStreamARN = "arn:aws:dynamodb:us-east-1:123456789012:table/T/stream/2026-01-01T00:00:00.000"
+	// The stream has a closed shard and its open child.
+	parentShard = "shardId-00000000000000000000-00000001"
+	childShard  = "shardId-00000000000000000000-00000002"
+)
+
+// fakeStream is a DynamoDB service with a table whose stream has a record
+// that creates and then updates an item in the parent shard, and a record
+// that deletes it in the child shard.
+type fakeStream struct {
+	noStream bool
+
+	mu        sync.Mutex
+	iterTypes map[string]string // the shard iterator types asked for, by shard
+}
+
+func (f *fakeStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	op := r.Header.Get("X-Amz-Target")
+	op = op[strings.Index(op, ".")+1:]
+	var in map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item := func(v string) map[string]interface{} {
+		return map[string]interface{}{"ID": map[string]string{"S": "a"}, "V": map[string]string{"N": v}}
+	}
+	record := func(event, seq string, oldImage, newImage map[string]interface{}) map[string]interface{} {
+		sr := map[string]interface{}{
+			"Keys":           map[string]interface{}{"ID": map[string]string{"S": "a"}},
+			"SequenceNumber": seq,
+		}
+		if oldImage != nil {
+			sr["OldImage"] = oldImage
+		}
+		if newImage != nil {
+			sr["NewImage"] = newImage
+		}
+		return map[string]interface{}{"eventName": event, "dynamodb": sr}
+	}
+	var out interface{}
+	switch op {
+	case "DescribeTable":
+		table := map[string]interface{}{"TableName": "T"}
+		if !f.noStream {
+			table["StreamSpecification"] = map[string]interface{}{"StreamEnabled": true, "StreamViewType": "NEW_AND_OLD_IMAGES"}
+			table["LatestStreamArn"] = testStreamARN
+		}
+		out = map[string]interface{}{"Table": table}
+	case "DescribeStream":
+		out = map[string]interface{}{"StreamDescription": map[string]interface{}{
+			"StreamArn": testStreamARN,
+			"Shards": []interface{}{
+				map[string]interface{}{
+					"ShardId": parentShard,
+					"SequenceNumberRange": map[string]string{
+						"StartingSequenceNumber": "100000000000000000001",
+						"EndingSequenceNumber":   "100000000000000000002",
+					},
+				},
+				map[string]interface{}{
+					"ShardId":             childShard,
+					"ParentShardId":       parentShard,
+					"SequenceNumberRange": map[string]string{"StartingSequenceNumber": "100000000000000000003"},
+				},
+			},
+		}}
+	case "GetShardIterator":
+		f.mu.Lock()
+		f.iterTypes[in["ShardId"]] = in["ShardIteratorType"]
+		f.mu.Unlock()
+		out = map[string]string{"ShardIterator": in["ShardId"] + "/" + in["ShardIteratorType"] + "/0"}
+	case "GetRecords":
+		parts := strings.Split(in["ShardIterator"], "/")
+		switch {
+		case parts[0] == parentShard:
+			// The parent shard is closed after its records.
+			out = map[string]interface{}{"Records": []interface{}{
+				record("INSERT", "100000000000000000001", nil, item("1")),
+				record("MODIFY", "100000000000000000002", item("1"), item("2")),
+			}}
+		case parts[1] == streams.ShardIteratorTypeTrimHorizon && parts[2] == "0":
+			out = map[string]interface{}{
+				"Records":           []interface{}{record("REMOVE", "100000000000000000003", item("2"), nil)},
+				"NextShardIterator": childShard + "/" + parts[1] + "/1",
+			}
+		default:
+			out = map[string]interface{}{"Records": []interface{}{}, "NextShardIterator": in["ShardIterator"]}
+		}
+	default:
+		http.Error(w, "unexpected "+op, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(out)
+}
+
+func newStreamCollection(t *testing.T, f *fakeStream) *docstore.Collection {
+	t.Helper()
+	f.iterTypes = map[string]string{}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(&collection{
+		db:           dyn.New(sess),
+		table:        "T",
+		partitionKey: "ID",
+		description:  &dyn.TableDescription{},
+		opts:         &Options{RevisionField: docstore.DefaultRevisionField},
+	})
+	t.Cleanup(func() { coll.Close() })
+	return coll
+}
+
+func TestWatch(t *testing.T) {
+	defer func(d time.Duration) { streamPollInterval = d }(streamPollInterval)
+	streamPollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	coll := newStreamCollection(t, &fakeStream{})
+	iter := coll.Watch(ctx, &docstore.WatchOptions{FromOldest: true})
+	defer iter.Stop()
+
+	type doc struct {
+		ID string
+		V  int
+	}
+	// The child shard is read after its parent, so the changes are in order.
+	for _, want := range []struct {
+		kind     docstore.ChangeKind
+		old, new int // 0 for none
+	}{
+		{docstore.Created, 0, 1},
+		{docstore.Updated, 1, 2},
+		{docstore.Deleted, 2, 0},
+	} {
+		ch, err := iter.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch.Kind != want.kind {
+			t.Fatalf("got %s, want %s", ch.Kind, want.kind)
+		}
+		var key, old, new doc
+		if err := ch.Key(&key); err != nil || key.ID != "a" {
+			t.Errorf("%s: Key: got %+v, %v; want ID a", ch.Kind, key, err)
+		}
+		for _, v := range []struct {
+			name   string
+			decode func(docstore.Document) error
+			d      *doc
+			want   int
+		}{
+			{"Old", ch.Old, &old, want.old},
+			{"New", ch.New, &new, want.new},
+		} {
+			err := v.decode(v.d)
+			if v.want == 0 {
+				if gcerrors.Code(err) != gcerrors.NotFound {
+					t.Errorf("%s: %s: got %v, want NotFound", ch.Kind, v.name, err)
+				}
+			} else if err != nil || v.d.V != v.want {
+				t.Errorf("%s: %s: got %+v, %v; want V %d", ch.Kind, v.name, v.d, err, v.want)
+			}
+		}
+		var rec *streams.Record
+		if !ch.As(&rec) || rec.Dynamodb == nil {
+			t.Errorf("%s: As: got %v, want the stream record", ch.Kind, rec)
+		}
+	}
+}
+
+func TestWatchLatest(t *testing.T) {
+	f := &fakeStream{}
+	coll := newStreamCollection(t, f)
+	iter := coll.Watch(context.Background(), nil)
+	defer iter.Stop()
+
+	// Only the open shard is read, from its latest record.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f.mu.Lock()
+		got := map[string]string{}
+		for k, v := range f.iterTypes {
+			got[k] = v
+		}
+		f.mu.Unlock()
+		if len(got) > 0 {
+			if len(got) != 1 || got[childShard] != streams.ShardIteratorTypeLatest {
+				t.Errorf("got shard iterators %v, want a LATEST one for the child shard", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a shard iterator")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchNoStream(t *testing.T) {
+	coll := newStreamCollection(t, &fakeStream{noStream: true})
+	iter := coll.Watch(context.Background(), nil)
+	defer iter.Stop()
+	if _, err := iter.Next(context.Background()); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+}
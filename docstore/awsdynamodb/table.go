@@ -0,0 +1,284 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/googleapis/gax-go/v2"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/retry"
+)
+
+// A TableSchema describes a DynamoDB table for EnsureTable.
+type TableSchema struct {
+	// The partition key of the table. Required.
+	PartitionKey KeyAttribute
+	// The sort key of the table, if its Name is not empty.
+	SortKey KeyAttribute
+
+	// The local secondary indexes of the table. They can only be created with
+	// the table.
+	LocalIndexes []IndexSchema
+	// The global secondary indexes of the table.
+	GlobalIndexes []IndexSchema
+
+	// The view type of the table's DynamoDB stream, such as
+	// dynamodb.StreamViewTypeNewAndOldImages, for Collection.Watch. If empty,
+	// the table has no stream. It only applies when the table is created.
+	StreamView string
+
+	// The billing mode of the table: dynamodb.BillingModePayPerRequest, the
+	// default, or dynamodb.BillingModeProvisioned.
+	BillingMode string
+	// The provisioned read and write capacity units of the table and of each
+	// global index, with dynamodb.BillingModeProvisioned. They default to 5.
+	ReadCapacity, WriteCapacity int64
+}
+
+// A KeyAttribute is an attribute of the key of a table or index.
+type KeyAttribute struct {
+	Name string
+	// The type of the attribute: dynamodb.ScalarAttributeTypeS, the default,
+	// dynamodb.ScalarAttributeTypeN or dynamodb.ScalarAttributeTypeB.
+	Type string
+}
+
+// An IndexSchema describes a secondary index of a table.
+type IndexSchema struct {
+	Name string
+	// The partition key of the index. For a local index, it is the partition
+	// key of the table, and may be left empty.
+	PartitionKey KeyAttribute
+	// The sort key of the index. Required for a local index.
+	SortKey KeyAttribute
+	// The attributes projected into the index besides the keys. If nil, all
+	// attributes are projected; if empty, only the keys.
+	Attributes []string
+}
+
+// tableBackoff is the backoff between the calls that wait for a table and its
+// global indexes to become active.
+var tableBackoff = gax.Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second}
+
+// errNotActive is returned from a check of a table that is not yet active.
+var errNotActive = errors.New("awsdynamodb: table not active")
+
+// EnsureTable creates the table described by schema if it does not exist, or
+// else adds the global indexes of schema that the table does not have, and
+// waits until the table and its indexes are active. It is meant for tests and
+// development environments, such as DynamoDB Local, rather than for managing
+// production tables.
+//
+// EnsureTable fails with code FailedPrecondition if an existing table has
+// different keys, or lacks one of the local indexes of schema.
+func EnsureTable(ctx context.Context, db *dyn.DynamoDB, tableName string, schema *TableSchema) error {
+	if schema.PartitionKey.Name == "" {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "EnsureTable %s: no partition key", tableName)
+	}
+	out, err := db.DescribeTableWithContext(ctx, &dyn.DescribeTableInput{TableName: aws.String(tableName)})
+	if ae, ok := err.(awserr.Error); ok && ae.Code() == dyn.ErrCodeResourceNotFoundException {
+		_, err = db.CreateTableWithContext(ctx, createTableInput(tableName, schema))
+		// Another process may have created the table first.
+		if ae, ok := err.(awserr.Error); ok && ae.Code() == dyn.ErrCodeResourceInUseException {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+		return waitForTable(ctx, db, tableName)
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkTable(tableName, out.Table, schema); err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, gi := range out.Table.GlobalSecondaryIndexes {
+		have[aws.StringValue(gi.IndexName)] = true
+	}
+	// DynamoDB creates one global index per UpdateTable call, and no other
+	// while the table is being updated.
+	for _, gi := range schema.GlobalIndexes {
+		if have[gi.Name] {
+			continue
+		}
+		if err := waitForTable(ctx, db, tableName); err != nil {
+			return err
+		}
+		_, err := db.UpdateTableWithContext(ctx, &dyn.UpdateTableInput{
+			TableName:            aws.String(tableName),
+			AttributeDefinitions: attributeDefinitions(schema),
+			GlobalSecondaryIndexUpdates: []*dyn.GlobalSecondaryIndexUpdate{{
+				Create: &dyn.CreateGlobalSecondaryIndexAction{
+					IndexName:             aws.String(gi.Name),
+					KeySchema:             newKeySchema(gi.PartitionKey, gi.SortKey),
+					Projection:            indexProjection(gi.Attributes),
+					ProvisionedThroughput: throughput(schema),
+				},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return waitForTable(ctx, db, tableName)
+}
+
+// createTableInput returns the input of the CreateTable call that creates the
+// table described by schema.
+func createTableInput(tableName string, schema *TableSchema) *dyn.CreateTableInput {
+	in := &dyn.CreateTableInput{
+		TableName:             aws.String(tableName),
+		AttributeDefinitions:  attributeDefinitions(schema),
+		KeySchema:             newKeySchema(schema.PartitionKey, schema.SortKey),
+		BillingMode:           aws.String(dyn.BillingModePayPerRequest),
+		ProvisionedThroughput: throughput(schema),
+	}
+	if schema.BillingMode != "" {
+		in.BillingMode = aws.String(schema.BillingMode)
+	}
+	if schema.StreamView != "" {
+		in.StreamSpecification = &dyn.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String(schema.StreamView),
+		}
+	}
+	for _, li := range schema.LocalIndexes {
+		in.LocalSecondaryIndexes = append(in.LocalSecondaryIndexes, &dyn.LocalSecondaryIndex{
+			IndexName:  aws.String(li.Name),
+			KeySchema:  newKeySchema(schema.PartitionKey, li.SortKey),
+			Projection: indexProjection(li.Attributes),
+		})
+	}
+	for _, gi := range schema.GlobalIndexes {
+		in.GlobalSecondaryIndexes = append(in.GlobalSecondaryIndexes, &dyn.GlobalSecondaryIndex{
+			IndexName:             aws.String(gi.Name),
+			KeySchema:             newKeySchema(gi.PartitionKey, gi.SortKey),
+			Projection:            indexProjection(gi.Attributes),
+			ProvisionedThroughput: throughput(schema),
+		})
+	}
+	return in
+}
+
+// attributeDefinitions returns the definitions of the key attributes of the
+// table and indexes of schema.
+func attributeDefinitions(schema *TableSchema) []*dyn.AttributeDefinition {
+	var defs []*dyn.AttributeDefinition
+	seen := map[string]bool{}
+	add := func(ka KeyAttribute) {
+		if ka.Name == "" || seen[ka.Name] {
+			return
+		}
+		seen[ka.Name] = true
+		t := ka.Type
+		if t == "" {
+			t = dyn.ScalarAttributeTypeS
+		}
+		defs = append(defs, &dyn.AttributeDefinition{AttributeName: aws.String(ka.Name), AttributeType: aws.String(t)})
+	}
+	add(schema.PartitionKey)
+	add(schema.SortKey)
+	for _, li := range schema.LocalIndexes {
+		add(li.SortKey)
+	}
+	for _, gi := range schema.GlobalIndexes {
+		add(gi.PartitionKey)
+		add(gi.SortKey)
+	}
+	return defs
+}
+
+func newKeySchema(pkey, skey KeyAttribute) []*dyn.KeySchemaElement {
+	ks := []*dyn.KeySchemaElement{{AttributeName: aws.String(pkey.Name), KeyType: aws.String(dyn.KeyTypeHash)}}
+	if skey.Name != "" {
+		ks = append(ks, &dyn.KeySchemaElement{AttributeName: aws.String(skey.Name), KeyType: aws.String(dyn.KeyTypeRange)})
+	}
+	return ks
+}
+
+func indexProjection(attrs []string) *dyn.Projection {
+	switch {
+	case attrs == nil:
+		return &dyn.Projection{ProjectionType: aws.String(dyn.ProjectionTypeAll)}
+	case len(attrs) == 0:
+		return &dyn.Projection{ProjectionType: aws.String(dyn.ProjectionTypeKeysOnly)}
+	default:
+		return &dyn.Projection{ProjectionType: aws.String(dyn.ProjectionTypeInclude), NonKeyAttributes: aws.StringSlice(attrs)}
+	}
+}
+
+// throughput returns the provisioned throughput of the table and global
+// indexes of schema, or nil if they are billed per request.
+func throughput(schema *TableSchema) *dyn.ProvisionedThroughput {
+	if schema.BillingMode != dyn.BillingModeProvisioned {
+		return nil
+	}
+	pt := &dyn.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5)}
+	if schema.ReadCapacity > 0 {
+		pt.ReadCapacityUnits = aws.Int64(schema.ReadCapacity)
+	}
+	if schema.WriteCapacity > 0 {
+		pt.WriteCapacityUnits = aws.Int64(schema.WriteCapacity)
+	}
+	return pt
+}
+
+// checkTable checks that the existing table td has the keys and local
+// indexes of schema.
+func checkTable(tableName string, td *dyn.TableDescription, schema *TableSchema) error {
+	pkey, skey := keyAttributes(td.KeySchema)
+	if pkey != schema.PartitionKey.Name || skey != schema.SortKey.Name {
+		return gcerr.Newf(gcerr.FailedPrecondition, nil, "EnsureTable %s: table has keys %q and %q, want %q and %q",
+			tableName, pkey, skey, schema.PartitionKey.Name, schema.SortKey.Name)
+	}
+	have := map[string]bool{}
+	for _, li := range td.LocalSecondaryIndexes {
+		have[aws.StringValue(li.IndexName)] = true
+	}
+	for _, li := range schema.LocalIndexes {
+		if !have[li.Name] {
+			return gcerr.Newf(gcerr.FailedPrecondition, nil, "EnsureTable %s: table has no local index %q, which can only be created with the table",
+				tableName, li.Name)
+		}
+	}
+	return nil
+}
+
+// waitForTable waits until the table and all its global indexes are active.
+func waitForTable(ctx context.Context, db *dyn.DynamoDB, tableName string) error {
+	return retry.Call(ctx, tableBackoff, func(err error) bool { return err == errNotActive }, func() error {
+		out, err := db.DescribeTableWithContext(ctx, &dyn.DescribeTableInput{TableName: aws.String(tableName)})
+		if err != nil {
+			return err
+		}
+		if aws.StringValue(out.Table.TableStatus) != dyn.TableStatusActive {
+			return errNotActive
+		}
+		for _, gi := range out.Table.GlobalSecondaryIndexes {
+			if aws.StringValue(gi.IndexStatus) != dyn.IndexStatusActive {
+				return errNotActive
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gocloud.dev/gcerrors"
+)
+
+var testSchema = &TableSchema{
+	PartitionKey: KeyAttribute{Name: "Game"},
+	SortKey:      KeyAttribute{Name: "Player"},
+	LocalIndexes: []IndexSchema{
+		{Name: "local", SortKey: KeyAttribute{Name: "Score", Type: dyn.ScalarAttributeTypeN}, Attributes: []string{}},
+	},
+	GlobalIndexes: []IndexSchema{
+		{Name: "global", PartitionKey: KeyAttribute{Name: "Player"}, SortKey: KeyAttribute{Name: "Time"}, Attributes: []string{"Score"}},
+	},
+	BillingMode:  dyn.BillingModeProvisioned,
+	ReadCapacity: 10,
+}
+
+func TestCreateTableInput(t *testing.T) {
+	pt := &dyn.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)}
+	want := &dyn.CreateTableInput{
+		TableName: aws.String("T"),
+		AttributeDefinitions: []*dyn.AttributeDefinition{
+			{AttributeName: aws.String("Game"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Player"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Score"), AttributeType: aws.String("N")},
+			{AttributeName: aws.String("Time"), AttributeType: aws.String("S")},
+		},
+		KeySchema:             keySchema("Game", "Player"),
+		BillingMode:           aws.String(dyn.BillingModeProvisioned),
+		ProvisionedThroughput: pt,
+		LocalSecondaryIndexes: []*dyn.LocalSecondaryIndex{{
+			IndexName:  aws.String("local"),
+			KeySchema:  keySchema("Game", "Score"),
+			Projection: indexProjection([]string{}),
+		}},
+		GlobalSecondaryIndexes: []*dyn.GlobalSecondaryIndex{{
+			IndexName:             aws.String("global"),
+			KeySchema:             keySchema("Player", "Time"),
+			Projection:            indexProjection([]string{"Score"}),
+			ProvisionedThroughput: pt,
+		}},
+	}
+	got := createTableInput("T", testSchema)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(dyn.CreateTableInput{})); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+
+	// Tables are billed per request by default.
+	got = createTableInput("T", &TableSchema{PartitionKey: KeyAttribute{Name: "ID"}})
+	if aws.StringValue(got.BillingMode) != dyn.BillingModePayPerRequest || got.ProvisionedThroughput != nil || len(got.KeySchema) != 1 {
+		t.Errorf("got %v, want a table billed per request with a partition key", got)
+	}
+
+	got = createTableInput("T", &TableSchema{PartitionKey: KeyAttribute{Name: "ID"}, StreamView: dyn.StreamViewTypeNewAndOldImages})
+	if s := got.StreamSpecification; s == nil || !aws.BoolValue(s.StreamEnabled) || aws.StringValue(s.StreamViewType) != dyn.StreamViewTypeNewAndOldImages {
+		t.Errorf("got stream %v, want new and old images", s)
+	}
+}
+
+// fakeTables is a DynamoDB service that holds the description of one table,
+// which becomes active when it is described.
+type fakeTables struct {
+	mu    sync.Mutex
+	table map[string]interface{} // nil if there is no table
+	calls []string
+}
+
+func (f *fakeTables) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op := r.Header.Get("X-Amz-Target")
+	op = op[strings.Index(op, ".")+1:]
+	f.calls = append(f.calls, op)
+	var in map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	switch op {
+	case "DescribeTable":
+		if f.table == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazonaws.dynamodb.v20120810#ResourceNotFoundException"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"Table": f.table})
+		f.table["TableStatus"] = dyn.TableStatusActive
+		gis, _ := f.table["GlobalSecondaryIndexes"].([]interface{})
+		for _, gi := range gis {
+			gi.(map[string]interface{})["IndexStatus"] = dyn.IndexStatusActive
+		}
+	case "CreateTable":
+		f.table = in
+		f.table["TableStatus"] = dyn.TableStatusCreating
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	case "UpdateTable":
+		gis, _ := f.table["GlobalSecondaryIndexes"].([]interface{})
+		for _, u := range in["GlobalSecondaryIndexUpdates"].([]interface{}) {
+			gi := u.(map[string]interface{})["Create"].(map[string]interface{})
+			gi["IndexStatus"] = dyn.IndexStatusCreating
+			gis = append(gis, gi)
+		}
+		f.table["GlobalSecondaryIndexes"] = gis
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	default:
+		http.Error(w, "unexpected "+op, http.StatusBadRequest)
+	}
+}
+
+func TestEnsureTable(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeTables{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := dyn.New(sess)
+
+	check := func(desc string, want []string) {
+		t.Helper()
+		if !cmp.Equal(f.calls, want) {
+			t.Errorf("%s: got calls %v, want %v", desc, f.calls, want)
+		}
+		f.calls = nil
+	}
+
+	// The table does not exist: create it with all its indexes.
+	schema := *testSchema
+	schema.GlobalIndexes = nil
+	if err := EnsureTable(ctx, db, "T", &schema); err != nil {
+		t.Fatal(err)
+	}
+	// The new table is active at the second check.
+	check("create", []string{"DescribeTable", "CreateTable", "DescribeTable", "DescribeTable"})
+
+	// The table exists: add the missing global index.
+	if err := EnsureTable(ctx, db, "T", testSchema); err != nil {
+		t.Fatal(err)
+	}
+	check("add index", []string{"DescribeTable", "DescribeTable", "UpdateTable", "DescribeTable", "DescribeTable"})
+
+	// The table is up to date.
+	if err := EnsureTable(ctx, db, "T", testSchema); err != nil {
+		t.Fatal(err)
+	}
+	check("up to date", []string{"DescribeTable", "DescribeTable"})
+
+	// The table has other keys, or lacks a local index.
+	for _, schema := range []*TableSchema{
+		{PartitionKey: KeyAttribute{Name: "Game"}},
+		{PartitionKey: KeyAttribute{Name: "Game"}, SortKey: KeyAttribute{Name: "Player"}, LocalIndexes: []IndexSchema{{Name: "other"}}},
+	} {
+		if err := EnsureTable(ctx, db, "T", schema); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+			t.Errorf("%+v: got %v, want FailedPrecondition", schema, err)
+		}
+	}
+	if err := EnsureTable(ctx, db, "T", &TableSchema{}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("no partition key: got %v, want InvalidArgument", err)
+	}
+}
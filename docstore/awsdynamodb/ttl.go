@@ -0,0 +1,89 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// encodeTTL encodes v, the value of a TTL field, as a number of seconds since
+// the Unix epoch, as DynamoDB Time to Live requires. It returns nil if v is nil
+// or the zero time, meaning that the document does not expire.
+func encodeTTL(v interface{}) (*dyn.AttributeValue, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		if t.IsZero() {
+			return nil, nil
+		}
+		return encodeTime(t, TimeUnixSeconds), nil
+	case *time.Time:
+		if t == nil {
+			return nil, nil
+		}
+		return encodeTTL(*t)
+	}
+	rv := reflect.ValueOf(v)
+	if !isNumberKind(rv.Kind()) {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "TTL field value %v of type %[1]T is not a time.Time or a number", v)
+	}
+	var e encoder
+	if err := driver.Encode(rv, &e); err != nil {
+		return nil, err
+	}
+	return e.av, nil
+}
+
+// EnableTTL turns on DynamoDB Time to Live for a table, so that DynamoDB
+// deletes each item some time after the time in its field attribute has
+// passed. Open the table's collection with Options.TTLField set to field, so
+// that the times are written as DynamoDB requires.
+//
+// See https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/TTL.html.
+func EnableTTL(ctx context.Context, db *dyn.DynamoDB, tableName, field string) error {
+	_, err := db.UpdateTimeToLiveWithContext(ctx, &dyn.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &dyn.TimeToLiveSpecification{
+			AttributeName: aws.String(field),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	return err
+}
+
+// DescribeTTL returns the Time to Live attribute of a table, and its status:
+// one of the dynamodb.TimeToLiveStatus constants, such as "ENABLED". field is
+// empty if Time to Live has never been enabled.
+func DescribeTTL(ctx context.Context, db *dyn.DynamoDB, tableName string) (field, status string, err error) {
+	out, err := db.DescribeTimeToLiveWithContext(ctx, &dyn.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	d := out.TimeToLiveDescription
+	if d == nil {
+		return "", dyn.TimeToLiveStatusDisabled, nil
+	}
+	return aws.StringValue(d.AttributeName), aws.StringValue(d.TimeToLiveStatus), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+)
+
+func TestTTLField(t *testing.T) {
+	type doc struct {
+		ID      string
+		Expires time.Time
+		Created time.Time
+	}
+	tm := time.Unix(1767323045, 0)
+	opts := &Options{TTLField: "Expires", TimeFormat: TimeUnixMillis}
+
+	av, err := encodeDoc(drivertest.MustDocument(&doc{ID: "a", Expires: tm, Created: tm}), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := av.M["Expires"]; got.N == nil || *got.N != "1767323045" {
+		t.Errorf("Expires: got %v, want epoch seconds", got)
+	}
+	if got := av.M["Created"]; got.N == nil || *got.N != "1767323045000" {
+		t.Errorf("Created: got %v, want epoch milliseconds", got)
+	}
+	var got doc
+	if err := decodeDoc(av, drivertest.MustDocument(&got), opts); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Expires.Equal(tm) || !got.Created.Equal(tm) {
+		t.Errorf("got %+v, want times %v", got, tm)
+	}
+
+	// The zero time means no expiry.
+	av, err = encodeDoc(drivertest.MustDocument(&doc{ID: "a"}), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := av.M["Expires"]; ok {
+		t.Errorf("zero Expires: got %v, want no attribute", av.M["Expires"])
+	}
+
+	// Numbers are stored as they are; other types are errors.
+	av, err = encodeDoc(drivertest.MustDocument(map[string]interface{}{"ID": "a", "Expires": 12}), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := av.M["Expires"]; got.N == nil || *got.N != "12" {
+		t.Errorf("numeric Expires: got %v, want 12", got)
+	}
+	if _, err := encodeDoc(drivertest.MustDocument(map[string]interface{}{"ID": "a", "Expires": "soon"}), opts); err == nil {
+		t.Error("string Expires: got nil error, want error")
+	}
+}
+
+func TestTTLUpdate(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "ID",
+		opts:         &Options{TTLField: "Expires", RevisionField: "rev"},
+	}
+	for _, test := range []struct {
+		value      interface{}
+		wantUpdate string
+		wantValue  string
+	}{
+		{time.Unix(100, 0), "SET", "100"},
+		{time.Time{}, "REMOVE", ""},
+	} {
+		a := &driver.Action{
+			Kind: driver.Update,
+			Doc:  drivertest.MustDocument(map[string]interface{}{"ID": "a"}),
+			Mods: []driver.Mod{{FieldPath: []string{"Expires"}, Value: test.value}},
+		}
+		op, err := c.newUpdate(a, &driver.RunActionsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		up := op.writeItem.Update
+		if got := *up.UpdateExpression; !strings.HasPrefix(got, test.wantUpdate) {
+			t.Errorf("%v: got update %q, want %s", test.value, got, test.wantUpdate)
+		}
+		if test.wantValue == "" {
+			continue
+		}
+		var found bool
+		for _, v := range up.ExpressionAttributeValues {
+			if v.N != nil && *v.N == test.wantValue {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%v: got values %v, want a number %s", test.value, up.ExpressionAttributeValues, test.wantValue)
+		}
+	}
+}
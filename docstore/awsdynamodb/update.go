@@ -0,0 +1,191 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"regexp"
+	"strings"
+
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// ListAppend returns a modification that appends values to the list in a
+// field, or sets the field to a list of the values if it is absent. It is the
+// same as docstore.ListAppend.
+func ListAppend(values ...interface{}) interface{} {
+	return driver.ListAppendOp{Values: values}
+}
+
+// ListPrepend is like ListAppend, but inserts the values at the beginning of
+// the list.
+func ListPrepend(values ...interface{}) interface{} {
+	return listAppend{values: values, prepend: true}
+}
+
+type listAppend struct {
+	values  []interface{}
+	prepend bool
+}
+
+// pathComponent matches a component of an update's field path: an attribute
+// name, optionally followed by list indexes, as in "Tags" or "Grid[1][2]".
+var pathComponent = regexp.MustCompile(`^[^\[\]]+((\[\d+\])*)$`)
+
+// pathElements splits the field path of an update into the names and list
+// indexes that it is made of, so that "Grid[1].X" has the elements "Grid",
+// "[1]" and "X".
+func pathElements(fp []string) ([]string, error) {
+	var elems []string
+	for _, c := range fp {
+		m := pathComponent.FindStringSubmatch(c)
+		if m == nil {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "invalid field path %q: bad list index in %q", strings.Join(fp, "."), c)
+		}
+		elems = append(elems, strings.TrimSuffix(c, m[1]))
+		for _, i := range strings.SplitAfter(m[1], "]") {
+			if i != "" {
+				elems = append(elems, i)
+			}
+		}
+	}
+	return elems, nil
+}
+
+// hasPrefix reports whether the path elements p begin with prefix.
+func hasPrefix(p, prefix []string) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// updateBuilder returns the update expression for mods. A mod's field path
+// names a nested attribute with its dot-separated components, and may index
+// into lists, as in "Profile.Addresses[0].City". A nil value removes the
+// attribute, an Increment adds to it, a ListAppend or ListPrepend
+// concatenates lists, and a SetAdd or SetRemove becomes an ADD or DELETE of a
+// set; any other value is set.
+func (c *collection) updateBuilder(mods []driver.Mod) (expression.UpdateBuilder, error) {
+	var ub expression.UpdateBuilder
+	// DynamoDB rejects updates of overlapping paths, like "Tags" and "Tags[0]".
+	paths := make([][]string, 0, len(mods))
+	for _, m := range mods {
+		elems, err := pathElements(m.FieldPath)
+		if err != nil {
+			return ub, err
+		}
+		for _, p := range paths {
+			if hasPrefix(elems, p) || hasPrefix(p, elems) {
+				return ub, gcerr.Newf(gcerr.InvalidArgument, nil, "field paths %q and %q overlap",
+					strings.Join(p, "."), strings.Join(m.FieldPath, "."))
+			}
+		}
+		paths = append(paths, elems)
+
+		fp := expression.Name(strings.Join(m.FieldPath, "."))
+		switch v := m.Value.(type) {
+		case nil:
+			ub = ub.Remove(fp)
+		case driver.IncOp:
+			ub = ub.Add(fp, expression.Value(v.Amount))
+		case driver.ListAppendOp:
+			ub, err = c.appendList(ub, fp, v.Values, false)
+			if err != nil {
+				return ub, err
+			}
+		case listAppend:
+			ub, err = c.appendList(ub, fp, v.values, v.prepend)
+			if err != nil {
+				return ub, err
+			}
+		case driver.SetAddOp:
+			set, err := c.encodeSet(v.Values)
+			if err != nil {
+				return ub, err
+			}
+			ub = ub.Add(fp, expression.Value(encodedValue{set}))
+		case driver.SetRemoveOp:
+			set, err := c.encodeSet(v.Values)
+			if err != nil {
+				return ub, err
+			}
+			ub = ub.Delete(fp, expression.Value(encodedValue{set}))
+		default:
+			if c.opts.TTLField != "" && len(m.FieldPath) == 1 && m.FieldPath[0] == c.opts.TTLField {
+				ttl, err := encodeTTL(m.Value)
+				if err != nil {
+					return ub, err
+				}
+				if ttl == nil {
+					ub = ub.Remove(fp)
+				} else {
+					ub = ub.Set(fp, expression.Value(encodedValue{ttl}))
+				}
+				continue
+			}
+			av, err := encodeUpdateValue(m.FieldPath, m.Value, c.opts)
+			if err != nil {
+				return ub, err
+			}
+			ub = ub.Set(fp, expression.Value(encodedValue{av}))
+		}
+	}
+	return ub, nil
+}
+
+// appendList adds to ub the concatenation of the encoded values to the list
+// at fp, before it if prepend is true.
+func (c *collection) appendList(ub expression.UpdateBuilder, fp expression.NameBuilder, values []interface{}, prepend bool) (expression.UpdateBuilder, error) {
+	l := &dyn.AttributeValue{L: []*dyn.AttributeValue{}}
+	for _, x := range values {
+		av, err := encodeValue(x, c.opts)
+		if err != nil {
+			return ub, err
+		}
+		l.L = append(l.L, av)
+	}
+	// An absent list is treated as an empty one.
+	old := expression.IfNotExists(fp, expression.Value(encodedValue{&dyn.AttributeValue{L: []*dyn.AttributeValue{}}}))
+	vals := expression.Value(encodedValue{l})
+	if prepend {
+		return ub.Set(fp, expression.ListAppend(vals, old)), nil
+	}
+	return ub.Set(fp, expression.ListAppend(old, vals)), nil
+}
+
+// encodeSet encodes the values of a SetAdd or SetRemove as a DynamoDB set.
+func (c *collection) encodeSet(values []interface{}) (*dyn.AttributeValue, error) {
+	l := make([]*dyn.AttributeValue, len(values))
+	for i, x := range values {
+		av, err := encodeValue(x, c.opts)
+		if err != nil {
+			return nil, err
+		}
+		l[i] = av
+	}
+	set, err := listAsSet(l)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "SetAdd or SetRemove")
+	}
+	return set, nil
+}
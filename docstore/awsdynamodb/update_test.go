@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsdynamodb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
+)
+
+func TestUpdateExpressions(t *testing.T) {
+	c := &collection{
+		table:        "T",
+		partitionKey: "ID",
+		opts:         &Options{RevisionField: "rev"},
+	}
+	for _, test := range []struct {
+		desc       string
+		mods       []driver.Mod
+		wantUpdate string
+		wantNames  []string
+	}{
+		{
+			desc:       "nested field",
+			mods:       []driver.Mod{{FieldPath: []string{"profile", "settings", "theme"}, Value: "dark"}},
+			wantUpdate: "SET #1.#2.#3 = :0\n",
+			wantNames:  []string{"profile", "settings", "theme"},
+		},
+		{
+			desc:       "list indexes",
+			mods:       []driver.Mod{{FieldPath: []string{"grid[1][2]", "x"}, Value: 1}},
+			wantUpdate: "SET #1[1][2].#2 = :0\n",
+			wantNames:  []string{"grid", "x"},
+		},
+		{
+			desc:       "remove nested field",
+			mods:       []driver.Mod{{FieldPath: []string{"profile", "tags[0]"}, Value: nil}},
+			wantUpdate: "REMOVE #1.#2[0]\n",
+			wantNames:  []string{"profile", "tags"},
+		},
+		{
+			desc:       "append",
+			mods:       []driver.Mod{{FieldPath: []string{"profile", "tags"}, Value: ListAppend("a", "b")}},
+			wantUpdate: "SET #1.#2 = list_append(if_not_exists(#1.#2, :0), :1)\n",
+			wantNames:  []string{"profile", "tags"},
+		},
+		{
+			desc:       "prepend",
+			mods:       []driver.Mod{{FieldPath: []string{"tags"}, Value: ListPrepend("a")}},
+			wantUpdate: "SET #1 = list_append(:0, if_not_exists(#1, :1))\n",
+			wantNames:  []string{"tags"},
+		},
+		{
+			desc:       "set add",
+			mods:       []driver.Mod{{FieldPath: []string{"tags"}, Value: driver.SetAddOp{Values: []interface{}{"a"}}}},
+			wantUpdate: "ADD #1 :0\n",
+			wantNames:  []string{"tags"},
+		},
+		{
+			desc:       "set remove",
+			mods:       []driver.Mod{{FieldPath: []string{"tags"}, Value: driver.SetRemoveOp{Values: []interface{}{1, 2}}}},
+			wantUpdate: "DELETE #1 :0\n",
+			wantNames:  []string{"tags"},
+		},
+	} {
+		a := &driver.Action{
+			Kind: driver.Update,
+			Doc:  drivertest.MustDocument(map[string]interface{}{"ID": "a"}),
+			Mods: test.mods,
+		}
+		op, err := c.newUpdate(a, &driver.RunActionsOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		up := op.writeItem.Update
+		if got := aws.StringValue(up.UpdateExpression); got != test.wantUpdate {
+			t.Errorf("%s: got update %q, want %q", test.desc, got, test.wantUpdate)
+		}
+		// #0 is the partition key, in the condition that the item exists.
+		var gotNames []string
+		for i := range test.wantNames {
+			gotNames = append(gotNames, aws.StringValue(up.ExpressionAttributeNames[fmt.Sprintf("#%d", i+1)]))
+		}
+		if !cmp.Equal(gotNames, test.wantNames) {
+			t.Errorf("%s: got names %v, want %v", test.desc, gotNames, test.wantNames)
+		}
+	}
+}
+
+func TestListAppendValues(t *testing.T) {
+	c := &collection{table: "T", partitionKey: "ID", opts: &Options{}}
+	a := &driver.Action{
+		Kind: driver.Update,
+		Doc:  drivertest.MustDocument(map[string]interface{}{"ID": "a"}),
+		Mods: []driver.Mod{{FieldPath: []string{"tags"}, Value: ListAppend("a", 2)}},
+	}
+	op, err := c.newUpdate(a, &driver.RunActionsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals := op.writeItem.Update.ExpressionAttributeValues
+	// :0 is the empty list for an absent field, :1 the appended values.
+	if got := vals[":0"]; got == nil || got.L == nil || len(got.L) != 0 {
+		t.Errorf("got %v, want an empty list", got)
+	}
+	got := vals[":1"]
+	if got == nil || len(got.L) != 2 || aws.StringValue(got.L[0].S) != "a" || aws.StringValue(got.L[1].N) != "2" {
+		t.Errorf("got %v, want the list [a, 2]", got)
+	}
+}
+
+func TestUpdateExpressionErrors(t *testing.T) {
+	c := &collection{table: "T", partitionKey: "ID", opts: &Options{}}
+	for _, mods := range [][]driver.Mod{
+		{{FieldPath: []string{"tags[x]"}, Value: 1}},
+		{{FieldPath: []string{"tags[0"}, Value: 1}},
+		{{FieldPath: []string{"[0]"}, Value: 1}},
+		{{FieldPath: []string{"tags"}, Value: 1}, {FieldPath: []string{"tags[0]"}, Value: 1}},
+		{{FieldPath: []string{"a", "b[1]", "c"}, Value: 1}, {FieldPath: []string{"a", "b[1]"}, Value: nil}},
+		{{FieldPath: []string{"tags"}, Value: ListAppend(func() {})}},
+		{{FieldPath: []string{"tags"}, Value: driver.SetAddOp{Values: []interface{}{"a", 1}}}},
+	} {
+		if _, err := c.updateBuilder(mods); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%v: got %v, want InvalidArgument", mods, err)
+		}
+	}
+}
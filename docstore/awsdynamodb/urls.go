@@ -19,6 +19,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go/aws/client"
@@ -71,6 +73,25 @@ const Scheme = "dynamodb"
 //   - sort_key: the path to the sort key of a table or an index.
 //   - allow_scans: if "true", allow table scans to be used for queries
 //   - consistent_read: if "true", a strongly consistent read is used whenever possible.
+//   - encode_sets: if "true", Go sets are encoded as DynamoDB sets; see Options.EncodeSets.
+//   - set_fields: a comma-separated list of field paths whose lists are encoded as
+//     DynamoDB sets; see Options.SetFields.
+//   - time_format: how times are stored: "rfc3339" (the default), "unix" (seconds),
+//     "unix_millis" or "unix_nano"; see Options.TimeFormat.
+//   - ttl_field: the field that holds the expiration time of a document; see
+//     Options.TTLField.
+//   - scan_segments: the number of segments of a scan, scanned in parallel; see
+//     Options.ScanSegments.
+//   - null_empty_strings: if "true", empty strings are stored as NULL; see
+//     Options.NullEmptyStrings.
+//   - partiql: if "true", queries are run as PartiQL statements; see
+//     Options.PartiQL.
+//   - version_revisions: if "true", revisions are version numbers; see
+//     Options.VersionRevisions.
+//   - adaptive_retry: if "true", the collection's requests slow down together
+//     while DynamoDB throttles them; see Options.AdaptiveRetry.
+//   - return_consumed_capacity: the consumed capacity that DynamoDB returns,
+//     "TOTAL", "INDEXES" or "NONE"; see Options.ReturnConsumedCapacity.
 //
 // See https://godoc.org/gocloud.dev/aws#ConfigFromURLParams for supported query
 // parameters for overriding the aws.Session from the URL.
@@ -99,13 +120,57 @@ func (o *URLOpener) processURL(u *url.URL) (db *dyn.DynamoDB, tableName, partiti
 	sortKey = q.Get("sort_key")
 	q.Del("sort_key")
 	opts = &Options{
-		AllowScans:     q.Get("allow_scans") == "true",
-		RevisionField:  q.Get("revision_field"),
-		ConsistentRead: q.Get("consistent_read") == "true",
+		AllowScans:       q.Get("allow_scans") == "true",
+		RevisionField:    q.Get("revision_field"),
+		ConsistentRead:   q.Get("consistent_read") == "true",
+		EncodeSets:       q.Get("encode_sets") == "true",
+		TTLField:         q.Get("ttl_field"),
+		NullEmptyStrings: q.Get("null_empty_strings") == "true",
+		PartiQL:          q.Get("partiql") == "true",
+		AdaptiveRetry:    q.Get("adaptive_retry") == "true",
+		VersionRevisions: q.Get("version_revisions") == "true",
+	}
+	if sf := q.Get("set_fields"); sf != "" {
+		opts.SetFields = strings.Split(sf, ",")
+	}
+	switch tf := q.Get("time_format"); tf {
+	case "", "rfc3339":
+		opts.TimeFormat = TimeRFC3339
+	case "unix":
+		opts.TimeFormat = TimeUnixSeconds
+	case "unix_millis":
+		opts.TimeFormat = TimeUnixMillis
+	case "unix_nano":
+		opts.TimeFormat = TimeUnixNano
+	default:
+		return nil, "", "", "", nil, fmt.Errorf("open collection %s: invalid time_format %q", u, tf)
+	}
+	if ss := q.Get("scan_segments"); ss != "" {
+		n, err := strconv.Atoi(ss)
+		if err != nil || n < 0 {
+			return nil, "", "", "", nil, fmt.Errorf("open collection %s: invalid scan_segments %q", u, ss)
+		}
+		opts.ScanSegments = n
+	}
+	switch rcc := q.Get("return_consumed_capacity"); rcc {
+	case "", dyn.ReturnConsumedCapacityTotal, dyn.ReturnConsumedCapacityIndexes, dyn.ReturnConsumedCapacityNone:
+		opts.ReturnConsumedCapacity = rcc
+	default:
+		return nil, "", "", "", nil, fmt.Errorf("open collection %s: invalid return_consumed_capacity %q", u, rcc)
 	}
 	q.Del("allow_scans")
 	q.Del("revision_field")
 	q.Del("consistent_read")
+	q.Del("encode_sets")
+	q.Del("set_fields")
+	q.Del("time_format")
+	q.Del("ttl_field")
+	q.Del("scan_segments")
+	q.Del("return_consumed_capacity")
+	q.Del("null_empty_strings")
+	q.Del("partiql")
+	q.Del("adaptive_retry")
+	q.Del("version_revisions")
 
 	tableName = u.Host
 	if tableName == "" {
@@ -38,6 +38,28 @@ func TestProcessURL(t *testing.T) {
 		{"dynamodb://docstore-test?partition_key=_kind&revision_field=123", false},
 		// Passing consistent read field.
 		{"dynamodb://docstore-test?partition_key=_kind&consistent_read=true", false},
+		// Set encodings.
+		{"dynamodb://docstore-test?partition_key=_kind&encode_sets=true&set_fields=Tags,Info.Aliases", false},
+		// Time formats.
+		{"dynamodb://docstore-test?partition_key=_kind&time_format=unix", false},
+		{"dynamodb://docstore-test?partition_key=_kind&time_format=unix_millis", false},
+		{"dynamodb://docstore-test?partition_key=_kind&time_format=rfc3339", false},
+		{"dynamodb://docstore-test?partition_key=_kind&time_format=bad", true},
+		// TTL field.
+		{"dynamodb://docstore-test?partition_key=_kind&ttl_field=expires_at", false},
+		// Scan segments.
+		{"dynamodb://docstore-test?partition_key=_kind&scan_segments=8", false},
+		{"dynamodb://docstore-test?partition_key=_kind&scan_segments=x", true},
+		{"dynamodb://docstore-test?partition_key=_kind&scan_segments=-1", true},
+		// Empty strings as NULL.
+		{"dynamodb://docstore-test?partition_key=_kind&null_empty_strings=true", false},
+		// PartiQL queries.
+		{"dynamodb://docstore-test?partition_key=_kind&partiql=true", false},
+		{"dynamodb://docstore-test?partition_key=_kind&adaptive_retry=true", false},
+		{"dynamodb://docstore-test?partition_key=_kind&version_revisions=true&revision_field=version", false},
+		// Consumed capacity.
+		{"dynamodb://docstore-test?partition_key=_kind&return_consumed_capacity=INDEXES", false},
+		{"dynamodb://docstore-test?partition_key=_kind&return_consumed_capacity=all", true},
 		// Unknown parameter.
 		{"dynamodb://docstore-test?partition_key=_kind&param=value", true},
 		// With path.
@@ -0,0 +1,308 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gocloud.dev/docstore/driver"
+)
+
+// Documents are encoded as the Go values that encoding/json marshals to the
+// JSON of a Cosmos DB item, and decoded from the values that encoding/json
+// unmarshals with UseNumber: numbers are json.Numbers.
+//
+// JSON has no binary values, so a []byte is stored as an object with a single
+// property, "$bytes", holding the bytes in base64. That way the bytes can be
+// told apart from a string when they are decoded into an interface{}.
+
+// bytesProperty is the property of an object that holds encoded bytes.
+const bytesProperty = "$bytes"
+
+// timeFormat is the format of encoded times. Times are stored in UTC with
+// all nine digits of their fraction of a second, so that the order of the
+// strings is the order of the times.
+const timeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+func encodeDoc(doc driver.Document) (map[string]interface{}, error) {
+	var e encoder
+	if err := doc.Encode(&e); err != nil {
+		return nil, err
+	}
+	return e.val.(map[string]interface{}), nil
+}
+
+func encodeValue(v interface{}) (interface{}, error) {
+	var e encoder
+	if err := driver.Encode(reflect.ValueOf(v), &e); err != nil {
+		return nil, err
+	}
+	return e.val, nil
+}
+
+type encoder struct {
+	val interface{}
+}
+
+func (e *encoder) EncodeNil()            { e.val = nil }
+func (e *encoder) EncodeBool(x bool)     { e.val = x }
+func (e *encoder) EncodeInt(x int64)     { e.val = x }
+func (e *encoder) EncodeUint(x uint64)   { e.val = x }
+func (e *encoder) EncodeFloat(x float64) { e.val = x }
+func (e *encoder) EncodeString(x string) { e.val = x }
+func (e *encoder) ListIndex(int)         { panic("impossible") }
+func (e *encoder) MapKey(string)         { panic("impossible") }
+
+func (e *encoder) EncodeBytes(x []byte) {
+	e.val = map[string]interface{}{bytesProperty: base64.StdEncoding.EncodeToString(x)}
+}
+
+var typeOfGoTime = reflect.TypeOf(time.Time{})
+
+func (e *encoder) EncodeSpecial(v reflect.Value) (bool, error) {
+	if v.Type() == typeOfGoTime {
+		e.val = v.Interface().(time.Time).UTC().Format(timeFormat)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (e *encoder) EncodeList(n int) driver.Encoder {
+	s := make([]interface{}, n)
+	e.val = s
+	return &listEncoder{s: s}
+}
+
+type listEncoder struct {
+	s []interface{}
+	encoder
+}
+
+func (e *listEncoder) ListIndex(i int) { e.s[i] = e.val }
+
+func (e *encoder) EncodeMap(n int) driver.Encoder {
+	m := make(map[string]interface{}, n)
+	e.val = m
+	return &mapEncoder{m: m}
+}
+
+type mapEncoder struct {
+	m map[string]interface{}
+	encoder
+}
+
+func (e *mapEncoder) MapKey(k string) { e.m[k] = e.val }
+
+////////////////////////////////////////////////////////////////
+
+// decodeDoc decodes the item m into doc. If fps is not empty, only the fields
+// at those paths are decoded.
+func decodeDoc(m map[string]interface{}, doc driver.Document, fps [][]string) error {
+	if len(fps) > 0 {
+		m = project(m, fps)
+	}
+	return doc.Decode(decoder{m})
+}
+
+// project returns a copy of m with only the fields at the paths fps.
+func project(m map[string]interface{}, fps [][]string) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, fp := range fps {
+		v, ok := getAtFieldPath(m, fp)
+		if !ok {
+			continue
+		}
+		dst := res
+		for _, f := range fp[:len(fp)-1] {
+			sub, ok := dst[f].(map[string]interface{})
+			if !ok {
+				sub = map[string]interface{}{}
+				dst[f] = sub
+			}
+			dst = sub
+		}
+		dst[fp[len(fp)-1]] = v
+	}
+	return res
+}
+
+type decoder struct {
+	val interface{}
+}
+
+func (d decoder) String() string {
+	return fmt.Sprint(d.val)
+}
+
+func (d decoder) AsNull() bool {
+	return d.val == nil
+}
+
+func (d decoder) AsBool() (bool, bool) {
+	b, ok := d.val.(bool)
+	return b, ok
+}
+
+func (d decoder) AsString() (string, bool) {
+	s, ok := d.val.(string)
+	return s, ok
+}
+
+func (d decoder) AsInt() (int64, bool) {
+	n, ok := d.val.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	if i, err := n.Int64(); err == nil {
+		return i, true
+	}
+	// Cosmos DB may write a whole float, like 2.0, with a fraction or exponent.
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func (d decoder) AsUint() (uint64, bool) {
+	n, ok := d.val.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return u, true
+	}
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+func (d decoder) AsFloat() (float64, bool) {
+	n, ok := d.val.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func (d decoder) AsBytes() ([]byte, bool) {
+	return asBytes(d.val)
+}
+
+// asBytes returns the bytes encoded in v, which is a "$bytes" object, or a
+// base64 string as other Cosmos DB clients write []byte.
+func asBytes(v interface{}) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok {
+		m, isMap := v.(map[string]interface{})
+		if !isMap || len(m) != 1 {
+			return nil, false
+		}
+		if s, ok = m[bytesProperty].(string); !ok {
+			return nil, false
+		}
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	return b, err == nil
+}
+
+func (d decoder) AsInterface() (interface{}, error) {
+	return toGoValue(d.val), nil
+}
+
+// toGoValue returns a copy of the decoded JSON value v in which numbers are
+// int64s if they are integers, and float64s otherwise, and "$bytes" objects
+// are []byte.
+func toGoValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = toGoValue(e)
+		}
+		return s
+	case map[string]interface{}:
+		if _, ok := v[bytesProperty]; ok && len(v) == 1 {
+			if b, ok := asBytes(v); ok {
+				return b
+			}
+		}
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = toGoValue(e)
+		}
+		return m
+	default:
+		// nil, bool and string.
+		return v
+	}
+}
+
+func (d decoder) ListLen() (int, bool) {
+	if s, ok := d.val.([]interface{}); ok {
+		return len(s), true
+	}
+	return 0, false
+}
+
+func (d decoder) DecodeList(f func(i int, d2 driver.Decoder) bool) {
+	for i, e := range d.val.([]interface{}) {
+		if !f(i, decoder{e}) {
+			return
+		}
+	}
+}
+
+func (d decoder) MapLen() (int, bool) {
+	if m, ok := d.val.(map[string]interface{}); ok {
+		return len(m), true
+	}
+	return 0, false
+}
+
+func (d decoder) DecodeMap(f func(key string, d2 driver.Decoder, _ bool) bool) {
+	for k, v := range d.val.(map[string]interface{}) {
+		if !f(k, decoder{v}, true) {
+			return
+		}
+	}
+}
+
+func (d decoder) AsSpecial(v reflect.Value) (bool, interface{}, error) {
+	if v.Type() == typeOfGoTime {
+		s, ok := d.val.(string)
+		if !ok {
+			return true, nil, fmt.Errorf("expected string field for time.Time, got %T", d.val)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return true, t, err
+	}
+	return false, nil, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore/drivertest"
+)
+
+type aStruct struct {
+	I int
+	U uint
+	F float64
+	S string
+	B []byte
+	T time.Time
+	L []int
+	M map[string]bool
+	P *string
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	s := "p"
+	in := &aStruct{
+		I: -3,
+		U: 1 << 60,
+		F: 2.5,
+		S: "str",
+		B: []byte{0, 1, 2},
+		T: time.Date(2019, time.March, 27, 1, 2, 3, 5*1e6+7, time.FixedZone("X", 3600)),
+		L: []int{4, 5},
+		M: map[string]bool{"a": true},
+		P: &s,
+	}
+	got := &aStruct{}
+	roundTrip(t, in, got)
+	if !got.T.Equal(in.T) {
+		t.Errorf("got time %v, want %v", got.T, in.T)
+	}
+	got.T = in.T
+	if diff := cmp.Diff(got, in); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCodecInterface(t *testing.T) {
+	in := map[string]interface{}{
+		"i": 3,
+		"f": 2.5,
+		"b": []byte("abc"),
+		"l": []interface{}{int64(1), "x", nil},
+		"m": map[string]interface{}{"n": 7},
+	}
+	got := map[string]interface{}{}
+	roundTrip(t, in, got)
+	want := map[string]interface{}{
+		"i": int64(3),
+		"f": 2.5,
+		"b": []byte("abc"),
+		"l": []interface{}{int64(1), "x", nil},
+		"m": map[string]interface{}{"n": int64(7)},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDecodeBase64String(t *testing.T) {
+	// Other Cosmos DB clients write []byte as base64 strings.
+	var got struct{ B []byte }
+	if err := decodeDoc(map[string]interface{}{"B": "YWJj"}, drivertest.MustDocument(&got), nil); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.B) != "abc" {
+		t.Errorf("got %q, want %q", got.B, "abc")
+	}
+}
+
+func TestTimeOrder(t *testing.T) {
+	// Encoded times order as strings in the order of the times.
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Nanosecond)
+	t3 := t2.In(time.FixedZone("X", -3600)).Add(time.Second)
+	e1, _ := encodeValue(t1)
+	e2, _ := encodeValue(t2)
+	e3, _ := encodeValue(t3)
+	if !(e1.(string) < e2.(string) && e2.(string) < e3.(string)) {
+		t.Errorf("got %v, %v, %v, want increasing", e1, e2, e3)
+	}
+}
+
+// roundTrip encodes in, marshals and unmarshals it as JSON, and decodes it
+// into out.
+func roundTrip(t *testing.T, in, out interface{}) {
+	t.Helper()
+	m, err := encodeDoc(drivertest.MustDocument(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dm map[string]interface{}
+	if err := jsonDecode(b, &dm); err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeDoc(dm, drivertest.MustDocument(out), nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,819 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azurecosmos provides a docstore implementation backed by the NoSQL
+// (SQL) API of Azure Cosmos DB. Use OpenCollection to construct a
+// *docstore.Collection for a Cosmos DB container.
+//
+// To use Cosmos DB through its API for MongoDB, use mongodocstore instead.
+//
+// # URLs
+//
+// For docstore.OpenCollection, azurecosmos registers for the scheme "cosmos".
+// The default URL opener connects to the account whose endpoint, like
+// "https://myaccount.documents.azure.com:443/", is in the environment variable
+// AZURE_COSMOS_ENDPOINT. It authenticates with the account key in
+// AZURE_COSMOS_KEY if that is set, and otherwise with
+// azidentity.DefaultAzureCredential.
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Keys
+//
+// A Cosmos DB item is identified by its "id", a string, together with the
+// value of its partition key. The key field of a Collection is stored as the
+// item's "id", so its values must be strings. With OpenCollectionWithIDFunc,
+// the "id" is computed from the document instead, and is not a document
+// field.
+//
+// The partition key field is a top-level document field, and the container's
+// partition key path must be "/" followed by its name. If it is the key
+// field, the path must be "/id". Every document passed to an action must
+// have the partition key field, and Update cannot change it.
+//
+// # Revisions
+//
+// Revisions are the ETags that Cosmos DB gives items. An item written from a
+// document with a revision field holds that field with a null value, and
+// reading the item sets the field to the item's ETag. A write of a document
+// whose revision field is not nil is conditional on the item's ETag being
+// the same.
+//
+// # Action Lists
+//
+// Each action is a request of its own. The actions of a list run
+// concurrently, at most Options.MaxOutstandingActionRPCs at a time. An Update
+// reads the item, applies the mods to it and replaces it if its ETag has not
+// changed, trying again if it has, unless the document has a revision.
+// The BeforeDo function is called before each request.
+//
+// # Queries
+//
+// Query filters become the WHERE clause of a Cosmos DB SQL query. A query
+// with an equality filter on the partition key field runs in that partition,
+// and its ordering and limit are also part of the SQL query. Other queries
+// run across partitions, which the Cosmos DB REST API cannot order. Their
+// results are ordered in memory, so an ordered query across partitions reads
+// all the documents that it matches.
+//
+// # Special Considerations
+//
+// Cosmos DB stores numbers as IEEE 754 doubles, so integers whose absolute
+// value is greater than 2^53 lose precision. Times are stored as RFC 3339
+// strings in UTC, with nanoseconds, so that their order is that of the
+// strings. Byte slices are stored as objects with a single "$bytes" property
+// holding the bytes in base64, so that they are not confused with strings;
+// base64 strings written by other clients can also be read into []byte.
+//
+// # As
+//
+// azurecosmos exposes the following types for As:
+//   - Collection: *Client
+//   - ActionList.BeforeDo: *http.Request
+//   - Query.BeforeQuery: *SQLQuery
+//   - Error: *azcore.ResponseError
+package azurecosmos // import "gocloud.dev/docstore/azurecosmos"
+
+// Cosmos DB REST API reference:
+// https://learn.microsoft.com/rest/api/cosmos-db/
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"go.opentelemetry.io/otel/attribute"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/telemetry"
+	"gocloud.dev/internal/useragent"
+)
+
+// apiVersion is the version of the Cosmos DB REST API that the Client uses.
+const apiVersion = "2018-12-31"
+
+// A Client sends requests to a Cosmos DB account.
+type Client struct {
+	endpoint string
+	pl       runtime.Pipeline
+}
+
+// ClientOptions contains the optional settings of a Client.
+type ClientOptions struct {
+	azcore.ClientOptions
+}
+
+// NewClient returns a Client for the account at endpoint, such as
+// "https://myaccount.documents.azure.com:443/", that authenticates with
+// Microsoft Entra ID tokens from cred.
+func NewClient(endpoint string, cred azcore.TokenCredential, opts *ClientOptions) (*Client, error) {
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	scope := u.Scheme + "://" + u.Hostname() + "/.default"
+	return newClient(u, &tokenPolicy{cred: cred, scopes: []string{scope}}, opts), nil
+}
+
+// NewClientWithKey returns a Client for the account at endpoint, such as
+// "https://myaccount.documents.azure.com:443/", that authenticates with the
+// account key key.
+func NewClientWithKey(endpoint, key string, opts *ClientOptions) (*Client, error) {
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	k, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("azurecosmos: invalid account key: %v", err)
+	}
+	return newClient(u, &keyPolicy{key: k}, opts), nil
+}
+
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("azurecosmos: invalid endpoint %q: %v", endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("azurecosmos: invalid endpoint %q: want a URL like https://myaccount.documents.azure.com:443/", endpoint)
+	}
+	return u, nil
+}
+
+func newClient(u *url.URL, auth policy.Policy, opts *ClientOptions) *Client {
+	var copts policy.ClientOptions
+	if opts != nil {
+		copts = opts.ClientOptions
+	}
+	if copts.Telemetry.ApplicationID == "" {
+		copts.Telemetry.ApplicationID = useragent.AzureUserAgentPrefix("docstore")
+	}
+	pl := runtime.NewPipeline("azurecosmos", "v1", runtime.PipelineOptions{PerRetry: []policy.Policy{auth}}, &copts)
+	return &Client{endpoint: u.Scheme + "://" + u.Host, pl: pl}
+}
+
+// A resource is the Cosmos DB resource that a request is for, as its
+// signature names it.
+type resource struct {
+	typ  string // "docs" for items
+	link string // like "dbs/mydb/colls/mycontainer", not escaped
+}
+
+// keyPolicy signs requests with an account key.
+type keyPolicy struct {
+	key []byte
+}
+
+func (p *keyPolicy) Do(req *policy.Request) (*http.Response, error) {
+	var r resource
+	req.OperationValue(&r)
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Raw().Header.Set("x-ms-date", date)
+	req.Raw().Header.Set("Authorization", keyAuthorization(p.key, req.Raw().Method, r, date))
+	return req.Next()
+}
+
+// keyAuthorization returns the Authorization header of a request with method
+// for r sent at date, signed with the account key key.
+// See https://learn.microsoft.com/rest/api/cosmos-db/access-control-on-cosmosdb-resources.
+func keyAuthorization(key []byte, method string, r resource, date string) string {
+	payload := strings.ToLower(method) + "\n" + strings.ToLower(r.typ) + "\n" + r.link + "\n" + strings.ToLower(date) + "\n\n"
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return url.QueryEscape("type=master&ver=1.0&sig=" + sig)
+}
+
+// tokenPolicy authorizes requests with Microsoft Entra ID tokens.
+type tokenPolicy struct {
+	cred   azcore.TokenCredential
+	scopes []string
+}
+
+func (p *tokenPolicy) Do(req *policy.Request) (*http.Response, error) {
+	tok, err := p.cred.GetToken(req.Raw().Context(), policy.TokenRequestOptions{Scopes: p.scopes})
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Raw().Header.Set("Authorization", url.QueryEscape("type=aad&ver=1.0&sig="+tok.Token))
+	return req.Next()
+}
+
+// A request is a request to the Cosmos DB REST API.
+type request struct {
+	method string
+	res    resource
+	path   string // the escaped URL path, without the leading slash
+	header http.Header
+	body   interface{} // marshaled as JSON, if not nil
+	// The content type of the body. Defaults to "application/json".
+	contentType string
+	beforeDo    func(asFunc func(interface{}) bool) error
+}
+
+// do sends r, and returns the response and its body if its status is one of
+// ok. Otherwise it returns an *azcore.ResponseError.
+func (c *Client) do(ctx context.Context, r *request, ok ...int) (*http.Response, []byte, error) {
+	req, err := runtime.NewRequest(ctx, r.method, c.endpoint+"/"+r.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.SetOperationValue(r.res)
+	h := req.Raw().Header
+	h.Set("x-ms-version", apiVersion)
+	for k, v := range r.header {
+		h[k] = v
+	}
+	if r.body != nil {
+		b, err := json.Marshal(r.body)
+		if err != nil {
+			return nil, nil, gcerr.Newf(gcerr.InvalidArgument, err, "encoding request body")
+		}
+		ct := r.contentType
+		if ct == "" {
+			ct = "application/json"
+		}
+		if err := req.SetBody(streaming.NopCloser(bytes.NewReader(b)), ct); err != nil {
+			return nil, nil, err
+		}
+	}
+	if r.beforeDo != nil {
+		if err := r.beforeDo(driver.AsFunc(req.Raw())); err != nil {
+			return nil, nil, err
+		}
+	}
+	resp, err := c.pl.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := runtime.Payload(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !runtime.HasStatusCode(resp, ok...) {
+		return nil, nil, runtime.NewResponseError(resp)
+	}
+	return resp, body, nil
+}
+
+// Options are optional arguments to the OpenCollection functions.
+type Options struct {
+	// The name of the field holding the document revision.
+	// Defaults to docstore.DefaultRevisionField.
+	RevisionField string
+
+	// The maximum number of concurrent requests made by a single call to
+	// ActionList.Do. If less than 1, there is no limit.
+	MaxOutstandingActionRPCs int
+}
+
+type collection struct {
+	client    *Client
+	db        string
+	container string
+	idField   string
+	idFunc    func(docstore.Document) interface{}
+	// The document field holding the partition key, or "" if it is the id.
+	partitionKey string
+	opts         *Options
+}
+
+// OpenCollection opens the Cosmos DB container named container, in the
+// database named db, for use with Docstore. idField is the name of the
+// document field stored as the item's "id"; if empty, it is "id".
+// partitionKey is the name of the document field holding the partition key;
+// if empty, it is idField.
+func OpenCollection(client *Client, db, container, idField, partitionKey string, opts *Options) (*docstore.Collection, error) {
+	if idField == "" {
+		idField = idProperty
+	}
+	c, err := newCollection(client, db, container, idField, nil, partitionKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	return docstore.NewCollection(c), nil
+}
+
+// OpenCollectionWithIDFunc is like OpenCollection, but the item's "id" is
+// computed from the document by idFunc, which must return a string, or nil
+// if the document is missing the information to construct it. partitionKey
+// must not be empty.
+func OpenCollectionWithIDFunc(client *Client, db, container string, idFunc func(docstore.Document) interface{}, partitionKey string, opts *Options) (*docstore.Collection, error) {
+	if partitionKey == "" {
+		return nil, errors.New("azurecosmos: OpenCollectionWithIDFunc requires a partition key field")
+	}
+	c, err := newCollection(client, db, container, "", idFunc, partitionKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	return docstore.NewCollection(c), nil
+}
+
+func newCollection(client *Client, db, container, idField string, idFunc func(docstore.Document) interface{}, partitionKey string, opts *Options) (*collection, error) {
+	if client == nil {
+		return nil, errors.New("azurecosmos: nil Client")
+	}
+	if db == "" || container == "" {
+		return nil, errors.New("azurecosmos: database and container names are required")
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.RevisionField == "" {
+		opts.RevisionField = docstore.DefaultRevisionField
+	}
+	if partitionKey == idField {
+		partitionKey = ""
+	}
+	return &collection{
+		client:       client,
+		db:           db,
+		container:    container,
+		idField:      idField,
+		idFunc:       idFunc,
+		partitionKey: partitionKey,
+		opts:         opts,
+	}, nil
+}
+
+// The names of item properties.
+const (
+	idProperty   = "id"
+	etagProperty = "_etag"
+)
+
+// systemProperties are the properties that Cosmos DB adds to items.
+var systemProperties = []string{"_rid", "_self", etagProperty, "_attachments", "_ts"}
+
+// Key implements driver.Collection.Key.
+func (c *collection) Key(doc driver.Document) (interface{}, error) {
+	var id interface{}
+	if c.idField != "" {
+		id, _ = doc.GetField(c.idField) // missing field is not an error
+	} else {
+		id = c.idFunc(doc.Origin)
+	}
+	if id == nil || driver.IsEmptyValue(reflect.ValueOf(id)) {
+		if c.idFunc != nil {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "missing document key")
+		}
+		return nil, nil
+	}
+	if _, ok := id.(string); !ok {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "document key %v is a %T, not a string", id, id)
+	}
+	return id, nil
+}
+
+// RevisionField implements driver.Collection.RevisionField.
+func (c *collection) RevisionField() string {
+	return c.opts.RevisionField
+}
+
+// collectionPath returns the URL path of the container, and its resource.
+func (c *collection) collectionPath() (string, resource) {
+	return "dbs/" + url.PathEscape(c.db) + "/colls/" + url.PathEscape(c.container),
+		resource{typ: "docs", link: "dbs/" + c.db + "/colls/" + c.container}
+}
+
+// itemsPath returns the URL path of the container's items, and the resource
+// of requests to create or query them.
+func (c *collection) itemsPath() (string, resource) {
+	path, res := c.collectionPath()
+	return path + "/docs", res
+}
+
+// itemPath returns the URL path and resource of the item with id.
+func (c *collection) itemPath(id string) (string, resource) {
+	path, res := c.collectionPath()
+	res.link += "/docs/" + id
+	return path + "/docs/" + url.PathEscape(id), res
+}
+
+// partitionKeyValue returns the encoded value of the partition key of doc,
+// whose id is id.
+func (c *collection) partitionKeyValue(doc driver.Document, id string) (interface{}, error) {
+	if c.partitionKey == "" {
+		return id, nil
+	}
+	v, err := doc.GetField(c.partitionKey)
+	if err != nil || v == nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "document has no partition key field %q", c.partitionKey)
+	}
+	ev, err := encodeValue(v)
+	if err != nil {
+		return nil, err
+	}
+	switch ev.(type) {
+	case string, bool, int64, uint64, float64:
+		return ev, nil
+	default:
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "partition key field %q holds a %T, not a string, number or bool", c.partitionKey, v)
+	}
+}
+
+// partitionKeyHeader returns the headers of a request for the item or items
+// with the partition key pk.
+func partitionKeyHeader(pk interface{}) http.Header {
+	b, _ := json.Marshal([]interface{}{pk}) // pk is a string, number or bool
+	h := http.Header{}
+	h.Set("x-ms-documentdb-partitionkey", string(b))
+	return h
+}
+
+// encodeItem encodes doc as the item with id.
+func (c *collection) encodeItem(doc driver.Document, id string) (map[string]interface{}, error) {
+	m, err := encodeDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc.HasField(c.opts.RevisionField) {
+		// Mark the item as having a revision, so that reads return its ETag.
+		m[c.opts.RevisionField] = nil
+	} else {
+		delete(m, c.opts.RevisionField)
+	}
+	if c.idField != "" {
+		delete(m, c.idField)
+	}
+	m[idProperty] = id
+	return m, nil
+}
+
+// toDocument turns the item m into the map of its document.
+func (c *collection) toDocument(m map[string]interface{}) {
+	etag := m[etagProperty]
+	for _, p := range systemProperties {
+		delete(m, p)
+	}
+	if c.idField != idProperty {
+		if c.idField != "" {
+			m[c.idField] = m[idProperty]
+		}
+		delete(m, idProperty)
+	}
+	if _, ok := m[c.opts.RevisionField]; ok {
+		m[c.opts.RevisionField] = etag
+	}
+}
+
+// decodeItem decodes the JSON of an item.
+func decodeItem(b []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("decoding item: %v", err)
+	}
+	return m, nil
+}
+
+// revision returns the revision of doc, or "" if it has none.
+func (c *collection) revision(doc driver.Document) (string, error) {
+	v, err := doc.GetField(c.opts.RevisionField)
+	if err != nil || v == nil {
+		return "", nil // no revision field, or a nil one
+	}
+	rev, ok := v.(string)
+	if !ok {
+		return "", gcerr.Newf(gcerr.InvalidArgument, nil, "revision field %s holds a %T, not a string", c.opts.RevisionField, v)
+	}
+	return rev, nil
+}
+
+// setRevision sets the revision field of doc, if it has one, to the ETag of
+// the item whose JSON is body.
+func (c *collection) setRevision(doc driver.Document, body []byte) error {
+	if !doc.HasField(c.opts.RevisionField) {
+		return nil
+	}
+	var item struct {
+		ETag string `json:"_etag"`
+	}
+	if err := json.Unmarshal(body, &item); err != nil {
+		return fmt.Errorf("decoding item: %v", err)
+	}
+	return doc.SetField(c.opts.RevisionField, item.ETag)
+}
+
+// RunActions implements driver.Collection.RunActions.
+func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	errs := make([]error, len(actions))
+	beforeGets, gets, writes, afterGets := driver.GroupActions(actions)
+	c.runActions(ctx, beforeGets, errs, opts)
+	done := make(chan struct{})
+	go func() {
+		c.runActions(ctx, writes, errs, opts)
+		close(done)
+	}()
+	c.runActions(ctx, gets, errs, opts)
+	<-done
+	c.runActions(ctx, afterGets, errs, opts)
+	return driver.NewActionListError(errs)
+}
+
+// runActions runs actions concurrently, and stores their errors in errs.
+func (c *collection) runActions(ctx context.Context, actions []*driver.Action, errs []error, opts *driver.RunActionsOptions) {
+	t := driver.NewThrottle(c.opts.MaxOutstandingActionRPCs)
+	for _, a := range actions {
+		a := a
+		t.Acquire()
+		go func() {
+			defer t.Release()
+			errs[a.Index] = c.runAction(ctx, a, opts)
+		}()
+	}
+	t.Wait()
+}
+
+func (c *collection) runAction(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	switch a.Kind {
+	case driver.Get:
+		return c.get(ctx, a, opts)
+	case driver.Create, driver.Replace, driver.Put:
+		return c.write(ctx, a, opts)
+	case driver.Update:
+		return c.update(ctx, a, opts)
+	case driver.Delete:
+		return c.delete(ctx, a, opts)
+	default:
+		return gcerr.Newf(gcerr.Internal, nil, "bad action %+v", a)
+	}
+}
+
+// readItem reads the item of the document of a.
+func (c *collection) readItem(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) (map[string]interface{}, error) {
+	id := a.Key.(string)
+	pk, err := c.partitionKeyValue(a.Doc, id)
+	if err != nil {
+		return nil, err
+	}
+	path, res := c.itemPath(id)
+	_, body, err := c.client.do(ctx, &request{
+		method:   http.MethodGet,
+		res:      res,
+		path:     path,
+		header:   partitionKeyHeader(pk),
+		beforeDo: opts.BeforeDo,
+	}, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return decodeItem(body)
+}
+
+func (c *collection) get(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	m, err := c.readItem(ctx, a, opts)
+	if err != nil {
+		return err
+	}
+	c.toDocument(m)
+	return decodeDoc(m, a.Doc, a.FieldPaths)
+}
+
+// withKey returns fps with the key field, if fps is not empty.
+func (c *collection) withKey(fps [][]string) [][]string {
+	if len(fps) == 0 || c.idField == "" {
+		return fps
+	}
+	return append([][]string{{c.idField}}, fps...)
+}
+
+// write runs a Create, Replace or Put.
+func (c *collection) write(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	id, _ := a.Key.(string)
+	newID := id == ""
+	if newID {
+		// Only a Create can lack a key.
+		id = driver.UniqueString()
+	}
+	item, err := c.encodeItem(a.Doc, id)
+	if err != nil {
+		return err
+	}
+	pk, err := c.partitionKeyValue(a.Doc, id)
+	if err != nil {
+		return err
+	}
+	rev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	r := &request{
+		method:   http.MethodPost,
+		header:   partitionKeyHeader(pk),
+		body:     item,
+		beforeDo: opts.BeforeDo,
+	}
+	switch {
+	case a.Kind == driver.Create:
+		r.path, r.res = c.itemsPath()
+	case a.Kind == driver.Put && rev == "":
+		r.path, r.res = c.itemsPath()
+		r.header.Set("x-ms-documentdb-is-upsert", "True")
+	default:
+		// A Replace, or a Put with a revision, which must also replace.
+		r.method = http.MethodPut
+		r.path, r.res = c.itemPath(id)
+		if rev != "" {
+			r.header.Set("If-Match", rev)
+		}
+	}
+	_, body, err := c.client.do(ctx, r, http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	if newID && c.idField != "" {
+		if err := a.Doc.SetField(c.idField, id); err != nil {
+			return err
+		}
+	}
+	return c.setRevision(a.Doc, body)
+}
+
+// maxUpdateAttempts is the number of times an Update reads and replaces an
+// item that changes in between.
+const maxUpdateAttempts = 5
+
+func (c *collection) update(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	rev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	id := a.Key.(string)
+	pk, err := c.partitionKeyValue(a.Doc, id)
+	if err != nil {
+		return err
+	}
+	path, res := c.itemPath(id)
+	for attempt := 1; ; attempt++ {
+		m, err := c.readItem(ctx, a, opts)
+		if err != nil {
+			return err
+		}
+		etag, _ := m[etagProperty].(string)
+		if rev != "" && rev != etag {
+			return gcerr.Newf(gcerr.FailedPrecondition, nil, "document with key %q has a different revision", id)
+		}
+		for _, p := range systemProperties {
+			delete(m, p)
+		}
+		if err := applyMods(m, a.Mods); err != nil {
+			return err
+		}
+		header := partitionKeyHeader(pk)
+		header.Set("If-Match", etag)
+		_, body, err := c.client.do(ctx, &request{
+			method:   http.MethodPut,
+			res:      res,
+			path:     path,
+			header:   header,
+			body:     m,
+			beforeDo: opts.BeforeDo,
+		}, http.StatusOK)
+		if err == nil {
+			return c.setRevision(a.Doc, body)
+		}
+		if rev != "" || c.ErrorCode(err) != gcerrors.FailedPrecondition || attempt == maxUpdateAttempts {
+			return err
+		}
+	}
+}
+
+func (c *collection) delete(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	rev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	id := a.Key.(string)
+	pk, err := c.partitionKeyValue(a.Doc, id)
+	if err != nil {
+		return err
+	}
+	path, res := c.itemPath(id)
+	header := partitionKeyHeader(pk)
+	if rev != "" {
+		header.Set("If-Match", rev)
+	}
+	_, _, err = c.client.do(ctx, &request{
+		method:   http.MethodDelete,
+		res:      res,
+		path:     path,
+		header:   header,
+		beforeDo: opts.BeforeDo,
+	}, http.StatusOK, http.StatusNoContent)
+	if c.ErrorCode(err) == gcerrors.NotFound {
+		if rev == "" {
+			// Deleting a document that does not exist is not an error.
+			return nil
+		}
+		return gcerr.Newf(gcerr.FailedPrecondition, err, "document with key %q does not exist", id)
+	}
+	return err
+}
+
+// RevisionToBytes implements driver.Collection.RevisionToBytes.
+func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	s, ok := rev.(string)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "revision %v of type %[1]T is not a string", rev)
+	}
+	return []byte(s), nil
+}
+
+// BytesToRevision implements driver.Collection.BytesToRevision.
+func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	return string(b), nil
+}
+
+// TelemetryAttributes implements telemetry.Attributer.
+func (c *collection) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.DBSystemKey.String("cosmosdb"),
+		telemetry.DBCollectionNameKey.String(c.container),
+		telemetry.DBNamespaceKey.String(c.db),
+	}
+}
+
+// As implements driver.Collection.As.
+func (c *collection) As(i interface{}) bool {
+	p, ok := i.(**Client)
+	if !ok {
+		return false
+	}
+	*p = c.client
+	return true
+}
+
+// ErrorAs implements driver.Collection.ErrorAs.
+func (c *collection) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Collection.ErrorCode.
+func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
+	if err == nil {
+		return gcerrors.OK
+	}
+	var gerr *gcerr.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code
+	}
+	var rerr *azcore.ResponseError
+	if !errors.As(err, &rerr) {
+		return gcerrors.Unknown
+	}
+	switch rerr.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return gcerrors.InvalidArgument
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return gcerrors.PermissionDenied
+	case http.StatusNotFound:
+		return gcerrors.NotFound
+	case http.StatusRequestTimeout:
+		return gcerrors.DeadlineExceeded
+	case http.StatusConflict:
+		return gcerrors.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return gcerrors.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return gcerrors.ResourceExhausted
+	case http.StatusInternalServerError:
+		return gcerrors.Internal
+	default:
+		return gcerrors.Unknown
+	}
+}
+
+// Close implements driver.Collection.Close.
+func (c *collection) Close() error { return nil }
@@ -0,0 +1,620 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
+)
+
+const (
+	testDB         = "docstore-test"
+	container1     = "docstore-test-1" // partitioned by id
+	container2     = "docstore-test-2" // partitioned by Game
+	testAccountKey = "dGVzdC1hY2NvdW50LWtleQ=="
+)
+
+// fakeServer is an in-memory implementation of the parts of the Cosmos DB
+// REST API that the driver uses. It checks the signature of each request,
+// and supports only the SQL queries that the driver writes.
+type fakeServer struct {
+	t   testing.TB
+	key []byte
+
+	mu         sync.Mutex
+	containers map[string]*fakeContainer // by "dbs/db/colls/coll"
+	etag       int
+}
+
+type fakeContainer struct {
+	partitionKey string // the partition key property
+	items        map[string]map[string]interface{}
+}
+
+// The number of items in a page of query results.
+const fakePageSize = 3
+
+func newFakeServer(t testing.TB) (*fakeServer, *httptest.Server) {
+	key, _ := base64.StdEncoding.DecodeString(testAccountKey)
+	f := &fakeServer{t: t, key: key, containers: map[string]*fakeContainer{}}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	return f, ts
+}
+
+// createContainer creates or empties the container coll of db, whose
+// partition key path is "/"+partitionKey.
+func (f *fakeServer) createContainer(db, coll, partitionKey string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers["dbs/"+db+"/colls/"+coll] = &fakeContainer{
+		partitionKey: partitionKey,
+		items:        map[string]map[string]interface{}{},
+	}
+}
+
+var itemPathRegexp = regexp.MustCompile(`^/(dbs/[^/]+/colls/[^/]+)/docs(?:/([^/]+))?$`)
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m := itemPathRegexp.FindStringSubmatch(r.URL.EscapedPath())
+	if m == nil {
+		fakeError(w, http.StatusNotFound, "NotFound", "no such resource")
+		return
+	}
+	collPath, _ := url.PathUnescape(m[1])
+	id, _ := url.PathUnescape(m[2])
+	link := collPath
+	if m[2] != "" {
+		link += "/docs/" + id
+	}
+	if r.Header.Get("x-ms-version") != apiVersion {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "bad x-ms-version")
+		return
+	}
+	want := keyAuthorization(f.key, r.Method, resource{typ: "docs", link: link}, r.Header.Get("x-ms-date"))
+	if r.Header.Get("Authorization") != want {
+		fakeError(w, http.StatusUnauthorized, "Unauthorized", "bad signature")
+		return
+	}
+	body, err := readJSON(r)
+	if err != nil {
+		fakeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	var pk interface{}
+	if h := r.Header.Get("x-ms-documentdb-partitionkey"); h != "" {
+		var pks []interface{}
+		if err := jsonDecode([]byte(h), &pks); err != nil || len(pks) != 1 {
+			fakeError(w, http.StatusBadRequest, "BadRequest", "bad partition key header")
+			return
+		}
+		pk = pks[0]
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.containers[collPath]
+	if c == nil {
+		fakeError(w, http.StatusNotFound, "NotFound", "no such container")
+		return
+	}
+	if r.Header.Get("x-ms-documentdb-isquery") == "True" {
+		f.query(w, r, c, pk, body)
+		return
+	}
+	if pk == nil {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "missing partition key")
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		f.create(w, r, c, pk, body)
+	case id == "":
+		fakeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	case r.Method == http.MethodGet:
+		item := c.items[storeKey(pk, id)]
+		if item == nil {
+			fakeError(w, http.StatusNotFound, "NotFound", "no such item")
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	case r.Method == http.MethodPut:
+		key := storeKey(pk, id)
+		old := c.items[key]
+		if old == nil {
+			fakeError(w, http.StatusNotFound, "NotFound", "no such item")
+			return
+		}
+		if im := r.Header.Get("If-Match"); im != "" && im != old[etagProperty] {
+			fakeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "etag mismatch")
+			return
+		}
+		item, ok := body.(map[string]interface{})
+		if !ok || item[idProperty] != id {
+			fakeError(w, http.StatusBadRequest, "BadRequest", "item id does not match URL")
+			return
+		}
+		f.store(w, http.StatusOK, c, pk, item)
+	case r.Method == http.MethodDelete:
+		key := storeKey(pk, id)
+		old := c.items[key]
+		if old == nil {
+			fakeError(w, http.StatusNotFound, "NotFound", "no such item")
+			return
+		}
+		if im := r.Header.Get("If-Match"); im != "" && im != old[etagProperty] {
+			fakeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "etag mismatch")
+			return
+		}
+		delete(c.items, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+func (f *fakeServer) create(w http.ResponseWriter, r *http.Request, c *fakeContainer, pk, body interface{}) {
+	item, ok := body.(map[string]interface{})
+	if !ok {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "body is not an object")
+		return
+	}
+	id, ok := item[idProperty].(string)
+	if !ok || id == "" {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "item has no id")
+		return
+	}
+	if c.items[storeKey(pk, id)] != nil {
+		if r.Header.Get("x-ms-documentdb-is-upsert") != "True" {
+			fakeError(w, http.StatusConflict, "Conflict", "item exists")
+			return
+		}
+		f.store(w, http.StatusOK, c, pk, item)
+		return
+	}
+	f.store(w, http.StatusCreated, c, pk, item)
+}
+
+// store stores item, whose partition key the request's header says is pk.
+func (f *fakeServer) store(w http.ResponseWriter, status int, c *fakeContainer, pk interface{}, item map[string]interface{}) {
+	if !jsonEqual(item[c.partitionKey], pk) {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "partition key of item does not match header")
+		return
+	}
+	for p := range item {
+		if strings.HasPrefix(p, "_") {
+			delete(item, p)
+		}
+	}
+	f.etag++
+	item[etagProperty] = fmt.Sprintf(`"%08d-0000-0000-0000-000000000000"`, f.etag)
+	item["_rid"] = "rid"
+	item["_self"] = "self"
+	item["_ts"] = json.Number("1700000000")
+	item["_attachments"] = "attachments/"
+	c.items[storeKey(pk, item[idProperty].(string))] = item
+	writeJSON(w, status, item)
+}
+
+var (
+	queryRegexp = regexp.MustCompile(`^SELECT \* FROM c(?: WHERE (.*?))?(?: ORDER BY (c(?:\["[^"]*"\])+) (ASC|DESC))?(?: OFFSET (\d+) LIMIT (\d+))?$`)
+	condRegexp  = regexp.MustCompile(`^(?:(NOT )?ARRAY_CONTAINS\((@p\d+), (c(?:\["[^"]*"\])+)\)|(c(?:\["[^"]*"\])+) (=|<|<=|>|>=) (@p\d+))$`)
+	propRegexp  = regexp.MustCompile(`\[("[^"]*")\]`)
+)
+
+func (f *fakeServer) query(w http.ResponseWriter, r *http.Request, c *fakeContainer, pk, body interface{}) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/query+json" {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "bad query request")
+		return
+	}
+	b, _ := json.Marshal(body)
+	var q SQLQuery
+	if err := jsonDecode(b, &q); err != nil {
+		fakeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+	if pk == nil && r.Header.Get("x-ms-documentdb-query-enablecrosspartition") != "True" {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "cross-partition query not enabled")
+		return
+	}
+	m := queryRegexp.FindStringSubmatch(q.Query)
+	if m == nil {
+		fakeError(w, http.StatusBadRequest, "BadRequest", "unsupported query "+q.Query)
+		return
+	}
+	if pk == nil && m[2] != "" {
+		// Like the Cosmos DB gateway, which cannot merge ordered results.
+		fakeError(w, http.StatusBadRequest, "BadRequest", "cross-partition ORDER BY")
+		return
+	}
+	params := map[string]interface{}{}
+	for _, p := range q.Parameters {
+		params[p.Name] = p.Value
+	}
+	var conds []func(map[string]interface{}) bool
+	if m[1] != "" {
+		for _, s := range strings.Split(m[1], " AND ") {
+			cond, err := parseCond(s, params)
+			if err != nil {
+				fakeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+				return
+			}
+			conds = append(conds, cond)
+		}
+	}
+	var items []map[string]interface{}
+	for _, item := range c.items {
+		if pk != nil && !jsonEqual(item[c.partitionKey], pk) {
+			continue
+		}
+		match := true
+		for _, cond := range conds {
+			if !cond(item) {
+				match = false
+				break
+			}
+		}
+		if match {
+			items = append(items, item)
+		}
+	}
+	// Return items in a fixed order, as Cosmos DB does.
+	sort.Slice(items, func(i, j int) bool { return items[i][idProperty].(string) < items[j][idProperty].(string) })
+	if m[2] != "" {
+		path := parseProp(m[2])
+		if len(path) != 1 {
+			fakeError(w, http.StatusBadRequest, "BadRequest", "unsupported ORDER BY")
+			return
+		}
+		sortItems(items, path[0], m[3] == "ASC")
+	}
+	if m[4] != "" {
+		offset, _ := strconv.Atoi(m[4])
+		limit, _ := strconv.Atoi(m[5])
+		items = items[min(offset, len(items)):]
+		items = items[:min(limit, len(items))]
+	}
+	start := 0
+	if cont := r.Header.Get("x-ms-continuation"); cont != "" {
+		start, _ = strconv.Atoi(cont)
+	}
+	end := min(start+fakePageSize, len(items))
+	if end < len(items) {
+		w.Header().Set("x-ms-continuation", strconv.Itoa(end))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_rid":      "rid",
+		"Documents": items[start:end],
+		"_count":    end - start,
+	})
+}
+
+func parseCond(s string, params map[string]interface{}) (func(map[string]interface{}) bool, error) {
+	m := condRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported condition %q", s)
+	}
+	if m[3] != "" {
+		list, ok := params[m[2]].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parameter %s is not an array", m[2])
+		}
+		path := parseProp(m[3])
+		not := m[1] != ""
+		return func(item map[string]interface{}) bool {
+			v, ok := getAtFieldPath(item, path)
+			found := false
+			for _, e := range list {
+				if ok && jsonEqual(v, e) {
+					found = true
+				}
+			}
+			return found != not
+		}, nil
+	}
+	path := parseProp(m[4])
+	op := m[5]
+	want, ok := params[m[6]]
+	if !ok {
+		return nil, fmt.Errorf("no parameter %s", m[6])
+	}
+	return func(item map[string]interface{}) bool {
+		v, ok := getAtFieldPath(item, path)
+		if !ok || rank(v) != rank(want) || rank(v) > 3 {
+			// Comparisons of different types are undefined.
+			return false
+		}
+		c := compareValues(map[string]interface{}{"v": v}, map[string]interface{}{"v": want}, "v")
+		switch op {
+		case "=":
+			return c == 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		default:
+			return c >= 0
+		}
+	}, nil
+}
+
+// parseProp returns the property path of an expression like c["a"]["b"].
+func parseProp(s string) []string {
+	var path []string
+	for _, m := range propRegexp.FindAllStringSubmatch(s, -1) {
+		var p string
+		json.Unmarshal([]byte(m[1]), &p)
+		path = append(path, p)
+	}
+	return path
+}
+
+func storeKey(pk interface{}, id string) string {
+	b, _ := json.Marshal(pk)
+	return string(b) + "\x00" + id
+}
+
+func jsonEqual(x, y interface{}) bool {
+	if _, ok := x.(json.Number); ok {
+		if _, ok := y.(json.Number); ok {
+			_, f1, _ := number(x)
+			_, f2, _ := number(y)
+			return f1 == f2
+		}
+	}
+	bx, err1 := json.Marshal(x)
+	by, err2 := json.Marshal(y)
+	return err1 == nil && err2 == nil && bytes.Equal(bx, by)
+}
+
+func readJSON(r *http.Request) (interface{}, error) {
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(r.Body); err != nil {
+		return nil, err
+	}
+	if b.Len() == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	if err := jsonDecode(b.Bytes(), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func jsonDecode(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, map[string]string{"code": code, "message": msg})
+}
+
+// newTestClient returns a Client for the fake server at ts, that does not
+// retry requests.
+func newTestClient(t testing.TB, ts *httptest.Server) *Client {
+	client, err := NewClientWithKey(ts.URL, testAccountKey, &ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+			Transport: ts.Client(),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+type harness struct {
+	server *fakeServer
+	client *Client
+}
+
+func (h *harness) MakeCollection(_ context.Context, kind drivertest.CollectionKind) (driver.Collection, error) {
+	switch kind {
+	case drivertest.SingleKey:
+		h.server.createContainer(testDB, container1, idProperty)
+		return newCollection(h.client, testDB, container1, drivertest.KeyField, nil, "", nil)
+	case drivertest.TwoKey:
+		h.server.createContainer(testDB, container2, "Game")
+		return newCollection(h.client, testDB, container2, "", drivertest.HighScoreKey, "Game", nil)
+	case drivertest.AltRev:
+		h.server.createContainer(testDB, container1, idProperty)
+		return newCollection(h.client, testDB, container1, drivertest.KeyField, nil, "",
+			&Options{RevisionField: drivertest.AlternateRevisionField})
+	case drivertest.NoRev:
+		h.server.createContainer(testDB, container1, idProperty)
+		return newCollection(h.client, testDB, container1, drivertest.KeyField, nil, "", nil)
+	default:
+		panic("bad kind")
+	}
+}
+
+func (*harness) BeforeDoTypes() []interface{} {
+	return []interface{}{&http.Request{}}
+}
+
+func (*harness) BeforeQueryTypes() []interface{} {
+	return []interface{}{&SQLQuery{}}
+}
+
+func (*harness) RevisionsEqual(rev1, rev2 interface{}) bool {
+	return rev1 == rev2
+}
+
+func (*harness) Close() {}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As"
+}
+
+func (verifyAs) CollectionCheck(coll *docstore.Collection) error {
+	var c *Client
+	if !coll.As(&c) {
+		return errors.New("Collection.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) QueryCheck(it *docstore.DocumentIterator) error {
+	return nil
+}
+
+func (verifyAs) ErrorCheck(c *docstore.Collection, err error) error {
+	var rerr *azcore.ResponseError
+	if !c.ErrorAs(err, &rerr) {
+		return fmt.Errorf("Collection.ErrorAs failed, got %T", err)
+	}
+	return nil
+}
+
+func TestConformance(t *testing.T) {
+	server, ts := newFakeServer(t)
+	client := newTestClient(t, ts)
+	newHarness := func(context.Context, *testing.T) (drivertest.Harness, error) {
+		return &harness{server: server, client: client}, nil
+	}
+	drivertest.RunConformanceTests(t, newHarness, nil, []drivertest.AsTest{verifyAs{}})
+}
+
+func TestKeyAuthorization(t *testing.T) {
+	// The example in
+	// https://learn.microsoft.com/rest/api/cosmos-db/access-control-on-cosmosdb-resources.
+	key, err := base64.StdEncoding.DecodeString("dsZQi3KtZmCv1ljt3VNWNm7sQUF1y5rJfC6kv5JiwvW0EndXdDku/dkKBp8/ufDToSxLzR4y+O/0H/t4bQtVNw==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := keyAuthorization(key, "GET", resource{typ: "dbs", link: "dbs/ToDoList"}, "Thu, 27 Apr 2017 00:51:12 GMT")
+	got, err = url.QueryUnescape(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "type=master&ver=1.0&sig=c09PEVJrgp2uQRkr934kFbTqhByc7TVr3OHyqlu+c+c="
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestUpdateRetry(t *testing.T) {
+	ctx := context.Background()
+	server, ts := newFakeServer(t)
+	server.createContainer(testDB, container1, idProperty)
+	dc, err := newCollection(newTestClient(t, ts), testDB, container1, "name", nil, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(dc)
+	defer coll.Close()
+	doc := map[string]interface{}{"name": "a", "n": 1, docstore.DefaultRevisionField: nil}
+	if err := coll.Put(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change the item between each read and replacement, until the last
+	// attempt.
+	var replaces int
+	beforeDo := func(asFunc func(interface{}) bool) error {
+		var r *http.Request
+		if !asFunc(&r) || r.Method != http.MethodPut {
+			return nil
+		}
+		replaces++
+		if replaces < maxUpdateAttempts {
+			server.mu.Lock()
+			server.etag++
+			server.containers["dbs/"+testDB+"/colls/"+container1].items[storeKey("a", "a")][etagProperty] = strconv.Itoa(server.etag)
+			server.mu.Unlock()
+		}
+		return nil
+	}
+	err = coll.Actions().BeforeDo(beforeDo).Update(map[string]interface{}{"name": "a"}, docstore.Mods{"n": docstore.Increment(1)}).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaces != maxUpdateAttempts {
+		t.Errorf("got %d replaces, want %d", replaces, maxUpdateAttempts)
+	}
+	got := map[string]interface{}{"name": "a"}
+	if err := coll.Get(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got["n"] != int64(2) {
+		t.Errorf("got n = %v, want 2", got["n"])
+	}
+
+	// An update of a document with a revision is not retried.
+	replaces = 0
+	doc = map[string]interface{}{"name": "a", docstore.DefaultRevisionField: got[docstore.DefaultRevisionField]}
+	err = coll.Actions().BeforeDo(beforeDo).Update(doc, docstore.Mods{"n": docstore.Increment(1)}).Do(ctx)
+	if gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+	if replaces != 1 {
+		t.Errorf("got %d replaces, want 1", replaces)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	c := &collection{}
+	for _, test := range []struct {
+		status int
+		want   gcerrors.ErrorCode
+	}{
+		{http.StatusBadRequest, gcerrors.InvalidArgument},
+		{http.StatusForbidden, gcerrors.PermissionDenied},
+		{http.StatusNotFound, gcerrors.NotFound},
+		{http.StatusConflict, gcerrors.AlreadyExists},
+		{http.StatusPreconditionFailed, gcerrors.FailedPrecondition},
+		{http.StatusTooManyRequests, gcerrors.ResourceExhausted},
+		{http.StatusBadGateway, gcerrors.Unknown},
+	} {
+		err := &azcore.ResponseError{StatusCode: test.status}
+		if got := c.ErrorCode(fmt.Errorf("wrapped: %w", err)); got != test.want {
+			t.Errorf("%d: got %v, want %v", test.status, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/azurecosmos"
+)
+
+func ExampleOpenCollection() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := azurecosmos.NewClient("https://my-account.documents.azure.com:443/", cred, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// The container's partition key path is "/id", so the partition key
+	// field is the key field, userID.
+	coll, err := azurecosmos.OpenCollection(client, "my-db", "my-container", "userID", "", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer coll.Close()
+}
+
+func ExampleOpenCollectionWithIDFunc() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	type HighScore struct {
+		Game   string
+		Player string
+	}
+
+	client, err := azurecosmos.NewClientWithKey("https://my-account.documents.azure.com:443/", "my-account-key", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The id of an item is constructed from the Game and Player fields. The
+	// container's partition key path is "/Game".
+	idFromDocument := func(doc docstore.Document) interface{} {
+		hs := doc.(*HighScore)
+		return hs.Game + "|" + hs.Player
+	}
+
+	coll, err := azurecosmos.OpenCollectionWithIDFunc(client, "my-db", "my-container", idFromDocument, "Game", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer coll.Close()
+}
+
+func Example_openCollectionFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/docstore/azurecosmos"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// docstore.OpenCollection creates a *docstore.Collection from a URL.
+	coll, err := docstore.OpenCollection(ctx, "cosmos://my-db/my-container?id_field=Player&partition_key=Game")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer coll.Close()
+}
@@ -0,0 +1,384 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gocloud.dev/docstore/driver"
+)
+
+// An SQLQuery is a Cosmos DB SQL query with parameters, as the REST API
+// sends it.
+type SQLQuery struct {
+	Query      string         `json:"query"`
+	Parameters []SQLParameter `json:"parameters"`
+}
+
+// An SQLParameter is a parameter of an SQLQuery.
+type SQLParameter struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// A queryPlan is how a driver.Query runs.
+type queryPlan struct {
+	sql SQLQuery
+	// The partition key of a single-partition query, or nil.
+	partitionKey interface{}
+	// For a cross-partition query, the ordering, offset and limit that are
+	// applied in memory.
+	orderBy        string
+	orderAscending bool
+	offset, limit  int
+}
+
+// planQuery returns the plan of q.
+func (c *collection) planQuery(q *driver.Query) (*queryPlan, error) {
+	p := &queryPlan{}
+	var conds []string
+	for _, f := range q.Filters {
+		name := fmt.Sprintf("@p%d", len(p.sql.Parameters))
+		v, err := encodeValue(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		p.sql.Parameters = append(p.sql.Parameters, SQLParameter{Name: name, Value: v})
+		field := c.property(f.FieldPath)
+		switch f.Op {
+		case "in":
+			conds = append(conds, fmt.Sprintf("ARRAY_CONTAINS(%s, %s)", name, field))
+		case "not-in":
+			conds = append(conds, fmt.Sprintf("NOT ARRAY_CONTAINS(%s, %s)", name, field))
+		default:
+			conds = append(conds, fmt.Sprintf("%s %s %s", field, f.Op, name))
+		}
+		if f.Op == driver.EqualOp && p.partitionKey == nil && c.isPartitionKey(f.FieldPath) {
+			switch v.(type) {
+			case string, bool, int64, uint64, float64:
+				p.partitionKey = v
+			}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("SELECT * FROM c")
+	if len(conds) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(conds, " AND "))
+	}
+	if p.partitionKey == nil {
+		p.orderBy = q.OrderByField
+		p.orderAscending = q.OrderAscending
+		p.offset = q.Offset
+		p.limit = q.Limit
+	} else {
+		if q.OrderByField != "" {
+			dir := "ASC"
+			if !q.OrderAscending {
+				dir = "DESC"
+			}
+			fmt.Fprintf(&sb, " ORDER BY %s %s", c.property([]string{q.OrderByField}), dir)
+		}
+		if q.Limit > 0 {
+			fmt.Fprintf(&sb, " OFFSET %d LIMIT %d", max(q.Offset, 0), q.Limit)
+		} else if q.Offset > 0 {
+			// Cosmos DB requires a LIMIT with an OFFSET.
+			p.offset = q.Offset
+		}
+	}
+	p.sql.Query = sb.String()
+	return p, nil
+}
+
+// property returns the SQL expression of the item property for the document
+// field at fp.
+func (c *collection) property(fp []string) string {
+	switch {
+	case len(fp) == 1 && fp[0] == c.idField:
+		fp = []string{idProperty}
+	case len(fp) == 1 && fp[0] == c.opts.RevisionField:
+		fp = []string{etagProperty}
+	}
+	var sb strings.Builder
+	sb.WriteString("c")
+	for _, f := range fp {
+		b, _ := json.Marshal(f) // strings always marshal
+		fmt.Fprintf(&sb, "[%s]", b)
+	}
+	return sb.String()
+}
+
+// isPartitionKey reports whether fp is the path of the partition key field.
+func (c *collection) isPartitionKey(fp []string) bool {
+	if len(fp) != 1 {
+		return false
+	}
+	if c.partitionKey == "" {
+		return c.idField != "" && fp[0] == c.idField
+	}
+	return fp[0] == c.partitionKey
+}
+
+// RunGetQuery implements driver.Collection.RunGetQuery.
+func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	p, err := c.planQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	if q.BeforeQuery != nil {
+		asFunc := func(i interface{}) bool {
+			sq, ok := i.(**SQLQuery)
+			if !ok {
+				return false
+			}
+			*sq = &p.sql
+			return true
+		}
+		if err := q.BeforeQuery(asFunc); err != nil {
+			return nil, err
+		}
+	}
+	it := &docIterator{
+		coll:       c,
+		plan:       p,
+		fieldPaths: c.withKey(q.FieldPaths),
+		skip:       p.offset,
+	}
+	if p.orderBy != "" {
+		// Read all the results, to order them.
+		for {
+			more, err := it.nextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				break
+			}
+		}
+		sortItems(it.items, c.orderProperty(p.orderBy), p.orderAscending)
+	} else if _, err := it.nextPage(ctx); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// orderProperty returns the item property for ordering by the document field
+// f.
+func (c *collection) orderProperty(f string) string {
+	switch f {
+	case c.idField:
+		return idProperty
+	case c.opts.RevisionField:
+		return etagProperty
+	}
+	return f
+}
+
+type docIterator struct {
+	coll         *collection
+	plan         *queryPlan
+	fieldPaths   [][]string
+	items        []map[string]interface{}
+	continuation string
+	done         bool // no more pages
+	skip         int  // items left to skip
+	returned     int  // items returned
+	err          error
+}
+
+// nextPage reads the next page of results into it.items, and reports whether
+// there was one.
+func (it *docIterator) nextPage(ctx context.Context) (bool, error) {
+	if it.done {
+		return false, nil
+	}
+	c := it.coll
+	path, res := c.itemsPath()
+	var header http.Header
+	if it.plan.partitionKey != nil {
+		header = partitionKeyHeader(it.plan.partitionKey)
+	} else {
+		header = http.Header{}
+		header.Set("x-ms-documentdb-query-enablecrosspartition", "True")
+	}
+	header.Set("x-ms-documentdb-isquery", "True")
+	if it.continuation != "" {
+		header.Set("x-ms-continuation", it.continuation)
+	}
+	resp, body, err := c.client.do(ctx, &request{
+		method:      http.MethodPost,
+		res:         res,
+		path:        path,
+		header:      header,
+		body:        &it.plan.sql,
+		contentType: "application/query+json",
+	}, http.StatusOK)
+	if err != nil {
+		return false, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var page struct {
+		Documents []map[string]interface{}
+	}
+	if err := dec.Decode(&page); err != nil {
+		return false, fmt.Errorf("decoding query results: %v", err)
+	}
+	it.items = append(it.items, page.Documents...)
+	it.continuation = resp.Header.Get("x-ms-continuation")
+	it.done = it.continuation == ""
+	return true, nil
+}
+
+// Next implements driver.DocumentIterator.Next.
+func (it *docIterator) Next(ctx context.Context, doc driver.Document) error {
+	if it.err != nil {
+		return it.err
+	}
+	it.err = it.next(ctx, doc)
+	return it.err
+}
+
+func (it *docIterator) next(ctx context.Context, doc driver.Document) error {
+	for {
+		if it.plan.limit > 0 && it.returned >= it.plan.limit {
+			return io.EOF
+		}
+		for len(it.items) == 0 {
+			more, err := it.nextPage(ctx)
+			if err != nil {
+				return err
+			}
+			if !more {
+				return io.EOF
+			}
+		}
+		m := it.items[0]
+		it.items = it.items[1:]
+		if it.skip > 0 {
+			it.skip--
+			continue
+		}
+		it.coll.toDocument(m)
+		if err := decodeDoc(m, doc, it.fieldPaths); err != nil {
+			return err
+		}
+		it.returned++
+		return nil
+	}
+}
+
+// Stop implements driver.DocumentIterator.Stop.
+func (it *docIterator) Stop() {
+	it.err = io.EOF
+}
+
+// As implements driver.DocumentIterator.As.
+func (it *docIterator) As(i interface{}) bool {
+	return false
+}
+
+// sortItems sorts items by the value of their property p, in the order of
+// Cosmos DB: undefined, null, false, true, numbers, then strings.
+func sortItems(items []map[string]interface{}, p string, asc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compareValues(items[i], items[j], p)
+		if asc {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+func compareValues(m1, m2 map[string]interface{}, p string) int {
+	v1, ok1 := m1[p]
+	v2, ok2 := m2[p]
+	switch {
+	case !ok1 && !ok2:
+		return 0
+	case !ok1:
+		return -1
+	case !ok2:
+		return 1
+	}
+	r1, r2 := rank(v1), rank(v2)
+	if r1 != r2 {
+		return r1 - r2
+	}
+	switch v1 := v1.(type) {
+	case bool:
+		switch {
+		case v1 == v2.(bool):
+			return 0
+		case v1:
+			return 1
+		default:
+			return -1
+		}
+	case json.Number:
+		_, f1, _ := number(v1)
+		_, f2, _ := number(v2)
+		switch {
+		case f1 < f2:
+			return -1
+		case f1 > f2:
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		return strings.Compare(v1, v2.(string))
+	}
+	return 0
+}
+
+// rank returns the rank of the type of the JSON value v in the order of
+// sortItems. Arrays and objects come last.
+func rank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case json.Number:
+		return 2
+	case string:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// QueryPlan implements driver.Collection.QueryPlan.
+func (c *collection) QueryPlan(q *driver.Query) (string, error) {
+	p, err := c.planQuery(q)
+	if err != nil {
+		return "", err
+	}
+	if p.partitionKey != nil {
+		return "single-partition query: " + p.sql.Query, nil
+	}
+	if p.orderBy != "" {
+		return fmt.Sprintf("cross-partition query: %s, sorted in memory by %s", p.sql.Query, p.orderBy), nil
+	}
+	return "cross-partition query: " + p.sql.Query, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+)
+
+func TestQueryPlan(t *testing.T) {
+	c := &collection{idField: "name", partitionKey: "Game", opts: &Options{RevisionField: docstore.DefaultRevisionField}}
+	for _, test := range []struct {
+		q    *driver.Query
+		want string
+	}{
+		{
+			q:    &driver.Query{},
+			want: "cross-partition query: SELECT * FROM c",
+		},
+		{
+			q: &driver.Query{
+				Filters:      []driver.Filter{{FieldPath: []string{"name"}, Op: ">", Value: "a"}},
+				OrderByField: "Score",
+				Limit:        2,
+			},
+			want: `cross-partition query: SELECT * FROM c WHERE c["id"] > @p0, sorted in memory by Score`,
+		},
+		{
+			q: &driver.Query{
+				Filters: []driver.Filter{
+					{FieldPath: []string{"Game"}, Op: "=", Value: "Zork"},
+					{FieldPath: []string{"a", "b"}, Op: "in", Value: []int{1, 2}},
+					{FieldPath: []string{"DocstoreRevision"}, Op: "not-in", Value: []string{"x"}},
+				},
+				OrderByField:   "Score",
+				OrderAscending: false,
+				Offset:         1,
+				Limit:          2,
+			},
+			want: `single-partition query: SELECT * FROM c WHERE c["Game"] = @p0 AND ARRAY_CONTAINS(@p1, c["a"]["b"]) AND ` +
+				`NOT ARRAY_CONTAINS(@p2, c["_etag"]) ORDER BY c["Score"] DESC OFFSET 1 LIMIT 2`,
+		},
+	} {
+		got, err := c.QueryPlan(test.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%+v:\ngot  %s\nwant %s", test.q, got, test.want)
+		}
+	}
+}
+
+func TestCrossPartitionQuery(t *testing.T) {
+	// Cross-partition queries read several pages, and order, skip and limit
+	// in memory.
+	ctx := context.Background()
+	server, ts := newFakeServer(t)
+	server.createContainer(testDB, container2, "Game")
+	coll, err := OpenCollection(newTestClient(t, ts), testDB, container2, "name", "Game", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coll.Close()
+	al := coll.Actions()
+	for i := 0; i < 10; i++ {
+		al.Put(map[string]interface{}{"name": fmt.Sprintf("p%d", i), "Game": fmt.Sprintf("g%d", i%3), "Score": (i * 7) % 10})
+	}
+	if err := al.Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var sql *SQLQuery
+	iter := coll.Query().Where("Score", ">", 1).OrderBy("Score", docstore.Descending).Offset(1).Limit(4).
+		BeforeQuery(func(asFunc func(interface{}) bool) error {
+			if !asFunc(&sql) {
+				return fmt.Errorf("asFunc failed")
+			}
+			return nil
+		}).Get(ctx, "Score")
+	defer iter.Stop()
+	var got []int64
+	for {
+		doc := map[string]interface{}{}
+		err := iter.Next(ctx, doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, doc["Score"].(int64))
+	}
+	if want := []int64{8, 7, 6, 5}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if want := `SELECT * FROM c WHERE c["Score"] > @p0`; sql == nil || sql.Query != want {
+		t.Errorf("got query %+v, want %q", sql, want)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// Cosmos DB's partial document update cannot remove a field that is not
+// there, or set a field in a map that is not there, both of which docstore
+// allows. So Update reads the item, applies the mods to it, and replaces it
+// if it has not changed since it was read.
+
+// applyMods applies mods to the item m, whose values are as encoding/json
+// unmarshals them with UseNumber.
+func applyMods(m map[string]interface{}, mods []driver.Mod) error {
+	for _, mod := range mods {
+		if err := applyMod(m, mod); err != nil {
+			return gcerr.Newf(gcerr.InvalidArgument, err, "field %s", strings.Join(mod.FieldPath, "."))
+		}
+	}
+	return nil
+}
+
+func applyMod(m map[string]interface{}, mod driver.Mod) error {
+	fp := mod.FieldPath
+	if mod.Value == nil {
+		deleteAtFieldPath(m, fp)
+		return nil
+	}
+	cur, exists := getAtFieldPath(m, fp)
+	var val interface{}
+	switch op := mod.Value.(type) {
+	case driver.IncOp:
+		amount, err := encodeValue(op.Amount)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			val = amount
+		} else if val, err = add(cur, amount); err != nil {
+			return err
+		}
+	case driver.ListAppendOp:
+		vals, err := encodeList(op.Values)
+		if err != nil {
+			return err
+		}
+		list, err := listAt(cur, exists)
+		if err != nil {
+			return err
+		}
+		val = append(list, vals...)
+	case driver.SetAddOp:
+		vals, err := encodeList(op.Values)
+		if err != nil {
+			return err
+		}
+		list, err := listAt(cur, exists)
+		if err != nil {
+			return err
+		}
+		for _, v := range vals {
+			if indexOf(list, v) < 0 {
+				list = append(list, v)
+			}
+		}
+		val = list
+	case driver.SetRemoveOp:
+		if !exists {
+			return nil
+		}
+		vals, err := encodeList(op.Values)
+		if err != nil {
+			return err
+		}
+		list, err := listAt(cur, exists)
+		if err != nil {
+			return err
+		}
+		var kept []interface{}
+		for _, e := range list {
+			if indexOf(vals, e) < 0 {
+				kept = append(kept, e)
+			}
+		}
+		if kept == nil {
+			kept = []interface{}{}
+		}
+		val = kept
+	default:
+		v, err := encodeValue(mod.Value)
+		if err != nil {
+			return err
+		}
+		val = v
+	}
+	return setAtFieldPath(m, fp, val)
+}
+
+func encodeList(vals []interface{}) ([]interface{}, error) {
+	res := make([]interface{}, len(vals))
+	for i, v := range vals {
+		e, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = e
+	}
+	return res, nil
+}
+
+// listAt returns the value cur of a field as a list, or an empty list if the
+// field does not exist.
+func listAt(cur interface{}, exists bool) ([]interface{}, error) {
+	if !exists || cur == nil {
+		return nil, nil
+	}
+	list, ok := cur.([]interface{})
+	if !ok {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "value %v is not a list", cur)
+	}
+	return list, nil
+}
+
+// indexOf returns the index of the first element of list with the same JSON
+// as v, or -1.
+func indexOf(list []interface{}, v interface{}) int {
+	want, err := json.Marshal(v)
+	if err != nil {
+		return -1
+	}
+	for i, e := range list {
+		if got, err := json.Marshal(e); err == nil && bytes.Equal(got, want) {
+			return i
+		}
+	}
+	return -1
+}
+
+// add returns the sum of the numbers x and y. It is an integer if both are.
+func add(x, y interface{}) (interface{}, error) {
+	xi, xf, xok := number(x)
+	yi, yf, yok := number(y)
+	if xok == 0 || yok == 0 {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot increment %v by %v", x, y)
+	}
+	if xok == isInt && yok == isInt {
+		return xi + yi, nil
+	}
+	return xf + yf, nil
+}
+
+const (
+	isInt   = 1
+	isFloat = 2
+)
+
+// number returns the value of the number x as an int64 and a float64, and
+// whether it is an integer (isInt), another number (isFloat) or not a
+// number (0).
+func number(x interface{}) (int64, float64, int) {
+	switch x := x.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i, float64(i), isInt
+		}
+		if f, err := x.Float64(); err == nil {
+			return 0, f, isFloat
+		}
+	case int64:
+		return x, float64(x), isInt
+	case uint64:
+		return int64(x), float64(x), isInt
+	case float64:
+		return 0, x, isFloat
+	}
+	return 0, 0, 0
+}
+
+// getAtFieldPath returns the value of the field at fp in m, and whether it
+// exists.
+func getAtFieldPath(m map[string]interface{}, fp []string) (interface{}, bool) {
+	for _, f := range fp[:len(fp)-1] {
+		sub, ok := m[f].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = sub
+	}
+	v, ok := m[fp[len(fp)-1]]
+	return v, ok
+}
+
+// setAtFieldPath sets the field at fp in m to v, creating the maps on the
+// way as needed.
+func setAtFieldPath(m map[string]interface{}, fp []string, v interface{}) error {
+	for _, f := range fp[:len(fp)-1] {
+		x, ok := m[f]
+		if !ok || x == nil {
+			sub := map[string]interface{}{}
+			m[f] = sub
+			m = sub
+			continue
+		}
+		sub, ok := x.(map[string]interface{})
+		if !ok {
+			return gcerr.Newf(gcerr.InvalidArgument, nil, "field %q is not a map", f)
+		}
+		m = sub
+	}
+	m[fp[len(fp)-1]] = v
+	return nil
+}
+
+// deleteAtFieldPath deletes the field at fp in m, if it exists.
+func deleteAtFieldPath(m map[string]interface{}, fp []string) {
+	for _, f := range fp[:len(fp)-1] {
+		sub, ok := m[f].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = sub
+	}
+	delete(m, fp[len(fp)-1])
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"gocloud.dev/docstore"
+)
+
+func init() {
+	docstore.DefaultURLMux().RegisterCollection(Scheme, new(defaultOpener))
+}
+
+// defaultOpener creates a Client for the account in the environment variable
+// AZURE_COSMOS_ENDPOINT, authenticating with the key in AZURE_COSMOS_KEY if
+// it is set, and with azidentity.DefaultAzureCredential otherwise.
+type defaultOpener struct {
+	mu       sync.Mutex
+	endpoint string
+	key      string
+	opener   *URLOpener
+}
+
+func (o *defaultOpener) OpenCollectionURL(ctx context.Context, u *url.URL) (*docstore.Collection, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	endpoint := os.Getenv("AZURE_COSMOS_ENDPOINT")
+	key := os.Getenv("AZURE_COSMOS_KEY")
+	if endpoint == "" {
+		return nil, fmt.Errorf("open collection %s: %v", u, errors.New("AZURE_COSMOS_ENDPOINT environment variable is not set"))
+	}
+	// If the environment has changed, then create a new Client.
+	if o.opener == nil || endpoint != o.endpoint || key != o.key {
+		var client *Client
+		var err error
+		if key != "" {
+			client, err = NewClientWithKey(endpoint, key, nil)
+		} else {
+			cred, cerr := azidentity.NewDefaultAzureCredential(nil)
+			if cerr != nil {
+				return nil, fmt.Errorf("open collection %s: failed azidentity.NewDefaultAzureCredential: %v", u, cerr)
+			}
+			client, err = NewClient(endpoint, cred, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("open collection %s: %v", u, err)
+		}
+		o.endpoint = endpoint
+		o.key = key
+		o.opener = &URLOpener{Client: client}
+	}
+	return o.opener.OpenCollectionURL(ctx, u)
+}
+
+// Scheme is the URL scheme azurecosmos registers its URLOpener under on
+// docstore.DefaultMux.
+const Scheme = "cosmos"
+
+// URLOpener opens URLs like "cosmos://mydb/mycontainer?partition_key=Game".
+//
+// The URL Host is used as the database name.
+// The URL Path is used as the container name.
+//
+// The following query parameters are supported:
+//
+//   - id_field (optional): the field stored as the item's "id"; defaults to "id".
+//   - partition_key (optional): the partition key field; defaults to id_field.
+//   - revision_field (optional): the name of the revision field.
+type URLOpener struct {
+	// Client sends requests to the Cosmos DB account. It must be non-nil.
+	Client *Client
+
+	// Options specifies the options to pass to OpenCollection.
+	Options Options
+}
+
+// OpenCollectionURL opens the Collection URL.
+func (o *URLOpener) OpenCollectionURL(ctx context.Context, u *url.URL) (*docstore.Collection, error) {
+	q := u.Query()
+	idField := q.Get("id_field")
+	q.Del("id_field")
+	partitionKey := q.Get("partition_key")
+	q.Del("partition_key")
+	opts := o.Options
+	if rf := q.Get("revision_field"); rf != "" {
+		opts.RevisionField = rf
+	}
+	q.Del("revision_field")
+
+	for param := range q {
+		return nil, fmt.Errorf("open collection %s: invalid query parameter %q", u, param)
+	}
+
+	dbName := u.Host
+	if dbName == "" {
+		return nil, fmt.Errorf("open collection %s: URL must have a non-empty Host (database name)", u)
+	}
+	containerName := strings.TrimPrefix(u.Path, "/")
+	if containerName == "" || strings.ContainsRune(containerName, '/') {
+		return nil, fmt.Errorf("open collection %s: URL must have a Path with a single container name", u)
+	}
+	coll, err := OpenCollection(o.Client, dbName, containerName, idField, partitionKey, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("open collection %s: %v", u, err)
+	}
+	return coll, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurecosmos
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"gocloud.dev/docstore"
+)
+
+func TestOpenCollectionURL(t *testing.T) {
+	t.Setenv("AZURE_COSMOS_ENDPOINT", "https://myaccount.documents.azure.com:443/")
+	t.Setenv("AZURE_COSMOS_KEY", testAccountKey)
+
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"cosmos://mydb/mycontainer", false},
+		// Missing database name.
+		{"cosmos:///mycontainer", true},
+		// Missing container name.
+		{"cosmos://mydb/", true},
+		// Too many path elements.
+		{"cosmos://mydb/mycontainer/x", true},
+		// Passing id_field and partition_key parameters.
+		{"cosmos://mydb/mycontainer?id_field=name&partition_key=Game", false},
+		// Passing revision field.
+		{"cosmos://mydb/mycontainer?revision_field=123", false},
+		// Invalid parameter.
+		{"cosmos://mydb/mycontainer?param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		d, err := docstore.OpenCollection(ctx, test.URL)
+		if d != nil {
+			defer d.Close()
+		}
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+	}
+}
+
+func TestDefaultOpenerOpenCollectionURL(t *testing.T) {
+	ctx := context.Background()
+	u, err := url.Parse("cosmos://mydb/mycontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := new(defaultOpener)
+
+	t.Setenv("AZURE_COSMOS_ENDPOINT", "")
+	if _, err := o.OpenCollectionURL(ctx, u); err == nil {
+		t.Error("got nil error with AZURE_COSMOS_ENDPOINT unset, want error")
+	}
+
+	t.Setenv("AZURE_COSMOS_ENDPOINT", "https://account1.documents.azure.com:443/")
+	t.Setenv("AZURE_COSMOS_KEY", testAccountKey)
+	if _, err := o.OpenCollectionURL(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	client1 := o.opener.Client
+
+	// The Client is reused until the environment changes.
+	if _, err := o.OpenCollectionURL(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if o.opener.Client != client1 {
+		t.Error("got a new Client, want the same one")
+	}
+	t.Setenv("AZURE_COSMOS_ENDPOINT", "https://account2.documents.azure.com:443/")
+	if _, err := o.OpenCollectionURL(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if o.opener.Client == client1 {
+		t.Error("got the same Client after AZURE_COSMOS_ENDPOINT changed, want a new one")
+	}
+
+	t.Setenv("AZURE_COSMOS_KEY", "not base64!")
+	if _, err := o.OpenCollectionURL(ctx, u); err == nil {
+		t.Error("got nil error with an invalid AZURE_COSMOS_KEY, want error")
+	}
+}
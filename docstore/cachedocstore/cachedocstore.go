@@ -0,0 +1,628 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachedocstore provides a *docstore.Collection that caches the
+// documents of a slow Collection in a fast one, such as a memdocstore
+// Collection. Use NewCollection or NewCollectionWithKeyFunc to construct one.
+//
+//	backend, err := awsdynamodb.OpenCollection(db, "users", "ID", "", nil)
+//	...
+//	cache, err := memdocstore.OpenCollection("ID", &memdocstore.Options{RevisionField: "CacheRevision"})
+//	...
+//	coll := cachedocstore.NewCollection(backend, cache, "ID", &cachedocstore.Options{TTL: time.Minute})
+//
+// A Get of a whole document reads through the cache: the document is read
+// from the cache if it holds it, and otherwise from the backend, after which
+// it is stored in the cache for Options.TTL. A Get with field paths is served
+// from the cache if it holds the document, but never fills it.
+//
+// Every write to the backend invalidates the cached document. With
+// Options.WriteThrough, the documents written by successful Creates, Replaces
+// and Puts are then stored in the cache. Updates and Deletes only invalidate.
+// With Options.CheckRevision, each document read from the cache is checked
+// against the revision in the backend, and read from the backend if it is
+// stale; that costs a read of the backend, but a small one.
+//
+// Queries are run on the backend, and so are atomic action lists, since the
+// cache cannot take part in a transaction; their writes invalidate the cache
+// like other writes. Watch, the aggregations of Query and pagination tokens
+// are passed on to the backend; they are supported if it supports them.
+//
+// # Invalidation
+//
+// The Collection tracks which documents are cached, and until when, in
+// memory. A document is only read from the cache if it was stored there by
+// the same Collection, and has not been invalidated since; so a cache shared
+// by several processes is not a way for them to share documents, and their
+// writes to the backend are not seen by the others until the TTL expires,
+// unless Options.CheckRevision is set.
+//
+// # The Cache Collection
+//
+// The cache stores the backend's documents as they are, revision field
+// included. Its own revision field must therefore be different from the
+// backend's, and not be a field of the documents, so that it does not change
+// them: open a memdocstore cache with a memdocstore.Options.RevisionField
+// such as "CacheRevision".
+//
+// # As
+//
+// cachedocstore exposes the types of the backend Collection for As.
+package cachedocstore // import "gocloud.dev/docstore/cachedocstore"
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+)
+
+// Options sets how the Collection returned by NewCollection caches
+// documents.
+type Options struct {
+	// TTL is how long a document is read from the cache after it is stored
+	// there. Zero means until it is invalidated by a write.
+	TTL time.Duration
+
+	// WriteThrough stores the documents written by successful Creates,
+	// Replaces and Puts in the cache.
+	WriteThrough bool
+
+	// CheckRevision reads the revision of each document read from the cache
+	// from the backend, and reads the document from the backend if the
+	// revisions differ.
+	CheckRevision bool
+
+	// RevisionField is the name of the field holding the document revision
+	// in the backend Collection. Defaults to docstore.DefaultRevisionField.
+	RevisionField string
+}
+
+// NewCollection returns a *docstore.Collection that caches the documents of
+// backend in cache. keyField is the document field holding the primary key
+// of both. Closing the returned Collection closes neither backend nor cache.
+func NewCollection(backend, cache *docstore.Collection, keyField string, opts *Options) *docstore.Collection {
+	return docstore.NewCollection(newCollection(backend, cache, keyField, nil, opts))
+}
+
+// NewCollectionWithKeyFunc is like NewCollection, but for Collections
+// whose primary key is made of more than one field. keyFunc takes a
+// document and returns its primary key, or nil if the document does not
+// have one.
+func NewCollectionWithKeyFunc(backend, cache *docstore.Collection, keyFunc func(docstore.Document) interface{}, opts *Options) *docstore.Collection {
+	return docstore.NewCollection(newCollection(backend, cache, "", keyFunc, opts))
+}
+
+func newCollection(backend, cache *docstore.Collection, keyField string, keyFunc func(docstore.Document) interface{}, opts *Options) *collection {
+	if opts == nil {
+		opts = &Options{}
+	}
+	c := &collection{
+		backend:  backend,
+		cache:    cache,
+		keyField: keyField,
+		keyFunc:  keyFunc,
+		opts:     opts,
+		now:      time.Now,
+		entries:  map[interface{}]entry{},
+	}
+	c.revisionField = opts.RevisionField
+	if c.revisionField == "" {
+		c.revisionField = docstore.DefaultRevisionField
+	}
+	return c
+}
+
+// collection implements driver.Collection.
+type collection struct {
+	backend       *docstore.Collection
+	cache         *docstore.Collection
+	keyField      string
+	keyFunc       func(docstore.Document) interface{}
+	opts          *Options
+	revisionField string
+	now           func() time.Time
+
+	mu      sync.Mutex
+	entries map[interface{}]entry // by document key
+	gen     uint64                // the generation of the last invalidation
+}
+
+// An entry records whether the document with a key is cached.
+//
+// Each invalidation of a document gives its entry a new generation. A
+// document read from the backend is marked as cached only if the generation
+// of its entry has not changed since the read began, so that a write to the
+// backend that races with the read does not leave a stale document cached.
+type entry struct {
+	gen     uint64
+	cached  bool
+	expires time.Time // zero for no expiry
+}
+
+// lookup returns the generation of the entry for key, and whether the
+// document is cached and has not expired.
+func (c *collection) lookup(key interface{}) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[key]
+	fresh := e.cached && (e.expires.IsZero() || c.now().Before(e.expires))
+	return e.gen, fresh
+}
+
+// invalidate marks the document with key as not cached, and returns the new
+// generation of its entry.
+func (c *collection) invalidate(key interface{}) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.entries[key] = entry{gen: c.gen}
+	return c.gen
+}
+
+// fill marks the document with key, which was just stored in the cache, as
+// cached, if its entry is still of generation gen.
+func (c *collection) fill(key interface{}, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key].gen != gen {
+		return
+	}
+	e := entry{gen: gen, cached: true}
+	if c.opts.TTL > 0 {
+		e.expires = c.now().Add(c.opts.TTL)
+	}
+	c.entries[key] = e
+}
+
+// Key implements driver.Collection.Key.
+func (c *collection) Key(doc driver.Document) (interface{}, error) {
+	if c.keyField != "" {
+		key, _ := doc.GetField(c.keyField) // no error on missing key, and it will be nil
+		return key, nil
+	}
+	return c.keyFunc(doc.Origin), nil
+}
+
+// RevisionField implements driver.Collection.RevisionField.
+func (c *collection) RevisionField() string {
+	return c.revisionField
+}
+
+// RunActions implements driver.Collection.RunActions.
+func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	errs := make([]error, len(actions))
+	// For each Get, the generation of its document's entry before the read.
+	gens := make([]uint64, len(actions))
+	// Whether each Get was served from the cache.
+	cached := make([]bool, len(actions))
+
+	// Read the cached documents that no earlier action in the list writes
+	// from the cache.
+	var hits []int
+	cl := c.cache.Actions()
+	written := map[interface{}]bool{}
+	for i, a := range actions {
+		if a.Kind != driver.Get {
+			if a.Key != nil {
+				written[a.Key] = true
+			}
+			continue
+		}
+		if a.Key == nil || written[a.Key] {
+			continue
+		}
+		gen, fresh := c.lookup(a.Key)
+		gens[i] = gen
+		if fresh {
+			cl.Get(a.Doc.Origin, fieldPaths(a.FieldPaths)...)
+			hits = append(hits, i)
+		}
+	}
+	// A Get that fails in the cache is a miss.
+	for j, err := range do(ctx, cl, len(hits)) {
+		cached[hits[j]] = err == nil
+	}
+
+	// Run the writes and the other Gets on the backend, and read the
+	// revisions of the cached documents to check.
+	checked := make([]bool, len(actions))
+	revs := make([]interface{}, len(actions))
+	var run []int
+	bl := c.backend.Actions()
+	if opts.BeforeDo != nil {
+		bl.BeforeDo(opts.BeforeDo)
+	}
+	for i, a := range actions {
+		switch {
+		case !cached[i]:
+			addBackendAction(bl, a)
+		case c.opts.CheckRevision && a.Doc.HasField(c.revisionField):
+			checked[i] = true
+			revs[i], _ = a.Doc.GetField(c.revisionField)
+			bl.Get(a.Doc.Origin, docstore.FieldPath(c.revisionField))
+		default:
+			continue
+		}
+		run = append(run, i)
+	}
+	for j, err := range do(ctx, bl, len(run)) {
+		errs[run[j]] = err
+	}
+
+	// Read the stale cached documents from the backend.
+	var stale []int
+	sl := c.backend.Actions()
+	for i, a := range actions {
+		if !checked[i] || errs[i] != nil {
+			continue
+		}
+		if rev, _ := a.Doc.GetField(c.revisionField); !reflect.DeepEqual(rev, revs[i]) {
+			cached[i] = false
+			gens[i] = c.invalidate(a.Key)
+			sl.Get(a.Doc.Origin, fieldPaths(a.FieldPaths)...)
+			stale = append(stale, i)
+		}
+	}
+	for j, err := range do(ctx, sl, len(stale)) {
+		errs[stale[j]] = err
+	}
+
+	c.updateCache(ctx, actions, errs, gens, cached)
+	return actionListError(actions, errs)
+}
+
+// RunActionsAtomically implements driver.AtomicActionRunner. All the actions,
+// Gets included, run as an atomic action list of the backend. The cache is
+// then updated as for RunActions.
+func (c *collection) RunActionsAtomically(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	gens := make([]uint64, len(actions))
+	bl := c.backend.Actions().Atomic()
+	if opts.BeforeDo != nil {
+		bl.BeforeDo(opts.BeforeDo)
+	}
+	for i, a := range actions {
+		if a.Kind == driver.Get && a.Key != nil {
+			gens[i], _ = c.lookup(a.Key)
+		}
+		addBackendAction(bl, a)
+	}
+	errs := do(ctx, bl, len(actions))
+	c.updateCache(ctx, actions, errs, gens, make([]bool, len(actions)))
+	return actionListError(actions, errs)
+}
+
+// actionListError returns the errors of actions, errs, as a
+// driver.ActionListError.
+func actionListError(actions []*driver.Action, errs []error) driver.ActionListError {
+	var alerr driver.ActionListError
+	for i, err := range errs {
+		if err != nil {
+			alerr = append(alerr, struct {
+				Index int
+				Err   error
+			}{actions[i].Index, err})
+		}
+	}
+	return alerr
+}
+
+// updateCache invalidates the documents that actions wrote to the backend,
+// and stores in the cache the documents that they read from it, and with
+// Options.WriteThrough, the ones that they wrote. errs are the errors of the
+// actions, gens the generations of the entries of the documents read before
+// they were read, and cached reports which were read from the cache.
+func (c *collection) updateCache(ctx context.Context, actions []*driver.Action, errs []error, gens []uint64, cached []bool) {
+	l := c.cache.Actions()
+	// The cache actions, and for each stored document, its key and
+	// generation.
+	type fill struct {
+		key interface{}
+		gen uint64
+	}
+	var fills []*fill
+	for i, a := range actions {
+		switch a.Kind {
+		case driver.Get:
+			if a.Key == nil {
+				continue
+			}
+			if errs[i] != nil {
+				// The document may have been deleted since it was cached.
+				if gcerrors.Code(errs[i]) == gcerrors.NotFound {
+					c.invalidate(a.Key)
+				}
+				continue
+			}
+			if cached[i] || len(a.FieldPaths) > 0 {
+				continue
+			}
+			l.Put(a.Doc.Origin)
+			fills = append(fills, &fill{a.Key, gens[i]})
+
+		case driver.Create, driver.Replace, driver.Put:
+			// A Create may have set the key.
+			key, err := c.Key(a.Doc)
+			if err != nil || key == nil {
+				continue
+			}
+			gen := c.invalidate(key)
+			if errs[i] == nil && c.opts.WriteThrough {
+				l.Put(a.Doc.Origin)
+				fills = append(fills, &fill{key, gen})
+			} else {
+				l.Delete(a.Doc.Origin)
+				fills = append(fills, nil)
+			}
+
+		default: // Update or Delete
+			if a.Key == nil {
+				continue
+			}
+			c.invalidate(a.Key)
+			l.Delete(a.Doc.Origin)
+			fills = append(fills, nil)
+		}
+	}
+	// Failures leave the documents uncached.
+	for j, err := range do(ctx, l, len(fills)) {
+		if f := fills[j]; err == nil && f != nil {
+			c.fill(f.key, f.gen)
+		}
+	}
+}
+
+// addBackendAction adds a to l.
+func addBackendAction(l *docstore.ActionList, a *driver.Action) {
+	doc := a.Doc.Origin
+	switch a.Kind {
+	case driver.Create:
+		l.Create(doc)
+	case driver.Replace:
+		l.Replace(doc)
+	case driver.Put:
+		l.Put(doc)
+	case driver.Get:
+		l.Get(doc, fieldPaths(a.FieldPaths)...)
+	case driver.Delete:
+		l.Delete(doc)
+	case driver.Update:
+		mods := docstore.Mods{}
+		for _, m := range a.Mods {
+			v := m.Value
+			if inc, ok := v.(driver.IncOp); ok {
+				v = docstore.Increment(inc.Amount)
+			}
+			mods[fieldPath(m.FieldPath)] = v
+		}
+		l.Update(doc, mods)
+	}
+}
+
+// do runs l, which holds n actions, and returns the error of each. An error
+// that is not of a single action is the error of all those that have none.
+func do(ctx context.Context, l *docstore.ActionList, n int) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	err := l.Do(ctx)
+	if err == nil {
+		return errs
+	}
+	var lerr docstore.ActionListError
+	if !errors.As(err, &lerr) {
+		lerr = docstore.ActionListError{{Index: -1, Err: err}}
+	}
+	var listErr error
+	for _, e := range lerr {
+		if e.Index < 0 {
+			listErr = e.Err
+			continue
+		}
+		errs[e.Index] = e.Err
+	}
+	if listErr != nil {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = listErr
+			}
+		}
+	}
+	return errs
+}
+
+// RunGetQuery implements driver.Collection.RunGetQuery.
+func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	return &docIterator{it: c.query(q).Get(ctx, fieldPaths(q.FieldPaths)...)}, nil
+}
+
+// QueryPlan implements driver.Collection.QueryPlan.
+func (c *collection) QueryPlan(q *driver.Query) (string, error) {
+	return c.query(q).Plan(fieldPaths(q.FieldPaths)...)
+}
+
+// query returns the query on the backend for q.
+func (c *collection) query(q *driver.Query) *docstore.Query {
+	dq := c.backend.Query()
+	for _, f := range q.Filters {
+		dq.Where(fieldPath(f.FieldPath), f.Op, f.Value)
+	}
+	if q.Offset > 0 {
+		dq.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		dq.Limit(q.Limit)
+	}
+	if q.OrderByField != "" {
+		dir := docstore.Descending
+		if q.OrderAscending {
+			dir = docstore.Ascending
+		}
+		dq.OrderBy(q.OrderByField, dir)
+	}
+	if q.BeforeQuery != nil {
+		dq.BeforeQuery(q.BeforeQuery)
+	}
+	if q.PaginationToken != nil {
+		dq.StartFrom(base64.RawURLEncoding.EncodeToString(q.PaginationToken))
+	}
+	return dq
+}
+
+// SupportsPaginationTokens implements driver.PaginatedQueryer. Queries with a
+// pagination token fail if the backend does not support them.
+func (c *collection) SupportsPaginationTokens() bool {
+	return true
+}
+
+// docIterator implements driver.DocumentIterator.
+type docIterator struct {
+	it *docstore.DocumentIterator
+}
+
+func (it *docIterator) Next(ctx context.Context, doc driver.Document) error {
+	return it.it.Next(ctx, doc.Origin)
+}
+
+func (it *docIterator) Stop() {
+	it.it.Stop()
+}
+
+func (it *docIterator) As(i interface{}) bool {
+	return it.it.As(i)
+}
+
+// PaginationToken implements driver.PaginatedIterator with the tokens of the
+// backend.
+func (it *docIterator) PaginationToken() ([]byte, error) {
+	token, err := it.it.PaginationToken()
+	if err != nil || token == "" {
+		return nil, err
+	}
+	return base64.RawURLEncoding.DecodeString(token)
+}
+
+// RunAggregateQuery implements driver.Aggregator with the aggregations of
+// the backend.
+func (c *collection) RunAggregateQuery(ctx context.Context, q *driver.Query, agg driver.Aggregation) (interface{}, error) {
+	dq := c.query(q)
+	fp := fieldPath(agg.FieldPath)
+	switch agg.Op {
+	case "count":
+		n, err := dq.Count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case "sum":
+		sum, err := dq.Sum(ctx, fp)
+		if err != nil {
+			return nil, err
+		}
+		return sum, nil
+	case "min":
+		return dq.Min(ctx, fp)
+	case "max":
+		return dq.Max(ctx, fp)
+	default:
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "unknown aggregation %q", agg.Op)
+	}
+}
+
+// Watch implements driver.Watcher with the changes of the backend.
+func (c *collection) Watch(ctx context.Context, opts *driver.WatchOptions) (driver.ChangeIterator, error) {
+	return &changeIterator{it: c.backend.Watch(ctx, &docstore.WatchOptions{FromOldest: opts.FromOldest})}, nil
+}
+
+// changeIterator implements driver.ChangeIterator.
+type changeIterator struct {
+	it *docstore.ChangeIterator
+}
+
+func (it *changeIterator) Next(ctx context.Context) (*driver.Change, error) {
+	ch, err := it.it.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decode := func(f func(docstore.Document) error) func(driver.Document) error {
+		return func(doc driver.Document) error { return f(doc.Origin) }
+	}
+	return &driver.Change{
+		Kind:      driver.ChangeKind(ch.Kind),
+		DecodeKey: decode(ch.Key),
+		DecodeOld: decode(ch.Old),
+		DecodeNew: decode(ch.New),
+		AsFunc:    ch.As,
+	}, nil
+}
+
+func (it *changeIterator) Stop() {
+	it.it.Stop()
+}
+
+func fieldPath(fp []string) docstore.FieldPath {
+	return docstore.FieldPath(strings.Join(fp, "."))
+}
+
+func fieldPaths(fps [][]string) []docstore.FieldPath {
+	var res []docstore.FieldPath
+	for _, fp := range fps {
+		res = append(res, fieldPath(fp))
+	}
+	return res
+}
+
+// RevisionToBytes implements driver.Collection.RevisionToBytes.
+func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	s, err := c.backend.RevisionToString(rev)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// BytesToRevision implements driver.Collection.BytesToRevision.
+func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	return c.backend.StringToRevision(string(b))
+}
+
+// As implements driver.Collection.As.
+func (c *collection) As(i interface{}) bool {
+	return c.backend.As(i)
+}
+
+// ErrorAs implements driver.Collection.ErrorAs.
+func (c *collection) ErrorAs(err error, i interface{}) bool {
+	return c.backend.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Collection.ErrorCode.
+func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Collection.Close. It closes neither the backend
+// nor the cache.
+func (c *collection) Close() error {
+	return nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachedocstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/docstore/memdocstore"
+	"gocloud.dev/gcerrors"
+)
+
+const cacheRevisionField = "CacheRevision"
+
+type harness struct {
+	opts  Options
+	colls []*docstore.Collection // the backends and caches, to close
+}
+
+func (h *harness) open(coll *docstore.Collection, err error) (*docstore.Collection, error) {
+	if err == nil {
+		h.colls = append(h.colls, coll)
+	}
+	return coll, err
+}
+
+func (h *harness) MakeCollection(_ context.Context, kind drivertest.CollectionKind) (driver.Collection, error) {
+	opts := h.opts
+	cacheOpts := &memdocstore.Options{RevisionField: cacheRevisionField}
+	switch kind {
+	case drivertest.SingleKey, drivertest.NoRev:
+		backend, err := h.open(memdocstore.OpenCollection(drivertest.KeyField, nil))
+		if err != nil {
+			return nil, err
+		}
+		cache, err := h.open(memdocstore.OpenCollection(drivertest.KeyField, cacheOpts))
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(backend, cache, drivertest.KeyField, nil, &opts), nil
+	case drivertest.TwoKey:
+		backend, err := h.open(memdocstore.OpenCollectionWithKeyFunc(drivertest.HighScoreKey, nil))
+		if err != nil {
+			return nil, err
+		}
+		cache, err := h.open(memdocstore.OpenCollectionWithKeyFunc(drivertest.HighScoreKey, cacheOpts))
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(backend, cache, "", drivertest.HighScoreKey, &opts), nil
+	case drivertest.AltRev:
+		backend, err := h.open(memdocstore.OpenCollection(drivertest.KeyField, &memdocstore.Options{RevisionField: drivertest.AlternateRevisionField}))
+		if err != nil {
+			return nil, err
+		}
+		cache, err := h.open(memdocstore.OpenCollection(drivertest.KeyField, cacheOpts))
+		if err != nil {
+			return nil, err
+		}
+		opts.RevisionField = drivertest.AlternateRevisionField
+		return newCollection(backend, cache, drivertest.KeyField, nil, &opts), nil
+	default:
+		panic("bad kind")
+	}
+}
+
+func (*harness) BeforeDoTypes() []interface{}    { return nil }
+func (*harness) BeforeQueryTypes() []interface{} { return nil }
+
+func (*harness) RevisionsEqual(rev1, rev2 interface{}) bool { return rev1 == rev2 }
+
+func (h *harness) Close() {
+	for _, coll := range h.colls {
+		coll.Close()
+	}
+}
+
+// TestConformance checks that a Collection behaves like its backend, with
+// and without the options that serve more Gets from the cache.
+func TestConformance(t *testing.T) {
+	for _, opts := range []Options{
+		{},
+		{WriteThrough: true},
+		{WriteThrough: true, CheckRevision: true},
+	} {
+		newHarness := func(context.Context, *testing.T) (drivertest.Harness, error) {
+			return &harness{opts: opts}, nil
+		}
+		drivertest.RunConformanceTests(t, newHarness, nil, nil)
+	}
+}
+
+type docmap = map[string]interface{}
+
+// get gets the value of the field "v" of the document with ID id.
+func get(t *testing.T, coll *docstore.Collection, id string) interface{} {
+	t.Helper()
+	doc := docmap{"ID": id, docstore.DefaultRevisionField: nil}
+	if err := coll.Get(context.Background(), doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc["v"]
+}
+
+func newTestCollection(t *testing.T, opts *Options) (coll, backend *docstore.Collection, c *collection) {
+	backend, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { backend.Close() })
+	cache, err := memdocstore.OpenCollection("ID", &memdocstore.Options{RevisionField: cacheRevisionField})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	c = newCollection(backend, cache, "ID", nil, opts)
+	coll = docstore.NewCollection(c)
+	t.Cleanup(func() { coll.Close() })
+	return coll, backend, c
+}
+
+func TestReadThrough(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	coll, backend, c := newTestCollection(t, &Options{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(1) {
+		t.Fatalf("got %v, want 1", got)
+	}
+	// A write made around the Collection is not seen until the TTL expires.
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(1) {
+		t.Errorf("got %v, want the cached 1", got)
+	}
+	now = now.Add(time.Minute)
+	if got := get(t, coll, "a"); got != int64(2) {
+		t.Errorf("after the TTL: got %v, want 2", got)
+	}
+
+	// Writes through the Collection invalidate.
+	if err := coll.Update(ctx, docmap{"ID": "a"}, docstore.Mods{"v": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(3) {
+		t.Errorf("after Update: got %v, want 3", got)
+	}
+	if err := coll.Delete(ctx, docmap{"ID": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := coll.Get(ctx, docmap{"ID": "a"}); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("after Delete: got %v, want NotFound", err)
+	}
+}
+
+func TestWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	coll, backend, _ := newTestCollection(t, &Options{WriteThrough: true})
+
+	if err := coll.Put(ctx, docmap{"ID": "a", "v": 1}); err != nil {
+		t.Fatal(err)
+	}
+	// The document is cached by the Put.
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(1) {
+		t.Errorf("got %v, want the cached 1", got)
+	}
+}
+
+func TestCheckRevision(t *testing.T) {
+	ctx := context.Background()
+	coll, backend, _ := newTestCollection(t, &Options{CheckRevision: true})
+
+	rev := docstore.DefaultRevisionField
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 1, rev: nil}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(1) {
+		t.Fatalf("got %v, want 1", got)
+	}
+	// A write made around the Collection changes the revision, so the
+	// cached document is stale.
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 2, rev: nil}); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, coll, "a"); got != int64(2) {
+		t.Errorf("got %v, want 2", got)
+	}
+	if err := backend.Delete(ctx, docmap{"ID": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	doc := docmap{"ID": "a", rev: nil}
+	if err := coll.Get(ctx, doc); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("after Delete: got %v, want NotFound", err)
+	}
+}
+
+func TestInvalidationRace(t *testing.T) {
+	_, _, c := newTestCollection(t, nil)
+	// A read of the backend that began before a write must not mark its
+	// stale document as cached.
+	gen, _ := c.lookup("a")
+	c.invalidate("a")
+	c.fill("a", gen)
+	if _, fresh := c.lookup("a"); fresh {
+		t.Error("document read before an invalidation was cached")
+	}
+	gen, _ = c.lookup("a")
+	c.fill("a", gen)
+	if _, fresh := c.lookup("a"); !fresh {
+		t.Error("document was not cached")
+	}
+}
+
+func TestAtomicActions(t *testing.T) {
+	ctx := context.Background()
+	coll, backend, c := newTestCollection(t, nil)
+	if err := backend.Put(ctx, docmap{"ID": "a", "v": 1}); err != nil {
+		t.Fatal(err)
+	}
+	get(t, coll, "a")
+	gen, fresh := c.lookup("a")
+	if !fresh {
+		t.Fatal("document was not cached")
+	}
+	// memdocstore does not support transactions, but the attempted write
+	// still invalidates the cached document.
+	err := coll.Actions().Atomic().Put(docmap{"ID": "a", "v": 2}).Do(ctx)
+	if gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("got %v, want Unimplemented", err)
+	}
+	if g, fresh := c.lookup("a"); fresh || g == gen {
+		t.Error("atomic write did not invalidate the cached document")
+	}
+}
+
+func TestBackendFeatures(t *testing.T) {
+	ctx := context.Background()
+	coll, _, _ := newTestCollection(t, nil)
+	for _, id := range []string{"a", "b"} {
+		if err := coll.Put(ctx, docmap{"ID": id, "v": 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := coll.Query().Count(ctx); err != nil || n != 2 {
+		t.Errorf("Count: got %d, %v; want 2", n, err)
+	}
+	if sum, err := coll.Query().Sum(ctx, "v"); err != nil || sum != 4 {
+		t.Errorf("Sum: got %v, %v; want 4", sum, err)
+	}
+
+	// memdocstore supports neither pagination tokens nor Watch.
+	it := coll.Query().StartFrom("dG9rZW4").Get(ctx)
+	defer it.Stop()
+	if err := it.Next(ctx, docmap{}); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("StartFrom: got %v, want Unimplemented", err)
+	}
+	cit := coll.Watch(ctx, nil)
+	defer cit.Stop()
+	if _, err := cit.Next(ctx); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("Watch: got %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,690 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cassandradocstore provides a docstore implementation backed by
+// Apache Cassandra or ScyllaDB, using the gocql client.
+// Use OpenCollection to construct a *docstore.Collection.
+//
+// # URLs
+//
+// For docstore.OpenCollection, cassandradocstore registers for the scheme
+// "cassandra".
+// The default URL opener connects to the comma-separated hosts in the
+// environment variable CASSANDRA_HOSTS, authenticating with
+// CASSANDRA_USERNAME and CASSANDRA_PASSWORD if they are set.
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Tables
+//
+// A Collection is a table whose primary key columns are the key fields of the
+// documents, and which has two more columns: "revision", of type text, and
+// "doc", of type text, which holds the other fields of a document as a JSON
+// object. For example, a collection of documents whose key is made of the
+// fields Game and Player, which are strings, can be created with:
+//
+//	CREATE TABLE highscores (
+//	    "Game" text,
+//	    "Player" text,
+//	    revision text,
+//	    doc text,
+//	    PRIMARY KEY (("Game"), "Player")
+//	)
+//
+// and opened with
+//
+//	OpenCollection(session, "highscores", []string{"Game"}, []string{"Player"}, nil)
+//
+// Names of tables and columns are quoted in CQL statements, so they are case
+// sensitive.
+//
+// The partition key and clustering columns may hold any CQL type that gocql
+// can convert the values of the key fields to. A Create of a document without
+// a key gives it a random string key; that requires a single key column of
+// type text.
+//
+// # Revisions
+//
+// Revisions are random strings stored in the revision column. Writes of
+// documents with revisions, and Creates, Replaces and Updates, are Cassandra
+// lightweight transactions, conditional on the revision or on the existence
+// of the row. Puts and Deletes of documents without revisions are not.
+// As the Cassandra documentation explains, conditional and unconditional
+// writes of the same row that are close in time may not be applied in the
+// order they were made.
+//
+// An Update reads the document, applies the mods to it and writes it if its
+// revision has not changed, trying again if it has, unless the document has a
+// revision.
+//
+// # Queries
+//
+// Filters on key fields that Cassandra can evaluate without ALLOW FILTERING
+// become the WHERE clause of the CQL query: equality filters on all the
+// partition key columns, then equality filters on a prefix of the clustering
+// columns, followed by range filters on the next clustering column. An
+// ordering by the first clustering column is also part of the query when the
+// partition is restricted, and so is the limit when nothing else is left to
+// do. The other filters, ordering, offset and limit are applied to the rows
+// as they are read. A query that does not restrict the partition reads the
+// whole table, and an ordering that is not part of the query reads all the
+// rows that the query matches.
+//
+// # As
+//
+// cassandradocstore exposes the following types for As:
+//   - Collection: *gocql.Session
+//   - ActionList.BeforeDo: *gocql.Query
+//   - Query.BeforeQuery: *gocql.Query
+//   - DocumentIterator: *gocql.Iter
+//   - Error: gocql.RequestError
+package cassandradocstore // import "gocloud.dev/docstore/cassandradocstore"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+)
+
+// The names of the columns that hold the revision and the rest of a document.
+const (
+	revisionColumn = "revision"
+	docColumn      = "doc"
+)
+
+// Options are optional arguments to the OpenCollection functions.
+type Options struct {
+	// The name of the field holding the document revision.
+	// Defaults to docstore.DefaultRevisionField.
+	RevisionField string
+
+	// The maximum number of concurrent statements run by a single call to
+	// ActionList.Do. If less than 1, there is no limit.
+	MaxOutstandingActionRPCs int
+}
+
+type collection struct {
+	session       *gocql.Session
+	table         string // quoted
+	partitionKey  []string
+	clusteringKey []string
+	keyFields     []string // partitionKey, then clusteringKey
+	opts          *Options
+}
+
+// OpenCollection creates a *docstore.Collection representing a Cassandra or
+// ScyllaDB table. table is the name of the table, qualified by its keyspace
+// if the session has no default keyspace, like "mykeyspace.mytable".
+// partitionKey holds the names of the fields stored in the partition key
+// columns, and clusteringKey those stored in the clustering columns, in the
+// order of the table's primary key. Together they make the key of a document.
+// clusteringKey may be empty.
+func OpenCollection(session *gocql.Session, table string, partitionKey, clusteringKey []string, opts *Options) (*docstore.Collection, error) {
+	c, err := newCollection(session, table, partitionKey, clusteringKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	return docstore.NewCollection(c), nil
+}
+
+func newCollection(session *gocql.Session, table string, partitionKey, clusteringKey []string, opts *Options) (*collection, error) {
+	if session == nil {
+		return nil, errors.New("cassandradocstore: nil Session")
+	}
+	if table == "" {
+		return nil, errors.New("cassandradocstore: empty table name")
+	}
+	if len(partitionKey) == 0 {
+		return nil, errors.New("cassandradocstore: at least one partition key field is required")
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.RevisionField == "" {
+		opts.RevisionField = docstore.DefaultRevisionField
+	}
+	keyFields := append(append([]string{}, partitionKey...), clusteringKey...)
+	seen := map[string]bool{}
+	for _, f := range keyFields {
+		switch {
+		case f == "":
+			return nil, errors.New("cassandradocstore: empty key field name")
+		case f == revisionColumn || f == docColumn || f == opts.RevisionField:
+			return nil, fmt.Errorf("cassandradocstore: key field name %q is reserved", f)
+		case seen[f]:
+			return nil, fmt.Errorf("cassandradocstore: duplicate key field %q", f)
+		}
+		seen[f] = true
+	}
+	var parts []string
+	for _, p := range strings.Split(table, ".") {
+		parts = append(parts, quote(p))
+	}
+	return &collection{
+		session:       session,
+		table:         strings.Join(parts, "."),
+		partitionKey:  partitionKey,
+		clusteringKey: clusteringKey,
+		keyFields:     keyFields,
+		opts:          opts,
+	}, nil
+}
+
+// quote returns the quoted CQL identifier for name.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Key implements driver.Collection.Key.
+func (c *collection) Key(doc driver.Document) (interface{}, error) {
+	vals, missing, err := c.keyValues(doc)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case missing == len(vals):
+		return nil, nil // missing key is not an error
+	case missing > 0:
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "document is missing some of its key fields %v", c.keyFields)
+	case len(vals) == 1:
+		if reflect.TypeOf(vals[0]).Comparable() {
+			return vals[0], nil
+		}
+	}
+	// Return a comparable value made of all the key values.
+	enc := make([]interface{}, len(vals))
+	for i, v := range vals {
+		if enc[i], err = encodeValue(v); err != nil {
+			return nil, err
+		}
+	}
+	b, err := json.Marshal(enc)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "encoding key")
+	}
+	return string(b), nil
+}
+
+// keyValues returns the values of the key fields of doc, and how many of
+// them are missing or empty.
+func (c *collection) keyValues(doc driver.Document) ([]interface{}, int, error) {
+	vals := make([]interface{}, len(c.keyFields))
+	missing := 0
+	for i, f := range c.keyFields {
+		v, err := doc.GetField(f)
+		if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return nil, 0, err
+		}
+		if v == nil || driver.IsEmptyValue(reflect.ValueOf(v)) {
+			missing++
+			continue
+		}
+		vals[i] = v
+	}
+	return vals, missing, nil
+}
+
+// RevisionField implements driver.Collection.RevisionField.
+func (c *collection) RevisionField() string {
+	return c.opts.RevisionField
+}
+
+// keyCondition returns a CQL condition selecting the row with the key of a
+// document.
+func (c *collection) keyCondition() string {
+	conds := make([]string, len(c.keyFields))
+	for i, f := range c.keyFields {
+		conds[i] = quote(f) + " = ?"
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// encodeDocColumn returns the value of the document column for doc: a JSON
+// object of its fields other than its key fields.
+func (c *collection) encodeDocColumn(doc driver.Document) (string, error) {
+	m, err := encodeDoc(doc)
+	if err != nil {
+		return "", err
+	}
+	return c.marshalDocColumn(m)
+}
+
+// marshalDocColumn is like encodeDocColumn, for an encoded document.
+func (c *collection) marshalDocColumn(m map[string]interface{}) (string, error) {
+	for _, f := range c.keyFields {
+		delete(m, f)
+	}
+	// A document with a revision field keeps it, with a null value, so that
+	// reads return the revision.
+	if _, ok := m[c.opts.RevisionField]; ok {
+		m[c.opts.RevisionField] = nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", gcerr.Newf(gcerr.InvalidArgument, err, "encoding document")
+	}
+	return string(b), nil
+}
+
+// decodeDocColumn decodes the document column s, and sets the revision field
+// to rev if the document has one.
+func (c *collection) decodeDocColumn(s, rev string) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "decoding %s column", docColumn)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	if _, ok := m[c.opts.RevisionField]; ok {
+		m[c.opts.RevisionField] = rev
+	}
+	return m, nil
+}
+
+// revision returns the revision of doc, or "" if it has none.
+func (c *collection) revision(doc driver.Document) (string, error) {
+	v, err := doc.GetField(c.opts.RevisionField)
+	if err != nil || v == nil {
+		return "", nil // no revision field, or a nil one
+	}
+	rev, ok := v.(string)
+	if !ok {
+		return "", gcerr.Newf(gcerr.InvalidArgument, nil, "revision field %s holds a %T, not a string", c.opts.RevisionField, v)
+	}
+	return rev, nil
+}
+
+// setRevision sets the revision field of doc to rev, if it has one.
+func (c *collection) setRevision(doc driver.Document, rev string) error {
+	if !doc.HasField(c.opts.RevisionField) {
+		return nil
+	}
+	return doc.SetField(c.opts.RevisionField, rev)
+}
+
+// RunActions implements driver.Collection.RunActions.
+func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	errs := make([]error, len(actions))
+	beforeGets, gets, writes, afterGets := driver.GroupActions(actions)
+	c.runActions(ctx, beforeGets, errs, opts)
+	done := make(chan struct{})
+	go func() {
+		c.runActions(ctx, writes, errs, opts)
+		close(done)
+	}()
+	c.runActions(ctx, gets, errs, opts)
+	<-done
+	c.runActions(ctx, afterGets, errs, opts)
+	return driver.NewActionListError(errs)
+}
+
+// runActions runs actions concurrently, and stores their errors in errs.
+func (c *collection) runActions(ctx context.Context, actions []*driver.Action, errs []error, opts *driver.RunActionsOptions) {
+	t := driver.NewThrottle(c.opts.MaxOutstandingActionRPCs)
+	for _, a := range actions {
+		a := a
+		t.Acquire()
+		go func() {
+			defer t.Release()
+			errs[a.Index] = c.runAction(ctx, a, opts)
+		}()
+	}
+	t.Wait()
+}
+
+func (c *collection) runAction(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	switch a.Kind {
+	case driver.Get:
+		return c.get(ctx, a, opts)
+	case driver.Create:
+		return c.create(ctx, a, opts)
+	case driver.Replace, driver.Put:
+		return c.write(ctx, a, opts)
+	case driver.Update:
+		return c.update(ctx, a, opts)
+	case driver.Delete:
+		return c.delete(ctx, a, opts)
+	default:
+		return gcerr.Newf(gcerr.Internal, nil, "bad action %+v", a)
+	}
+}
+
+// query returns a query for stmt with values, after calling opts.BeforeDo
+// with it.
+func (c *collection) query(ctx context.Context, opts *driver.RunActionsOptions, stmt string, values ...interface{}) (*gocql.Query, error) {
+	q := c.session.Query(stmt, values...).WithContext(ctx)
+	if opts.BeforeDo != nil {
+		if err := opts.BeforeDo(driver.AsFunc(q)); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// read reads the revision and document columns of the row of doc.
+func (c *collection) read(ctx context.Context, doc driver.Document, opts *driver.RunActionsOptions) (rev, docCol string, err error) {
+	keys, _, err := c.keyValues(doc)
+	if err != nil {
+		return "", "", err
+	}
+	stmt := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s", revisionColumn, docColumn, c.table, c.keyCondition())
+	q, err := c.query(ctx, opts, stmt, keys...)
+	if err != nil {
+		return "", "", err
+	}
+	if err := q.Scan(&rev, &docCol); err != nil {
+		if err == gocql.ErrNotFound {
+			return "", "", gcerr.Newf(gcerr.NotFound, err, "document with key %v does not exist", keys)
+		}
+		return "", "", err
+	}
+	return rev, docCol, nil
+}
+
+func (c *collection) get(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	rev, docCol, err := c.read(ctx, a.Doc, opts)
+	if err != nil {
+		return err
+	}
+	m, err := c.decodeDocColumn(docCol, rev)
+	if err != nil {
+		return err
+	}
+	return decodeDoc(m, a.Doc, a.FieldPaths)
+}
+
+func (c *collection) create(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	if a.Key == nil {
+		if len(c.keyFields) != 1 {
+			return gcerr.Newf(gcerr.InvalidArgument, nil, "cannot generate a key for a document with key fields %v", c.keyFields)
+		}
+		if err := a.Doc.SetField(c.keyFields[0], driver.UniqueString()); err != nil {
+			return gcerr.Newf(gcerr.InvalidArgument, err, "setting the key field")
+		}
+	}
+	keys, _, err := c.keyValues(a.Doc)
+	if err != nil {
+		return err
+	}
+	docCol, err := c.encodeDocColumn(a.Doc)
+	if err != nil {
+		return err
+	}
+	cols := make([]string, len(c.keyFields))
+	for i, f := range c.keyFields {
+		cols[i] = quote(f)
+	}
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (%s?, ?) IF NOT EXISTS",
+		c.table, strings.Join(cols, ", "), revisionColumn, docColumn, strings.Repeat("?, ", len(cols)))
+	rev := driver.UniqueString()
+	q, err := c.query(ctx, opts, stmt, append(keys, rev, docCol)...)
+	if err != nil {
+		return err
+	}
+	applied, err := q.MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return gcerr.Newf(gcerr.AlreadyExists, nil, "document with key %v exists", keys)
+	}
+	return c.setRevision(a.Doc, rev)
+}
+
+// write runs a Replace or Put.
+func (c *collection) write(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	oldRev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	keys, _, err := c.keyValues(a.Doc)
+	if err != nil {
+		return err
+	}
+	docCol, err := c.encodeDocColumn(a.Doc)
+	if err != nil {
+		return err
+	}
+	rev := driver.UniqueString()
+	if a.Kind == driver.Put && oldRev == "" {
+		cols := make([]string, len(c.keyFields))
+		for i, f := range c.keyFields {
+			cols[i] = quote(f)
+		}
+		stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (%s?, ?)",
+			c.table, strings.Join(cols, ", "), revisionColumn, docColumn, strings.Repeat("?, ", len(cols)))
+		q, err := c.query(ctx, opts, stmt, append(keys, rev, docCol)...)
+		if err != nil {
+			return err
+		}
+		if err := q.Exec(); err != nil {
+			return err
+		}
+		return c.setRevision(a.Doc, rev)
+	}
+	if err := c.replace(ctx, opts, keys, oldRev, rev, docCol); err != nil {
+		return err
+	}
+	return c.setRevision(a.Doc, rev)
+}
+
+// replace sets the revision and document columns of the row with keys to rev
+// and docCol, if the row exists and, if oldRev is not empty, has that
+// revision.
+func (c *collection) replace(ctx context.Context, opts *driver.RunActionsOptions, keys []interface{}, oldRev, rev, docCol string) error {
+	stmt := fmt.Sprintf("UPDATE %s SET %s = ?, %s = ? WHERE %s IF ", c.table, revisionColumn, docColumn, c.keyCondition())
+	values := append([]interface{}{rev, docCol}, keys...)
+	if oldRev == "" {
+		stmt += "EXISTS"
+	} else {
+		stmt += revisionColumn + " = ?"
+		values = append(values, oldRev)
+	}
+	q, err := c.query(ctx, opts, stmt, values...)
+	if err != nil {
+		return err
+	}
+	applied, err := q.MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	switch {
+	case applied:
+		return nil
+	case oldRev == "":
+		return gcerr.Newf(gcerr.NotFound, nil, "document with key %v does not exist", keys)
+	default:
+		return gcerr.Newf(gcerr.FailedPrecondition, nil, "document with key %v does not exist or has a different revision", keys)
+	}
+}
+
+// maxUpdateAttempts is the number of times an Update reads and writes a
+// document that changes in between.
+const maxUpdateAttempts = 5
+
+func (c *collection) update(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	wantRev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	for _, mod := range a.Mods {
+		for _, f := range c.keyFields {
+			if mod.FieldPath[0] == f {
+				return gcerr.Newf(gcerr.InvalidArgument, nil, "cannot update key field %q", f)
+			}
+		}
+	}
+	keys, _, err := c.keyValues(a.Doc)
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		oldRev, docCol, err := c.read(ctx, a.Doc, opts)
+		if err != nil {
+			return err
+		}
+		if wantRev != "" && wantRev != oldRev {
+			return gcerr.Newf(gcerr.FailedPrecondition, nil, "document with key %v has a different revision", keys)
+		}
+		m, err := c.decodeDocColumn(docCol, "")
+		if err != nil {
+			return err
+		}
+		if err := applyMods(m, a.Mods); err != nil {
+			return err
+		}
+		newDocCol, err := c.marshalDocColumn(m)
+		if err != nil {
+			return err
+		}
+		rev := driver.UniqueString()
+		err = c.replace(ctx, opts, keys, oldRev, rev, newDocCol)
+		if err == nil {
+			return c.setRevision(a.Doc, rev)
+		}
+		if wantRev != "" || c.ErrorCode(err) != gcerrors.FailedPrecondition || attempt == maxUpdateAttempts {
+			return err
+		}
+	}
+}
+
+func (c *collection) delete(ctx context.Context, a *driver.Action, opts *driver.RunActionsOptions) error {
+	rev, err := c.revision(a.Doc)
+	if err != nil {
+		return err
+	}
+	keys, _, err := c.keyValues(a.Doc)
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s", c.table, c.keyCondition())
+	if rev == "" {
+		// Deleting a document that does not exist is not an error.
+		q, err := c.query(ctx, opts, stmt, keys...)
+		if err != nil {
+			return err
+		}
+		return q.Exec()
+	}
+	q, err := c.query(ctx, opts, stmt+" IF "+revisionColumn+" = ?", append(keys, rev)...)
+	if err != nil {
+		return err
+	}
+	applied, err := q.MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return gcerr.Newf(gcerr.FailedPrecondition, nil, "document with key %v does not exist or has a different revision", keys)
+	}
+	return nil
+}
+
+// RevisionToBytes implements driver.Collection.RevisionToBytes.
+func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	s, ok := rev.(string)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "revision %v of type %[1]T is not a string", rev)
+	}
+	return []byte(s), nil
+}
+
+// BytesToRevision implements driver.Collection.BytesToRevision.
+func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	return string(b), nil
+}
+
+// As implements driver.Collection.As.
+func (c *collection) As(i interface{}) bool {
+	p, ok := i.(**gocql.Session)
+	if !ok {
+		return false
+	}
+	*p = c.session
+	return true
+}
+
+// ErrorAs implements driver.Collection.ErrorAs.
+func (c *collection) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// The codes of CQL protocol errors that ErrorCode maps.
+// See https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec.
+const (
+	errCredentials  = 0x0100
+	errUnavailable  = 0x1000
+	errOverloaded   = 0x1001
+	errWriteTimeout = 0x1100
+	errReadTimeout  = 0x1200
+	errSyntax       = 0x2000
+	errUnauthorized = 0x2100
+	errInvalid      = 0x2200
+	errAlreadyExist = 0x2400
+)
+
+// ErrorCode implements driver.Collection.ErrorCode.
+func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
+	if err == nil {
+		return gcerrors.OK
+	}
+	var gerr *gcerr.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code
+	}
+	switch {
+	case errors.Is(err, gocql.ErrNotFound):
+		return gcerrors.NotFound
+	case errors.Is(err, gocql.ErrTimeoutNoResponse):
+		return gcerrors.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return gcerrors.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return gcerrors.DeadlineExceeded
+	}
+	var rerr gocql.RequestError
+	if !errors.As(err, &rerr) {
+		return gcerrors.Unknown
+	}
+	switch rerr.Code() {
+	case errSyntax, errInvalid:
+		return gcerrors.InvalidArgument
+	case errCredentials, errUnauthorized:
+		return gcerrors.PermissionDenied
+	case errOverloaded, errUnavailable:
+		return gcerrors.ResourceExhausted
+	case errWriteTimeout, errReadTimeout:
+		return gcerrors.DeadlineExceeded
+	case errAlreadyExist:
+		return gcerrors.AlreadyExists
+	default:
+		return gcerrors.Unknown
+	}
+}
+
+// Close implements driver.Collection.Close. It does not close the session.
+func (c *collection) Close() error { return nil }
@@ -0,0 +1,244 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+// To run these tests against a real ScyllaDB server, first run
+// ./localcassandra.sh. Then wait until the server accepts connections.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/testing/setup"
+)
+
+const (
+	serverHost   = "localhost"
+	keyspace     = "docstore_test"
+	table1       = "docstore_test_1"
+	table2       = "docstore_test_2"
+	connectLimit = 10 * time.Second
+)
+
+type harness struct {
+	session *gocql.Session
+}
+
+func (h *harness) MakeCollection(ctx context.Context, kind drivertest.CollectionKind) (driver.Collection, error) {
+	var coll *collection
+	var err error
+	switch kind {
+	case drivertest.SingleKey, drivertest.NoRev:
+		coll, err = newCollection(h.session, table1, []string{drivertest.KeyField}, nil, nil)
+	case drivertest.TwoKey:
+		coll, err = newCollection(h.session, table2, []string{"Game"}, []string{"Player"}, nil)
+	case drivertest.AltRev:
+		coll, err = newCollection(h.session, table1, []string{drivertest.KeyField}, nil,
+			&Options{RevisionField: drivertest.AlternateRevisionField})
+	default:
+		panic("bad kind")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := h.session.Query("TRUNCATE " + coll.table).WithContext(ctx).Exec(); err != nil {
+		return nil, err
+	}
+	return coll, nil
+}
+
+func (*harness) BeforeDoTypes() []interface{} {
+	return []interface{}{&gocql.Query{}}
+}
+
+func (*harness) BeforeQueryTypes() []interface{} {
+	return []interface{}{&gocql.Query{}}
+}
+
+func (*harness) RevisionsEqual(rev1, rev2 interface{}) bool {
+	return rev1 == rev2
+}
+
+func (*harness) Close() {}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As"
+}
+
+func (verifyAs) CollectionCheck(coll *docstore.Collection) error {
+	var s *gocql.Session
+	if !coll.As(&s) {
+		return errors.New("Collection.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) QueryCheck(it *docstore.DocumentIterator) error {
+	var i *gocql.Iter
+	if !it.As(&i) {
+		return errors.New("DocumentIterator.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(c *docstore.Collection, err error) error {
+	// Creating an existing document fails in the driver, with no Cassandra
+	// error to expose.
+	var rerr gocql.RequestError
+	if c.ErrorAs(err, &rerr) {
+		return errors.New("Collection.ErrorAs succeeded for a driver error")
+	}
+	return nil
+}
+
+func TestConformance(t *testing.T) {
+	session := newTestSession(t)
+	defer session.Close()
+
+	newHarness := func(context.Context, *testing.T) (drivertest.Harness, error) {
+		return &harness{session}, nil
+	}
+	// The codec is the JSON encoding of the document column, which has no
+	// native counterpart; see codec_test.go.
+	drivertest.RunConformanceTests(t, newHarness, nil, []drivertest.AsTest{verifyAs{}})
+}
+
+// newTestSession connects to the local server and creates the test keyspace
+// and tables.
+func newTestSession(t *testing.T) *gocql.Session {
+	if !setup.HasDockerTestEnvironment() {
+		t.Skip("Skipping Cassandra tests since the Cassandra server is not available")
+	}
+	cluster := gocql.NewCluster(serverHost)
+	cluster.Timeout = connectLimit
+	s, err := cluster.CreateSession()
+	if err != nil {
+		t.Skipf("Skipping Cassandra tests since the server at %s is not available: %v", serverHost, err)
+	}
+	for _, stmt := range []string{
+		fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}", keyspace),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s ("name" text PRIMARY KEY, revision text, doc text)`, keyspace, table1),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s ("Game" text, "Player" text, revision text, doc text, PRIMARY KEY (("Game"), "Player"))`, keyspace, table2),
+	} {
+		if err := s.Query(stmt).Exec(); err != nil {
+			s.Close()
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	s.Close()
+	cluster.Keyspace = keyspace
+	s, err = cluster.CreateSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestOpenCollection(t *testing.T) {
+	s := &gocql.Session{}
+	for _, test := range []struct {
+		table         string
+		partitionKey  []string
+		clusteringKey []string
+		wantTable     string
+		wantErr       bool
+	}{
+		{"t", []string{"a"}, nil, `"t"`, false},
+		{"ks.T", []string{"a", "b"}, []string{"c"}, `"ks"."T"`, false},
+		{"", []string{"a"}, nil, "", true},
+		{"t", nil, []string{"a"}, "", true},
+		{"t", []string{"a"}, []string{"a"}, "", true},
+		{"t", []string{"a", ""}, nil, "", true},
+		{"t", []string{docColumn}, nil, "", true},
+		{"t", []string{docstore.DefaultRevisionField}, nil, "", true},
+	} {
+		c, err := newCollection(s, test.table, test.partitionKey, test.clusteringKey, nil)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q %v %v: got error %v, want error %t", test.table, test.partitionKey, test.clusteringKey, err, test.wantErr)
+		}
+		if err == nil && c.table != test.wantTable {
+			t.Errorf("%q: got table %s, want %s", test.table, c.table, test.wantTable)
+		}
+	}
+	if _, err := newCollection(nil, "t", []string{"a"}, nil, nil); err == nil {
+		t.Error("got nil error for a nil session")
+	}
+}
+
+func TestKey(t *testing.T) {
+	c, err := newCollection(&gocql.Session{}, "t", []string{"Game"}, []string{"Player"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		doc      map[string]interface{}
+		want     interface{}
+		wantCode gcerrors.ErrorCode
+	}{
+		{map[string]interface{}{"Game": "g", "Player": "p"}, `["g","p"]`, gcerrors.OK},
+		{map[string]interface{}{"Score": 1}, nil, gcerrors.OK},
+		{map[string]interface{}{"Game": "g"}, nil, gcerrors.InvalidArgument},
+	} {
+		got, err := c.Key(drivertest.MustDocument(test.doc))
+		if gcerrors.Code(err) != test.wantCode {
+			t.Errorf("%v: got error %v, want code %v", test.doc, err, test.wantCode)
+		}
+		if got != test.want {
+			t.Errorf("%v: got %v, want %v", test.doc, got, test.want)
+		}
+	}
+}
+
+type requestError struct{ code int }
+
+func (e requestError) Code() int       { return e.code }
+func (e requestError) Message() string { return "message" }
+func (e requestError) Error() string   { return "request error" }
+
+func TestErrorCode(t *testing.T) {
+	c := &collection{}
+	for _, test := range []struct {
+		err  error
+		want gcerrors.ErrorCode
+	}{
+		{nil, gcerrors.OK},
+		{gcerr.Newf(gcerr.FailedPrecondition, nil, "x"), gcerrors.FailedPrecondition},
+		{gocql.ErrNotFound, gcerrors.NotFound},
+		{gocql.ErrTimeoutNoResponse, gcerrors.DeadlineExceeded},
+		{fmt.Errorf("wrapped: %w", context.Canceled), gcerrors.Canceled},
+		{requestError{errSyntax}, gcerrors.InvalidArgument},
+		{requestError{errUnauthorized}, gcerrors.PermissionDenied},
+		{requestError{errOverloaded}, gcerrors.ResourceExhausted},
+		{fmt.Errorf("wrapped: %w", requestError{errWriteTimeout}), gcerrors.DeadlineExceeded},
+		{requestError{errAlreadyExist}, gcerrors.AlreadyExists},
+		{requestError{0x0000}, gcerrors.Unknown},
+		{errors.New("other"), gcerrors.Unknown},
+	} {
+		if got := c.ErrorCode(test.err); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.err, got, test.want)
+		}
+	}
+}
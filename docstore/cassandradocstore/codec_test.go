@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/drivertest"
+)
+
+type aStruct struct {
+	Name             string
+	I                int
+	U                uint
+	F                float64
+	S                string
+	B                []byte
+	T                time.Time
+	L                []int
+	M                map[string]bool
+	P                *string
+	DocstoreRevision interface{}
+}
+
+func TestDocColumnRoundTrip(t *testing.T) {
+	c := &collection{keyFields: []string{"Name"}, opts: &Options{RevisionField: docstore.DefaultRevisionField}}
+	s := "p"
+	in := &aStruct{
+		Name:             "n",
+		I:                -3,
+		U:                1 << 60,
+		F:                2.5,
+		S:                "str",
+		B:                []byte{0, 1, 2},
+		T:                time.Date(2019, time.March, 27, 1, 2, 3, 5*1e6+7, time.FixedZone("X", 3600)),
+		L:                []int{4, 5},
+		M:                map[string]bool{"a": true},
+		P:                &s,
+		DocstoreRevision: "old",
+	}
+	col, err := c.encodeDocColumn(drivertest.MustDocument(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The column holds neither the key nor the revision.
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(col), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["Name"]; ok {
+		t.Errorf("document column %s has the key field", col)
+	}
+	if rev, ok := raw[docstore.DefaultRevisionField]; !ok || rev != nil {
+		t.Errorf("document column %s: got revision %v, want null", col, rev)
+	}
+
+	m, err := c.decodeDocColumn(col, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m["Name"] = "n"
+	got := &aStruct{}
+	if err := decodeDoc(m, drivertest.MustDocument(got), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !got.T.Equal(in.T) {
+		t.Errorf("got time %v, want %v", got.T, in.T)
+	}
+	got.T = in.T
+	in.DocstoreRevision = "new"
+	if diff := cmp.Diff(got, in); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDecodeInterface(t *testing.T) {
+	c := &collection{opts: &Options{RevisionField: docstore.DefaultRevisionField}}
+	in := map[string]interface{}{
+		"i": 3,
+		"f": 2.5,
+		"b": []byte("abc"),
+		"l": []interface{}{int64(1), "x", nil},
+		"m": map[string]interface{}{"n": 7},
+	}
+	col, err := c.encodeDocColumn(drivertest.MustDocument(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := c.decodeDocColumn(col, "rev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]interface{}{}
+	if err := decodeDoc(m, drivertest.MustDocument(got), nil); err != nil {
+		t.Fatal(err)
+	}
+	// A document written without a revision field is read without one.
+	want := map[string]interface{}{
+		"i": int64(3),
+		"f": 2.5,
+		"b": []byte("abc"),
+		"l": []interface{}{int64(1), "x", nil},
+		"m": map[string]interface{}{"n": int64(7)},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFromColumn(t *testing.T) {
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("X", 3600))
+	uuid := gocql.TimeUUID()
+	for _, test := range []struct {
+		in interface{}
+		// want is the value encoding the column value gives; nil means in.
+		want interface{}
+	}{
+		{"s", nil},
+		{true, nil},
+		{int32(7), int64(7)},
+		{int64(-8), nil},
+		{float32(1.5), 1.5},
+		{tm, nil},
+		{uuid, uuid.String()},
+		{[]byte("b"), nil},
+	} {
+		want := test.want
+		if want == nil {
+			want = test.in
+		}
+		enc, err := encodeValue(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := fromColumn(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compare the values as JSON, as they are in the document column.
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(enc)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%v: got %s, want %s", test.in, gotJSON, wantJSON)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore_test
+
+import (
+	"context"
+	"log"
+
+	"github.com/gocql/gocql"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/cassandradocstore"
+)
+
+func ExampleOpenCollection() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	cluster := gocql.NewCluster("my-host")
+	cluster.Keyspace = "my-keyspace"
+	session, err := cluster.CreateSession()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer session.Close()
+
+	// The table has the partition key column "Game" and the clustering
+	// column "Player".
+	coll, err := cassandradocstore.OpenCollection(session, "highscores", []string{"Game"}, []string{"Player"}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer coll.Close()
+}
+
+func Example_openCollectionFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/docstore/cassandradocstore"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// docstore.OpenCollection creates a *docstore.Collection from a URL.
+	coll, err := docstore.OpenCollection(ctx, "cassandra://my-keyspace/highscores?partition_key=Game&clustering_key=Player")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer coll.Close()
+}
@@ -0,0 +1,463 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"gocloud.dev/docstore/driver"
+)
+
+// A queryPlan is how a driver.Query runs: a CQL statement, and what is left to
+// do with the rows it returns.
+type queryPlan struct {
+	stmt   string
+	values []interface{}
+	// The filters that are applied to the rows.
+	filters []driver.Filter
+	// The ordering that is applied to the rows, if any.
+	orderBy        string
+	orderAscending bool
+	// The number of rows to skip, and the number of rows to return after
+	// that, or zero for all.
+	offset, limit int
+}
+
+// isRange reports whether op is a range comparison.
+func isRange(op string) bool {
+	return op == "<" || op == "<=" || op == ">" || op == ">="
+}
+
+// planQuery returns the plan of q.
+func (c *collection) planQuery(q *driver.Query) *queryPlan {
+	p := &queryPlan{}
+	rest := append([]driver.Filter(nil), q.Filters...)
+	// take removes the first filter on field f with an operator for which ok
+	// is true from rest, and returns it.
+	take := func(f string, ok func(op string) bool) (driver.Filter, bool) {
+		for i, fl := range rest {
+			if len(fl.FieldPath) == 1 && fl.FieldPath[0] == f && ok(fl.Op) {
+				rest = append(rest[:i], rest[i+1:]...)
+				return fl, true
+			}
+		}
+		return driver.Filter{}, false
+	}
+	isEqual := func(op string) bool { return op == driver.EqualOp }
+
+	var conds []string
+	partitionRestricted := true
+	for _, f := range c.partitionKey {
+		if _, ok := findFilter(rest, f, isEqual); !ok {
+			partitionRestricted = false
+			break
+		}
+	}
+	if partitionRestricted {
+		for _, f := range c.partitionKey {
+			fl, _ := take(f, isEqual)
+			conds = append(conds, quote(f)+" = ?")
+			p.values = append(p.values, fl.Value)
+		}
+		for _, f := range c.clusteringKey {
+			if fl, ok := take(f, isEqual); ok {
+				conds = append(conds, quote(f)+" = ?")
+				p.values = append(p.values, fl.Value)
+				continue
+			}
+			for {
+				fl, ok := take(f, isRange)
+				if !ok {
+					break
+				}
+				conds = append(conds, quote(f)+" "+fl.Op+" ?")
+				p.values = append(p.values, fl.Value)
+			}
+			break
+		}
+	}
+	p.filters = rest
+
+	cols := []string{revisionColumn, docColumn}
+	for _, f := range c.keyFields {
+		cols = append(cols, quote(f))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "SELECT %s FROM %s", strings.Join(cols, ", "), c.table)
+	if len(conds) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(conds, " AND "))
+	}
+	orderPushed := false
+	if q.OrderByField != "" {
+		if partitionRestricted && len(c.clusteringKey) > 0 && q.OrderByField == c.clusteringKey[0] {
+			dir := "ASC"
+			if !q.OrderAscending {
+				dir = "DESC"
+			}
+			fmt.Fprintf(&sb, " ORDER BY %s %s", quote(q.OrderByField), dir)
+			orderPushed = true
+		} else {
+			p.orderBy = q.OrderByField
+			p.orderAscending = q.OrderAscending
+		}
+	}
+	p.offset = max(q.Offset, 0)
+	p.limit = q.Limit
+	if q.Limit > 0 && len(p.filters) == 0 && (q.OrderByField == "" || orderPushed) {
+		fmt.Fprintf(&sb, " LIMIT %d", p.offset+q.Limit)
+	}
+	p.stmt = sb.String()
+	return p
+}
+
+// findFilter returns the first filter of fs on the field f with an operator
+// for which ok is true.
+func findFilter(fs []driver.Filter, f string, ok func(op string) bool) (driver.Filter, bool) {
+	for _, fl := range fs {
+		if len(fl.FieldPath) == 1 && fl.FieldPath[0] == f && ok(fl.Op) {
+			return fl, true
+		}
+	}
+	return driver.Filter{}, false
+}
+
+// RunGetQuery implements driver.Collection.RunGetQuery.
+func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	p := c.planQuery(q)
+	filters, err := encodeFilters(p.filters)
+	if err != nil {
+		return nil, err
+	}
+	cq := c.session.Query(p.stmt, p.values...).WithContext(ctx)
+	if q.BeforeQuery != nil {
+		if err := q.BeforeQuery(driver.AsFunc(cq)); err != nil {
+			return nil, err
+		}
+	}
+	it := &docIterator{
+		coll:       c,
+		iter:       cq.Iter(),
+		filters:    filters,
+		fieldPaths: c.withKey(q.FieldPaths),
+		skip:       p.offset,
+		limit:      p.limit,
+	}
+	if p.orderBy != "" {
+		// Read all the matching rows, to order them.
+		var rows []map[string]interface{}
+		for {
+			m, err := it.nextMatch()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, m)
+		}
+		sortRows(rows, p.orderBy, p.orderAscending)
+		it.rows = rows
+		it.sorted = true
+	}
+	return it, nil
+}
+
+// withKey returns fps with the key fields, if fps is not empty.
+func (c *collection) withKey(fps [][]string) [][]string {
+	if len(fps) == 0 {
+		return nil
+	}
+	res := make([][]string, 0, len(fps)+len(c.keyFields))
+	for _, f := range c.keyFields {
+		res = append(res, []string{f})
+	}
+	return append(res, fps...)
+}
+
+// encodeFilters returns fs with their values encoded, so they compare with
+// the values of rows.
+func encodeFilters(fs []driver.Filter) ([]driver.Filter, error) {
+	res := make([]driver.Filter, len(fs))
+	for i, f := range fs {
+		v, err := encodeValue(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		f.Value = v
+		res[i] = f
+	}
+	return res, nil
+}
+
+type docIterator struct {
+	coll       *collection
+	iter       *gocql.Iter
+	filters    []driver.Filter
+	fieldPaths [][]string
+	skip       int  // rows left to skip
+	limit      int  // rows to return, or zero for all
+	returned   int  // rows returned
+	sorted     bool // all matching rows are in rows
+	rows       []map[string]interface{}
+	err        error
+}
+
+// nextMatch returns the next row read that matches the filters, as a
+// document map.
+func (it *docIterator) nextMatch() (map[string]interface{}, error) {
+	for {
+		row := map[string]interface{}{}
+		if !it.iter.MapScan(row) {
+			if err := it.iter.Close(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		m, err := it.coll.rowToDoc(row)
+		if err != nil {
+			return nil, err
+		}
+		if filtersMatch(it.filters, m) {
+			return m, nil
+		}
+	}
+}
+
+// rowToDoc returns the document map of a row read by a query.
+func (c *collection) rowToDoc(row map[string]interface{}) (map[string]interface{}, error) {
+	rev, _ := row[revisionColumn].(string)
+	docCol, _ := row[docColumn].(string)
+	m, err := c.decodeDocColumn(docCol, rev)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range c.keyFields {
+		v, err := fromColumn(row[f])
+		if err != nil {
+			return nil, err
+		}
+		m[f] = v
+	}
+	return m, nil
+}
+
+// Next implements driver.DocumentIterator.Next.
+func (it *docIterator) Next(ctx context.Context, doc driver.Document) error {
+	if it.err != nil {
+		return it.err
+	}
+	it.err = it.next(doc)
+	return it.err
+}
+
+func (it *docIterator) next(doc driver.Document) error {
+	for {
+		if it.limit > 0 && it.returned >= it.limit {
+			return io.EOF
+		}
+		var m map[string]interface{}
+		if it.sorted {
+			if len(it.rows) == 0 {
+				return io.EOF
+			}
+			m = it.rows[0]
+			it.rows = it.rows[1:]
+		} else {
+			var err error
+			if m, err = it.nextMatch(); err != nil {
+				return err
+			}
+		}
+		if it.skip > 0 {
+			it.skip--
+			continue
+		}
+		if err := decodeDoc(m, doc, it.fieldPaths); err != nil {
+			return err
+		}
+		it.returned++
+		return nil
+	}
+}
+
+// Stop implements driver.DocumentIterator.Stop.
+func (it *docIterator) Stop() {
+	it.err = io.EOF
+	it.iter.Close()
+}
+
+// As implements driver.DocumentIterator.As.
+func (it *docIterator) As(i interface{}) bool {
+	p, ok := i.(**gocql.Iter)
+	if !ok {
+		return false
+	}
+	*p = it.iter
+	return true
+}
+
+func filtersMatch(fs []driver.Filter, m map[string]interface{}) bool {
+	for _, f := range fs {
+		if !filterMatches(f, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterMatches reports whether the document map m matches f, whose value is
+// encoded.
+func filterMatches(f driver.Filter, m map[string]interface{}) bool {
+	v, ok := getAtFieldPath(m, f.FieldPath)
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case "in", "not-in":
+		in := false
+		for _, e := range f.Value.([]interface{}) {
+			if c, ok := compareValues(v, e); ok && c == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (f.Op == "in")
+	}
+	c, ok := compareValues(v, f.Value)
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case driver.EqualOp:
+		return c == 0
+	case ">":
+		return c > 0
+	case "<":
+		return c < 0
+	case ">=":
+		return c >= 0
+	case "<=":
+		return c <= 0
+	default:
+		return false
+	}
+}
+
+// compareValues compares two encoded values of the same kind: numbers,
+// strings, which include times, or bools. It returns false for other values.
+func compareValues(x, y interface{}) (int, bool) {
+	switch x := x.(type) {
+	case string:
+		y, ok := y.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case bool:
+		y, ok := y.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x == y:
+			return 0, true
+		case x:
+			return 1, true
+		default:
+			return -1, true
+		}
+	}
+	xi, xf, xk := number(x)
+	yi, yf, yk := number(y)
+	switch {
+	case xk == 0 || yk == 0:
+		return 0, false
+	case xk == isInt && yk == isInt:
+		return cmpOrdered(xi, yi), true
+	default:
+		return cmpOrdered(xf, yf), true
+	}
+}
+
+func cmpOrdered[T int64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sortRows sorts the document maps rows by field f. Rows without the field,
+// or with a value that does not compare with the others, come first.
+func sortRows(rows []map[string]interface{}, f string, asc bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		x, okx := rows[i][f]
+		y, oky := rows[j][f]
+		var c int
+		switch {
+		case !okx || !oky:
+			c = cmpBool(okx, oky)
+		default:
+			var ok bool
+			if c, ok = compareValues(x, y); !ok {
+				return false
+			}
+		}
+		if asc {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+func cmpBool(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case x:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// QueryPlan implements driver.Collection.QueryPlan.
+func (c *collection) QueryPlan(q *driver.Query) (string, error) {
+	p := c.planQuery(q)
+	var post []string
+	if len(p.filters) > 0 {
+		var fs []string
+		for _, f := range p.filters {
+			fs = append(fs, fmt.Sprintf("%s %s %v", strings.Join(f.FieldPath, "."), f.Op, f.Value))
+		}
+		post = append(post, "filtered by "+strings.Join(fs, " AND "))
+	}
+	if p.orderBy != "" {
+		post = append(post, "sorted by "+p.orderBy)
+	}
+	if len(post) == 0 {
+		return p.stmt, nil
+	}
+	return p.stmt + ", " + strings.Join(post, ", "), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore/driver"
+)
+
+func TestQueryPlan(t *testing.T) {
+	c, err := newCollection(&gocql.Session{}, "scores", []string{"Game", "Region"}, []string{"Player", "Time"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const sel = `SELECT revision, doc, "Game", "Region", "Player", "Time" FROM "scores"`
+	eq := func(f string, v interface{}) driver.Filter {
+		return driver.Filter{FieldPath: []string{f}, Op: driver.EqualOp, Value: v}
+	}
+	for _, test := range []struct {
+		q          *driver.Query
+		want       string
+		wantValues []interface{}
+	}{
+		{
+			q:    &driver.Query{Limit: 3},
+			want: sel + " LIMIT 3",
+		},
+		{
+			// Without the whole partition key, every filter is applied in memory.
+			q: &driver.Query{
+				Filters:      []driver.Filter{eq("Game", "g"), eq("Player", "p")},
+				OrderByField: "Player",
+				Limit:        3,
+			},
+			want: sel + ", filtered by Game = g AND Player = p, sorted by Player",
+		},
+		{
+			q: &driver.Query{
+				Filters: []driver.Filter{
+					eq("Region", "eu"),
+					{FieldPath: []string{"Player"}, Op: ">", Value: "m"},
+					eq("Game", "g"),
+					{FieldPath: []string{"Player"}, Op: "<=", Value: "t"},
+					{FieldPath: []string{"Time"}, Op: ">", Value: 1},
+				},
+				OrderByField:   "Player",
+				OrderAscending: false,
+				Offset:         1,
+				Limit:          2,
+			},
+			want: sel + ` WHERE "Game" = ? AND "Region" = ? AND "Player" > ? AND "Player" <= ? ORDER BY "Player" DESC` +
+				", filtered by Time > 1",
+			wantValues: []interface{}{"g", "eu", "m", "t"},
+		},
+		{
+			q: &driver.Query{
+				Filters:        []driver.Filter{eq("Game", "g"), eq("Region", "eu"), eq("Player", "p"), {FieldPath: []string{"Time"}, Op: ">=", Value: 5}},
+				OrderByField:   "Player",
+				OrderAscending: true,
+				Offset:         2,
+				Limit:          2,
+			},
+			want:       sel + ` WHERE "Game" = ? AND "Region" = ? AND "Player" = ? AND "Time" >= ? ORDER BY "Player" ASC LIMIT 4`,
+			wantValues: []interface{}{"g", "eu", "p", 5},
+		},
+		{
+			// Orderings by other fields are done in memory, after reading all the rows.
+			q: &driver.Query{
+				Filters:      []driver.Filter{eq("Game", "g"), eq("Region", "eu"), {FieldPath: []string{"Score"}, Op: "in", Value: []int{1, 2}}},
+				OrderByField: "Time",
+				Limit:        1,
+			},
+			want:       sel + ` WHERE "Game" = ? AND "Region" = ?, filtered by Score in [1 2], sorted by Time`,
+			wantValues: []interface{}{"g", "eu"},
+		},
+	} {
+		got, err := c.QueryPlan(test.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%+v:\ngot  %s\nwant %s", test.q, got, test.want)
+		}
+		if diff := cmp.Diff(c.planQuery(test.q).values, test.wantValues); diff != "" {
+			t.Errorf("%+v: values: %s", test.q, diff)
+		}
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	m := map[string]interface{}{
+		"n": json.Number("3"),
+		"f": json.Number("2.5"),
+		"s": "abc",
+		"b": true,
+		"a": map[string]interface{}{"x": json.Number("1")},
+	}
+	for _, test := range []struct {
+		f    driver.Filter
+		want bool
+	}{
+		{driver.Filter{FieldPath: []string{"n"}, Op: "=", Value: int64(3)}, true},
+		{driver.Filter{FieldPath: []string{"n"}, Op: ">", Value: 2.5}, true},
+		{driver.Filter{FieldPath: []string{"f"}, Op: "<", Value: int64(3)}, true},
+		{driver.Filter{FieldPath: []string{"s"}, Op: ">=", Value: "abd"}, false},
+		{driver.Filter{FieldPath: []string{"s"}, Op: "=", Value: int64(1)}, false},
+		{driver.Filter{FieldPath: []string{"b"}, Op: "=", Value: true}, true},
+		{driver.Filter{FieldPath: []string{"a", "x"}, Op: "<=", Value: int64(1)}, true},
+		{driver.Filter{FieldPath: []string{"missing"}, Op: "not-in", Value: []interface{}{int64(1)}}, false},
+		{driver.Filter{FieldPath: []string{"n"}, Op: "in", Value: []interface{}{"3", int64(3)}}, true},
+		{driver.Filter{FieldPath: []string{"n"}, Op: "not-in", Value: []interface{}{int64(3)}}, false},
+		{driver.Filter{FieldPath: []string{"s"}, Op: "not-in", Value: []interface{}{"x"}}, true},
+	} {
+		if got := filterMatches(test.f, m); got != test.want {
+			t.Errorf("%+v: got %t, want %t", test.f, got, test.want)
+		}
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"k": "a", "v": json.Number("2")},
+		{"k": "b"},
+		{"k": "c", "v": json.Number("1.5")},
+		{"k": "d", "v": json.Number("10")},
+	}
+	keys := func() []string {
+		var ks []string
+		for _, r := range rows {
+			ks = append(ks, r["k"].(string))
+		}
+		return ks
+	}
+	sortRows(rows, "v", true)
+	if got, want := keys(), []string{"b", "c", "a", "d"}; !cmp.Equal(got, want) {
+		t.Errorf("ascending: got %v, want %v", got, want)
+	}
+	sortRows(rows, "v", false)
+	if got, want := keys(), []string{"d", "a", "c", "b"}; !cmp.Equal(got, want) {
+		t.Errorf("descending: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"gocloud.dev/docstore"
+)
+
+func init() {
+	docstore.DefaultURLMux().RegisterCollection(Scheme, new(defaultOpener))
+}
+
+// defaultOpener creates a session with the hosts in the environment variable
+// CASSANDRA_HOSTS, and the credentials in CASSANDRA_USERNAME and
+// CASSANDRA_PASSWORD.
+type defaultOpener struct {
+	mu     sync.Mutex
+	env    [3]string
+	opener *URLOpener
+}
+
+func (o *defaultOpener) OpenCollectionURL(ctx context.Context, u *url.URL) (*docstore.Collection, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	env := [3]string{os.Getenv("CASSANDRA_HOSTS"), os.Getenv("CASSANDRA_USERNAME"), os.Getenv("CASSANDRA_PASSWORD")}
+	if env[0] == "" {
+		return nil, fmt.Errorf("open collection %s: CASSANDRA_HOSTS environment variable is not set", u)
+	}
+	// If the environment has changed, create a new session.
+	if o.opener == nil || env != o.env {
+		cluster := gocql.NewCluster(strings.Split(env[0], ",")...)
+		if env[1] != "" {
+			cluster.Authenticator = gocql.PasswordAuthenticator{Username: env[1], Password: env[2]}
+		}
+		session, err := cluster.CreateSession()
+		if err != nil {
+			return nil, fmt.Errorf("open collection %s: failed to connect to %q: %v", u, env[0], err)
+		}
+		if o.opener != nil {
+			o.opener.Session.Close()
+		}
+		o.env = env
+		o.opener = &URLOpener{Session: session}
+	}
+	return o.opener.OpenCollectionURL(ctx, u)
+}
+
+// Scheme is the URL scheme cassandradocstore registers its URLOpener under on
+// docstore.DefaultMux.
+const Scheme = "cassandra"
+
+// URLOpener opens URLs like
+// "cassandra://mykeyspace/mytable?partition_key=Game&clustering_key=Player".
+//
+// The URL Host is used as the keyspace name.
+// The URL Path is used as the table name.
+//
+// The following query parameters are supported:
+//
+//   - partition_key (required): the comma-separated names of the fields stored
+//     in the partition key columns.
+//   - clustering_key (optional): the comma-separated names of the fields
+//     stored in the clustering columns.
+//   - revision_field (optional): the name of the revision field.
+type URLOpener struct {
+	// Session is the session that runs statements. It must be non-nil.
+	Session *gocql.Session
+
+	// Options specifies the options to pass to OpenCollection.
+	Options Options
+}
+
+// OpenCollectionURL opens the Collection URL.
+func (o *URLOpener) OpenCollectionURL(ctx context.Context, u *url.URL) (*docstore.Collection, error) {
+	q := u.Query()
+	partitionKey := splitFields(q.Get("partition_key"))
+	q.Del("partition_key")
+	clusteringKey := splitFields(q.Get("clustering_key"))
+	q.Del("clustering_key")
+	opts := o.Options
+	if rf := q.Get("revision_field"); rf != "" {
+		opts.RevisionField = rf
+	}
+	q.Del("revision_field")
+	for param := range q {
+		return nil, fmt.Errorf("open collection %s: invalid query parameter %q", u, param)
+	}
+
+	keyspace := u.Host
+	if keyspace == "" {
+		return nil, fmt.Errorf("open collection %s: URL must have a non-empty Host (keyspace name)", u)
+	}
+	table := strings.TrimPrefix(u.Path, "/")
+	if table == "" {
+		return nil, fmt.Errorf("open collection %s: URL must have a non-empty Path (table name)", u)
+	}
+	if len(partitionKey) == 0 {
+		return nil, fmt.Errorf("open collection %s: partition_key query parameter is required", u)
+	}
+	coll, err := OpenCollection(o.Session, keyspace+"."+table, partitionKey, clusteringKey, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("open collection %s: %v", u, err)
+	}
+	return coll, nil
+}
+
+// splitFields splits a comma-separated list of field names.
+func splitFields(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
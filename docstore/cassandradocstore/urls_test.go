@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandradocstore
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestOpenCollectionURL(t *testing.T) {
+	o := &URLOpener{Session: &gocql.Session{}}
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"cassandra://ks/tbl?partition_key=name", false},
+		// OK, with a composite key and a revision field.
+		{"cassandra://ks/tbl?partition_key=Game,Region&clustering_key=Player&revision_field=Etag", false},
+		// Missing keyspace.
+		{"cassandra:///tbl?partition_key=name", true},
+		// Missing table.
+		{"cassandra://ks/?partition_key=name", true},
+		// Missing partition key.
+		{"cassandra://ks/tbl?clustering_key=Player", true},
+		// Duplicate key field.
+		{"cassandra://ks/tbl?partition_key=a&clustering_key=a", true},
+		// Invalid parameter.
+		{"cassandra://ks/tbl?partition_key=name&param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		coll, err := o.OpenCollectionURL(ctx, u)
+		if coll != nil {
+			defer coll.Close()
+		}
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+	}
+}
+
+func TestDefaultOpenerNoHosts(t *testing.T) {
+	old, ok := os.LookupEnv("CASSANDRA_HOSTS")
+	os.Unsetenv("CASSANDRA_HOSTS")
+	if ok {
+		defer os.Setenv("CASSANDRA_HOSTS", old)
+	}
+	u, err := url.Parse("cassandra://ks/tbl?partition_key=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := new(defaultOpener).OpenCollectionURL(context.Background(), u); err == nil {
+		t.Error("got nil error, want an error when CASSANDRA_HOSTS is not set")
+	}
+}
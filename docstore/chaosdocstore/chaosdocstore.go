@@ -0,0 +1,313 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaosdocstore provides a *docstore.Collection that injects faults
+// into the calls made to another Collection, so that retries, idempotency
+// and other resilience logic can be tested. Use NewCollection or
+// NewCollectionWithKeyFunc to construct one.
+//
+// Each action list and query is delayed by Options.Latency, and fails with
+// probability Options.ErrorRate. An action list is run only in part with
+// probability Options.PartialWriteRate: only a random number of its first
+// actions, possibly none, are run, and the rest fail.
+//
+// Faults are chosen by a pseudo-random source seeded with Options.Seed, so
+// a test that makes the same calls in the same order sees the same faults.
+//
+//	coll, err := memdocstore.OpenCollection("ID", nil)
+//	...
+//	coll = chaosdocstore.NewCollection(coll, "ID", &chaosdocstore.Options{
+//		Seed:             1,
+//		PartialWriteRate: 0.1,
+//	})
+//
+// Injected errors have the code Options.ErrorCode, and
+// gcerrors.IsRetryable reports true for them unless the code says
+// otherwise, such as gcerrors.NotFound.
+//
+// # As
+//
+// chaosdocstore exposes the types of the underlying Collection for As.
+package chaosdocstore // import "gocloud.dev/docstore/chaosdocstore"
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/chaos"
+)
+
+// Options sets the faults injected by the Collection returned by
+// NewCollection.
+type Options struct {
+	// Seed seeds the pseudo-random source that chooses the faults.
+	Seed int64
+
+	// Latency delays each call by a fixed amount.
+	Latency time.Duration
+	// LatencyJitter adds a random delay of up to LatencyJitter to each
+	// call.
+	LatencyJitter time.Duration
+
+	// ErrorRate is the probability, from 0 to 1, that a call fails.
+	ErrorRate float64
+	// ErrorCode is the code of injected errors. It defaults to
+	// gcerrors.Internal.
+	ErrorCode gcerrors.ErrorCode
+
+	// PartialWriteRate is the probability, from 0 to 1, that an action
+	// list is run only in part.
+	PartialWriteRate float64
+
+	// RevisionField is the name of the field holding the document revision
+	// in the underlying Collection. Defaults to
+	// docstore.DefaultRevisionField.
+	RevisionField string
+}
+
+// NewCollection returns a *docstore.Collection that injects faults into the
+// calls made to coll. keyField is the document field holding the primary
+// key of coll. Closing the returned Collection does not close coll.
+func NewCollection(coll *docstore.Collection, keyField string, opts *Options) *docstore.Collection {
+	return docstore.NewCollection(newCollection(coll, keyField, nil, opts))
+}
+
+// NewCollectionWithKeyFunc is like NewCollection, but for a Collection
+// whose primary key is made of more than one field. keyFunc takes a
+// document and returns its primary key, or nil if the document does not
+// have one.
+func NewCollectionWithKeyFunc(coll *docstore.Collection, keyFunc func(docstore.Document) interface{}, opts *Options) *docstore.Collection {
+	return docstore.NewCollection(newCollection(coll, "", keyFunc, opts))
+}
+
+func newCollection(coll *docstore.Collection, keyField string, keyFunc func(docstore.Document) interface{}, opts *Options) *collection {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &collection{
+		coll:     coll,
+		keyField: keyField,
+		keyFunc:  keyFunc,
+		in: chaos.New(chaos.Faults{
+			Seed:          opts.Seed,
+			Latency:       opts.Latency,
+			LatencyJitter: opts.LatencyJitter,
+			ErrorRate:     opts.ErrorRate,
+			ErrorCode:     opts.ErrorCode,
+		}),
+		partialWriteRate: opts.PartialWriteRate,
+		revisionField:    opts.RevisionField,
+	}
+}
+
+// collection implements driver.Collection.
+type collection struct {
+	coll             *docstore.Collection
+	keyField         string
+	keyFunc          func(docstore.Document) interface{}
+	in               *chaos.Injector
+	partialWriteRate float64
+	revisionField    string
+}
+
+// Key implements driver.Collection.Key.
+func (c *collection) Key(doc driver.Document) (interface{}, error) {
+	if c.keyField != "" {
+		key, _ := doc.GetField(c.keyField) // no error on missing key, and it will be nil
+		return key, nil
+	}
+	return c.keyFunc(doc.Origin), nil
+}
+
+// RevisionField implements driver.Collection.RevisionField.
+func (c *collection) RevisionField() string {
+	return c.revisionField
+}
+
+// RunActions implements driver.Collection.RunActions.
+func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	if err := c.in.Before(ctx, "RunActions"); err != nil {
+		return driver.ActionListError{{Index: -1, Err: err}}
+	}
+	var alerr driver.ActionListError
+	if len(actions) > 0 && c.in.Chance(c.partialWriteRate) {
+		n := c.in.Intn(len(actions))
+		for _, a := range actions[n:] {
+			alerr = append(alerr, struct {
+				Index int
+				Err   error
+			}{a.Index, c.in.Fault("RunActions")})
+		}
+		actions = actions[:n]
+	}
+	if len(actions) == 0 {
+		return alerr
+	}
+
+	l := c.coll.Actions()
+	if opts.BeforeDo != nil {
+		l.BeforeDo(opts.BeforeDo)
+	}
+	for _, a := range actions {
+		doc := a.Doc.Origin
+		switch a.Kind {
+		case driver.Create:
+			l.Create(doc)
+		case driver.Replace:
+			l.Replace(doc)
+		case driver.Put:
+			l.Put(doc)
+		case driver.Get:
+			l.Get(doc, fieldPaths(a.FieldPaths)...)
+		case driver.Delete:
+			l.Delete(doc)
+		case driver.Update:
+			mods := docstore.Mods{}
+			for _, m := range a.Mods {
+				v := m.Value
+				if inc, ok := v.(driver.IncOp); ok {
+					v = docstore.Increment(inc.Amount)
+				}
+				mods[fieldPath(m.FieldPath)] = v
+			}
+			l.Update(doc, mods)
+		}
+	}
+	err := l.Do(ctx)
+	if err == nil {
+		return alerr
+	}
+	var lerr docstore.ActionListError
+	if !errors.As(err, &lerr) {
+		return append(alerr, struct {
+			Index int
+			Err   error
+		}{-1, err})
+	}
+	// Report the errors at the indexes of the actions passed to RunActions.
+	for _, e := range lerr {
+		if e.Index >= 0 {
+			e.Index = actions[e.Index].Index
+		}
+		alerr = append(alerr, e)
+	}
+	return alerr
+}
+
+// RunGetQuery implements driver.Collection.RunGetQuery.
+func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	if err := c.in.Before(ctx, "RunGetQuery"); err != nil {
+		return nil, err
+	}
+	return &docIterator{it: c.query(q).Get(ctx, fieldPaths(q.FieldPaths)...)}, nil
+}
+
+// QueryPlan implements driver.Collection.QueryPlan.
+func (c *collection) QueryPlan(q *driver.Query) (string, error) {
+	return c.query(q).Plan(fieldPaths(q.FieldPaths)...)
+}
+
+// query returns the query on the underlying Collection for q.
+func (c *collection) query(q *driver.Query) *docstore.Query {
+	dq := c.coll.Query()
+	for _, f := range q.Filters {
+		dq.Where(fieldPath(f.FieldPath), f.Op, f.Value)
+	}
+	if q.Offset > 0 {
+		dq.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		dq.Limit(q.Limit)
+	}
+	if q.OrderByField != "" {
+		dir := docstore.Descending
+		if q.OrderAscending {
+			dir = docstore.Ascending
+		}
+		dq.OrderBy(q.OrderByField, dir)
+	}
+	if q.BeforeQuery != nil {
+		dq.BeforeQuery(q.BeforeQuery)
+	}
+	return dq
+}
+
+// docIterator implements driver.DocumentIterator.
+type docIterator struct {
+	it *docstore.DocumentIterator
+}
+
+func (it *docIterator) Next(ctx context.Context, doc driver.Document) error {
+	return it.it.Next(ctx, doc.Origin)
+}
+
+func (it *docIterator) Stop() {
+	it.it.Stop()
+}
+
+func (it *docIterator) As(i interface{}) bool {
+	return it.it.As(i)
+}
+
+func fieldPath(fp []string) docstore.FieldPath {
+	return docstore.FieldPath(strings.Join(fp, "."))
+}
+
+func fieldPaths(fps [][]string) []docstore.FieldPath {
+	var res []docstore.FieldPath
+	for _, fp := range fps {
+		res = append(res, fieldPath(fp))
+	}
+	return res
+}
+
+// RevisionToBytes implements driver.Collection.RevisionToBytes.
+func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	s, err := c.coll.RevisionToString(rev)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// BytesToRevision implements driver.Collection.BytesToRevision.
+func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	return c.coll.StringToRevision(string(b))
+}
+
+// As implements driver.Collection.As.
+func (c *collection) As(i interface{}) bool {
+	return c.coll.As(i)
+}
+
+// ErrorAs implements driver.Collection.ErrorAs.
+func (c *collection) ErrorAs(err error, i interface{}) bool {
+	return c.coll.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Collection.ErrorCode.
+func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Collection.Close. It does not close the
+// underlying Collection.
+func (c *collection) Close() error {
+	return nil
+}
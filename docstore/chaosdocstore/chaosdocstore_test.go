@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaosdocstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/docstore/memdocstore"
+	"gocloud.dev/gcerrors"
+)
+
+type harness struct{}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{}, nil
+}
+
+func (h *harness) MakeCollection(_ context.Context, kind drivertest.CollectionKind) (driver.Collection, error) {
+	switch kind {
+	case drivertest.SingleKey, drivertest.NoRev:
+		coll, err := memdocstore.OpenCollection(drivertest.KeyField, nil)
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(coll, drivertest.KeyField, nil, nil), nil
+	case drivertest.TwoKey:
+		coll, err := memdocstore.OpenCollectionWithKeyFunc(drivertest.HighScoreKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(coll, "", drivertest.HighScoreKey, nil), nil
+	case drivertest.AltRev:
+		coll, err := memdocstore.OpenCollection(drivertest.KeyField, &memdocstore.Options{RevisionField: drivertest.AlternateRevisionField})
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(coll, drivertest.KeyField, nil, &Options{RevisionField: drivertest.AlternateRevisionField}), nil
+	default:
+		panic("bad kind")
+	}
+}
+
+func (*harness) BeforeDoTypes() []interface{}    { return nil }
+func (*harness) BeforeQueryTypes() []interface{} { return nil }
+
+func (*harness) RevisionsEqual(rev1, rev2 interface{}) bool { return rev1 == rev2 }
+
+func (*harness) Close() {}
+
+// TestConformance checks that a Collection that injects no faults behaves
+// like the Collection it wraps.
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, nil, nil)
+}
+
+type docmap = map[string]interface{}
+
+func TestErrorRate(t *testing.T) {
+	ctx := context.Background()
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	coll := NewCollection(mem, "ID", &Options{ErrorRate: 1, ErrorCode: gcerrors.ResourceExhausted})
+	defer coll.Close()
+
+	err = coll.Put(ctx, docmap{"ID": "a"})
+	if gcerrors.Code(err) != gcerrors.ResourceExhausted || !gcerrors.IsRetryable(err) {
+		t.Errorf("Put: got %v, want a retryable ResourceExhausted error", err)
+	}
+	it := coll.Query().Get(ctx)
+	defer it.Stop()
+	if err := it.Next(ctx, docmap{}); gcerrors.Code(err) != gcerrors.ResourceExhausted {
+		t.Errorf("query: got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestPartialWrite(t *testing.T) {
+	ctx := context.Background()
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	coll := NewCollection(mem, "ID", &Options{Seed: 3, PartialWriteRate: 1})
+	defer coll.Close()
+
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	l := coll.Actions()
+	for _, id := range ids {
+		l.Put(docmap{"ID": id})
+	}
+	err = l.Do(ctx)
+	var alerr docstore.ActionListError
+	if !errors.As(err, &alerr) || len(alerr) == 0 {
+		t.Fatalf("got %v, want an ActionListError", err)
+	}
+	failed := map[int]bool{}
+	for _, e := range alerr {
+		failed[e.Index] = true
+	}
+	// The actions that did not fail were run; the rest were not.
+	for i, id := range ids {
+		err := mem.Get(ctx, docmap{"ID": id})
+		if failed[i] != (gcerrors.Code(err) == gcerrors.NotFound) {
+			t.Errorf("%s: failed %t, but Get returned %v", id, failed[i], err)
+		}
+	}
+}
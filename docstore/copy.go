@@ -0,0 +1,403 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultCopyWorkers   = 4
+	defaultCopyBatchSize = 100
+)
+
+// CopyOptions controls the behavior of Copy.
+type CopyOptions struct {
+	// Query selects the documents to copy. It must be a query on the source
+	// Collection, and is run with Get. If nil, all documents are copied.
+	Query *Query
+
+	// Workers is the number of batches of documents written concurrently.
+	// If zero, 4 is used.
+	Workers int
+
+	// BatchSize is the number of documents written by each ActionList.
+	// If zero, 100 is used.
+	BatchSize int
+
+	// Progress, if not nil, is called after each batch of documents is
+	// written, with the number of documents written so far. Calls are not
+	// concurrent.
+	Progress func(n int64)
+}
+
+// Copy copies the documents of src to dst, which may be of different
+// drivers. Both Collections must have the same key fields.
+//
+// The documents are read into maps, as a query does, and written to dst
+// with Put, replacing any documents of dst with the same keys. The revision
+// of each document in src is dropped, and dst gives the document a new one.
+//
+// Documents are read from src as one stream, and written to dst in batches,
+// several at a time. Copy stops at the first error; some batches may have
+// been written by then, and others not.
+func Copy(ctx context.Context, src, dst *Collection, opts *CopyOptions) error {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	q := opts.Query
+	if q == nil {
+		q = src.Query()
+	} else if q.coll != src {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "Copy: CopyOptions.Query is not a query on the source Collection")
+	}
+	srcRev := src.driver.RevisionField()
+	it := q.Get(ctx)
+	defer it.Stop()
+	return writeDocs(ctx, dst, opts.Workers, opts.BatchSize, opts.Progress, func(doc map[string]interface{}) error {
+		if err := it.Next(ctx, doc); err != nil {
+			return err
+		}
+		delete(doc, srcRev)
+		return nil
+	})
+}
+
+// ExportOptions controls the behavior of Export.
+type ExportOptions struct {
+	// Query selects the documents to export. It must be a query on the
+	// exported Collection, and is run with Get. If nil, all documents are
+	// exported.
+	Query *Query
+
+	// Progress, if not nil, is called after each document is written, with
+	// the number of documents written so far.
+	Progress func(n int64)
+}
+
+// Export writes the documents of coll to w as newline-delimited JSON: one
+// JSON object per document, with its revision dropped. The output can be
+// read into any Collection with the same key fields by Import. To back up a
+// Collection to a bucket, pass Export the *blob.Writer of a blob.
+//
+// Values that JSON cannot represent exactly are written as objects with a
+// single field: a []byte as {"$bytes": base64}, a time.Time as
+// {"$time": RFC 3339 string}, and a NaN or infinite float as
+// {"$float": "NaN"}. Integers are written without, and other floats with, a
+// decimal point or exponent, so that Import can tell them apart. A map with
+// a single field named "$bytes", "$time" or "$float" is therefore not read
+// back as a map.
+func Export(ctx context.Context, coll *Collection, w io.Writer, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	q := opts.Query
+	if q == nil {
+		q = coll.Query()
+	} else if q.coll != coll {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "Export: ExportOptions.Query is not a query on the exported Collection")
+	}
+	rev := coll.driver.RevisionField()
+	it := q.Get(ctx)
+	defer it.Stop()
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	var n int64
+	for {
+		doc := map[string]interface{}{}
+		err := it.Next(ctx, doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		delete(doc, rev)
+		var e jsonEncoder
+		if err := driver.Encode(reflect.ValueOf(doc), &e); err != nil {
+			return wrapError(coll.driver, err)
+		}
+		if err := enc.Encode(e.val); err != nil {
+			return err
+		}
+		n++
+		if opts.Progress != nil {
+			opts.Progress(n)
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportOptions controls the behavior of Import.
+type ImportOptions struct {
+	// Workers is the number of batches of documents written concurrently.
+	// If zero, 4 is used.
+	Workers int
+
+	// BatchSize is the number of documents written by each ActionList.
+	// If zero, 100 is used.
+	BatchSize int
+
+	// Progress, if not nil, is called after each batch of documents is
+	// written, with the number of documents written so far. Calls are not
+	// concurrent.
+	Progress func(n int64)
+}
+
+// Import reads documents written by Export from r, and writes them to coll
+// with Put, as Copy does. To restore a Collection from a bucket, pass Import
+// the *blob.Reader of a blob.
+func Import(ctx context.Context, r io.Reader, coll *Collection, opts *ImportOptions) error {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var n int // the number of documents read
+	return writeDocs(ctx, coll, opts.Workers, opts.BatchSize, opts.Progress, func(doc map[string]interface{}) error {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				return err
+			}
+			return gcerr.Newf(gcerr.InvalidArgument, err, "Import: document %d", n+1)
+		}
+		n++
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return gcerr.Newf(gcerr.InvalidArgument, nil, "Import: document %d is not a JSON object", n)
+		}
+		for k, x := range m {
+			y, err := fromJSON(x)
+			if err != nil {
+				return gcerr.Newf(gcerr.InvalidArgument, err, "Import: document %d, field %q", n, k)
+			}
+			doc[k] = y
+		}
+		return nil
+	})
+}
+
+// writeDocs writes the documents returned by next to coll with Puts, in
+// batches of batchSize run by workers goroutines, until next returns io.EOF.
+// next is called from a single goroutine. Each document is given coll's
+// revision field, so that coll gives it a revision.
+func writeDocs(ctx context.Context, coll *Collection, workers, batchSize int, progress func(int64), next func(map[string]interface{}) error) error {
+	if workers <= 0 {
+		workers = defaultCopyWorkers
+	}
+	if batchSize <= 0 {
+		batchSize = defaultCopyBatchSize
+	}
+	rev := coll.driver.RevisionField()
+	g, gctx := errgroup.WithContext(ctx)
+	batches := make(chan []map[string]interface{})
+	g.Go(func() error {
+		defer close(batches)
+		var batch []map[string]interface{}
+		send := func() error {
+			select {
+			case batches <- batch:
+				batch = nil
+				return nil
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		for {
+			doc := map[string]interface{}{}
+			err := next(doc)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			doc[rev] = nil
+			batch = append(batch, doc)
+			if len(batch) == batchSize {
+				if err := send(); err != nil {
+					return err
+				}
+			}
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		return send()
+	})
+
+	var (
+		mu sync.Mutex
+		n  int64
+	)
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			for batch := range batches {
+				l := coll.Actions()
+				for _, doc := range batch {
+					l.Put(doc)
+				}
+				if err := l.Do(gctx); err != nil {
+					return err
+				}
+				mu.Lock()
+				n += int64(len(batch))
+				if progress != nil {
+					progress(n)
+				}
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// jsonEncoder encodes a value as one that encoding/json writes as described
+// in the documentation of Export.
+type jsonEncoder struct {
+	val interface{}
+}
+
+func (e *jsonEncoder) EncodeNil()            { e.val = nil }
+func (e *jsonEncoder) EncodeBool(x bool)     { e.val = x }
+func (e *jsonEncoder) EncodeInt(x int64)     { e.val = x }
+func (e *jsonEncoder) EncodeUint(x uint64)   { e.val = x }
+func (e *jsonEncoder) EncodeFloat(x float64) { e.val = jsonFloat(x) }
+func (e *jsonEncoder) EncodeString(x string) { e.val = x }
+func (e *jsonEncoder) ListIndex(int)         { panic("impossible") }
+func (e *jsonEncoder) MapKey(string)         { panic("impossible") }
+
+func (e *jsonEncoder) EncodeBytes(x []byte) {
+	e.val = map[string]interface{}{"$bytes": base64.StdEncoding.EncodeToString(x)}
+}
+
+func (e *jsonEncoder) EncodeSpecial(v reflect.Value) (bool, error) {
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		e.val = map[string]interface{}{"$time": v.Interface().(time.Time).Format(time.RFC3339Nano)}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (e *jsonEncoder) EncodeList(n int) driver.Encoder {
+	s := make([]interface{}, n)
+	e.val = s
+	return &jsonListEncoder{s: s}
+}
+
+func (e *jsonEncoder) EncodeMap(n int) driver.Encoder {
+	m := make(map[string]interface{}, n)
+	e.val = m
+	return &jsonMapEncoder{m: m}
+}
+
+type jsonListEncoder struct {
+	s []interface{}
+	jsonEncoder
+}
+
+func (e *jsonListEncoder) ListIndex(i int) { e.s[i] = e.val }
+
+type jsonMapEncoder struct {
+	m map[string]interface{}
+	jsonEncoder
+}
+
+func (e *jsonMapEncoder) MapKey(k string) { e.m[k] = e.val }
+
+// A jsonFloat is a float64 that is written to JSON with a decimal point or
+// exponent, or as a "$float" object if JSON has no number for it.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	x := float64(f)
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return json.Marshal(map[string]string{"$float": strconv.FormatFloat(x, 'g', -1, 64)})
+	}
+	b := strconv.AppendFloat(nil, x, 'g', -1, 64)
+	if !strings.ContainsAny(string(b), ".e") {
+		b = append(b, ".0"...)
+	}
+	return b, nil
+}
+
+// fromJSON converts a value decoded by encoding/json with UseNumber back to
+// the one that Export wrote.
+func fromJSON(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		s := string(v)
+		if strings.ContainsAny(s, ".eE") {
+			return strconv.ParseFloat(s, 64)
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		return strconv.ParseUint(s, 10, 64)
+	case []interface{}:
+		for i, x := range v {
+			y, err := fromJSON(x)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = y
+		}
+		return v, nil
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for k, x := range v {
+				s, ok := x.(string)
+				if !ok {
+					break
+				}
+				switch k {
+				case "$bytes":
+					return base64.StdEncoding.DecodeString(s)
+				case "$time":
+					return time.Parse(time.RFC3339Nano, s)
+				case "$float":
+					return strconv.ParseFloat(s, 64)
+				}
+			}
+		}
+		for k, x := range v {
+			y, err := fromJSON(x)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = y
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docstore_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/memdocstore"
+	"gocloud.dev/gcerrors"
+)
+
+type docmap = map[string]interface{}
+
+func openMem(t *testing.T, revField string) *docstore.Collection {
+	t.Helper()
+	coll, err := memdocstore.OpenCollection("ID", &memdocstore.Options{RevisionField: revField})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { coll.Close() })
+	return coll
+}
+
+// readAll returns the documents of coll, without their revisions, and
+// checks that each has one.
+func readAll(t *testing.T, coll *docstore.Collection, revField string) []docmap {
+	t.Helper()
+	ctx := context.Background()
+	it := coll.Query().OrderBy("ID", docstore.Ascending).Get(ctx)
+	defer it.Stop()
+	var docs []docmap
+	for {
+		doc := docmap{}
+		err := it.Next(ctx, doc)
+		if err == io.EOF {
+			return docs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc[revField] == nil {
+			t.Errorf("document %v has no revision", doc["ID"])
+		}
+		delete(doc, revField)
+		docs = append(docs, doc)
+	}
+}
+
+func putDocs(t *testing.T, coll *docstore.Collection, n int) []docmap {
+	t.Helper()
+	when := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	var docs []docmap
+	l := coll.Actions()
+	for i := 0; i < n; i++ {
+		doc := docmap{
+			"ID":    fmt.Sprintf("%03d", i),
+			"N":     int64(i),
+			"F":     float64(i) + 0.5,
+			"Whole": 2.0,
+			"B":     []byte{byte(i)},
+			"T":     when,
+			"L":     []interface{}{"a", int64(1), nil, true},
+			"M":     docmap{"x": docmap{"y": -1.25}, "$bytes": "not bytes", "z": "w"},
+			"Inf":   math.Inf(-1),
+		}
+		docs = append(docs, doc)
+		put := docmap{docstore.DefaultRevisionField: nil}
+		for k, v := range doc {
+			put[k] = v
+		}
+		l.Put(put)
+	}
+	if err := l.Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return docs
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	src := openMem(t, "")
+	want := putDocs(t, src, 25)
+	dst := openMem(t, "Rev")
+
+	var calls []int64
+	err := docstore.Copy(ctx, src, dst, &docstore.CopyOptions{
+		Workers:   3,
+		BatchSize: 4,
+		Progress:  func(n int64) { calls = append(calls, n) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, readAll(t, dst, "Rev")); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+	if len(calls) != 7 || calls[len(calls)-1] != 25 || !sort.SliceIsSorted(calls, func(i, j int) bool { return calls[i] < calls[j] }) {
+		t.Errorf("got progress %v, want 7 increasing calls ending with 25", calls)
+	}
+
+	// A query selects the documents to copy.
+	dst = openMem(t, "Rev")
+	q := src.Query().Where("N", "<", 5)
+	if err := docstore.Copy(ctx, src, dst, &docstore.CopyOptions{Query: q}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(readAll(t, dst, "Rev")); got != 5 {
+		t.Errorf("got %d documents, want 5", got)
+	}
+	err = docstore.Copy(ctx, src, dst, &docstore.CopyOptions{Query: dst.Query()})
+	if gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Copy with a query on dst: got %v, want InvalidArgument", err)
+	}
+
+	// Write errors are returned.
+	dst.Close()
+	if err := docstore.Copy(ctx, src, dst, nil); err == nil {
+		t.Error("Copy to a closed Collection: got nil, want an error")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	src := openMem(t, "")
+	want := putDocs(t, src, 10)
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	w, err := bucket.NewWriter(ctx, "backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported int64
+	if err := docstore.Export(ctx, src, w, &docstore.ExportOptions{Progress: func(n int64) { exported = n }}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if exported != 10 {
+		t.Errorf("got %d exported, want 10", exported)
+	}
+	data, err := bucket.ReadAll(ctx, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes.Count(data, []byte("\n")); got != 10 {
+		t.Errorf("got %d lines, want 10", got)
+	}
+	if bytes.Contains(data, []byte(docstore.DefaultRevisionField)) {
+		t.Error("export contains revisions")
+	}
+
+	r, err := bucket.NewReader(ctx, "backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	dst := openMem(t, "")
+	var imported int64
+	if err := docstore.Import(ctx, r, dst, &docstore.ImportOptions{Progress: func(n int64) { imported = n }}); err != nil {
+		t.Fatal(err)
+	}
+	if imported != 10 {
+		t.Errorf("got %d imported, want 10", imported)
+	}
+	got := readAll(t, dst, docstore.DefaultRevisionField)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, in := range []string{
+		`{"ID": "a"} {`,
+		`["ID"]`,
+		`{"ID": "a", "B": {"$bytes": "!"}}`,
+		`{"ID": "a", "T": {"$time": "yesterday"}}`,
+	} {
+		err := docstore.Import(ctx, strings.NewReader(in), openMem(t, ""), nil)
+		if gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", in, err)
+		}
+	}
+}
@@ -112,6 +112,24 @@
 // encoding.BinaryMarshaler or encoding.TextMarshaler is permitted. This set of types
 // closely matches the encoding/json package (see https://golang.org/pkg/encoding/json).
 //
+// To store a type as a value of another type, such as a decimal type as a
+// number or an enum as its name, implement ValueMarshaler and
+// ValueUnmarshaler. These take precedence over the other interfaces and over
+// the special encodings of drivers, and query filter values that implement
+// ValueMarshaler are replaced by the values they are stored as:
+//
+//	func (s Status) MarshalDocstoreValue() (interface{}, error) {
+//		return s.String(), nil
+//	}
+//
+//	func (s *Status) UnmarshalDocstoreValue(decode func(interface{}) error) error {
+//		var name string
+//		if err := decode(&name); err != nil {
+//			return err
+//		}
+//		return s.Set(name)
+//	}
+//
 // Times deserve special mention. Docstore can store and retrieve values of type
 // time.Time, with two caveats. First, the timezone will not be preserved. Second,
 // Docstore guarantees only that time.Time values are represented to millisecond
@@ -163,6 +181,9 @@
 // document are executed in the user-specified order. See the documentation of
 // ActionList for details.
 //
+// Some drivers can also execute an action list atomically, so that either all
+// of its writes happen or none do; see ActionList.Atomic.
+//
 // # Revisions
 //
 // Docstore supports document revisions to distinguish different versions of a
@@ -235,6 +256,53 @@
 //	    fmt.Println(m)
 //	}
 //
+// To page through the results of a query across requests, save the position of
+// an iterator with DocumentIterator.PaginationToken, and resume the query later
+// with Query.StartFrom. Not all drivers support pagination tokens.
+//
+//	token, err := iter.PaginationToken()
+//	...
+//	iter = coll.Query().Where("size", ">", 10).StartFrom(token).Limit(5).Get(ctx)
+//
+// Instead of calling Get, call Count, Sum, Min or Max to aggregate the
+// documents that a query matches. Drivers that can do so compute the
+// aggregation in the service; otherwise it is computed from the documents,
+// reading only the aggregated field.
+//
+//	n, err := coll.Query().Where("size", ">", 10).Count(ctx)
+//	total, err := coll.Query().Where("size", ">", 10).Sum(ctx, "size")
+//
+// # Changes
+//
+// Some drivers can report the changes to the documents of a collection as they
+// happen. Call Collection.Watch, then call Next on the returned ChangeIterator
+// to get each Change, and decode the document before and after the change with
+// its Old and New methods.
+//
+//	iter := coll.Watch(ctx, nil)
+//	defer iter.Stop()
+//	for {
+//	    change, err := iter.Next(ctx)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    var book Book
+//	    if change.Kind == docstore.Deleted {
+//	        err = change.Key(&book)
+//	    } else {
+//	        err = change.New(&book)
+//	    }
+//	    ...
+//	}
+//
+// # Copying Collections
+//
+// Copy copies the documents of one Collection to another, which may be of a
+// different driver, for example to migrate from DynamoDB to MongoDB. Export
+// writes the documents of a Collection as newline-delimited JSON to an
+// io.Writer, such as a *blob.Writer, and Import writes them back to any
+// Collection.
+//
 // # Errors
 //
 // The errors returned from this package can be inspected in several ways:
@@ -246,6 +314,13 @@
 // the returned error. See the specific driver's package doc for the supported
 // types.
 //
+// # OpenTelemetry Integration
+//
+// This API reports the method calls below as OpenTelemetry spans, and as the
+// metric "gocdk.docstore.duration", to the global tracer and meter providers.
+// See https://pkg.go.dev/gocloud.dev/telemetry for the span and metric
+// names, and for configuring exporters.
+//
 // # OpenCensus Integration
 //
 // OpenCensus supports tracing and metric collection for multiple languages and
@@ -254,6 +329,8 @@
 // This API collects OpenCensus traces and metrics for the following methods:
 //   - ActionList.Do
 //   - Query.Get (for the first query only; drivers may make additional calls while iterating over results)
+//   - Collection.Watch (for starting the watch only)
+//   - Query.Count, Query.Sum, Query.Min and Query.Max
 //
 // All trace and metric names begin with the package import path.
 // The traces add the method name.
@@ -26,10 +26,12 @@ import (
 	"sync"
 	"unicode/utf8"
 
+	"gocloud.dev/callopt"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/gcerr"
 	"gocloud.dev/internal/oc"
+	"gocloud.dev/internal/telemetry"
 )
 
 // A Document is a set of field-value pairs. One or more fields, called the key
@@ -41,12 +43,23 @@ import (
 // structs, the exported fields are the document fields.
 type Document = interface{}
 
+// A ValueMarshaler is a type that is stored as the value that its
+// MarshalDocstoreValue method returns, such as a number for a decimal type or
+// a string for a UUID. A ValueUnmarshaler is a type that is read back by its
+// UnmarshalDocstoreValue method, which is passed a function that decodes the
+// stored value into a variable of the method's choosing. See the Representing
+// Data section of the package documentation.
+type (
+	ValueMarshaler   = driver.ValueMarshaler
+	ValueUnmarshaler = driver.ValueUnmarshaler
+)
+
 // A Collection represents a set of documents. It provides an easy and portable
 // way to interact with document stores.
 // To create a Collection, use constructors found in driver subpackages.
 type Collection struct {
 	driver driver.Collection
-	tracer *oc.Tracer
+	tracer *telemetry.Tracer
 	mu     sync.Mutex
 	closed bool
 }
@@ -69,11 +82,7 @@ var NewCollection = newCollection
 func newCollection(d driver.Collection) *Collection {
 	c := &Collection{
 		driver: d,
-		tracer: &oc.Tracer{
-			Package:        pkgName,
-			Provider:       oc.ProviderName(d),
-			LatencyMeasure: latencyMeasure,
-		},
+		tracer: telemetry.NewTracer(pkgName, telemetry.ProviderName(d), latencyMeasure, telemetry.DriverAttributes(d)...),
 	}
 	_, file, lineno, ok := runtime.Caller(1)
 	runtime.SetFinalizer(c, func(c *Collection) {
@@ -138,6 +147,7 @@ type ActionList struct {
 	coll     *Collection
 	actions  []*Action
 	beforeDo func(asFunc func(interface{}) bool) error
+	atomic   bool
 }
 
 // An Action is a read or write on a single document.
@@ -262,6 +272,8 @@ func (l *ActionList) Update(doc Document, mods Mods) *ActionList {
 // At present, a modification is one of:
 //   - nil, to delete the field
 //   - an Increment value, to add a number to the field
+//   - a ListAppend value, to append elements to a list
+//   - a SetAdd or SetRemove value, to add elements to or remove them from a set
 //   - any other value, to set the field to that value
 //
 // See ActionList.Update.
@@ -277,6 +289,33 @@ func Increment(amount interface{}) interface{} {
 	return driver.IncOp{amount}
 }
 
+// ListAppend returns a modification that appends values to the list in a
+// field, or sets the field to a list of the values if it is absent. Unlike
+// reading the list and writing it back, it does not race with other writers:
+//
+//	docstore.Mods{"Tags": docstore.ListAppend("new", "blue")}
+//
+// Not every driver supports it; see the driver's documentation.
+func ListAppend(values ...interface{}) interface{} {
+	return driver.ListAppendOp{Values: values}
+}
+
+// SetAdd returns a modification that adds the values that are not already in
+// the set in a field, or sets the field to a set of the values if it is
+// absent. There must be at least one value.
+//
+// Drivers store sets differently; some as lists without duplicates. See the
+// driver's documentation for which fields and values it supports.
+func SetAdd(values ...interface{}) interface{} {
+	return driver.SetAddOp{Values: values}
+}
+
+// SetRemove returns a modification that removes the values from the set in a
+// field. There must be at least one value. See SetAdd.
+func SetRemove(values ...interface{}) interface{} {
+	return driver.SetRemoveOp{Values: values}
+}
+
 // An ActionListError is returned by ActionList.Do. It contains all the errors
 // encountered while executing the ActionList, and the positions of the corresponding
 // actions.
@@ -321,6 +360,19 @@ func (l *ActionList) BeforeDo(f func(asFunc func(interface{}) bool) error) *Acti
 	return l
 }
 
+// Atomic makes Do execute the writes in the action list as a single
+// transaction: either they all succeed, or none of them take effect. The Gets
+// that come before the writes in the list see the documents as they were before
+// the transaction, and those that come after see them as they are after it.
+//
+// Services limit the size of transactions; see the driver's documentation.
+// If the driver does not support transactions, Do fails with code
+// Unimplemented.
+func (l *ActionList) Atomic() *ActionList {
+	l.atomic = true
+	return l
+}
+
 // Do executes the action list.
 //
 // If Do returns a non-nil error, it will be of type ActionListError. If any action
@@ -340,6 +392,8 @@ func (l *ActionList) do(ctx context.Context, oc bool) (err error) {
 	if err := l.coll.checkClosed(); err != nil {
 		return ActionListError{{-1, errClosed}}
 	}
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 
 	if oc {
 		ctx = l.coll.tracer.Start(ctx, "ActionList.Do")
@@ -351,7 +405,16 @@ func (l *ActionList) do(ctx context.Context, oc bool) (err error) {
 		return err
 	}
 	dopts := &driver.RunActionsOptions{BeforeDo: l.beforeDo}
-	alerr := ActionListError(l.coll.driver.RunActions(ctx, das, dopts))
+	var alerr ActionListError
+	if l.atomic {
+		ar, ok := l.coll.driver.(driver.AtomicActionRunner)
+		if !ok {
+			return ActionListError{{-1, gcerr.Newf(gcerr.Unimplemented, nil, "atomic action lists are not supported by this driver")}}
+		}
+		alerr = ActionListError(ar.RunActionsAtomically(ctx, das, dopts))
+	} else {
+		alerr = ActionListError(l.coll.driver.RunActions(ctx, das, dopts))
+	}
 	if len(alerr) == 0 {
 		return nil // Explicitly return nil, because alerr is not of type error.
 	}
@@ -495,6 +558,16 @@ func toDriverMods(mods Mods) ([]driver.Mod, error) {
 			return nil, gcerr.Newf(gcerr.InvalidArgument, nil,
 				"Increment amount %v of type %[1]T must be an integer or floating-point number", inc.Amount)
 		}
+		switch op := v.(type) {
+		case driver.SetAddOp:
+			if len(op.Values) == 0 {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "SetAdd of %q has no values", k)
+			}
+		case driver.SetRemoveOp:
+			if len(op.Values) == 0 {
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "SetRemove of %q has no values", k)
+			}
+		}
 		dmods = append(dmods, driver.Mod{FieldPath: fp, Value: v})
 	}
 	return dmods, nil
@@ -16,6 +16,7 @@ package docstore
 
 import (
 	"context"
+	"io"
 	"reflect"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
 )
 
 type Book struct {
@@ -87,6 +89,7 @@ func TestActionsDo(t *testing.T) {
 		{c.Actions().Update(d1, Mods{"a.b.c": 1, "a.b": 2, "a.b+c": 3}), []int{0}},                                  // a.b is a prefix of a.b.c
 		{c.Actions().Update(d1, Mods{"": 1}).Update(ds1, Mods{".f": 2}), []int{0, 1}},                               // invalid field path
 		{c.Actions().Update(d1, Mods{"a": Increment(true)}).Update(ds1, Mods{"name": Increment("b")}), []int{0, 1}}, // invalid incOp
+		{c.Actions().Update(d1, Mods{"a": SetAdd()}).Update(ds1, Mods{"name": SetRemove()}), []int{0, 1}},           // empty set mod
 	} {
 		err := test.alist.Do(context.Background())
 		if err == nil {
@@ -108,6 +111,237 @@ func TestActionsDo(t *testing.T) {
 	}
 }
 
+// atomicDriverCollection is a fakeDriverCollection that records whether its
+// actions were run atomically.
+type atomicDriverCollection struct {
+	fakeDriverCollection
+	atomic *bool
+}
+
+func (c atomicDriverCollection) RunActionsAtomically(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	*c.atomic = true
+	return nil
+}
+
+func TestAtomicActions(t *testing.T) {
+	ctx := context.Background()
+	doc := map[string]interface{}{"key": 1}
+
+	c := NewCollection(fakeDriverCollection{})
+	defer c.Close()
+	if err := c.Actions().Put(doc).Atomic().Do(ctx); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("without driver support: got %v, want Unimplemented", err)
+	}
+
+	var atomic bool
+	c = NewCollection(atomicDriverCollection{atomic: &atomic})
+	defer c.Close()
+	if err := c.Actions().Put(doc).Do(ctx); err != nil || atomic {
+		t.Errorf("non-atomic list: got %v, atomic %t; want nil, false", err, atomic)
+	}
+	if err := c.Actions().Put(doc).Atomic().Do(ctx); err != nil || !atomic {
+		t.Errorf("atomic list: got %v, atomic %t; want nil, true", err, atomic)
+	}
+}
+
+func TestPaginationTokens(t *testing.T) {
+	ctx := context.Background()
+	c := NewCollection(fakeDriverCollection{})
+	defer c.Close()
+	doc := map[string]interface{}{}
+
+	for _, token := range []string{"", "not base64!"} {
+		iter := c.Query().StartFrom(token).Get(ctx)
+		if err := iter.Next(ctx, doc); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("StartFrom(%q): got %v, want InvalidArgument", token, err)
+		}
+	}
+	iter := c.Query().StartFrom("dG9rZW4").StartFrom("dG9rZW4").Get(ctx)
+	if err := iter.Next(ctx, doc); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("two StartFroms: got %v, want InvalidArgument", err)
+	}
+
+	// The fake driver does not support pagination tokens, so the query must
+	// not run.
+	var fps [][]string
+	dc := NewCollection(docsDriverCollection{fieldPaths: &fps})
+	defer dc.Close()
+	iter = dc.Query().StartFrom("dG9rZW4").Get(ctx, "a")
+	if err := iter.Next(ctx, doc); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("StartFrom: got %v, want Unimplemented", err)
+	}
+	if fps != nil {
+		t.Error("StartFrom: ran the query, want no query")
+	}
+	iter = c.Query().Get(ctx)
+	if _, err := iter.PaginationToken(); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("PaginationToken: got %v, want Unimplemented", err)
+	}
+	iter.Stop()
+	if _, err := iter.PaginationToken(); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("PaginationToken after Stop: got %v, want FailedPrecondition", err)
+	}
+}
+
+// docsDriverCollection is a fakeDriverCollection whose queries return docs,
+// and record their field paths.
+type docsDriverCollection struct {
+	fakeDriverCollection
+	docs       []map[string]interface{}
+	fieldPaths *[][]string
+}
+
+func (c docsDriverCollection) RunGetQuery(_ context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	*c.fieldPaths = q.FieldPaths
+	return &docsIterator{docs: c.docs}, nil
+}
+
+type docsIterator struct {
+	fakeDriverDocumentIterator
+	docs []map[string]interface{}
+}
+
+func (it *docsIterator) Next(_ context.Context, doc driver.Document) error {
+	if len(it.docs) == 0 {
+		return io.EOF
+	}
+	for k, v := range it.docs[0] {
+		if err := doc.SetField(k, v); err != nil {
+			return err
+		}
+	}
+	it.docs = it.docs[1:]
+	return nil
+}
+
+// aggDriverCollection is a docsDriverCollection that counts documents itself.
+type aggDriverCollection struct {
+	docsDriverCollection
+}
+
+func (aggDriverCollection) RunAggregateQuery(_ context.Context, _ *driver.Query, agg driver.Aggregation) (interface{}, error) {
+	if agg.Op == "count" {
+		return int64(42), nil
+	}
+	return nil, gcerr.Newf(gcerr.Unimplemented, nil, "unimplemented")
+}
+
+func TestAggregate(t *testing.T) {
+	ctx := context.Background()
+	var fps [][]string
+	dc := docsDriverCollection{
+		docs: []map[string]interface{}{
+			{"n": 1, "s": "b"},
+			{"n": 2.5, "s": "a"},
+			{"n": "x"},
+		},
+		fieldPaths: &fps,
+	}
+	c := NewCollection(dc)
+	defer c.Close()
+
+	n, err := c.Query().Count(ctx)
+	if err != nil || n != 3 {
+		t.Errorf("Count: got %d, %v; want 3", n, err)
+	}
+	sum, err := c.Query().Sum(ctx, "n")
+	if err != nil || sum != 3.5 {
+		t.Errorf("Sum: got %v, %v; want 3.5", sum, err)
+	}
+	if want := [][]string{{"n"}}; !cmp.Equal(fps, want) {
+		t.Errorf("Sum: got field paths %v, want %v", fps, want)
+	}
+	for _, test := range []struct {
+		agg  func(context.Context, FieldPath) (interface{}, error)
+		fp   FieldPath
+		want interface{}
+	}{
+		{c.Query().Min, "n", 1},
+		{c.Query().Max, "n", "x"}, // strings are greater than numbers
+		{c.Query().Min, "s", "a"},
+		{c.Query().Max, "none", nil},
+	} {
+		got, err := test.agg(ctx, test.fp)
+		if err != nil || got != test.want {
+			t.Errorf("%s: got %v, %v; want %v", test.fp, got, err, test.want)
+		}
+	}
+
+	// The driver counts, and the portable type computes the rest.
+	c = NewCollection(aggDriverCollection{dc})
+	defer c.Close()
+	if n, err := c.Query().Count(ctx); err != nil || n != 42 {
+		t.Errorf("Count with driver: got %d, %v; want 42", n, err)
+	}
+	if max, err := c.Query().Max(ctx, "n"); err != nil || max != "x" {
+		t.Errorf("Max with driver: got %v, %v; want x", max, err)
+	}
+	if _, err := c.Query().Sum(ctx, ""); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Sum with no field: got %v, want InvalidArgument", err)
+	}
+}
+
+// watchDriverCollection is a fakeDriverCollection whose Watch returns changes.
+type watchDriverCollection struct {
+	fakeDriverCollection
+	changes []*driver.Change
+}
+
+func (c watchDriverCollection) Watch(context.Context, *driver.WatchOptions) (driver.ChangeIterator, error) {
+	return &fakeChangeIterator{changes: c.changes}, nil
+}
+
+type fakeChangeIterator struct {
+	changes []*driver.Change
+}
+
+func (it *fakeChangeIterator) Next(context.Context) (*driver.Change, error) {
+	if len(it.changes) == 0 {
+		return nil, io.EOF
+	}
+	ch := it.changes[0]
+	it.changes = it.changes[1:]
+	return ch, nil
+}
+
+func (*fakeChangeIterator) Stop() {}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	c := NewCollection(fakeDriverCollection{})
+	defer c.Close()
+	iter := c.Watch(ctx, nil)
+	if _, err := iter.Next(ctx); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("without driver support: got %v, want Unimplemented", err)
+	}
+	iter.Stop()
+
+	setKey := func(doc driver.Document) error { return doc.SetField("key", "k") }
+	c = NewCollection(watchDriverCollection{changes: []*driver.Change{
+		{Kind: driver.ChangeCreate, DecodeKey: setKey, DecodeNew: setKey},
+	}})
+	defer c.Close()
+	iter = c.Watch(ctx, &WatchOptions{FromOldest: true})
+	defer iter.Stop()
+	ch, err := iter.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Kind != Created {
+		t.Errorf("got kind %s, want Created", ch.Kind)
+	}
+	doc := map[string]interface{}{}
+	if err := ch.New(doc); err != nil || doc["key"] != "k" {
+		t.Errorf("New: got %v, %v; want the document", doc, err)
+	}
+	if err := ch.Old(doc); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("Old: got %v, want NotFound", err)
+	}
+	if _, err := iter.Next(ctx); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
+
 func TestClosedErrors(t *testing.T) {
 	// Check that all collection methods return errClosed if the collection is closed.
 	ctx := context.Background()
@@ -188,3 +422,5 @@ type fakeDriverDocumentIterator struct {
 }
 
 func (fakeDriverDocumentIterator) Next(context.Context, driver.Document) error { return nil }
+
+func (fakeDriverDocumentIterator) Stop() {}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// An Accumulator computes an aggregation from documents, one at a time. It
+// is for drivers, and the portable type, that compute aggregations on the
+// client.
+//
+// "sum" adds the numeric values of the field. "min" and "max" compare the
+// numeric, string and time.Time values of the field; numbers are less than
+// strings, and strings less than times. Other values are skipped, as are
+// documents without the field.
+type Accumulator struct {
+	agg   Aggregation
+	count int64
+	sum   float64
+	best  interface{}
+}
+
+// NewAccumulator returns an Accumulator for agg. It returns an error if agg
+// is invalid.
+func NewAccumulator(agg Aggregation) (*Accumulator, error) {
+	switch agg.Op {
+	case "count":
+	case "sum", "min", "max":
+		if len(agg.FieldPath) == 0 {
+			return nil, fmt.Errorf("%s aggregation without a field", agg.Op)
+		}
+	default:
+		return nil, fmt.Errorf("invalid aggregation %q", agg.Op)
+	}
+	return &Accumulator{agg: agg}, nil
+}
+
+// Add adds doc to the aggregation.
+func (a *Accumulator) Add(doc Document) {
+	if a.agg.Op == "count" {
+		a.count++
+		return
+	}
+	v, err := doc.Get(a.agg.FieldPath)
+	if err != nil || v == nil {
+		return
+	}
+	rv := reflect.ValueOf(v)
+	switch a.agg.Op {
+	case "sum":
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			a.sum += float64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			a.sum += float64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			a.sum += rv.Float()
+		}
+	case "min", "max":
+		if valueClass(v) < 0 {
+			return
+		}
+		if a.best == nil {
+			a.best = v
+			return
+		}
+		c := compareValues(v, a.best)
+		if (a.agg.Op == "min" && c < 0) || (a.agg.Op == "max" && c > 0) {
+			a.best = v
+		}
+	}
+}
+
+// Value returns the value of the aggregation over the documents added so
+// far: an int64 for "count", a float64 for "sum", and for "min" and "max" the
+// least or greatest value, or nil if there is none.
+func (a *Accumulator) Value() interface{} {
+	switch a.agg.Op {
+	case "count":
+		return a.count
+	case "sum":
+		return a.sum
+	default:
+		return a.best
+	}
+}
+
+// valueClass returns the rank of the kind of v in the order of min and max:
+// 0 for numbers, 1 for strings, 2 for times, and -1 for values that are not
+// compared.
+func valueClass(v interface{}) int {
+	if _, ok := v.(time.Time); ok {
+		return 2
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return 0
+	case reflect.String:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// compareValues compares two values that valueClass ranks.
+func compareValues(v1, v2 interface{}) int {
+	c1, c2 := valueClass(v1), valueClass(v2)
+	switch {
+	case c1 < c2:
+		return -1
+	case c1 > c2:
+		return 1
+	}
+	switch c1 {
+	case 0:
+		c, _ := CompareNumbers(v1, v2)
+		return c
+	case 1:
+		return strings.Compare(reflect.ValueOf(v1).String(), reflect.ValueOf(v2).String())
+	default:
+		return CompareTimes(v1.(time.Time), v2.(time.Time))
+	}
+}
@@ -34,8 +34,55 @@ var (
 	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
 	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 	protoMessageType      = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	valueMarshalerType    = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType  = reflect.TypeOf((*ValueUnmarshaler)(nil)).Elem()
 )
 
+// A ValueMarshaler is a type that encodes itself as another value. Use it for
+// types that would otherwise need a shadow field of another type, such as a
+// decimal type stored as a number or an enum stored as its name.
+type ValueMarshaler interface {
+	// MarshalDocstoreValue returns the value to encode in place of the
+	// receiver. It can be any value that Encode accepts, including values
+	// that a driver encodes specially, but not a value of the receiver's
+	// type.
+	MarshalDocstoreValue() (interface{}, error)
+}
+
+// A ValueUnmarshaler is a type that decodes itself from another value, for
+// instance the value that its MarshalDocstoreValue method returns.
+type ValueUnmarshaler interface {
+	// UnmarshalDocstoreValue is called with a function that decodes the
+	// stored value into its argument, which must be a non-nil pointer, as
+	// Decode would. The method can call it more than once, with pointers to
+	// different types, until one succeeds.
+	UnmarshalDocstoreValue(decode func(interface{}) error) error
+}
+
+// MarshalValue returns the value that v is encoded as: the result of its
+// MarshalDocstoreValue method if it is a ValueMarshaler, and v otherwise.
+func MarshalValue(v interface{}) (interface{}, error) {
+	m, ok := v.(ValueMarshaler)
+	if !ok {
+		return v, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+	return marshalValue(m)
+}
+
+func marshalValue(m ValueMarshaler) (interface{}, error) {
+	x, err := m.MarshalDocstoreValue()
+	if err != nil {
+		return nil, err
+	}
+	if x != nil && reflect.TypeOf(x) == reflect.TypeOf(m) {
+		return nil, fmt.Errorf("MarshalDocstoreValue of %T returned a value of the same type", m)
+	}
+	return x, nil
+}
+
 // An Encoder encodes Go values in some other form (e.g. JSON, protocol buffers).
 // The encoding protocol is designed to avoid losing type information by passing
 // values using interface{}. An Encoder is responsible for storing the value
@@ -99,6 +146,10 @@ type Encoder interface {
 // encounters a non-nil pointer, it encodes the value that it points to.
 // Encode treats a few interfaces specially:
 //
+// If the value implements ValueMarshaler, Encode invokes MarshalDocstoreValue on
+// it and encodes the resulting value, before any special encoding of the
+// Encoder.
+//
 // If the value implements encoding.BinaryMarshaler, Encode invokes MarshalBinary
 // on it and encodes the resulting byte slice.
 //
@@ -120,6 +171,9 @@ func encode(v reflect.Value, enc Encoder) error {
 		enc.EncodeNil()
 		return nil
 	}
+	if done, err := encodeMarshaler(v, enc); done {
+		return err
+	}
 	done, err := enc.EncodeSpecial(v)
 	if done {
 		return err
@@ -207,6 +261,29 @@ func encode(v reflect.Value, enc Encoder) error {
 	return nil
 }
 
+// encodeMarshaler encodes v with its MarshalDocstoreValue method, or that of
+// its address, and returns true, if it has one.
+func encodeMarshaler(v reflect.Value, enc Encoder) (bool, error) {
+	var m ValueMarshaler
+	switch {
+	case v.Type().Implements(valueMarshalerType):
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			enc.EncodeNil()
+			return true, nil
+		}
+		m = v.Interface().(ValueMarshaler)
+	case v.CanAddr() && reflect.PtrTo(v.Type()).Implements(valueMarshalerType):
+		m = v.Addr().Interface().(ValueMarshaler)
+	default:
+		return false, nil
+	}
+	x, err := marshalValue(m)
+	if err != nil {
+		return true, err
+	}
+	return true, encode(reflect.ValueOf(x), enc)
+}
+
 // Encode an array or non-nil slice.
 func encodeList(v reflect.Value, enc Encoder) error {
 	// Byte slices encode specially.
@@ -357,6 +434,9 @@ type Decoder interface {
 	DecodeMap(func(string, Decoder, bool) bool)
 
 	// AsInterface should decode the value into the Go value that best represents it.
+	// Lists and maps should be decoded into []interface{} and map[string]interface{}
+	// holding such values, not into the driver's own representation, and should
+	// not share memory with it.
 	AsInterface() (interface{}, error)
 
 	// If the decoder wants to decode a value in a special way it should do so here
@@ -370,7 +450,7 @@ type Decoder interface {
 
 // Decode decodes the value held in the Decoder d into v.
 // Decode creates slices, maps and pointer elements as needed.
-// It treats values that implement encoding.BinaryUnmarshaler,
+// It treats values that implement ValueUnmarshaler, encoding.BinaryUnmarshaler,
 // encoding.TextUnmarshaler and proto.Message specially; see Encode.
 func Decode(v reflect.Value, d Decoder) error {
 	return wrap(decode(v, d), gcerr.InvalidArgument)
@@ -392,6 +472,16 @@ func decode(v reflect.Value, d Decoder) error {
 		}
 	}
 
+	if reflect.PtrTo(v.Type()).Implements(valueUnmarshalerType) {
+		return v.Addr().Interface().(ValueUnmarshaler).UnmarshalDocstoreValue(func(x interface{}) error {
+			rx := reflect.ValueOf(x)
+			if rx.Kind() != reflect.Ptr || rx.IsNil() {
+				return fmt.Errorf("UnmarshalDocstoreValue: decode called with %T, want a non-nil pointer", x)
+			}
+			return decode(rx.Elem(), d)
+		})
+	}
+
 	if done, val, err := d.AsSpecial(v); done {
 		if err != nil {
 			return err
@@ -51,6 +51,41 @@ func (*special) UnmarshalBinary([]byte) error  { panic("should never be called")
 
 type badSpecial int
 
+// level is stored by name, and read back from a name or a number.
+type level int
+
+var levelNames = []string{"low", "high"}
+
+func (l level) MarshalDocstoreValue() (interface{}, error) {
+	if l < 0 || int(l) >= len(levelNames) {
+		return nil, fmt.Errorf("bad level %d", l)
+	}
+	return levelNames[l], nil
+}
+
+func (l *level) UnmarshalDocstoreValue(decode func(interface{}) error) error {
+	var name string
+	if err := decode(&name); err != nil {
+		var n int
+		if err := decode(&n); err != nil {
+			return err
+		}
+		*l = level(n)
+		return nil
+	}
+	for i, ln := range levelNames {
+		if ln == name {
+			*l = level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("bad level %q", name)
+}
+
+type selfMarshaler struct{}
+
+func (s selfMarshaler) MarshalDocstoreValue() (interface{}, error) { return s, nil }
+
 type Embed1 struct {
 	E1 string
 }
@@ -113,6 +148,9 @@ func TestEncode(t *testing.T) {
 		{[]int(nil), nil},
 		{[]int{}, []interface{}{}},
 		{[]int{1, 2}, []interface{}{int64(1), int64(2)}},
+		{level(1), "high"},
+		{(*level)(nil), nil},
+		{map[string]level{"a": 0}, map[string]interface{}{"a": "low"}},
 		{
 			[][]string{{"a", "b"}, {"c", "d"}},
 			[]interface{}{
@@ -218,6 +256,8 @@ func TestEncodeErrors(t *testing.T) {
 	}{
 		{"MarshalBinary fails", badBinaryMarshaler{}},
 		{"MarshalText fails", badTextMarshaler{}},
+		{"MarshalDocstoreValue fails", level(7)},
+		{"MarshalDocstoreValue returns its receiver", selfMarshaler{}},
 		{"bad type", make(chan int)},
 		{"bad type in list", []interface{}{func() {}}},
 		{"bad type in map", map[string]interface{}{"a": func() {}}},
@@ -322,6 +362,9 @@ func TestDecode(t *testing.T) {
 		{new(*int), int64(2), &two, true},
 		{new(*int), nil, (*int)(nil), true},
 		{new([]byte), []byte("foo"), []byte("foo"), true},
+		{new(level), "high", level(1), true},
+		{new(level), int64(1), level(1), true},
+		{new(*level), "low", func() *level { l := level(0); return &l }(), true},
 		{new([]string), []interface{}{"a", "b"}, []string{"a", "b"}, true},
 		{new([]**bool), []interface{}{true, false}, []**bool{&ptru, &pfa}, true},
 		{&[1]int{1}, []interface{}{2}, [1]int{2}, true},
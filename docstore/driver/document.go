@@ -148,10 +148,27 @@ func (d Document) SetField(field string, value interface{}) error {
 		return gcerr.Newf(gcerr.InvalidArgument, nil, "cannot set field %s in struct of type %s: not addressable",
 			field, d.s.Type())
 	}
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
 	v.Set(reflect.ValueOf(value))
 	return nil
 }
 
+// FieldType returns the type of the named top-level field of d: the type of
+// the struct field, or interface{} for a map.
+func (d Document) FieldType(field string) (reflect.Type, error) {
+	if d.m != nil {
+		return reflect.TypeOf(d.m).Elem(), nil
+	}
+	f := d.fields.MatchFold(field)
+	if f == nil {
+		return nil, gcerr.Newf(gcerr.NotFound, nil, "field %q not found in struct type %s", field, d.s.Type())
+	}
+	return f.Type, nil
+}
+
 // FieldNames returns names of the top-level fields of d.
 func (d Document) FieldNames() []string {
 	var names []string
@@ -16,6 +16,7 @@ package driver
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -180,6 +181,46 @@ func TestGetField(t *testing.T) {
 	}
 }
 
+func TestFieldType(t *testing.T) {
+	type E struct {
+		C string
+	}
+	type S struct {
+		A int         `docstore:"a"`
+		B interface{} `docstore:"b"`
+		*E
+	}
+	for _, test := range []struct {
+		in    interface{}
+		field string
+		want  reflect.Type
+	}{
+		{map[string]interface{}{"a": 1}, "a", reflect.TypeOf((*interface{})(nil)).Elem()},
+		{&S{}, "a", reflect.TypeOf(0)},
+		{&S{}, "b", reflect.TypeOf((*interface{})(nil)).Elem()},
+		{&S{}, "C", reflect.TypeOf("")},
+	} {
+		doc, err := NewDocument(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := doc.FieldType(test.field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%v, %q: got %v, want %v", test.in, test.field, got, test.want)
+		}
+	}
+	doc, err := NewDocument(&S{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.FieldType("x"); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
 func TestFieldNames(t *testing.T) {
 	type E struct {
 		C int
@@ -103,6 +103,29 @@ type DeleteQueryer interface {
 	RunDeleteQuery(context.Context, *Query) error
 }
 
+// AtomicActionRunner should be implemented by Collections that can run an
+// action list as a transaction. If a Collection does not implement this
+// interface, ActionList.Do fails with code Unimplemented for an atomic action list.
+type AtomicActionRunner interface {
+	// RunActionsAtomically executes the actions like RunActions, except that
+	// the writes happen as a single transaction: either they all succeed, or
+	// none of them take effect. The Gets that come before the writes see the
+	// documents as they are before the transaction, and those that come after
+	// see them as they are after it.
+	RunActionsAtomically(ctx context.Context, actions []*Action, opts *RunActionsOptions) ActionListError
+}
+
+// PaginatedQueryer should be implemented by Collections whose queries can
+// resume from a Query.PaginationToken. Query.Get fails with code
+// Unimplemented, without running the query, for a query with a pagination
+// token on a Collection that does not implement it.
+type PaginatedQueryer interface {
+	// SupportsPaginationTokens reports whether queries accept
+	// Query.PaginationToken, and return DocumentIterators that implement
+	// PaginatedIterator.
+	SupportsPaginationTokens() bool
+}
+
 // UpdateQueryer should be implemented by Collections that can handle Query.Update
 // efficiently. If a Collection does not implement this interface, then Query.Update
 // will be implemented by calling RunGetQuery and updating the returned documents.
@@ -139,6 +162,8 @@ type Action struct {
 // At present, the only modifications supported are:
 // - set the value at the field path, or create the field path if it doesn't exist
 // - delete the field path (when Value is nil)
+// - increment, append to a list, or add to or remove from a set (when Value is
+// an IncOp, ListAppendOp, SetAddOp or SetRemoveOp)
 type Mod struct {
 	FieldPath []string
 	Value     interface{}
@@ -149,6 +174,25 @@ type IncOp struct {
 	Amount interface{}
 }
 
+// ListAppendOp is a value representing a modification that appends Values to
+// a list, or sets the field to a list of Values if it is absent.
+type ListAppendOp struct {
+	Values []interface{}
+}
+
+// SetAddOp is a value representing a modification that adds the Values that
+// are not already in a set (or a list, for drivers without sets), or sets the
+// field to a set of Values if it is absent.
+type SetAddOp struct {
+	Values []interface{}
+}
+
+// SetRemoveOp is a value representing a modification that removes Values from
+// a set (or a list, for drivers without sets).
+type SetRemoveOp struct {
+	Values []interface{}
+}
+
 // An ActionListError contains all the errors encountered from a call to RunActions,
 // and the positions of the corresponding actions.
 type ActionListError []struct {
@@ -211,6 +255,12 @@ type Query struct {
 	// underlying service's query is executed. asFunc allows drivers to expose
 	// driver-specific types.
 	BeforeQuery func(asFunc func(interface{}) bool) error
+
+	// PaginationToken, if non-nil, is a token returned by the PaginationToken
+	// method of a PaginatedIterator for the same query. The query resumes
+	// after the documents that the iterator had returned. It is only set for
+	// Collections that implement PaginatedQueryer.
+	PaginationToken []byte
 }
 
 // A Filter defines a filter expression used to filter the query result.
@@ -240,6 +290,92 @@ type DocumentIterator interface {
 	As(i interface{}) bool
 }
 
+// Watcher should be implemented by Collections that can report the changes to
+// their documents. If a Collection does not implement this interface,
+// Collection.Watch fails with code Unimplemented.
+type Watcher interface {
+	// Watch returns an iterator over the changes to the documents of the
+	// collection. The iterator may use ctx until it is stopped.
+	Watch(ctx context.Context, opts *WatchOptions) (ChangeIterator, error)
+}
+
+// WatchOptions controls Watch.
+type WatchOptions struct {
+	// If true, the iterator starts with the oldest change that the service
+	// still has. Otherwise it starts with the changes made after Watch.
+	FromOldest bool
+}
+
+// ChangeKind is the kind of a change to a document.
+type ChangeKind int
+
+const (
+	// ChangeCreate is the creation of a document.
+	ChangeCreate ChangeKind = iota + 1
+	// ChangeUpdate is a modification of an existing document.
+	ChangeUpdate
+	// ChangeDelete is the deletion of a document.
+	ChangeDelete
+)
+
+// A Change is a change to a document.
+type Change struct {
+	Kind ChangeKind
+	// DecodeKey decodes the key fields of the document into its argument.
+	DecodeKey func(Document) error
+	// DecodeOld and DecodeNew decode the document as it was before the change
+	// and after it into their argument. Either is nil if the change does not
+	// have that version of the document.
+	DecodeOld, DecodeNew func(Document) error
+	// AsFunc converts its argument to driver-specific types for Change.As.
+	AsFunc func(interface{}) bool
+}
+
+// A ChangeIterator iterates over the changes to the documents of a
+// collection.
+type ChangeIterator interface {
+	// Next returns the next change, waiting for one if there is none yet. It
+	// returns io.EOF if there will be no more changes.
+	Next(ctx context.Context) (*Change, error)
+
+	// Stop stops the iterator, and the work it does in the background.
+	Stop()
+}
+
+// PaginatedIterator should be implemented by DocumentIterators whose position
+// can be saved, so that a later query can resume from it. See
+// Query.PaginationToken.
+type PaginatedIterator interface {
+	// PaginationToken returns a token that records the position after the
+	// documents that Next has returned. It returns nil if there are no more
+	// documents.
+	PaginationToken() ([]byte, error)
+}
+
+// An Aggregation is a value computed from the documents that a query matches.
+type Aggregation struct {
+	// Op is one of "count", "sum", "min" or "max".
+	Op string
+	// FieldPath is the field that the aggregation is computed from. It is nil
+	// for "count".
+	FieldPath []string
+}
+
+// Aggregator should be implemented by Collections that can compute
+// aggregations without returning every document of a query to the client.
+type Aggregator interface {
+	// RunAggregateQuery returns the value of agg over the documents that q
+	// matches, taking q's Offset and Limit into account. The value of "count"
+	// is an int64, of "sum" a float64, and of "min" and "max" the least or
+	// greatest value of the field, as decoded into an interface{}, or nil if
+	// no document has a value; see Accumulator.
+	//
+	// If it cannot compute agg, RunAggregateQuery returns an error with code
+	// Unimplemented, and the portable type computes agg from the documents
+	// that RunGetQuery returns.
+	RunAggregateQuery(ctx context.Context, q *Query, agg Aggregation) (interface{}, error)
+}
+
 // EqualOp is the name of the equality operator.
 // It is defined here to avoid confusion between "=" and "==".
 const EqualOp = "="
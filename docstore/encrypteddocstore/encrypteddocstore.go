@@ -0,0 +1,846 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encrypteddocstore provides a *docstore.Collection that encrypts
+// selected fields of documents with a *secrets.Keeper before they are written
+// to another Collection, and decrypts them when they are read. Use
+// NewCollection or NewCollectionWithKeyFunc to construct one.
+//
+//	coll, err := awsdynamodb.OpenCollection(db, "users", "ID", "", nil)
+//	...
+//	coll, err = encrypteddocstore.NewCollection(coll, "ID", &encrypteddocstore.Options{
+//		Keeper: keeper,
+//		Fields: []string{"SSN", "Email"},
+//	})
+//
+// Only top-level fields can be encrypted, and their values must be strings or
+// byte slices. Each is stored as the ciphertext of its value, a byte slice
+// that drivers store as a binary attribute; a nil value is stored as is.
+//
+// The ciphertexts are made with Keeper.EncryptWithAAD, with the field name and
+// the document key as the associated data, so a ciphertext copied to another
+// field or document fails to decrypt. The Keeper must therefore support
+// associated data, and a document must have a key when it is created with
+// encrypted fields, rather than be given one by the underlying Collection.
+//
+// Encrypting the same value twice gives different ciphertexts, so encrypted
+// fields cannot be used in query filters or sort orders. They can be set or
+// deleted in an Update, but not modified with docstore.Increment or the other
+// modifications. The primary key fields cannot be encrypted.
+//
+// # Underlying Collection
+//
+// The Collection passes the documents it writes to the underlying one as
+// map[string]interface{} values, and reads struct documents into structs of
+// an unnamed type with the same docstore field names. The underlying
+// Collection must therefore find the primary key of a document by its field
+// names, not with a function that expects the document's own type.
+//
+// Atomic action lists, Watch, the aggregations of Query and pagination
+// tokens are passed on to the underlying Collection; they are supported if it
+// supports them. The changes that Watch returns decrypt their documents.
+// Encrypted fields cannot be aggregated.
+//
+// # As
+//
+// encrypteddocstore exposes the types of the underlying Collection for As.
+package encrypteddocstore // import "gocloud.dev/docstore/encrypteddocstore"
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+	"google.golang.org/protobuf/proto"
+)
+
+// Options sets how the Collection returned by NewCollection encrypts
+// documents.
+type Options struct {
+	// Keeper encrypts and decrypts the values of the fields. Required.
+	Keeper *secrets.Keeper
+
+	// Fields are the names of the top-level document fields that are
+	// encrypted. Required.
+	Fields []string
+
+	// RevisionField is the name of the field holding the document revision
+	// in the underlying Collection. Defaults to
+	// docstore.DefaultRevisionField.
+	RevisionField string
+}
+
+// NewCollection returns a *docstore.Collection that encrypts the fields
+// opts.Fields of the documents written to coll, and decrypts them when they
+// are read. keyField is the document field holding the primary key of coll.
+// Closing the returned Collection does not close coll or opts.Keeper.
+func NewCollection(coll *docstore.Collection, keyField string, opts *Options) (*docstore.Collection, error) {
+	c, err := newCollection(coll, keyField, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return docstore.NewCollection(c), nil
+}
+
+// NewCollectionWithKeyFunc is like NewCollection, but for a Collection
+// whose primary key is made of more than one field. keyFunc takes a
+// document and returns its primary key, or nil if the document does not
+// have one. The fields of the key must not be encrypted.
+func NewCollectionWithKeyFunc(coll *docstore.Collection, keyFunc func(docstore.Document) interface{}, opts *Options) (*docstore.Collection, error) {
+	c, err := newCollection(coll, "", keyFunc, opts)
+	if err != nil {
+		return nil, err
+	}
+	return docstore.NewCollection(c), nil
+}
+
+func newCollection(coll *docstore.Collection, keyField string, keyFunc func(docstore.Document) interface{}, opts *Options) (*collection, error) {
+	if opts == nil || opts.Keeper == nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "encrypteddocstore: Options.Keeper is required")
+	}
+	if len(opts.Fields) == 0 {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "encrypteddocstore: Options.Fields is required")
+	}
+	c := &collection{
+		coll:          coll,
+		keyField:      keyField,
+		keyFunc:       keyFunc,
+		keeper:        opts.Keeper,
+		fields:        map[string]bool{},
+		revisionField: opts.RevisionField,
+	}
+	for _, f := range opts.Fields {
+		if f == "" || strings.Contains(f, ".") {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "encrypteddocstore: %q is not a top-level field", f)
+		}
+		if f == keyField {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "encrypteddocstore: the key field %q cannot be encrypted", f)
+		}
+		c.fields[f] = true
+	}
+	return c, nil
+}
+
+// collection implements driver.Collection.
+type collection struct {
+	coll          *docstore.Collection
+	keyField      string
+	keyFunc       func(docstore.Document) interface{}
+	keeper        *secrets.Keeper
+	fields        map[string]bool // the encrypted fields
+	revisionField string
+}
+
+// Key implements driver.Collection.Key.
+func (c *collection) Key(doc driver.Document) (interface{}, error) {
+	if c.keyField != "" {
+		key, _ := doc.GetField(c.keyField) // no error on missing key, and it will be nil
+		return key, nil
+	}
+	return c.keyFunc(doc.Origin), nil
+}
+
+// RevisionField implements driver.Collection.RevisionField.
+func (c *collection) RevisionField() string {
+	return c.revisionField
+}
+
+// RunActions implements driver.Collection.RunActions.
+func (c *collection) RunActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	return c.runActions(ctx, actions, opts, false)
+}
+
+// RunActionsAtomically implements driver.AtomicActionRunner with an atomic
+// action list of the underlying Collection.
+func (c *collection) RunActionsAtomically(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions) driver.ActionListError {
+	return c.runActions(ctx, actions, opts, true)
+}
+
+func (c *collection) runActions(ctx context.Context, actions []*driver.Action, opts *driver.RunActionsOptions, atomic bool) driver.ActionListError {
+	var alerr driver.ActionListError
+	addErr := func(i int, err error) {
+		alerr = append(alerr, struct {
+			Index int
+			Err   error
+		}{i, err})
+	}
+
+	l := c.coll.Actions()
+	if opts.BeforeDo != nil {
+		l.BeforeDo(opts.BeforeDo)
+	}
+	if atomic {
+		l.Atomic()
+	}
+	// The actions added to l, and for each a function that copies its
+	// results back to the action's document.
+	var (
+		added   []*driver.Action
+		results []func() error
+	)
+	for _, a := range actions {
+		f, err := c.addAction(ctx, l, a)
+		if err != nil {
+			addErr(a.Index, err)
+			continue
+		}
+		added = append(added, a)
+		results = append(results, f)
+	}
+	// None of an atomic list may run if one of its actions cannot.
+	if len(added) == 0 || (atomic && len(alerr) > 0) {
+		return alerr
+	}
+
+	failed := map[int]bool{}
+	if err := l.Do(ctx); err != nil {
+		var lerr docstore.ActionListError
+		if !errors.As(err, &lerr) {
+			addErr(-1, err)
+			return alerr
+		}
+		// Report the errors at the indexes of the actions passed to
+		// RunActions.
+		for _, e := range lerr {
+			if e.Index < 0 {
+				addErr(-1, e.Err)
+				return alerr
+			}
+			failed[e.Index] = true
+			addErr(added[e.Index].Index, e.Err)
+		}
+	}
+	for i, a := range added {
+		if failed[i] || results[i] == nil {
+			continue
+		}
+		if err := results[i](); err != nil {
+			addErr(a.Index, err)
+		}
+	}
+	return alerr
+}
+
+// addAction adds the action a to l, encrypting its fields and values. It
+// returns a function, which may be nil, that copies the results of the
+// action back to its document after it has run.
+func (c *collection) addAction(ctx context.Context, l *docstore.ActionList, a *driver.Action) (func() error, error) {
+	switch a.Kind {
+	case driver.Get:
+		if err := c.checkFieldPaths(a.FieldPaths); err != nil {
+			return nil, err
+		}
+		target, decrypt, err := c.readTarget(a.Doc, c.readFields(a.FieldPaths))
+		if err != nil {
+			return nil, err
+		}
+		l.Get(target, fieldPaths(a.FieldPaths)...)
+		return func() error { return decrypt(ctx) }, nil
+
+	case driver.Delete:
+		m, err := toMap(a.Doc)
+		if err != nil {
+			return nil, err
+		}
+		l.Delete(m)
+		return nil, nil
+
+	case driver.Update:
+		m, err := toMap(a.Doc)
+		if err != nil {
+			return nil, err
+		}
+		key, err := c.Key(a.Doc)
+		if err != nil {
+			return nil, err
+		}
+		mods, err := c.encryptMods(ctx, key, a.Mods)
+		if err != nil {
+			return nil, err
+		}
+		l.Update(m, mods)
+		return func() error { return c.copyWritten(a.Doc, m) }, nil
+
+	default: // Create, Replace or Put
+		m, err := toMap(a.Doc)
+		if err != nil {
+			return nil, err
+		}
+		key, err := c.Key(a.Doc)
+		if err != nil {
+			return nil, err
+		}
+		for name := range c.fields {
+			v, ok := m[name]
+			if !ok {
+				continue
+			}
+			if m[name], err = c.encrypt(ctx, key, name, v); err != nil {
+				return nil, err
+			}
+		}
+		switch a.Kind {
+		case driver.Create:
+			l.Create(m)
+		case driver.Replace:
+			l.Replace(m)
+		default:
+			l.Put(m)
+		}
+		return func() error { return c.copyWritten(a.Doc, m) }, nil
+	}
+}
+
+// copyWritten copies the key and revision that the underlying Collection
+// set in m, the written form of doc, back to doc.
+func (c *collection) copyWritten(doc driver.Document, m map[string]interface{}) error {
+	rev := c.revisionField
+	if rev == "" {
+		rev = docstore.DefaultRevisionField
+	}
+	// The key is set by a Create without one.
+	if v, ok := m[c.keyField]; ok && c.keyField != "" {
+		if err := doc.SetField(c.keyField, v); err != nil {
+			return err
+		}
+	}
+	if v, ok := m[rev]; ok && doc.HasField(rev) {
+		return doc.SetField(rev, v)
+	}
+	return nil
+}
+
+// encryptMods returns the docstore.Mods for mods, modifications of the
+// document with the given key, with the values of the encrypted fields
+// encrypted.
+func (c *collection) encryptMods(ctx context.Context, key interface{}, mods []driver.Mod) (docstore.Mods, error) {
+	dmods := docstore.Mods{}
+	for _, m := range mods {
+		v := m.Value
+		switch {
+		case !c.fields[m.FieldPath[0]]:
+			if inc, ok := v.(driver.IncOp); ok {
+				v = docstore.Increment(inc.Amount)
+			}
+		case len(m.FieldPath) > 1:
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot modify %q inside the encrypted field %q",
+				fieldPath(m.FieldPath), m.FieldPath[0])
+		default:
+			switch v.(type) {
+			case driver.IncOp, driver.ListAppendOp, driver.SetAddOp, driver.SetRemoveOp:
+				return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "the encrypted field %q can only be set or deleted", m.FieldPath[0])
+			}
+			var err error
+			if v, err = c.encrypt(ctx, key, m.FieldPath[0], v); err != nil {
+				return nil, err
+			}
+		}
+		dmods[fieldPath(m.FieldPath)] = v
+	}
+	return dmods, nil
+}
+
+// checkFieldPaths returns an error if one of fps names a field inside an
+// encrypted field.
+func (c *collection) checkFieldPaths(fps [][]string) error {
+	for _, fp := range fps {
+		if len(fp) > 1 && c.fields[fp[0]] {
+			return gcerr.Newf(gcerr.InvalidArgument, nil, "cannot get %q inside the encrypted field %q", fieldPath(fp), fp[0])
+		}
+	}
+	return nil
+}
+
+// readFields returns the encrypted fields that a read with the field paths
+// fps reads.
+func (c *collection) readFields(fps [][]string) map[string]bool {
+	read := map[string]bool{}
+	for name := range c.fields {
+		read[name] = len(fps) == 0
+	}
+	for _, fp := range fps {
+		if c.fields[fp[0]] {
+			read[fp[0]] = true
+		}
+	}
+	return read
+}
+
+// readTarget returns the document to read doc's stored form into, and a
+// function that decrypts the encrypted fields in read into doc once it has
+// been read. The other fields are copied to doc as they are.
+//
+// For a map, the target is a map. For a struct, it is a struct with the same
+// docstore field names and types, except that the encrypted fields are byte
+// slices, so that the underlying Collection decodes the other fields as it
+// would into doc.
+func (c *collection) readTarget(doc driver.Document, read map[string]bool) (docstore.Document, func(context.Context) error, error) {
+	if m, ok := doc.Origin.(map[string]interface{}); ok {
+		target := map[string]interface{}{}
+		for k, v := range m {
+			if !c.fields[k] {
+				target[k] = v
+			}
+		}
+		return target, func(ctx context.Context) error {
+			// Copy the key first, since the ciphertexts are bound to it.
+			for k, v := range target {
+				if !c.fields[k] {
+					m[k] = v
+				}
+			}
+			key, err := c.Key(doc)
+			if err != nil {
+				return err
+			}
+			for k, v := range target {
+				if !c.fields[k] || !read[k] {
+					continue
+				}
+				if m[k], err = c.decrypt(ctx, key, k, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	names := doc.FieldNames()
+	sfs := make([]reflect.StructField, len(names))
+	for i, name := range names {
+		t, err := doc.FieldType(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.fields[name] {
+			t = typeOfBytes
+		}
+		sfs[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: t,
+			Tag:  reflect.StructTag(fmt.Sprintf("docstore:%q", name)),
+		}
+	}
+	target := reflect.New(reflect.StructOf(sfs))
+	tv := target.Elem()
+	for i, name := range names {
+		if c.fields[name] {
+			continue
+		}
+		// A field in a nil embedded struct pointer has no value.
+		if v, err := doc.GetField(name); err == nil && v != nil {
+			tv.Field(i).Set(reflect.ValueOf(v))
+		}
+	}
+	return target.Interface(), func(ctx context.Context) error {
+		// Set the key first, since the ciphertexts are bound to it.
+		for i, name := range names {
+			if !c.fields[name] {
+				if err := setField(doc, name, tv.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+		key, err := c.Key(doc)
+		if err != nil {
+			return err
+		}
+		for i, name := range names {
+			if !c.fields[name] || !read[name] {
+				continue
+			}
+			v, err := c.decrypt(ctx, key, name, tv.Field(i).Interface())
+			if err != nil {
+				return err
+			}
+			t, _ := doc.FieldType(name)
+			var rv reflect.Value
+			switch {
+			case v == nil:
+				rv = reflect.Zero(t)
+			case reflect.TypeOf(v).ConvertibleTo(t):
+				rv = reflect.ValueOf(v).Convert(t)
+			default:
+				return gcerr.Newf(gcerr.InvalidArgument, nil, "cannot decrypt the field %q of type %T into %s", name, v, t)
+			}
+			if err := setField(doc, name, rv); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// setField sets the named field of doc to v, unless the field is in a nil
+// embedded struct pointer and v is its zero value.
+func setField(doc driver.Document, name string, v reflect.Value) error {
+	if _, err := doc.GetField(name); err != nil {
+		if v.IsZero() {
+			return nil
+		}
+		return err
+	}
+	return doc.SetField(name, v.Interface())
+}
+
+var typeOfBytes = reflect.TypeOf([]byte(nil))
+
+// The first byte of a plaintext says what type of value the rest is.
+const (
+	plainString byte = 's'
+	plainBytes  byte = 'b'
+)
+
+// encrypt returns the ciphertext of v, the value of the named field of the
+// document with the given key. It returns nil for nil.
+func (c *collection) encrypt(ctx context.Context, key interface{}, name string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if key == nil || reflect.ValueOf(key).IsZero() {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "a document with the encrypted field %q must have a key", name)
+	}
+	var plaintext []byte
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.String:
+		plaintext = append([]byte{plainString}, rv.String()...)
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		plaintext = append([]byte{plainBytes}, rv.Bytes()...)
+	default:
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "the encrypted field %q must be a string or a byte slice, not %T", name, v)
+	}
+	ciphertext, err := c.keeper.EncryptWithAAD(ctx, plaintext, aad(key, name))
+	if err != nil {
+		return nil, fmt.Errorf("encrypting %q: %w", name, err)
+	}
+	return ciphertext, nil
+}
+
+// decrypt returns the value that encrypt encrypted into v, a ciphertext
+// read from the named field of the document with the given key. It returns
+// nil for nil.
+func (c *collection) decrypt(ctx context.Context, key interface{}, name string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	ciphertext, ok := v.([]byte)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "the encrypted field %q holds %T, not a ciphertext", name, v)
+	}
+	if ciphertext == nil {
+		return nil, nil
+	}
+	plaintext, err := c.keeper.DecryptWithAAD(ctx, ciphertext, aad(key, name))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %q: %w", name, err)
+	}
+	if len(plaintext) == 0 {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "the encrypted field %q has an empty plaintext", name)
+	}
+	switch plaintext[0] {
+	case plainString:
+		return string(plaintext[1:]), nil
+	case plainBytes:
+		return plaintext[1:], nil
+	default:
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "the encrypted field %q has a plaintext of unknown type %q", name, plaintext[0])
+	}
+}
+
+// aad returns the associated data of the ciphertext of the named field of
+// the document with the given key. Keys are compared by their formatting,
+// since a key read back may have another type than the one written, such as
+// int64 for int.
+func aad(key interface{}, name string) []byte {
+	return []byte(fmt.Sprintf("%s\x00%v", name, key))
+}
+
+// toMap returns the top-level fields of doc, as they would be encoded.
+func toMap(doc driver.Document) (map[string]interface{}, error) {
+	e := &fieldsEncoder{m: map[string]interface{}{}}
+	if err := doc.Encode(e); err != nil {
+		return nil, err
+	}
+	return e.m, nil
+}
+
+// A fieldsEncoder collects the values of the top-level fields of a
+// document without encoding them, so that the underlying Collection encodes
+// them as it would the document's. Struct fields that are omitted when empty
+// are left out.
+type fieldsEncoder struct {
+	m   map[string]interface{}
+	val interface{}
+}
+
+func (e *fieldsEncoder) EncodeNil()                    { e.val = nil }
+func (e *fieldsEncoder) EncodeBool(x bool)             { e.val = x }
+func (e *fieldsEncoder) EncodeString(x string)         { e.val = x }
+func (e *fieldsEncoder) EncodeInt(x int64)             { e.val = x }
+func (e *fieldsEncoder) EncodeUint(x uint64)           { e.val = x }
+func (e *fieldsEncoder) EncodeFloat(x float64)         { e.val = x }
+func (e *fieldsEncoder) EncodeBytes(x []byte)          { e.val = x }
+func (e *fieldsEncoder) EncodeList(int) driver.Encoder { panic("impossible") }
+func (e *fieldsEncoder) ListIndex(int)                 { panic("impossible") }
+func (e *fieldsEncoder) EncodeMap(int) driver.Encoder  { return e }
+func (e *fieldsEncoder) MapKey(k string)               { e.m[k] = e.val }
+
+// EncodeSpecial keeps every field value as it is.
+func (e *fieldsEncoder) EncodeSpecial(v reflect.Value) (bool, error) {
+	// A struct field whose address is a proto.Message is encoded as one,
+	// but the copy of it in an interface{} is not addressable.
+	if v.CanAddr() && !v.Type().Implements(typeOfProtoMessage) && reflect.PtrTo(v.Type()).Implements(typeOfProtoMessage) {
+		v = v.Addr()
+	}
+	e.val = v.Interface()
+	return true, nil
+}
+
+var typeOfProtoMessage = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// RunGetQuery implements driver.Collection.RunGetQuery.
+func (c *collection) RunGetQuery(ctx context.Context, q *driver.Query) (driver.DocumentIterator, error) {
+	dq, err := c.query(q)
+	if err != nil {
+		return nil, err
+	}
+	return &docIterator{c: c, it: dq.Get(ctx, fieldPaths(q.FieldPaths)...), read: c.readFields(q.FieldPaths)}, nil
+}
+
+// SupportsPaginationTokens implements driver.PaginatedQueryer. Queries with a
+// pagination token fail if the underlying Collection does not support them.
+func (c *collection) SupportsPaginationTokens() bool {
+	return true
+}
+
+// QueryPlan implements driver.Collection.QueryPlan.
+func (c *collection) QueryPlan(q *driver.Query) (string, error) {
+	dq, err := c.query(q)
+	if err != nil {
+		return "", err
+	}
+	return dq.Plan(fieldPaths(q.FieldPaths)...)
+}
+
+// query returns the query on the underlying Collection for q.
+func (c *collection) query(q *driver.Query) (*docstore.Query, error) {
+	if err := c.checkFieldPaths(q.FieldPaths); err != nil {
+		return nil, err
+	}
+	dq := c.coll.Query()
+	for _, f := range q.Filters {
+		if c.fields[f.FieldPath[0]] {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot filter on the encrypted field %q", f.FieldPath[0])
+		}
+		dq.Where(fieldPath(f.FieldPath), f.Op, f.Value)
+	}
+	if q.Offset > 0 {
+		dq.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		dq.Limit(q.Limit)
+	}
+	if q.OrderByField != "" {
+		if c.fields[q.OrderByField] {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot order by the encrypted field %q", q.OrderByField)
+		}
+		dir := docstore.Descending
+		if q.OrderAscending {
+			dir = docstore.Ascending
+		}
+		dq.OrderBy(q.OrderByField, dir)
+	}
+	if q.BeforeQuery != nil {
+		dq.BeforeQuery(q.BeforeQuery)
+	}
+	if q.PaginationToken != nil {
+		dq.StartFrom(base64.RawURLEncoding.EncodeToString(q.PaginationToken))
+	}
+	return dq, nil
+}
+
+// docIterator implements driver.DocumentIterator.
+type docIterator struct {
+	c    *collection
+	it   *docstore.DocumentIterator
+	read map[string]bool // the encrypted fields that the query reads
+}
+
+func (it *docIterator) Next(ctx context.Context, doc driver.Document) error {
+	target, decrypt, err := it.c.readTarget(doc, it.read)
+	if err != nil {
+		return err
+	}
+	if err := it.it.Next(ctx, target); err != nil {
+		return err
+	}
+	return decrypt(ctx)
+}
+
+func (it *docIterator) Stop() {
+	it.it.Stop()
+}
+
+func (it *docIterator) As(i interface{}) bool {
+	return it.it.As(i)
+}
+
+// PaginationToken implements driver.PaginatedIterator with the tokens of the
+// underlying Collection.
+func (it *docIterator) PaginationToken() ([]byte, error) {
+	token, err := it.it.PaginationToken()
+	if err != nil || token == "" {
+		return nil, err
+	}
+	return base64.RawURLEncoding.DecodeString(token)
+}
+
+// RunAggregateQuery implements driver.Aggregator with the aggregations of
+// the underlying Collection.
+func (c *collection) RunAggregateQuery(ctx context.Context, q *driver.Query, agg driver.Aggregation) (interface{}, error) {
+	if len(agg.FieldPath) > 0 && c.fields[agg.FieldPath[0]] {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "cannot aggregate the encrypted field %q", agg.FieldPath[0])
+	}
+	dq, err := c.query(q)
+	if err != nil {
+		return nil, err
+	}
+	fp := fieldPath(agg.FieldPath)
+	switch agg.Op {
+	case "count":
+		n, err := dq.Count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	case "sum":
+		sum, err := dq.Sum(ctx, fp)
+		if err != nil {
+			return nil, err
+		}
+		return sum, nil
+	case "min":
+		return dq.Min(ctx, fp)
+	case "max":
+		return dq.Max(ctx, fp)
+	default:
+		return nil, gcerr.Newf(gcerr.Unimplemented, nil, "unknown aggregation %q", agg.Op)
+	}
+}
+
+// Watch implements driver.Watcher with the changes of the underlying
+// Collection, whose documents it decrypts.
+func (c *collection) Watch(ctx context.Context, opts *driver.WatchOptions) (driver.ChangeIterator, error) {
+	it := c.coll.Watch(ctx, &docstore.WatchOptions{FromOldest: opts.FromOldest})
+	return &changeIterator{c: c, ctx: ctx, it: it}, nil
+}
+
+// changeIterator implements driver.ChangeIterator.
+type changeIterator struct {
+	c   *collection
+	ctx context.Context // for decrypting, which Change methods take no context for
+	it  *docstore.ChangeIterator
+}
+
+func (it *changeIterator) Next(ctx context.Context) (*driver.Change, error) {
+	ch, err := it.it.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// decode decodes a version of the changed document with f, decrypting
+	// the encrypted fields in read.
+	decode := func(f func(docstore.Document) error, read map[string]bool) func(driver.Document) error {
+		return func(doc driver.Document) error {
+			target, decrypt, err := it.c.readTarget(doc, read)
+			if err != nil {
+				return err
+			}
+			if err := f(target); err != nil {
+				return err
+			}
+			return decrypt(it.ctx)
+		}
+	}
+	all := it.c.readFields(nil)
+	return &driver.Change{
+		Kind:      driver.ChangeKind(ch.Kind),
+		DecodeKey: decode(ch.Key, nil),
+		DecodeOld: decode(ch.Old, all),
+		DecodeNew: decode(ch.New, all),
+		AsFunc:    ch.As,
+	}, nil
+}
+
+func (it *changeIterator) Stop() {
+	it.it.Stop()
+}
+
+func fieldPath(fp []string) docstore.FieldPath {
+	return docstore.FieldPath(strings.Join(fp, "."))
+}
+
+func fieldPaths(fps [][]string) []docstore.FieldPath {
+	var res []docstore.FieldPath
+	for _, fp := range fps {
+		res = append(res, fieldPath(fp))
+	}
+	return res
+}
+
+// RevisionToBytes implements driver.Collection.RevisionToBytes.
+func (c *collection) RevisionToBytes(rev interface{}) ([]byte, error) {
+	s, err := c.coll.RevisionToString(rev)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// BytesToRevision implements driver.Collection.BytesToRevision.
+func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
+	return c.coll.StringToRevision(string(b))
+}
+
+// As implements driver.Collection.As.
+func (c *collection) As(i interface{}) bool {
+	return c.coll.As(i)
+}
+
+// ErrorAs implements driver.Collection.ErrorAs.
+func (c *collection) ErrorAs(err error, i interface{}) bool {
+	return c.coll.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Collection.ErrorCode.
+func (c *collection) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Collection.Close. It does not close the
+// underlying Collection or the Keeper.
+func (c *collection) Close() error {
+	return nil
+}
@@ -0,0 +1,387 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypteddocstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/docstore"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/docstore/memdocstore"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+type harness struct {
+	keeper *secrets.Keeper
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{keeper: newKeeper(t)}, nil
+}
+
+func newKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	key, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(key)
+}
+
+// highScoreKey is like drivertest.HighScoreKey, but finds the key of any
+// document by its field names, as the underlying Collection must.
+func highScoreKey(doc docstore.Document) interface{} {
+	d, err := driver.NewDocument(doc)
+	if err != nil {
+		panic(err)
+	}
+	g, _ := d.GetField("Game")
+	p, _ := d.GetField("Player")
+	if g == nil || g == "" || p == nil || p == "" {
+		return ""
+	}
+	return fmt.Sprintf("%v|%v", g, p)
+}
+
+func (h *harness) MakeCollection(_ context.Context, kind drivertest.CollectionKind) (driver.Collection, error) {
+	// No conformance document has the encrypted field, so the Collection
+	// must behave like the one it wraps.
+	opts := &Options{Keeper: h.keeper, Fields: []string{"Secret"}}
+	switch kind {
+	case drivertest.SingleKey, drivertest.NoRev:
+		coll, err := memdocstore.OpenCollection(drivertest.KeyField, nil)
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(coll, drivertest.KeyField, nil, opts)
+	case drivertest.TwoKey:
+		coll, err := memdocstore.OpenCollectionWithKeyFunc(highScoreKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		return newCollection(coll, "", drivertest.HighScoreKey, opts)
+	case drivertest.AltRev:
+		coll, err := memdocstore.OpenCollection(drivertest.KeyField, &memdocstore.Options{RevisionField: drivertest.AlternateRevisionField})
+		if err != nil {
+			return nil, err
+		}
+		opts.RevisionField = drivertest.AlternateRevisionField
+		return newCollection(coll, drivertest.KeyField, nil, opts)
+	default:
+		panic("bad kind")
+	}
+}
+
+func (*harness) BeforeDoTypes() []interface{}    { return nil }
+func (*harness) BeforeQueryTypes() []interface{} { return nil }
+
+func (*harness) RevisionsEqual(rev1, rev2 interface{}) bool { return rev1 == rev2 }
+
+func (*harness) Close() {}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, nil, nil)
+}
+
+type docmap = map[string]interface{}
+
+type ssn string
+
+type user struct {
+	ID               string
+	Name             string
+	SSN              ssn
+	Photo            []byte `docstore:"photo,omitempty"`
+	Joined           time.Time
+	DocstoreRevision interface{}
+}
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	coll, err := NewCollection(mem, "ID", &Options{Keeper: newKeeper(t), Fields: []string{"SSN", "photo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coll.Close()
+
+	joined := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	u := &user{ID: "a", Name: "Ann", SSN: "123-45-6789", Photo: []byte{1, 2, 3}, Joined: joined}
+	if err := coll.Put(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if u.DocstoreRevision == nil {
+		t.Error("Put did not set the revision")
+	}
+
+	// The underlying Collection holds ciphertexts.
+	stored := docmap{"ID": "a"}
+	if err := mem.Get(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := stored["SSN"].([]byte); !ok || bytes.Contains(s, []byte("6789")) {
+		t.Errorf("stored SSN is %v, want a ciphertext", stored["SSN"])
+	}
+	if stored["Name"] != "Ann" {
+		t.Errorf("stored Name is %v, want it unencrypted", stored["Name"])
+	}
+
+	got := &user{ID: "a"}
+	if err := coll.Get(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(u, got); diff != "" {
+		t.Errorf("Get: diff (-want +got):\n%s", diff)
+	}
+	gotMap := docmap{"ID": "a"}
+	if err := coll.Get(ctx, gotMap, "SSN", "Name"); err != nil {
+		t.Fatal(err)
+	}
+	if want := (docmap{"ID": "a", "Name": "Ann", "SSN": "123-45-6789"}); !cmp.Equal(gotMap, want) {
+		t.Errorf("Get of a map: got %v, want %v", gotMap, want)
+	}
+
+	// Encrypted fields can be set and deleted, not otherwise modified.
+	if err := coll.Update(ctx, &user{ID: "a"}, docstore.Mods{"SSN": "987-65-4321", "photo": nil}); err != nil {
+		t.Fatal(err)
+	}
+	for _, mods := range []docstore.Mods{
+		{"SSN": docstore.ListAppend("x")},
+		{"SSN": 1},
+		{"SSN.x": "y"},
+	} {
+		if err := coll.Update(ctx, &user{ID: "a"}, mods); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("Update %v: got %v, want InvalidArgument", mods, err)
+		}
+	}
+
+	it := coll.Query().Where("Name", "=", "Ann").Get(ctx)
+	defer it.Stop()
+	got = &user{}
+	if err := it.Next(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SSN != "987-65-4321" || got.Photo != nil || !got.Joined.Equal(joined) {
+		t.Errorf("query: got %+v, want the updated SSN and no photo", got)
+	}
+	if err := it.Next(ctx, got); err != io.EOF {
+		t.Errorf("query: got %v, want EOF", err)
+	}
+
+	it = coll.Query().Where("SSN", "=", "987-65-4321").Get(ctx)
+	defer it.Stop()
+	if err := it.Next(ctx, &user{}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("query filtering on an encrypted field: got %v, want InvalidArgument", err)
+	}
+
+	if err := coll.Put(ctx, docmap{"ID": "b", "SSN": 12}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Put of a number: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestCiphertextBinding(t *testing.T) {
+	ctx := context.Background()
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	coll, err := NewCollection(mem, "ID", &Options{Keeper: newKeeper(t), Fields: []string{"SSN", "Other"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coll.Close()
+
+	if err := coll.Put(ctx, docmap{"ID": "a", "SSN": "123"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := coll.Put(ctx, docmap{"ID": "b", "SSN": "456"}); err != nil {
+		t.Fatal(err)
+	}
+	stored := docmap{"ID": "a"}
+	if err := mem.Get(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ciphertext of a's SSN does not decrypt in another document or field.
+	for _, test := range []struct {
+		id    string
+		field docstore.FieldPath
+	}{
+		{"b", "SSN"},
+		{"a", "Other"},
+	} {
+		if err := mem.Update(ctx, docmap{"ID": test.id}, docstore.Mods{test.field: stored["SSN"]}); err != nil {
+			t.Fatal(err)
+		}
+		if err := coll.Get(ctx, docmap{"ID": test.id}); err == nil {
+			t.Errorf("%s of %q: got no error, want one", test.field, test.id)
+		}
+	}
+
+	// A document without a key cannot have encrypted fields.
+	if err := coll.Create(ctx, docmap{"SSN": "789"}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Create without a key: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestUnderlyingFeatures(t *testing.T) {
+	ctx := context.Background()
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	coll, err := NewCollection(mem, "ID", &Options{Keeper: newKeeper(t), Fields: []string{"SSN"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coll.Close()
+	for _, id := range []string{"a", "b"} {
+		if err := coll.Put(ctx, docmap{"ID": id, "SSN": "123", "N": 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := coll.Query().Count(ctx); err != nil || n != 2 {
+		t.Errorf("Count: got %d, %v; want 2", n, err)
+	}
+	if sum, err := coll.Query().Sum(ctx, "N"); err != nil || sum != 4 {
+		t.Errorf("Sum: got %v, %v; want 4", sum, err)
+	}
+	if _, err := coll.Query().Max(ctx, "SSN"); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Max of an encrypted field: got %v, want InvalidArgument", err)
+	}
+
+	// memdocstore supports neither transactions, pagination tokens nor Watch.
+	err = coll.Actions().Atomic().Put(docmap{"ID": "c", "SSN": "x"}).Do(ctx)
+	if gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("atomic action list: got %v, want Unimplemented", err)
+	}
+	it := coll.Query().StartFrom("dG9rZW4").Get(ctx)
+	defer it.Stop()
+	if err := it.Next(ctx, docmap{}); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("StartFrom: got %v, want Unimplemented", err)
+	}
+	cit := coll.Watch(ctx, nil)
+	defer cit.Stop()
+	if _, err := cit.Next(ctx); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("Watch: got %v, want Unimplemented", err)
+	}
+}
+
+// watchCollection is a driver.Collection whose only change is the creation
+// of a document.
+type watchCollection struct {
+	driver.Collection
+	doc docmap
+}
+
+func (c *watchCollection) Watch(context.Context, *driver.WatchOptions) (driver.ChangeIterator, error) {
+	return &watchIterator{doc: c.doc}, nil
+}
+
+func (*watchCollection) ErrorCode(err error) gcerrors.ErrorCode { return gcerrors.Code(err) }
+
+type watchIterator struct {
+	doc docmap
+}
+
+func (it *watchIterator) Next(context.Context) (*driver.Change, error) {
+	if it.doc == nil {
+		return nil, io.EOF
+	}
+	doc := it.doc
+	it.doc = nil
+	decode := func(ddoc driver.Document) error {
+		for k, v := range doc {
+			if err := ddoc.SetField(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return &driver.Change{Kind: driver.ChangeCreate, DecodeKey: decode, DecodeNew: decode}, nil
+}
+
+func (*watchIterator) Stop() {}
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	wc := &watchCollection{}
+	c, err := newCollection(docstore.NewCollection(wc), "ID", nil, &Options{Keeper: newKeeper(t), Fields: []string{"SSN"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := c.encrypt(ctx, "a", "SSN", "123-45-6789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc.doc = docmap{"ID": "a", "Name": "Ann", "SSN": ciphertext}
+
+	it := docstore.NewCollection(c).Watch(ctx, nil)
+	defer it.Stop()
+	ch, err := it.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &user{}
+	if err := ch.New(got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "a" || got.Name != "Ann" || got.SSN != "123-45-6789" {
+		t.Errorf("New: got %+v, want the decrypted document", got)
+	}
+	key := &user{SSN: "unchanged"}
+	if err := ch.Key(key); err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != "a" || key.SSN != "unchanged" {
+		t.Errorf("Key: got %+v, want only the key set", key)
+	}
+}
+
+func TestNewCollectionErrors(t *testing.T) {
+	mem, err := memdocstore.OpenCollection("ID", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mem.Close()
+	keeper := newKeeper(t)
+	for _, opts := range []*Options{
+		nil,
+		{Fields: []string{"SSN"}},
+		{Keeper: keeper},
+		{Keeper: keeper, Fields: []string{"ID"}},
+		{Keeper: keeper, Fields: []string{"a.b"}},
+	} {
+		if _, err := NewCollection(mem, "ID", opts); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%+v: got %v, want InvalidArgument", opts, err)
+		}
+	}
+}
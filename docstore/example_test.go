@@ -23,6 +23,7 @@ import (
 
 	firestore "cloud.google.com/go/firestore/apiv1"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"gocloud.dev/blob"
 	"gocloud.dev/docstore"
 	_ "gocloud.dev/docstore/awsdynamodb"
 	_ "gocloud.dev/docstore/gcpfirestore"
@@ -284,3 +285,45 @@ func Example_optimisticLocking() {
 	// &{Pat 7 1}
 	// &{Pat 14 2}
 }
+
+func ExampleExport() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var coll *docstore.Collection
+	var bucket *blob.Bucket
+
+	// Back up the collection to a blob.
+	w, err := bucket.NewWriter(ctx, "backups/players.ndjson", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := docstore.Export(ctx, coll, w, nil); err != nil {
+		w.Close()
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleImport() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var coll *docstore.Collection
+	var bucket *blob.Bucket
+
+	// Restore the collection from a blob written by Export.
+	r, err := bucket.NewReader(ctx, "backups/players.ndjson", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+	err = docstore.Import(ctx, r, coll, &docstore.ImportOptions{
+		Progress: func(n int64) { log.Printf("restored %d documents", n) },
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -60,6 +60,13 @@
 // https://cloud.google.com/firestore/docs/query-data/indexing for details.
 //
 // See https://cloud.google.com/firestore/docs/query-data/queries for more information on Firestore queries.
+//
+// # Updates
+//
+// docstore.SetAdd and docstore.SetRemove modify array fields with the
+// arrayUnion and arrayRemove transforms. Firestore has no transform that
+// appends to an array unconditionally, so docstore.ListAppend returns an
+// Unimplemented error.
 package gcpfirestore // import "gocloud.dev/docstore/gcpfirestore"
 
 import (
@@ -75,11 +82,13 @@ import (
 	vkit "cloud.google.com/go/firestore/apiv1"
 	pb "cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/attribute"
 	"gocloud.dev/docstore"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/gcp"
 	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/internal/useragent"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
@@ -545,8 +554,9 @@ func processMods(mods []driver.Mod) (fields map[string]*pb.Value, maskPaths []st
 		sfp := toServiceFieldPath(m.FieldPath)
 		// If m.Value is nil, we want to delete it. In that case, we put the field in
 		// the mask but not in the doc.
-		if inc, ok := m.Value.(driver.IncOp); ok {
-			pv, err := encodeValue(inc.Amount)
+		switch v := m.Value.(type) {
+		case driver.IncOp:
+			pv, err := encodeValue(v.Amount)
 			if err != nil {
 				return nil, nil, nil, err
 			}
@@ -556,7 +566,32 @@ func processMods(mods []driver.Mod) (fields map[string]*pb.Value, maskPaths []st
 					Increment: pv,
 				},
 			})
-		} else {
+		case driver.ListAppendOp:
+			// Firestore can only append the elements that a list lacks.
+			return nil, nil, nil, gcerr.Newf(gcerr.Unimplemented, nil, "ListAppend is not supported; use SetAdd")
+		case driver.SetAddOp:
+			pv, err := encodeValue(v.Values)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			transforms = append(transforms, &pb.DocumentTransform_FieldTransform{
+				FieldPath: sfp,
+				TransformType: &pb.DocumentTransform_FieldTransform_AppendMissingElements{
+					AppendMissingElements: pv.GetArrayValue(),
+				},
+			})
+		case driver.SetRemoveOp:
+			pv, err := encodeValue(v.Values)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			transforms = append(transforms, &pb.DocumentTransform_FieldTransform{
+				FieldPath: sfp,
+				TransformType: &pb.DocumentTransform_FieldTransform_RemoveAllFromArray{
+					RemoveAllFromArray: pv.GetArrayValue(),
+				},
+			})
+		default:
 			// The field path of every other mod belongs in the mask.
 			maskPaths = append(maskPaths, sfp)
 			if m.Value != nil {
@@ -784,6 +819,18 @@ func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
 	return &ts, nil
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (c *collection) TelemetryAttributes() []attribute.KeyValue {
+	name := c.collPath
+	if i := strings.Index(name, "/documents/"); i >= 0 {
+		name = name[i+len("/documents/"):]
+	}
+	return []attribute.KeyValue{
+		telemetry.DBSystemKey.String("firestore"),
+		telemetry.DBCollectionNameKey.String(name),
+	}
+}
+
 func (c *collection) As(i interface{}) bool {
 	p, ok := i.(**vkit.Client)
 	if !ok {
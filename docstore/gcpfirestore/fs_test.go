@@ -24,6 +24,7 @@ import (
 	"gocloud.dev/docstore"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/docstore/drivertest"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc/status"
@@ -206,3 +207,27 @@ func TestResourceIDRegexp(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessModsSets(t *testing.T) {
+	mods := []driver.Mod{
+		{FieldPath: []string{"a"}, Value: driver.SetAddOp{Values: []interface{}{"x", "y"}}},
+		{FieldPath: []string{"b"}, Value: driver.SetRemoveOp{Values: []interface{}{1}}},
+	}
+	fields, maskPaths, transforms, err := processMods(mods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 0 || len(maskPaths) != 0 || len(transforms) != 2 {
+		t.Fatalf("got %d fields, %d mask paths and %d transforms, want only 2 transforms", len(fields), len(maskPaths), len(transforms))
+	}
+	if got := transforms[0].GetAppendMissingElements(); len(got.GetValues()) != 2 || got.Values[1].GetStringValue() != "y" {
+		t.Errorf("SetAdd: got %v, want an arrayUnion of x and y", transforms[0])
+	}
+	if got := transforms[1].GetRemoveAllFromArray(); len(got.GetValues()) != 1 || got.Values[0].GetIntegerValue() != 1 {
+		t.Errorf("SetRemove: got %v, want an arrayRemove of 1", transforms[1])
+	}
+	_, _, _, err = processMods([]driver.Mod{{FieldPath: []string{"a"}, Value: driver.ListAppendOp{Values: []interface{}{"x"}}}})
+	if gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("ListAppend: got %v, want Unimplemented", err)
+	}
+}
@@ -162,7 +162,33 @@ func (d decoder) AsBytes() ([]byte, bool) {
 }
 
 func (d decoder) AsInterface() (interface{}, error) {
-	return d.val, nil
+	return toGoValue(d.val), nil
+}
+
+// toGoValue returns a copy of the encoded value v that shares nothing with
+// the stored document: lists and maps become fresh []interface{} and
+// map[string]interface{} values, and byte slices are copied. Changing the
+// decoded value does not change the collection.
+func toGoValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = toGoValue(e)
+		}
+		return s
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = toGoValue(e)
+		}
+		return m
+	case []byte:
+		return append([]byte(nil), v...)
+	default:
+		// nil, bool, int64, float64, string and time.Time.
+		return v
+	}
 }
 
 func (d decoder) ListLen() (int, bool) {
@@ -157,3 +157,34 @@ func TestDecodeDoc(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeDocCopies(t *testing.T) {
+	// Values decoded into interface{} must not share memory with the stored
+	// document.
+	in := storedDoc{
+		"X": map[string]interface{}{
+			"l": []interface{}{int64(1), map[string]interface{}{"a": "b"}},
+			"b": []byte("abc"),
+		},
+	}
+	var got struct{ X interface{} }
+	if err := decodeDoc(in, drivertest.MustDocument(&got), nil); err != nil {
+		t.Fatal(err)
+	}
+	m := got.X.(map[string]interface{})
+	m["new"] = true
+	l := m["l"].([]interface{})
+	l[0] = int64(2)
+	l[1].(map[string]interface{})["a"] = "c"
+	m["b"].([]byte)[0] = 'x'
+
+	want := storedDoc{
+		"X": map[string]interface{}{
+			"l": []interface{}{int64(1), map[string]interface{}{"a": "b"}},
+			"b": []byte("abc"),
+		},
+	}
+	if diff := cmp.Diff(want, in); diff != "" {
+		t.Errorf("stored document changed (-want +got):\n%s", diff)
+	}
+}
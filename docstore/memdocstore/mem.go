@@ -308,15 +308,29 @@ func (c *collection) update(doc storedDoc, mods []driver.Mod) error {
 			return err
 		}
 		gmod.key = mod.FieldPath[len(mod.FieldPath)-1]
-		if inc, ok := mod.Value.(driver.IncOp); ok {
-			amt, err := encodeValue(inc.Amount)
+		switch v := mod.Value.(type) {
+		case nil:
+		case driver.IncOp:
+			amt, err := encodeValue(v.Amount)
 			if err != nil {
 				return err
 			}
 			if gmod.encodedValue, err = add(gmod.parentMap[gmod.key], amt); err != nil {
 				return err
 			}
-		} else if mod.Value != nil {
+		case driver.ListAppendOp:
+			if gmod.encodedValue, err = modifyList(gmod.parentMap[gmod.key], v.Values, appendAll); err != nil {
+				return err
+			}
+		case driver.SetAddOp:
+			if gmod.encodedValue, err = modifyList(gmod.parentMap[gmod.key], v.Values, addMissing); err != nil {
+				return err
+			}
+		case driver.SetRemoveOp:
+			if gmod.encodedValue, err = modifyList(gmod.parentMap[gmod.key], v.Values, removeAll); err != nil {
+				return err
+			}
+		default:
 			// Make sure the value encodes successfully.
 			if gmod.encodedValue, err = encodeValue(mod.Value); err != nil {
 				return err
@@ -368,6 +382,73 @@ func add(x, y interface{}) (interface{}, error) {
 	}
 }
 
+// The ways that modifyList modifies a list.
+const (
+	appendAll  = iota // append the values
+	addMissing        // append the values that are not in the list
+	removeAll         // remove the elements equal to a value
+)
+
+// modifyList returns a copy of the encoded list old, which may be nil,
+// modified with values as how says. Sets are stored as lists.
+func modifyList(old interface{}, values []interface{}, how int) (interface{}, error) {
+	var list []interface{}
+	if old != nil {
+		l, ok := old.([]interface{})
+		if !ok {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "value %v being modified is not a list", old)
+		}
+		list = append(list, l...)
+	}
+	// A removal from an absent field leaves it absent.
+	if old == nil && how == removeAll {
+		return nil, nil
+	}
+	for _, v := range values {
+		ev, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		switch how {
+		case appendAll:
+			list = append(list, ev)
+		case addMissing:
+			if !listContains(list, ev) {
+				list = append(list, ev)
+			}
+		case removeAll:
+			var kept []interface{}
+			for _, x := range list {
+				if !listContains([]interface{}{x}, ev) {
+					kept = append(kept, x)
+				}
+			}
+			list = kept
+		}
+	}
+	if list == nil {
+		list = []interface{}{}
+	}
+	return list, nil
+}
+
+// listContains reports whether the encoded list contains the encoded value v.
+func listContains(list []interface{}, v interface{}) bool {
+	for _, x := range list {
+		// compare treats a slice as the values of an "in" filter.
+		if reflect.ValueOf(v).Kind() == reflect.Slice || reflect.ValueOf(x).Kind() == reflect.Slice {
+			if reflect.DeepEqual(x, v) {
+				return true
+			}
+			continue
+		}
+		if c, ok := compare(x, v); ok && c == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Must be called with the lock held.
 func (c *collection) changeRevision(doc storedDoc) {
 	c.curRevision++
@@ -96,6 +96,52 @@ func TestUpdateEncodesValues(t *testing.T) {
 	}
 }
 
+func TestUpdateLists(t *testing.T) {
+	ctx := context.Background()
+	dc, err := newCollection(drivertest.KeyField, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := docstore.NewCollection(dc)
+	defer coll.Close()
+	doc := docmap{drivertest.KeyField: "testUpdateLists", "tags": []interface{}{"a", "b"}, dc.RevisionField(): nil}
+	if err := coll.Put(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		mods docstore.Mods
+		want docmap
+	}{
+		{
+			docstore.Mods{"tags": docstore.ListAppend("b", "c"), "new": docstore.ListAppend(1)},
+			docmap{"tags": []interface{}{"a", "b", "b", "c"}, "new": []interface{}{int64(1)}},
+		},
+		{
+			docstore.Mods{"tags": docstore.SetAdd("a", "d", "d"), "new": docstore.SetAdd(int64(1), 2)},
+			docmap{"tags": []interface{}{"a", "b", "b", "c", "d"}, "new": []interface{}{int64(1), int64(2)}},
+		},
+		{
+			docstore.Mods{"tags": docstore.SetRemove("b", "x"), "absent": docstore.SetRemove("a")},
+			docmap{"tags": []interface{}{"a", "c", "d"}, "new": []interface{}{int64(1), int64(2)}},
+		},
+	} {
+		if err := coll.Update(ctx, doc, test.mods); err != nil {
+			t.Fatal(err)
+		}
+		got := docmap{drivertest.KeyField: doc[drivertest.KeyField]}
+		if err := coll.Get(ctx, got, "tags", "new", "absent"); err != nil {
+			t.Fatal(err)
+		}
+		test.want[drivertest.KeyField] = doc[drivertest.KeyField]
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%v: diff (-want +got):\n%s", test.mods, diff)
+		}
+	}
+	if err := coll.Update(ctx, doc, docstore.Mods{drivertest.KeyField: docstore.SetAdd("x")}); err == nil {
+		t.Error("SetAdd to a string: got nil, want error")
+	}
+}
+
 func TestUpdateAtomic(t *testing.T) {
 	// Check that update is atomic.
 	ctx := context.Background()
@@ -186,6 +186,8 @@ func (d decoder) AsInterface() (interface{}, error) {
 
 func toGoValue(v interface{}) (interface{}, error) {
 	switch v := v.(type) {
+	case int32:
+		return int64(v), nil
 	case primitive.A:
 		r := make([]interface{}, len(v))
 		for i, e := range v {
@@ -210,6 +212,16 @@ func toGoValue(v interface{}) (interface{}, error) {
 			r[k] = d
 		}
 		return r, nil
+	case primitive.D:
+		r := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			d, err := toGoValue(e.Value)
+			if err != nil {
+				return nil, err
+			}
+			r[e.Key] = d
+		}
+		return r, nil
 	default:
 		return v, nil
 	}
@@ -52,6 +52,9 @@
 // struct field names; other docstore drivers do not. This means that you have to choose
 // between interoperating with the MongoDB driver and interoperating with other docstore drivers.
 // See Options.LowercaseFields for more information.
+//
+// docstore.ListAppend, docstore.SetAdd and docstore.SetRemove become the
+// $push, $addToSet and $pullAll update operators on array fields.
 package mongodocstore // import "gocloud.dev/docstore/mongodocstore"
 
 // MongoDB reference manual: https://docs.mongodb.com/manual
@@ -70,10 +73,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
 	"gocloud.dev/docstore"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/telemetry"
 )
 
 // Dial returns a new mongoDB client that is connected to the server URI.
@@ -388,21 +393,43 @@ func (c *collection) prepareUpdate(a *driver.Action) (filter bson.D, updateDoc m
 
 func (c *collection) newUpdateDoc(mods []driver.Mod, writeRevision bool) (map[string]bson.D, string, error) {
 	var (
-		sets   bson.D
-		unsets bson.D
-		incs   bson.D
+		sets    bson.D
+		unsets  bson.D
+		incs    bson.D
+		pushes  bson.D
+		adds    bson.D
+		removes bson.D
 	)
 	for _, m := range mods {
 		key := c.toMongoFieldPath(m.FieldPath)
-		if m.Value == nil {
+		switch v := m.Value.(type) {
+		case nil:
 			unsets = append(unsets, bson.E{Key: key, Value: ""})
-		} else if inc, ok := m.Value.(driver.IncOp); ok {
-			val, err := encodeValue(inc.Amount)
+		case driver.IncOp:
+			val, err := encodeValue(v.Amount)
 			if err != nil {
 				return nil, "", err
 			}
 			incs = append(incs, bson.E{Key: key, Value: val})
-		} else {
+		case driver.ListAppendOp:
+			val, err := encodeValue(v.Values)
+			if err != nil {
+				return nil, "", err
+			}
+			pushes = append(pushes, bson.E{Key: key, Value: bson.D{{Key: "$each", Value: val}}})
+		case driver.SetAddOp:
+			val, err := encodeValue(v.Values)
+			if err != nil {
+				return nil, "", err
+			}
+			adds = append(adds, bson.E{Key: key, Value: bson.D{{Key: "$each", Value: val}}})
+		case driver.SetRemoveOp:
+			val, err := encodeValue(v.Values)
+			if err != nil {
+				return nil, "", err
+			}
+			removes = append(removes, bson.E{Key: key, Value: val})
+		default:
 			val, err := encodeValue(m.Value)
 			if err != nil {
 				return nil, "", err
@@ -425,6 +452,15 @@ func (c *collection) newUpdateDoc(mods []driver.Mod, writeRevision bool) (map[st
 	if len(incs) > 0 {
 		updateDoc["$inc"] = incs
 	}
+	if len(pushes) > 0 {
+		updateDoc["$push"] = pushes
+	}
+	if len(adds) > 0 {
+		updateDoc["$addToSet"] = adds
+	}
+	if len(removes) > 0 {
+		updateDoc["$pullAll"] = removes
+	}
 	return updateDoc, rev, nil
 }
 
@@ -650,6 +686,15 @@ func (c *collection) BytesToRevision(b []byte) (interface{}, error) {
 	return string(b), nil
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (c *collection) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.DBSystemKey.String("mongodb"),
+		telemetry.DBCollectionNameKey.String(c.coll.Name()),
+		telemetry.DBNamespaceKey.String(c.coll.Database().Name()),
+	}
+}
+
 // As implements driver.As.
 func (c *collection) As(i interface{}) bool {
 	p, ok := i.(**mongo.Collection)
@@ -16,10 +16,12 @@ package docstore
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
 	"reflect"
 	"time"
 
+	"gocloud.dev/callopt"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/internal/gcerr"
 )
@@ -39,6 +41,7 @@ func (c *Collection) Query() *Query {
 // Where expresses a condition on the query.
 // Valid ops are: "=", ">", "<", ">=", "<=, "in", "not-in".
 // Valid values are strings, integers, floating-point numbers, time.Time and boolean (only for "=", "in" and "not-in") values.
+// A ValueMarshaler is replaced by the value that it is stored as, so it is valid if that value is.
 func (q *Query) Where(fp FieldPath, op string, value interface{}) *Query {
 	if q.err != nil {
 		return q
@@ -52,6 +55,10 @@ func (q *Query) Where(fp FieldPath, op string, value interface{}) *Query {
 	if !ok {
 		return q.invalidf("invalid filter operator: %q. Use one of: =, >, <, >=, <=, in, not-in", op)
 	}
+	value, err = marshalFilterValue(value)
+	if err != nil {
+		return q.invalidf("invalid filter value: %v", err)
+	}
 	if !validator(value) {
 		return q.invalidf("invalid filter value: %v", value)
 	}
@@ -106,6 +113,27 @@ func validFilterValue(v interface{}) bool {
 	}
 }
 
+// marshalFilterValue replaces a ValueMarshaler, or the ValueMarshaler
+// elements of a slice, with the values that they are stored as.
+func marshalFilterValue(v interface{}) (interface{}, error) {
+	if _, ok := v.(ValueMarshaler); ok {
+		return driver.MarshalValue(v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeOf((*ValueMarshaler)(nil)).Elem()) {
+		return v, nil
+	}
+	vs := make([]interface{}, rv.Len())
+	for i := range vs {
+		x, err := driver.MarshalValue(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = x
+	}
+	return vs, nil
+}
+
 func validFilterSlice(v interface{}) bool {
 	if v == nil || reflect.TypeOf(v).Kind() != reflect.Slice {
 		return false
@@ -187,6 +215,28 @@ func (q *Query) OrderBy(field, direction string) *Query {
 	return q
 }
 
+// StartFrom makes the query resume from a position saved by
+// DocumentIterator.PaginationToken: Get returns the documents after those that
+// the earlier iterator had returned. The query must be the same as the one
+// whose iterator returned the token, apart from its Offset and Limit.
+//
+// Not all drivers support pagination tokens; with those that do not, Get fails
+// with code Unimplemented.
+func (q *Query) StartFrom(token string) *Query {
+	if q.err != nil {
+		return q
+	}
+	if q.dq.PaginationToken != nil {
+		return q.invalidf("a query can have at most one StartFrom")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) == 0 {
+		return q.invalidf("StartFrom: invalid pagination token %q", token)
+	}
+	q.dq.PaginationToken = b
+	return q
+}
+
 // BeforeQuery takes a callback function that will be called before the Query is
 // executed to the underlying service's query functionality. The callback takes
 // a parameter, asFunc, that converts its argument to driver-specific types.
@@ -210,14 +260,25 @@ func (q *Query) get(ctx context.Context, oc bool, fps ...FieldPath) *DocumentIte
 	if err := q.initGet(fps); err != nil {
 		return &DocumentIterator{err: wrapError(dcoll, err)}
 	}
+	if pq, ok := dcoll.(driver.PaginatedQueryer); q.dq.PaginationToken != nil && (!ok || !pq.SupportsPaginationTokens()) {
+		err := gcerr.Newf(gcerr.Unimplemented, nil, "pagination tokens are not supported by this driver")
+		return &DocumentIterator{err: wrapError(dcoll, err)}
+	}
 
+	// The timeout in ctx, if any, covers the query until the iterator is
+	// stopped, since drivers may use ctx to fetch later results.
+	ctx, cancel := callopt.Context(ctx)
 	var err error
 	if oc {
 		ctx = q.coll.tracer.Start(ctx, "Query.Get")
 		defer func() { q.coll.tracer.End(ctx, err) }()
 	}
 	it, err := dcoll.RunGetQuery(ctx, q.dq)
-	return &DocumentIterator{iter: it, coll: q.coll, err: wrapError(dcoll, err)}
+	if err != nil {
+		cancel()
+		return &DocumentIterator{iter: it, coll: q.coll, err: wrapError(dcoll, err)}
+	}
+	return &DocumentIterator{iter: it, coll: q.coll, cancel: cancel}
 }
 
 func (q *Query) initGet(fps []FieldPath) error {
@@ -257,9 +318,11 @@ func (q *Query) invalidf(format string, args ...interface{}) *Query {
 //
 // Always call Stop on the iterator.
 type DocumentIterator struct {
-	iter driver.DocumentIterator
-	coll *Collection
-	err  error // already wrapped
+	iter    driver.DocumentIterator
+	coll    *Collection
+	err     error              // already wrapped
+	cancel  context.CancelFunc // cancels the context of the query; may be nil
+	stopped bool               // whether Stop has been called
 }
 
 // Next stores the next document in dst. It returns io.EOF if there are no more
@@ -285,6 +348,10 @@ func (it *DocumentIterator) Next(ctx context.Context, dst Document) error {
 // Stop stops the iterator. Calling Next on a stopped iterator will return io.EOF, or
 // the error that Next previously returned.
 func (it *DocumentIterator) Stop() {
+	it.stopped = true
+	if it.cancel != nil {
+		defer it.cancel()
+	}
 	if it.err != nil {
 		return
 	}
@@ -292,6 +359,35 @@ func (it *DocumentIterator) Stop() {
 	it.iter.Stop()
 }
 
+// PaginationToken returns a token that records the position of the iterator,
+// to be passed to Query.StartFrom to resume the query after the documents that
+// Next has returned, for instance in a later HTTP request. The token is an
+// opaque string that is safe to use in URLs. PaginationToken returns "" if Next
+// has returned io.EOF. It must be called before Stop.
+//
+// Not all drivers support pagination tokens; with those that do not,
+// PaginationToken fails with code Unimplemented.
+func (it *DocumentIterator) PaginationToken() (string, error) {
+	if it.stopped {
+		return "", gcerr.Newf(gcerr.FailedPrecondition, nil, "PaginationToken called after Stop")
+	}
+	if it.err == io.EOF {
+		return "", nil
+	}
+	if it.err != nil {
+		return "", it.err
+	}
+	pi, ok := it.iter.(driver.PaginatedIterator)
+	if !ok {
+		return "", gcerr.Newf(gcerr.Unimplemented, nil, "pagination tokens are not supported by this driver")
+	}
+	b, err := pi.PaginationToken()
+	if err != nil {
+		return "", wrapError(it.coll.driver, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // As converts i to driver-specific types.
 // See https://gocloud.dev/concepts/as/ for background information, the "As"
 // examples in this package for examples, and the driver package
@@ -16,9 +16,11 @@ package docstore
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"gocloud.dev/docstore/driver"
 	"gocloud.dev/gcerrors"
 )
@@ -47,6 +49,25 @@ func TestQueryValidFilter(t *testing.T) {
 	}
 }
 
+// uuid is stored as its string form.
+type uuid [2]byte
+
+func (u uuid) MarshalDocstoreValue() (interface{}, error) { return fmt.Sprintf("%x", u[:]), nil }
+
+func TestQueryMarshalFilter(t *testing.T) {
+	q := Query{dq: &driver.Query{}}
+	q.Where("a", "=", uuid{1, 2}).Where("b", "in", []uuid{{3, 4}, {5, 6}})
+	if q.err != nil {
+		t.Fatal(q.err)
+	}
+	if got, want := q.dq.Filters[0].Value, "0102"; got != want {
+		t.Errorf("=: got %v, want %v", got, want)
+	}
+	if got, want := q.dq.Filters[1].Value, []interface{}{"0304", "0506"}; !cmp.Equal(got, want) {
+		t.Errorf("in: got %v, want %v", got, want)
+	}
+}
+
 func TestInvalidQuery(t *testing.T) {
 	ctx := context.Background()
 	// We detect that these queries are invalid before they reach the driver.
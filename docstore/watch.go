@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docstore
+
+import (
+	"context"
+	"io"
+
+	"gocloud.dev/callopt"
+	"gocloud.dev/docstore/driver"
+	"gocloud.dev/internal/gcerr"
+)
+
+// ChangeKind is the kind of a change to a document.
+type ChangeKind int
+
+const (
+	// Created is the creation of a document.
+	Created = ChangeKind(driver.ChangeCreate)
+	// Updated is a modification of an existing document.
+	Updated = ChangeKind(driver.ChangeUpdate)
+	// Deleted is the deletion of a document.
+	Deleted = ChangeKind(driver.ChangeDelete)
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Created:
+		return "Created"
+	case Updated:
+		return "Updated"
+	case Deleted:
+		return "Deleted"
+	default:
+		return "ChangeKind(?)"
+	}
+}
+
+// WatchOptions controls Watch.
+type WatchOptions struct {
+	// If true, the iterator starts with the oldest change that the service
+	// still has. Otherwise it starts with the changes made after Watch.
+	FromOldest bool
+}
+
+// Watch returns an iterator over the changes to the documents of the
+// collection, as they happen. The timeout in ctx, if any, covers the watch
+// until the iterator is stopped.
+//
+// Changes to the same document arrive in the order they happened. Not all
+// drivers support Watch; with those that do not, Next fails with code
+// Unimplemented. See the driver package documentation for the setup that a
+// driver needs, if any.
+func (c *Collection) Watch(ctx context.Context, opts *WatchOptions) *ChangeIterator {
+	if err := c.checkClosed(); err != nil {
+		return &ChangeIterator{err: err}
+	}
+	w, ok := c.driver.(driver.Watcher)
+	if !ok {
+		return &ChangeIterator{err: gcerr.Newf(gcerr.Unimplemented, nil, "Watch is not supported by this driver")}
+	}
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	ctx, cancel := callopt.Context(ctx)
+	var err error
+	ctx = c.tracer.Start(ctx, "Collection.Watch")
+	defer func() { c.tracer.End(ctx, err) }()
+	it, err := w.Watch(ctx, &driver.WatchOptions{FromOldest: opts.FromOldest})
+	if err != nil {
+		cancel()
+		return &ChangeIterator{err: wrapError(c.driver, err)}
+	}
+	return &ChangeIterator{iter: it, coll: c, cancel: cancel}
+}
+
+// ChangeIterator iterates over the changes to the documents of a collection.
+//
+// Always call Stop on the iterator.
+type ChangeIterator struct {
+	iter   driver.ChangeIterator
+	coll   *Collection
+	err    error              // already wrapped
+	cancel context.CancelFunc // cancels the context of the watch; may be nil
+}
+
+// Next returns the next change, waiting for one if there is none yet. It
+// returns io.EOF if there will be no more changes.
+// Once Next returns an error, it will always return the same error.
+func (it *ChangeIterator) Next(ctx context.Context) (*Change, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if err := it.coll.checkClosed(); err != nil {
+		it.err = err
+		return nil, it.err
+	}
+	dc, err := it.iter.Next(ctx)
+	if err != nil {
+		it.err = wrapError(it.coll.driver, err)
+		return nil, it.err
+	}
+	return &Change{Kind: ChangeKind(dc.Kind), dc: dc, coll: it.coll}, nil
+}
+
+// Stop stops the iterator. Calling Next on a stopped iterator will return
+// io.EOF, or the error that Next previously returned.
+func (it *ChangeIterator) Stop() {
+	if it.cancel != nil {
+		defer it.cancel()
+	}
+	if it.err != nil {
+		return
+	}
+	it.err = io.EOF
+	it.iter.Stop()
+}
+
+// A Change is a change to a document.
+type Change struct {
+	Kind ChangeKind
+	dc   *driver.Change
+	coll *Collection
+}
+
+// Key sets the key fields of doc to those of the changed document.
+func (c *Change) Key(doc Document) error {
+	return c.decode(c.dc.DecodeKey, doc)
+}
+
+// Old decodes the document as it was before the change into doc. It fails
+// with code NotFound if the change has no such document: if the document was
+// created, or the driver does not record the old version.
+func (c *Change) Old(doc Document) error {
+	return c.decode(c.dc.DecodeOld, doc)
+}
+
+// New decodes the document as it is after the change into doc. It fails with
+// code NotFound if the change has no such document: if the document was
+// deleted, or the driver does not record the new version.
+func (c *Change) New(doc Document) error {
+	return c.decode(c.dc.DecodeNew, doc)
+}
+
+func (c *Change) decode(f func(driver.Document) error, doc Document) error {
+	if f == nil {
+		return gcerr.Newf(gcerr.NotFound, nil, "%s change has no such document", c.Kind)
+	}
+	ddoc, err := driver.NewDocument(doc)
+	if err != nil {
+		return wrapError(c.coll.driver, err)
+	}
+	return wrapError(c.coll.driver, f(ddoc))
+}
+
+// As converts i to driver-specific types.
+// See https://gocloud.dev/concepts/as/ for background information and the
+// driver package documentation for the specific types supported for that
+// driver.
+func (c *Change) As(i interface{}) bool {
+	if i == nil || c.dc.AsFunc == nil {
+		return false
+	}
+	return c.dc.AsFunc(i)
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcerrors
+
+import "gocloud.dev/internal/gcerr"
+
+// Details are structured information about an error, beyond its code, such
+// as the service's error code and request ID. Empty fields are unknown.
+type Details = gcerr.Details
+
+// DetailsOf returns the structured details of err. They come from details
+// attached by the Go CDK or a driver, such as the key of a blob, and from
+// the errors of AWS APIs, Google HTTP APIs and gRPC services that err wraps.
+// It reports whether any details were found.
+func DetailsOf(err error) (Details, bool) {
+	return gcerr.ErrorDetails(err)
+}
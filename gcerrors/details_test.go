@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcerrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gocloud.dev/internal/gcerr"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// awsRequestError mimics the errors of the AWS SDK, with a request ID.
+type awsRequestError struct{ awsError }
+
+func (awsRequestError) ServiceRequestID() string { return "req-123" }
+
+func TestDetailsOf(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "bad").WithDetails(
+		&errdetails.ErrorInfo{Reason: "FIELD_INVALID"},
+		&errdetails.RequestInfo{RequestId: "req-456"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name"}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := func(err error) error { return gcerr.New(Unknown, err, 1, "wrapped") }
+
+	for _, test := range []struct {
+		name   string
+		in     error
+		want   Details
+		wantOK bool
+	}{
+		{"Nil", nil, Details{}, false},
+		{"Plain", errors.New("plain"), Details{}, false},
+		{
+			"Attached",
+			wrap(gcerr.WithDetails(errors.New("x"), Details{Key: "k", ProviderCode: "outer"})),
+			Details{Key: "k", ProviderCode: "outer"},
+			true,
+		},
+		{
+			"AttachedTakesPrecedence",
+			gcerr.WithDetails(awsRequestError{awsError{"NoSuchKey", 404}}, Details{ProviderCode: "Custom", Field: "f"}),
+			Details{ProviderCode: "Custom", RequestID: "req-123", Field: "f"},
+			true,
+		},
+		{
+			"AWS",
+			wrap(fmt.Errorf("op: %w", awsRequestError{awsError{"ThrottlingException", 400}})),
+			Details{ProviderCode: "ThrottlingException", RequestID: "req-123"},
+			true,
+		},
+		{
+			"GoogleHTTP",
+			wrap(&googleapi.Error{
+				Code:   429,
+				Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}},
+				Header: http.Header{"Retry-After": {"7"}},
+			}),
+			Details{ProviderCode: "rateLimitExceeded", RetryAfter: 7 * time.Second},
+			true,
+		},
+		{
+			"GRPC",
+			wrap(st.Err()),
+			Details{ProviderCode: "FIELD_INVALID", RequestID: "req-456", RetryAfter: 3 * time.Second, Field: "name"},
+			true,
+		},
+		{
+			"GRPCWithoutDetails",
+			wrap(status.Error(codes.Unavailable, "down")),
+			Details{ProviderCode: "Unavailable"},
+			true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := DetailsOf(test.in)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("got %+v, %t; want %+v, %t", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
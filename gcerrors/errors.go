@@ -13,7 +13,8 @@
 // limitations under the License.
 
 // Package gcerrors provides support for getting error codes from
-// errors returned by Go CDK APIs.
+// errors returned by Go CDK APIs, and for retrying operations whose errors
+// are transient.
 package gcerrors
 
 import (
@@ -0,0 +1,182 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcerrors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	"gocloud.dev/internal/retry"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// throttlingCodes are the error codes that AWS services return when
+// requests are throttled.
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// IsRetryable reports whether err is likely to be transient, so that the
+// operation that returned it may succeed if it is tried again.
+//
+// It returns true for errors with the ResourceExhausted code, and for errors
+// that wrap a transient error from the underlying service: gRPC's
+// Unavailable, ResourceExhausted and Aborted codes; HTTP 408, 429, 500, 502,
+// 503 and 504 responses from Google and AWS APIs; AWS throttling error codes;
+// network timeouts; and reset connections. It returns false for nil, for
+// context errors, and for all other errors.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	switch Code(err) {
+	case ResourceExhausted:
+		return true
+	case NotFound, AlreadyExists, InvalidArgument, Unimplemented, FailedPrecondition, PermissionDenied:
+		return false
+	}
+	return isTransient(err)
+}
+
+// isTransient reports whether err wraps a transient error from a service
+// or the network.
+func isTransient(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return isTransientHTTPStatus(gerr.Code)
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		if s := grpcErr.GRPCStatus(); s != nil {
+			switch s.Code() {
+			case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+				return true
+			}
+			return false
+		}
+	}
+	// AWS errors.
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) && throttlingCodes[coded.ErrorCode()] {
+		return true
+	}
+	var retryable interface{ RetryableError() bool }
+	if errors.As(err, &retryable) {
+		return retryable.RetryableError()
+	}
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return isTransientHTTPStatus(httpErr.HTTPStatusCode())
+	}
+	// Network errors.
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isTransientHTTPStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// A RetryPolicy controls how Retry retries an operation. A nil *RetryPolicy,
+// and zero fields, use the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, including the first.
+	// If zero, it is 5; if negative, calls are limited only by the context.
+	MaxAttempts int
+
+	// InitialBackoff is the longest pause before the first retry. Each
+	// pause is chosen at random up to its limit, which grows by Multiplier
+	// up to MaxBackoff. The defaults are 100ms, 10s and 2.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// IsRetryable reports whether to retry after an error. If nil,
+	// the IsRetryable function of this package is used.
+	IsRetryable func(error) bool
+}
+
+// Retry calls f until it returns nil, returns an error that is not
+// retryable, or has been called policy.MaxAttempts times, pausing with
+// exponential backoff between calls. It returns the last error from f.
+//
+// If ctx is done while Retry is pausing, Retry returns an error that
+// includes ctx.Err() and the last error from f, and whose Code is
+// Canceled or DeadlineExceeded.
+func Retry(ctx context.Context, policy *RetryPolicy, f func(context.Context) error) error {
+	p := policy.WithDefaults()
+	bo := gax.Backoff{Initial: p.InitialBackoff, Max: p.MaxBackoff, Multiplier: p.Multiplier}
+	attempts := 0
+	isRetryable := func(err error) bool {
+		return (p.MaxAttempts < 0 || attempts < p.MaxAttempts) && p.IsRetryable(err)
+	}
+	return retry.Call(ctx, bo, isRetryable, func() error {
+		attempts++
+		return f(ctx)
+	})
+}
+
+// WithDefaults returns a copy of p with the defaults in place of zero
+// fields, for drivers that map the policy to their own retry options.
+// A nil p gives the default policy.
+func (p *RetryPolicy) WithDefaults() RetryPolicy {
+	var d RetryPolicy
+	if p != nil {
+		d = *p
+	}
+	if d.MaxAttempts == 0 {
+		d.MaxAttempts = 5
+	}
+	if d.InitialBackoff <= 0 {
+		d.InitialBackoff = 100 * time.Millisecond
+	}
+	if d.MaxBackoff <= 0 {
+		d.MaxBackoff = 10 * time.Second
+	}
+	if d.Multiplier < 1 {
+		d.Multiplier = 2
+	}
+	if d.IsRetryable == nil {
+		d.IsRetryable = IsRetryable
+	}
+	return d
+}
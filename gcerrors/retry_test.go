@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"gocloud.dev/internal/gcerr"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// awsError mimics the API and HTTP response errors of the AWS SDK.
+type awsError struct {
+	code   string
+	status int
+}
+
+func (e awsError) Error() string       { return e.code }
+func (e awsError) ErrorCode() string   { return e.code }
+func (e awsError) HTTPStatusCode() int { return e.status }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	wrap := func(c ErrorCode, err error) error { return gcerr.New(c, err, 1, "wrapped") }
+	for _, test := range []struct {
+		in   error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{context.Canceled, false},
+		{fmt.Errorf("get: %w", context.DeadlineExceeded), false},
+		{gcerr.New(ResourceExhausted, nil, 1, "quota"), true},
+		{wrap(NotFound, status.Error(codes.Unavailable, "")), false},
+		{wrap(Unknown, status.Error(codes.Unavailable, "")), true},
+		{wrap(Unknown, status.Error(codes.Aborted, "")), true},
+		{wrap(Internal, status.Error(codes.Internal, "")), false},
+		{wrap(Unknown, &googleapi.Error{Code: 503}), true},
+		{wrap(Unknown, &googleapi.Error{Code: 400}), false},
+		{wrap(Unknown, awsError{"ThrottlingException", 400}), true},
+		{wrap(Unknown, awsError{"InternalError", 500}), true},
+		{wrap(Unknown, awsError{"ValidationException", 400}), false},
+		{wrap(Unknown, timeoutError{}), true},
+		{wrap(Unknown, fmt.Errorf("read: %w", syscall.ECONNRESET)), true},
+		{wrap(Unknown, io.ErrUnexpectedEOF), true},
+	} {
+		if got := IsRetryable(test.in); got != test.want {
+			t.Errorf("IsRetryable(%v) = %t, want %t", test.in, got, test.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	transient := gcerr.New(ResourceExhausted, nil, 1, "busy")
+	policy := &RetryPolicy{InitialBackoff: time.Millisecond}
+
+	// Retries until success.
+	calls := 0
+	err := Retry(ctx, policy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("got %v after %d calls, want success after 3", err, calls)
+	}
+
+	// Stops at MaxAttempts.
+	calls = 0
+	err = Retry(ctx, policy, func(context.Context) error {
+		calls++
+		return transient
+	})
+	if err != transient || calls != 5 {
+		t.Errorf("got %v after %d calls, want the transient error after 5", err, calls)
+	}
+
+	// Does not retry permanent errors.
+	calls = 0
+	permanent := errors.New("permanent")
+	err = Retry(ctx, policy, func(context.Context) error {
+		calls++
+		return permanent
+	})
+	if err != permanent || calls != 1 {
+		t.Errorf("got %v after %d calls, want the permanent error after 1", err, calls)
+	}
+
+	// Uses the policy's IsRetryable.
+	calls = 0
+	custom := &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, IsRetryable: func(error) bool { return true }}
+	Retry(ctx, custom, func(context.Context) error {
+		calls++
+		return permanent
+	})
+	if calls != 2 {
+		t.Errorf("custom IsRetryable: got %d calls, want 2", calls)
+	}
+
+	// Stops when the context is done.
+	ctx, cancel := context.WithCancel(ctx)
+	err = Retry(ctx, &RetryPolicy{MaxAttempts: -1, InitialBackoff: time.Millisecond}, func(context.Context) error {
+		cancel()
+		return transient
+	})
+	if Code(err) != Canceled {
+		t.Errorf("canceled: got %v with code %v, want Canceled", err, Code(err))
+	}
+}
@@ -17,6 +17,12 @@
 package cloudsql // import "gocloud.dev/gcp/cloudsql"
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/cloudsqlconn"
 	"github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/certs"
 	"github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/proxy"
 	"github.com/google/wire"
@@ -41,3 +47,68 @@ func NewCertSource(c *gcp.HTTPClient) *certs.RemoteCertSource {
 func NewCertSourceWithIAM(c *gcp.HTTPClient, t oauth2.TokenSource) *certs.RemoteCertSource {
 	return certs.NewCertSourceOpts(&c.Client, certs.RemoteOpts{EnableIAMLogin: true, TokenSource: t})
 }
+
+// DialOptions are the options of connections to a Cloud SQL instance
+// through the Cloud SQL Go connector, set by URL parameters.
+type DialOptions struct {
+	// IAMAuthN enables automatic IAM database authentication: the
+	// connector includes an access token of its credentials in the
+	// ephemeral certificate of each connection, so database users that are
+	// IAM principals connect without a password.
+	IAMAuthN bool
+
+	// IPType is the type of instance IP address to connect to: "PUBLIC",
+	// "PRIVATE" or "PSC" for a Private Service Connect endpoint. If empty,
+	// the public address is used.
+	IPType string
+}
+
+// ConnectorOptions returns the cloudsqlconn dial options for o.
+func (o *DialOptions) ConnectorOptions() []cloudsqlconn.DialOption {
+	opts := []cloudsqlconn.DialOption{cloudsqlconn.WithDialIAMAuthN(o.IAMAuthN)}
+	switch o.IPType {
+	case "PRIVATE":
+		opts = append(opts, cloudsqlconn.WithPrivateIP())
+	case "PSC":
+		opts = append(opts, cloudsqlconn.WithPSC())
+	default:
+		opts = append(opts, cloudsqlconn.WithPublicIP())
+	}
+	return opts
+}
+
+// DialOptionsFromURLParams returns the options set by the URL parameters in
+// q, or nil if q sets none, and the parameters in q that it did not use.
+// The parameters are:
+//   - iam_authn: "true" to enable automatic IAM database authentication.
+//   - ip_type: the type of instance IP address to connect to: "public",
+//     "private" or "psc".
+func DialOptionsFromURLParams(q url.Values) (*DialOptions, url.Values, error) {
+	var opts *DialOptions
+	rest := url.Values{}
+	for param, values := range q {
+		switch param {
+		case "iam_authn":
+			if opts == nil {
+				opts = &DialOptions{}
+			}
+			var err error
+			if opts.IAMAuthN, err = strconv.ParseBool(values[0]); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for iam_authn: %w", err)
+			}
+		case "ip_type":
+			if opts == nil {
+				opts = &DialOptions{}
+			}
+			switch t := strings.ToUpper(values[0]); t {
+			case "PUBLIC", "PRIVATE", "PSC":
+				opts.IPType = t
+			default:
+				return nil, nil, fmt.Errorf("invalid value for ip_type: %q; want public, private or psc", values[0])
+			}
+		default:
+			rest[param] = values
+		}
+	}
+	return opts, rest, nil
+}
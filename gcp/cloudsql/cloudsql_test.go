@@ -0,0 +1,59 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudsql
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDialOptionsFromURLParams(t *testing.T) {
+	tests := []struct {
+		query    string
+		want     *DialOptions
+		wantRest string
+		wantErr  bool
+	}{
+		{query: "sslmode=disable", wantRest: "sslmode=disable"},
+		{query: "iam_authn=true", want: &DialOptions{IAMAuthN: true}},
+		{
+			query:    "ip_type=Private&parseTime=true",
+			want:     &DialOptions{IPType: "PRIVATE"},
+			wantRest: "parseTime=true",
+		},
+		{query: "ip_type=psc&iam_authn=1", want: &DialOptions{IAMAuthN: true, IPType: "PSC"}},
+		{query: "iam_authn=maybe", wantErr: true},
+		{query: "ip_type=private,public", wantErr: true},
+	}
+	for _, test := range tests {
+		q, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, rest, err := DialOptionsFromURLParams(q)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error %t", test.query, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(got, test.want); diff != "" {
+			t.Errorf("%q: options diff (-got +want):\n%s", test.query, diff)
+		}
+		if err == nil && rest.Encode() != test.wantRest {
+			t.Errorf("%q: got remaining params %q, want %q", test.query, rest.Encode(), test.wantRest)
+		}
+	}
+}
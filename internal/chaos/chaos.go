@@ -0,0 +1,128 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaos injects the faults of the chaosblob, chaosdocstore and
+// chaospubsub wrappers.
+package chaos // import "gocloud.dev/internal/chaos"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+)
+
+// Faults are the faults common to all wrappers.
+type Faults struct {
+	Seed          int64
+	Latency       time.Duration
+	LatencyJitter time.Duration
+	ErrorRate     float64
+	ErrorCode     gcerrors.ErrorCode
+}
+
+// An Injector decides which faults to inject. Its decisions come from a
+// pseudo-random source seeded with Faults.Seed, so the same sequence of
+// calls sees the same faults. It is safe for concurrent use.
+type Injector struct {
+	f Faults
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New returns an Injector for f. A zero f.ErrorCode means gcerrors.Internal.
+func New(f Faults) *Injector {
+	if f.ErrorCode == gcerrors.OK {
+		f.ErrorCode = gcerrors.Internal
+	}
+	return &Injector{f: f, rand: rand.New(rand.NewSource(f.Seed))}
+}
+
+// Chance reports true with probability rate.
+func (in *Injector) Chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.rand.Float64() < rate
+}
+
+// Intn returns a pseudo-random number in [0, n).
+func (in *Injector) Intn(n int) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.rand.Intn(n)
+}
+
+// Before is called before the operation op reaches the wrapped resource.
+// It waits for the injected latency, then returns an injected error with
+// probability Faults.ErrorRate. It returns ctx.Err() if ctx is done while
+// waiting.
+func (in *Injector) Before(ctx context.Context, op string) error {
+	if d := in.latency(); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if in.Chance(in.f.ErrorRate) {
+		return in.Fault(op)
+	}
+	return nil
+}
+
+func (in *Injector) latency() time.Duration {
+	d := in.f.Latency
+	if in.f.LatencyJitter > 0 {
+		in.mu.Lock()
+		d += time.Duration(in.rand.Int63n(int64(in.f.LatencyJitter)))
+		in.mu.Unlock()
+	}
+	return d
+}
+
+// Fault returns an injected error for the operation op, with code
+// Faults.ErrorCode.
+func (in *Injector) Fault(op string) error {
+	return gcerr.New(in.f.ErrorCode, &faultError{op: op}, 2, "")
+}
+
+// faultError is the cause of injected errors. It reports itself as
+// retryable, so gcerrors.IsRetryable treats injected errors as transient
+// unless their code says otherwise.
+type faultError struct {
+	op string
+}
+
+func (e *faultError) Error() string {
+	return fmt.Sprintf("chaos: injected fault in %s", e.op)
+}
+
+func (e *faultError) RetryableError() bool { return true }
+
+// IsFault reports whether err is, or wraps, an injected error.
+func IsFault(err error) bool {
+	var f *faultError
+	return errors.As(err, &f)
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcerr
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Details are structured information about an error, beyond its code.
+// Empty fields are unknown.
+type Details struct {
+	// ProviderCode is the service's code for the error, such as
+	// "NoSuchKey" or "ThrottlingException".
+	ProviderCode string
+	// RequestID identifies the failed request to the service, for
+	// reporting problems to the provider.
+	RequestID string
+	// RetryAfter is how long the service asked the client to wait before
+	// retrying, typically because it throttled the request.
+	RetryAfter time.Duration
+	// Key is the key of the blob, document or other item that the error is
+	// about.
+	Key string
+	// Field is the document field or request parameter that the error is
+	// about.
+	Field string
+}
+
+// merge sets the empty fields of d from other.
+func (d *Details) merge(other Details) {
+	if d.ProviderCode == "" {
+		d.ProviderCode = other.ProviderCode
+	}
+	if d.RequestID == "" {
+		d.RequestID = other.RequestID
+	}
+	if d.RetryAfter == 0 {
+		d.RetryAfter = other.RetryAfter
+	}
+	if d.Key == "" {
+		d.Key = other.Key
+	}
+	if d.Field == "" {
+		d.Field = other.Field
+	}
+}
+
+// WithDetails returns an error that wraps err and carries d, or nil if err
+// is nil. Drivers use it to attach details to the errors they return; their
+// ErrorCode and ErrorAs methods receive the wrapping error, so they should
+// inspect it with errors.As rather than type assertions.
+func WithDetails(err error, d Details) error {
+	if err == nil {
+		return nil
+	}
+	return &detailsError{err: err, details: d}
+}
+
+type detailsError struct {
+	err     error
+	details Details
+}
+
+func (e *detailsError) Error() string { return e.err.Error() }
+
+func (e *detailsError) Unwrap() error { return e.err }
+
+// AttachedDetails returns the details attached with WithDetails to err or
+// the errors it wraps. When several are attached, each field comes from the
+// outermost error that sets it. It reports whether any were attached.
+func AttachedDetails(err error) (Details, bool) {
+	var d Details
+	found := false
+	for err != nil {
+		if de, ok := err.(*detailsError); ok {
+			d.merge(de.details)
+			found = true
+		}
+		err = errors.Unwrap(err)
+	}
+	return d, found
+}
+
+// ErrorDetails returns the details attached to err, as AttachedDetails
+// does, with the empty fields set from the errors of AWS APIs, Google HTTP
+// APIs and gRPC services that err wraps. It reports whether any details were
+// found.
+func ErrorDetails(err error) (Details, bool) {
+	d, found := AttachedDetails(err)
+	if err == nil {
+		return d, found
+	}
+	var inferred Details
+	// AWS errors.
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) {
+		inferred.ProviderCode = coded.ErrorCode()
+	}
+	var withRequestID interface{ ServiceRequestID() string }
+	if errors.As(err, &withRequestID) {
+		inferred.RequestID = withRequestID.ServiceRequestID()
+	}
+	// Google HTTP API errors.
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		if len(gerr.Errors) > 0 {
+			inferred.ProviderCode = gerr.Errors[0].Reason
+		}
+		if secs, err := strconv.Atoi(gerr.Header.Get("Retry-After")); err == nil && secs > 0 {
+			inferred.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+	// gRPC errors, with the standard error details of Google APIs.
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		if s := grpcErr.GRPCStatus(); s != nil {
+			grpcDetails(s, &inferred)
+		}
+	}
+	d.merge(inferred)
+	return d, found || d != Details{}
+}
+
+// grpcDetails sets the fields of d from the status s.
+func grpcDetails(s *status.Status, d *Details) {
+	d.ProviderCode = s.Code().String()
+	for _, detail := range s.Details() {
+		switch v := detail.(type) {
+		case *errdetails.ErrorInfo:
+			d.ProviderCode = v.GetReason()
+		case *errdetails.RequestInfo:
+			d.RequestID = v.GetRequestId()
+		case *errdetails.RetryInfo:
+			d.RetryAfter = v.GetRetryDelay().AsDuration()
+		case *errdetails.BadRequest:
+			if fv := v.GetFieldViolations(); len(fv) > 0 {
+				d.Field = fv[0].GetField()
+			}
+		}
+	}
+}
@@ -201,5 +201,13 @@ func ErrorAs(err error, target interface{}, errorAs func(error, interface{}) boo
 	if e, ok := err.(*Error); ok {
 		err = e.Unwrap()
 	}
+	// Drivers' errorAs functions expect their own errors, without details.
+	for {
+		de, ok := err.(*detailsError)
+		if !ok {
+			break
+		}
+		err = de.err
+	}
 	return errorAs(err, target)
 }
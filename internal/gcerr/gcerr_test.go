@@ -116,3 +116,21 @@ func TestError(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorAsDetails(t *testing.T) {
+	// ErrorAs looks through details attached by WithDetails to the error
+	// that the driver wrapped.
+	inner := errors.New("driver")
+	err := New(NotFound, WithDetails(inner, Details{Key: "k"}), 1, "")
+	var got error
+	ok := ErrorAs(err, &got, func(e error, i interface{}) bool {
+		if e != inner {
+			return false
+		}
+		*i.(*error) = e
+		return true
+	})
+	if !ok || got != inner {
+		t.Errorf("got %v, %t; want the driver error", got, ok)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localdb runs databases for local development and tests, for the
+// localmysql and localpostgres URL openers. Servers run in Docker
+// containers, started with the docker command; SQLite databases use a
+// database/sql driver that the application links in.
+package localdb // import "gocloud.dev/internal/localdb"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dockerCommand is the command that runs containers. Tests replace it.
+var dockerCommand = "docker"
+
+// DefaultReadyTimeout is how long Wait waits for a new server by default.
+// Database images initialize their data directory on first start, which
+// takes a while.
+const DefaultReadyTimeout = 2 * time.Minute
+
+// Params are the URL parameters of the local openers.
+type Params struct {
+	// Image is the Docker image of the server, from the "image" parameter.
+	Image string
+	// SQLite is the path of a SQLite database file, from the "sqlite"
+	// parameter. If set, no server is started.
+	SQLite string
+	// ReadyTimeout is how long to wait for a new server to accept
+	// connections, from the "ready_timeout" parameter.
+	ReadyTimeout time.Duration
+}
+
+// ParamsFromURL returns the parameters in q for the local openers, with
+// defaultImage as the image if q does not set one. It also returns the
+// parameters in q that it did not use.
+func ParamsFromURL(q url.Values, defaultImage string) (*Params, url.Values, error) {
+	p := &Params{Image: defaultImage, ReadyTimeout: DefaultReadyTimeout}
+	rest := url.Values{}
+	for param, values := range q {
+		switch param {
+		case "image":
+			p.Image = values[0]
+		case "sqlite":
+			p.SQLite = values[0]
+		case "ready_timeout":
+			d, err := time.ParseDuration(values[0])
+			if err != nil || d <= 0 {
+				return nil, nil, fmt.Errorf("invalid value for ready_timeout: %q", values[0])
+			}
+			p.ReadyTimeout = d
+		default:
+			rest[param] = values
+		}
+	}
+	return p, rest, nil
+}
+
+// A Container is a database server running in a Docker container.
+type Container struct {
+	id string
+	// Addr is the "host:port" address of the server on localhost.
+	Addr string
+}
+
+// Start runs image in a new container with the environment variables in
+// env, and publishes the container's TCP port on a free port of localhost.
+// The container is removed when it stops.
+func Start(ctx context.Context, image string, port int, env map[string]string) (*Container, error) {
+	args := []string{"run", "--detach", "--rm", "--publish", "127.0.0.1::" + strconv.Itoa(port)}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--env", k+"="+env[k])
+	}
+	args = append(args, image)
+	out, err := exec.CommandContext(ctx, dockerCommand, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("start %s container: %v", image, commandError(err))
+	}
+	c := &Container{id: strings.TrimSpace(string(out))}
+	out, err = exec.CommandContext(ctx, dockerCommand, "port", c.id, fmt.Sprintf("%d/tcp", port)).Output()
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("get port of %s container: %v", image, commandError(err))
+	}
+	// docker port prints a line for each address, such as
+	// "127.0.0.1:49153".
+	c.Addr = strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if c.Addr == "" {
+		c.Close()
+		return nil, fmt.Errorf("%s container does not publish port %d", image, port)
+	}
+	return c, nil
+}
+
+// Close removes the container.
+func (c *Container) Close() error {
+	if err := exec.Command(dockerCommand, "rm", "--force", c.id).Run(); err != nil {
+		return fmt.Errorf("remove container %s: %v", c.id, commandError(err))
+	}
+	return nil
+}
+
+// Wrap returns a connector that opens connections with dc, and removes c
+// when the database is closed.
+func (c *Container) Wrap(dc driver.Connector) driver.Connector {
+	return connector{dc, c}
+}
+
+type connector struct {
+	driver.Connector
+	container *Container
+}
+
+// Close is called by sql.DB.Close.
+func (c connector) Close() error {
+	return c.container.Close()
+}
+
+// Wait pings db until it succeeds, or timeout passes.
+func Wait(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server not ready after %v: %v", timeout, err)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// OpenSQLite opens the SQLite database file at path, with the first
+// registered database/sql driver named "sqlite" or "sqlite3".
+func OpenSQLite(path string) (*sql.DB, error) {
+	drivers := sql.Drivers()
+	for _, name := range []string{"sqlite", "sqlite3"} {
+		for _, d := range drivers {
+			if d == name {
+				return sql.Open(name, path)
+			}
+		}
+	}
+	return nil, errors.New("no SQLite driver is registered; link one in, such as modernc.org/sqlite or github.com/mattn/go-sqlite3")
+}
+
+// commandError adds the standard error of a failed command to err.
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localdb
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParamsFromURL(t *testing.T) {
+	q := url.Values{"image": {"mysql:5.7"}, "ready_timeout": {"30s"}, "parseTime": {"true"}}
+	p, rest, err := ParamsFromURL(q, "mysql:8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Image != "mysql:5.7" || p.ReadyTimeout != 30*time.Second || p.SQLite != "" {
+		t.Errorf("got params %+v, want the ones from the URL", p)
+	}
+	if rest.Encode() != "parseTime=true" {
+		t.Errorf("got remaining params %v, want only parseTime", rest)
+	}
+
+	p, _, err = ParamsFromURL(url.Values{}, "mysql:8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Image != "mysql:8.0" || p.ReadyTimeout != DefaultReadyTimeout {
+		t.Errorf("got params %+v, want the defaults", p)
+	}
+
+	if _, _, err := ParamsFromURL(url.Values{"ready_timeout": {"soon"}}, "mysql:8.0"); err == nil {
+		t.Error("invalid ready_timeout: got nil error")
+	}
+}
+
+// fakeDocker is a docker command that logs its arguments, prints a
+// container ID for "run" and an address for "port".
+const fakeDocker = `#!/bin/sh
+echo "$@" >> "$(dirname "$0")/log"
+case "$1" in
+run) echo 0123abcd ;;
+port) echo 127.0.0.1:49153; echo '[::1]:49153' ;;
+esac
+`
+
+func TestStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker command is a shell script")
+	}
+	dir := t.TempDir()
+	docker := filepath.Join(dir, "docker")
+	if err := os.WriteFile(docker, []byte(fakeDocker), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old string) { dockerCommand = old }(dockerCommand)
+	dockerCommand = docker
+
+	c, err := Start(context.Background(), "postgres:16", 5432, map[string]string{"POSTGRES_USER": "alice", "POSTGRES_DB": "mydb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr != "127.0.0.1:49153" {
+		t.Errorf("got address %q, want 127.0.0.1:49153", c.Addr)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	log, err := os.ReadFile(filepath.Join(dir, "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"run --detach --rm --publish 127.0.0.1::5432 --env POSTGRES_DB=mydb --env POSTGRES_USER=alice postgres:16",
+		"port 0123abcd 5432/tcp",
+		"rm --force 0123abcd",
+	}
+	if got := strings.Split(strings.TrimSpace(string(log)), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("docker was run with:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	dockerCommand = filepath.Join(dir, "missing")
+	if _, err := Start(context.Background(), "postgres:16", 5432, nil); err == nil {
+		t.Error("missing docker command: got nil error")
+	}
+}
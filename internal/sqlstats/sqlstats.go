@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlstats reports the connection pool statistics and health of
+// SQL databases opened by the Go CDK's database URL openers.
+package sqlstats // import "gocloud.dev/internal/sqlstats"
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gocloud.dev/server/health"
+	"gocloud.dev/server/health/sqlhealth"
+)
+
+// scope is the OpenTelemetry instrumentation scope of the metrics.
+const scope = "gocloud.dev/internal/sqlstats"
+
+// HealthCheckTimeout is how long the health check of a database waits for
+// a ping before reporting the database as unhealthy.
+const HealthCheckTimeout = 5 * time.Second
+
+// Instrument reports the connection pool statistics of db as metrics to mp,
+// and passes a health check of db to addHealthCheck. system is the database
+// system, such as "mysql" or "postgresql", and pool names the database, for
+// example by its instance or endpoint. Either mp or addHealthCheck may be
+// nil, and the corresponding reporting is skipped.
+//
+// The metrics follow the OpenTelemetry semantic conventions for database
+// client connection pools:
+//   - db.client.connections.usage: connections by state, "idle" or "used".
+//   - db.client.connections.max: the maximum number of open connections,
+//     or 0 if unlimited.
+//   - db.client.connections.waits: the number of times a caller waited for
+//     a connection.
+//   - db.client.connections.wait_time: the total time callers waited for a
+//     connection, in seconds.
+//
+// The metrics are reported for as long as mp is in use, including after db
+// is closed.
+func Instrument(db *sql.DB, system, pool string, mp metric.MeterProvider, addHealthCheck func(health.Checker)) error {
+	if mp != nil {
+		if err := registerMetrics(db, system, pool, mp.Meter(scope)); err != nil {
+			return err
+		}
+	}
+	if addHealthCheck != nil {
+		addHealthCheck(sqlhealth.NewPinger(db, HealthCheckTimeout))
+	}
+	return nil
+}
+
+func registerMetrics(db *sql.DB, system, pool string, m metric.Meter) error {
+	usage, err := m.Int64ObservableUpDownCounter("db.client.connections.usage",
+		metric.WithDescription("The number of connections that are currently in the state described by the state attribute."),
+		metric.WithUnit("{connection}"))
+	if err != nil {
+		return err
+	}
+	maxOpen, err := m.Int64ObservableUpDownCounter("db.client.connections.max",
+		metric.WithDescription("The maximum number of open connections allowed, or 0 if unlimited."),
+		metric.WithUnit("{connection}"))
+	if err != nil {
+		return err
+	}
+	waits, err := m.Int64ObservableCounter("db.client.connections.waits",
+		metric.WithDescription("The number of times a caller waited for a connection."),
+		metric.WithUnit("{wait}"))
+	if err != nil {
+		return err
+	}
+	waitTime, err := m.Float64ObservableCounter("db.client.connections.wait_time",
+		metric.WithDescription("The total time callers waited for a connection."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	poolAttrs := []attribute.KeyValue{
+		attribute.String("db.system", system),
+		attribute.String("db.client.connections.pool.name", pool),
+	}
+	attrs := metric.WithAttributes(poolAttrs...)
+	idleAttrs := metric.WithAttributes(append(poolAttrs, attribute.String("state", "idle"))...)
+	usedAttrs := metric.WithAttributes(append(poolAttrs, attribute.String("state", "used"))...)
+	_, err = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(usage, int64(stats.Idle), idleAttrs)
+		o.ObserveInt64(usage, int64(stats.InUse), usedAttrs)
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+		o.ObserveInt64(waits, stats.WaitCount, attrs)
+		o.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), attrs)
+		return nil
+	}, usage, maxOpen, waits, waitTime)
+	return err
+}
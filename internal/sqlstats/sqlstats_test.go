@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstats
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gocloud.dev/server/health"
+)
+
+// fakeConnector connects to nothing; connections fail if err is set.
+type fakeConnector struct {
+	err error
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return fakeConn{}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+func TestInstrument(t *testing.T) {
+	ctx := context.Background()
+	db := sql.OpenDB(fakeConnector{})
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	var checks []health.Checker
+	if err := Instrument(db, "mysql", "mydb", mp, func(c health.Checker) { checks = append(checks, c) }); err != nil {
+		t.Fatal(err)
+	}
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				if v, ok := dp.Attributes.Value("db.client.connections.pool.name"); !ok || v.AsString() != "mydb" {
+					t.Errorf("%s: got pool name %v, want mydb", m.Name, v)
+				}
+				key := m.Name
+				if state, ok := dp.Attributes.Value(attribute.Key("state")); ok {
+					key += "/" + state.AsString()
+				}
+				got[key] = dp.Value
+			}
+		}
+	}
+	for name, want := range map[string]int64{
+		"db.client.connections.usage/used": 1,
+		"db.client.connections.usage/idle": 0,
+		"db.client.connections.max":        3,
+		"db.client.connections.waits":      0,
+	} {
+		if v, ok := got[name]; !ok || v != want {
+			t.Errorf("%s = %d (reported: %t), want %d", name, v, ok, want)
+		}
+	}
+
+	if len(checks) != 1 {
+		t.Fatalf("got %d health checks, want 1", len(checks))
+	}
+	if err := checks[0].CheckHealth(); err != nil {
+		t.Errorf("CheckHealth: %v", err)
+	}
+}
+
+func TestInstrumentUnhealthy(t *testing.T) {
+	db := sql.OpenDB(fakeConnector{err: errors.New("unreachable")})
+	defer db.Close()
+	var check health.Checker
+	if err := Instrument(db, "postgresql", "mydb", nil, func(c health.Checker) { check = c }); err != nil {
+		t.Fatal(err)
+	}
+	if err := check.CheckHealth(); err == nil {
+		t.Error("CheckHealth of an unreachable database: got nil error")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqltls configures the TLS connections of the Go CDK's database
+// URL openers from URL parameters.
+//
+// The parameters are:
+//   - tls_ca: PEM CA certificates that verify the server, instead of the
+//     provider's CA certificates.
+//   - tls_cert and tls_key: a PEM client certificate and its private key,
+//     for servers that require mutual TLS.
+//   - tls_mode: how the server is verified. "verify-full", the default,
+//     verifies its certificate chain and hostname; "verify-ca" verifies only
+//     its certificate chain; "require" does not verify it.
+//
+// The certificates and key are read from a location, which is one of:
+//   - a file path, or a "file://" URL.
+//   - a blob URL, whose path is the blob's key: for example,
+//     "s3://mybucket/certs/ca.pem?region=us-west-1" reads "certs/ca.pem"
+//     from the bucket opened with "s3://mybucket?region=us-west-1".
+//   - a runtimevar URL, such as a secret in AWS Secrets Manager or GCP
+//     Secret Manager, whose value is the PEM data.
+//
+// The blob and runtimevar schemes are those registered on
+// blob.DefaultURLMux and runtimevar.DefaultURLMux, so the corresponding
+// drivers must be linked in.
+package sqltls // import "gocloud.dev/internal/sqltls"
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/runtimevar"
+)
+
+// Options are the TLS settings for database connections.
+// A nil *Options uses the defaults.
+type Options struct {
+	// RootCAs, if not nil, verifies server certificates instead of the
+	// provider's CA certificates.
+	RootCAs *x509.CertPool
+	// Certificates are the client certificates presented to servers.
+	Certificates []tls.Certificate
+	// Mode is "verify-full", "verify-ca" or "require".
+	Mode string
+}
+
+// FromURLParams returns the options set by the URL parameters in q, reading
+// the certificates and key they refer to, and the parameters in q that it
+// did not use.
+func FromURLParams(ctx context.Context, q url.Values) (*Options, url.Values, error) {
+	var ca, cert, key string
+	opts := &Options{Mode: "verify-full"}
+	rest := url.Values{}
+	for param, values := range q {
+		switch param {
+		case "tls_ca":
+			ca = values[0]
+		case "tls_cert":
+			cert = values[0]
+		case "tls_key":
+			key = values[0]
+		case "tls_mode":
+			opts.Mode = values[0]
+			if opts.Mode != "verify-full" && opts.Mode != "verify-ca" && opts.Mode != "require" {
+				return nil, nil, fmt.Errorf("invalid value for tls_mode: %q; want verify-full, verify-ca or require", opts.Mode)
+			}
+		default:
+			rest[param] = values
+		}
+	}
+	if ca != "" {
+		pem, err := read(ctx, ca)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read tls_ca: %v", err)
+		}
+		opts.RootCAs = x509.NewCertPool()
+		if !opts.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("tls_ca %q contains no PEM certificates", ca)
+		}
+	}
+	if (cert == "") != (key == "") {
+		return nil, nil, errors.New("tls_cert and tls_key must be set together")
+	}
+	if cert != "" {
+		certPEM, err := read(ctx, cert)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read tls_cert: %v", err)
+		}
+		keyPEM, err := read(ctx, key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read tls_key: %v", err)
+		}
+		c, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load tls_cert and tls_key: %v", err)
+		}
+		opts.Certificates = []tls.Certificate{c}
+	}
+	return opts, rest, nil
+}
+
+// HasRootCAs reports whether o replaces the provider's CA certificates, in
+// which case they need not be fetched.
+func (o *Options) HasRootCAs() bool {
+	return o != nil && o.RootCAs != nil
+}
+
+// Config returns a TLS configuration for connecting to serverName, which
+// verifies the server with o.RootCAs, or roots if o.RootCAs is nil. A nil
+// *Options verifies the server with roots.
+func (o *Options) Config(serverName string, roots *x509.CertPool) *tls.Config {
+	if o == nil {
+		o = &Options{}
+	}
+	if o.RootCAs != nil {
+		roots = o.RootCAs
+	}
+	cfg := &tls.Config{
+		ServerName:   serverName,
+		RootCAs:      roots,
+		Certificates: o.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	}
+	switch o.Mode {
+	case "verify-ca":
+		// Verify the chain in VerifyConnection, without the hostname.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			intermediates := x509.NewCertPool()
+			for _, c := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		}
+	case "require":
+		cfg.InsecureSkipVerify = true
+	}
+	return cfg
+}
+
+// read returns the contents of the file, blob or runtime variable at loc.
+func read(ctx context.Context, loc string) ([]byte, error) {
+	u, err := url.Parse(loc)
+	// A single-letter scheme is a Windows drive letter.
+	if err != nil || len(u.Scheme) <= 1 {
+		return os.ReadFile(loc)
+	}
+	switch {
+	case u.Scheme == "file":
+		return os.ReadFile(u.Path)
+	case blob.DefaultURLMux().ValidBucketScheme(u.Scheme):
+		key := strings.TrimPrefix(u.Path, "/")
+		if key == "" {
+			return nil, fmt.Errorf("%q has no blob key in its path", loc)
+		}
+		bucketURL := *u
+		bucketURL.Path = ""
+		bucketURL.RawPath = ""
+		b, err := blob.OpenBucket(ctx, bucketURL.String())
+		if err != nil {
+			return nil, err
+		}
+		defer b.Close()
+		return b.ReadAll(ctx, key)
+	case runtimevar.DefaultURLMux().ValidVariableScheme(u.Scheme):
+		v, err := runtimevar.OpenVariable(ctx, loc)
+		if err != nil {
+			return nil, err
+		}
+		defer v.Close()
+		snap, err := v.Latest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch val := snap.Value.(type) {
+		case []byte:
+			return val, nil
+		case string:
+			return []byte(val), nil
+		}
+		return nil, fmt.Errorf("runtime variable %q has a %T value, want bytes or a string", loc, snap.Value)
+	}
+	return nil, fmt.Errorf("unsupported scheme %q; want a file, blob or runtimevar URL", u.Scheme)
+}
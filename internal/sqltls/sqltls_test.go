@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqltls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/memblob"
+	_ "gocloud.dev/runtimevar/constantvar"
+)
+
+// selfSigned returns a PEM certificate valid for 127.0.0.1, and its key.
+func selfSigned(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sqltls test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// testBuckets opens a single in-memory bucket for the "sqltlstest" scheme.
+type testBuckets struct{ b *blob.Bucket }
+
+func (o testBuckets) OpenBucketURL(context.Context, *url.URL) (*blob.Bucket, error) {
+	return o.b, nil
+}
+
+func TestFromURLParams(t *testing.T) {
+	ctx := context.Background()
+	certPEM, keyPEM := selfSigned(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	b := memblob.OpenBucket(nil)
+	if err := b.WriteAll(ctx, "keys/key.pem", keyPEM, nil); err != nil {
+		t.Fatal(err)
+	}
+	blob.DefaultURLMux().RegisterBucket("sqltlstest", testBuckets{b})
+
+	q := url.Values{
+		"tls_ca":    {"constant://?decoder=string&val=" + url.QueryEscape(string(certPEM))},
+		"tls_cert":  {"file://" + filepath.ToSlash(certFile)},
+		"tls_key":   {"sqltlstest://bucket/keys/key.pem"},
+		"tls_mode":  {"verify-ca"},
+		"parseTime": {"true"},
+	}
+	opts, rest, err := FromURLParams(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.HasRootCAs() || len(opts.Certificates) != 1 || opts.Mode != "verify-ca" {
+		t.Errorf("got options %+v, want CA certificates, a client certificate and verify-ca", opts)
+	}
+	if rest.Encode() != "parseTime=true" {
+		t.Errorf("got remaining params %v, want only parseTime", rest)
+	}
+
+	opts, _, err = FromURLParams(ctx, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.HasRootCAs() || len(opts.Certificates) != 0 || opts.Mode != "verify-full" {
+		t.Errorf("without parameters: got options %+v, want the defaults", opts)
+	}
+
+	for _, bad := range []url.Values{
+		{"tls_mode": {"disable"}},
+		{"tls_cert": {certFile}},
+		{"tls_ca": {filepath.Join(dir, "missing.pem")}},
+		{"tls_ca": {certFile}, "tls_key": {certFile}, "tls_cert": {certFile}},
+		{"tls_ca": {"unknown://ca.pem"}},
+	} {
+		if _, _, err := FromURLParams(ctx, bad); err == nil {
+			t.Errorf("%v: got nil error", bad)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	certPEM, keyPEM := selfSigned(t)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certPEM)
+
+	tests := []struct {
+		name       string
+		opts       *Options
+		serverName string
+		roots      *x509.CertPool
+		wantErr    bool
+	}{
+		{name: "Nil", serverName: "127.0.0.1", roots: roots},
+		{name: "VerifyFull", opts: &Options{Mode: "verify-full"}, serverName: "127.0.0.1", roots: roots},
+		{name: "VerifyFullWrongHost", opts: &Options{Mode: "verify-full"}, serverName: "db.example.com", roots: roots, wantErr: true},
+		{name: "VerifyFullOwnRoots", opts: &Options{Mode: "verify-full", RootCAs: roots}, serverName: "127.0.0.1", roots: x509.NewCertPool()},
+		{name: "VerifyCAWrongHost", opts: &Options{Mode: "verify-ca"}, serverName: "db.example.com", roots: roots},
+		{name: "VerifyCAUnknownCA", opts: &Options{Mode: "verify-ca"}, serverName: "127.0.0.1", roots: x509.NewCertPool(), wantErr: true},
+		{name: "Require", opts: &Options{Mode: "require"}, serverName: "db.example.com", roots: x509.NewCertPool()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+			conn, err := tls.Dial("tcp", l.Addr().String(), test.opts.Config(test.serverName, test.roots))
+			if err == nil {
+				conn.Close()
+			}
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
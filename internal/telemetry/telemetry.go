@@ -0,0 +1,243 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry supports OpenTelemetry tracing and metrics for the
+// portable types of the Go Cloud Development Kit.
+//
+// Spans and metrics are reported to the global OpenTelemetry tracer and
+// meter providers, so that exporters are configured once per process, for
+// example with gocloud.dev/telemetry.Init. The global providers delegate to
+// the providers set later, so portable types may be created first.
+//
+// For compatibility, each call is also reported to OpenCensus, for the
+// OpenCensusViews of the portable types.
+package telemetry // import "gocloud.dev/internal/telemetry"
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/oc"
+)
+
+// Attribute keys of the spans and metrics of the portable types.
+var (
+	// PackageKey is the import path of the portable type's package, such
+	// as "gocloud.dev/blob".
+	PackageKey = attribute.Key("gocdk.package")
+	// MethodKey is the name of the method called, such as "NewReader".
+	MethodKey = attribute.Key("gocdk.method")
+	// ProviderKey is the package path of the driver, such as
+	// "gocloud.dev/blob/s3blob".
+	ProviderKey = attribute.Key("gocdk.provider")
+	// StatusKey is the gcerrors.ErrorCode of the call, such as "OK" or
+	// "NotFound".
+	StatusKey = attribute.Key("gocdk.status")
+	// ErrorTypeKey is the semantic convention attribute for the type of
+	// error of a failed call; it is the same as StatusKey.
+	ErrorTypeKey = attribute.Key("error.type")
+	// RetryKey is the number of the retry of a call, starting at 1, in the
+	// "retry" events of spans.
+	RetryKey = attribute.Key("gocdk.retry")
+)
+
+// Semantic convention attribute keys that drivers return from
+// Attributer.TelemetryAttributes; see
+// https://opentelemetry.io/docs/specs/semconv/.
+var (
+	// DBSystemKey identifies the database, such as "dynamodb".
+	DBSystemKey = attribute.Key("db.system")
+	// DBCollectionNameKey is the name of the table or collection.
+	DBCollectionNameKey = attribute.Key("db.collection.name")
+	// DBNamespaceKey is the name of the database of the collection.
+	DBNamespaceKey = attribute.Key("db.namespace")
+	// MessagingSystemKey identifies the messaging system, such as
+	// "gcp_pubsub".
+	MessagingSystemKey = attribute.Key("messaging.system")
+	// MessagingDestinationNameKey is the name of the topic or queue.
+	MessagingDestinationNameKey = attribute.Key("messaging.destination.name")
+	// MessagingSubscriptionNameKey is the name of the subscription.
+	MessagingSubscriptionNameKey = attribute.Key("messaging.destination.subscription.name")
+)
+
+// Attributer is implemented by drivers that describe the service they call
+// with semantic convention attributes, such as db.system, which are added to
+// the spans of the portable type.
+type Attributer interface {
+	TelemetryAttributes() []attribute.KeyValue
+}
+
+// DriverAttributes returns the attributes of driver if it implements
+// Attributer, or nil.
+func DriverAttributes(driver interface{}) []attribute.KeyValue {
+	if a, ok := driver.(Attributer); ok {
+		return a.TelemetryAttributes()
+	}
+	return nil
+}
+
+// ProviderName returns the name of the provider associated with the driver
+// value, for Tracer.Provider. It is the package path of the driver's type.
+func ProviderName(driver interface{}) string {
+	return oc.ProviderName(driver)
+}
+
+// MetricName returns the name of the metric of pkg with the given suffix.
+// For example, the "duration" metric of "gocloud.dev/secrets/secretstore"
+// is "gocdk.secrets.secretstore.duration".
+func MetricName(pkg, suffix string) string {
+	pkg = strings.TrimPrefix(pkg, "gocloud.dev/")
+	return "gocdk." + strings.ReplaceAll(pkg, "/", ".") + "." + suffix
+}
+
+// Meter returns the meter of pkg from the global meter provider.
+func Meter(pkg string) metric.Meter {
+	return otel.GetMeterProvider().Meter(pkg)
+}
+
+// A Tracer reports the method calls of a portable type as spans and as a
+// latency histogram.
+type Tracer struct {
+	// Package is the import path of the portable type's package.
+	Package string
+	// Provider is the package path of the driver.
+	Provider string
+
+	oc       *oc.Tracer
+	tracer   trace.Tracer
+	attrs    []attribute.KeyValue
+	duration metric.Float64Histogram
+	retries  metric.Int64Counter
+}
+
+// NewTracer returns a Tracer for the portable type in pkg whose driver is
+// in the package provider. OpenCensus latency measurements are recorded in
+// latencyMeasure. attrs are added to every span, typically the
+// DriverAttributes of the driver; they are not added to metrics, to keep
+// their cardinality low.
+func NewTracer(pkg, provider string, latencyMeasure *stats.Float64Measure, attrs ...attribute.KeyValue) *Tracer {
+	duration, err := Meter(pkg).Float64Histogram(MetricName(pkg, "duration"),
+		metric.WithDescription("Duration of method calls, by provider, method and status."),
+		metric.WithUnit("s"))
+	if err != nil {
+		// The only possible errors are from invalid instrument names, and
+		// those are programming errors that will be found during testing.
+		panic(err)
+	}
+	return &Tracer{
+		Package:  pkg,
+		Provider: provider,
+		oc: &oc.Tracer{
+			Package:        pkg,
+			Provider:       provider,
+			LatencyMeasure: latencyMeasure,
+		},
+		tracer:   otel.GetTracerProvider().Tracer(pkg),
+		attrs:    attrs,
+		duration: duration,
+		retries: Int64Counter(pkg, "retries",
+			"Count of retried method calls, by provider, method and status of the failed attempt.", "{retry}"),
+	}
+}
+
+// Context key for the method and starting time of a call.
+type callKey struct{}
+
+type call struct {
+	method string
+	start  time.Time
+}
+
+// Start starts a span for a call of the method methodName, with attrs in
+// addition to the standard attributes, and prepares for recording its
+// latency. The returned context must be passed to End.
+func (t *Tracer) Start(ctx context.Context, methodName string, attrs ...attribute.KeyValue) context.Context {
+	ctx = t.oc.Start(ctx, methodName)
+	// Copy attrs, so that appending doesn't write into the caller's slice.
+	attrs = append(append(slices.Clone(attrs), t.attrs...),
+		PackageKey.String(t.Package),
+		MethodKey.String(methodName),
+		ProviderKey.String(t.Provider))
+	ctx, _ = t.tracer.Start(ctx, t.Package+"."+methodName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, callKey{}, call{methodName, time.Now()})
+}
+
+// End ends the span of the call started by Start with the given error, and
+// records the call's latency.
+func (t *Tracer) End(ctx context.Context, err error) {
+	c := ctx.Value(callKey{}).(call)
+	elapsed := time.Since(c.start)
+	t.oc.End(ctx, err)
+
+	status := StatusKey.String(gcerrors.Code(err).String())
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(status)
+	if err != nil {
+		span.SetAttributes(ErrorTypeKey.String(gcerrors.Code(err).String()))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	t.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+		MethodKey.String(c.method),
+		ProviderKey.String(t.Provider),
+		status))
+}
+
+// CountRetries returns isRetryable, wrapped to record each retry of the
+// method methodName as an increment of the retries metric of the package and
+// a "retry" event on the span of ctx. Use it with retry.Call for retry loops
+// in portable types.
+func (t *Tracer) CountRetries(ctx context.Context, methodName string, isRetryable func(error) bool) func(error) bool {
+	var attempt int64
+	return func(err error) bool {
+		if !isRetryable(err) {
+			return false
+		}
+		attempt++
+		code := gcerrors.Code(err).String()
+		t.retries.Add(ctx, 1, metric.WithAttributes(
+			MethodKey.String(methodName),
+			ProviderKey.String(t.Provider),
+			StatusKey.String(code)))
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			RetryKey.Int64(attempt),
+			StatusKey.String(code),
+			ErrorTypeKey.String(code)))
+		return true
+	}
+}
+
+// Int64Counter returns a counter of pkg from the global meter provider,
+// named by MetricName(pkg, suffix).
+func Int64Counter(pkg, suffix, description, unit string) metric.Int64Counter {
+	c, err := Meter(pkg).Int64Counter(MetricName(pkg, suffix),
+		metric.WithDescription(description),
+		metric.WithUnit(unit))
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/oc"
+)
+
+func TestMetricName(t *testing.T) {
+	for _, test := range []struct {
+		pkg, suffix, want string
+	}{
+		{"gocloud.dev/blob", "duration", "gocdk.blob.duration"},
+		{"gocloud.dev/secrets/secretstore", "duration", "gocdk.secrets.secretstore.duration"},
+		{"example.com/store", "bytes_read", "gocdk.example.com.store.bytes_read"},
+	} {
+		if got := MetricName(test.pkg, test.suffix); got != test.want {
+			t.Errorf("MetricName(%q, %q) = %q, want %q", test.pkg, test.suffix, got, test.want)
+		}
+	}
+}
+
+type attributer struct{}
+
+func (attributer) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{DBSystemKey.String("testdb")}
+}
+
+func TestTracer(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)))
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	if got := DriverAttributes(struct{}{}); got != nil {
+		t.Errorf("DriverAttributes of a non-Attributer: got %v, want nil", got)
+	}
+	const pkg = "gocloud.dev/telemetrytest"
+	tr := NewTracer(pkg, "gocloud.dev/telemetrytest/driver", oc.LatencyMeasure(pkg), DriverAttributes(attributer{})...)
+
+	errUnavailable := gcerr.Newf(gcerr.ResourceExhausted, nil, "resource exhausted")
+	ctx = tr.Start(ctx, "Get")
+	isRetryable := tr.CountRetries(ctx, "Get", func(err error) bool { return err == errUnavailable })
+	for i := 0; i < 2; i++ {
+		if !isRetryable(errUnavailable) {
+			t.Fatal("got isRetryable false, want true")
+		}
+	}
+	if isRetryable(errors.New("permanent")) {
+		t.Fatal("got isRetryable true for a permanent error, want false")
+	}
+	tr.End(ctx, errUnavailable)
+
+	got := spans.GetSpans()
+	if len(got) != 1 {
+		t.Fatalf("got %d spans, want 1", len(got))
+	}
+	s := got[0]
+	if s.Name != pkg+".Get" {
+		t.Errorf("got span name %q, want %q", s.Name, pkg+".Get")
+	}
+	var hasSystem bool
+	for _, a := range s.Attributes {
+		if a == DBSystemKey.String("testdb") {
+			hasSystem = true
+		}
+	}
+	if !hasSystem {
+		t.Errorf("got attributes %v, want db.system testdb", s.Attributes)
+	}
+	// The span also records the error returned to End as an exception event.
+	if len(s.Events) != 3 || s.Events[0].Name != "retry" || s.Events[1].Name != "retry" {
+		t.Fatalf("got events %v, want 2 retry events and an exception", s.Events)
+	}
+	attrs := attribute.NewSet(s.Events[1].Attributes...)
+	if v, _ := attrs.Value(RetryKey); v.AsInt64() != 2 {
+		t.Errorf("got retry event attributes %v, want gocdk.retry 2", s.Events[1].Attributes)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	var retries int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "gocdk.telemetrytest.retries" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				retries += dp.Value
+			}
+		}
+	}
+	if retries != 2 {
+		t.Errorf("got %d retries, want 2", retries)
+	}
+}
+
+func TestTracerStartKeepsCallerAttributes(t *testing.T) {
+	const pkg = "gocloud.dev/telemetrytest"
+	tr := NewTracer(pkg, "gocloud.dev/telemetrytest/driver", oc.LatencyMeasure(pkg), DBSystemKey.String("testdb"))
+	attrs := make([]attribute.KeyValue, 1, 8)
+	attrs[0] = MethodKey.String("caller")
+	ctx := tr.Start(context.Background(), "Get", attrs...)
+	tr.End(ctx, nil)
+	if got := attrs[:cap(attrs)][1]; got.Valid() {
+		t.Errorf("Start wrote %v into the spare capacity of the caller's attributes", got)
+	}
+}
@@ -47,6 +47,11 @@ import (
 // Record is true iff the tests are being run in "record" mode.
 var Record = flag.Bool("record", false, "whether to run tests against cloud resources and record the interactions")
 
+// Emulators is true iff the tests are being run against local emulators in
+// Docker, started by gocloud.dev/testing/emulators, instead of replaying
+// recorded interactions. Only some drivers' tests support it.
+var Emulators = flag.Bool("emulators", false, "whether to run tests that support it against local emulators in Docker instead of replaying recorded interactions")
+
 // FakeGCPCredentials gets fake GCP credentials.
 func FakeGCPCredentials(ctx context.Context) (*google.Credentials, error) {
 	return google.CredentialsFromJSON(ctx, []byte(`{"type": "service_account", "project_id": "my-project-id"}`))
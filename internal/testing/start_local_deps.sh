@@ -24,6 +24,7 @@ set -euo pipefail
 ./pubsub/kafkapubsub/localkafka.sh
 ./pubsub/rabbitpubsub/localrabbit.sh
 ./runtimevar/etcdvar/localetcd.sh
+./docstore/cassandradocstore/localcassandra.sh
 ./docstore/mongodocstore/localmongo.sh
 ./secrets/hashivault/localvault.sh
 
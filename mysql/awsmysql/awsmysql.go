@@ -24,21 +24,74 @@
 // see URLOpener.
 //
 // See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # IAM database authentication
+//
+// Instead of a password, connections can authenticate with IAM database
+// authentication tokens, generated from the AWS credentials in the
+// environment. Set the URL parameter "iam_auth=true", optionally with
+// "region" and "profile" parameters, or set URLOpener.AuthTokens. Tokens
+// expire after 15 minutes, so new connections from the pool get a fresh
+// one; existing connections are unaffected by expiry.
+//
+// # Credential rotation
+//
+// The URL parameter "credentials_url" reads the database user name and
+// password from a secret store instead of the URL. It is a runtimevar URL,
+// escaped as a query parameter value, such as
+// "awssecretsmanager://mydb-credentials?region=us-west-1&decoder=string".
+// The secret is a JSON object with "username" and "password" fields, as in
+// the secrets that RDS manages in Secrets Manager, a database URL, or the
+// password itself. New connections use the latest version of the secret,
+// and a connection that fails to authenticate reads the secret again and
+// retries, so the password can be rotated without restarting. The runtimevar
+// driver for the URL's scheme must be linked in, for example with a blank
+// import of gocloud.dev/runtimevar/awssecretsmanager.
+//
+// # RDS Proxy and Aurora
+//
+// Connections to RDS Proxy endpoints verify the proxy's certificate with the
+// system root CAs, since RDS Proxy does not use the RDS CA bundle. Proxy
+// endpoints are recognized by their hostname, or set "rds_proxy=true".
+//
+// For an Aurora cluster, "aurora_endpoint=reader" or "aurora_endpoint=writer"
+// selects the reader or writer (cluster) endpoint, given either of them in
+// the URL. Setting "failover_timeout", such as "failover_timeout=30s",
+// retries new connections with backoff during a failover, limits how many
+// are attempted at once, and rejects connections to the writer endpoint
+// that reach a read-only instance because of stale DNS.
+//
+// # TLS
+//
+// Connections always use TLS, verified with the RDS CA certificates by
+// default. The "tls_ca", "tls_cert", "tls_key" and "tls_mode" URL
+// parameters set custom CA certificates, a client certificate, and how the
+// server is verified: "verify-full" (the default), "verify-ca" or "require".
+// The certificates and key are read from a file path, a blob URL whose path
+// is the blob's key, such as "s3://mybucket/ca.pem?region=us-west-1", or a
+// runtimevar URL, such as a secret in AWS Secrets Manager.
 package awsmysql // import "gocloud.dev/mysql/awsmysql"
 
 import (
 	"context"
-	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 	"github.com/go-sql-driver/mysql"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/metric"
 	"gocloud.dev/aws/rds"
+	"gocloud.dev/internal/sqlcreds"
+	"gocloud.dev/internal/sqlstats"
+	"gocloud.dev/internal/sqltls"
 	gcmysql "gocloud.dev/mysql"
+	"gocloud.dev/server/health"
 )
 
 // Set is a Wire provider set that provides a *sql.DB given
@@ -56,6 +109,18 @@ type URLOpener struct {
 	CertSource rds.CertPoolProvider
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+	// AuthTokens, if set, generates IAM authentication tokens that are used
+	// as the password for each new connection, instead of the password in
+	// the URL. If nil, the "iam_auth" URL parameter creates a generator.
+	AuthTokens *rds.AuthTokenGenerator
+	// MeterProvider, if set, receives metrics of the connection pool of
+	// each opened database, such as the number of open and in-use
+	// connections and the time spent waiting for one.
+	MeterProvider metric.MeterProvider
+	// AddHealthCheck, if set, is called with a health check of each opened
+	// database, which pings it. For example, pass the Add method of a
+	// *health.Handler.
+	AddHealthCheck func(health.Checker)
 }
 
 // Scheme is the URL scheme awsmysql registers its URLOpener under on
@@ -67,7 +132,7 @@ func init() {
 }
 
 // OpenMySQLURL opens a new RDS database connection wrapped with OpenCensus instrumentation.
-func (uo *URLOpener) OpenMySQLURL(_ context.Context, u *url.URL) (*sql.DB, error) {
+func (uo *URLOpener) OpenMySQLURL(ctx context.Context, u *url.URL) (*sql.DB, error) {
 	source := uo.CertSource
 	if source == nil {
 		source = new(rds.CertFetcher)
@@ -76,21 +141,68 @@ func (uo *URLOpener) OpenMySQLURL(_ context.Context, u *url.URL) (*sql.DB, error
 		return nil, fmt.Errorf("open RDS: empty endpoint")
 	}
 
-	cfg, err := gcmysql.ConfigFromURL(u)
+	c, err := uo.newConnector(ctx, u, source)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(c.creds.Wrap(c, isAuthError))
+	if err := sqlstats.Instrument(db, "mysql", u.Host, uo.MeterProvider, uo.AddHealthCheck); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	return db, nil
+}
+
+func (uo *URLOpener) newConnector(ctx context.Context, u *url.URL, source CertPoolProvider) (*connector, error) {
+	tokens, query, err := rds.AuthTokenGeneratorFromURLParams(ctx, u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	if uo.AuthTokens != nil {
+		tokens = uo.AuthTokens
+	}
+	endpoint, query, err := rds.EndpointOptionsFromURLParams(query)
+	if err != nil {
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	tlsOpts, query, err := sqltls.FromURLParams(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	u2 := new(url.URL)
+	*u2 = *u
+	if u2.Host, err = endpoint.Host(u.Host); err != nil {
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	creds, query, err := sqlcreds.FromURLParams(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("open RDS: %v", err)
+	}
+	if creds != nil && tokens != nil {
+		creds.Close()
+		return nil, fmt.Errorf("open RDS: credentials_url cannot be used with IAM authentication")
+	}
+	u2.RawQuery = query.Encode()
+	cfg, err := gcmysql.ConfigFromURL(u2)
 	if err != nil {
+		creds.Close()
 		return nil, err
 	}
 	c := &connector{
-		dsn: cfg.FormatDSN(),
+		cfg: cfg,
 		// Make a copy of TraceOpts to avoid caller modifying.
 		traceOpts: append([]ocsql.TraceOption(nil), uo.TraceOpts...),
-		provider:  source,
+		provider:  endpoint.CertPoolProvider(u2.Host, source),
+		tokens:    tokens,
+		creds:     creds,
+		tls:       tlsOpts,
+		failover:  endpoint.Failover(u2.Host, readOnlyQuery),
 
 		sem:   make(chan struct{}, 1),
 		ready: make(chan struct{}),
 	}
 	c.sem <- struct{}{}
-	return sql.OpenDB(c), nil
+	return c, nil
 }
 
 type connector struct {
@@ -98,28 +210,38 @@ type connector struct {
 
 	sem      chan struct{} // receive to acquire, send to release
 	provider CertPoolProvider
+	tokens   *rds.AuthTokenGenerator // nil to use the password in cfg
+	creds    *sqlcreds.Source        // nil to use the password in cfg
+	tls      *sqltls.Options         // TLS settings from the URL
+	failover *rds.Failover           // nil to connect only once
 
-	ready chan struct{} // closed after resolving dsn
-	dsn   string
+	ready chan struct{} // closed after setting cfg.TLS
+	cfg   *mysql.Config
 }
 
 func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.failover != nil {
+		return c.failover.Connect(ctx, c.connect)
+	}
+	return c.connect(ctx)
+}
+
+func (c *connector) connect(ctx context.Context) (driver.Conn, error) {
 	select {
 	case <-c.sem:
-		certPool, err := c.provider.RDSCertPool(ctx)
-		if err != nil {
-			c.sem <- struct{}{} // release
-			return nil, fmt.Errorf("connect RDS: %v", err)
+		var certPool *x509.CertPool
+		if !c.tls.HasRootCAs() {
+			var err error
+			if certPool, err = c.provider.RDSCertPool(ctx); err != nil {
+				c.sem <- struct{}{} // release
+				return nil, fmt.Errorf("connect RDS: %v", err)
+			}
 		}
-		cfg, err := mysql.ParseDSN(c.dsn)
+		host, _, err := net.SplitHostPort(c.cfg.Addr)
 		if err != nil {
-			c.sem <- struct{}{} // release
-			return nil, fmt.Errorf("connect RDS: parse DSN: %v", err)
+			host = c.cfg.Addr
 		}
-		cfg.TLS = &tls.Config{
-			RootCAs: certPool,
-		}
-		c.dsn = cfg.FormatDSN()
+		c.cfg.TLS = c.tls.Config(host, certPool)
 		close(c.ready)
 		// Don't release sem: make it block forever, so this case won't be run again.
 	case <-c.ready:
@@ -127,13 +249,66 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	case <-ctx.Done():
 		return nil, fmt.Errorf("connect RDS: waiting for certificates: %v", ctx.Err())
 	}
-	return c.Driver().Open(c.dsn)
+	cfg, err := c.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect RDS: %v", err)
+	}
+	conn, err := mc.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ocsql.WrapConn(conn, c.traceOpts...), nil
+}
+
+// config returns the configuration for a new connection, with the
+// credentials from the secret store, or a fresh IAM authentication token as
+// its password, if c uses them.
+func (c *connector) config(ctx context.Context) (*mysql.Config, error) {
+	cfg := c.cfg.Clone()
+	creds, err := c.creds.Credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connect RDS: %v", err)
+	}
+	if creds.User != "" {
+		cfg.User = creds.User
+	}
+	if creds.Password != "" {
+		cfg.Passwd = creds.Password
+	}
+	if c.tokens == nil {
+		return cfg, nil
+	}
+	addr := cfg.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "3306")
+	}
+	token, err := c.tokens.AuthToken(ctx, addr, cfg.User)
+	if err != nil {
+		return nil, fmt.Errorf("connect RDS: %v", err)
+	}
+	cfg.Passwd = token
+	return cfg, nil
 }
 
 func (c *connector) Driver() driver.Driver {
 	return ocsql.Wrap(mysql.MySQLDriver{}, c.traceOpts...)
 }
 
+// isAuthError reports whether err is MySQL's "access denied" error, which
+// is returned for a wrong user name or password.
+func isAuthError(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == 1045
+}
+
+// readOnlyQuery returns whether a MySQL instance is read-only. Aurora
+// readers run with innodb_read_only set.
+const readOnlyQuery = "SELECT @@innodb_read_only"
+
 // A CertPoolProvider obtains a certificate pool that contains the RDS CA certificate.
 type CertPoolProvider = rds.CertPoolProvider
 
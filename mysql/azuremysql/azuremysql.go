@@ -22,20 +22,40 @@
 // see URLOpener.
 //
 // See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Microsoft Entra ID authentication
+//
+// Instead of a password, connections can authenticate with Microsoft Entra
+// ID (formerly Azure AD) access tokens, obtained with the default Azure
+// credential. Set the URL parameter "aad_auth=true", or set
+// URLOpener.Tokens. New connections from the pool get a fresh token before
+// the previous one expires.
+//
+// # TLS
+//
+// Connections always use TLS, verified with the Azure CA certificates by
+// default. The "tls_ca", "tls_cert", "tls_key" and "tls_mode" URL
+// parameters set custom CA certificates, a client certificate, and how the
+// server is verified: "verify-full" (the default), "verify-ca" or "require".
+// The certificates and key are read from a file path, a blob URL whose path
+// is the blob's key, such as "azblob://mycontainer/ca.pem", or a runtimevar
+// URL.
 package azuremysql // import "gocloud.dev/mysql/azuremysql"
 
 import (
 	"context"
-	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 	"github.com/go-sql-driver/mysql"
 	"gocloud.dev/azure/azuredb"
+	"gocloud.dev/internal/sqltls"
 	cdkmysql "gocloud.dev/mysql"
 )
 
@@ -47,6 +67,10 @@ type URLOpener struct {
 	CertSource azuredb.CertPoolProvider
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+	// Tokens, if set, provides access tokens that are used as the password
+	// for each new connection, instead of the password in the URL. If nil,
+	// the "aad_auth" URL parameter creates one.
+	Tokens *azuredb.TokenSource
 }
 
 // Scheme is the URL scheme azuremysql registers its URLOpener under on
@@ -66,15 +90,33 @@ func (uo *URLOpener) OpenMySQLURL(ctx context.Context, u *url.URL) (*sql.DB, err
 	if u.Host == "" {
 		return nil, fmt.Errorf("open Azure database: empty endpoint")
 	}
+	tokens, query, err := azuredb.TokenSourceFromURLParams(u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("open Azure database: %v", err)
+	}
+	tlsOpts, _, err := sqltls.FromURLParams(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("open Azure database: %v", err)
+	}
+	if uo.Tokens != nil {
+		tokens = uo.Tokens
+	}
 	password, _ := u.User.Password()
 	c := &connector{
-		addr:     u.Host,
-		user:     u.User.Username(),
-		password: password,
-		dbName:   strings.TrimPrefix(u.Path, "/"),
+		cfg: &mysql.Config{
+			Net:                     "tcp",
+			Addr:                    u.Host,
+			User:                    u.User.Username(),
+			Passwd:                  password,
+			AllowCleartextPasswords: true,
+			AllowNativePasswords:    true,
+			DBName:                  strings.TrimPrefix(u.Path, "/"),
+		},
 		// Make a copy of TraceOpts to avoid caller modifying.
 		traceOpts: append([]ocsql.TraceOption(nil), uo.TraceOpts...),
 		provider:  source,
+		tokens:    tokens,
+		tls:       tlsOpts,
 
 		sem:   make(chan struct{}, 1),
 		ready: make(chan struct{}),
@@ -84,38 +126,33 @@ func (uo *URLOpener) OpenMySQLURL(ctx context.Context, u *url.URL) (*sql.DB, err
 }
 
 type connector struct {
-	addr      string
-	user      string
-	password  string
-	dbName    string
 	traceOpts []ocsql.TraceOption
 
-	sem      chan struct{}    // receive to acquire, send to release
-	provider CertPoolProvider // provides the CA certificate pool
+	sem      chan struct{}        // receive to acquire, send to release
+	provider CertPoolProvider     // provides the CA certificate pool
+	tokens   *azuredb.TokenSource // nil to use the password in cfg
+	tls      *sqltls.Options      // TLS settings from the URL
 
-	ready chan struct{} // closed after writing dsn
-	dsn   string
+	ready chan struct{} // closed after setting cfg.TLS
+	cfg   *mysql.Config
 }
 
 func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	select {
 	case <-c.sem:
-		certPool, err := c.provider.AzureCertPool(ctx)
-		if err != nil {
-			c.sem <- struct{}{} // release
-			return nil, fmt.Errorf("connect Azure MySql: %v", err)
+		var certPool *x509.CertPool
+		if !c.tls.HasRootCAs() {
+			var err error
+			if certPool, err = c.provider.AzureCertPool(ctx); err != nil {
+				c.sem <- struct{}{} // release
+				return nil, fmt.Errorf("connect Azure MySql: %v", err)
+			}
 		}
-		cfg := &mysql.Config{
-			Net:                     "tcp",
-			Addr:                    c.addr,
-			User:                    c.user,
-			Passwd:                  c.password,
-			TLS:                     &tls.Config{RootCAs: certPool},
-			AllowCleartextPasswords: true,
-			AllowNativePasswords:    true,
-			DBName:                  c.dbName,
+		host, _, err := net.SplitHostPort(c.cfg.Addr)
+		if err != nil {
+			host = c.cfg.Addr
 		}
-		c.dsn = cfg.FormatDSN()
+		c.cfg.TLS = c.tls.Config(host, certPool)
 		close(c.ready)
 		// Don't release sem: make it block forever, so this case won't be run again.
 	case <-c.ready:
@@ -123,7 +160,34 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	case <-ctx.Done():
 		return nil, fmt.Errorf("connect Azure MySql: waiting for certificates: %v", ctx.Err())
 	}
-	return c.Driver().Open(c.dsn)
+	cfg, err := c.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect Azure MySql: %v", err)
+	}
+	conn, err := mc.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ocsql.WrapConn(conn, c.traceOpts...), nil
+}
+
+// config returns the configuration for a new connection, with an access
+// token as its password if c uses them.
+func (c *connector) config(ctx context.Context) (*mysql.Config, error) {
+	cfg := c.cfg.Clone()
+	if c.tokens == nil {
+		return cfg, nil
+	}
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connect Azure MySql: %v", err)
+	}
+	cfg.Passwd = token
+	return cfg, nil
 }
 
 func (c *connector) Driver() driver.Driver {
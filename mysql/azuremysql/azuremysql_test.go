@@ -22,8 +22,14 @@ package azuremysql
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	gomysql "github.com/go-sql-driver/mysql"
+	"gocloud.dev/azure/azuredb"
 	"gocloud.dev/internal/testing/terraform"
 	"gocloud.dev/mysql"
 )
@@ -65,3 +71,32 @@ func TestURLOpener(t *testing.T) {
 		t.Error("Close: ", err)
 	}
 }
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestTokenAuth(t *testing.T) {
+	ctx := context.Background()
+	c := &connector{
+		cfg:    &gomysql.Config{User: "alice", Passwd: "unused"},
+		tokens: azuredb.NewTokenSource(fakeCredential{}),
+	}
+	cfg, err := c.config(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Passwd != "token" {
+		t.Errorf("got password %q, want the access token", cfg.Passwd)
+	}
+	if c.cfg.Passwd != "unused" {
+		t.Error("config modified the connector's configuration")
+	}
+
+	uo := new(URLOpener)
+	if _, err := uo.OpenMySQLURL(ctx, &url.URL{Scheme: Scheme, Host: "localhost", RawQuery: "aad_auth=maybe"}); err == nil {
+		t.Error("invalid aad_auth: got nil error")
+	}
+}
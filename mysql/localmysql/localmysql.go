@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localmysql provides MySQL databases for local development and
+// continuous integration, under the same URL-opening API as awsmysql,
+// azuremysql and gcpmysql, so that code that calls mysql.Open runs
+// unchanged with a local URL.
+//
+// # URLs
+//
+// For mysql.Open, localmysql registers for the scheme "localmysql".
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+//
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Docker
+//
+// A URL without a host, such as "localmysql://root:password@/mydb", starts
+// a MySQL server in a new Docker container, using the docker command, and
+// waits for it to accept connections. The server has the user, password and
+// database in the URL, and the container is removed when the *sql.DB is
+// closed. A URL with a host connects to a server that is already running,
+// such as a CI service container, without TLS.
+//
+// # SQLite
+//
+// The URL parameter "sqlite" opens a SQLite database file instead, with no
+// server, as in "localmysql:///?sqlite=dev.db". A SQLite database/sql
+// driver, such as modernc.org/sqlite or github.com/mattn/go-sqlite3, must be
+// linked in. Queries that use MySQL-specific SQL do not work with SQLite.
+package localmysql // import "gocloud.dev/mysql/localmysql"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+
+	"contrib.go.opencensus.io/integrations/ocsql"
+	"github.com/go-sql-driver/mysql"
+	"gocloud.dev/internal/localdb"
+	gcmysql "gocloud.dev/mysql"
+)
+
+// Scheme is the URL scheme localmysql registers its URLOpener under on
+// mysql.DefaultMux.
+const Scheme = "localmysql"
+
+// DefaultImage is the Docker image of the MySQL servers that URLOpener
+// starts by default.
+const DefaultImage = "mysql:8.0"
+
+func init() {
+	gcmysql.DefaultURLMux().RegisterMySQL(Scheme, &URLOpener{})
+}
+
+// URLOpener opens local MySQL URLs like "localmysql://root:password@/mydb".
+//
+// The URL's user defaults to "root". The following query parameters are
+// supported; others are passed to the MySQL driver, as in mysql.URLOpener:
+//   - image: the Docker image of the server; defaults to URLOpener.Image.
+//   - ready_timeout: how long to wait for a new server to accept
+//     connections, such as "30s"; defaults to 2 minutes.
+//   - sqlite: the path of a SQLite database file to open instead.
+type URLOpener struct {
+	// Image is the Docker image of the servers that the opener starts. If
+	// empty, DefaultImage is used.
+	Image string
+	// TraceOpts contains options for OpenCensus.
+	TraceOpts []ocsql.TraceOption
+}
+
+// OpenMySQLURL opens a local MySQL database, starting a server for it if the
+// URL has no host, wrapped with OpenCensus instrumentation.
+func (uo *URLOpener) OpenMySQLURL(ctx context.Context, u *url.URL) (*sql.DB, error) {
+	image := uo.Image
+	if image == "" {
+		image = DefaultImage
+	}
+	params, query, err := localdb.ParamsFromURL(u.Query(), image)
+	if err != nil {
+		return nil, fmt.Errorf("localmysql: open: %v", err)
+	}
+	if params.SQLite != "" {
+		db, err := localdb.OpenSQLite(params.SQLite)
+		if err != nil {
+			return nil, fmt.Errorf("localmysql: open: %v", err)
+		}
+		return db, nil
+	}
+
+	u2 := new(url.URL)
+	*u2 = *u
+	u2.RawQuery = query.Encode()
+	if u2.User.Username() == "" {
+		u2.User = url.User("root")
+		if password, ok := u.User.Password(); ok {
+			u2.User = url.UserPassword("root", password)
+		}
+	}
+	var container *localdb.Container
+	if u2.Host == "" {
+		if container, err = localdb.Start(ctx, params.Image, 3306, containerEnv(u2)); err != nil {
+			return nil, fmt.Errorf("localmysql: open: %v", err)
+		}
+		u2.Host = container.Addr
+	}
+	cfg, err := gcmysql.ConfigFromURL(u2)
+	var mc driver.Connector
+	if err == nil {
+		mc, err = mysql.NewConnector(cfg)
+	}
+	if err != nil {
+		if container != nil {
+			container.Close()
+		}
+		return nil, fmt.Errorf("localmysql: open: %v", err)
+	}
+
+	var c driver.Connector = connector{mc, append([]ocsql.TraceOption(nil), uo.TraceOpts...)}
+	if container == nil {
+		return sql.OpenDB(c), nil
+	}
+	db := sql.OpenDB(container.Wrap(c))
+	if err := localdb.Wait(ctx, db, params.ReadyTimeout); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("localmysql: open: %v", err)
+	}
+	return db, nil
+}
+
+// containerEnv returns the environment variables that configure the MySQL
+// image to create the user and database in u.
+func containerEnv(u *url.URL) map[string]string {
+	user := u.User.Username()
+	password, _ := u.User.Password()
+	env := map[string]string{}
+	if db := u.Path; len(db) > 1 {
+		env["MYSQL_DATABASE"] = db[1:]
+	}
+	switch {
+	case user != "root":
+		env["MYSQL_USER"] = user
+		env["MYSQL_PASSWORD"] = password
+		env["MYSQL_RANDOM_ROOT_PASSWORD"] = "yes"
+	case password != "":
+		env["MYSQL_ROOT_PASSWORD"] = password
+	default:
+		env["MYSQL_ALLOW_EMPTY_PASSWORD"] = "yes"
+	}
+	return env
+}
+
+// connector wraps the connections of a MySQL connector with OpenCensus
+// instrumentation.
+type connector struct {
+	driver.Connector
+	traceOpts []ocsql.TraceOption
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ocsql.WrapConn(conn, c.traceOpts...), nil
+}
@@ -24,23 +24,88 @@
 // see URLOpener.
 //
 // See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # IAM database authentication
+//
+// Instead of a password, connections can authenticate with IAM database
+// authentication tokens, generated from the AWS credentials in the
+// environment. Set the URL parameter "iam_auth=true", optionally with
+// "region" and "profile" parameters, or set URLOpener.AuthTokens. Tokens
+// expire after 15 minutes, so new connections from the pool get a fresh
+// one; existing connections are unaffected by expiry.
+//
+// # Credential rotation
+//
+// The URL parameter "credentials_url" reads the database user name and
+// password from a secret store instead of the URL. It is a runtimevar URL,
+// escaped as a query parameter value, such as
+// "awssecretsmanager://mydb-credentials?region=us-west-1&decoder=string".
+// The secret is a JSON object with "username" and "password" fields, as in
+// the secrets that RDS manages in Secrets Manager, a database URL, or the
+// password itself. New connections use the latest version of the secret,
+// and a connection that fails to authenticate reads the secret again and
+// retries, so the password can be rotated without restarting. The runtimevar
+// driver for the URL's scheme must be linked in, for example with a blank
+// import of gocloud.dev/runtimevar/awssecretsmanager. OpenPgxPoolURL does
+// not support it.
+//
+// # RDS Proxy and Aurora
+//
+// Connections to RDS Proxy endpoints verify the proxy's certificate with the
+// system root CAs, since RDS Proxy does not use the RDS CA bundle. Proxy
+// endpoints are recognized by their hostname, or set "rds_proxy=true".
+//
+// For an Aurora cluster, "aurora_endpoint=reader" or "aurora_endpoint=writer"
+// selects the reader or writer (cluster) endpoint, given either of them in
+// the URL. Setting "failover_timeout", such as "failover_timeout=30s",
+// retries new connections with backoff during a failover, limits how many
+// are attempted at once, and rejects connections to the writer endpoint
+// that reach a read-only instance because of stale DNS.
+//
+// # TLS
+//
+// Connections always use TLS, verified with the RDS CA certificates by
+// default. The "tls_ca", "tls_cert", "tls_key" and "tls_mode" URL
+// parameters set custom CA certificates, a client certificate, and how the
+// server is verified: "verify-full" (the default), "verify-ca" or "require".
+// The certificates and key are read from a file path, a blob URL whose path
+// is the blob's key, such as "s3://mybucket/ca.pem?region=us-west-1", or a
+// runtimevar URL, such as a secret in AWS Secrets Manager.
+//
+// # Drivers
+//
+// Connections use the lib/pq driver by default. Set the URL parameter
+// "driver=pgx" to use pgx instead, through its database/sql adapter, or use
+// URLOpener.OpenPgxPoolURL to open a native pgx connection pool.
 package awspostgres // import "gocloud.dev/postgres/awspostgres"
 
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/url"
+	"strconv"
 	"time"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/metric"
 	"gocloud.dev/aws/rds"
+	"gocloud.dev/internal/sqlcreds"
+	"gocloud.dev/internal/sqlstats"
+	"gocloud.dev/internal/sqltls"
 	"gocloud.dev/postgres"
+	"gocloud.dev/server/health"
 )
 
 // URLOpener opens RDS PostgreSQL URLs
@@ -51,6 +116,18 @@ type URLOpener struct {
 	CertSource rds.CertPoolProvider
 	// TraceOpts contains options for OpenCensus.
 	TraceOpts []ocsql.TraceOption
+	// AuthTokens, if set, generates IAM authentication tokens that are used
+	// as the password for each new connection, instead of the password in
+	// the URL. If nil, the "iam_auth" URL parameter creates a generator.
+	AuthTokens *rds.AuthTokenGenerator
+	// MeterProvider, if set, receives metrics of the connection pool of
+	// each opened database, such as the number of open and in-use
+	// connections and the time spent waiting for one.
+	MeterProvider metric.MeterProvider
+	// AddHealthCheck, if set, is called with a health check of each opened
+	// database, which pings it. For example, pass the Add method of a
+	// *health.Handler.
+	AddHealthCheck func(health.Checker)
 }
 
 // Scheme is the URL scheme awspostgres registers its URLOpener under on
@@ -63,35 +140,234 @@ func init() {
 
 // OpenPostgresURL opens a new RDS database connection wrapped with OpenCensus instrumentation.
 func (uo *URLOpener) OpenPostgresURL(ctx context.Context, u *url.URL) (*sql.DB, error) {
+	cfg, err := uo.dbConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	traceOpts := append([]ocsql.TraceOption(nil), uo.TraceOpts...)
+	var c driver.Connector
+	switch cfg.driver {
+	case "", "pq":
+		c = connector{
+			dialer:    cfg.dialer,
+			pqConn:    cfg.connURL.String(),
+			traceOpts: traceOpts,
+			tokens:    cfg.tokens,
+			creds:     cfg.creds,
+		}
+	case "pgx":
+		connConfig, err := cfg.pgxConfig()
+		if err != nil {
+			cfg.creds.Close()
+			return nil, err
+		}
+		var opts []stdlib.OptionOpenDB
+		if cfg.tokens != nil || cfg.creds != nil {
+			opts = append(opts, stdlib.OptionBeforeConnect(pgxBeforeConnect(cfg.creds, cfg.tokens)))
+		}
+		c = pgxConnector{stdlib.GetConnector(*connConfig, opts...), traceOpts}
+	default:
+		cfg.creds.Close()
+		return nil, fmt.Errorf("awspostgres: open: unknown driver %q; want pq or pgx", cfg.driver)
+	}
+	if cfg.failover != nil {
+		c = failoverConnector{c, cfg.failover}
+	}
+	db := sql.OpenDB(cfg.creds.Wrap(c, isAuthError))
+	if err := sqlstats.Instrument(db, "postgresql", u.Host, uo.MeterProvider, uo.AddHealthCheck); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	return db, nil
+}
+
+// OpenPgxPoolURL opens a native pgx connection pool for a URL of the form
+// that OpenPostgresURL accepts. The "driver" URL parameter is ignored, and
+// pgxpool's "pool_*" parameters, such as "pool_max_conns", configure the
+// pool. The pool is not instrumented with OpenCensus, MeterProvider or
+// AddHealthCheck, does not handle failovers, and does not support the
+// "credentials_url" parameter.
+func (uo *URLOpener) OpenPgxPoolURL(ctx context.Context, u *url.URL) (*pgxpool.Pool, error) {
+	if u.Query().Has("credentials_url") {
+		return nil, fmt.Errorf("awspostgres: open: credentials_url is not supported for pgx pools")
+	}
+	cfg, err := uo.dbConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig, err := pgxpool.ParseConfig(cfg.connURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	poolConfig.ConnConfig.DialFunc = cfg.dialer.dial
+	if cfg.tokens != nil {
+		poolConfig.BeforeConnect = pgxBeforeConnect(nil, cfg.tokens)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	return pool, nil
+}
+
+// dbConfig is the configuration of a database connection, parsed from a URL
+// by URLOpener.dbConfig.
+type dbConfig struct {
+	dialer   dialer
+	tokens   *rds.AuthTokenGenerator
+	creds    *sqlcreds.Source
+	failover *rds.Failover // nil to connect only once
+	// driver is the value of the "driver" URL parameter.
+	driver string
+	// connURL is the URL for the driver. Its TLS is disabled because the
+	// dialer provides it.
+	connURL *url.URL
+}
+
+func (uo *URLOpener) dbConfig(ctx context.Context, u *url.URL) (*dbConfig, error) {
 	source := uo.CertSource
 	if source == nil {
 		source = new(rds.CertFetcher)
 	}
 
-	query := u.Query()
+	tokens, query, err := rds.AuthTokenGeneratorFromURLParams(ctx, u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	if uo.AuthTokens != nil {
+		tokens = uo.AuthTokens
+	}
+	endpoint, query, err := rds.EndpointOptionsFromURLParams(query)
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	tlsOpts, query, err := sqltls.FromURLParams(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
 	for k := range query {
 		// Forbid SSL-related parameters.
 		if k == "sslmode" || k == "sslcert" || k == "sslkey" || k == "sslrootcert" {
-			return nil, fmt.Errorf("awspostgres: open: parameter %q not allowed; sslmode must be disabled because the underlying dialer is already providing TLS", k)
+			return nil, fmt.Errorf("awspostgres: open: parameter %q not allowed; sslmode must be disabled because the underlying dialer is already providing TLS; use the tls_* parameters to configure it", k)
 		}
 	}
+	driverName := query.Get("driver")
+	query.Del("driver")
 	// sslmode must be disabled because the underlying dialer is already providing TLS.
 	query.Set("sslmode", "disable")
 
 	u2 := new(url.URL)
 	*u2 = *u
 	u2.Scheme = "postgres"
+	if u2.Host, err = endpoint.Host(u.Host); err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	creds, query, err := sqlcreds.FromURLParams(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	if creds != nil && tokens != nil {
+		creds.Close()
+		return nil, fmt.Errorf("awspostgres: open: credentials_url cannot be used with IAM authentication")
+	}
 	u2.RawQuery = query.Encode()
-	db := sql.OpenDB(connector{
-		provider:  source,
-		pqConn:    u2.String(),
-		traceOpts: append([]ocsql.TraceOption(nil), uo.TraceOpts...),
-	})
-	return db, nil
+	return &dbConfig{
+		dialer:   dialer{endpoint.CertPoolProvider(u2.Host, source), tlsOpts},
+		tokens:   tokens,
+		creds:    creds,
+		failover: endpoint.Failover(u2.Host, readOnlyQuery),
+		driver:   driverName,
+		connURL:  u2,
+	}, nil
 }
 
+// pgxConfig returns the pgx configuration of cfg, which dials with
+// cfg.dialer.
+func (cfg *dbConfig) pgxConfig() (*pgx.ConnConfig, error) {
+	connConfig, err := pgx.ParseConfig(cfg.connURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("awspostgres: open: %v", err)
+	}
+	connConfig.DialFunc = cfg.dialer.dial
+	return connConfig, nil
+}
+
+// pgxBeforeConnect returns a pgx BeforeConnect hook that sets the user name
+// and password of each new connection to the credentials from creds, or the
+// password to an IAM authentication token from tokens. Either may be nil.
+func pgxBeforeConnect(creds *sqlcreds.Source, tokens *rds.AuthTokenGenerator) func(context.Context, *pgx.ConnConfig) error {
+	return func(ctx context.Context, cfg *pgx.ConnConfig) error {
+		c, err := creds.Credentials(ctx)
+		if err != nil {
+			return fmt.Errorf("awspostgres: %v", err)
+		}
+		if c.User != "" {
+			cfg.User = c.User
+		}
+		if c.Password != "" {
+			cfg.Password = c.Password
+		}
+		if tokens == nil {
+			return nil
+		}
+		addr := net.JoinHostPort(cfg.Host, strconv.Itoa(int(cfg.Port)))
+		token, err := tokens.AuthToken(ctx, addr, cfg.User)
+		if err != nil {
+			return fmt.Errorf("awspostgres: %v", err)
+		}
+		cfg.Password = token
+		return nil
+	}
+}
+
+// pgxConnector wraps the connections of a pgx connector with OpenCensus
+// instrumentation.
+type pgxConnector struct {
+	driver.Connector
+	traceOpts []ocsql.TraceOption
+}
+
+func (c pgxConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ocsql.WrapConn(conn, c.traceOpts...), nil
+}
+
+// failoverConnector opens connections through a Failover.
+type failoverConnector struct {
+	driver.Connector
+	failover *rds.Failover
+}
+
+func (c failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.failover.Connect(ctx, c.Connector.Connect)
+}
+
+// isAuthError reports whether err is the error of either driver for a
+// wrong user name or password.
+func isAuthError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return isAuthErrorCode(string(pqErr.Code))
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && isAuthErrorCode(pgErr.Code)
+}
+
+// isAuthErrorCode reports whether code is the SQLSTATE of
+// invalid_password or invalid_authorization_specification.
+func isAuthErrorCode(code string) bool {
+	return code == "28P01" || code == "28000"
+}
+
+// readOnlyQuery returns whether a PostgreSQL instance is read-only. Aurora
+// readers are in recovery.
+const readOnlyQuery = "SELECT pg_is_in_recovery()"
+
 type pqDriver struct {
-	provider  rds.CertPoolProvider
+	dialer    dialer
 	traceOpts []ocsql.TraceOption
 }
 
@@ -101,29 +377,75 @@ func (d pqDriver) Open(name string) (driver.Conn, error) {
 }
 
 func (d pqDriver) OpenConnector(name string) (driver.Connector, error) {
-	return connector{d.provider, name + " sslmode=disable", d.traceOpts}, nil
+	return connector{d.dialer, name + " sslmode=disable", d.traceOpts, nil, nil}, nil
 }
 
 type connector struct {
-	provider  rds.CertPoolProvider
+	dialer    dialer
 	pqConn    string
 	traceOpts []ocsql.TraceOption
+	// tokens, if not nil, generates the password for each connection. pqConn
+	// must then be a URL.
+	tokens *rds.AuthTokenGenerator
+	// creds, if not nil, provides the user name and password for each
+	// connection. pqConn must then be a URL.
+	creds *sqlcreds.Source
 }
 
-func (c connector) Connect(context.Context) (driver.Conn, error) {
-	conn, err := pq.DialOpen(dialer{c.provider}, c.pqConn)
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	pqConn, err := c.connString(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := pq.DialOpen(c.dialer, pqConn)
 	if err != nil {
 		return nil, err
 	}
 	return ocsql.WrapConn(conn, c.traceOpts...), nil
 }
 
+// connString returns the connection string for a new connection, with the
+// credentials from the secret store, or a fresh IAM authentication token as
+// its password, if c uses them.
+func (c connector) connString(ctx context.Context) (string, error) {
+	if c.tokens == nil && c.creds == nil {
+		return c.pqConn, nil
+	}
+	u, err := url.Parse(c.pqConn)
+	if err != nil {
+		return "", fmt.Errorf("awspostgres: parse connection URL: %v", err)
+	}
+	if c.creds != nil {
+		creds, err := c.creds.Credentials(ctx)
+		if err != nil {
+			return "", fmt.Errorf("awspostgres: %v", err)
+		}
+		user := creds.User
+		if user == "" {
+			user = u.User.Username()
+		}
+		u.User = url.UserPassword(user, creds.Password)
+		return u.String(), nil
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "5432")
+	}
+	token, err := c.tokens.AuthToken(ctx, addr, u.User.Username())
+	if err != nil {
+		return "", fmt.Errorf("awspostgres: %v", err)
+	}
+	u.User = url.UserPassword(u.User.Username(), token)
+	return u.String(), nil
+}
+
 func (c connector) Driver() driver.Driver {
-	return pqDriver{c.provider, c.traceOpts}
+	return pqDriver{c.dialer, c.traceOpts}
 }
 
 type dialer struct {
 	provider rds.CertPoolProvider
+	tls      *sqltls.Options
 }
 
 func (d dialer) dial(ctx context.Context, network, address string) (net.Conn, error) {
@@ -131,9 +453,11 @@ func (d dialer) dial(ctx context.Context, network, address string) (net.Conn, er
 	if err != nil {
 		return nil, fmt.Errorf("awspostgres: parse address: %v", err)
 	}
-	certPool, err := d.provider.RDSCertPool(ctx)
-	if err != nil {
-		return nil, err
+	var certPool *x509.CertPool
+	if !d.tls.HasRootCAs() {
+		if certPool, err = d.provider.RDSCertPool(ctx); err != nil {
+			return nil, err
+		}
 	}
 	conn, err := new(net.Dialer).DialContext(ctx, network, address)
 	if err != nil {
@@ -162,11 +486,9 @@ func (d dialer) dial(ctx context.Context, network, address string) (net.Conn, er
 	}
 
 	// Begin TLS communication.
-	crypt := tls.Client(conn, &tls.Config{
-		ServerName:    host,
-		RootCAs:       certPool,
-		Renegotiation: tls.RenegotiateFreelyAsClient,
-	})
+	tlsConfig := d.tls.Config(host, certPool)
+	tlsConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+	crypt := tls.Client(conn, tlsConfig)
 	if err := crypt.Handshake(); err != nil {
 		return nil, err
 	}
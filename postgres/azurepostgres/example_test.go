@@ -0,0 +1,42 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurepostgres_test
+
+import (
+	"context"
+	"log"
+
+	"gocloud.dev/postgres"
+	_ "gocloud.dev/postgres/azurepostgres"
+)
+
+func Example() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/postgres/azurepostgres"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// Replace this with your actual settings. With "aad_auth=true", the
+	// password is a Microsoft Entra ID access token for the user.
+	db, err := postgres.Open(ctx,
+		"azurepostgres://user@example00.postgres.database.azure.com/testdb?aad_auth=true")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	// Use database in your program.
+	db.ExecContext(ctx, "CREATE TABLE foo (bar INT);")
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localpostgres provides PostgreSQL databases for local development
+// and continuous integration, under the same URL-opening API as
+// awspostgres, azurepostgres and gcppostgres, so that code that calls
+// postgres.Open runs unchanged with a local URL.
+//
+// # URLs
+//
+// For postgres.Open, localpostgres registers for the scheme
+// "localpostgres". To customize the URL opener, or for more details on the
+// URL format, see URLOpener.
+//
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Docker
+//
+// A URL without a host, such as "localpostgres://postgres:password@/mydb",
+// starts a PostgreSQL server in a new Docker container, using the docker
+// command, and waits for it to accept connections. The server has the user,
+// password and database in the URL; without a password, it trusts all
+// connections. The container is removed when the *sql.DB is closed. A URL
+// with a host connects to a server that is already running, such as a CI
+// service container, with TLS disabled unless "sslmode" is set.
+//
+// # SQLite
+//
+// The URL parameter "sqlite" opens a SQLite database file instead, with no
+// server, as in "localpostgres:///?sqlite=dev.db". A SQLite database/sql
+// driver, such as modernc.org/sqlite or github.com/mattn/go-sqlite3, must be
+// linked in. Queries that use PostgreSQL-specific SQL do not work with
+// SQLite.
+package localpostgres // import "gocloud.dev/postgres/localpostgres"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+
+	"contrib.go.opencensus.io/integrations/ocsql"
+	"github.com/lib/pq"
+	"gocloud.dev/internal/localdb"
+	"gocloud.dev/postgres"
+)
+
+// Scheme is the URL scheme localpostgres registers its URLOpener under on
+// postgres.DefaultMux.
+const Scheme = "localpostgres"
+
+// DefaultImage is the Docker image of the PostgreSQL servers that URLOpener
+// starts by default.
+const DefaultImage = "postgres:16"
+
+func init() {
+	postgres.DefaultURLMux().RegisterPostgres(Scheme, &URLOpener{})
+}
+
+// URLOpener opens local PostgreSQL URLs like
+// "localpostgres://postgres:password@/mydb".
+//
+// The URL's user defaults to "postgres". The following query parameters are
+// supported; others are passed to the lib/pq driver, as in
+// postgres.URLOpener:
+//   - image: the Docker image of the server; defaults to URLOpener.Image.
+//   - ready_timeout: how long to wait for a new server to accept
+//     connections, such as "30s"; defaults to 2 minutes.
+//   - sqlite: the path of a SQLite database file to open instead.
+type URLOpener struct {
+	// Image is the Docker image of the servers that the opener starts. If
+	// empty, DefaultImage is used.
+	Image string
+	// TraceOpts contains options for OpenCensus.
+	TraceOpts []ocsql.TraceOption
+}
+
+// OpenPostgresURL opens a local PostgreSQL database, starting a server for
+// it if the URL has no host, wrapped with OpenCensus instrumentation.
+func (uo *URLOpener) OpenPostgresURL(ctx context.Context, u *url.URL) (*sql.DB, error) {
+	image := uo.Image
+	if image == "" {
+		image = DefaultImage
+	}
+	params, query, err := localdb.ParamsFromURL(u.Query(), image)
+	if err != nil {
+		return nil, fmt.Errorf("localpostgres: open: %v", err)
+	}
+	if params.SQLite != "" {
+		db, err := localdb.OpenSQLite(params.SQLite)
+		if err != nil {
+			return nil, fmt.Errorf("localpostgres: open: %v", err)
+		}
+		return db, nil
+	}
+	if query.Get("sslmode") == "" {
+		query.Set("sslmode", "disable")
+	}
+
+	u2 := new(url.URL)
+	*u2 = *u
+	u2.Scheme = "postgres"
+	u2.RawQuery = query.Encode()
+	if u2.User.Username() == "" {
+		u2.User = url.User("postgres")
+		if password, ok := u.User.Password(); ok {
+			u2.User = url.UserPassword("postgres", password)
+		}
+	}
+	var container *localdb.Container
+	if u2.Host == "" {
+		if container, err = localdb.Start(ctx, params.Image, 5432, containerEnv(u2)); err != nil {
+			return nil, fmt.Errorf("localpostgres: open: %v", err)
+		}
+		u2.Host = container.Addr
+	}
+	pc, err := pq.NewConnector(u2.String())
+	if err != nil {
+		if container != nil {
+			container.Close()
+		}
+		return nil, fmt.Errorf("localpostgres: open: %v", err)
+	}
+
+	var c driver.Connector = connector{pc, append([]ocsql.TraceOption(nil), uo.TraceOpts...)}
+	if container == nil {
+		return sql.OpenDB(c), nil
+	}
+	db := sql.OpenDB(container.Wrap(c))
+	if err := localdb.Wait(ctx, db, params.ReadyTimeout); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("localpostgres: open: %v", err)
+	}
+	return db, nil
+}
+
+// containerEnv returns the environment variables that configure the
+// PostgreSQL image to create the user and database in u.
+func containerEnv(u *url.URL) map[string]string {
+	env := map[string]string{"POSTGRES_USER": u.User.Username()}
+	if db := u.Path; len(db) > 1 {
+		env["POSTGRES_DB"] = db[1:]
+	}
+	if password, _ := u.User.Password(); password != "" {
+		env["POSTGRES_PASSWORD"] = password
+	} else {
+		env["POSTGRES_HOST_AUTH_METHOD"] = "trust"
+	}
+	return env
+}
+
+// connector wraps the connections of a lib/pq connector with OpenCensus
+// instrumentation.
+type connector struct {
+	driver.Connector
+	traceOpts []ocsql.TraceOption
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ocsql.WrapConn(conn, c.traceOpts...), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesis
+
+import (
+	"context"
+	"sync"
+)
+
+// ShardEnd is the checkpoint of a shard that has been closed by resharding
+// and whose records have all been acked.
+const ShardEnd = "SHARD_END"
+
+// CheckpointStore stores the progress of a Subscription through the shards
+// of a stream. Implementations backed by durable storage, such as a DynamoDB
+// table or a docstore collection, let a Subscription resume where a previous
+// one stopped.
+//
+// A Subscription calls the methods of the store concurrently for different
+// shards, and in order for each shard.
+type CheckpointStore interface {
+	// Checkpoint returns the checkpoint of the shard of the stream: the
+	// sequence number of the last acked record, or ShardEnd. It returns ""
+	// with a nil error if the shard has no checkpoint.
+	Checkpoint(ctx context.Context, stream, shardID string) (string, error)
+
+	// SetCheckpoint stores the checkpoint of the shard of the stream.
+	SetCheckpoint(ctx context.Context, stream, shardID, checkpoint string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore that keeps the checkpoints in
+// memory. It can be shared by Subscriptions in the same process, for
+// example to resume reading a stream after a Subscription is shut down.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[[2]string]string // by stream and shard ID
+}
+
+// NewMemoryCheckpointStore returns an empty *MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[[2]string]string{}}
+}
+
+// Checkpoint implements CheckpointStore.Checkpoint.
+func (s *MemoryCheckpointStore) Checkpoint(_ context.Context, stream, shardID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[[2]string{stream, shardID}], nil
+}
+
+// SetCheckpoint implements CheckpointStore.SetCheckpoint.
+func (s *MemoryCheckpointStore) SetCheckpoint(_ context.Context, stream, shardID, checkpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[[2]string{stream, shardID}] = checkpoint
+	return nil
+}
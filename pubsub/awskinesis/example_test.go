@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesis_test
+
+import (
+	"context"
+	"log"
+
+	awsv2cfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/awskinesis"
+)
+
+func ExampleOpenTopic() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// Establish a AWS V2 Config.
+	// See https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/ for more info.
+	cfg, err := awsv2cfg.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Create a *pubsub.Topic.
+	client := kinesis.NewFromConfig(cfg)
+	topic := awskinesis.OpenTopic(client, "mystream", nil)
+	defer topic.Shutdown(ctx)
+}
+
+func ExampleOpenSubscription() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// Establish a AWS V2 Config.
+	// See https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/ for more info.
+	cfg, err := awsv2cfg.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Construct a *pubsub.Subscription that reads all the records of the
+	// stream. Use a durable CheckpointStore to resume where the
+	// Subscription stopped after a restart.
+	client := kinesis.NewFromConfig(cfg)
+	subscription := awskinesis.OpenSubscription(client, "mystream", &awskinesis.SubscriptionOptions{
+		InitialPosition: awskinesis.InitialPositionTrimHorizon,
+	})
+	defer subscription.Shutdown(ctx)
+}
+
+func Example_openTopicFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/pubsub/awskinesis"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// pubsub.OpenTopic creates a *pubsub.Topic from a URL.
+	// This URL will open the stream "mystream" in the region "us-east-2".
+	topic, err := pubsub.OpenTopic(ctx, "kinesis://mystream?region=us-east-2")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer topic.Shutdown(ctx)
+}
+
+func Example_openSubscriptionFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/pubsub/awskinesis"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// pubsub.OpenSubscription creates a *pubsub.Subscription from a URL.
+	// This URL will read the stream "mystream" in the region "us-east-2",
+	// starting with its oldest records.
+	subscription, err := pubsub.OpenSubscription(ctx,
+		"kinesis://mystream?region=us-east-2&initial_position=trim_horizon")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer subscription.Shutdown(ctx)
+}
@@ -0,0 +1,961 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awskinesis provides an implementation of pubsub that uses AWS
+// Kinesis Data Streams. Use OpenTopic to construct a *pubsub.Topic that puts
+// records into a stream, and/or OpenSubscription to construct a
+// *pubsub.Subscription that reads the records of all the shards of a stream.
+//
+// # URLs
+//
+// For pubsub.OpenTopic and pubsub.OpenSubscription, awskinesis registers
+// for the scheme "kinesis".
+// The default URL opener will use the default AWS SDK V2 configuration from
+// the environment.
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # Ordering
+//
+// Message.OrderingKey is sent as the record's partition key, which selects
+// its shard; messages without one get a random partition key. A Subscription
+// receives the records of each shard in order. When the stream is resharded,
+// a Subscription finishes reading a parent shard, and checkpoints its end,
+// before it reads the child shards, so records with the same partition key
+// stay in order. The OrderingKey of a received message is the one it was
+// sent with or, for Raw Subscriptions, the partition key of the record.
+//
+// # Checkpoints
+//
+// Kinesis does not track which records have been read. A Subscription
+// stores its progress through each shard in a CheckpointStore: the sequence
+// number of the last record such that it and all the records before it in
+// the shard have been acked. A Subscription opened with the same store
+// resumes after the checkpoints; see SubscriptionOptions.Checkpoints.
+//
+// # Message Delivery Semantics
+//
+// Kinesis supports at-least-once semantics through checkpoints; applications
+// must call Message.Ack after processing a message, or it will be delivered
+// again by the next Subscription that resumes from the checkpoint store.
+// Message.Nack is not supported: Message.Nackable will return false, and
+// Message.Nack will panic if called.
+// See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
+// for more background.
+//
+// A Subscription reads every shard of the stream; it does not share the
+// shards with other processes. Use one Subscription, with its own checkpoint
+// store, per application that consumes the stream.
+//
+// # Escaping
+//
+// Kinesis records have no attributes, so by default the message Metadata and
+// Body are encoded together in the record's data as a JSON object with the
+// fields "metadata", an object of strings, "body", a base64-encoded string,
+// and "ordering_key", the OrderingKey of the message if it has one. Set TopicOptions.Raw and SubscriptionOptions.Raw to put and read
+// the Body alone, dropping the Metadata, for example to exchange records with
+// applications that do not use the Go CDK. A Subscription that is not Raw
+// delivers records that are not encoded as JSON objects with a "body" field
+// as they are, with the partition key as the OrderingKey.
+//
+// # As
+//
+// awskinesis exposes the following types for As:
+//   - Topic: *kinesis.Client
+//   - Subscription: *kinesis.Client
+//   - Message: types.Record
+//   - Message.BeforeSend: *types.PutRecordsRequestEntry
+//   - Message.AfterSend: types.PutRecordsResultEntry
+//   - Error: any error type returned by the service, notably smithy.APIError
+package awskinesis // import "gocloud.dev/pubsub/awskinesis"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/smithy-go"
+	"github.com/googleapis/gax-go/v2"
+	gcaws "gocloud.dev/aws"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/retry"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/batcher"
+	"gocloud.dev/pubsub/driver"
+)
+
+var sendBatcherOpts = &batcher.Options{
+	MaxBatchSize: 500, // PutRecords supports 500 records at a time
+	MaxHandlers:  100, // max concurrency for sends
+	// PutRecords supports 5 MiB at a time, including partition keys; leave
+	// room for the base64 encoding of bodies.
+	MaxBatchByteSize: 3 << 20,
+}
+
+var recvBatcherOpts = &batcher.Options{
+	// GetRecords returns at most 10000 records at a time.
+	MaxBatchSize: 10000,
+	// Shards are read by one ReceiveBatch at a time, to keep their records
+	// in order.
+	MaxHandlers: 1,
+}
+
+const (
+	// defaultPollInterval is the default for
+	// SubscriptionOptions.PollInterval.
+	defaultPollInterval = time.Second
+	// defaultShardRefreshInterval is the default for
+	// SubscriptionOptions.ShardRefreshInterval.
+	defaultShardRefreshInterval = time.Minute
+	// minShardPollInterval is the minimum delay between GetRecords calls
+	// to a shard, which supports 5 of them per second.
+	minShardPollInterval = 200 * time.Millisecond
+)
+
+func init() {
+	pubsub.DefaultURLMux().RegisterTopic(Scheme, new(URLOpener))
+	pubsub.DefaultURLMux().RegisterSubscription(Scheme, new(URLOpener))
+}
+
+// Scheme is the URL scheme awskinesis registers its URLOpeners under on
+// pubsub.DefaultMux.
+const Scheme = "kinesis"
+
+// URLOpener opens Kinesis URLs like "kinesis://mystream" for topics and
+// subscriptions. The URL's host+path is the stream name or, for a stream
+// ARN, leave the host blank and put the ARN in the path (e.g.,
+// "kinesis:///arn:aws:kinesis:us-east-2:123456789012:stream/mystream").
+//
+// See gocloud.dev/aws/V2ConfigFromURLParams for supported query parameters
+// for overriding the AWS SDK V2 configuration.
+//
+// In addition, the following query parameters are supported:
+//   - raw: sets TopicOptions.Raw and SubscriptionOptions.Raw. The value must
+//     be parseable by `strconv.ParseBool`.
+//   - initial_position (for Subscriptions only): sets
+//     SubscriptionOptions.InitialPosition; "latest" or "trim_horizon".
+//   - poll_interval (for Subscriptions only): sets
+//     SubscriptionOptions.PollInterval, in time.ParseDuration formats.
+type URLOpener struct {
+	// TopicOptions specifies the options to pass to OpenTopic.
+	TopicOptions TopicOptions
+	// SubscriptionOptions specifies the options to pass to OpenSubscription.
+	SubscriptionOptions SubscriptionOptions
+}
+
+// OpenTopicURL opens a pubsub.Topic based on u.
+func (o *URLOpener) OpenTopicURL(ctx context.Context, u *url.URL) (*pubsub.Topic, error) {
+	opts := o.TopicOptions
+	q := u.Query()
+	if rawStr := q.Get("raw"); rawStr != "" {
+		var err error
+		opts.Raw, err = strconv.ParseBool(rawStr)
+		if err != nil {
+			return nil, fmt.Errorf("open topic %v: invalid value %q for raw: %v", u, rawStr, err)
+		}
+		q.Del("raw")
+	}
+	cfg, err := gcaws.V2ConfigFromURLParams(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("open topic %v: %v", u, err)
+	}
+	return OpenTopic(kinesis.NewFromConfig(cfg), streamFromURL(u), &opts), nil
+}
+
+// OpenSubscriptionURL opens a pubsub.Subscription based on u.
+func (o *URLOpener) OpenSubscriptionURL(ctx context.Context, u *url.URL) (*pubsub.Subscription, error) {
+	opts := o.SubscriptionOptions
+	q := u.Query()
+	if rawStr := q.Get("raw"); rawStr != "" {
+		var err error
+		opts.Raw, err = strconv.ParseBool(rawStr)
+		if err != nil {
+			return nil, fmt.Errorf("open subscription %v: invalid value %q for raw: %v", u, rawStr, err)
+		}
+		q.Del("raw")
+	}
+	if posStr := q.Get("initial_position"); posStr != "" {
+		switch posStr {
+		case "latest":
+			opts.InitialPosition = InitialPositionLatest
+		case "trim_horizon":
+			opts.InitialPosition = InitialPositionTrimHorizon
+		default:
+			return nil, fmt.Errorf("open subscription %v: invalid value %q for initial_position", u, posStr)
+		}
+		q.Del("initial_position")
+	}
+	if pollStr := q.Get("poll_interval"); pollStr != "" {
+		var err error
+		opts.PollInterval, err = time.ParseDuration(pollStr)
+		if err != nil {
+			return nil, fmt.Errorf("open subscription %v: invalid value %q for poll_interval: %v", u, pollStr, err)
+		}
+		q.Del("poll_interval")
+	}
+	cfg, err := gcaws.V2ConfigFromURLParams(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("open subscription %v: %v", u, err)
+	}
+	return OpenSubscription(kinesis.NewFromConfig(cfg), streamFromURL(u), &opts), nil
+}
+
+// streamFromURL returns the stream name or ARN of u. The leading "/" is
+// trimmed if the host is empty, so that "kinesis:///arn:..." gives "arn:...".
+func streamFromURL(u *url.URL) string {
+	return strings.TrimPrefix(path.Join(u.Host, u.Path), "/")
+}
+
+// stream identifies a stream by name or ARN in requests.
+type stream string
+
+func (s stream) name() *string {
+	if strings.HasPrefix(string(s), "arn:") {
+		return nil
+	}
+	return aws.String(string(s))
+}
+
+func (s stream) arn() *string {
+	if strings.HasPrefix(string(s), "arn:") {
+		return aws.String(string(s))
+	}
+	return nil
+}
+
+// envelope is the encoding of a message in the data of a record, unless
+// the Topic or Subscription is Raw.
+type envelope struct {
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Body        []byte            `json:"body"`
+	OrderingKey string            `json:"ordering_key,omitempty"`
+}
+
+// TopicOptions sets options for constructing a *pubsub.Topic backed by
+// Kinesis.
+type TopicOptions struct {
+	// Raw puts the message Body alone in the record's data, and drops the
+	// Metadata. See the package documentation for details.
+	Raw bool
+
+	// BatcherOptions adds constraints to the default batching done for sends.
+	BatcherOptions batcher.Options
+}
+
+type topic struct {
+	client *kinesis.Client
+	stream stream
+	opts   TopicOptions
+}
+
+// OpenTopic returns a *pubsub.Topic that puts records into the stream with
+// the given name or ARN.
+func OpenTopic(client *kinesis.Client, streamNameOrARN string, opts *TopicOptions) *pubsub.Topic {
+	dt := openTopic(client, streamNameOrARN, opts)
+	bo := sendBatcherOpts.NewMergedOptions(&dt.opts.BatcherOptions)
+	return pubsub.NewTopic(dt, bo)
+}
+
+// openTopic returns the driver for OpenTopic. This function exists so the test
+// harness can get the driver interface implementation if it needs to.
+func openTopic(client *kinesis.Client, streamNameOrARN string, opts *TopicOptions) *topic {
+	if opts == nil {
+		opts = &TopicOptions{}
+	}
+	return &topic{client: client, stream: stream(streamNameOrARN), opts: *opts}
+}
+
+// randomPartitionKey returns a partition key for a message without an
+// OrderingKey, to spread such messages across the shards.
+func randomPartitionKey() string {
+	return strconv.FormatUint(rand.Uint64(), 36)
+}
+
+// SendBatch implements driver.Topic.SendBatch.
+func (t *topic) SendBatch(ctx context.Context, dms []*driver.Message) error {
+	entries := make([]types.PutRecordsRequestEntry, 0, len(dms))
+	for _, dm := range dms {
+		data := dm.Body
+		if !t.opts.Raw {
+			var err error
+			data, err = json.Marshal(envelope{Metadata: dm.Metadata, Body: dm.Body, OrderingKey: dm.OrderingKey})
+			if err != nil {
+				return err
+			}
+		}
+		key := dm.OrderingKey
+		if key == "" {
+			key = randomPartitionKey()
+		}
+		entry := types.PutRecordsRequestEntry{Data: data, PartitionKey: aws.String(key)}
+		if dm.BeforeSend != nil {
+			asFunc := func(i interface{}) bool {
+				if p, ok := i.(**types.PutRecordsRequestEntry); ok {
+					*p = &entry
+					return true
+				}
+				return false
+			}
+			if err := dm.BeforeSend(asFunc); err != nil {
+				return err
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	// PutRecords may put only some of the records, for example if a shard
+	// is throttled; put the others again, with backoff.
+	results := make([]types.PutRecordsResultEntry, len(entries))
+	remaining := make([]int, len(entries)) // indexes of the records to put
+	for i := range remaining {
+		remaining[i] = i
+	}
+	err := retry.Call(ctx, gax.Backoff{}, isRetryablePutError, func() error {
+		req := &kinesis.PutRecordsInput{StreamName: t.stream.name(), StreamARN: t.stream.arn()}
+		for _, i := range remaining {
+			req.Records = append(req.Records, entries[i])
+		}
+		resp, err := t.client.PutRecords(ctx, req)
+		if err != nil {
+			return err
+		}
+		if len(resp.Records) != len(remaining) {
+			return fmt.Errorf("awskinesis: PutRecords returned %d results for %d records", len(resp.Records), len(remaining))
+		}
+		var failed []int
+		var failure *putRecordError
+		for j, r := range resp.Records {
+			i := remaining[j]
+			if r.ErrorCode == nil {
+				results[i] = r
+				continue
+			}
+			failed = append(failed, i)
+			if failure == nil || failure.retryable() {
+				failure = &putRecordError{code: aws.ToString(r.ErrorCode), message: aws.ToString(r.ErrorMessage)}
+			}
+		}
+		remaining = failed
+		if failure != nil {
+			return failure
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, dm := range dms {
+		if dm.AfterSend != nil {
+			asFunc := func(i2 interface{}) bool {
+				if p, ok := i2.(*types.PutRecordsResultEntry); ok {
+					*p = results[i]
+					return true
+				}
+				return false
+			}
+			if err := dm.AfterSend(asFunc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// putRecordError is the error of a record that PutRecords did not put.
+type putRecordError struct {
+	code, message string
+}
+
+func (e *putRecordError) Error() string {
+	return fmt.Sprintf("awskinesis: record not put: %s: %s", e.code, e.message)
+}
+
+// ErrorCode implements smithy.APIError, so the error is handled like the
+// errors of requests.
+func (e *putRecordError) ErrorCode() string { return e.code }
+
+// ErrorMessage implements smithy.APIError.
+func (e *putRecordError) ErrorMessage() string { return e.message }
+
+// ErrorFault implements smithy.APIError.
+func (e *putRecordError) ErrorFault() smithy.ErrorFault { return smithy.FaultUnknown }
+
+func (e *putRecordError) retryable() bool {
+	return e.code == "ProvisionedThroughputExceededException" || e.code == "InternalFailure"
+}
+
+// isRetryablePutError reports whether the records of a partially failed
+// PutRecords call should be put again. Failed requests have already been
+// retried by the AWS SDK.
+func isRetryablePutError(err error) bool {
+	var pe *putRecordError
+	return errors.As(err, &pe) && pe.retryable()
+}
+
+// IsRetryable implements driver.Topic.IsRetryable.
+func (*topic) IsRetryable(error) bool {
+	// The client handles retries.
+	return false
+}
+
+// As implements driver.Topic.As.
+func (t *topic) As(i interface{}) bool {
+	c, ok := i.(**kinesis.Client)
+	if !ok {
+		return false
+	}
+	*c = t.client
+	return true
+}
+
+// ErrorAs implements driver.Topic.ErrorAs.
+func (*topic) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Topic.ErrorCode.
+func (*topic) ErrorCode(err error) gcerrors.ErrorCode {
+	return errorCode(err)
+}
+
+// Close implements driver.Topic.Close.
+func (*topic) Close() error { return nil }
+
+// InitialPosition is where a Subscription starts reading the shards that
+// have no checkpoint.
+type InitialPosition int
+
+const (
+	// InitialPositionLatest reads the records put after the Subscription
+	// was opened.
+	InitialPositionLatest InitialPosition = iota
+	// InitialPositionTrimHorizon reads all the records in the stream,
+	// starting with the oldest.
+	InitialPositionTrimHorizon
+)
+
+// SubscriptionOptions sets options for constructing a *pubsub.Subscription
+// backed by Kinesis.
+type SubscriptionOptions struct {
+	// Checkpoints stores the progress of the Subscription through the shards
+	// of the stream. If nil, a new in-memory store is used, so the
+	// Subscription starts at InitialPosition.
+	Checkpoints CheckpointStore
+
+	// InitialPosition is where the shards that have no checkpoint are read
+	// from. Shards that are created by resharding after the Subscription has
+	// started reading their parents are always read from their first record.
+	InitialPosition InitialPosition
+
+	// Raw reads the record's data as the message Body, without decoding
+	// Metadata. See the package documentation for details.
+	Raw bool
+
+	// PollInterval is how long to wait before reading again from a shard
+	// that had no new records. If zero, it defaults to one second.
+	PollInterval time.Duration
+
+	// ShardRefreshInterval is how often the shards of the stream are listed
+	// to find the shards created by resharding. The shards are also listed
+	// when a shard is closed. If zero, it defaults to one minute.
+	ShardRefreshInterval time.Duration
+}
+
+// OpenSubscription returns a *pubsub.Subscription that reads the records of
+// the stream with the given name or ARN.
+func OpenSubscription(client *kinesis.Client, streamNameOrARN string, opts *SubscriptionOptions) *pubsub.Subscription {
+	return pubsub.NewSubscription(openSubscription(client, streamNameOrARN, opts), recvBatcherOpts, nil)
+}
+
+// openSubscription returns a driver.Subscription.
+func openSubscription(client *kinesis.Client, streamNameOrARN string, opts *SubscriptionOptions) *subscription {
+	o := SubscriptionOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Checkpoints == nil {
+		o.Checkpoints = NewMemoryCheckpointStore()
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	if o.ShardRefreshInterval <= 0 {
+		o.ShardRefreshInterval = defaultShardRefreshInterval
+	}
+	return &subscription{
+		client:   client,
+		stream:   stream(streamNameOrARN),
+		opts:     o,
+		openTime: time.Now(),
+		shards:   map[string]*shard{},
+	}
+}
+
+type subscription struct {
+	client   *kinesis.Client
+	stream   stream
+	opts     SubscriptionOptions
+	openTime time.Time // the start of InitialPositionLatest
+
+	// The fields below are only used by ReceiveBatch, which is not called
+	// concurrently.
+	listed      bool      // whether the shards have been listed
+	nextRefresh time.Time // when to list the shards again
+	next        int       // the index in order of the next shard to read
+
+	mu     sync.Mutex        // protects the shards' pending records and states
+	shards map[string]*shard // by ID
+	order  []*shard          // in the order they were listed
+}
+
+// A shard is the state of the Subscription in a shard of the stream.
+type shard struct {
+	id      string
+	parents []string // the IDs of the parent shards
+	// discovered is true if the shard was first listed after the
+	// Subscription started reading, so it was created by resharding.
+	discovered bool
+
+	started      bool   // whether the shard's starting position is known
+	iterator     string // the shard iterator, or "" if it must be obtained
+	lastSeq      string // the sequence number of the last record read
+	checkpointed bool   // whether the shard has had a sequence checkpoint
+	ended        bool   // whether all the records of the shard have been read
+	finished     bool   // whether the end of the shard is checkpointed
+	notBefore    time.Time
+
+	// pending holds the records that have been read but whose checkpoint has
+	// not been stored, in order.
+	pending []*record
+	// cpMu serializes the checkpoints of the shard, so they are stored in
+	// order.
+	cpMu sync.Mutex
+}
+
+// A record is the AckID of a message.
+type record struct {
+	shard *shard
+	seq   string
+	acked bool
+}
+
+// refreshShards lists the shards of the stream, if it is time to.
+func (s *subscription) refreshShards(ctx context.Context) error {
+	now := time.Now()
+	if s.listed && now.Before(s.nextRefresh) {
+		return nil
+	}
+	var listed []types.Shard
+	req := &kinesis.ListShardsInput{StreamName: s.stream.name(), StreamARN: s.stream.arn()}
+	for {
+		resp, err := s.client.ListShards(ctx, req)
+		if err != nil {
+			return err
+		}
+		listed = append(listed, resp.Shards...)
+		if resp.NextToken == nil {
+			break
+		}
+		// NextToken cannot be combined with the stream.
+		req = &kinesis.ListShardsInput{NextToken: resp.NextToken}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ls := range listed {
+		id := aws.ToString(ls.ShardId)
+		if _, ok := s.shards[id]; ok {
+			continue
+		}
+		sh := &shard{id: id, discovered: s.listed}
+		for _, p := range []*string{ls.ParentShardId, ls.AdjacentParentShardId} {
+			if p != nil {
+				sh.parents = append(sh.parents, *p)
+			}
+		}
+		s.shards[id] = sh
+		s.order = append(s.order, sh)
+	}
+	s.listed = true
+	s.nextRefresh = now.Add(s.opts.ShardRefreshInterval)
+	return nil
+}
+
+// readable reports whether sh can be read now: it has not ended, and its
+// parents, if they are still in the stream, have been finished.
+// s.mu must be held.
+func (s *subscription) readable(sh *shard, now time.Time) bool {
+	if sh.ended || now.Before(sh.notBefore) {
+		return false
+	}
+	for _, id := range sh.parents {
+		if p, ok := s.shards[id]; ok && !p.finished {
+			return false
+		}
+	}
+	return true
+}
+
+// start reads the checkpoint of sh. It marks sh finished if it has nothing
+// left to read.
+func (s *subscription) start(ctx context.Context, sh *shard) error {
+	cp, err := s.opts.Checkpoints.Checkpoint(ctx, string(s.stream), sh.id)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh.started = true
+	switch cp {
+	case "":
+	case ShardEnd:
+		sh.ended, sh.finished = true, true
+	default:
+		sh.lastSeq, sh.checkpointed = cp, true
+	}
+	return nil
+}
+
+// parentCheckpointed reports whether a parent of sh has been checkpointed,
+// so sh must be read from its first record to continue from it.
+// s.mu must be held.
+func (s *subscription) parentCheckpointed(sh *shard) bool {
+	for _, id := range sh.parents {
+		if p, ok := s.shards[id]; ok && p.checkpointed {
+			return true
+		}
+	}
+	return false
+}
+
+// iterator returns a new shard iterator for sh, starting after the last
+// record read.
+func (s *subscription) iterator(ctx context.Context, sh *shard) (string, error) {
+	req := &kinesis.GetShardIteratorInput{
+		StreamName: s.stream.name(),
+		StreamARN:  s.stream.arn(),
+		ShardId:    aws.String(sh.id),
+	}
+	s.mu.Lock()
+	switch {
+	case sh.lastSeq != "":
+		req.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		req.StartingSequenceNumber = aws.String(sh.lastSeq)
+	case s.opts.InitialPosition == InitialPositionLatest && !sh.discovered && !s.parentCheckpointed(sh):
+		// Iterate from the time the Subscription was opened, rather than
+		// from LATEST, so that the records put after then are read even if
+		// the shard is first read later, or has been closed since. Shards
+		// closed before then have no records to read, and end at once.
+		req.ShardIteratorType = types.ShardIteratorTypeAtTimestamp
+		req.Timestamp = aws.Time(s.openTime)
+	default:
+		req.ShardIteratorType = types.ShardIteratorTypeTrimHorizon
+	}
+	s.mu.Unlock()
+	resp, err := s.client.GetShardIterator(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(resp.ShardIterator), nil
+}
+
+// ReceiveBatch implements driver.Subscription.ReceiveBatch. It reads the
+// shards that can be read, in turn, until it has read some records, or
+// waits until a shard can be read again.
+func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	if err := s.refreshShards(ctx); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	s.mu.Lock()
+	var candidates []*shard
+	for i := range s.order {
+		sh := s.order[(s.next+i)%len(s.order)]
+		if s.readable(sh, now) {
+			candidates = append(candidates, sh)
+		}
+	}
+	if len(s.order) > 0 {
+		s.next = (s.next + 1) % len(s.order)
+	}
+	s.mu.Unlock()
+
+	var dms []*driver.Message
+	for _, sh := range candidates {
+		if len(dms) >= maxMessages {
+			break
+		}
+		shardDMs, err := s.read(ctx, sh, maxMessages-len(dms))
+		if err != nil {
+			return nil, err
+		}
+		dms = append(dms, shardDMs...)
+	}
+	if len(dms) == 0 {
+		// When we return no messages and no error, the portable type will
+		// call ReceiveBatch again immediately. Wait for a shard to be
+		// readable to avoid spinning.
+		s.wait(ctx)
+	}
+	return dms, nil
+}
+
+// wait waits until a shard may be readable, or until the shards are to be
+// listed again.
+func (s *subscription) wait(ctx context.Context) {
+	until := s.nextRefresh
+	s.mu.Lock()
+	for _, sh := range s.order {
+		if !sh.ended && sh.notBefore.Before(until) {
+			until = sh.notBefore
+		}
+	}
+	s.mu.Unlock()
+	d := time.Until(until)
+	if d < minShardPollInterval {
+		d = minShardPollInterval
+	}
+	if d > s.opts.PollInterval {
+		d = s.opts.PollInterval
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+// read reads up to maxMessages records from sh.
+func (s *subscription) read(ctx context.Context, sh *shard, maxMessages int) ([]*driver.Message, error) {
+	if !sh.started {
+		if err := s.start(ctx, sh); err != nil {
+			return nil, err
+		}
+		if sh.ended {
+			return nil, nil
+		}
+	}
+	if sh.iterator == "" {
+		it, err := s.iterator(ctx, sh)
+		if err != nil {
+			return nil, err
+		}
+		sh.iterator = it
+	}
+	if maxMessages > 10000 {
+		maxMessages = 10000
+	}
+	resp, err := s.client.GetRecords(ctx, &kinesis.GetRecordsInput{
+		ShardIterator: aws.String(sh.iterator),
+		StreamARN:     s.stream.arn(),
+		Limit:         aws.Int32(int32(maxMessages)),
+	})
+	if err != nil {
+		var ee *types.ExpiredIteratorException
+		if errors.As(err, &ee) {
+			// Get a new iterator, after the last record read, next time.
+			sh.iterator = ""
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dms := make([]*driver.Message, 0, len(resp.Records))
+	s.mu.Lock()
+	for _, r := range resp.Records {
+		r := r
+		rec := &record{shard: sh, seq: aws.ToString(r.SequenceNumber)}
+		sh.pending = append(sh.pending, rec)
+		sh.lastSeq = rec.seq
+		dm := &driver.Message{
+			LoggableID:  rec.seq,
+			Body:        r.Data,
+			OrderingKey: aws.ToString(r.PartitionKey),
+			AckID:       rec,
+			AsFunc: func(i interface{}) bool {
+				p, ok := i.(*types.Record)
+				if !ok {
+					return false
+				}
+				*p = r
+				return true
+			},
+		}
+		if !s.opts.Raw {
+			var env envelope
+			if err := json.Unmarshal(r.Data, &env); err == nil && env.Body != nil {
+				dm.Body, dm.Metadata, dm.OrderingKey = env.Body, env.Metadata, env.OrderingKey
+			}
+		}
+		dms = append(dms, dm)
+	}
+	now := time.Now()
+	if len(dms) > 0 {
+		sh.notBefore = now.Add(minShardPollInterval)
+	} else {
+		sh.notBefore = now.Add(s.opts.PollInterval)
+	}
+	sh.iterator = aws.ToString(resp.NextShardIterator)
+	ended := resp.NextShardIterator == nil
+	if ended {
+		// The shard is closed and all its records have been read. List the
+		// shards to find its children.
+		sh.ended = true
+		s.nextRefresh = now
+	}
+	s.mu.Unlock()
+	if ended {
+		if err := s.checkpoint(ctx, sh); err != nil {
+			return nil, err
+		}
+	}
+	return dms, nil
+}
+
+// checkpoint stores the checkpoint of sh after its acked records, and its
+// end if it has ended and all its records have been acked.
+func (s *subscription) checkpoint(ctx context.Context, sh *shard) error {
+	sh.cpMu.Lock()
+	defer sh.cpMu.Unlock()
+	s.mu.Lock()
+	var seq string
+	n := 0
+	for n < len(sh.pending) && sh.pending[n].acked {
+		seq = sh.pending[n].seq
+		n++
+	}
+	sh.pending = sh.pending[n:]
+	end := sh.ended && !sh.finished && len(sh.pending) == 0
+	s.mu.Unlock()
+
+	if seq != "" {
+		if err := s.opts.Checkpoints.SetCheckpoint(ctx, string(s.stream), sh.id, seq); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		sh.checkpointed = true
+		s.mu.Unlock()
+	}
+	if end {
+		if err := s.opts.Checkpoints.SetCheckpoint(ctx, string(s.stream), sh.id, ShardEnd); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		sh.finished = true
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// SendAcks implements driver.Subscription.SendAcks. It stores the
+// checkpoints of the shards of the records.
+func (s *subscription) SendAcks(ctx context.Context, ids []driver.AckID) error {
+	var shards []*shard
+	s.mu.Lock()
+	for _, id := range ids {
+		rec := id.(*record)
+		rec.acked = true
+		shards = append(shards, rec.shard)
+	}
+	s.mu.Unlock()
+	done := map[*shard]bool{}
+	for _, sh := range shards {
+		if done[sh] {
+			continue
+		}
+		done[sh] = true
+		if err := s.checkpoint(ctx, sh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanNack implements driver.CanNack.
+func (*subscription) CanNack() bool { return false }
+
+// SendNacks implements driver.Subscription.SendNacks.
+func (*subscription) SendNacks(ctx context.Context, ids []driver.AckID) error {
+	panic("unreachable")
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable.
+func (*subscription) IsRetryable(error) bool {
+	// The client handles retries.
+	return false
+}
+
+// As implements driver.Subscription.As.
+func (s *subscription) As(i interface{}) bool {
+	c, ok := i.(**kinesis.Client)
+	if !ok {
+		return false
+	}
+	*c = s.client
+	return true
+}
+
+// ErrorAs implements driver.Subscription.ErrorAs.
+func (*subscription) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Subscription.ErrorCode.
+func (*subscription) ErrorCode(err error) gcerrors.ErrorCode {
+	return errorCode(err)
+}
+
+// Close implements driver.Subscription.Close.
+func (*subscription) Close() error { return nil }
+
+func errorCode(err error) gcerrors.ErrorCode {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return gcerrors.Unknown
+	}
+	ec, ok := errorCodeMap[ae.ErrorCode()]
+	if !ok {
+		return gcerrors.Unknown
+	}
+	return ec
+}
+
+var errorCodeMap = map[string]gcerrors.ErrorCode{
+	"AccessDeniedException":                  gcerrors.PermissionDenied,
+	"ExpiredIteratorException":               gcerrors.FailedPrecondition,
+	"ExpiredNextTokenException":              gcerrors.FailedPrecondition,
+	"InternalFailure":                        gcerrors.Internal,
+	"InvalidArgumentException":               gcerrors.InvalidArgument,
+	"KMSAccessDeniedException":               gcerrors.PermissionDenied,
+	"KMSDisabledException":                   gcerrors.FailedPrecondition,
+	"KMSInvalidStateException":               gcerrors.FailedPrecondition,
+	"KMSNotFoundException":                   gcerrors.NotFound,
+	"KMSOptInRequired":                       gcerrors.FailedPrecondition,
+	"KMSThrottlingException":                 gcerrors.ResourceExhausted,
+	"LimitExceededException":                 gcerrors.ResourceExhausted,
+	"ProvisionedThroughputExceededException": gcerrors.ResourceExhausted,
+	"ResourceInUseException":                 gcerrors.FailedPrecondition,
+	"ResourceNotFoundException":              gcerrors.NotFound,
+	"ValidationException":                    gcerrors.InvalidArgument,
+}
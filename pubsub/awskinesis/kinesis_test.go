@@ -0,0 +1,730 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskinesis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+	"gocloud.dev/pubsub/drivertest"
+)
+
+// fakeKinesis is an in-memory Kinesis server that implements the operations
+// used by the driver.
+type fakeKinesis struct {
+	mu      sync.Mutex
+	streams map[string]*fakeStream
+	nextSeq int
+	// failPuts is the number of records that PutRecords fails to put, with
+	// ProvisionedThroughputExceededException.
+	failPuts int
+}
+
+type fakeStream struct {
+	shards []*fakeShard
+}
+
+type fakeShard struct {
+	id, parent, adjacentParent string
+	closed                     bool
+	records                    []fakeRecord
+}
+
+type fakeRecord struct {
+	seq, key string
+	data     []byte
+	at       time.Time
+}
+
+func newFakeKinesis() *fakeKinesis {
+	return &fakeKinesis{streams: map[string]*fakeStream{}}
+}
+
+// createStream creates a stream with n shards.
+func (f *fakeKinesis) createStream(name string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := &fakeStream{}
+	for i := 0; i < n; i++ {
+		st.shards = append(st.shards, &fakeShard{id: fmt.Sprintf("shardId-%012d", i)})
+	}
+	f.streams[name] = st
+}
+
+func (f *fakeKinesis) deleteStream(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.streams, name)
+}
+
+// splitShard closes the shard with the given ID and creates two children.
+func (f *fakeKinesis) splitShard(stream, shardID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := f.streams[stream]
+	for _, sh := range st.shards {
+		if sh.id == shardID {
+			sh.closed = true
+		}
+	}
+	for i := 0; i < 2; i++ {
+		st.shards = append(st.shards, &fakeShard{id: fmt.Sprintf("shardId-%012d", len(st.shards)), parent: shardID})
+	}
+}
+
+// mergeShards closes the two shards and creates their child.
+func (f *fakeKinesis) mergeShards(stream, shardID, adjacentShardID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := f.streams[stream]
+	for _, sh := range st.shards {
+		if sh.id == shardID || sh.id == adjacentShardID {
+			sh.closed = true
+		}
+	}
+	st.shards = append(st.shards, &fakeShard{
+		id:             fmt.Sprintf("shardId-%012d", len(st.shards)),
+		parent:         shardID,
+		adjacentParent: adjacentShardID,
+	})
+}
+
+// openShards returns the shards that records can be put into.
+func (st *fakeStream) openShards() []*fakeShard {
+	var open []*fakeShard
+	for _, sh := range st.shards {
+		if !sh.closed {
+			open = append(open, sh)
+		}
+	}
+	return open
+}
+
+func (st *fakeStream) shard(id string) *fakeShard {
+	for _, sh := range st.shards {
+		if sh.id == id {
+			return sh
+		}
+	}
+	return nil
+}
+
+type fakeError struct {
+	status int
+	typ    string
+}
+
+func (f *fakeKinesis) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Kinesis_20131202.")
+	f.mu.Lock()
+	resp, ferr := f.handle(op, req)
+	f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	if ferr != nil {
+		w.WriteHeader(ferr.status)
+		json.NewEncoder(w).Encode(map[string]string{"__type": ferr.typ, "message": ferr.typ})
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func field[T any](req map[string]json.RawMessage, name string) T {
+	var v T
+	json.Unmarshal(req[name], &v)
+	return v
+}
+
+func (f *fakeKinesis) stream(req map[string]json.RawMessage) (string, *fakeStream, *fakeError) {
+	name := field[string](req, "StreamName")
+	if arn := field[string](req, "StreamARN"); arn != "" {
+		name = arn[strings.LastIndex(arn, "/")+1:]
+	}
+	st, ok := f.streams[name]
+	if !ok {
+		return "", nil, &fakeError{http.StatusBadRequest, "ResourceNotFoundException"}
+	}
+	return name, st, nil
+}
+
+func (f *fakeKinesis) handle(op string, req map[string]json.RawMessage) (interface{}, *fakeError) {
+	switch op {
+	case "ListShards":
+		_, st, ferr := f.stream(req)
+		if ferr != nil {
+			return nil, ferr
+		}
+		var shards []map[string]interface{}
+		for _, sh := range st.shards {
+			rng := map[string]interface{}{"StartingSequenceNumber": "0"}
+			if sh.closed {
+				rng["EndingSequenceNumber"] = strconv.Itoa(f.nextSeq)
+			}
+			s := map[string]interface{}{"ShardId": sh.id, "SequenceNumberRange": rng}
+			if sh.parent != "" {
+				s["ParentShardId"] = sh.parent
+			}
+			if sh.adjacentParent != "" {
+				s["AdjacentParentShardId"] = sh.adjacentParent
+			}
+			shards = append(shards, s)
+		}
+		return map[string]interface{}{"Shards": shards}, nil
+
+	case "PutRecords":
+		_, st, ferr := f.stream(req)
+		if ferr != nil {
+			return nil, ferr
+		}
+		var results []map[string]interface{}
+		failed := 0
+		for _, e := range field[[]struct {
+			Data         []byte
+			PartitionKey string
+		}](req, "Records") {
+			if f.failPuts > 0 {
+				f.failPuts--
+				failed++
+				results = append(results, map[string]interface{}{
+					"ErrorCode":    "ProvisionedThroughputExceededException",
+					"ErrorMessage": "slow down",
+				})
+				continue
+			}
+			open := st.openShards()
+			h := fnv.New32a()
+			h.Write([]byte(e.PartitionKey))
+			sh := open[int(h.Sum32())%len(open)]
+			f.nextSeq++
+			seq := fmt.Sprintf("%020d", f.nextSeq)
+			sh.records = append(sh.records, fakeRecord{seq: seq, key: e.PartitionKey, data: e.Data, at: time.Now()})
+			results = append(results, map[string]interface{}{"SequenceNumber": seq, "ShardId": sh.id})
+		}
+		return map[string]interface{}{"FailedRecordCount": failed, "Records": results}, nil
+
+	case "GetShardIterator":
+		name, st, ferr := f.stream(req)
+		if ferr != nil {
+			return nil, ferr
+		}
+		sh := st.shard(field[string](req, "ShardId"))
+		if sh == nil {
+			return nil, &fakeError{http.StatusBadRequest, "ResourceNotFoundException"}
+		}
+		pos := 0
+		switch types.ShardIteratorType(field[string](req, "ShardIteratorType")) {
+		case types.ShardIteratorTypeTrimHorizon:
+		case types.ShardIteratorTypeLatest:
+			pos = len(sh.records)
+		case types.ShardIteratorTypeAtTimestamp:
+			ts := time.UnixMilli(int64(field[float64](req, "Timestamp") * 1000))
+			for pos < len(sh.records) && sh.records[pos].at.Before(ts) {
+				pos++
+			}
+		case types.ShardIteratorTypeAfterSequenceNumber:
+			seq := field[string](req, "StartingSequenceNumber")
+			for pos < len(sh.records) && sh.records[pos].seq <= seq {
+				pos++
+			}
+		default:
+			return nil, &fakeError{http.StatusBadRequest, "InvalidArgumentException"}
+		}
+		return map[string]string{"ShardIterator": fmt.Sprintf("%s|%s|%d", name, sh.id, pos)}, nil
+
+	case "GetRecords":
+		parts := strings.Split(field[string](req, "ShardIterator"), "|")
+		if len(parts) != 3 {
+			return nil, &fakeError{http.StatusBadRequest, "InvalidArgumentException"}
+		}
+		st, ok := f.streams[parts[0]]
+		if !ok {
+			return nil, &fakeError{http.StatusBadRequest, "ResourceNotFoundException"}
+		}
+		sh := st.shard(parts[1])
+		pos, _ := strconv.Atoi(parts[2])
+		limit := field[int](req, "Limit")
+		var records []map[string]interface{}
+		for ; pos < len(sh.records) && (limit == 0 || len(records) < limit); pos++ {
+			r := sh.records[pos]
+			records = append(records, map[string]interface{}{
+				"SequenceNumber":              r.seq,
+				"PartitionKey":                r.key,
+				"Data":                        r.data,
+				"ApproximateArrivalTimestamp": float64(r.at.UnixMilli()) / 1000,
+			})
+		}
+		resp := map[string]interface{}{"Records": records, "MillisBehindLatest": 0}
+		if !sh.closed || pos < len(sh.records) {
+			resp["NextShardIterator"] = fmt.Sprintf("%s|%s|%d", parts[0], parts[1], pos)
+		}
+		return resp, nil
+	}
+	return nil, &fakeError{http.StatusBadRequest, "UnknownOperationException"}
+}
+
+// newFakeClient starts a fake Kinesis server, and returns a client for it.
+func newFakeClient(t *testing.T) (*fakeKinesis, *kinesis.Client) {
+	f := newFakeKinesis()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := kinesis.New(kinesis.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		Region:       "us-east-2",
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	return f, client
+}
+
+type harness struct {
+	fake   *fakeKinesis
+	client *kinesis.Client
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	f, client := newFakeClient(t)
+	return &harness{fake: f, client: client}, nil
+}
+
+func streamForTest(testName string) string {
+	return strings.ReplaceAll(testName, "/", "_")
+}
+
+func (h *harness) CreateTopic(ctx context.Context, testName string) (driver.Topic, func(), error) {
+	name := streamForTest(testName)
+	h.fake.createStream(name, 2)
+	return openTopic(h.client, name, nil), func() { h.fake.deleteStream(name) }, nil
+}
+
+func (h *harness) MakeNonexistentTopic(ctx context.Context) (driver.Topic, error) {
+	return openTopic(h.client, "nonexistent-stream", nil), nil
+}
+
+func (h *harness) CreateSubscription(ctx context.Context, dt driver.Topic, testName string) (driver.Subscription, func(), error) {
+	ds := openSubscription(h.client, string(dt.(*topic).stream), &SubscriptionOptions{PollInterval: 50 * time.Millisecond})
+	return ds, func() {}, nil
+}
+
+func (h *harness) MakeNonexistentSubscription(ctx context.Context) (driver.Subscription, func(), error) {
+	return openSubscription(h.client, "nonexistent-stream", nil), func() {}, nil
+}
+
+func (h *harness) Close() {}
+
+func (h *harness) MaxBatchSizes() (int, int) { return sendBatcherOpts.MaxBatchSize, 0 }
+
+func (*harness) SupportsMultipleSubscriptions() bool { return true }
+
+func TestConformance(t *testing.T) {
+	asTests := []drivertest.AsTest{awsAsTest{}}
+	drivertest.RunConformanceTests(t, newHarness, asTests)
+}
+
+type awsAsTest struct{}
+
+func (awsAsTest) Name() string {
+	return "aws test"
+}
+
+func (awsAsTest) TopicCheck(topic *pubsub.Topic) error {
+	var c *kinesis.Client
+	if !topic.As(&c) {
+		return fmt.Errorf("cast failed for %T", &c)
+	}
+	return nil
+}
+
+func (awsAsTest) SubscriptionCheck(sub *pubsub.Subscription) error {
+	var c *kinesis.Client
+	if !sub.As(&c) {
+		return fmt.Errorf("cast failed for %T", &c)
+	}
+	return nil
+}
+
+func (awsAsTest) TopicErrorCheck(t *pubsub.Topic, err error) error {
+	var ae smithy.APIError
+	if !t.ErrorAs(err, &ae) {
+		return fmt.Errorf("failed to convert %v (%T) to a smithy.APIError", err, err)
+	}
+	if got, want := ae.ErrorCode(), "ResourceNotFoundException"; got != want {
+		return fmt.Errorf("got %q, want %q", got, want)
+	}
+	return nil
+}
+
+func (awsAsTest) SubscriptionErrorCheck(s *pubsub.Subscription, err error) error {
+	var ae smithy.APIError
+	if !s.ErrorAs(err, &ae) {
+		return fmt.Errorf("failed to convert %v (%T) to a smithy.APIError", err, err)
+	}
+	if got, want := ae.ErrorCode(), "ResourceNotFoundException"; got != want {
+		return fmt.Errorf("got %q, want %q", got, want)
+	}
+	return nil
+}
+
+func (awsAsTest) MessageCheck(m *pubsub.Message) error {
+	var r types.Record
+	if !m.As(&r) {
+		return fmt.Errorf("cast failed for %T", &r)
+	}
+	return nil
+}
+
+func (awsAsTest) BeforeSend(as func(interface{}) bool) error {
+	var e *types.PutRecordsRequestEntry
+	if !as(&e) {
+		return fmt.Errorf("cast failed for %T", &e)
+	}
+	return nil
+}
+
+func (awsAsTest) AfterSend(as func(interface{}) bool) error {
+	var r types.PutRecordsResultEntry
+	if !as(&r) {
+		return fmt.Errorf("cast failed for %T", &r)
+	}
+	if r.SequenceNumber == nil {
+		return errors.New("got a result without a sequence number")
+	}
+	return nil
+}
+
+// receiveBodies receives and acks n messages from sub, and returns their
+// bodies.
+func receiveBodies(ctx context.Context, t *testing.T, sub *pubsub.Subscription, n int) []string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var got []string
+	for len(got) < n {
+		m, err := sub.Receive(ctx)
+		if err != nil {
+			t.Fatalf("after %v: %v", got, err)
+		}
+		got = append(got, string(m.Body))
+		m.Ack()
+	}
+	return got
+}
+
+func sendBodies(ctx context.Context, t *testing.T, topic *pubsub.Topic, orderingKey string, bodies ...string) {
+	t.Helper()
+	for _, b := range bodies {
+		if err := topic.Send(ctx, &pubsub.Message{Body: []byte(b), OrderingKey: orderingKey}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResharding(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	topic := OpenTopic(client, "s", nil)
+	defer topic.Shutdown(ctx)
+	store := NewMemoryCheckpointStore()
+	sub := OpenSubscription(client, "s", &SubscriptionOptions{
+		Checkpoints:          store,
+		PollInterval:         10 * time.Millisecond,
+		ShardRefreshInterval: 10 * time.Millisecond,
+	})
+	defer sub.Shutdown(ctx)
+
+	// The records of a parent shard are read before those of its children,
+	// even though the children's records are put first.
+	sendBodies(ctx, t, topic, "k", "1", "2")
+	f.splitShard("s", "shardId-000000000000")
+	sendBodies(ctx, t, topic, "k", "3", "4")
+	f.mergeShards("s", "shardId-000000000001", "shardId-000000000002")
+	sendBodies(ctx, t, topic, "k", "5")
+	if diff := cmp.Diff(receiveBodies(ctx, t, sub, 5), []string{"1", "2", "3", "4", "5"}); diff != "" {
+		t.Errorf("received bodies (-got +want):\n%s", diff)
+	}
+
+	// Once all their records are acked, the closed shards are checkpointed
+	// at their end.
+	for _, id := range []string{"shardId-000000000000", "shardId-000000000001", "shardId-000000000002"} {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			cp, err := store.Checkpoint(ctx, "s", id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cp == ShardEnd {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: got checkpoint %q, want %q", id, cp, ShardEnd)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	topic := OpenTopic(client, "s", nil)
+	defer topic.Shutdown(ctx)
+	sendBodies(ctx, t, topic, "", "1", "2", "3")
+
+	store := NewMemoryCheckpointStore()
+	opts := &SubscriptionOptions{
+		Checkpoints:     store,
+		InitialPosition: InitialPositionTrimHorizon,
+		PollInterval:    10 * time.Millisecond,
+	}
+	sub := OpenSubscription(client, "s", opts)
+	if diff := cmp.Diff(receiveBodies(ctx, t, sub, 2), []string{"1", "2"}); diff != "" {
+		t.Errorf("received bodies (-got +want):\n%s", diff)
+	}
+	// Shutdown waits for the acks, and so for the checkpoints.
+	if err := sub.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// A Subscription with the same store resumes after the acked records,
+	// although the first one read ahead the third record.
+	sendBodies(ctx, t, topic, "", "4")
+	sub = OpenSubscription(client, "s", opts)
+	defer sub.Shutdown(ctx)
+	if diff := cmp.Diff(receiveBodies(ctx, t, sub, 2), []string{"3", "4"}); diff != "" {
+		t.Errorf("received bodies (-got +want):\n%s", diff)
+	}
+}
+
+func TestCheckpointsWaitForEarlierAcks(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	topic := OpenTopic(client, "s", nil)
+	defer topic.Shutdown(ctx)
+	sendBodies(ctx, t, topic, "", "1", "2")
+
+	store := NewMemoryCheckpointStore()
+	ds := openSubscription(client, "s", &SubscriptionOptions{Checkpoints: store, InitialPosition: InitialPositionTrimHorizon})
+	var dms []*driver.Message
+	for len(dms) < 2 {
+		got, err := ds.ReceiveBatch(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dms = append(dms, got...)
+	}
+	checkpoint := func() string {
+		cp, err := store.Checkpoint(ctx, "s", "shardId-000000000000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cp
+	}
+
+	// The second record is not checkpointed until the first is acked.
+	if err := ds.SendAcks(ctx, []driver.AckID{dms[1].AckID}); err != nil {
+		t.Fatal(err)
+	}
+	if cp := checkpoint(); cp != "" {
+		t.Errorf("got checkpoint %q, want none", cp)
+	}
+	if err := ds.SendAcks(ctx, []driver.AckID{dms[0].AckID}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := checkpoint(), dms[1].LoggableID; got != want {
+		t.Errorf("got checkpoint %q, want %q", got, want)
+	}
+}
+
+func TestInitialPositionLatest(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	topic := OpenTopic(client, "s", nil)
+	defer topic.Shutdown(ctx)
+	sendBodies(ctx, t, topic, "k", "before")
+	// The closed shard is not read, and its children are read from the time
+	// the Subscription is opened.
+	f.splitShard("s", "shardId-000000000000")
+	sendBodies(ctx, t, topic, "k", "before too")
+	time.Sleep(10 * time.Millisecond)
+
+	sub := OpenSubscription(client, "s", &SubscriptionOptions{PollInterval: 10 * time.Millisecond})
+	defer sub.Shutdown(ctx)
+	sendBodies(ctx, t, topic, "k", "after")
+	if diff := cmp.Diff(receiveBodies(ctx, t, sub, 1), []string{"after"}); diff != "" {
+		t.Errorf("received bodies (-got +want):\n%s", diff)
+	}
+}
+
+func TestPartialPutFailure(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	f.failPuts = 2
+	dt := openTopic(client, "s", nil)
+	var dms []*driver.Message
+	for i := 0; i < 3; i++ {
+		dms = append(dms, &driver.Message{Body: []byte(strconv.Itoa(i))})
+	}
+	if err := dt.SendBatch(ctx, dms); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(f.streams["s"].shards[0].records); got != 3 {
+		t.Errorf("got %d records, want 3", got)
+	}
+}
+
+func TestRaw(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakeClient(t)
+	f.createStream("s", 1)
+	topic := OpenTopic(client, "s", &TopicOptions{Raw: true})
+	defer topic.Shutdown(ctx)
+	sub := OpenSubscription(client, "s", &SubscriptionOptions{Raw: true, PollInterval: 10 * time.Millisecond})
+	defer sub.Shutdown(ctx)
+
+	if err := topic.Send(ctx, &pubsub.Message{Body: []byte("hello"), Metadata: map[string]string{"a": "1"}, OrderingKey: "k"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(f.streams["s"].shards[0].records[0].data); got != "hello" {
+		t.Errorf("got record data %q, want %q", got, "hello")
+	}
+	m, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Ack()
+	if string(m.Body) != "hello" || m.Metadata != nil || m.OrderingKey != "k" {
+		t.Errorf("got %q %v %q, want %q %v %q", m.Body, m.Metadata, m.OrderingKey, "hello", nil, "k")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want gcerrors.ErrorCode
+	}{
+		{&types.ResourceNotFoundException{}, gcerrors.NotFound},
+		{&types.ProvisionedThroughputExceededException{}, gcerrors.ResourceExhausted},
+		{&types.ExpiredIteratorException{}, gcerrors.FailedPrecondition},
+		{&types.AccessDeniedException{}, gcerrors.PermissionDenied},
+		{&putRecordError{code: "InternalFailure"}, gcerrors.Internal},
+		{fmt.Errorf("wrapped: %w", &types.InvalidArgumentException{}), gcerrors.InvalidArgument},
+		{errors.New("other"), gcerrors.Unknown},
+	} {
+		if got := errorCode(test.err); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestStreamFromURL(t *testing.T) {
+	for _, test := range []struct {
+		URL, want string
+	}{
+		{"kinesis://mystream", "mystream"},
+		{"kinesis:///arn:aws:kinesis:us-east-2:123456789012:stream/mystream", "arn:aws:kinesis:us-east-2:123456789012:stream/mystream"},
+	} {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := streamFromURL(u); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.URL, got, test.want)
+		}
+	}
+}
+
+func TestOpenTopicFromURL(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"kinesis://mystream?region=us-east-2", false},
+		// OK, setting raw.
+		{"kinesis://mystream?region=us-east-2&raw=true", false},
+		// Invalid raw.
+		{"kinesis://mystream?region=us-east-2&raw=foo", true},
+		// Invalid parameter.
+		{"kinesis://mystream?region=us-east-2&param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		topic, err := pubsub.OpenTopic(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if topic != nil {
+			topic.Shutdown(ctx)
+		}
+	}
+}
+
+func TestOpenSubscriptionFromURL(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"kinesis://mystream?region=us-east-2", false},
+		// OK, setting the options.
+		{"kinesis://mystream?region=us-east-2&raw=true&initial_position=trim_horizon&poll_interval=5s", false},
+		// Invalid raw.
+		{"kinesis://mystream?region=us-east-2&raw=foo", true},
+		// Invalid initial_position.
+		{"kinesis://mystream?region=us-east-2&initial_position=oldest", true},
+		// Invalid poll_interval.
+		{"kinesis://mystream?region=us-east-2&poll_interval=5", true},
+		// Invalid parameter.
+		{"kinesis://mystream?region=us-east-2&param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		sub, err := pubsub.OpenSubscription(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if sub != nil {
+			sub.Shutdown(ctx)
+		}
+	}
+}
@@ -37,6 +37,19 @@
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// Message.DeliveryAttempt is set from the ApproximateReceiveCount attribute
+// of received messages.
+//
+// # Ordering
+//
+// Message.OrderingKey is sent as the message group ID, which SNS and SQS FIFO
+// topics and queues use to deliver messages in order. FIFO topics and queues
+// also require a deduplication ID for each message; either enable
+// content-based deduplication, or set it with MetadataKeyDeduplicationID.
+// Standard topics and queues do not order messages.
+// The message group ID of a received message is reported in
+// Message.OrderingKey.
+//
 // # Escaping
 //
 // Go CDK supports all UTF-8 strings; to make this work with services lacking
@@ -88,9 +101,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/smithy-go"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/attribute"
 	gcaws "gocloud.dev/aws"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/escape"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/pubsub"
 	"gocloud.dev/pubsub/batcher"
 	"gocloud.dev/pubsub/driver"
@@ -477,13 +492,18 @@ func maybeEncodeBody(body []byte, opt BodyBase64Encoding) (string, bool) {
 //			awssnssqs.MetadataKeyMessageGroupID:  "my-group-id",
 //		},
 //	}
+//
+// MetadataKeyMessageGroupID takes precedence over Message.OrderingKey.
 const (
 	MetadataKeyDeduplicationID = "DeduplicationId"
 	MetadataKeyMessageGroupID  = "MessageGroupId"
 )
 
-// reviseSnsEntryAttributes sets attributes on a [sns.PublishBatchRequestEntry] based on [driver.Message.Metadata].
+// reviseSnsEntryAttributes sets attributes on a [sns.PublishBatchRequestEntry] based on [driver.Message.OrderingKey] and [driver.Message.Metadata].
 func reviseSnsEntryAttributes(dm *driver.Message, entry *sns.PublishBatchRequestEntry) {
+	if dm.OrderingKey != "" {
+		entry.MessageGroupId = aws.String(dm.OrderingKey)
+	}
 	if dedupID, ok := dm.Metadata[MetadataKeyDeduplicationID]; ok {
 		entry.MessageDeduplicationId = aws.String(dedupID)
 	}
@@ -492,8 +512,11 @@ func reviseSnsEntryAttributes(dm *driver.Message, entry *sns.PublishBatchRequest
 	}
 }
 
-// reviseSnsV2EntryAttributes sets attributes on a [snstypesv2.PublishBatchRequestEntry] based on [driver.Message.Metadata].
+// reviseSnsV2EntryAttributes sets attributes on a [snstypesv2.PublishBatchRequestEntry] based on [driver.Message.OrderingKey] and [driver.Message.Metadata].
 func reviseSnsV2EntryAttributes(dm *driver.Message, entry *snstypesv2.PublishBatchRequestEntry) {
+	if dm.OrderingKey != "" {
+		entry.MessageGroupId = aws.String(dm.OrderingKey)
+	}
 	if dedupID, ok := dm.Metadata[MetadataKeyDeduplicationID]; ok {
 		entry.MessageDeduplicationId = aws.String(dedupID)
 	}
@@ -793,8 +816,11 @@ func openSQSTopicV2(ctx context.Context, client *sqsv2.Client, qURL string, opts
 	}
 }
 
-// reviseSqsEntryAttributes sets attributes on a [sqs.SendMessageBatchRequestEntry] based on [driver.Message.Metadata].
+// reviseSqsEntryAttributes sets attributes on a [sqs.SendMessageBatchRequestEntry] based on [driver.Message.OrderingKey] and [driver.Message.Metadata].
 func reviseSqsEntryAttributes(dm *driver.Message, entry *sqs.SendMessageBatchRequestEntry) {
+	if dm.OrderingKey != "" {
+		entry.MessageGroupId = aws.String(dm.OrderingKey)
+	}
 	if dedupID, ok := dm.Metadata[MetadataKeyDeduplicationID]; ok {
 		entry.MessageDeduplicationId = aws.String(dedupID)
 	}
@@ -803,8 +829,11 @@ func reviseSqsEntryAttributes(dm *driver.Message, entry *sqs.SendMessageBatchReq
 	}
 }
 
-// reviseSqsV2EntryAttributes sets attributes on a [sqstypesv2.SendMessageBatchRequestEntry] based on [driver.Message.Metadata].
+// reviseSqsV2EntryAttributes sets attributes on a [sqstypesv2.SendMessageBatchRequestEntry] based on [driver.Message.OrderingKey] and [driver.Message.Metadata].
 func reviseSqsV2EntryAttributes(dm *driver.Message, entry *sqstypesv2.SendMessageBatchRequestEntry) {
+	if dm.OrderingKey != "" {
+		entry.MessageGroupId = aws.String(dm.OrderingKey)
+	}
 	if dedupID, ok := dm.Metadata[MetadataKeyDeduplicationID]; ok {
 		entry.MessageDeduplicationId = aws.String(dedupID)
 	}
@@ -1209,10 +1238,12 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 			}
 
 			m2 := &driver.Message{
-				LoggableID: aws.StringValue(m.MessageId),
-				Body:       b,
-				Metadata:   attrs,
-				AckID:      m.ReceiptHandle,
+				LoggableID:      aws.StringValue(m.MessageId),
+				Body:            b,
+				Metadata:        attrs,
+				OrderingKey:     m.Attributes[string(sqstypesv2.MessageSystemAttributeNameMessageGroupId)],
+				DeliveryAttempt: receiveCount(m.Attributes[string(sqstypesv2.MessageSystemAttributeNameApproximateReceiveCount)]),
+				AckID:           m.ReceiptHandle,
 				AsFunc: func(i interface{}) bool {
 					p, ok := i.(*sqstypesv2.Message)
 					if !ok {
@@ -1273,10 +1304,12 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 			}
 
 			m2 := &driver.Message{
-				LoggableID: aws.StringValue(m.MessageId),
-				Body:       b,
-				Metadata:   attrs,
-				AckID:      m.ReceiptHandle,
+				LoggableID:      aws.StringValue(m.MessageId),
+				Body:            b,
+				Metadata:        attrs,
+				OrderingKey:     aws.StringValue(m.Attributes[sqs.MessageSystemAttributeNameMessageGroupId]),
+				DeliveryAttempt: receiveCount(aws.StringValue(m.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount])),
+				AckID:           m.ReceiptHandle,
 				AsFunc: func(i interface{}) bool {
 					p, ok := i.(**sqs.Message)
 					if !ok {
@@ -1298,6 +1331,13 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 	return ms, nil
 }
 
+// receiveCount returns the delivery attempt of a message from the value of
+// its ApproximateReceiveCount attribute, or 0 if it is missing.
+func receiveCount(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
 func extractBody(bodyStr string, rawAttrs map[string]string, raw bool) (body string, attributes map[string]string) {
 	// If the user told us that message bodies are raw, or if there are
 	// top-level MessageAttributes, then it's raw.
@@ -1454,6 +1494,14 @@ func (*subscription) IsRetryable(error) bool {
 	return false
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (s *subscription) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.MessagingSystemKey.String("aws_sqs"),
+		telemetry.MessagingDestinationNameKey.String(s.qURL),
+	}
+}
+
 // As implements driver.Subscription.As.
 func (s *subscription) As(i interface{}) bool {
 	if s.useV2 {
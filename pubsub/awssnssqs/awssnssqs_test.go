@@ -963,3 +963,45 @@ func testFIFOTopic(t *testing.T, useV2 bool, kind topicKind) {
 		})
 	}
 }
+
+func TestReviseEntryAttributesOrderingKey(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		dm   *driver.Message
+		want string
+	}{
+		{
+			name: "no key",
+			dm:   &driver.Message{},
+		},
+		{
+			name: "OrderingKey",
+			dm:   &driver.Message{OrderingKey: "k"},
+			want: "k",
+		},
+		{
+			name: "metadata takes precedence",
+			dm: &driver.Message{
+				OrderingKey: "k",
+				Metadata:    map[string]string{MetadataKeyMessageGroupID: "g"},
+			},
+			want: "g",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var snsEntry sns.PublishBatchRequestEntry
+			reviseSnsEntryAttributes(tt.dm, &snsEntry)
+			var snsV2Entry snstypesv2.PublishBatchRequestEntry
+			reviseSnsV2EntryAttributes(tt.dm, &snsV2Entry)
+			var sqsEntry sqs.SendMessageBatchRequestEntry
+			reviseSqsEntryAttributes(tt.dm, &sqsEntry)
+			var sqsV2Entry sqstypesv2.SendMessageBatchRequestEntry
+			reviseSqsV2EntryAttributes(tt.dm, &sqsV2Entry)
+			for _, got := range []*string{snsEntry.MessageGroupId, snsV2Entry.MessageGroupId, sqsEntry.MessageGroupId, sqsV2Entry.MessageGroupId} {
+				if aws.StringValue(got) != tt.want {
+					t.Errorf("got MessageGroupId %q, want %q", aws.StringValue(got), tt.want)
+				}
+			}
+		})
+	}
+}
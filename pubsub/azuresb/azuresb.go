@@ -43,6 +43,12 @@
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// ServiceBus only guarantees ordering within sessions, which azuresb does not
+// support, so Message.OrderingKey is ignored; ordering is best-effort.
+//
+// Message.DeliveryAttempt is set from the delivery count of received
+// messages.
+//
 // # As
 //
 // azuresb exposes the following types for As:
@@ -503,11 +509,12 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 			}
 		}
 		messages = append(messages, &driver.Message{
-			LoggableID: sbmsg.MessageID,
-			Body:       sbmsg.Body,
-			Metadata:   metadata,
-			AckID:      sbmsg,
-			AsFunc:     messageAsFunc(sbmsg),
+			LoggableID:      sbmsg.MessageID,
+			Body:            sbmsg.Body,
+			Metadata:        metadata,
+			DeliveryAttempt: int(sbmsg.DeliveryCount),
+			AckID:           sbmsg,
+			AsFunc:          messageAsFunc(sbmsg),
 		})
 	}
 	// Mask rctx timeouts, they are expected if no messages are available.
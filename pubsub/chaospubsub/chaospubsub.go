@@ -0,0 +1,308 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaospubsub provides a *pubsub.Topic and a *pubsub.Subscription
+// that inject faults into the calls made to another Topic or Subscription,
+// so that retries, idempotent message handling and dead-letter logic can be
+// tested. Use NewTopic and NewSubscription to construct them.
+//
+// Each batch of messages sent or received is delayed by Options.Latency,
+// and fails with probability Options.ErrorRate. A batch of messages is sent
+// only in part with probability Options.PartialWriteRate: only a random
+// number of its first messages, possibly none, are sent, and the batch
+// fails. Since the Topic retries batches that fail with retryable errors,
+// the messages that were sent are then sent again.
+//
+// A message received by a Subscription is delivered again with probability
+// Options.RedeliveryRate, whether or not the first delivery has been acked,
+// as at-least-once services may do. Acking or nacking any delivery of a
+// message acks or nacks it in the underlying Subscription; acks and nacks
+// of the other deliveries are ignored.
+//
+// Faults are chosen by a pseudo-random source seeded with Options.Seed, so
+// a test that makes the same calls in the same order sees the same faults.
+// Topics and Subscriptions send and receive batches concurrently, so the
+// faults seen by each message may vary between runs unless the test sends
+// and receives one message at a time.
+//
+//	topic := chaospubsub.NewTopic(mempubsub.NewTopic(), &chaospubsub.Options{
+//		Seed:      1,
+//		ErrorRate: 0.1,
+//		ErrorCode: gcerrors.ResourceExhausted,
+//	})
+//
+// Injected errors have the code Options.ErrorCode. The Topic and
+// Subscription retry the calls that fail with them if gcerrors.IsRetryable
+// reports true, which it does unless the code says otherwise, such as
+// gcerrors.NotFound. A Subscription stops receiving after an error that is
+// not retryable.
+//
+// # As
+//
+// chaospubsub exposes the types of the underlying Topic, Subscription and
+// Messages for As.
+package chaospubsub // import "gocloud.dev/pubsub/chaospubsub"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/chaos"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+)
+
+// Options sets the faults injected by the Topics and Subscriptions
+// returned by NewTopic and NewSubscription.
+type Options struct {
+	// Seed seeds the pseudo-random source that chooses the faults.
+	Seed int64
+
+	// Latency delays each call by a fixed amount.
+	Latency time.Duration
+	// LatencyJitter adds a random delay of up to LatencyJitter to each
+	// call.
+	LatencyJitter time.Duration
+
+	// ErrorRate is the probability, from 0 to 1, that a call fails.
+	ErrorRate float64
+	// ErrorCode is the code of injected errors. It defaults to
+	// gcerrors.Internal.
+	ErrorCode gcerrors.ErrorCode
+
+	// PartialWriteRate is the probability, from 0 to 1, that a batch of
+	// messages is sent only in part. It is used by Topics only.
+	PartialWriteRate float64
+
+	// RedeliveryRate is the probability, from 0 to 1, that a received
+	// message is delivered again. It is used by Subscriptions only.
+	RedeliveryRate float64
+}
+
+func newInjector(opts *Options) *chaos.Injector {
+	return chaos.New(chaos.Faults{
+		Seed:          opts.Seed,
+		Latency:       opts.Latency,
+		LatencyJitter: opts.LatencyJitter,
+		ErrorRate:     opts.ErrorRate,
+		ErrorCode:     opts.ErrorCode,
+	})
+}
+
+// isRetryable reports whether err is an injected error that should be
+// retried. Other errors come from the underlying Topic or Subscription,
+// which has already retried them.
+func isRetryable(err error) bool {
+	return chaos.IsFault(err) && gcerrors.IsRetryable(err)
+}
+
+// NewTopic returns a *pubsub.Topic that injects faults into the calls made
+// to t. Shutting down the returned Topic does not shut down t.
+func NewTopic(t *pubsub.Topic, opts *Options) *pubsub.Topic {
+	return pubsub.NewTopic(newTopic(t, opts), nil)
+}
+
+func newTopic(t *pubsub.Topic, opts *Options) *topic {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &topic{t: t, in: newInjector(opts), partialWriteRate: opts.PartialWriteRate}
+}
+
+// topic implements driver.Topic.
+type topic struct {
+	t                *pubsub.Topic
+	in               *chaos.Injector
+	partialWriteRate float64
+}
+
+// SendBatch implements driver.Topic.SendBatch.
+func (t *topic) SendBatch(ctx context.Context, ms []*driver.Message) error {
+	if err := t.in.Before(ctx, "SendBatch"); err != nil {
+		return err
+	}
+	var fault error
+	if len(ms) > 0 && t.in.Chance(t.partialWriteRate) {
+		fault = t.in.Fault("SendBatch")
+		ms = ms[:t.in.Intn(len(ms))]
+	}
+	for _, m := range ms {
+		err := t.t.Send(ctx, &pubsub.Message{
+			LoggableID: m.LoggableID,
+			Body:       m.Body,
+			Metadata:   m.Metadata,
+			BeforeSend: m.BeforeSend,
+			AfterSend:  m.AfterSend,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return fault
+}
+
+// IsRetryable implements driver.Topic.IsRetryable.
+func (t *topic) IsRetryable(err error) bool {
+	return isRetryable(err)
+}
+
+// As implements driver.Topic.As.
+func (t *topic) As(i interface{}) bool {
+	return t.t.As(i)
+}
+
+// ErrorAs implements driver.Topic.ErrorAs.
+func (t *topic) ErrorAs(err error, i interface{}) bool {
+	return t.t.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Topic.ErrorCode.
+func (t *topic) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Topic.Close. It does not shut down the
+// underlying Topic.
+func (t *topic) Close() error {
+	return nil
+}
+
+// NewSubscription returns a *pubsub.Subscription that injects faults into
+// the calls made to s. Shutting down the returned Subscription does not
+// shut down s.
+func NewSubscription(s *pubsub.Subscription, opts *Options) *pubsub.Subscription {
+	return pubsub.NewSubscription(newSubscription(s, opts), nil, nil)
+}
+
+func newSubscription(s *pubsub.Subscription, opts *Options) *subscription {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &subscription{s: s, in: newInjector(opts), redeliveryRate: opts.RedeliveryRate}
+}
+
+// subscription implements driver.Subscription.
+type subscription struct {
+	s              *pubsub.Subscription
+	in             *chaos.Injector
+	redeliveryRate float64
+
+	mu sync.Mutex
+	// redeliveries holds the messages to deliver again.
+	redeliveries []*delivery
+}
+
+// A delivery is a message received from the underlying Subscription. It is
+// the AckID of all the deliveries of the message.
+type delivery struct {
+	m    *pubsub.Message
+	once sync.Once // acks or nacks m
+}
+
+// ReceiveBatch implements driver.Subscription.ReceiveBatch. It returns
+// the messages to deliver again if there are any, or else the next message
+// of the underlying Subscription.
+func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	if err := s.in.Before(ctx, "ReceiveBatch"); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	n := len(s.redeliveries)
+	if n > maxMessages {
+		n = maxMessages
+	}
+	ds := s.redeliveries[:n:n]
+	s.redeliveries = s.redeliveries[n:]
+	s.mu.Unlock()
+
+	if len(ds) == 0 {
+		m, err := s.s.Receive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ds = []*delivery{{m: m}}
+	}
+	var ms []*driver.Message
+	for _, d := range ds {
+		if s.in.Chance(s.redeliveryRate) {
+			s.mu.Lock()
+			s.redeliveries = append(s.redeliveries, d)
+			s.mu.Unlock()
+		}
+		ms = append(ms, &driver.Message{
+			LoggableID: d.m.LoggableID,
+			Body:       d.m.Body,
+			Metadata:   d.m.Metadata,
+			AckID:      d,
+			AsFunc:     d.m.As,
+		})
+	}
+	return ms, nil
+}
+
+// SendAcks implements driver.Subscription.SendAcks.
+func (s *subscription) SendAcks(ctx context.Context, ackIDs []driver.AckID) error {
+	for _, id := range ackIDs {
+		d := id.(*delivery)
+		d.once.Do(d.m.Ack)
+	}
+	return nil
+}
+
+// CanNack implements driver.Subscription.CanNack.
+func (s *subscription) CanNack() bool {
+	return true
+}
+
+// SendNacks implements driver.Subscription.SendNacks. Nacks of messages
+// that the underlying Subscription cannot nack are ignored.
+func (s *subscription) SendNacks(ctx context.Context, ackIDs []driver.AckID) error {
+	for _, id := range ackIDs {
+		d := id.(*delivery)
+		d.once.Do(func() {
+			if d.m.Nackable() {
+				d.m.Nack()
+			}
+		})
+	}
+	return nil
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable.
+func (s *subscription) IsRetryable(err error) bool {
+	return isRetryable(err)
+}
+
+// As implements driver.Subscription.As.
+func (s *subscription) As(i interface{}) bool {
+	return s.s.As(i)
+}
+
+// ErrorAs implements driver.Subscription.ErrorAs.
+func (s *subscription) ErrorAs(err error, i interface{}) bool {
+	return s.s.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Subscription.ErrorCode.
+func (s *subscription) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Subscription.Close. It does not shut down the
+// underlying Subscription.
+func (s *subscription) Close() error {
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaospubsub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+	"gocloud.dev/pubsub/mempubsub"
+)
+
+func TestSendReceive(t *testing.T) {
+	ctx := context.Background()
+	mt := mempubsub.NewTopic()
+	defer mt.Shutdown(ctx)
+	ms := mempubsub.NewSubscription(mt, time.Minute)
+	defer ms.Shutdown(ctx)
+	topic := NewTopic(mt, nil)
+	defer topic.Shutdown(ctx)
+	sub := NewSubscription(ms, nil)
+	defer sub.Shutdown(ctx)
+
+	if err := topic.Send(ctx, &pubsub.Message{Body: []byte("hello"), Metadata: map[string]string{"k": "v"}}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Ack()
+	if string(m.Body) != "hello" || m.Metadata["k"] != "v" {
+		t.Errorf("got %q %v, want the message sent", m.Body, m.Metadata)
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	ctx := context.Background()
+	mt := mempubsub.NewTopic()
+	defer mt.Shutdown(ctx)
+	topic := NewTopic(mt, &Options{ErrorRate: 1, ErrorCode: gcerrors.FailedPrecondition})
+	defer topic.Shutdown(ctx)
+
+	err := topic.Send(ctx, &pubsub.Message{Body: []byte("hello")})
+	if gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestPartialWrite(t *testing.T) {
+	ctx := context.Background()
+	mt := mempubsub.NewTopic()
+	defer mt.Shutdown(ctx)
+	ms := mempubsub.NewSubscription(mt, time.Minute)
+	defer ms.Shutdown(ctx)
+	dt := newTopic(mt, &Options{Seed: 1, PartialWriteRate: 1})
+
+	var batch []*driver.Message
+	for i := 0; i < 5; i++ {
+		batch = append(batch, &driver.Message{Body: []byte(fmt.Sprint(i))})
+	}
+	err := dt.SendBatch(ctx, batch)
+	if err == nil || !dt.IsRetryable(err) {
+		t.Fatalf("got %v, want a retryable error", err)
+	}
+
+	// mempubsub doesn't preserve the order of messages, so compare the set
+	// of messages received with the prefix of the batch that was sent.
+	got := map[string]bool{}
+	for {
+		rctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		m, err := ms.Receive(rctx)
+		cancel()
+		if err != nil {
+			break
+		}
+		m.Ack()
+		got[string(m.Body)] = true
+	}
+	if len(got) >= len(batch) {
+		t.Errorf("got all %d messages, want some not to be sent", len(got))
+	}
+	for i := 0; i < len(got); i++ {
+		if !got[fmt.Sprint(i)] {
+			t.Errorf("got messages %v, want the first %d of the batch", got, len(got))
+			break
+		}
+	}
+}
+
+func TestRedelivery(t *testing.T) {
+	ctx := context.Background()
+	mt := mempubsub.NewTopic()
+	defer mt.Shutdown(ctx)
+	ms := mempubsub.NewSubscription(mt, time.Minute)
+	defer ms.Shutdown(ctx)
+	sub := NewSubscription(ms, &Options{RedeliveryRate: 1})
+	defer sub.Shutdown(ctx)
+
+	if err := mt.Send(ctx, &pubsub.Message{Body: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+	// Every delivery is delivered again, so the message keeps coming back,
+	// even after it is acked.
+	for i := 0; i < 3; i++ {
+		m, err := sub.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(m.Body) != "hello" {
+			t.Errorf("got %q, want hello", m.Body)
+		}
+		m.Ack()
+	}
+}
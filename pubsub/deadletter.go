@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/telemetry"
+	"gocloud.dev/pubsub/driver"
+)
+
+// DefaultMaxDeliveryAttempts is the default for
+// DeadLetterOptions.MaxDeliveryAttempts.
+const DefaultMaxDeliveryAttempts = 5
+
+// DeadLetterOptions sets options for NewDeadLetterSubscription.
+type DeadLetterOptions struct {
+	// MaxDeliveryAttempts is the number of times a message is delivered
+	// before it is dead-lettered: a message that is nacked on its
+	// MaxDeliveryAttempts-th delivery is sent to the dead-letter topic.
+	// If zero, DefaultMaxDeliveryAttempts is used.
+	MaxDeliveryAttempts int
+}
+
+var (
+	deadLetteredCounter = telemetry.Int64Counter(pkgName, "dead_lettered",
+		"Count of messages sent to a dead-letter topic by provider.", "{message}")
+	deadLetterErrorsCounter = telemetry.Int64Counter(pkgName, "dead_letter_errors",
+		"Count of messages that could not be sent to a dead-letter topic by provider.", "{message}")
+)
+
+// NewDeadLetterSubscription returns a *Subscription that receives the
+// messages of s, and sends those that are nacked too often to the
+// dead-letter topic dlt instead of redelivering them.
+//
+// When a message received from the returned Subscription is nacked on its
+// opts.MaxDeliveryAttempts-th delivery, it is sent to dlt with its Body,
+// Metadata and OrderingKey, and then acked in s. If it cannot be sent, it is
+// nacked in s, and is dead-lettered again after its next delivery. Messages
+// of a Subscription that cannot nack, such as at-most-once services, are
+// dead-lettered when they are first nacked, since they cannot be redelivered.
+// Other messages are acked and nacked in s as usual; the returned
+// Subscription's messages can always be nacked.
+//
+// Deliveries are counted by the service: a message's delivery count is its
+// DeliveryAttempt, which drivers set when the service reports it. Nackable
+// messages without a DeliveryAttempt are never dead-lettered; see the
+// driver documentation for which services report it. Services with native
+// dead-letter support, such as GCP Pub/Sub dead-letter topics, SQS redrive
+// policies and Service Bus dead-letter queues, should be configured there
+// instead where possible.
+//
+// The messages sent to dlt and those that could not be sent are counted by
+// the OpenTelemetry metrics "gocdk.pubsub.dead_lettered" and
+// "gocdk.pubsub.dead_letter_errors", by the provider of s.
+//
+// Shutting down the returned Subscription does not shut down s or dlt.
+func NewDeadLetterSubscription(s *Subscription, dlt *Topic, opts *DeadLetterOptions) *Subscription {
+	return newSubscription(newDeadLetterSubscription(s, dlt, opts), nil, nil)
+}
+
+func newDeadLetterSubscription(s *Subscription, dlt *Topic, opts *DeadLetterOptions) *deadLetterSubscription {
+	attempts := DefaultMaxDeliveryAttempts
+	if opts != nil && opts.MaxDeliveryAttempts > 0 {
+		attempts = opts.MaxDeliveryAttempts
+	}
+	return &deadLetterSubscription{
+		s:           s,
+		dlt:         dlt,
+		maxAttempts: attempts,
+		provider:    telemetry.ProviderName(s.driver),
+	}
+}
+
+// deadLetterSubscription implements driver.Subscription. Its AckIDs are the
+// *Messages received from the underlying Subscription.
+type deadLetterSubscription struct {
+	s           *Subscription
+	dlt         *Topic
+	maxAttempts int
+	provider    string
+}
+
+// ReceiveBatch implements driver.Subscription.ReceiveBatch. It returns the
+// next message of the underlying Subscription.
+func (s *deadLetterSubscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	m, err := s.s.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []*driver.Message{{
+		LoggableID:      m.LoggableID,
+		Body:            m.Body,
+		Metadata:        m.Metadata,
+		OrderingKey:     m.OrderingKey,
+		DeliveryAttempt: m.DeliveryAttempt,
+		AckID:           m,
+		AsFunc:          m.As,
+	}}, nil
+}
+
+// SendAcks implements driver.Subscription.SendAcks.
+func (s *deadLetterSubscription) SendAcks(ctx context.Context, ackIDs []driver.AckID) error {
+	for _, id := range ackIDs {
+		id.(*Message).Ack()
+	}
+	return nil
+}
+
+// CanNack implements driver.Subscription.CanNack.
+func (s *deadLetterSubscription) CanNack() bool {
+	return true
+}
+
+// SendNacks implements driver.Subscription.SendNacks. It dead-letters the
+// messages that have been delivered too often, and nacks the others.
+//
+// It does not return errors: the messages that could not be dead-lettered
+// are nacked, and retrying the call would ack or nack messages twice.
+func (s *deadLetterSubscription) SendNacks(ctx context.Context, ackIDs []driver.AckID) error {
+	for _, id := range ackIDs {
+		m := id.(*Message)
+		if m.Nackable() && (m.DeliveryAttempt == 0 || m.DeliveryAttempt < s.maxAttempts) {
+			m.Nack()
+			continue
+		}
+		attrs := metric.WithAttributes(telemetry.ProviderKey.String(s.provider))
+		err := s.dlt.Send(ctx, &Message{Body: m.Body, Metadata: m.Metadata, OrderingKey: m.OrderingKey})
+		if err != nil {
+			deadLetterErrorsCounter.Add(ctx, 1, attrs)
+			if m.Nackable() {
+				m.Nack()
+			}
+			continue
+		}
+		deadLetteredCounter.Add(ctx, 1, attrs)
+		m.Ack()
+	}
+	return nil
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable. The underlying
+// Subscription has already retried the calls that failed.
+func (*deadLetterSubscription) IsRetryable(error) bool {
+	return false
+}
+
+// As implements driver.Subscription.As.
+func (s *deadLetterSubscription) As(i interface{}) bool {
+	return s.s.As(i)
+}
+
+// ErrorAs implements driver.Subscription.ErrorAs.
+func (s *deadLetterSubscription) ErrorAs(err error, i interface{}) bool {
+	return s.s.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Subscription.ErrorCode.
+func (*deadLetterSubscription) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
+
+// Close implements driver.Subscription.Close. It does not shut down the
+// underlying Subscription.
+func (*deadLetterSubscription) Close() error {
+	return nil
+}
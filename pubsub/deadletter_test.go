@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+	"gocloud.dev/pubsub/mempubsub"
+)
+
+// metricReader collects the metrics of the tests. The global meter provider
+// is set once, since the package's counters only use the first one set.
+var metricReader = func() *sdkmetric.ManualReader {
+	r := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(r)))
+	return r
+}()
+
+// counterValues returns the values of the int64 counters collected by
+// metricReader, by name.
+func counterValues(ctx context.Context, t *testing.T) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := metricReader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				counts[m.Name] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func TestDeadLetterSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	before := counterValues(ctx, t)
+
+	topic := mempubsub.NewTopic()
+	defer topic.Shutdown(ctx)
+	sub := mempubsub.NewSubscription(topic, time.Minute)
+	defer sub.Shutdown(ctx)
+	dlt := mempubsub.NewTopic()
+	defer dlt.Shutdown(ctx)
+	dlsub := mempubsub.NewSubscription(dlt, time.Minute)
+	defer dlsub.Shutdown(ctx)
+	s := pubsub.NewDeadLetterSubscription(sub, dlt, &pubsub.DeadLetterOptions{MaxDeliveryAttempts: 3})
+	defer s.Shutdown(ctx)
+
+	// A message that is nacked on its third delivery is dead-lettered.
+	want := &pubsub.Message{Body: []byte("poison"), Metadata: map[string]string{"k": "v"}, OrderingKey: "o"}
+	if err := topic.Send(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		m, err := s.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !m.Nackable() {
+			t.Fatal("got a message that cannot be nacked")
+		}
+		if m.DeliveryAttempt != i+1 {
+			t.Errorf("got DeliveryAttempt %d, want %d", m.DeliveryAttempt, i+1)
+		}
+		m.Nack()
+	}
+	got, err := dlsub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Ack()
+	if string(got.Body) != string(want.Body) || !cmp.Equal(got.Metadata, want.Metadata) || got.OrderingKey != want.OrderingKey {
+		t.Errorf("got dead-lettered message %q %v %q, want %q %v %q",
+			got.Body, got.Metadata, got.OrderingKey, want.Body, want.Metadata, want.OrderingKey)
+	}
+
+	// A message that is nacked fewer times is delivered again until it is
+	// acked, and is not dead-lettered.
+	if err := topic.Send(ctx, &pubsub.Message{Body: []byte("ok")}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		m, err := s.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(m.Body) != "ok" {
+			t.Fatalf("got message %q, want %q", m.Body, "ok")
+		}
+		if i < 2 {
+			m.Nack()
+		} else {
+			m.Ack()
+		}
+	}
+	rctx, rcancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer rcancel()
+	if m, err := dlsub.Receive(rctx); err == nil {
+		m.Ack()
+		t.Errorf("got dead-lettered message %q, want none", m.Body)
+	}
+
+	after := counterValues(ctx, t)
+	for name, want := range map[string]int64{
+		"gocdk.pubsub.dead_lettered":      1,
+		"gocdk.pubsub.dead_letter_errors": 0,
+	} {
+		if got := after[name] - before[name]; got != want {
+			t.Errorf("%s: got %d, want %d", name, got, want)
+		}
+	}
+}
+
+// uncountedSub is a driver.Subscription that delivers the same message on
+// every receive, without a delivery count.
+type uncountedSub struct {
+	driver.Subscription
+}
+
+func (uncountedSub) ReceiveBatch(context.Context, int) ([]*driver.Message, error) {
+	return []*driver.Message{{
+		LoggableID: "poison",
+		Body:       []byte("poison"),
+		AckID:      1,
+		AsFunc:     func(interface{}) bool { return false },
+	}}, nil
+}
+
+func (uncountedSub) SendAcks(context.Context, []driver.AckID) error  { return nil }
+func (uncountedSub) SendNacks(context.Context, []driver.AckID) error { return nil }
+func (uncountedSub) CanNack() bool                                   { return true }
+func (uncountedSub) IsRetryable(error) bool                          { return false }
+func (uncountedSub) ErrorCode(error) gcerrors.ErrorCode              { return gcerrors.Unknown }
+func (uncountedSub) Close() error                                    { return nil }
+
+func TestDeadLetterSubscriptionUncounted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sub := pubsub.NewSubscription(uncountedSub{}, nil, nil)
+	defer sub.Shutdown(ctx)
+	dlt := mempubsub.NewTopic()
+	defer dlt.Shutdown(ctx)
+	dlsub := mempubsub.NewSubscription(dlt, time.Minute)
+	defer dlsub.Shutdown(ctx)
+	s := pubsub.NewDeadLetterSubscription(sub, dlt, &pubsub.DeadLetterOptions{MaxDeliveryAttempts: 2})
+	defer s.Shutdown(ctx)
+
+	// Messages without a delivery count are never dead-lettered, even if
+	// they have the same LoggableID.
+	for i := 0; i < 4; i++ {
+		m, err := s.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Nack()
+	}
+	rctx, rcancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer rcancel()
+	if m, err := dlsub.Receive(rctx); err == nil {
+		m.Ack()
+		t.Errorf("got dead-lettered message %q, want none", m.Body)
+	}
+}
@@ -47,6 +47,18 @@ type Message struct {
 	// Metadata has key/value pairs describing the message.
 	Metadata map[string]string
 
+	// OrderingKey groups messages that must be delivered in order.
+	// Drivers should map it to the service's native ordering concept when
+	// sending, or document their best-effort behavior, and should set it on
+	// messages returned from ReceiveBatch when the service reports it.
+	OrderingKey string
+
+	// DeliveryAttempt is the number of times a received message has been
+	// delivered, including this delivery, as counted by the service.
+	// Drivers should set it on messages returned from ReceiveBatch when the
+	// service reports it, and leave it zero otherwise.
+	DeliveryAttempt int
+
 	// AckID should be set to something identifying the message on the
 	// server. It may be passed to Subscription.SendAcks to acknowledge
 	// the message, or to Subscription.SendNacks. This field should only
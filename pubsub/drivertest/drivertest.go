@@ -697,6 +697,7 @@ func receiveN(ctx context.Context, t *testing.T, sub *pubsub.Subscription, n int
 func diffMessageSets(got, want []*pubsub.Message) string {
 	for _, m := range got {
 		m.LoggableID = ""
+		m.DeliveryAttempt = 0
 	}
 	less := func(x, y *pubsub.Message) bool { return bytes.Compare(x.Body, y.Body) < 0 }
 	return cmp.Diff(got, want, cmpopts.SortSlices(less), cmpopts.IgnoreUnexported(pubsub.Message{}))
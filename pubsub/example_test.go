@@ -114,6 +114,34 @@ recvLoop:
 	}
 }
 
+func ExampleNewDeadLetterSubscription() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+	var subscription *pubsub.Subscription
+	var deadLetterTopic *pubsub.Topic
+	process := func(*pubsub.Message) error { return nil }
+
+	// Messages that are nacked on their fifth delivery are sent to
+	// deadLetterTopic instead of being delivered again.
+	s := pubsub.NewDeadLetterSubscription(subscription, deadLetterTopic, &pubsub.DeadLetterOptions{
+		MaxDeliveryAttempts: 5,
+	})
+	defer s.Shutdown(ctx)
+	for {
+		msg, err := s.Receive(ctx)
+		if err != nil {
+			log.Printf("Receiving message: %v", err)
+			break
+		}
+		if err := process(msg); err != nil {
+			msg.Nack()
+			continue
+		}
+		msg.Ack()
+	}
+}
+
 func ExampleMessage_As() {
 	// This example is specific to the gcppubsub implementation; it demonstrates
 	// access to the underlying PubsubMessage type.
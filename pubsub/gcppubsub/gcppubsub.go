@@ -38,6 +38,14 @@
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// Message.OrderingKey is sent as the Pub/Sub ordering key, and is set on
+// received messages. Messages are only delivered in order to subscriptions
+// created with message ordering enabled; see
+// https://cloud.google.com/pubsub/docs/ordering.
+//
+// Message.DeliveryAttempt is set on received messages of subscriptions with
+// a dead-letter policy; Pub/Sub does not count deliveries for others.
+//
 // # As
 //
 // gcppubsub exposes the following types for As:
@@ -64,9 +72,11 @@ import (
 	raw "cloud.google.com/go/pubsub/apiv1"
 	pb "cloud.google.com/go/pubsub/apiv1/pubsubpb"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/attribute"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/gcp"
 	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/internal/useragent"
 	"gocloud.dev/pubsub"
 	"gocloud.dev/pubsub/batcher"
@@ -372,7 +382,7 @@ func openTopic(client *raw.PublisherClient, topicPath string) driver.Topic {
 func (t *topic) SendBatch(ctx context.Context, dms []*driver.Message) error {
 	var ms []*pb.PubsubMessage
 	for _, dm := range dms {
-		psm := &pb.PubsubMessage{Data: dm.Body, Attributes: dm.Metadata}
+		psm := &pb.PubsubMessage{Data: dm.Body, Attributes: dm.Metadata, OrderingKey: dm.OrderingKey}
 		if dm.BeforeSend != nil {
 			asFunc := func(i interface{}) bool {
 				if p, ok := i.(**pb.PubsubMessage); ok {
@@ -417,6 +427,14 @@ func (t *topic) IsRetryable(error) bool {
 	return false
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (t *topic) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.MessagingSystemKey.String("gcp_pubsub"),
+		telemetry.MessagingDestinationNameKey.String(t.path),
+	}
+}
+
 // As implements driver.Topic.As.
 func (t *topic) As(i interface{}) bool {
 	c, ok := i.(**raw.PublisherClient)
@@ -555,11 +573,13 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 		rm := rm
 		rmm := rm.Message
 		m := &driver.Message{
-			LoggableID: rmm.MessageId,
-			Body:       rmm.Data,
-			Metadata:   rmm.Attributes,
-			AckID:      rm.AckId,
-			AsFunc:     messageAsFunc(rmm, rm),
+			LoggableID:      rmm.MessageId,
+			Body:            rmm.Data,
+			Metadata:        rmm.Attributes,
+			OrderingKey:     rmm.OrderingKey,
+			DeliveryAttempt: int(rm.DeliveryAttempt),
+			AckID:           rm.AckId,
+			AsFunc:          messageAsFunc(rmm, rm),
 		}
 		ms = append(ms, m)
 	}
@@ -617,6 +637,14 @@ func (s *subscription) IsRetryable(err error) bool {
 	return s.ErrorCode(err) == gcerrors.DeadlineExceeded
 }
 
+// TelemetryAttributes implements telemetry.Attributer.
+func (s *subscription) TelemetryAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.MessagingSystemKey.String("gcp_pubsub"),
+		telemetry.MessagingSubscriptionNameKey.String(s.path),
+	}
+}
+
 // As implements driver.Subscription.As.
 func (s *subscription) As(i interface{}) bool {
 	c, ok := i.(**raw.SubscriberClient)
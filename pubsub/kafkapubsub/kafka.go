@@ -41,6 +41,14 @@
 // []byte for both key and value. These are converted to string for use in
 // Message.Metadata.
 //
+// # Ordering
+//
+// Message.OrderingKey is sent as the Kafka message key, unless
+// TopicOptions.KeyName selects a Message.Metadata value to use instead.
+// The default partitioner sends messages with the same key to the same
+// partition, where they are delivered in order. The key of a received message
+// is reported in Message.OrderingKey.
+//
 // # As
 //
 // kafkapubsub exposes the following types for As:
@@ -227,7 +235,8 @@ type TopicOptions struct {
 	// KeyName optionally sets the Message.Metadata key to use as the optional
 	// Kafka message key. If set, and if a matching Message.Metadata key is found,
 	// the value for that key will be used as the message key when sending to
-	// Kafka, instead of being added to the message headers.
+	// Kafka, instead of being added to the message headers. It takes
+	// precedence over Message.OrderingKey.
 	KeyName string
 
 	// BatcherOptions adds constraints to the default batching done for sends.
@@ -269,6 +278,9 @@ func (t *topic) SendBatch(ctx context.Context, dms []*driver.Message) error {
 	ms := make([]*sarama.ProducerMessage, 0, len(dms))
 	for _, dm := range dms {
 		var kafkaKey sarama.Encoder
+		if dm.OrderingKey != "" {
+			kafkaKey = sarama.StringEncoder(dm.OrderingKey)
+		}
 		var headers []sarama.RecordHeader
 		for k, v := range dm.Metadata {
 			if k == t.opts.KeyName {
@@ -577,10 +589,11 @@ func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*dr
 			loggableID = string(msg.Key)
 		}
 		dm := &driver.Message{
-			LoggableID: loggableID,
-			Body:       msg.Value,
-			Metadata:   md,
-			AckID:      ack,
+			LoggableID:  loggableID,
+			Body:        msg.Value,
+			Metadata:    md,
+			OrderingKey: string(msg.Key),
+			AckID:       ack,
 			AsFunc: func(i interface{}) bool {
 				if p, ok := i.(**sarama.ConsumerMessage); ok {
 					*p = msg
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -265,6 +266,7 @@ func TestKafkaKey(t *testing.T) {
 
 	m.BeforeSend = nil // don't expect this in the received message
 	m.LoggableID = keyValue
+	m.OrderingKey = keyValue
 	if diff := cmp.Diff(got, m, cmpopts.IgnoreUnexported(pubsub.Message{})); diff != "" {
 		t.Errorf("got\n%v\nwant\n%v\ndiff\n%v", got, m, diff)
 	}
@@ -279,6 +281,91 @@ func TestKafkaKey(t *testing.T) {
 	}
 }
 
+// TestOrderingKey tests that Message.OrderingKey is sent as the Kafka message
+// key, and that messages with the same key are received in order.
+func TestOrderingKey(t *testing.T) {
+	if !setup.HasDockerTestEnvironment() {
+		t.Skip("Skipping Kafka tests since the Kafka server is not available")
+	}
+	const (
+		orderingKey = "orderingkey"
+		numMsgs     = 10
+	)
+	uniqueID := rand.Int()
+	ctx := context.Background()
+
+	topicName := fmt.Sprintf("%s-topic-%d", sanitize(t.Name()), uniqueID)
+	topicCleanup, err := createKafkaTopic(topicName, 3)
+	defer topicCleanup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic, err := OpenTopic(localBrokerAddrs, MinimalConfig(), topicName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := topic.Shutdown(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	groupID := fmt.Sprintf("%s-sub-%d", sanitize(t.Name()), uniqueID)
+	sub, err := OpenSubscription(localBrokerAddrs, MinimalConfig(), groupID, []string{topicName}, subscriptionOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sub.Shutdown(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	for i := 0; i < numMsgs; i++ {
+		m := &pubsub.Message{
+			Body:        []byte(strconv.Itoa(i)),
+			OrderingKey: orderingKey,
+			BeforeSend: func(as func(interface{}) bool) error {
+				var pm *sarama.ProducerMessage
+				if !as(&pm) {
+					return errors.New("failed to convert to ProducerMessage")
+				}
+				if pm.Key == nil {
+					return errors.New("Kafka key wasn't set")
+				}
+				gotKeyBytes, err := pm.Key.Encode()
+				if err != nil {
+					return fmt.Errorf("failed to Encode Kafka Key: %v", err)
+				}
+				if gotKey := string(gotKeyBytes); gotKey != orderingKey {
+					return fmt.Errorf("got Kafka key %q, want %q", gotKey, orderingKey)
+				}
+				return nil
+			},
+		}
+		if err := topic.Send(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The test will hang here if the messages aren't available, so use a shorter timeout.
+	ctx2, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for i := 0; i < numMsgs; i++ {
+		got, err := sub.Receive(ctx2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got.Ack()
+		if want := strconv.Itoa(i); string(got.Body) != want {
+			t.Errorf("got message %q, want %q", got.Body, want)
+		}
+		if got.OrderingKey != orderingKey {
+			t.Errorf("got OrderingKey %q, want %q", got.OrderingKey, orderingKey)
+		}
+	}
+}
+
 // TestMultiplePartionsWithRebalancing tests use of a topic with multiple
 // partitions, including the rebalancing that happens when a new consumer
 // appears in the group.
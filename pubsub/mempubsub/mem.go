@@ -34,6 +34,13 @@
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// Messages are delivered in no particular order, except that of the messages
+// with the same Message.OrderingKey, only the oldest unacknowledged one is
+// delivered, so they are received in the order in which they were sent.
+//
+// Message.DeliveryAttempt counts the deliveries of a message to each
+// subscription.
+//
 // # As
 //
 // mempubsub does not support any types for As.
@@ -279,6 +286,8 @@ func newSubscription(topic *topic, ackDeadline time.Duration) *subscription {
 type message struct {
 	msg        *driver.Message
 	expiration time.Time
+	// deliveries is the number of times the message has been delivered.
+	deliveries int
 }
 
 func (s *subscription) add(ms []*driver.Message) {
@@ -293,14 +302,32 @@ func (s *subscription) add(ms []*driver.Message) {
 
 // Collect some messages available for delivery. Since we're iterating over a map,
 // the order of the messages won't match the publish order, which mimics the actual
-// behavior of most pub/sub services.
+// behavior of most pub/sub services. Messages with an ordering key are held back
+// until the older messages with the same key have been acked.
 func (s *subscription) receiveNoWait(now time.Time, max int) []*driver.Message {
 	var msgs []*driver.Message
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	for _, m := range s.msgs {
+	// oldest maps each ordering key to the ack ID of its oldest unacked message.
+	oldest := map[string]int{}
+	for id, m := range s.msgs {
+		if k := m.msg.OrderingKey; k != "" {
+			if o, ok := oldest[k]; !ok || id.(int) < o {
+				oldest[k] = id.(int)
+			}
+		}
+	}
+	for id, m := range s.msgs {
+		if k := m.msg.OrderingKey; k != "" && oldest[k] != id.(int) {
+			continue
+		}
 		if now.After(m.expiration) {
-			msgs = append(msgs, m.msg)
+			// The message is shared with the topic's other subscriptions,
+			// so its delivery count is set on a copy.
+			m.deliveries++
+			dm := *m.msg
+			dm.DeliveryAttempt = m.deliveries
+			msgs = append(msgs, &dm)
 			m.expiration = now.Add(s.ackDeadline)
 			if len(msgs) == max {
 				return msgs
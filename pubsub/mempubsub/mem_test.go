@@ -76,6 +76,42 @@ func TestReceive(t *testing.T) {
 	}
 }
 
+func TestReceiveOrderingKey(t *testing.T) {
+	ctx := context.Background()
+	topic := &topic{}
+	sub := newSubscription(topic, 3*time.Second)
+	if err := topic.SendBatch(ctx, []*driver.Message{
+		{Body: []byte("a1"), OrderingKey: "a"},
+		{Body: []byte("b1"), OrderingKey: "b"},
+		{Body: []byte("a2"), OrderingKey: "a"},
+		{Body: []byte("c")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	// Only the oldest message of each ordering key is available.
+	msgs := sub.receiveNoWait(now, 10)
+	if got, want := len(msgs), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	var ackIDs []driver.AckID
+	for _, m := range msgs {
+		if string(m.Body) == "a2" {
+			t.Fatal("got a2 before a1 was acked")
+		}
+		ackIDs = append(ackIDs, m.AckID)
+	}
+	// Once a1 is acked, a2 is delivered.
+	sub.SendAcks(ctx, ackIDs)
+	msgs = sub.receiveNoWait(now, 10)
+	if got, want := len(msgs), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := string(msgs[0].Body), "a2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 func TestOpenTopicFromURL(t *testing.T) {
 	tests := []struct {
 		URL     string
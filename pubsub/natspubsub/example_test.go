@@ -17,6 +17,7 @@ package natspubsub_test
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/nats-io/nats.go"
 
@@ -84,6 +85,60 @@ func ExampleOpenQueueSubscription() {
 	defer subscription.Shutdown(ctx)
 }
 
+func ExampleOpenJetStreamTopic() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	natsConn, err := nats.Connect("nats://nats.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer natsConn.Close()
+	js, err := natsConn.JetStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// A JetStream stream must store the subject.
+	topic, err := natspubsub.OpenJetStreamTopic(js, "example.mysubject", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer topic.Shutdown(ctx)
+}
+
+func ExampleOpenJetStreamSubscription() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	natsConn, err := nats.Connect("nats://nats.example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer natsConn.Close()
+	js, err := natsConn.JetStream()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Messages that are not acknowledged within a minute are redelivered,
+	// up to 5 times.
+	subscription, err := natspubsub.OpenJetStreamSubscription(
+		js,
+		"example.mysubject",
+		&natspubsub.JetStreamSubscriptionOptions{
+			Durable:    "myconsumer",
+			AckWait:    time.Minute,
+			MaxDeliver: 5,
+		})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer subscription.Shutdown(ctx)
+}
+
 func Example_openTopicFromURL() {
 	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
 	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/pubsub/natspubsub"
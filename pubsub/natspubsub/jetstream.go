@@ -0,0 +1,352 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natspubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/batcher"
+	"gocloud.dev/pubsub/driver"
+)
+
+var jsRecvBatcherOpts = &batcher.Options{
+	// JetStream redelivers messages that are not acknowledged, so it is safe
+	// to read ahead. Fetches on a pull subscription are not run concurrently.
+	MaxBatchSize: 100,
+	MaxHandlers:  1,
+}
+
+// jsReceiveWait is how long ReceiveBatch waits for messages.
+const jsReceiveWait = 100 * time.Millisecond
+
+// JetStreamSubscriptionOptions sets options for constructing a
+// *pubsub.Subscription backed by a NATS JetStream consumer.
+type JetStreamSubscriptionOptions struct {
+	// Durable is the name of the durable consumer to receive messages from.
+	// It is created if it does not exist. Subscriptions with the same durable
+	// consumer share its messages. Required.
+	Durable string
+
+	// Stream is the name of the stream that stores the messages of the
+	// subject. If empty, the stream is looked up by subject.
+	Stream string
+
+	// AckWait is how long the server waits for a message to be acknowledged
+	// before redelivering it. If zero, the server default of 30 seconds is used.
+	AckWait time.Duration
+
+	// MaxDeliver is the maximum number of times a message is delivered. If
+	// zero, messages are redelivered until they are acknowledged.
+	MaxDeliver int
+
+	// BackOff is the sequence of delays before redelivering a message that
+	// is not acknowledged in time; the last delay is used for further
+	// redeliveries. It overrides AckWait.
+	BackOff []time.Duration
+
+	// NackDelay is how long the server waits before redelivering a message
+	// that was negatively acknowledged. If zero, it is redelivered at once.
+	NackDelay time.Duration
+}
+
+type jsTopic struct {
+	js   nats.JetStreamContext
+	subj string
+}
+
+// OpenJetStreamTopic returns a *pubsub.Topic that publishes to a NATS
+// JetStream stream. The subject must be stored by a stream.
+// Messages are encoded with native NATS message headers, like OpenTopicV2,
+// and Send returns after the stream has stored them.
+func OpenJetStreamTopic(js nats.JetStreamContext, subject string, _ *TopicOptions) (*pubsub.Topic, error) {
+	dt, err := openJetStreamTopic(js, subject)
+	if err != nil {
+		return nil, err
+	}
+	return pubsub.NewTopic(dt, nil), nil
+}
+
+// openJetStreamTopic returns the driver for OpenJetStreamTopic. This function
+// exists so the test harness can get the driver interface implementation if
+// it needs to.
+func openJetStreamTopic(js nats.JetStreamContext, subject string) (driver.Topic, error) {
+	if js == nil {
+		return nil, errors.New("natspubsub: nats.JetStreamContext is required")
+	}
+	return &jsTopic{js: js, subj: subject}, nil
+}
+
+// SendBatch implements driver.Topic.SendBatch.
+func (t *jsTopic) SendBatch(ctx context.Context, msgs []*driver.Message) error {
+	if t == nil || t.js == nil {
+		return errNotInitialized
+	}
+	for _, m := range msgs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		msg := encodeMessageV2(m, t.subj)
+		if m.BeforeSend != nil {
+			asFunc := func(i interface{}) bool {
+				if nm, ok := i.(**nats.Msg); ok {
+					*nm = msg
+					return true
+				}
+				return false
+			}
+			if err := m.BeforeSend(asFunc); err != nil {
+				return err
+			}
+		}
+		ack, err := t.js.PublishMsg(msg, nats.Context(ctx))
+		if err != nil {
+			return err
+		}
+		if m.AfterSend != nil {
+			asFunc := func(i interface{}) bool {
+				if pa, ok := i.(**nats.PubAck); ok {
+					*pa = ack
+					return true
+				}
+				return false
+			}
+			if err := m.AfterSend(asFunc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsRetryable implements driver.Topic.IsRetryable.
+func (*jsTopic) IsRetryable(error) bool { return false }
+
+// As implements driver.Topic.As.
+func (t *jsTopic) As(i interface{}) bool {
+	c, ok := i.(*nats.JetStreamContext)
+	if !ok {
+		return false
+	}
+	*c = t.js
+	return true
+}
+
+// ErrorAs implements driver.Topic.ErrorAs.
+func (*jsTopic) ErrorAs(err error, i interface{}) bool {
+	return jsErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Topic.ErrorCode.
+func (*jsTopic) ErrorCode(err error) gcerrors.ErrorCode {
+	return jsErrorCode(err)
+}
+
+// Close implements driver.Topic.Close.
+func (*jsTopic) Close() error { return nil }
+
+type jsSubscription struct {
+	nsub      *nats.Subscription
+	nackDelay time.Duration
+}
+
+// OpenJetStreamSubscription returns a *pubsub.Subscription that receives the
+// messages of subject from a durable NATS JetStream consumer. Messages must
+// be acknowledged; those that are not, or that are negatively acknowledged,
+// are redelivered as configured by opts.
+//
+// The consumer is created with explicit acknowledgements if it does not
+// exist; an existing consumer is used as it is, without applying the options
+// that configure it. The consumer is not deleted when the subscription is
+// shut down.
+func OpenJetStreamSubscription(js nats.JetStreamContext, subject string, opts *JetStreamSubscriptionOptions) (*pubsub.Subscription, error) {
+	ds, err := openJetStreamSubscription(js, subject, opts)
+	if err != nil {
+		return nil, err
+	}
+	return pubsub.NewSubscription(ds, jsRecvBatcherOpts, nil), nil
+}
+
+func openJetStreamSubscription(js nats.JetStreamContext, subject string, opts *JetStreamSubscriptionOptions) (driver.Subscription, error) {
+	if js == nil {
+		return nil, errors.New("natspubsub: nats.JetStreamContext is required")
+	}
+	if opts == nil || opts.Durable == "" {
+		return nil, errors.New("natspubsub: JetStreamSubscriptionOptions.Durable is required")
+	}
+	stream := opts.Stream
+	if stream == "" {
+		var err error
+		if stream, err = js.StreamNameBySubject(subject); err != nil {
+			return nil, fmt.Errorf("natspubsub: looking up the stream of subject %q: %w", subject, err)
+		}
+	}
+	if _, err := js.ConsumerInfo(stream, opts.Durable); errors.Is(err, nats.ErrConsumerNotFound) {
+		_, err = js.AddConsumer(stream, &nats.ConsumerConfig{
+			Durable:       opts.Durable,
+			FilterSubject: subject,
+			AckPolicy:     nats.AckExplicitPolicy,
+			AckWait:       opts.AckWait,
+			MaxDeliver:    opts.MaxDeliver,
+			BackOff:       opts.BackOff,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	// Binding to the consumer keeps Unsubscribe from deleting it.
+	sub, err := js.PullSubscribe(subject, opts.Durable, nats.Bind(stream, opts.Durable))
+	if err != nil {
+		return nil, err
+	}
+	return &jsSubscription{nsub: sub, nackDelay: opts.NackDelay}, nil
+}
+
+// ReceiveBatch implements driver.ReceiveBatch.
+func (s *jsSubscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	if s == nil || s.nsub == nil {
+		return nil, nats.ErrBadSubscription
+	}
+	fctx, cancel := context.WithTimeout(ctx, jsReceiveWait)
+	defer cancel()
+	msgs, err := s.nsub.Fetch(maxMessages, nats.Context(fctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	dms := make([]*driver.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		dm, err := decodeMessageV2(msg)
+		if err != nil {
+			return nil, err
+		}
+		dm.AckID = msg
+		if md, err := msg.Metadata(); err == nil {
+			dm.LoggableID = fmt.Sprintf("%s #%d", md.Stream, md.Sequence.Stream)
+			dm.DeliveryAttempt = int(md.NumDelivered)
+		}
+		dms = append(dms, dm)
+	}
+	return dms, nil
+}
+
+// SendAcks implements driver.Subscription.SendAcks.
+func (s *jsSubscription) SendAcks(ctx context.Context, ids []driver.AckID) error {
+	for _, id := range ids {
+		if err := id.(*nats.Msg).Ack(); err != nil && !errors.Is(err, nats.ErrMsgAlreadyAckd) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanNack implements driver.CanNack.
+func (s *jsSubscription) CanNack() bool { return true }
+
+// SendNacks implements driver.Subscription.SendNacks.
+func (s *jsSubscription) SendNacks(ctx context.Context, ids []driver.AckID) error {
+	for _, id := range ids {
+		if err := id.(*nats.Msg).NakWithDelay(s.nackDelay); err != nil && !errors.Is(err, nats.ErrMsgAlreadyAckd) {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable.
+func (*jsSubscription) IsRetryable(error) bool { return false }
+
+// As implements driver.Subscription.As.
+func (s *jsSubscription) As(i interface{}) bool {
+	c, ok := i.(**nats.Subscription)
+	if !ok {
+		return false
+	}
+	*c = s.nsub
+	return true
+}
+
+// ErrorAs implements driver.Subscription.ErrorAs.
+func (*jsSubscription) ErrorAs(err error, i interface{}) bool {
+	return jsErrorAs(err, i)
+}
+
+// ErrorCode implements driver.Subscription.ErrorCode.
+func (*jsSubscription) ErrorCode(err error) gcerrors.ErrorCode {
+	return jsErrorCode(err)
+}
+
+// Close implements driver.Subscription.Close.
+func (s *jsSubscription) Close() error {
+	if s == nil || s.nsub == nil {
+		return nil
+	}
+	return s.nsub.Unsubscribe()
+}
+
+// jsErrorAs converts err to a *nats.APIError.
+func jsErrorAs(err error, i interface{}) bool {
+	p, ok := i.(**nats.APIError)
+	if !ok {
+		return false
+	}
+	return errors.As(err, p)
+}
+
+func jsErrorCode(err error) gcerrors.ErrorCode {
+	switch {
+	case err == nil:
+		return gcerrors.OK
+	case errors.Is(err, context.Canceled):
+		return gcerrors.Canceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, nats.ErrTimeout):
+		return gcerrors.DeadlineExceeded
+	case errors.Is(err, errNotInitialized), errors.Is(err, nats.ErrBadSubscription),
+		errors.Is(err, nats.ErrStreamNotFound), errors.Is(err, nats.ErrConsumerNotFound),
+		errors.Is(err, nats.ErrNoStreamResponse), errors.Is(err, nats.ErrNoResponders):
+		return gcerrors.NotFound
+	case errors.Is(err, nats.ErrBadSubject), errors.Is(err, nats.ErrJetStreamNotEnabled):
+		return gcerrors.FailedPrecondition
+	case errors.Is(err, nats.ErrAuthorization):
+		return gcerrors.PermissionDenied
+	case errors.Is(err, nats.ErrMaxPayload), errors.Is(err, nats.ErrSlowConsumer):
+		return gcerrors.ResourceExhausted
+	}
+	var apiErr *nats.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case 400:
+			return gcerrors.InvalidArgument
+		case 404:
+			return gcerrors.NotFound
+		case 503:
+			return gcerrors.FailedPrecondition
+		}
+	}
+	return gcerrors.Unknown
+}
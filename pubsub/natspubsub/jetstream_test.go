@@ -0,0 +1,408 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natspubsub
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	gnatsd "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+	"gocloud.dev/pubsub/drivertest"
+)
+
+type jsHarness struct {
+	s      *server.Server
+	nc     *nats.Conn
+	js     nats.JetStreamContext
+	nextID int
+}
+
+func newJetStreamHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	s, nc, js, err := runJetStreamServer(t)
+	if err != nil {
+		return nil, err
+	}
+	return &jsHarness{s: s, nc: nc, js: js}, nil
+}
+
+// runJetStreamServer runs a NATS server with JetStream enabled, and connects
+// to it.
+func runJetStreamServer(t *testing.T) (*server.Server, *nats.Conn, nats.JetStreamContext, error) {
+	opts := gnatsd.DefaultTestOptions
+	opts.Port = testPort
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	s := gnatsd.RunServer(&opts)
+	nc, err := nats.Connect(fmt.Sprintf("nats://127.0.0.1:%d", testPort))
+	if err != nil {
+		s.Shutdown()
+		return nil, nil, nil, err
+	}
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		s.Shutdown()
+		return nil, nil, nil, err
+	}
+	return s, nc, js, nil
+}
+
+// streamName returns a valid stream name for the subject subj.
+func streamName(subj string) string {
+	return strings.NewReplacer("/", "_", ".", "_", " ", "_", "*", "_", ">", "_").Replace(subj)
+}
+
+func (h *jsHarness) CreateTopic(ctx context.Context, testName string) (driver.Topic, func(), error) {
+	stream := streamName(testName)
+	if _, err := h.js.AddStream(&nats.StreamConfig{Name: stream, Subjects: []string{testName}}); err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { h.js.DeleteStream(stream) }
+	dt, err := openJetStreamTopic(h.js, testName)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return dt, cleanup, nil
+}
+
+func (h *jsHarness) MakeNonexistentTopic(ctx context.Context) (driver.Topic, error) {
+	// No stream stores the subject.
+	return openJetStreamTopic(h.js, "nonexistent-subject")
+}
+
+func (h *jsHarness) CreateSubscription(ctx context.Context, dt driver.Topic, testName string) (driver.Subscription, func(), error) {
+	h.nextID++
+	ds, err := openJetStreamSubscription(h.js, testName, &JetStreamSubscriptionOptions{
+		Durable: fmt.Sprintf("sub%d", h.nextID),
+		Stream:  streamName(testName),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ds, func() {}, nil
+}
+
+func (h *jsHarness) MakeNonexistentSubscription(ctx context.Context) (driver.Subscription, func(), error) {
+	return (*jsSubscription)(nil), func() {}, nil
+}
+
+func (h *jsHarness) Close() {
+	h.nc.Close()
+	h.s.Shutdown()
+}
+
+func (*jsHarness) MaxBatchSizes() (int, int) { return 0, 0 }
+
+func (*jsHarness) SupportsMultipleSubscriptions() bool { return true }
+
+type jsAsTest struct{}
+
+func (jsAsTest) Name() string {
+	return "jetstream test"
+}
+
+func (jsAsTest) TopicCheck(topic *pubsub.Topic) error {
+	var c *nats.Conn
+	if topic.As(&c) {
+		return fmt.Errorf("cast succeeded for %T, want failure", &c)
+	}
+	var js nats.JetStreamContext
+	if !topic.As(&js) {
+		return fmt.Errorf("cast failed for %T", &js)
+	}
+	return nil
+}
+
+func (jsAsTest) SubscriptionCheck(sub *pubsub.Subscription) error {
+	var s *nats.Subscription
+	if !sub.As(&s) {
+		return fmt.Errorf("cast failed for %T", &s)
+	}
+	return nil
+}
+
+func (jsAsTest) TopicErrorCheck(t *pubsub.Topic, err error) error {
+	var dummy string
+	if t.ErrorAs(err, &dummy) {
+		return fmt.Errorf("cast succeeded for %T, want failure", &dummy)
+	}
+	return nil
+}
+
+func (jsAsTest) SubscriptionErrorCheck(s *pubsub.Subscription, err error) error {
+	var dummy string
+	if s.ErrorAs(err, &dummy) {
+		return fmt.Errorf("cast succeeded for %T, want failure", &dummy)
+	}
+	return nil
+}
+
+func (jsAsTest) MessageCheck(m *pubsub.Message) error {
+	var pm *nats.Msg
+	if !m.As(&pm) {
+		return fmt.Errorf("cast failed for %T", &pm)
+	}
+	return nil
+}
+
+func (jsAsTest) BeforeSend(as func(interface{}) bool) error {
+	var pm *nats.Msg
+	if !as(&pm) {
+		return fmt.Errorf("cast failed for %T", &pm)
+	}
+	return nil
+}
+
+func (jsAsTest) AfterSend(as func(interface{}) bool) error {
+	var pa *nats.PubAck
+	if !as(&pa) {
+		return fmt.Errorf("cast failed for %T", &pa)
+	}
+	return nil
+}
+
+func TestConformanceJetStream(t *testing.T) {
+	asTests := []drivertest.AsTest{jsAsTest{}}
+	drivertest.RunConformanceTests(t, newJetStreamHarness, asTests)
+}
+
+// An unacknowledged message is redelivered to a new subscription with the
+// same durable consumer, as after a crash.
+func TestJetStreamRedeliveryToDurable(t *testing.T) {
+	ctx := context.Background()
+	s, nc, js, err := runJetStreamServer(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+	defer nc.Close()
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	topic, err := OpenJetStreamTopic(js, "orders", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Shutdown(ctx)
+	md := map[string]string{"a": "1"}
+	if err := topic.Send(ctx, &pubsub.Message{Body: []byte("hello"), Metadata: md}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &JetStreamSubscriptionOptions{Durable: "billing", AckWait: time.Second}
+	sub, err := OpenJetStreamSubscription(js, "orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Body) != "hello" || m.Metadata["a"] != "1" {
+		t.Errorf("got %q %v, want %q %v", m.Body, m.Metadata, "hello", md)
+	}
+	// Shut down without acknowledging the message.
+	if err := sub.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err = OpenJetStreamSubscription(js, "orders", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Shutdown(ctx)
+	ctx2, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	m, err = sub.Receive(ctx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Body) != "hello" {
+		t.Errorf("got %q, want %q", m.Body, "hello")
+	}
+	m.Ack()
+	var msg *nats.Msg
+	if !m.As(&msg) {
+		t.Fatal("Message.As failed")
+	}
+	md2, err := msg.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md2.NumDelivered != 2 {
+		t.Errorf("got %d deliveries, want 2", md2.NumDelivered)
+	}
+}
+
+func TestJetStreamSubscriptionOptions(t *testing.T) {
+	s, nc, js, err := runJetStreamServer(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+	defer nc.Close()
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The durable consumer is required.
+	if _, err := openJetStreamSubscription(js, "orders", &JetStreamSubscriptionOptions{}); err == nil {
+		t.Error("got nil error without a durable consumer, want an error")
+	}
+	// The stream of the subject must exist.
+	if _, err := openJetStreamSubscription(js, "nostream", &JetStreamSubscriptionOptions{Durable: "d"}); err == nil {
+		t.Error("got nil error for a subject without a stream, want an error")
+	}
+
+	// The consumer is created with the redelivery options.
+	ds, err := openJetStreamSubscription(js, "orders", &JetStreamSubscriptionOptions{
+		Durable:    "d",
+		AckWait:    5 * time.Second,
+		MaxDeliver: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := js.ConsumerInfo("ORDERS", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := info.Config; c.AckPolicy != nats.AckExplicitPolicy || c.AckWait != 5*time.Second || c.MaxDeliver != 3 || c.FilterSubject != "orders" {
+		t.Errorf("got consumer config %+v", c)
+	}
+	// Closing the subscription does not delete the consumer.
+	if err := ds.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := js.ConsumerInfo("ORDERS", "d"); err != nil {
+		t.Errorf("consumer after Close: %v", err)
+	}
+}
+
+func TestJetStreamErrorCode(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want gcerrors.ErrorCode
+	}{
+		{nil, gcerrors.OK},
+		{context.Canceled, gcerrors.Canceled},
+		{nats.ErrTimeout, gcerrors.DeadlineExceeded},
+		{nats.ErrStreamNotFound, gcerrors.NotFound},
+		{fmt.Errorf("wrapped: %w", nats.ErrConsumerNotFound), gcerrors.NotFound},
+		{nats.ErrNoStreamResponse, gcerrors.NotFound},
+		{nats.ErrBadSubscription, gcerrors.NotFound},
+		{nats.ErrJetStreamNotEnabled, gcerrors.FailedPrecondition},
+		{nats.ErrAuthorization, gcerrors.PermissionDenied},
+		{nats.ErrMaxPayload, gcerrors.ResourceExhausted},
+		{&nats.APIError{Code: 400, Description: "bad"}, gcerrors.InvalidArgument},
+		{fmt.Errorf("other"), gcerrors.Unknown},
+	} {
+		if got := jsErrorCode(test.err); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestOpenJetStreamFromURL(t *testing.T) {
+	ctx := context.Background()
+	s, nc, js, err := runJetStreamServer(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+	defer nc.Close()
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "ORDERS", Subjects: []string{"orders"}}); err != nil {
+		t.Fatal(err)
+	}
+	o := &URLOpener{Connection: nc}
+
+	topicTests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"nats://orders?jetstream", false},
+		{"nats://orders?jetstream=true", false},
+		// Invalid value.
+		{"nats://orders?jetstream=foo", true},
+		// Invalid parameter.
+		{"nats://orders?jetstream&durable=d", true},
+	}
+	for _, test := range topicTests {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		topic, err := o.OpenTopicURL(ctx, u)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if topic != nil {
+			topic.Shutdown(ctx)
+		}
+	}
+
+	subTests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"nats://orders?jetstream&durable=d1", false},
+		{"nats://orders?jetstream&durable=d2&stream=ORDERS&ack_wait=10s&max_deliver=5&nack_delay=1s", false},
+		// Missing durable consumer.
+		{"nats://orders?jetstream", true},
+		// Invalid values.
+		{"nats://orders?jetstream&durable=d3&ack_wait=10", true},
+		{"nats://orders?jetstream&durable=d3&max_deliver=x", true},
+		// Queue groups are not used with JetStream.
+		{"nats://orders?jetstream&durable=d3&queue=q", true},
+		// JetStream parameters need jetstream.
+		{"nats://orders?durable=d3", true},
+	}
+	for _, test := range subTests {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sub, err := o.OpenSubscriptionURL(ctx, u)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if sub != nil {
+			sub.Shutdown(ctx)
+		}
+	}
+
+	// UseJetStream applies to URLs without the parameter.
+	o.UseJetStream = true
+	o.JetStreamSubscriptionOptions.Durable = "d4"
+	sub, err := o.OpenSubscriptionURL(ctx, &url.URL{Scheme: Scheme, Host: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub.Shutdown(ctx)
+}
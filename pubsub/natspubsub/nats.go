@@ -48,21 +48,47 @@
 // see URLOpener.
 // See https://gocloud.dev/concepts/urls/ for background information.
 //
+// # JetStream
+//
+// OpenJetStreamTopic and OpenJetStreamSubscription publish and receive
+// messages through NATS JetStream, which stores them in streams and
+// redelivers them until they are acknowledged. Messages are encoded like
+// with natsv2. To use JetStream with URLs, set the query parameter
+// "jetstream", for example:
+//   - nats://orders?jetstream
+//   - nats://orders?jetstream&durable=billing&max_deliver=5
+//
+// This can also be enabled by setting the UseJetStream field in the
+// URLOpener.
+//
 // # Message Delivery Semantics
 //
 // NATS supports at-most-semantics; applications need not call Message.Ack,
 // and must not call Message.Nack.
+// NATS JetStream supports at-least-once semantics; applications must call
+// Message.Ack after processing a message, or it will be redelivered.
+// Message.Nack redelivers the message, after
+// JetStreamSubscriptionOptions.NackDelay.
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// NATS has no ordering keys, so Message.OrderingKey is ignored. Messages from
+// a single connection are delivered in the order they were published, but
+// queue groups and JetStream redeliveries can reorder them; ordering is
+// best-effort.
+//
+// Message.DeliveryAttempt is set on messages received from JetStream, from
+// their delivery count; core NATS does not count deliveries.
+//
 // # As
 //
 // natspubsub exposes the following types for As:
-//   - Topic: *nats.Conn
+//   - Topic: *nats.Conn; nats.JetStreamContext for JetStream.
 //   - Subscription: *nats.Subscription
-//   - Message.BeforeSend: None for v1, *nats.Msg for v2.
-//   - Message.AfterSend: None.
+//   - Message.BeforeSend: None for v1, *nats.Msg for v2 and JetStream.
+//   - Message.AfterSend: None; *nats.PubAck for JetStream.
 //   - Message: *nats.Msg
+//   - Error: *nats.APIError for JetStream.
 package natspubsub // import "gocloud.dev/pubsub/natspubsub"
 
 import (
@@ -209,7 +235,22 @@ const Scheme = "nats"
 //
 // The URL host+path is used as the subject.
 //
-// No query parameters are supported.
+// The following query parameters are supported:
+//
+//   - natsv2: see the package documentation.
+//   - queue: the queue group of a subscription; see SubscriptionOptions.Queue.
+//   - jetstream: publish and receive through NATS JetStream, like UseJetStream.
+//     Like natsv2, it needs no value.
+//
+// The following query parameters are supported for JetStream subscriptions,
+// and override the fields of JetStreamSubscriptionOptions:
+//
+//   - durable: the name of the durable consumer.
+//   - stream: the name of the stream.
+//   - ack_wait: the acknowledgement deadline, as a time.Duration string.
+//   - max_deliver: the maximum number of deliveries of a message.
+//   - nack_delay: the delay before redelivering a nacked message, as a
+//     time.Duration string.
 type URLOpener struct {
 	// Connection to use for communication with the server.
 	Connection *nats.Conn
@@ -219,19 +260,40 @@ type URLOpener struct {
 	SubscriptionOptions SubscriptionOptions
 	// UseV2 indicates whether the NATS Server is at least version 2.2.0.
 	UseV2 bool
+	// UseJetStream indicates whether to open topics with OpenJetStreamTopic
+	// and subscriptions with OpenJetStreamSubscription.
+	UseJetStream bool
+	// JetStreamSubscriptionOptions specifies the options to pass to
+	// OpenJetStreamSubscription.
+	JetStreamSubscriptionOptions JetStreamSubscriptionOptions
 }
 
-const natsV2QueryParameter = "natsv2"
+const (
+	natsV2QueryParameter    = "natsv2"
+	jetStreamQueryParameter = "jetstream"
+)
 
 // OpenTopicURL opens a pubsub.Topic based on u.
 func (o *URLOpener) OpenTopicURL(ctx context.Context, u *url.URL) (*pubsub.Topic, error) {
 	for param := range u.Query() {
-		if strings.ToLower(param) == natsV2QueryParameter {
+		switch strings.ToLower(param) {
+		case natsV2QueryParameter, jetStreamQueryParameter:
 			continue
 		}
 		return nil, fmt.Errorf("open topic %v: invalid query parameter %s", u, param)
 	}
+	useJS, err := o.useJetStream(u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("open topic %v: %v", u, err)
+	}
 	subject := path.Join(u.Host, u.Path)
+	if useJS {
+		js, err := o.Connection.JetStream()
+		if err != nil {
+			return nil, fmt.Errorf("open topic %v: %v", u, err)
+		}
+		return OpenJetStreamTopic(js, subject, &o.TopicOptions)
+	}
 	if o.UseV2 {
 		return OpenTopicV2(o.Connection, subject, &o.TopicOptions)
 	}
@@ -240,21 +302,51 @@ func (o *URLOpener) OpenTopicURL(ctx context.Context, u *url.URL) (*pubsub.Topic
 
 // OpenSubscriptionURL opens a pubsub.Subscription based on u.
 func (o *URLOpener) OpenSubscriptionURL(ctx context.Context, u *url.URL) (*pubsub.Subscription, error) {
+	useJS, err := o.useJetStream(u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("open subscription %v: %v", u, err)
+	}
 	opts := o.SubscriptionOptions
+	jsOpts := o.JetStreamSubscriptionOptions
 	for param, values := range u.Query() {
-		switch strings.ToLower(param) {
-		case natsV2QueryParameter:
+		param = strings.ToLower(param)
+		switch param {
+		case natsV2QueryParameter, jetStreamQueryParameter:
 			continue
-		case "queue":
-			if len(values) != 1 {
-				return nil, fmt.Errorf("open subscription %v: invalid query parameter %s", u, param)
-			}
-			opts.Queue = values[0]
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("open subscription %v: invalid query parameter %s", u, param)
+		}
+		value := values[0]
+		var err error
+		switch {
+		case param == "queue" && !useJS:
+			opts.Queue = value
+		case param == "durable" && useJS:
+			jsOpts.Durable = value
+		case param == "stream" && useJS:
+			jsOpts.Stream = value
+		case param == "ack_wait" && useJS:
+			jsOpts.AckWait, err = time.ParseDuration(value)
+		case param == "max_deliver" && useJS:
+			jsOpts.MaxDeliver, err = strconv.Atoi(value)
+		case param == "nack_delay" && useJS:
+			jsOpts.NackDelay, err = time.ParseDuration(value)
 		default:
 			return nil, fmt.Errorf("open subscription %v: invalid query parameter %s", u, param)
 		}
+		if err != nil {
+			return nil, fmt.Errorf("open subscription %v: invalid query parameter %s: %v", u, param, err)
+		}
 	}
 	subject := path.Join(u.Host, u.Path)
+	if useJS {
+		js, err := o.Connection.JetStream()
+		if err != nil {
+			return nil, fmt.Errorf("open subscription %v: %v", u, err)
+		}
+		return OpenJetStreamSubscription(js, subject, &jsOpts)
+	}
 	if o.UseV2 {
 		return OpenSubscriptionV2(o.Connection, subject, &opts)
 	}
@@ -631,10 +723,24 @@ func decodeMessage(data []byte, dm *driver.Message) error {
 }
 
 func queryUseV2(q url.Values) (bool, error) {
+	return queryBool(q, natsV2QueryParameter)
+}
+
+// useJetStream reports whether to use JetStream for a URL with the query q.
+func (o *URLOpener) useJetStream(q url.Values) (bool, error) {
+	if _, ok := q[jetStreamQueryParameter]; !ok {
+		return o.UseJetStream, nil
+	}
+	return queryBool(q, jetStreamQueryParameter)
+}
+
+// queryBool returns the value of the boolean query parameter param, which is
+// true if it is provided without a value.
+func queryBool(q url.Values, param string) (bool, error) {
 	if len(q) == 0 {
 		return false, nil
 	}
-	v, ok := q[natsV2QueryParameter]
+	v, ok := q[param]
 	if !ok {
 		return false, nil
 	}
@@ -645,16 +751,16 @@ func queryUseV2(q url.Values) (bool, error) {
 		return true, nil
 	}
 	if len(v) > 1 {
-		return false, fmt.Errorf("invalid query parameter %s - multiple values provided", natsV2QueryParameter)
+		return false, fmt.Errorf("invalid query parameter %s - multiple values provided", param)
 	}
 	if v[0] == "" {
 		return true, nil
 	}
-	useV2, err := strconv.ParseBool(v[0])
+	b, err := strconv.ParseBool(v[0])
 	if err != nil {
-		return false, fmt.Errorf("invalid query parameter %s - value either needs to be parsable as a boolean or empty", natsV2QueryParameter)
+		return false, fmt.Errorf("invalid query parameter %s - value either needs to be parsable as a boolean or empty", param)
 	}
-	return useV2, nil
+	return b, nil
 }
 
 func encodeMessageV2(dm *driver.Message, sub string) *nats.Msg {
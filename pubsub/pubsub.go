@@ -38,6 +38,15 @@
 //   - For some drivers, Nack is not supported and will panic; you can call
 //     Message.Nackable to see.
 //
+// # OpenTelemetry Integration
+//
+// This API reports the method calls below as OpenTelemetry spans, and as the
+// metric "gocdk.pubsub.duration", to the global tracer and meter providers.
+// Subscriptions returned by NewDeadLetterSubscription also report the metrics
+// "gocdk.pubsub.dead_lettered" and "gocdk.pubsub.dead_letter_errors".
+// See https://pkg.go.dev/gocloud.dev/telemetry for the span and metric
+// names, and for configuring exporters.
+//
 // # OpenCensus Integration
 //
 // OpenCensus supports tracing and metric collection for multiple languages and
@@ -83,6 +92,7 @@ import (
 	"gocloud.dev/internal/oc"
 	"gocloud.dev/internal/openurl"
 	"gocloud.dev/internal/retry"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/pubsub/batcher"
 	"gocloud.dev/pubsub/driver"
 	"golang.org/x/sync/errgroup"
@@ -108,6 +118,26 @@ type Message struct {
 	// associated metadata.
 	Metadata map[string]string
 
+	// OrderingKey groups messages that must be delivered in the order they
+	// were sent. Messages with the same OrderingKey are delivered in the order
+	// of the Send calls that sent them, provided each Send returned before
+	// the next one was made; messages with different keys, or with no key,
+	// may be delivered in any order.
+	//
+	// Drivers map it to the service's native concept where one exists
+	// (for example, a Pub/Sub ordering key, an SQS FIFO message group ID, or
+	// a Kafka message key); see the driver documentation for details. Drivers
+	// for services without one document their best-effort behavior.
+	//
+	// When receiving a message, OrderingKey is set if the driver reports it.
+	OrderingKey string
+
+	// DeliveryAttempt is the number of times a received message has been
+	// delivered, including this delivery, as counted by the service. It is
+	// zero if the driver does not report it, and is ignored when sending.
+	// See the driver documentation for details.
+	DeliveryAttempt int
+
 	// BeforeSend is a callback used when sending a message. It will always be
 	// set to nil for received messages.
 	//
@@ -218,7 +248,7 @@ func (m *Message) As(i interface{}) bool {
 type Topic struct {
 	driver  driver.Topic
 	batcher *batcher.Batcher
-	tracer  *oc.Tracer
+	tracer  *telemetry.Tracer
 	mu      sync.Mutex
 	err     error
 
@@ -254,15 +284,27 @@ func (t *Topic) Send(ctx context.Context, m *Message) (err error) {
 			return gcerr.Newf(gcerr.InvalidArgument, nil, "pubsub: Message.Metadata values must be valid UTF-8 strings: %q", v)
 		}
 	}
+	if !utf8.ValidString(m.OrderingKey) {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "pubsub: Message.OrderingKey must be a valid UTF-8 string: %q", m.OrderingKey)
+	}
 	dm := &driver.Message{
-		Body:       m.Body,
-		Metadata:   m.Metadata,
-		BeforeSend: m.BeforeSend,
-		AfterSend:  m.AfterSend,
+		Body:        m.Body,
+		Metadata:    m.Metadata,
+		OrderingKey: m.OrderingKey,
+		BeforeSend:  m.BeforeSend,
+		AfterSend:   m.AfterSend,
 	}
 	return t.batcher.Add(ctx, dm)
 }
 
+// Err returns the error that makes every Send fail, or nil if the Topic can
+// still send messages. It is non-nil once the Topic has been Shutdown.
+func (t *Topic) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
 var errTopicShutdown = gcerr.Newf(gcerr.FailedPrecondition, nil, "pubsub: Topic has been Shutdown")
 
 // Shutdown flushes pending message sends and disconnects the Topic.
@@ -317,7 +359,8 @@ var NewTopic = newTopic
 func newSendBatcher(ctx context.Context, t *Topic, dt driver.Topic, opts *batcher.Options) *batcher.Batcher {
 	handler := func(items interface{}) error {
 		dms := items.([]*driver.Message)
-		err := retry.Call(ctx, gax.Backoff{}, dt.IsRetryable, func() (err error) {
+		isRetryable := t.tracer.CountRetries(ctx, "driver.Topic.SendBatch", dt.IsRetryable)
+		err := retry.Call(ctx, gax.Backoff{}, isRetryable, func() (err error) {
 			ctx2 := t.tracer.Start(ctx, "driver.Topic.SendBatch")
 			defer func() { t.tracer.End(ctx2, err) }()
 			return dt.SendBatch(ctx2, dms)
@@ -355,18 +398,14 @@ var (
 	OpenCensusViews = oc.Views(pkgName, latencyMeasure)
 )
 
-func newTracer(driver interface{}) *oc.Tracer {
-	return &oc.Tracer{
-		Package:        pkgName,
-		Provider:       oc.ProviderName(driver),
-		LatencyMeasure: latencyMeasure,
-	}
+func newTracer(driver interface{}) *telemetry.Tracer {
+	return telemetry.NewTracer(pkgName, telemetry.ProviderName(driver), latencyMeasure, telemetry.DriverAttributes(driver)...)
 }
 
 // Subscription receives published messages.
 type Subscription struct {
 	driver driver.Subscription
-	tracer *oc.Tracer
+	tracer *telemetry.Tracer
 	// ackBatcher makes batches of acks and nacks and sends them to the server.
 	ackBatcher    *batcher.Batcher
 	canNack       bool            // true iff the driver supports Nack
@@ -602,11 +641,13 @@ func (s *Subscription) Receive(ctx context.Context) (_ *Message, err error) {
 				loggableID = "unknown"
 			}
 			m2 := &Message{
-				LoggableID: loggableID,
-				Body:       m.Body,
-				Metadata:   md,
-				asFunc:     m.AsFunc,
-				nackable:   s.canNack,
+				LoggableID:      loggableID,
+				Body:            m.Body,
+				Metadata:        md,
+				OrderingKey:     m.OrderingKey,
+				DeliveryAttempt: m.DeliveryAttempt,
+				asFunc:          m.AsFunc,
+				nackable:        s.canNack,
 			}
 			m2.ack = func(isAck bool) {
 				// Ignore the error channel. Errors are dealt with
@@ -662,7 +703,8 @@ func (s *Subscription) getNextBatch(nMessages int) chan msgsOrError {
 		curMaxMessagesInBatch := maxMessagesInBatch
 		g.Go(func() error {
 			var msgs []*driver.Message
-			err := retry.Call(ctx, gax.Backoff{}, s.driver.IsRetryable, func() error {
+			isRetryable := s.tracer.CountRetries(ctx, "driver.Subscription.ReceiveBatch", s.driver.IsRetryable)
+			err := retry.Call(ctx, gax.Backoff{}, isRetryable, func() error {
 				var err error
 				ctx2 := s.tracer.Start(ctx, "driver.Subscription.ReceiveBatch")
 				defer func() { s.tracer.End(ctx2, err) }()
@@ -686,6 +728,16 @@ func (s *Subscription) getNextBatch(nMessages int) chan msgsOrError {
 	return result
 }
 
+// Err returns the error that makes every Receive fail, or nil if the
+// Subscription can still receive messages. It is non-nil once the
+// Subscription has been Shutdown, or after a non-retryable error from the
+// service.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
 var errSubscriptionShutdown = gcerr.Newf(gcerr.FailedPrecondition, nil, "pubsub: Subscription has been Shutdown")
 
 // Shutdown flushes pending ack sends and disconnects the Subscription.
@@ -781,7 +833,8 @@ func newAckBatcher(ctx context.Context, s *Subscription, ds driver.Subscription,
 		g, ctx := errgroup.WithContext(ctx)
 		if len(acks) > 0 {
 			g.Go(func() error {
-				return retry.Call(ctx, gax.Backoff{}, ds.IsRetryable, func() (err error) {
+				isRetryable := s.tracer.CountRetries(ctx, "driver.Subscription.SendAcks", ds.IsRetryable)
+				return retry.Call(ctx, gax.Backoff{}, isRetryable, func() (err error) {
 					ctx2 := s.tracer.Start(ctx, "driver.Subscription.SendAcks")
 					defer func() { s.tracer.End(ctx2, err) }()
 					return ds.SendAcks(ctx2, acks)
@@ -790,7 +843,8 @@ func newAckBatcher(ctx context.Context, s *Subscription, ds driver.Subscription,
 		}
 		if len(nacks) > 0 {
 			g.Go(func() error {
-				return retry.Call(ctx, gax.Backoff{}, ds.IsRetryable, func() (err error) {
+				isRetryable := s.tracer.CountRetries(ctx, "driver.Subscription.SendNacks", ds.IsRetryable)
+				return retry.Call(ctx, gax.Backoff{}, isRetryable, func() (err error) {
 					ctx2 := s.tracer.Start(ctx, "driver.Subscription.SendNacks")
 					defer func() { s.tracer.End(ctx2, err) }()
 					return ds.SendNacks(ctx2, nacks)
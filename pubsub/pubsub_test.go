@@ -136,6 +136,35 @@ func TestSendReceive(t *testing.T) {
 	m2.Ack()
 }
 
+func TestOrderingKey(t *testing.T) {
+	ctx := context.Background()
+	ds := NewDriverSub()
+	dt := &driverTopic{
+		subs: []*driverSub{ds},
+	}
+	topic := NewTopic(dt, nil)
+	defer topic.Shutdown(ctx)
+	m := &Message{Body: []byte("a"), OrderingKey: "\xff"}
+	if err := topic.Send(ctx, m); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Fatalf("got error %v, want InvalidArgument for an OrderingKey that is not valid UTF-8", err)
+	}
+	m.OrderingKey = "user-1"
+	if err := topic.Send(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := NewSubscription(ds, nil, nil)
+	defer sub.Shutdown(ctx)
+	m2, err := sub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m2.Ack()
+	if m2.OrderingKey != m.OrderingKey {
+		t.Errorf("received message has OrderingKey %q, want %q", m2.OrderingKey, m.OrderingKey)
+	}
+}
+
 func TestConcurrentReceivesGetAllTheMessages(t *testing.T) {
 	howManyToSend := int(1e3)
 	ctx, cancel := context.WithCancel(context.Background())
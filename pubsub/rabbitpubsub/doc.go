@@ -42,6 +42,14 @@
 // See https://godoc.org/gocloud.dev/pubsub#hdr-At_most_once_and_At_least_once_Delivery
 // for more background.
 //
+// RabbitMQ has no ordering keys, so Message.OrderingKey is ignored. A queue
+// delivers messages in the order they were published, but redeliveries and
+// multiple consumers can reorder them; ordering is best-effort.
+//
+// Message.DeliveryAttempt is set from the "x-delivery-count" header, which
+// quorum queues add to redelivered messages. Other queues do not count
+// redeliveries, so it is only set on first deliveries.
+//
 // # As
 //
 // rabbitpubsub exposes the following types for As:
@@ -739,10 +739,11 @@ func toDriverMessage(d amqp.Delivery, opts *SubscriptionOptions) *driver.Message
 		loggableID = fmt.Sprintf("DeliveryTag %d", d.DeliveryTag)
 	}
 	return &driver.Message{
-		LoggableID: loggableID,
-		Body:       d.Body,
-		AckID:      d.DeliveryTag,
-		Metadata:   md,
+		LoggableID:      loggableID,
+		Body:            d.Body,
+		AckID:           d.DeliveryTag,
+		Metadata:        md,
+		DeliveryAttempt: deliveryAttempt(d),
 		AsFunc: func(i interface{}) bool {
 			p, ok := i.(*amqp.Delivery)
 			if !ok {
@@ -754,6 +755,25 @@ func toDriverMessage(d amqp.Delivery, opts *SubscriptionOptions) *driver.Message
 	}
 }
 
+// deliveryAttempt returns the delivery attempt of d, or 0 if it is unknown.
+// Quorum queues count the earlier deliveries of a message in the
+// "x-delivery-count" header; for other queues, only first deliveries are
+// known.
+func deliveryAttempt(d amqp.Delivery) int {
+	switch n := d.Headers["x-delivery-count"].(type) {
+	case int64:
+		return int(n) + 1
+	case int32:
+		return int(n) + 1
+	case int16:
+		return int(n) + 1
+	}
+	if !d.Redelivered {
+		return 1
+	}
+	return 0
+}
+
 // SendAcks implements driver.Subscription.SendAcks.
 func (s *subscription) SendAcks(ctx context.Context, ackIDs []driver.AckID) error {
 	return s.sendAcksOrNacks(ctx, ackIDs, true)
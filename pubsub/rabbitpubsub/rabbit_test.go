@@ -212,6 +212,22 @@ func TestErrorCode(t *testing.T) {
 	}
 }
 
+func TestDeliveryAttempt(t *testing.T) {
+	for _, test := range []struct {
+		d    amqp.Delivery
+		want int
+	}{
+		{amqp.Delivery{}, 1},
+		{amqp.Delivery{Redelivered: true}, 0},
+		{amqp.Delivery{Redelivered: true, Headers: amqp.Table{"x-delivery-count": int64(2)}}, 3},
+		{amqp.Delivery{Redelivered: true, Headers: amqp.Table{"x-delivery-count": int32(1)}}, 2},
+	} {
+		if got := deliveryAttempt(test.d); got != test.want {
+			t.Errorf("%+v: got %d, want %d", test.d.Headers, got, test.want)
+		}
+	}
+}
+
 func TestOpens(t *testing.T) {
 	ctx := context.Background()
 	if got := OpenTopic(nil, "t", nil); got == nil {
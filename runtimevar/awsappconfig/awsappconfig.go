@@ -0,0 +1,530 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awsappconfig provides a runtimevar implementation with variables
+// read from AWS AppConfig configuration profiles
+// (https://docs.aws.amazon.com/appconfig/latest/userguide/what-is-appconfig.html),
+// using the AppConfig Data API and the AWS SDK V2 configuration.
+// Use OpenVariable to construct a *runtimevar.Variable.
+//
+// A variable holds the configuration deployed to an environment of an
+// application with a configuration profile. The variable polls AppConfig at
+// the larger of Options.WaitDuration and the interval requested by AppConfig;
+// AppConfig only returns the configuration again when a new one is deployed.
+//
+// # URLs
+//
+// For runtimevar.OpenVariable, awsappconfig registers for the scheme
+// "awsappconfig". The default URL opener will create an AWS V2 Config with
+// the default credentials and configuration; see
+// https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/ for more details.
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// awsappconfig exposes the following types for As:
+//   - Snapshot: *Configuration
+//   - Error: any error type returned by the service, notably smithy.APIError
+package awsappconfig // import "gocloud.dev/runtimevar/awsappconfig"
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+	gcaws "gocloud.dev/aws"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/runtimevar"
+	"gocloud.dev/runtimevar/driver"
+)
+
+func init() {
+	runtimevar.DefaultURLMux().RegisterVariable(Scheme, new(URLOpener))
+}
+
+// Scheme is the URL scheme awsappconfig registers its URLOpener under on
+// runtimevar.DefaultMux.
+const Scheme = "awsappconfig"
+
+// URLOpener opens AWS AppConfig URLs like
+// "awsappconfig://myapp/myenv/myprofile", for the configuration profile
+// "myprofile" of the application "myapp", deployed to the environment
+// "myenv". The application, environment and profile can be given by their
+// names or IDs.
+//
+// See gocloud.dev/aws/V2ConfigFromURLParams for supported query parameters
+// for overriding the aws.Config from the URL.
+//
+// In addition, the following URL parameters are supported:
+//   - decoder: The decoder to use. Defaults to URLOpener.Decoder, or
+//     runtimevar.BytesDecoder if URLOpener.Decoder is nil.
+//     See runtimevar.DecoderByName for supported values.
+//   - wait: The poll interval, in time.ParseDuration formats.
+//     Defaults to 30s.
+//   - jitter: The fraction of the poll interval to randomize it by, like
+//     "0.1"; see Options.Jitter.
+type URLOpener struct {
+	// Decoder specifies the decoder to use if one is not specified in the URL.
+	// Defaults to runtimevar.BytesDecoder.
+	Decoder *runtimevar.Decoder
+
+	// Options specifies the options to pass to OpenVariable.
+	Options Options
+}
+
+// OpenVariableURL opens the variable at the URL's path. See the package doc
+// for more details.
+func (o *URLOpener) OpenVariableURL(ctx context.Context, u *url.URL) (*runtimevar.Variable, error) {
+	q := u.Query()
+
+	decoderName := q.Get("decoder")
+	q.Del("decoder")
+	decoder, err := runtimevar.DecoderByName(ctx, decoderName, o.Decoder)
+	if err != nil {
+		return nil, fmt.Errorf("open variable %v: invalid decoder: %v", u, err)
+	}
+	opts := o.Options
+	if s := q.Get("wait"); s != "" {
+		q.Del("wait")
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("open variable %v: invalid wait %q: %v", u, s, err)
+		}
+		opts.WaitDuration = d
+	}
+	if s := q.Get("jitter"); s != "" {
+		q.Del("jitter")
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("open variable %v: invalid jitter %q", u, s)
+		}
+		opts.Jitter = f
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if u.Host == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("open variable %v: URL must be like awsappconfig://application/environment/profile", u)
+	}
+	cfg, err := gcaws.V2ConfigFromURLParams(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("open variable %v: %v", u, err)
+	}
+	return OpenVariable(cfg, u.Host, parts[0], parts[1], decoder, &opts)
+}
+
+// Options sets options.
+type Options struct {
+	// WaitDuration controls the rate at which AppConfig is polled. AppConfig
+	// may require a longer interval, which is used instead.
+	// Defaults to 30 seconds.
+	WaitDuration time.Duration
+
+	// Jitter randomizes each poll interval by up to this fraction of it, in
+	// either direction, so that processes watching the same configuration
+	// spread out their requests. For example, 0.1 with a WaitDuration of 30
+	// seconds polls every 27 to 33 seconds. Defaults to 0, for no jitter.
+	Jitter float64
+}
+
+// Configuration is a configuration returned by AppConfig.
+type Configuration struct {
+	// Content is the content of the configuration.
+	Content []byte
+	// ContentType is the media type of the content, like "application/json".
+	ContentType string
+	// VersionLabel is the label of the version of the configuration, if it
+	// has one.
+	VersionLabel string
+}
+
+// OpenVariable constructs a *runtimevar.Variable backed by the configuration
+// profile of an AWS AppConfig application, deployed to environment.
+// The application, environment and profile can be given by their names or
+// IDs.
+// AppConfig returns raw bytes; provide a decoder to decode the raw bytes
+// into the appropriate type for runtimevar.Snapshot.Value.
+// See the runtimevar package documentation for examples of decoders.
+func OpenVariable(cfg awsv2.Config, application, environment, profile string, decoder *runtimevar.Decoder, opts *Options) (*runtimevar.Variable, error) {
+	w, err := newWatcher(cfg, application, environment, profile, decoder, opts)
+	if err != nil {
+		return nil, err
+	}
+	return runtimevar.New(w), nil
+}
+
+func newWatcher(cfg awsv2.Config, application, environment, profile string, decoder *runtimevar.Decoder, opts *Options) (*watcher, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("awsappconfig: the AWS config has no region")
+	}
+	if cfg.Credentials == nil {
+		return nil, errors.New("awsappconfig: the AWS config has no credentials")
+	}
+	endpoint, err := resolveEndpoint(cfg)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &watcher{
+		cfg:         cfg,
+		httpClient:  httpClient,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		signer:      v4.NewSigner(),
+		application: application,
+		environment: environment,
+		profile:     profile,
+		wait:        driver.WaitDuration(opts.WaitDuration),
+		jitter:      opts.Jitter,
+		decoder:     decoder,
+	}, nil
+}
+
+// resolveEndpoint returns the URL of the AppConfig Data API for cfg.
+func resolveEndpoint(cfg awsv2.Config) (string, error) {
+	if cfg.BaseEndpoint != nil {
+		return *cfg.BaseEndpoint, nil
+	}
+	if r := cfg.EndpointResolverWithOptions; r != nil {
+		ep, err := r.ResolveEndpoint("AppConfigData", cfg.Region)
+		if err == nil {
+			return ep.URL, nil
+		}
+		var nf *awsv2.EndpointNotFoundError
+		if !errors.As(err, &nf) {
+			return "", err
+		}
+	}
+	domain := "amazonaws.com"
+	if strings.HasPrefix(cfg.Region, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+	return "https://appconfigdata." + cfg.Region + "." + domain, nil
+}
+
+// state implements driver.State.
+type state struct {
+	val        interface{}
+	raw        *Configuration
+	updateTime time.Time
+	err        error
+}
+
+// Value implements driver.State.Value.
+func (s *state) Value() (interface{}, error) {
+	return s.val, s.err
+}
+
+// UpdateTime implements driver.State.UpdateTime.
+func (s *state) UpdateTime() time.Time {
+	return s.updateTime
+}
+
+// As implements driver.State.As.
+func (s *state) As(i interface{}) bool {
+	p, ok := i.(**Configuration)
+	if !ok || s.raw == nil {
+		return false
+	}
+	*p = s.raw
+	return true
+}
+
+// errorState returns a new State with err, unless prevS also represents
+// the same error, in which case it returns nil.
+func errorState(err error, prevS driver.State) driver.State {
+	s := &state{err: err}
+	if prevS == nil {
+		return s
+	}
+	prev := prevS.(*state)
+	if prev.err == nil {
+		// New error.
+		return s
+	}
+	if equivalentError(err, prev.err) {
+		// Same error, return nil to indicate no change.
+		return nil
+	}
+	return s
+}
+
+// equivalentError returns true iff err1 and err2 represent an equivalent error;
+// i.e., we don't want to return it to the user as a different error.
+func equivalentError(err1, err2 error) bool {
+	if err1 == err2 || err1.Error() == err2.Error() {
+		return true
+	}
+	code1 := getErrorCode(err1)
+	code2 := getErrorCode(err2)
+	return code1 != "" && code1 == code2
+}
+
+type watcher struct {
+	cfg        awsv2.Config
+	httpClient awsv2.HTTPClient
+	endpoint   string
+	signer     *v4.Signer
+
+	application string
+	environment string
+	profile     string
+
+	// wait is the amount of time to wait between querying AWS.
+	wait time.Duration
+	// jitter is the fraction of wait to randomize it by.
+	jitter float64
+	// decoder is the decoder that unmarshals the configuration.
+	decoder *runtimevar.Decoder
+
+	// token is the token to get the next configuration of the session with,
+	// or empty if a session must be started. The watcher is only used by a
+	// single goroutine.
+	token string
+	// full is true if the next configuration of the session is returned
+	// even if it has not changed, because it is the first one.
+	full bool
+}
+
+// startSession starts a configuration session, and returns its first token.
+func (w *watcher) startSession(ctx context.Context) (string, error) {
+	// AppConfig requires poll intervals between 15 seconds and 24 hours.
+	interval := int(w.wait / time.Second)
+	if interval < 15 {
+		interval = 15
+	} else if interval > 86400 {
+		interval = 86400
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"ApplicationIdentifier":                w.application,
+		"EnvironmentIdentifier":                w.environment,
+		"ConfigurationProfileIdentifier":       w.profile,
+		"RequiredMinimumPollIntervalInSeconds": interval,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, respBody, err := w.do(ctx, http.MethodPost, "/configurationsessions", nil, body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("awsappconfig: unexpected status %d starting a configuration session", resp.StatusCode)
+	}
+	var out struct {
+		InitialConfigurationToken string
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", err
+	}
+	if out.InitialConfigurationToken == "" {
+		return "", errors.New("awsappconfig: no configuration token in the response")
+	}
+	return out.InitialConfigurationToken, nil
+}
+
+// getLatest returns the configuration of the session if it changed, or nil.
+// It returns the next token of the session, and the poll interval requested
+// by AppConfig.
+func (w *watcher) getLatest(ctx context.Context) (*Configuration, string, time.Duration, error) {
+	resp, body, err := w.do(ctx, http.MethodGet, "/configuration", url.Values{"configuration_token": {w.token}}, nil)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	next := resp.Header.Get("Next-Poll-Configuration-Token")
+	if next == "" {
+		return nil, "", 0, errors.New("awsappconfig: no configuration token in the response")
+	}
+	var interval time.Duration
+	if n, err := strconv.Atoi(resp.Header.Get("Next-Poll-Interval-In-Seconds")); err == nil {
+		interval = time.Duration(n) * time.Second
+	}
+	// An empty configuration is returned when it has not changed since the
+	// last one of the session.
+	if len(body) == 0 && !w.full {
+		return nil, next, interval, nil
+	}
+	return &Configuration{
+		Content:      body,
+		ContentType:  resp.Header.Get("Content-Type"),
+		VersionLabel: resp.Header.Get("Version-Label"),
+	}, next, interval, nil
+}
+
+// do sends a signed request to the AppConfig Data API, and returns the
+// response and its body. It returns a *smithy.GenericAPIError for errors
+// returned by the service.
+func (w *watcher) do(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Response, []byte, error) {
+	u := w.endpoint + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	creds, err := w.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := sha256.Sum256(body)
+	if err := w.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "appconfig", w.cfg.Region, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, nil, serviceError(resp, respBody)
+	}
+	return resp, respBody, nil
+}
+
+// serviceError returns the error described by an error response.
+func serviceError(resp *http.Response, body []byte) error {
+	var out struct {
+		Message string
+		Type    string `json:"__type"`
+	}
+	json.Unmarshal(body, &out)
+	// The type is like "ResourceNotFoundException:http://internal.amazon.com/...".
+	code := resp.Header.Get("X-Amzn-Errortype")
+	if code == "" {
+		code = out.Type
+	}
+	if i := strings.IndexAny(code, ":#"); i >= 0 {
+		if code[i] == '#' {
+			code = code[i+1:]
+		} else {
+			code = code[:i]
+		}
+	}
+	if code == "" {
+		code = http.StatusText(resp.StatusCode)
+	}
+	if out.Message == "" {
+		out.Message = strings.TrimSpace(string(body))
+	}
+	fault := smithy.FaultClient
+	if resp.StatusCode >= 500 {
+		fault = smithy.FaultServer
+	}
+	return &smithy.GenericAPIError{Code: code, Message: out.Message, Fault: fault}
+}
+
+// WatchVariable implements driver.WatchVariable.
+func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.State, time.Duration) {
+	wait := driver.Jitter(w.wait, w.jitter)
+	if w.token == "" {
+		token, err := w.startSession(ctx)
+		if err != nil {
+			return errorState(err, prev), wait
+		}
+		w.token, w.full = token, true
+	}
+	raw, next, interval, err := w.getLatest(ctx)
+	if err != nil {
+		// Tokens can only be used once, and expire; start a new session on
+		// the next poll.
+		w.token = ""
+		return errorState(err, prev), wait
+	}
+	full := w.full
+	w.token, w.full = next, false
+	if interval > w.wait {
+		wait = driver.Jitter(interval, w.jitter)
+	}
+	if raw == nil {
+		// The configuration hasn't changed, so no change; return nil.
+		return nil, wait
+	}
+	if full && prev != nil {
+		// The first configuration of a new session is returned even if it
+		// hasn't changed.
+		if p := prev.(*state); p.raw != nil && bytes.Equal(p.raw.Content, raw.Content) && p.raw.ContentType == raw.ContentType {
+			return nil, wait
+		}
+	}
+
+	// New configuration. Decode it.
+	val, err := w.decoder.Decode(ctx, raw.Content)
+	if err != nil {
+		s := errorState(err, prev)
+		if s != nil {
+			s.(*state).raw = raw
+		}
+		return s, wait
+	}
+	return &state{val: val, raw: raw, updateTime: time.Now()}, wait
+}
+
+// Close implements driver.Close.
+func (w *watcher) Close() error {
+	return nil
+}
+
+// ErrorAs implements driver.ErrorAs.
+func (w *watcher) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+func getErrorCode(err error) string {
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		return ae.ErrorCode()
+	}
+	return ""
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (w *watcher) ErrorCode(err error) gcerrors.ErrorCode {
+	switch getErrorCode(err) {
+	case "ResourceNotFoundException":
+		return gcerrors.NotFound
+	case "BadRequestException":
+		return gcerrors.InvalidArgument
+	case "AccessDeniedException", "UnrecognizedClientException":
+		return gcerrors.PermissionDenied
+	case "ThrottlingException":
+		return gcerrors.ResourceExhausted
+	}
+	return gcerrors.Unknown
+}
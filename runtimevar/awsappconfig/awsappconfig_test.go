@@ -0,0 +1,367 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsappconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/runtimevar"
+	"gocloud.dev/runtimevar/driver"
+	"gocloud.dev/runtimevar/drivertest"
+)
+
+const (
+	testEnvironment = "test-env"
+	testProfile     = "test-profile"
+)
+
+// fakeServer is an in-memory implementation of the AppConfig Data API.
+// Variables are stored by application name.
+type fakeServer struct {
+	mu        sync.Mutex
+	configs   map[string][]byte
+	sessions  map[string]*fakeSession
+	nextToken int
+	sessionsN int
+}
+
+type fakeSession struct {
+	application string
+	// last is the configuration last returned to the session, or nil.
+	last []byte
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{configs: map[string][]byte{}, sessions: map[string]*fakeSession{}}
+}
+
+func (s *fakeServer) set(application string, val []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if val == nil {
+		delete(s.configs, application)
+	} else {
+		s.configs[application] = val
+	}
+}
+
+func (s *fakeServer) newToken(sess *fakeSession) string {
+	s.nextToken++
+	token := fmt.Sprintf("token-%d", s.nextToken)
+	s.sessions[token] = sess
+	return token
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("X-Amzn-ErrorType", code+":http://internal.amazon.com/coral/com.amazonaws.appconfigdata/")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"Message":%q}`, msg)
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256") || !strings.Contains(auth, "/us-east-1/appconfig/aws4_request") {
+		writeError(w, http.StatusForbidden, "UnrecognizedClientException", "missing signature")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/configurationsessions":
+		var in struct {
+			ApplicationIdentifier                string
+			EnvironmentIdentifier                string
+			ConfigurationProfileIdentifier       string
+			RequiredMinimumPollIntervalInSeconds int
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+			return
+		}
+		if in.RequiredMinimumPollIntervalInSeconds < 15 {
+			writeError(w, http.StatusBadRequest, "BadRequestException", "invalid poll interval")
+			return
+		}
+		if _, ok := s.configs[in.ApplicationIdentifier]; !ok || in.EnvironmentIdentifier != testEnvironment || in.ConfigurationProfileIdentifier != testProfile {
+			writeError(w, http.StatusNotFound, "ResourceNotFoundException", "not found")
+			return
+		}
+		s.sessionsN++
+		token := s.newToken(&fakeSession{application: in.ApplicationIdentifier})
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"InitialConfigurationToken":%q}`, token)
+	case r.Method == http.MethodGet && r.URL.Path == "/configuration":
+		token := r.URL.Query().Get("configuration_token")
+		sess, ok := s.sessions[token]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "BadRequestException", "invalid token")
+			return
+		}
+		delete(s.sessions, token)
+		val, ok := s.configs[sess.application]
+		if !ok {
+			writeError(w, http.StatusNotFound, "ResourceNotFoundException", "not found")
+			return
+		}
+		w.Header().Set("Next-Poll-Configuration-Token", s.newToken(sess))
+		w.Header().Set("Next-Poll-Interval-In-Seconds", "60")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Version-Label", "v1")
+		if sess.last != nil && string(sess.last) == string(val) {
+			return
+		}
+		sess.last = val
+		w.Write(val)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func testConfig(endpoint string) awsv2.Config {
+	return awsv2.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: awsv2.String(endpoint),
+		Credentials: awsv2.CredentialsProviderFunc(func(context.Context) (awsv2.Credentials, error) {
+			return awsv2.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		}),
+	}
+}
+
+type harness struct {
+	server *fakeServer
+	ts     *httptest.Server
+}
+
+func newHarness(t *testing.T) (drivertest.Harness, error) {
+	s := newFakeServer()
+	return &harness{server: s, ts: httptest.NewServer(s)}, nil
+}
+
+func (h *harness) MakeWatcher(ctx context.Context, name string, decoder *runtimevar.Decoder) (driver.Watcher, error) {
+	return newWatcher(testConfig(h.ts.URL), name, testEnvironment, testProfile, decoder, nil)
+}
+
+func (h *harness) CreateVariable(ctx context.Context, name string, val []byte) error {
+	h.server.set(name, val)
+	return nil
+}
+
+func (h *harness) UpdateVariable(ctx context.Context, name string, val []byte) error {
+	h.server.set(name, val)
+	return nil
+}
+
+func (h *harness) DeleteVariable(ctx context.Context, name string) error {
+	h.server.set(name, nil)
+	return nil
+}
+
+func (h *harness) Close() {
+	h.ts.Close()
+}
+
+func (h *harness) Mutable() bool { return true }
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As"
+}
+
+func (verifyAs) SnapshotCheck(s *runtimevar.Snapshot) error {
+	var c *Configuration
+	if !s.As(&c) {
+		return errors.New("Snapshot.As failed for Configuration")
+	}
+	if c.ContentType != "text/plain" || c.VersionLabel != "v1" {
+		return fmt.Errorf("got Configuration %+v, want ContentType text/plain and VersionLabel v1", c)
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(v *runtimevar.Variable, err error) error {
+	var e smithy.APIError
+	if !v.ErrorAs(err, &e) {
+		return errors.New("runtimevar.ErrorAs failed")
+	}
+	if got := e.ErrorCode(); got != "ResourceNotFoundException" {
+		return fmt.Errorf("got error code %q, want ResourceNotFoundException", got)
+	}
+	return nil
+}
+
+// AppConfig-specific tests.
+
+func TestWatchVariableSessions(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeServer()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	s.set("app", []byte("hello"))
+
+	w, err := newWatcher(testConfig(ts.URL), "app", testEnvironment, testProfile, runtimevar.StringDecoder, &Options{WaitDuration: 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, wait := w.WatchVariable(ctx, nil)
+	if state == nil {
+		t.Fatal("got nil state, want a value")
+	}
+	if val, err := state.Value(); err != nil || val != "hello" {
+		t.Fatalf("got %v, %v, want hello", val, err)
+	}
+	// The interval requested by the server is longer than WaitDuration.
+	if wait != time.Minute {
+		t.Errorf("got wait %v, want %v", wait, time.Minute)
+	}
+
+	// The configuration hasn't changed.
+	if got, _ := w.WatchVariable(ctx, state); got != nil {
+		t.Errorf("got state %v, want nil", got)
+	}
+
+	// An expired token starts a new session, which returns the configuration
+	// again; it hasn't changed, so the watcher still reports no change.
+	w.token = "expired"
+	if got, _ := w.WatchVariable(ctx, state); got == nil {
+		t.Fatal("got nil state, want an error for the invalid token")
+	} else if _, err := got.Value(); err == nil {
+		t.Fatal("got no error, want an error for the invalid token")
+	}
+	if got, _ := w.WatchVariable(ctx, state); got != nil {
+		t.Errorf("got state %v, want nil", got)
+	}
+	if s.sessionsN != 2 {
+		t.Errorf("got %d sessions, want 2", s.sessionsN)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want gcerrors.ErrorCode
+	}{
+		{"ResourceNotFoundException", gcerrors.NotFound},
+		{"BadRequestException", gcerrors.InvalidArgument},
+		{"AccessDeniedException", gcerrors.PermissionDenied},
+		{"ThrottlingException", gcerrors.ResourceExhausted},
+		{"InternalServerException", gcerrors.Unknown},
+	}
+	w := &watcher{}
+	for _, test := range tests {
+		if got := w.ErrorCode(&smithy.GenericAPIError{Code: test.code}); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.code, got, test.want)
+		}
+	}
+}
+
+func TestEquivalentError(t *testing.T) {
+	tests := []struct {
+		Err1, Err2 error
+		Want       bool
+	}{
+		{Err1: errors.New("not aws"), Err2: errors.New("not aws"), Want: true},
+		{Err1: errors.New("not aws"), Err2: errors.New("not aws but different")},
+		{Err1: errors.New("not aws"), Err2: &smithy.GenericAPIError{Code: "code1"}},
+		{Err1: &smithy.GenericAPIError{Code: "code1"}, Err2: &smithy.GenericAPIError{Code: "code2"}},
+		{Err1: &smithy.GenericAPIError{Code: "code1"}, Err2: &smithy.GenericAPIError{Code: "code1", Message: "other"}, Want: true},
+	}
+
+	for _, test := range tests {
+		got := equivalentError(test.Err1, test.Err2)
+		if got != test.Want {
+			t.Errorf("%v vs %v: got %v want %v", test.Err1, test.Err2, got, test.Want)
+		}
+	}
+}
+
+func TestResolveEndpoint(t *testing.T) {
+	tests := []struct {
+		cfg  awsv2.Config
+		want string
+	}{
+		{awsv2.Config{Region: "us-west-2"}, "https://appconfigdata.us-west-2.amazonaws.com"},
+		{awsv2.Config{Region: "cn-north-1"}, "https://appconfigdata.cn-north-1.amazonaws.com.cn"},
+		{awsv2.Config{Region: "us-west-2", BaseEndpoint: awsv2.String("http://localhost:2772")}, "http://localhost:2772"},
+	}
+	for _, test := range tests {
+		got, err := resolveEndpoint(test.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestOpenVariable(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"awsappconfig://myapp/myenv/myprofile", false},
+		// OK, setting region.
+		{"awsappconfig://myapp/myenv/myprofile?region=us-west-1", false},
+		// OK, setting decoder.
+		{"awsappconfig://myapp/myenv/myprofile?decoder=string", false},
+		// Invalid decoder.
+		{"awsappconfig://myapp/myenv/myprofile?decoder=notadecoder", true},
+		// OK, setting wait.
+		{"awsappconfig://myapp/myenv/myprofile?wait=2m", false},
+		// Invalid wait.
+		{"awsappconfig://myapp/myenv/myprofile?wait=x", true},
+		// OK, setting jitter.
+		{"awsappconfig://myapp/myenv/myprofile?jitter=0.1", false},
+		// Invalid jitter.
+		{"awsappconfig://myapp/myenv/myprofile?jitter=x", true},
+		// Missing profile.
+		{"awsappconfig://myapp/myenv", true},
+		// Too many path elements.
+		{"awsappconfig://myapp/myenv/myprofile/x", true},
+		// Invalid parameter.
+		{"awsappconfig://myapp/myenv/myprofile?param=value", true},
+	}
+
+	t.Setenv("AWS_ACCESS_KEY", "myaccesskey")
+	t.Setenv("AWS_SECRET_KEY", "mysecretkey")
+	t.Setenv("AWS_REGION", "us-east-1")
+	ctx := context.Background()
+	for _, test := range tests {
+		v, err := runtimevar.OpenVariable(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			v.Close()
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsappconfig_test
+
+import (
+	"context"
+	"log"
+	"time"
+
+	awsv2cfg "github.com/aws/aws-sdk-go-v2/config"
+	"gocloud.dev/runtimevar"
+	"gocloud.dev/runtimevar/awsappconfig"
+)
+
+func ExampleOpenVariable() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+
+	// Establish a AWS V2 Config.
+	// See https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/ for more info.
+	ctx := context.Background()
+	cfg, err := awsv2cfg.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Construct a *runtimevar.Variable that watches the configuration profile
+	// "myprofile" of the application "myapp", deployed to "myenv". Poll about
+	// every minute, give or take 10%.
+	v, err := awsappconfig.OpenVariable(cfg, "myapp", "myenv", "myprofile", runtimevar.StringDecoder, &awsappconfig.Options{
+		WaitDuration: time.Minute,
+		Jitter:       0.1,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer v.Close()
+}
+
+func Example_openVariableFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/runtimevar/awsappconfig"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// runtimevar.OpenVariable creates a *runtimevar.Variable from a URL.
+	// This URL watches the configuration profile "myprofile" of the
+	// application "myapp", deployed to "myenv", and decodes it as JSON.
+	v, err := runtimevar.OpenVariable(ctx, "awsappconfig://myapp/myenv/myprofile?region=us-west-1&decoder=json&jitter=0.1")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer v.Close()
+}
@@ -17,6 +17,17 @@
 // (https://docs.aws.amazon.com/systems-manager/latest/userguide/systems-manager-paramstore.html)
 // Use OpenVariable to construct a *runtimevar.Variable.
 //
+// SecureString parameters are decrypted with their KMS key, which requires
+// the kms:Decrypt permission for it in addition to ssm:GetParameter.
+//
+// Parameters can be written with runtimevar.Variable.Set and CompareAndSet,
+// which require ssm:PutParameter; new parameters are created as String
+// parameters. Parameter Store has no conditional writes, so CompareAndSet
+// compares the version of the parameter with the Snapshot's before writing
+// it; a write by another process in between can be lost. Overwritten
+// SecureString parameters keep their KMS key, which is read with
+// ssm:DescribeParameters.
+//
 // # URLs
 //
 // For runtimevar.OpenVariable, awsparamstore registers for the scheme "awsparamstore".
@@ -40,12 +51,14 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
 	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmv2types "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
@@ -83,6 +96,8 @@ var Set = wire.NewSet(
 //     See runtimevar.DecoderByName for supported values.
 //   - wait: The poll interval, in time.ParseDuration formats.
 //     Defaults to 30s.
+//   - jitter: The fraction of the poll interval to randomize it by, like
+//     "0.1"; see Options.Jitter.
 type URLOpener struct {
 	// UseV2 indicates whether the AWS SDK V2 should be used.
 	UseV2 bool
@@ -148,6 +163,14 @@ func (o *URLOpener) OpenVariableURL(ctx context.Context, u *url.URL) (*runtimeva
 		}
 		opts.WaitDuration = d
 	}
+	if s := q.Get("jitter"); s != "" {
+		q.Del("jitter")
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("open variable %v: invalid jitter %q", u, s)
+		}
+		opts.Jitter = f
+	}
 
 	if o.UseV2 {
 		cfg, err := gcaws.V2ConfigFromURLParams(ctx, q)
@@ -172,6 +195,12 @@ type Options struct {
 	// WaitDuration controls the rate at which Parameter Store is polled.
 	// Defaults to 30 seconds.
 	WaitDuration time.Duration
+
+	// Jitter randomizes each poll interval by up to this fraction of it, in
+	// either direction, so that processes watching the same parameter spread
+	// out their requests. For example, 0.1 with a WaitDuration of 30 seconds
+	// polls every 27 to 33 seconds. Defaults to 0, for no jitter.
+	Jitter float64
 }
 
 // OpenVariable constructs a *runtimevar.Variable backed by the variable name in
@@ -204,6 +233,7 @@ func newWatcher(useV2 bool, sess client.ConfigProvider, clientV2 *ssmv2.Client,
 		clientV2: clientV2,
 		name:     name,
 		wait:     driver.WaitDuration(opts.WaitDuration),
+		jitter:   opts.Jitter,
 		decoder:  decoder,
 	}
 }
@@ -291,6 +321,8 @@ type watcher struct {
 	name string
 	// wait is the amount of time to wait between querying AWS.
 	wait time.Duration
+	// jitter is the fraction of wait to randomize it by.
+	jitter float64
 	// decoder is the decoder that unmarshals the value in the param.
 	decoder *runtimevar.Decoder
 }
@@ -326,6 +358,7 @@ func getParameterV2(ctx context.Context, client *ssmv2.Client, name string) (int
 }
 
 func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.State, time.Duration) {
+	wait := driver.Jitter(w.wait, w.jitter)
 	lastVersion := int64(-1)
 	if prev != nil {
 		lastVersion = prev.(*state).version
@@ -348,17 +381,17 @@ func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.
 		newVersion, newVal, newLastModified, rawGetV1, err = getParameter(svc, w.name)
 	}
 	if err != nil {
-		return errorState(err, prev), w.wait
+		return errorState(err, prev), wait
 	}
 	if newVersion == lastVersion {
 		// Version hasn't changed, so no change; return nil.
-		return nil, w.wait
+		return nil, wait
 	}
 
 	// New value (or at least, new version). Decode it.
 	val, err := w.decoder.Decode(ctx, newVal)
 	if err != nil {
-		return errorState(err, prev), w.wait
+		return errorState(err, prev), wait
 	}
 	return &state{
 		val:        val,
@@ -366,7 +399,130 @@ func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.
 		rawGetV2:   rawGetV2,
 		updateTime: newLastModified,
 		version:    newVersion,
-	}, w.wait
+	}, wait
+}
+
+var errConflict = errors.New("parameter has changed")
+
+// SetVariable implements driver.Setter.SetVariable.
+func (w *watcher) SetVariable(ctx context.Context, val []byte, prev driver.State) error {
+	// Get the current version of the parameter to compare with prev, and its
+	// type, which PutParameter requires.
+	var svc *ssm.SSM
+	if !w.useV2 {
+		svc = ssm.New(w.sess)
+	}
+	var version int64
+	var typ string
+	var err error
+	if w.useV2 {
+		version, typ, err = describeParameterV2(ctx, w.clientV2, w.name)
+	} else {
+		version, typ, err = describeParameter(ctx, svc, w.name)
+	}
+	exists := err == nil
+	if !exists && getErrorCode(err) != "ParameterNotFound" {
+		return err
+	}
+	if prev != nil && (!exists || version != prev.(*state).version) {
+		return errConflict
+	}
+	if !exists {
+		typ = "String"
+	}
+	// PutParameter encrypts a SecureString with the account's default key
+	// unless it is given one, so keep the parameter's key.
+	var keyID *string
+	if typ == "SecureString" {
+		var id string
+		if w.useV2 {
+			id, err = parameterKeyIDV2(ctx, w.clientV2, w.name)
+		} else {
+			id, err = parameterKeyID(ctx, svc, w.name)
+		}
+		if err != nil {
+			return err
+		}
+		keyID = aws.String(id)
+	}
+	// Overwrite is false for new parameters, so that a concurrent creation
+	// fails instead of being overwritten.
+	if w.useV2 {
+		_, err = w.clientV2.PutParameter(ctx, &ssmv2.PutParameterInput{
+			Name:      aws.String(w.name),
+			Value:     aws.String(string(val)),
+			Type:      ssmv2types.ParameterType(typ),
+			KeyId:     keyID,
+			Overwrite: aws.Bool(exists),
+		})
+	} else {
+		_, err = svc.PutParameterWithContext(ctx, &ssm.PutParameterInput{
+			Name:      aws.String(w.name),
+			Value:     aws.String(string(val)),
+			Type:      aws.String(typ),
+			KeyId:     keyID,
+			Overwrite: aws.Bool(exists),
+		})
+	}
+	return err
+}
+
+// parameterKeyID returns the ID of the KMS key of the SecureString parameter
+// name.
+func parameterKeyID(ctx context.Context, svc *ssm.SSM, name string) (string, error) {
+	resp, err := svc.DescribeParametersWithContext(ctx, &ssm.DescribeParametersInput{
+		ParameterFilters: []*ssm.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: []*string{aws.String(name)},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Parameters) != 1 || aws.StringValue(resp.Parameters[0].KeyId) == "" {
+		return "", fmt.Errorf("unable to get the KMS key of %q parameter", name)
+	}
+	return aws.StringValue(resp.Parameters[0].KeyId), nil
+}
+
+func parameterKeyIDV2(ctx context.Context, client *ssmv2.Client, name string) (string, error) {
+	resp, err := client.DescribeParameters(ctx, &ssmv2.DescribeParametersInput{
+		ParameterFilters: []ssmv2types.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: []string{name},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Parameters) != 1 || aws.StringValue(resp.Parameters[0].KeyId) == "" {
+		return "", fmt.Errorf("unable to get the KMS key of %q parameter", name)
+	}
+	return aws.StringValue(resp.Parameters[0].KeyId), nil
+}
+
+func describeParameter(ctx context.Context, svc *ssm.SSM, name string) (int64, string, error) {
+	resp, err := svc.GetParameterWithContext(ctx, &ssm.GetParameterInput{Name: aws.String(name)})
+	if err != nil {
+		return 0, "", err
+	}
+	if resp.Parameter == nil {
+		return 0, "", fmt.Errorf("unable to get %q parameter", name)
+	}
+	return aws.Int64Value(resp.Parameter.Version), aws.StringValue(resp.Parameter.Type), nil
+}
+
+func describeParameterV2(ctx context.Context, client *ssmv2.Client, name string) (int64, string, error) {
+	resp, err := client.GetParameter(ctx, &ssmv2.GetParameterInput{Name: aws.String(name)})
+	if err != nil {
+		return 0, "", err
+	}
+	if resp.Parameter == nil {
+		return 0, "", fmt.Errorf("unable to get %q parameter", name)
+	}
+	return resp.Parameter.Version, string(resp.Parameter.Type), nil
 }
 
 // Close implements driver.Close.
@@ -402,9 +558,14 @@ func getErrorCode(err error) string {
 
 // ErrorCode implements driver.ErrorCode.
 func (w *watcher) ErrorCode(err error) gcerrors.ErrorCode {
-	code := getErrorCode(err)
-	if code == "ParameterNotFound" {
+	if err == errConflict {
+		return gcerrors.FailedPrecondition
+	}
+	switch getErrorCode(err) {
+	case "ParameterNotFound":
 		return gcerrors.NotFound
+	case "ParameterAlreadyExists":
+		return gcerrors.FailedPrecondition
 	}
 	return gcerrors.Unknown
 }
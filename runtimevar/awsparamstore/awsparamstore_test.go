@@ -16,16 +16,22 @@ package awsparamstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/smithy-go"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"gocloud.dev/runtimevar"
 	"gocloud.dev/runtimevar/driver"
@@ -171,6 +177,25 @@ func TestEquivalentError(t *testing.T) {
 	}
 }
 
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		Err  error
+		Want gcerrors.ErrorCode
+	}{
+		{Err: errConflict, Want: gcerrors.FailedPrecondition},
+		{Err: awserr.New("ParameterNotFound", "fail", nil), Want: gcerrors.NotFound},
+		{Err: awserr.New("ParameterAlreadyExists", "fail", nil), Want: gcerrors.FailedPrecondition},
+		{Err: &smithy.GenericAPIError{Code: "ParameterNotFound"}, Want: gcerrors.NotFound},
+		{Err: errors.New("not aws"), Want: gcerrors.Unknown},
+	}
+	w := &watcher{}
+	for _, test := range tests {
+		if got := w.ErrorCode(test.Err); got != test.Want {
+			t.Errorf("%v: got %v want %v", test.Err, got, test.Want)
+		}
+	}
+}
+
 func TestNoConnectionError(t *testing.T) {
 	t.Setenv("AWS_ACCESS_KEY", "myaccesskey")
 	t.Setenv("AWS_SECRET_KEY", "mysecretkey")
@@ -209,6 +234,10 @@ func TestOpenVariable(t *testing.T) {
 		{"awsparamstore://myvar?wait=2m", false},
 		// Invalid wait.
 		{"awsparamstore://myvar?wait=x", true},
+		// OK, setting jitter.
+		{"awsparamstore://myvar?jitter=0.1", false},
+		// Invalid jitter.
+		{"awsparamstore://myvar?jitter=2", true},
 		// Invalid parameter.
 		{"awsparamstore://myvar?param=value", true},
 		// OK, using SDK V2.
@@ -226,3 +255,47 @@ func TestOpenVariable(t *testing.T) {
 		}
 	}
 }
+
+func TestSetVariableKeepsKeyID(t *testing.T) {
+	var put map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSSM."); op {
+		case "GetParameter":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"Parameter": map[string]interface{}{"Name": "myvar", "Type": "SecureString", "Value": "x", "Version": 3},
+			})
+		case "DescribeParameters":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"Parameters": []interface{}{map[string]interface{}{"Name": "myvar", "Type": "SecureString", "KeyId": "alias/mykey", "Version": 3}},
+			})
+		case "PutParameter":
+			put = in
+			json.NewEncoder(w).Encode(map[string]interface{}{"Version": 4})
+		default:
+			http.Error(w, "unsupported "+op, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newWatcher(false, sess, nil, "myvar", runtimevar.StringDecoder, nil)
+	if err := w.SetVariable(context.Background(), []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if put["KeyId"] != "alias/mykey" || put["Type"] != "SecureString" || put["Overwrite"] != true {
+		t.Errorf("got PutParameter %v, want the SecureString overwritten with alias/mykey", put)
+	}
+}
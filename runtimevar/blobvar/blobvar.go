@@ -16,6 +16,12 @@
 // variables read from a blob.Bucket.
 // Use OpenVariable to construct a *runtimevar.Variable.
 //
+// Variables can be written with runtimevar.Variable.Set and CompareAndSet.
+// CompareAndSet makes a conditional write through the bucket's driver: it
+// is supported for buckets from gcsblob, which match the generation of the
+// blob, and s3blob, which match its ETag. For other buckets, it returns an
+// error for which gcerrors.Code returns gcerrors.Unimplemented.
+//
 // # URLs
 //
 // For runtimevar.OpenVariable, blobvar registers for the scheme "blob".
@@ -37,12 +43,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
 	"sync"
 	"time"
 
+	"cloud.google.com/go/storage"
+	s3managerv2 "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"gocloud.dev/blob"
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/runtimevar"
@@ -165,7 +180,60 @@ type state struct {
 	val        interface{}
 	updateTime time.Time
 	rawBytes   []byte
-	err        error
+	// version identifies the version of the blob that val was read from, for
+	// conditional writes; it is nil if the bucket's driver does not support
+	// them.
+	version *version
+	err     error
+}
+
+// version identifies a version of a blob, by its GCS generation or its S3
+// ETag.
+type version struct {
+	generation int64
+	etag       string
+}
+
+// readVersion returns the version of the blob read by r, or nil if its
+// driver does not support conditional writes.
+func readVersion(r *blob.Reader) *version {
+	var gr *storage.Reader
+	if r.As(&gr) {
+		return &version{generation: gr.Attrs.Generation}
+	}
+	var out s3.GetObjectOutput
+	if r.As(&out) {
+		return &version{etag: aws.StringValue(out.ETag)}
+	}
+	var outV2 s3v2.GetObjectOutput
+	if r.As(&outV2) {
+		return &version{etag: aws.StringValue(outV2.ETag)}
+	}
+	return nil
+}
+
+// precondition is a blob.WriterOptions.BeforeWrite function that makes the
+// write conditional on the blob being at version v.
+func (v *version) precondition(asFunc func(interface{}) bool) error {
+	var objp **storage.ObjectHandle
+	if asFunc(&objp) {
+		*objp = (*objp).If(storage.Conditions{GenerationMatch: v.generation})
+		return nil
+	}
+	ifMatch := map[string]string{"If-Match": v.etag}
+	var u *s3manager.Uploader
+	if asFunc(&u) {
+		u.RequestOptions = append(u.RequestOptions, request.WithSetRequestHeaders(ifMatch))
+		return nil
+	}
+	var uV2 *s3managerv2.Uploader
+	if asFunc(&uV2) {
+		uV2.ClientOptions = append(uV2.ClientOptions, func(o *s3v2.Options) {
+			o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue("If-Match", v.etag))
+		})
+		return nil
+	}
+	return errNoConditionalWrites
 }
 
 // Value implements driver.State.Value.
@@ -215,14 +283,23 @@ type watcher struct {
 // WatchVariable implements driver.WatchVariable.
 func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.State, time.Duration) {
 	// Read the blob.
-	b, err := w.bucket.ReadAll(ctx, w.key)
+	r, err := w.bucket.NewReader(ctx, w.key, nil)
 	if err != nil {
 		return errorState(err, prev), w.wait
 	}
-	// See if it's the same raw bytes as before.
+	b, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return errorState(err, prev), w.wait
+	}
+	ver := readVersion(r)
+	// See if it's the same raw bytes as before. A blob rewritten with the
+	// same bytes has a new version, which conditional writes need.
 	if prev != nil && bytes.Equal(b, prev.(*state).rawBytes) {
-		// No change!
-		return nil, w.wait
+		if p := prev.(*state); p.version == nil || ver == nil || *p.version == *ver {
+			// No change!
+			return nil, w.wait
+		}
 	}
 
 	// Decode the value.
@@ -230,7 +307,29 @@ func (w *watcher) WatchVariable(ctx context.Context, prev driver.State) (driver.
 	if err != nil {
 		return errorState(err, prev), w.wait
 	}
-	return &state{val: val, updateTime: time.Now(), rawBytes: b}, w.wait
+	return &state{val: val, updateTime: time.Now(), rawBytes: b, version: ver}, w.wait
+}
+
+var (
+	errConflict            = errors.New("blobvar: the variable has changed")
+	errNoConditionalWrites = errors.New("blobvar: CompareAndSet is not supported for this bucket")
+)
+
+// SetVariable implements driver.Setter.SetVariable.
+func (w *watcher) SetVariable(ctx context.Context, val []byte, prev driver.State) error {
+	if prev == nil {
+		return w.bucket.WriteAll(ctx, w.key, val, nil)
+	}
+	ver := prev.(*state).version
+	if ver == nil {
+		return errNoConditionalWrites
+	}
+	err := w.bucket.WriteAll(ctx, w.key, val, &blob.WriterOptions{BeforeWrite: ver.precondition})
+	switch gcerrors.Code(err) {
+	case gcerrors.FailedPrecondition, gcerrors.NotFound:
+		return errConflict
+	}
+	return err
 }
 
 // Close implements driver.Close.
@@ -247,6 +346,12 @@ func (w *watcher) ErrorAs(err error, i interface{}) bool {
 
 // ErrorCode implements driver.ErrorCode.
 func (*watcher) ErrorCode(err error) gcerrors.ErrorCode {
+	switch err {
+	case errConflict:
+		return gcerrors.FailedPrecondition
+	case errNoConditionalWrites:
+		return gcerrors.Unimplemented
+	}
 	// err might have come from blob, in which case use its code.
 	return gcerrors.Code(err)
 }
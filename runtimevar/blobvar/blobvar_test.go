@@ -17,15 +17,26 @@ package blobvar
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/google/go-cmp/cmp"
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/blob/s3blob"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/runtimevar"
 	"gocloud.dev/runtimevar/driver"
 	"gocloud.dev/runtimevar/drivertest"
@@ -94,6 +105,129 @@ func (verifyAs) ErrorCheck(v *runtimevar.Variable, err error) error {
 	return nil
 }
 
+func TestSetVariable(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	v, err := OpenVariable(bucket, "myvar", runtimevar.StringDecoder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	// Set creates the blob.
+	if err := v.Set(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := v.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Value != "hello" {
+		t.Fatalf("got %v, want hello", snap.Value)
+	}
+	// memblob has no conditional writes.
+	if err := v.CompareAndSet(ctx, snap, []byte("world")); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("got %v, want Unimplemented", err)
+	}
+	if got, err := bucket.ReadAll(ctx, "myvar"); err != nil || string(got) != "hello" {
+		t.Errorf("got %q, %v, want hello", got, err)
+	}
+}
+
+// fakeS3 is an S3 server for a single bucket that supports GetObject and
+// PutObject with an If-Match header.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	etags   map[string]string
+	n       int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	etag, ok := f.etags[key]
+	switch r.Method {
+	case http.MethodGet:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, f.objects[key])
+	case http.MethodPut:
+		if m := r.Header.Get("If-Match"); m != "" && (!ok || m != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			fmt.Fprint(w, "<Error><Code>PreconditionFailed</Code></Error>")
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.n++
+		f.objects[key] = string(b)
+		f.etags[key] = fmt.Sprintf("%q", fmt.Sprint(f.n))
+		w.Header().Set("ETag", f.etags[key])
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestCompareAndSetS3(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{objects: map[string]string{}, etags: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         aws.String(srv.URL),
+		Region:           aws.String("us-east-2"),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket, err := s3blob.OpenBucket(ctx, sess, "bucket", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bucket.Close()
+
+	v, err := OpenVariable(bucket, "myvar", runtimevar.StringDecoder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	if err := v.Set(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := v.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.CompareAndSet(ctx, snap, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.objects["myvar"]; got != "world" {
+		t.Errorf("got %q, want world", got)
+	}
+	// The blob has changed since snap.
+	if err := v.CompareAndSet(ctx, snap, []byte("again")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+	if got := fake.objects["myvar"]; got != "world" {
+		t.Errorf("after conflict: got %q, want world", got)
+	}
+}
+
 func TestOpenVariable(t *testing.T) {
 	dir := t.TempDir()
 
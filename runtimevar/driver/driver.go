@@ -19,6 +19,7 @@ package driver // import "gocloud.dev/runtimevar/driver"
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"gocloud.dev/gcerrors"
@@ -35,6 +36,19 @@ func WaitDuration(d time.Duration) time.Duration {
 	return d
 }
 
+// Jitter returns d, randomly increased or decreased by up to fraction of d,
+// so that processes polling a service at the same rate spread out their
+// requests. fraction is capped at 1; d is returned as is if fraction <= 0.
+func Jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || d <= 0 {
+		return d
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	return d + time.Duration((2*rand.Float64()-1)*fraction*float64(d))
+}
+
 // State represents the current state of a variable.
 type State interface {
 	// Value returns the current variable value.
@@ -97,3 +111,20 @@ type Watcher interface {
 	// one of the other methods in this interface.
 	ErrorCode(error) gcerrors.ErrorCode
 }
+
+// Setter is implemented by Watchers for services that support writing
+// variables. It is optional; runtimevar.Variable.Set returns an Unimplemented
+// error for drivers that don't implement it.
+//
+// SetVariable may be called concurrently with WatchVariable and with itself.
+type Setter interface {
+	// SetVariable sets the variable to val, which is in the format read by the
+	// Watcher's decoder, creating it if needed.
+	//
+	// If prev is not nil, it is a State holding a value that was returned by
+	// this Watcher's WatchVariable, and SetVariable must only set the variable
+	// if it hasn't changed since then, using the service's generation, version
+	// or ETag where possible. Otherwise, it must return an error for which
+	// ErrorCode returns gcerrors.FailedPrecondition.
+	SetVariable(ctx context.Context, val []byte, prev State) error
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	const d = 30 * time.Second
+	if got := Jitter(d, 0); got != d {
+		t.Errorf("got %v with no jitter, want %v", got, d)
+	}
+	for _, fraction := range []float64{0.1, 0.5, 2} {
+		lo, hi := d, d
+		for i := 0; i < 1000; i++ {
+			got := Jitter(d, fraction)
+			if got < lo {
+				lo = got
+			}
+			if got > hi {
+				hi = got
+			}
+		}
+		max := fraction
+		if max > 1 {
+			max = 1
+		}
+		if want := time.Duration((1 - max) * float64(d)); lo < want {
+			t.Errorf("fraction %v: got %v, want at least %v", fraction, lo, want)
+		}
+		if want := time.Duration((1 + max) * float64(d)); hi > want {
+			t.Errorf("fraction %v: got %v, want at most %v", fraction, hi, want)
+		}
+		if lo == d && hi == d {
+			t.Errorf("fraction %v: got no jitter", fraction)
+		}
+	}
+}
@@ -15,6 +15,9 @@
 // Package etcdvar provides a runtimevar implementation with variables
 // backed by etcd. Use OpenVariable to construct a *runtimevar.Variable.
 //
+// Variables can be written with runtimevar.Variable.Set and CompareAndSet;
+// CompareAndSet uses a transaction on the key's modification revision.
+//
 // # URLs
 //
 // For runtimevar.OpenVariable, etcdvar registers for the scheme "etcd".
@@ -145,13 +148,19 @@ func newWatcher(cli *clientv3.Client, name string, decoder *runtimevar.Decoder,
 		// See struct comments for why it's buffered.
 		ch:       make(chan *state, 1),
 		shutdown: cancel,
+		cli:      cli,
+		name:     name,
+		timeout:  driver.WaitDuration(opts.Timeout),
 	}
-	go w.watch(ctx, cli, name, decoder, driver.WaitDuration(opts.Timeout))
+	go w.watch(ctx, cli, name, decoder, w.timeout)
 	return w
 }
 
 // errNotExist is a sentinel error for nonexistent variables.
-var errNotExist = errors.New("variable does not exist")
+var (
+	errNotExist = errors.New("variable does not exist")
+	errConflict = errors.New("variable has changed")
+)
 
 // state implements driver.State.
 type state struct {
@@ -196,6 +205,11 @@ type watcher struct {
 	ch chan *state
 	// shutdown tells the background goroutine to exit.
 	shutdown func()
+
+	// cli, name and timeout are used by SetVariable.
+	cli     *clientv3.Client
+	name    string
+	timeout time.Duration
 }
 
 // WatchVariable implements driver.WatchVariable.
@@ -294,6 +308,28 @@ func (w *watcher) watch(ctx context.Context, cli *clientv3.Client, name string,
 	}
 }
 
+// SetVariable implements driver.Setter.SetVariable.
+func (w *watcher) SetVariable(ctx context.Context, val []byte, prev driver.State) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	if prev == nil {
+		_, err := w.cli.Put(ctx, w.name, string(val))
+		return err
+	}
+	rev := prev.(*state).raw.Kvs[0].ModRevision
+	resp, err := w.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(w.name), "=", rev)).
+		Then(clientv3.OpPut(w.name, string(val))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errConflict
+	}
+	return nil
+}
+
 // Close implements driver.Close.
 func (w *watcher) Close() error {
 	// Tell the background goroutine to shut down by canceling its ctx.
@@ -318,8 +354,11 @@ func (w *watcher) ErrorAs(err error, i interface{}) bool {
 
 // ErrorCode implements driver.ErrorCode.
 func (*watcher) ErrorCode(err error) gcerrors.ErrorCode {
-	if err == errNotExist {
+	switch err {
+	case errNotExist:
 		return gcerrors.NotFound
+	case errConflict:
+		return gcerrors.FailedPrecondition
 	}
 	return gcerrors.Unknown
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/etcdserver/api/v3rpc/rpctypes"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"gocloud.dev/runtimevar"
 	"gocloud.dev/runtimevar/driver"
@@ -144,6 +145,47 @@ func TestNoConnectionError(t *testing.T) {
 	}
 }
 
+func TestSetVariable(t *testing.T) {
+	h, err := newHarness(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := h.(*harness).client
+	ctx := context.Background()
+	const name = "set-var"
+	defer h.DeleteVariable(ctx, name)
+
+	v, err := OpenVariable(cli, name, runtimevar.StringDecoder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if err := v.Set(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := v.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Value != "hello" {
+		t.Fatalf("got %v, want hello", snap.Value)
+	}
+	if err := v.CompareAndSet(ctx, snap, []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	// The variable has changed since snap.
+	if err := v.CompareAndSet(ctx, snap, []byte("again")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+	resp, err := cli.Get(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(resp.Kvs[0].Value); got != "world" {
+		t.Errorf("got %q, want world", got)
+	}
+}
+
 func TestOpenVariable(t *testing.T) {
 	h, err := newHarness(t)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log"
 
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/runtimevar"
 	"gocloud.dev/runtimevar/constantvar"
 	"gocloud.dev/secrets"
@@ -96,6 +97,27 @@ func ExampleVariable_Latest() {
 	_ = snapshot
 }
 
+func ExampleVariable_CompareAndSet() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	var v *runtimevar.Variable
+	ctx := context.Background()
+
+	// Update the variable only if no one else has changed it since snapshot
+	// was read, for example to increment a counter.
+	snapshot, err := v.Latest(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	next := fmt.Sprint(snapshot.Value.(int) + 1)
+	err = v.CompareAndSet(ctx, snapshot, []byte(next))
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		// The variable has changed; read it again and retry.
+	} else if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleSnapshot_As() {
 	// This example is specific to the gcpruntimeconfig implementation; it
 	// demonstrates access to the underlying
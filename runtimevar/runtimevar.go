@@ -18,6 +18,13 @@
 //
 // See https://gocloud.dev/howto/runtimevar/ for a detailed how-to guide.
 //
+// # OpenTelemetry Integration
+//
+// This API reports the OpenTelemetry metric "gocdk.runtimevar.value_changes",
+// a count of the number of times all variables have changed values, by driver,
+// to the global meter provider. See https://pkg.go.dev/gocloud.dev/telemetry
+// for configuring exporters.
+//
 // # OpenCensus Integration
 //
 // OpenCensus supports tracing and metric collection for multiple languages and
@@ -47,9 +54,11 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/metric"
 	"gocloud.dev/internal/gcerr"
 	"gocloud.dev/internal/oc"
 	"gocloud.dev/internal/openurl"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/runtimevar/driver"
 	"gocloud.dev/secrets"
 )
@@ -65,6 +74,8 @@ type Snapshot struct {
 	UpdateTime time.Time
 
 	asFunc func(interface{}) bool
+	// state is the driver.State the Snapshot was made from, for CompareAndSet.
+	state driver.State
 }
 
 // As converts i to driver-specific types.
@@ -93,6 +104,8 @@ var (
 			Aggregation: view.Count(),
 		},
 	}
+
+	changeCounter = telemetry.Int64Counter(pkgName, "value_changes", "Count of variable value changes by provider.", "{change}")
 )
 
 // Variable provides an easy and portable way to watch runtime configuration
@@ -128,7 +141,7 @@ func newVar(w driver.Watcher) *Variable {
 	changed := make(chan struct{})
 	v := &Variable{
 		dw:               w,
-		provider:         oc.ProviderName(w),
+		provider:         telemetry.ProviderName(w),
 		backgroundCancel: cancel,
 		backgroundDone:   make(chan struct{}),
 		haveGoodCh:       make(chan struct{}),
@@ -205,6 +218,7 @@ func (c *Variable) background(ctx context.Context) {
 		prevState = curState
 		_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(oc.ProviderKey, c.provider)}, changeMeasure.M(1))
 		// Error from RecordWithTags is not possible.
+		changeCounter.Add(ctx, 1, metric.WithAttributes(telemetry.ProviderKey.String(c.provider)))
 
 		// Updates under the lock.
 		c.mu.Lock()
@@ -219,6 +233,7 @@ func (c *Variable) background(ctx context.Context) {
 				Value:      val,
 				UpdateTime: curState.UpdateTime(),
 				asFunc:     curState.As,
+				state:      curState,
 			}
 			c.lastErr = nil
 			c.lastGood = c.last
@@ -285,6 +300,46 @@ func (c *Variable) CheckHealth() error {
 	return c.lastErr
 }
 
+// Set sets the variable to val, creating it if needed. val is in the format
+// read by the Variable's decoder; for example, JSON for a Variable created
+// with a JSON decoder. Watch and Latest return the new value once the driver
+// sees the change.
+//
+// Set returns an error for which gcerrors.Code returns gcerrors.Unimplemented
+// if the driver doesn't support writing variables; see the driver package
+// documentation.
+func (c *Variable) Set(ctx context.Context, val []byte) error {
+	return c.set(ctx, val, nil)
+}
+
+// CompareAndSet sets the variable to val like Set, but only if the variable
+// hasn't changed since snap was returned by Watch or Latest on this Variable.
+// Otherwise, it returns an error for which gcerrors.Code returns
+// gcerrors.FailedPrecondition; Watch and Latest will return the current value.
+//
+// Drivers use the service's generation, version or ETag to detect changes
+// when possible; see the driver package documentation for any limitations.
+func (c *Variable) CompareAndSet(ctx context.Context, snap Snapshot, val []byte) error {
+	if snap.state == nil {
+		return gcerr.Newf(gcerr.InvalidArgument, nil, "runtimevar: CompareAndSet requires a Snapshot returned by Watch or Latest")
+	}
+	return c.set(ctx, val, snap.state)
+}
+
+func (c *Variable) set(ctx context.Context, val []byte, prev driver.State) error {
+	s, ok := c.dw.(driver.Setter)
+	if !ok {
+		return gcerr.Newf(gcerr.Unimplemented, nil, "runtimevar: %s does not support setting variables", c.provider)
+	}
+	c.mu.RLock()
+	closed := c.lastErr == ErrClosed
+	c.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+	return wrapError(c.dw, s.SetVariable(ctx, val, prev))
+}
+
 // Close closes the Variable. The Variable is unusable after Close returns.
 func (c *Variable) Close() error {
 	// Record that we're closing. Subsequent calls to Watch/Latest will return ErrClosed.
@@ -412,6 +412,78 @@ func TestErrorsAreWrapped(t *testing.T) {
 	verifyWrap("Close", err)
 }
 
+var errConflict = errors.New("conflict")
+
+// settableWatcher is a fakeWatcher that implements driver.Setter.
+type settableWatcher struct {
+	fakeWatcher
+}
+
+func (w *settableWatcher) SetVariable(ctx context.Context, val []byte, prev driver.State) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if prev != nil && prev != driver.State(w.state) {
+		return errConflict
+	}
+	w.state = &state{val: string(val), updateTime: time.Now()}
+	w.newval = true
+	return nil
+}
+
+func (*settableWatcher) ErrorCode(err error) gcerrors.ErrorCode {
+	if err == errConflict {
+		return gcerrors.FailedPrecondition
+	}
+	return gcerrors.Internal
+}
+
+func TestVariable_Set(t *testing.T) {
+	ctx := context.Background()
+
+	v := New(&fakeWatcher{})
+	if err := v.Set(ctx, []byte("foo")); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("Set without a driver.Setter: got %v, want Unimplemented", err)
+	}
+	v.Close()
+
+	v = New(&settableWatcher{})
+	defer func() {
+		if err := v.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := v.Set(ctx, []byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := v.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Value != "foo" {
+		t.Fatalf("got %v, want foo", snap.Value)
+	}
+
+	if err := v.CompareAndSet(ctx, snap, []byte("bar")); err != nil {
+		t.Fatal(err)
+	}
+	// snap is now stale.
+	if err := v.CompareAndSet(ctx, snap, []byte("baz")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("CompareAndSet with a stale Snapshot: got %v, want FailedPrecondition", err)
+	}
+	if err := v.CompareAndSet(ctx, Snapshot{}, []byte("baz")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("CompareAndSet with a zero Snapshot: got %v, want InvalidArgument", err)
+	}
+	snap, err = v.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for snap.Value != "bar" {
+		if snap, err = v.Watch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 var (
 	testOpenOnce sync.Once
 	testOpenGot  *url.URL
@@ -0,0 +1,251 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agesecrets provides a secrets implementation backed by age
+// (https://age-encryption.org), using X25519 recipients and identities.
+// Use OpenKeeper to construct a *secrets.Keeper.
+//
+// Encrypt encrypts to every configured recipient, so that data can be
+// encrypted to, for example, the keys of several operators and of a CI
+// system. Decrypt succeeds if any configured identity matches one of the
+// recipients the data was encrypted to. A Keeper that only encrypts needs
+// no identities, and one that only decrypts needs no recipients.
+//
+// # URLs
+//
+// For secrets.OpenKeeper, agesecrets registers for the scheme "age".
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// agesecrets exposes the following type for As:
+//   - Error: *age.NoIdentityMatchError
+package agesecrets // import "gocloud.dev/secrets/agesecrets"
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+func init() {
+	secrets.DefaultURLMux().RegisterKeeper(Scheme, &URLOpener{})
+}
+
+// Scheme is the URL scheme agesecrets registers its URLOpener under on
+// secrets.DefaultMux.
+const Scheme = "age"
+
+// URLOpener opens age URLs like
+// "age://?recipient=age1...&identity_file=/path/to/key.txt".
+//
+// The URL host and path must be empty.
+//
+// The following query parameters are supported:
+//   - recipient: An X25519 recipient ("age1...") to encrypt to. May be
+//     repeated.
+//   - recipient_file: A file of recipients, one per line, as accepted by the
+//     age command's -R flag. May be repeated.
+//   - identity_file: A file of X25519 identities ("AGE-SECRET-KEY-1..."), as
+//     generated by age-keygen. May be repeated.
+//   - armor: Sets KeeperOptions.Armor; e.g., "armor=true".
+type URLOpener struct {
+	// Options specifies the options to pass to OpenKeeper.
+	Options KeeperOptions
+}
+
+// OpenKeeperURL opens Keeper URLs.
+func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
+	if u.Host != "" || (u.Path != "" && u.Path != "/") {
+		return nil, fmt.Errorf("open keeper %v: URL host and path must be empty", u)
+	}
+	opts := o.Options
+	var recipients []age.Recipient
+	var identities []age.Identity
+	for param, vals := range u.Query() {
+		for _, val := range vals {
+			switch param {
+			case "recipient":
+				r, err := age.ParseX25519Recipient(val)
+				if err != nil {
+					return nil, fmt.Errorf("open keeper %v: %v", u, err)
+				}
+				recipients = append(recipients, r)
+			case "recipient_file":
+				rs, err := parseFile(val, age.ParseRecipients)
+				if err != nil {
+					return nil, fmt.Errorf("open keeper %v: %v", u, err)
+				}
+				recipients = append(recipients, rs...)
+			case "identity_file":
+				ids, err := parseFile(val, age.ParseIdentities)
+				if err != nil {
+					return nil, fmt.Errorf("open keeper %v: %v", u, err)
+				}
+				identities = append(identities, ids...)
+			case "armor":
+				b, err := strconv.ParseBool(val)
+				if err != nil {
+					return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q: %v", u, val, param, err)
+				}
+				opts.Armor = b
+			default:
+				return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
+			}
+		}
+	}
+	if len(recipients) == 0 && len(identities) == 0 {
+		return nil, fmt.Errorf("open keeper %v: at least one recipient or identity is required", u)
+	}
+	return OpenKeeper(recipients, identities, &opts), nil
+}
+
+// parseFile opens the file at path and parses it with parse.
+func parseFile[T any](path string, parse func(io.Reader) ([]T, error)) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	vs, err := parse(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return vs, nil
+}
+
+// KeeperOptions controls Keeper behaviors.
+type KeeperOptions struct {
+	// Armor makes Encrypt produce ASCII-armored (PEM-encoded) ciphertexts,
+	// which can be stored as text. Decrypt accepts both forms regardless.
+	Armor bool
+}
+
+// OpenKeeper returns a *secrets.Keeper that encrypts to recipients and
+// decrypts with identities.
+// See the package documentation for an example.
+func OpenKeeper(recipients []age.Recipient, identities []age.Identity, opts *KeeperOptions) *secrets.Keeper {
+	if opts == nil {
+		opts = &KeeperOptions{}
+	}
+	return secrets.NewKeeper(&keeper{
+		recipients: recipients,
+		identities: identities,
+		opts:       *opts,
+	})
+}
+
+// keeper implements driver.Keeper.
+type keeper struct {
+	recipients []age.Recipient
+	identities []age.Identity
+	opts       KeeperOptions
+}
+
+var (
+	errNoRecipients = gcerr.Newf(gcerr.FailedPrecondition, nil, "agesecrets: Keeper has no recipients and cannot encrypt")
+	errNoIdentities = gcerr.Newf(gcerr.FailedPrecondition, nil, "agesecrets: Keeper has no identities and cannot decrypt")
+)
+
+// Encrypt encrypts the plaintext to the Keeper's recipients.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if len(k.recipients) == 0 {
+		return nil, errNoRecipients
+	}
+	var buf bytes.Buffer
+	var dst io.Writer = &buf
+	var aw io.WriteCloser
+	if k.opts.Armor {
+		aw = armor.NewWriter(&buf)
+		dst = aw
+	}
+	w, err := age.Encrypt(dst, k.recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if aw != nil {
+		if err := aw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts the ciphertext with the Keeper's identities.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(k.identities) == 0 {
+		return nil, errNoIdentities
+	}
+	var src io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimLeft(ciphertext, " \t\r\n"), []byte(armor.Header)) {
+		src = armor.NewReader(src)
+	}
+	r, err := age.Decrypt(src, k.identities...)
+	if err != nil {
+		return nil, decryptError(err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, decryptError(err)
+	}
+	return plaintext, nil
+}
+
+// decryptError classifies an error from decrypting. Other than a missing
+// identity, age fails to decrypt only if the ciphertext is malformed or has
+// been tampered with.
+func decryptError(err error) error {
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return err
+	}
+	return gcerr.Newf(gcerr.InvalidArgument, err, "agesecrets: failed to decrypt")
+}
+
+// Close implements driver.Keeper.Close.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return gcerrors.PermissionDenied
+	}
+	return gcerrors.Unknown
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agesecrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+)
+
+type harness struct{}
+
+func newDriver() (*keeper, error) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	return &keeper{recipients: []age.Recipient{id.Recipient()}, identities: []age.Identity{id}}, nil
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	k1, err := newDriver()
+	if err != nil {
+		return nil, nil, err
+	}
+	k2, err := newDriver()
+	if err != nil {
+		return nil, nil, err
+	}
+	return k1, k2, nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s *age.NoIdentityMatchError
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs want false for a malformed ciphertext")
+	}
+	return nil
+}
+
+func TestRecipientsAndIdentities(t *testing.T) {
+	ctx := context.Background()
+	operator, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ci, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Encrypt-only Keeper, to both the operator and CI.
+	producer := OpenKeeper([]age.Recipient{operator.Recipient(), ci.Recipient()}, nil, &KeeperOptions{Armor: true})
+	defer producer.Close()
+	const plaintext = "hello world"
+	ciphertext, err := producer.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(ciphertext), "-----BEGIN AGE ENCRYPTED FILE-----") {
+		t.Errorf("got ciphertext %q, want armored", ciphertext)
+	}
+	if _, err := producer.Decrypt(ctx, ciphertext); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("Decrypt without identities: got error %v, want FailedPrecondition", err)
+	}
+
+	for _, id := range []*age.X25519Identity{operator, ci} {
+		consumer := OpenKeeper(nil, []age.Identity{id}, nil)
+		defer consumer.Close()
+		got, err := consumer.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != plaintext {
+			t.Errorf("got %q want %q", got, plaintext)
+		}
+		if _, err := consumer.Encrypt(ctx, got); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+			t.Errorf("Encrypt without recipients: got error %v, want FailedPrecondition", err)
+		}
+	}
+
+	outsider := OpenKeeper(nil, []age.Identity{other}, nil)
+	defer outsider.Close()
+	_, err = outsider.Decrypt(ctx, ciphertext)
+	if gcerrors.Code(err) != gcerrors.PermissionDenied {
+		t.Errorf("Decrypt with a non-matching identity: got error %v, want PermissionDenied", err)
+	}
+	var noMatch *age.NoIdentityMatchError
+	if !outsider.ErrorAs(err, &noMatch) {
+		t.Error("ErrorAs *age.NoIdentityMatchError: got false, want true")
+	}
+}
+
+func TestOpenKeeper(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	identityFile := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(identityFile, []byte("# created: today\n"+id.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	recipientFile := filepath.Join(dir, "recipients.txt")
+	if err := os.WriteFile(recipientFile, []byte(id.Recipient().String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	recipient := id.Recipient().String()
+
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK, recipient and identity.
+		{"age://?recipient=" + recipient + "&identity_file=" + identityFile, false},
+		// OK, recipient file and armor.
+		{"age://?recipient_file=" + recipientFile + "&armor=true", false},
+		// No recipients or identities.
+		{"age://", true},
+		// Invalid recipient.
+		{"age://?recipient=foo", true},
+		// Missing identity file.
+		{"age://?identity_file=" + filepath.Join(dir, "missing.txt"), true},
+		// Invalid armor.
+		{"age://?recipient=" + recipient + "&armor=maybe", true},
+		// Host is not allowed.
+		{"age://foo?recipient=" + recipient, true},
+		// Invalid parameter.
+		{"age://?recipient=" + recipient + "&param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		keeper, err := secrets.OpenKeeper(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = keeper.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
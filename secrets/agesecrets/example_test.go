@@ -0,0 +1,57 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agesecrets_test
+
+import (
+	"context"
+	"log"
+
+	"filippo.io/age"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/agesecrets"
+)
+
+func ExampleOpenKeeper() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+
+	// In practice, load identities with age.ParseIdentities, and recipients
+	// with age.ParseRecipients.
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		log.Fatal(err)
+	}
+	keeper := agesecrets.OpenKeeper(
+		[]age.Recipient{identity.Recipient()},
+		[]age.Identity{identity},
+		nil,
+	)
+	defer keeper.Close()
+}
+
+func Example_openFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/secrets/agesecrets"
+
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	keeper, err := secrets.OpenKeeper(ctx,
+		"age://?recipient=age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p&identity_file=/path/to/key.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer keeper.Close()
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEvent describes one call to a Keeper method that uses its key, for
+// key usage audits. See Keeper.SetAuditFunc.
+type AuditEvent struct {
+	// Method is the name of the Keeper method, for example "Encrypt".
+	Method string
+	// Provider is the name of the driver's provider, for example
+	// "gocloud.dev/secrets/awskms", as used in OpenCensus metrics.
+	Provider string
+	// Labels holds the labels added to the call's context with
+	// WithAuditLabels. It is nil if there are none, and must not be
+	// modified.
+	Labels map[string]string
+	// Start is when the call started.
+	Start time.Time
+	// Latency is how long the call took.
+	Latency time.Duration
+	// Err is the error returned by the call, or nil if it succeeded.
+	// Use gcerrors.Code to classify it.
+	Err error
+}
+
+type auditLabelsKey struct{}
+
+// WithAuditLabels returns a copy of ctx carrying labels, which are reported
+// in the AuditEvent of each Keeper call made with it. Labels already in ctx
+// are kept unless labels has the same key.
+//
+// Labels typically identify the caller and the data being protected, for
+// example a user or tenant ID and an object name; they must not contain
+// secrets.
+func WithAuditLabels(ctx context.Context, labels map[string]string) context.Context {
+	prev := AuditLabels(ctx)
+	merged := make(map[string]string, len(prev)+len(labels))
+	for k, v := range prev {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, auditLabelsKey{}, merged)
+}
+
+// AuditLabels returns the labels added to ctx with WithAuditLabels, or nil
+// if there are none. The returned map must not be modified.
+func AuditLabels(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(auditLabelsKey{}).(map[string]string)
+	return labels
+}
+
+// SetAuditFunc sets a function that is called after every call to Encrypt,
+// Decrypt, EncryptWithAAD, DecryptWithAAD, Sign, Verify, MAC, VerifyMAC and
+// GenerateDataKey, including failed ones, so that key usage can be fed into
+// an audit log or SIEM. EncryptStream and DecryptStream report the
+// GenerateDataKey or Decrypt call they make. fn is called synchronously, so
+// it should return quickly, for example by sending the event to a buffered
+// channel.
+//
+// SetAuditFunc must be called before the Keeper is used; pass nil to stop
+// reporting.
+func (k *Keeper) SetAuditFunc(fn func(ctx context.Context, e *AuditEvent)) {
+	k.auditFunc = fn
+}
+
+// end finishes the trace of a call to method that started at start, and
+// reports it to the audit function if there is one.
+func (k *Keeper) end(ctx context.Context, method string, start time.Time, err error) {
+	k.tracer.End(ctx, err)
+	if k.auditFunc == nil {
+		return
+	}
+	k.auditFunc(ctx, &AuditEvent{
+		Method:   method,
+		Provider: k.tracer.Provider,
+		Labels:   AuditLabels(ctx),
+		Start:    start,
+		Latency:  time.Since(start),
+		Err:      err,
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/gcerrors"
+)
+
+func TestAuditFunc(t *testing.T) {
+	k := NewKeeper(&reverseKeeper{})
+	defer k.Close()
+
+	var events []*AuditEvent
+	k.SetAuditFunc(func(ctx context.Context, e *AuditEvent) {
+		events = append(events, e)
+	})
+
+	ctx := WithAuditLabels(context.Background(), map[string]string{"tenant": "a", "object": "x"})
+	ctx = WithAuditLabels(ctx, map[string]string{"object": "y"})
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Decrypt(context.Background(), ciphertext); err != nil {
+		t.Fatal(err)
+	}
+	// reverseKeeper does not support signing.
+	if _, err := k.Sign(ctx, []byte("digest")); err == nil {
+		t.Fatal("got nil error from Sign, want error")
+	}
+	var buf bytes.Buffer
+	if _, err := k.EncryptStream(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var methods []string
+	for _, e := range events {
+		methods = append(methods, e.Method)
+		if e.Provider == "" || e.Start.IsZero() || e.Latency < 0 {
+			t.Errorf("%s: incomplete event %+v", e.Method, e)
+		}
+	}
+	if diff := cmp.Diff([]string{"Encrypt", "Decrypt", "Sign", "GenerateDataKey"}, methods); diff != "" {
+		t.Fatalf("methods: (-want +got)\n%s", diff)
+	}
+	wantLabels := map[string]string{"tenant": "a", "object": "y"}
+	if diff := cmp.Diff(wantLabels, events[0].Labels); diff != "" {
+		t.Errorf("Encrypt labels: (-want +got)\n%s", diff)
+	}
+	if events[1].Labels != nil {
+		t.Errorf("Decrypt labels: got %v, want nil", events[1].Labels)
+	}
+	if events[0].Err != nil {
+		t.Errorf("Encrypt: got error %v, want nil", events[0].Err)
+	}
+	if gcerrors.Code(events[2].Err) != gcerrors.Unimplemented {
+		t.Errorf("Sign: got error %v, want Unimplemented", events[2].Err)
+	}
+
+	// Clearing the function stops reporting.
+	k.SetAuditFunc(nil)
+	if _, err := k.Encrypt(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Errorf("got %d events after SetAuditFunc(nil), want 4", len(events))
+	}
+}
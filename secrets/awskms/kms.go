@@ -16,6 +16,21 @@
 // Use OpenKeeper to construct a *secrets.Keeper, or OpenKeeperV2 to
 // use AWS SDK V2.
 //
+// # Multi-Region keys
+//
+// With OpenKeeperV2, KeeperOptions.ReplicaKeyIDs can list the ARNs of
+// replicas of a multi-Region key. If a call to the key's own Region fails
+// because KMS is unavailable there, Encrypt, Decrypt and GenerateDataKey are
+// retried against each replica in turn, in the replica's Region. Ciphertexts
+// produced by any replica can be decrypted with any of the others.
+//
+// # Associated data
+//
+// EncryptWithAAD and DecryptWithAAD add the associated data, base64-encoded,
+// to the encryption context under the key "gocloud.dev/aad", alongside
+// KeeperOptions.EncryptionContext. KMS only supports encryption context for
+// symmetric keys.
+//
 // # URLs
 //
 // For secrets.OpenKeeper, awskms registers for the scheme "awskms".
@@ -35,20 +50,28 @@ package awskms // import "gocloud.dev/secrets/awskms"
 
 import (
 	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 	"strings"
 	"sync"
 
 	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	arnv2 "github.com/aws/aws-sdk-go-v2/aws/arn"
+	awshttpv2 "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	kmsv2 "github.com/aws/aws-sdk-go-v2/service/kms"
+	typesv2 "github.com/aws/aws-sdk-go-v2/service/kms/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/google/wire"
 	gcaws "gocloud.dev/aws"
 	"gocloud.dev/gcerrors"
@@ -129,6 +152,20 @@ const Scheme = "awskms"
 // an EncryptionContext of {abc=foo, def=bar}.
 // See https://docs.aws.amazon.com/kms/latest/developerguide/concepts.html#encrypt_context.
 //
+// The "signing_algorithm" URL parameter sets KeeperOptions.SigningAlgorithm;
+// e.g., "...&signing_algorithm=ECDSA_SHA_256".
+//
+// The "mac_algorithm" URL parameter sets KeeperOptions.MACAlgorithm;
+// e.g., "...&mac_algorithm=HMAC_SHA_256".
+//
+// The "encryption_algorithm" URL parameter sets KeeperOptions.EncryptionAlgorithm;
+// e.g., "...&encryption_algorithm=RSAES_OAEP_SHA_256".
+//
+// The "replica_key_id" URL parameter adds to KeeperOptions.ReplicaKeyIDs; it
+// may be repeated, e.g.,
+// "...&replica_key_id=arn:aws:kms:us-west-2:111122223333:key/mrk-1234&replica_key_id=...".
+// It requires AWS SDK V2.
+//
 // For V1, see gocloud.dev/aws/ConfigFromURLParams for supported query parameters
 // for overriding the aws.Session from the URL.
 // For V2, see gocloud.dev/aws/V2ConfigFromURLParams.
@@ -175,6 +212,25 @@ func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Kee
 	if err := addEncryptionContextFromURLParams(&opts, queryParams); err != nil {
 		return nil, err
 	}
+	if alg := queryParams.Get("signing_algorithm"); alg != "" {
+		opts.SigningAlgorithm = alg
+		queryParams.Del("signing_algorithm")
+	}
+	if alg := queryParams.Get("mac_algorithm"); alg != "" {
+		opts.MACAlgorithm = alg
+		queryParams.Del("mac_algorithm")
+	}
+	if alg := queryParams.Get("encryption_algorithm"); alg != "" {
+		opts.EncryptionAlgorithm = alg
+		queryParams.Del("encryption_algorithm")
+	}
+	if replicas := queryParams["replica_key_id"]; len(replicas) > 0 {
+		if !o.UseV2 {
+			return nil, fmt.Errorf("open keeper %v: replica_key_id requires AWS SDK V2", u)
+		}
+		opts.ReplicaKeyIDs = append(append([]string(nil), opts.ReplicaKeyIDs...), replicas...)
+		queryParams.Del("replica_key_id")
+	}
 
 	if o.UseV2 {
 		cfg, err := gcaws.V2ConfigFromURLParams(ctx, queryParams)
@@ -209,11 +265,17 @@ func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Kee
 // for more details.
 // See the package documentation for an example.
 //
+// KeeperOptions.ReplicaKeyIDs requires AWS SDK V2; if it is set, every call
+// of the returned Keeper fails with an InvalidArgument error.
+//
 // Deprecated: AWS no longer supports their V1 API. Please migrate to OpenKeeperV2.
 func OpenKeeper(client *kms.KMS, keyID string, opts *KeeperOptions) *secrets.Keeper {
 	if opts == nil {
 		opts = &KeeperOptions{}
 	}
+	if len(opts.ReplicaKeyIDs) > 0 {
+		return secrets.NewKeeper(&errKeeper{err: gcerr.Newf(gcerr.InvalidArgument, nil, "awskms: ReplicaKeyIDs requires AWS SDK V2; use OpenKeeperV2")})
+	}
 	return secrets.NewKeeper(&keeper{
 		useV2:  false,
 		keyID:  keyID,
@@ -240,6 +302,18 @@ func OpenKeeperV2(client *kmsv2.Client, keyID string, opts *KeeperOptions) *secr
 	})
 }
 
+// errKeeper is a driver.Keeper whose calls all fail with err. OpenKeeper
+// returns it for options that it doesn't support.
+type errKeeper struct {
+	err error
+}
+
+func (k *errKeeper) Decrypt(context.Context, []byte) ([]byte, error) { return nil, k.err }
+func (k *errKeeper) Encrypt(context.Context, []byte) ([]byte, error) { return nil, k.err }
+func (k *errKeeper) Close() error                                    { return nil }
+func (k *errKeeper) ErrorAs(error, interface{}) bool                 { return false }
+func (k *errKeeper) ErrorCode(err error) gcerrors.ErrorCode          { return gcerrors.Code(err) }
+
 type keeper struct {
 	useV2    bool
 	keyID    string
@@ -248,33 +322,132 @@ type keeper struct {
 	clientV2 *kmsv2.Client
 }
 
-func (k *keeper) v1EncryptionContext() map[string]*string {
-	if len(k.opts.EncryptionContext) == 0 {
+// aadContextKey is the encryption context key that holds the associated
+// data passed to EncryptWithAAD and DecryptWithAAD, base64-encoded.
+const aadContextKey = "gocloud.dev/aad"
+
+// encryptionContext returns KeeperOptions.EncryptionContext, with aad added
+// under aadContextKey if it is not empty.
+func (k *keeper) encryptionContext(aad []byte) map[string]string {
+	if len(aad) == 0 {
+		return k.opts.EncryptionContext
+	}
+	ec := make(map[string]string, len(k.opts.EncryptionContext)+1)
+	for k, v := range k.opts.EncryptionContext {
+		ec[k] = v
+	}
+	ec[aadContextKey] = base64.StdEncoding.EncodeToString(aad)
+	return ec
+}
+
+func (k *keeper) v1EncryptionContext(aad []byte) map[string]*string {
+	src := k.encryptionContext(aad)
+	if len(src) == 0 {
 		return nil
 	}
 	ec := map[string]*string{}
-	for k, v := range k.opts.EncryptionContext {
-		ec[k] = &v
+	for k, v := range src {
+		ec[k] = aws.String(v)
 	}
 	return ec
 }
 
+// withFailoverV2 calls op with the keeper's key ID, then with each of
+// KeeperOptions.ReplicaKeyIDs in turn while op fails with an error that
+// indicates that KMS is unavailable in the key's Region. Calls for replicas
+// are sent to the replica's Region.
+func (k *keeper) withFailoverV2(ctx context.Context, op func(keyID string, optFns []func(*kmsv2.Options)) error) error {
+	err := op(k.keyID, nil)
+	for _, replica := range k.opts.ReplicaKeyIDs {
+		if err == nil || !isRegionFailure(ctx, err) {
+			return err
+		}
+		var optFns []func(*kmsv2.Options)
+		if a, perr := arnv2.Parse(replica); perr == nil && a.Region != "" {
+			optFns = append(optFns, func(o *kmsv2.Options) { o.Region = a.Region })
+		}
+		err = op(replica, optFns)
+	}
+	return err
+}
+
+// isRegionFailure reports whether err, returned by a KMS call, means that
+// the call may succeed in another Region.
+func isRegionFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		switch ae.ErrorCode() {
+		case kms.ErrCodeInternalException, kms.ErrCodeKeyUnavailableException, kms.ErrCodeDependencyTimeoutException:
+			return true
+		}
+		var re *awshttpv2.ResponseError
+		return errors.As(err, &re) && re.HTTPStatusCode() >= 500
+	}
+	// The request could not be sent to the Region, or timed out. Other
+	// errors, such as invalid parameters or missing credentials, would fail
+	// in any Region.
+	var se *smithyhttp.RequestSendError
+	if errors.As(err, &se) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
 // Decrypt decrypts the ciphertext into a plaintext.
 func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ctx, ciphertext, nil)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	return k.decrypt(ctx, ciphertext, aad)
+}
+
+func (k *keeper) decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
 	if k.useV2 {
-		result, err := k.clientV2.Decrypt(ctx, &kmsv2.DecryptInput{
-			CiphertextBlob:    ciphertext,
-			EncryptionContext: k.opts.EncryptionContext,
+		var plaintext []byte
+		err := k.withFailoverV2(ctx, func(keyID string, optFns []func(*kmsv2.Options)) error {
+			input := &kmsv2.DecryptInput{
+				CiphertextBlob:    ciphertext,
+				EncryptionContext: k.encryptionContext(aad),
+			}
+			if k.opts.EncryptionAlgorithm != "" {
+				// Asymmetric keys require the key and algorithm to be specified.
+				input.KeyId = aws.String(keyID)
+				input.EncryptionAlgorithm = typesv2.EncryptionAlgorithmSpec(k.opts.EncryptionAlgorithm)
+			} else if optFns != nil {
+				// Name the replica, which shares its key ID with the
+				// key that produced the ciphertext.
+				input.KeyId = aws.String(keyID)
+			}
+			result, err := k.clientV2.Decrypt(ctx, input, optFns...)
+			if err != nil {
+				return err
+			}
+			plaintext = result.Plaintext
+			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
-		return result.Plaintext, nil
+		return plaintext, nil
 	}
-	result, err := k.client.Decrypt(&kms.DecryptInput{
+	input := &kms.DecryptInput{
 		CiphertextBlob:    ciphertext,
-		EncryptionContext: k.v1EncryptionContext(),
-	})
+		EncryptionContext: k.v1EncryptionContext(aad),
+	}
+	if k.opts.EncryptionAlgorithm != "" {
+		input.KeyId = aws.String(k.keyID)
+		input.EncryptionAlgorithm = aws.String(k.opts.EncryptionAlgorithm)
+	}
+	result, err := k.client.Decrypt(input)
 	if err != nil {
 		return nil, err
 	}
@@ -283,28 +456,239 @@ func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 
 // Encrypt encrypts the plaintext into a ciphertext.
 func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.encrypt(ctx, plaintext, nil)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD. The associated
+// data is added to the encryption context, which requires a symmetric key.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return k.encrypt(ctx, plaintext, aad)
+}
+
+func (k *keeper) encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
 	if k.useV2 {
-		result, err := k.clientV2.Encrypt(ctx, &kmsv2.EncryptInput{
-			KeyId:             aws.String(k.keyID),
-			Plaintext:         plaintext,
-			EncryptionContext: k.opts.EncryptionContext,
+		var ciphertext []byte
+		err := k.withFailoverV2(ctx, func(keyID string, optFns []func(*kmsv2.Options)) error {
+			result, err := k.clientV2.Encrypt(ctx, &kmsv2.EncryptInput{
+				KeyId:               aws.String(keyID),
+				Plaintext:           plaintext,
+				EncryptionContext:   k.encryptionContext(aad),
+				EncryptionAlgorithm: typesv2.EncryptionAlgorithmSpec(k.opts.EncryptionAlgorithm),
+			}, optFns...)
+			if err != nil {
+				return err
+			}
+			ciphertext = result.CiphertextBlob
+			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
-		return result.CiphertextBlob, nil
+		return ciphertext, nil
 	}
-	result, err := k.client.Encrypt(&kms.EncryptInput{
+	input := &kms.EncryptInput{
 		KeyId:             aws.String(k.keyID),
 		Plaintext:         plaintext,
-		EncryptionContext: k.v1EncryptionContext(),
-	})
+		EncryptionContext: k.v1EncryptionContext(aad),
+	}
+	if k.opts.EncryptionAlgorithm != "" {
+		input.EncryptionAlgorithm = aws.String(k.opts.EncryptionAlgorithm)
+	}
+	result, err := k.client.Encrypt(input)
 	if err != nil {
 		return nil, err
 	}
 	return result.CiphertextBlob, nil
 }
 
+// GenerateDataKey implements driver.DataKeyGenerator.GenerateDataKey using
+// KMS GenerateDataKey, which requires a symmetric key.
+func (k *keeper) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	if k.useV2 {
+		var result *kmsv2.GenerateDataKeyOutput
+		err := k.withFailoverV2(ctx, func(keyID string, optFns []func(*kmsv2.Options)) error {
+			var err error
+			result, err = k.clientV2.GenerateDataKey(ctx, &kmsv2.GenerateDataKeyInput{
+				KeyId:             aws.String(keyID),
+				KeySpec:           typesv2.DataKeySpecAes256,
+				EncryptionContext: k.opts.EncryptionContext,
+			}, optFns...)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Plaintext, result.CiphertextBlob, nil
+	}
+	result, err := k.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             aws.String(k.keyID),
+		KeySpec:           aws.String(kms.DataKeySpecAes256),
+		EncryptionContext: k.v1EncryptionContext(nil),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Plaintext, result.CiphertextBlob, nil
+}
+
+// PublicKey implements driver.PublicKeyer.PublicKey.
+func (k *keeper) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	var der []byte
+	if k.useV2 {
+		result, err := k.clientV2.GetPublicKey(ctx, &kmsv2.GetPublicKeyInput{KeyId: aws.String(k.keyID)})
+		if err != nil {
+			return nil, err
+		}
+		der = result.PublicKey
+	} else {
+		result, err := k.client.GetPublicKeyWithContext(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(k.keyID)})
+		if err != nil {
+			return nil, err
+		}
+		der = result.PublicKey
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "awskms: failed to parse public key for %q", k.keyID)
+	}
+	return pub, nil
+}
+
+// Sign implements driver.Signer.Sign.
+func (k *keeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	if k.opts.SigningAlgorithm == "" {
+		return nil, errNoSigningAlgorithm
+	}
+	if k.useV2 {
+		result, err := k.clientV2.Sign(ctx, &kmsv2.SignInput{
+			KeyId:            aws.String(k.keyID),
+			Message:          digest,
+			MessageType:      typesv2.MessageTypeDigest,
+			SigningAlgorithm: typesv2.SigningAlgorithmSpec(k.opts.SigningAlgorithm),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Signature, nil
+	}
+	result, err := k.client.SignWithContext(ctx, &kms.SignInput{
+		KeyId:            aws.String(k.keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(k.opts.SigningAlgorithm),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Signature, nil
+}
+
+// Verify implements driver.Signer.Verify.
+func (k *keeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	if k.opts.SigningAlgorithm == "" {
+		return false, errNoSigningAlgorithm
+	}
+	if k.useV2 {
+		result, err := k.clientV2.Verify(ctx, &kmsv2.VerifyInput{
+			KeyId:            aws.String(k.keyID),
+			Message:          digest,
+			MessageType:      typesv2.MessageTypeDigest,
+			Signature:        signature,
+			SigningAlgorithm: typesv2.SigningAlgorithmSpec(k.opts.SigningAlgorithm),
+		})
+		if err != nil {
+			var invalid *typesv2.KMSInvalidSignatureException
+			if errors.As(err, &invalid) {
+				return false, nil
+			}
+			return false, err
+		}
+		return result.SignatureValid, nil
+	}
+	result, err := k.client.VerifyWithContext(ctx, &kms.VerifyInput{
+		KeyId:            aws.String(k.keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		Signature:        signature,
+		SigningAlgorithm: aws.String(k.opts.SigningAlgorithm),
+	})
+	if err != nil {
+		if ae, ok := err.(awserr.Error); ok && ae.Code() == kms.ErrCodeKMSInvalidSignatureException {
+			return false, nil
+		}
+		return false, err
+	}
+	return aws.BoolValue(result.SignatureValid), nil
+}
+
+// MAC implements driver.MACer.MAC.
+func (k *keeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	if k.opts.MACAlgorithm == "" {
+		return nil, errNoMACAlgorithm
+	}
+	if k.useV2 {
+		result, err := k.clientV2.GenerateMac(ctx, &kmsv2.GenerateMacInput{
+			KeyId:        aws.String(k.keyID),
+			Message:      data,
+			MacAlgorithm: typesv2.MacAlgorithmSpec(k.opts.MACAlgorithm),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Mac, nil
+	}
+	result, err := k.client.GenerateMacWithContext(ctx, &kms.GenerateMacInput{
+		KeyId:        aws.String(k.keyID),
+		Message:      data,
+		MacAlgorithm: aws.String(k.opts.MACAlgorithm),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Mac, nil
+}
+
+// VerifyMAC implements driver.MACer.VerifyMAC.
+func (k *keeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	if k.opts.MACAlgorithm == "" {
+		return false, errNoMACAlgorithm
+	}
+	if k.useV2 {
+		result, err := k.clientV2.VerifyMac(ctx, &kmsv2.VerifyMacInput{
+			KeyId:        aws.String(k.keyID),
+			Message:      data,
+			Mac:          mac,
+			MacAlgorithm: typesv2.MacAlgorithmSpec(k.opts.MACAlgorithm),
+		})
+		if err != nil {
+			var invalid *typesv2.KMSInvalidMacException
+			if errors.As(err, &invalid) {
+				return false, nil
+			}
+			return false, err
+		}
+		return result.MacValid, nil
+	}
+	result, err := k.client.VerifyMacWithContext(ctx, &kms.VerifyMacInput{
+		KeyId:        aws.String(k.keyID),
+		Message:      data,
+		Mac:          mac,
+		MacAlgorithm: aws.String(k.opts.MACAlgorithm),
+	})
+	if err != nil {
+		if ae, ok := err.(awserr.Error); ok && ae.Code() == kms.ErrCodeKMSInvalidMacException {
+			return false, nil
+		}
+		return false, err
+	}
+	return aws.BoolValue(result.MacValid), nil
+}
+
+var (
+	errNoSigningAlgorithm = gcerr.Newf(gcerr.FailedPrecondition, nil, "awskms: KeeperOptions.SigningAlgorithm must be set to sign or verify")
+	errNoMACAlgorithm     = gcerr.Newf(gcerr.FailedPrecondition, nil, "awskms: KeeperOptions.MACAlgorithm must be set to compute or verify MACs")
+)
+
 // Close implements driver.Keeper.Close.
 func (k *keeper) Close() error { return nil }
 
@@ -358,6 +742,9 @@ var errorCodeMap = map[string]gcerrors.ErrorCode{
 	kms.ErrCodeInvalidGrantTokenException: gcerrors.PermissionDenied,
 	kms.ErrCodeKeyUnavailableException:    gcerrors.ResourceExhausted,
 	kms.ErrCodeDependencyTimeoutException: gcerrors.DeadlineExceeded,
+	// Returned, for example, by Sign or Verify when the key does not support
+	// the requested SigningAlgorithm.
+	kms.ErrCodeUnsupportedOperationException: gcerrors.Unimplemented,
 }
 
 // KeeperOptions controls Keeper behaviors.
@@ -366,4 +753,31 @@ type KeeperOptions struct {
 	// EncryptionContext parameters.
 	// See https://docs.aws.amazon.com/kms/latest/developerguide/concepts.html#encrypt_context.
 	EncryptionContext map[string]string
+
+	// SigningAlgorithm is the algorithm used by Sign and Verify, for example
+	// "RSASSA_PSS_SHA_256" or "ECDSA_SHA_256". It must be supported by the
+	// key, which must have a KeyUsage of SIGN_VERIFY.
+	// See https://docs.aws.amazon.com/kms/latest/developerguide/asymmetric-key-specs.html#key-spec-rsa-sign.
+	SigningAlgorithm string
+
+	// MACAlgorithm is the algorithm used by MAC and VerifyMAC, for example
+	// "HMAC_SHA_256". It must be supported by the key, which must be an HMAC
+	// key with a KeyUsage of GENERATE_VERIFY_MAC.
+	// See https://docs.aws.amazon.com/kms/latest/developerguide/hmac.html.
+	MACAlgorithm string
+
+	// EncryptionAlgorithm is the algorithm used by Encrypt and Decrypt for
+	// asymmetric keys, for example "RSAES_OAEP_SHA_256". It must be empty for
+	// symmetric keys. Ciphertexts produced with "RSAES_OAEP_SHA_256" can also
+	// be produced offline, without KMS credentials, using
+	// localsecrets.NewPublicKeyKeeper with crypto.SHA256 and the key returned
+	// by secrets.Keeper.PublicKey.
+	// See https://docs.aws.amazon.com/kms/latest/developerguide/asymmetric-key-specs.html#key-spec-rsa-encryption.
+	EncryptionAlgorithm string
+
+	// ReplicaKeyIDs are the ARNs of replicas of the key, which must be a
+	// multi-Region key, in the order to fail over to them. See the package
+	// documentation. They require OpenKeeperV2.
+	// See https://docs.aws.amazon.com/kms/latest/developerguide/multi-region-keys-overview.html.
+	ReplicaKeyIDs []string
 }
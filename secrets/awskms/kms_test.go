@@ -16,17 +16,27 @@ package awskms
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
 	kmsv2 "github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"gocloud.dev/secrets"
 	"gocloud.dev/secrets/driver"
@@ -185,6 +195,26 @@ func TestEncryptionContext(t *testing.T) {
 	}
 }
 
+func TestEncryptionContextAAD(t *testing.T) {
+	opts := KeeperOptions{EncryptionContext: map[string]string{"foo": "bar"}}
+	k := &keeper{opts: opts}
+
+	if diff := cmp.Diff(k.encryptionContext(nil), opts.EncryptionContext); diff != "" {
+		t.Errorf("no associated data: diff %v", diff)
+	}
+	want := map[string]string{"foo": "bar", aadContextKey: "dGVuYW50LTE="}
+	if diff := cmp.Diff(k.encryptionContext([]byte("tenant-1")), want); diff != "" {
+		t.Errorf("with associated data: diff %v", diff)
+	}
+	v1 := k.v1EncryptionContext([]byte("tenant-1"))
+	if len(v1) != 2 || *v1["foo"] != "bar" || *v1[aadContextKey] != "dGVuYW50LTE=" {
+		t.Errorf("v1: got %v", v1)
+	}
+	if _, ok := opts.EncryptionContext[aadContextKey]; ok {
+		t.Error("KeeperOptions.EncryptionContext was modified")
+	}
+}
+
 func TestOpenKeeper(t *testing.T) {
 	tests := []struct {
 		URL     string
@@ -204,8 +234,18 @@ func TestOpenKeeper(t *testing.T) {
 		{"awskms://alias/my-key?awssdk=v2", false},
 		// OK, adding EncryptionContext.
 		{"awskms://alias/my-key?context_abc=foo&context_def=bar", false},
+		// OK, setting signing_algorithm.
+		{"awskms://alias/my-key?signing_algorithm=ECDSA_SHA_256", false},
+		// OK, setting mac_algorithm.
+		{"awskms://alias/my-key?mac_algorithm=HMAC_SHA_256", false},
+		// OK, setting encryption_algorithm.
+		{"awskms://alias/my-key?encryption_algorithm=RSAES_OAEP_SHA_256", false},
 		// Multiple values for an EncryptionContext.
 		{"awskms://alias/my-key?context_abc=foo&context_abc=bar", true},
+		// OK, adding replicas.
+		{"awskms://alias/my-key?awssdk=v2&replica_key_id=arn:aws:kms:us-west-2:111122223333:key/mrk-1&replica_key_id=arn:aws:kms:eu-west-1:111122223333:key/mrk-1", false},
+		// Replicas require V2.
+		{"awskms://alias/my-key?awssdk=v1&replica_key_id=arn:aws:kms:us-west-2:111122223333:key/mrk-1", true},
 		// Unknown parameter.
 		{"awskms://alias/my-key?param=value", true},
 	}
@@ -223,3 +263,114 @@ func TestOpenKeeper(t *testing.T) {
 		}
 	}
 }
+
+// regionalKMS is an HTTP client for KMS that fails in the Regions in down
+// and records the Regions and key IDs of the requests it receives.
+type regionalKMS struct {
+	down map[string]bool
+
+	mu    sync.Mutex
+	calls []string // "region key-id"
+}
+
+func (f *regionalKMS) Do(req *http.Request) (*http.Response, error) {
+	region := strings.Split(req.URL.Host, ".")[1]
+	var body struct{ KeyId string }
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, region+" "+body.KeyId)
+	f.mu.Unlock()
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(`{"CiphertextBlob": "Y2lwaGVydGV4dA==", "Plaintext": "cGxhaW50ZXh0"}`)),
+		Request:    req,
+	}
+	if f.down[region] {
+		resp.StatusCode = http.StatusInternalServerError
+		resp.Header.Set("X-Amzn-Errortype", "KMSInternalException")
+		resp.Body = io.NopCloser(strings.NewReader(`{"__type": "KMSInternalException", "message": "down"}`))
+	}
+	return resp, nil
+}
+
+func TestReplicaFailover(t *testing.T) {
+	const (
+		primary = "arn:aws:kms:us-east-1:111122223333:key/mrk-1"
+		replica = "arn:aws:kms:us-west-2:111122223333:key/mrk-1"
+	)
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name      string
+		down      map[string]bool
+		wantErr   bool
+		wantCalls []string
+	}{
+		{"primary up", nil, false, []string{"us-east-1 " + primary}},
+		{"primary down", map[string]bool{"us-east-1": true}, false, []string{"us-east-1 " + primary, "us-west-2 " + replica}},
+		{"all down", map[string]bool{"us-east-1": true, "us-west-2": true}, true, []string{"us-east-1 " + primary, "us-west-2 " + replica}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &regionalKMS{down: tc.down}
+			client := kmsv2.New(kmsv2.Options{
+				Region:      "us-east-1",
+				Credentials: awsv2.AnonymousCredentials{},
+				HTTPClient:  fake,
+				Retryer:     awsv2.NopRetryer{},
+			})
+			k := OpenKeeperV2(client, primary, &KeeperOptions{ReplicaKeyIDs: []string{replica}})
+			defer k.Close()
+
+			got, err := k.Encrypt(ctx, []byte("plaintext"))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if err == nil && string(got) != "ciphertext" {
+				t.Errorf("got ciphertext %q", got)
+			}
+			if diff := cmp.Diff(fake.calls, tc.wantCalls); diff != "" {
+				t.Errorf("calls diff (-got +want): %s", diff)
+			}
+		})
+	}
+}
+
+func TestIsRegionFailure(t *testing.T) {
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"service unavailable", ctx, &smithy.GenericAPIError{Code: kms.ErrCodeKeyUnavailableException}, true},
+		{"not found", ctx, &smithy.GenericAPIError{Code: kms.ErrCodeNotFoundException}, false},
+		{"send failure", ctx, &smithyhttp.RequestSendError{Err: errors.New("connection refused")}, true},
+		{"network error", ctx, &net.OpError{Op: "dial", Err: errors.New("no route to host")}, true},
+		{"timeout", ctx, fmt.Errorf("operation error: %w", context.DeadlineExceeded), true},
+		{"invalid parameters", ctx, &smithy.InvalidParamsError{Context: "EncryptInput"}, false},
+		{"credentials", ctx, errors.New("failed to refresh cached credentials"), false},
+		{"canceled", canceled, &smithyhttp.RequestSendError{Err: context.Canceled}, false},
+	} {
+		if got := isRegionFailure(tc.ctx, tc.err); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOpenKeeperReplicasRequireV2(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := OpenKeeper(kms.New(sess), keyID1, &KeeperOptions{ReplicaKeyIDs: []string{"arn:aws:kms:us-west-2:111122223333:key/mrk-1"}})
+	defer k.Close()
+	if _, err := k.Encrypt(context.Background(), []byte("plaintext")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v, want InvalidArgument", err)
+	}
+}
@@ -16,6 +16,16 @@
 // See https://docs.microsoft.com/en-us/azure/key-vault/key-vault-whatis for more information.
 // Use OpenKeeper to construct a *secrets.Keeper.
 //
+// Keys in Azure Key Vault Managed HSM are supported too; use the HSM's URI,
+// like "https://{hsm-name}.managedhsm.azure.net/", in place of the vault's.
+//
+// # Key versions
+//
+// A key ID without a version uses the latest version of the key, which
+// changes when the key is rotated. To pin a Keeper to one version, include
+// the version in the key ID; set KeeperOptions.RequireKeyVersion to reject
+// key IDs without one. Use ListKeyVersions to find the versions of a key.
+//
 // # URLs
 //
 // For secrets.OpenKeeper, azurekeyvault registers for the scheme "azurekeyvault".
@@ -38,7 +48,10 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -89,7 +102,9 @@ func (o *defaultDialer) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets
 const Scheme = "azurekeyvault"
 
 // URLOpener opens Azure KeyVault URLs like
-// "azurekeyvault://{keyvault-name}.vault.azure.net/keys/{key-name}/{key-version}?algorithm=RSA-OAEP-256".
+// "azurekeyvault://{keyvault-name}.vault.azure.net/keys/{key-name}/{key-version}?algorithm=RSA-OAEP-256",
+// or Managed HSM URLs like
+// "azurekeyvault://{hsm-name}.managedhsm.azure.net/keys/{key-name}/{key-version}".
 //
 // The "azurekeyvault" URL scheme is replaced with "https" to construct an Azure
 // Key Vault keyID, as described in https://docs.microsoft.com/en-us/azure/key-vault/about-keys-secrets-and-certificates.
@@ -99,6 +114,14 @@ const Scheme = "azurekeyvault"
 // https://docs.microsoft.com/en-us/rest/api/keyvault/encrypt/encrypt#jsonwebkeyencryptionalgorithm
 // for supported algorithms. It defaults to "RSA-OAEP-256".
 //
+// The "signing_algorithm" query parameter sets the algorithm used by Sign and
+// Verify; see
+// https://docs.microsoft.com/en-us/rest/api/keyvault/keys/sign/sign#jsonwebkeysignaturealgorithm
+// for supported algorithms.
+//
+// The "require_version" query parameter sets KeeperOptions.RequireKeyVersion;
+// it must be a boolean value.
+//
 // No other query parameters are supported.
 type URLOpener struct {
 	// ClientMaker defaults to DefaultClientMaker.
@@ -116,6 +139,19 @@ func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Kee
 		o.Options.Algorithm = azkeys.JSONWebKeyEncryptionAlgorithm(algorithm)
 		q.Del("algorithm")
 	}
+	signingAlgorithm := q.Get("signing_algorithm")
+	if signingAlgorithm != "" {
+		o.Options.SigningAlgorithm = azkeys.JSONWebKeySignatureAlgorithm(signingAlgorithm)
+		q.Del("signing_algorithm")
+	}
+	if q.Has("require_version") {
+		b, err := strconv.ParseBool(q.Get("require_version"))
+		if err != nil {
+			return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q: %v", u, q.Get("require_version"), "require_version", err)
+		}
+		o.Options.RequireKeyVersion = b
+		q.Del("require_version")
+	}
 	for param := range q {
 		return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
 	}
@@ -144,6 +180,17 @@ type KeeperOptions struct {
 
 	// DecryptOptions are passed through to Decrypt.
 	DecryptOptions *azkeys.DecryptOptions
+
+	// SigningAlgorithm sets the algorithm used by Sign and Verify, for
+	// example "ES256" or "PS256". It must be set to use Sign or Verify.
+	// See https://docs.microsoft.com/en-us/rest/api/keyvault/keys/sign/sign#jsonwebkeysignaturealgorithm
+	// for more details.
+	SigningAlgorithm azkeys.JSONWebKeySignatureAlgorithm
+
+	// RequireKeyVersion makes OpenKeeper return an error if the key ID does
+	// not include a key version, for deployments where using the latest
+	// version of a key is not acceptable.
+	RequireKeyVersion bool
 }
 
 // DefaultClientMaker returns a function that constructs a KeyVault Client.
@@ -166,14 +213,16 @@ func DefaultClientMaker(keyVaultURI string) (*azkeys.Client, error) {
 }
 
 // Note that the last binding may be just a key, or key/version.
-var keyIDRE = regexp.MustCompile(`^(https://.+\.vault\.(?:[a-z\d-.]+)/)keys/(.+)$`)
+var keyIDRE = regexp.MustCompile(`^(https://.+\.(?:vault|managedhsm)\.(?:[a-z\d-.]+)/)keys/(.+)$`)
 
 // OpenKeeper returns a *secrets.Keeper that uses Azure keyVault.
 //
 // clientMaker is used to construct an azkeys.Client.
 //
-// keyID is a Azure Key Vault key identifier like "https://{keyvault-name}.vault.azure.net/keys/{key-name}/{key-version}".
-// The "/{key-version}" suffix is optional; it defaults to the latest version.
+// keyID is a Azure Key Vault key identifier like "https://{keyvault-name}.vault.azure.net/keys/{key-name}/{key-version}",
+// or a Managed HSM key identifier like "https://{hsm-name}.managedhsm.azure.net/keys/{key-name}/{key-version}".
+// The "/{key-version}" suffix is optional unless opts.RequireKeyVersion is
+// set; it defaults to the latest version.
 // See https://docs.microsoft.com/en-us/azure/key-vault/about-keys-secrets-and-certificates
 // for more details.
 func OpenKeeper(clientMaker ClientMakerT, keyID string, opts *KeeperOptions) (*secrets.Keeper, error) {
@@ -191,17 +240,12 @@ func openKeeper(clientMaker ClientMakerT, keyID string, opts *KeeperOptions) (*k
 	if opts.Algorithm == "" {
 		opts.Algorithm = azkeys.JSONWebKeyEncryptionAlgorithmRSAOAEP256
 	}
-	matches := keyIDRE.FindStringSubmatch(keyID)
-	if len(matches) != 3 {
-		return nil, fmt.Errorf("invalid keyID %q; must match %v %v", keyID, keyIDRE, matches)
+	keyVaultURI, keyName, keyVersion, err := parseKeyID(keyID)
+	if err != nil {
+		return nil, err
 	}
-	// matches[0] is the whole keyID, [1] is the keyVaultURI, and [2] is the key or the key/version.
-	keyVaultURI := matches[1]
-	parts := strings.SplitN(matches[2], "/", 2)
-	keyName := parts[0]
-	var keyVersion string
-	if len(parts) > 1 {
-		keyVersion = parts[1]
+	if opts.RequireKeyVersion && keyVersion == "" {
+		return nil, fmt.Errorf("invalid keyID %q; a key version is required", keyID)
 	}
 	client, err := clientMaker(keyVaultURI)
 	if err != nil {
@@ -216,6 +260,77 @@ func openKeeper(clientMaker ClientMakerT, keyID string, opts *KeeperOptions) (*k
 	}, nil
 }
 
+// parseKeyID splits a key identifier into the vault or HSM URI, the key
+// name, and the key version, which is empty if keyID has none.
+func parseKeyID(keyID string) (keyVaultURI, keyName, keyVersion string, err error) {
+	matches := keyIDRE.FindStringSubmatch(keyID)
+	if len(matches) != 3 {
+		return "", "", "", fmt.Errorf("invalid keyID %q; must match %v %v", keyID, keyIDRE, matches)
+	}
+	// matches[0] is the whole keyID, [1] is the keyVaultURI, and [2] is the key or the key/version.
+	parts := strings.SplitN(matches[2], "/", 2)
+	if len(parts) > 1 {
+		keyVersion = parts[1]
+	}
+	return matches[1], parts[0], keyVersion, nil
+}
+
+// KeyVersion describes a version of a key.
+type KeyVersion struct {
+	// KeyID is the key identifier including the version, suitable for
+	// OpenKeeper.
+	KeyID string
+	// Version is the key version.
+	Version string
+	// Enabled reports whether the version can be used.
+	Enabled bool
+	// Created is when the version was created.
+	Created time.Time
+}
+
+// ListKeyVersions returns the versions of the key identified by keyID,
+// oldest first. Any version in keyID is ignored.
+//
+// clientMaker is used to construct an azkeys.Client, as in OpenKeeper.
+func ListKeyVersions(ctx context.Context, clientMaker ClientMakerT, keyID string) ([]*KeyVersion, error) {
+	keyVaultURI, keyName, _, err := parseKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := clientMaker(keyVaultURI)
+	if err != nil {
+		return nil, err
+	}
+	var versions []*KeyVersion
+	pager := client.NewListKeyVersionsPager(keyName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Value {
+			if item.KID == nil {
+				continue
+			}
+			v := &KeyVersion{
+				KeyID:   string(*item.KID),
+				Version: item.KID.Version(),
+			}
+			if a := item.Attributes; a != nil {
+				v.Enabled = a.Enabled != nil && *a.Enabled
+				if a.Created != nil {
+					v.Created = *a.Created
+				}
+			}
+			versions = append(versions, v)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Created.Before(versions[j].Created)
+	})
+	return versions, nil
+}
+
 // Encrypt encrypts the plaintext into a ciphertext.
 func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
 	keyOpsResult, err := k.client.Encrypt(ctx, k.keyName, k.keyVersion, azkeys.KeyOperationsParameters{
@@ -240,6 +355,39 @@ func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 	return keyOpsResult.Result, nil
 }
 
+// Sign implements driver.Signer.Sign.
+func (k *keeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	if k.options.SigningAlgorithm == "" {
+		return nil, errNoSigningAlgorithm
+	}
+	signResult, err := k.client.Sign(ctx, k.keyName, k.keyVersion, azkeys.SignParameters{
+		Algorithm: &k.options.SigningAlgorithm,
+		Value:     digest,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return signResult.Result, nil
+}
+
+// Verify implements driver.Signer.Verify.
+func (k *keeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	if k.options.SigningAlgorithm == "" {
+		return false, errNoSigningAlgorithm
+	}
+	verifyResult, err := k.client.Verify(ctx, k.keyName, k.keyVersion, azkeys.VerifyParameters{
+		Algorithm: &k.options.SigningAlgorithm,
+		Digest:    digest,
+		Signature: signature,
+	}, nil)
+	if err != nil {
+		return false, err
+	}
+	return verifyResult.Value != nil && *verifyResult.Value, nil
+}
+
+var errNoSigningAlgorithm = gcerr.Newf(gcerr.FailedPrecondition, nil, "azurekeyvault: KeeperOptions.SigningAlgorithm must be set to sign or verify")
+
 // Close implements driver.Keeper.Close.
 func (k *keeper) Close() error { return nil }
 
@@ -17,8 +17,11 @@ package azurekeyvault
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -182,6 +185,14 @@ func TestOpenKeeper(t *testing.T) {
 		{"azurekeyvaultdummy:///vault.azure.net/keys/mykey/myversion", true},
 		// Missing "keys".
 		{"azurekeyvaultdummy://mykeyvault.vault.azure.net/mykey/myversion", true},
+		// Managed HSM -> OK.
+		{"azurekeyvaultdummy://myhsm.managedhsm.azure.net/keys/mykey/myversion", false},
+		// Requiring a version -> OK.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net/keys/mykey/myversion?require_version=true", false},
+		// Requiring a version without one.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net/keys/mykey?require_version=true", true},
+		// Invalid require_version.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net/keys/mykey?require_version=maybe", true},
 	}
 
 	secrets.DefaultURLMux().RegisterKeeper(Scheme+"dummy", &URLOpener{ClientMaker: dummyClientMaker})
@@ -237,6 +248,12 @@ func TestKeyIDRE(t *testing.T) {
 			keyName:     "mykey",
 			keyVersion:  "myversion",
 		},
+		{
+			keyID:       "https://myhsm.managedhsm.azure.net/keys/mykey/myversion",
+			keyVaultURI: "https://myhsm.managedhsm.azure.net/",
+			keyName:     "mykey",
+			keyVersion:  "myversion",
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.keyID, func(t *testing.T) {
@@ -260,3 +277,65 @@ func TestKeyIDRE(t *testing.T) {
 		})
 	}
 }
+
+// fakeListTransport answers list key versions requests with two versions,
+// the newer one first, after the authentication challenge.
+type fakeListTransport struct{}
+
+func (fakeListTransport) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Header: http.Header{"Www-Authenticate": []string{
+				`Bearer authorization="https://login.microsoftonline.com/tenant", resource="https://managedhsm.azure.net"`,
+			}},
+			Body:    io.NopCloser(strings.NewReader("")),
+			Request: req,
+		}, nil
+	}
+	body := `{"value": [
+		{"kid": "https://myhsm.managedhsm.azure.net/keys/mykey/v2", "attributes": {"enabled": true, "created": 1700000200}},
+		{"kid": "https://myhsm.managedhsm.azure.net/keys/mykey/v1", "attributes": {"enabled": false, "created": 1700000100}}
+	]}`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestListKeyVersions(t *testing.T) {
+	clientMaker := func(keyVaultURI string) (*azkeys.Client, error) {
+		return azkeys.NewClient(keyVaultURI, &dummyToken{}, &azkeys.ClientOptions{
+			ClientOptions: policy.ClientOptions{Transport: fakeListTransport{}},
+		})
+	}
+	versions, err := ListKeyVersions(context.Background(), clientMaker, "https://myhsm.managedhsm.azure.net/keys/mykey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2", len(versions))
+	}
+	if v := versions[0]; v.Version != "v1" || v.Enabled || v.KeyID != "https://myhsm.managedhsm.azure.net/keys/mykey/v1" {
+		t.Errorf("got oldest version %+v", v)
+	}
+	if v := versions[1]; v.Version != "v2" || !v.Enabled {
+		t.Errorf("got newest version %+v", v)
+	}
+}
+
+func TestRequireKeyVersion(t *testing.T) {
+	opts := &KeeperOptions{RequireKeyVersion: true}
+	if _, err := openKeeper(dummyClientMaker, "https://mykeyvault.vault.azure.net/keys/mykey", opts); err == nil {
+		t.Error("got nil error for key ID without version, want error")
+	}
+	k, err := openKeeper(dummyClientMaker, "https://mykeyvault.vault.azure.net/keys/mykey/myversion", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.keyVersion != "myversion" {
+		t.Errorf("got key version %q want %q", k.keyVersion, "myversion")
+	}
+}
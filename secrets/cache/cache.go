@@ -0,0 +1,278 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides a *secrets.Keeper that caches the results of
+// another Keeper, to reduce the number of calls made to a key service on hot
+// paths. Use NewKeeper to construct one.
+//
+// Decrypt results are cached by ciphertext for Options.TTL, and
+// DecryptWithAAD results by ciphertext and associated data. At most
+// Options.MaxEntries results are kept; the least recently used result is
+// evicted first. Errors are never cached. Encrypt and EncryptWithAAD are not
+// cached.
+//
+// Optionally, data keys returned by GenerateDataKey can also be reused for
+// a limited time and number of uses (see Options.DataKeyTTL). Combined with
+// the envelope package, this lets many payloads be encrypted with a single
+// key service call:
+//
+//	keeper := envelope.NewKeeper(cache.NewKeeper(kmsKeeper, &cache.Options{
+//		DataKeyTTL: time.Minute,
+//	}))
+//
+// Caching keeps plaintexts and data keys in memory, and reusing data keys
+// means that more data is encrypted under each of them; choose limits that
+// match your security requirements.
+//
+// # As
+//
+// cache exposes the types of the underlying Keeper for As.
+package cache // import "gocloud.dev/secrets/cache"
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+)
+
+const (
+	defaultTTL        = 5 * time.Minute
+	defaultMaxEntries = 1000
+)
+
+// Options controls the caching behavior of the Keeper returned by NewKeeper.
+type Options struct {
+	// TTL is how long a Decrypt result is cached. It defaults to 5 minutes.
+	TTL time.Duration
+
+	// MaxEntries is the maximum number of cached Decrypt results.
+	// It defaults to 1000.
+	MaxEntries int
+
+	// DataKeyTTL is how long a data key returned by GenerateDataKey is
+	// reused for later calls. If it is zero, data keys are not reused.
+	DataKeyTTL time.Duration
+
+	// DataKeyMaxUses is the maximum number of GenerateDataKey calls that
+	// return the same data key. If it is zero, the number of uses is limited
+	// only by DataKeyTTL.
+	DataKeyMaxUses int
+}
+
+// NewKeeper returns a *secrets.Keeper that caches the results of k.
+// Closing the returned Keeper drops the cache; it does not close k.
+func NewKeeper(k *secrets.Keeper, opts *Options) *secrets.Keeper {
+	return secrets.NewKeeper(newKeeper(k, opts))
+}
+
+func newKeeper(k *secrets.Keeper, opts *Options) *keeper {
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.TTL <= 0 {
+		o.TTL = defaultTTL
+	}
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = defaultMaxEntries
+	}
+	return &keeper{
+		k:       k,
+		opts:    o,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: map[[sha256.Size]byte]*list.Element{},
+	}
+}
+
+// keeper implements driver.Keeper, driver.AADKeeper and
+// driver.DataKeyGenerator.
+type keeper struct {
+	k    *secrets.Keeper
+	opts Options
+	now  func() time.Time // replaced in tests
+
+	mu sync.Mutex
+	// lru holds *entry values, most recently used first.
+	lru     *list.List
+	entries map[[sha256.Size]byte]*list.Element
+
+	// dataKeyMu guards dataKey. It is separate from mu so that generating a
+	// data key doesn't block Decrypt.
+	dataKeyMu sync.Mutex
+	dataKey   *dataKey
+}
+
+// entry is a cached Decrypt result.
+type entry struct {
+	sum       [sha256.Size]byte
+	plaintext []byte
+	expires   time.Time
+}
+
+// dataKey is a cached GenerateDataKey result.
+type dataKey struct {
+	plaintext, ciphertext []byte
+	expires               time.Time
+	uses                  int
+}
+
+// Encrypt implements driver.Keeper.Encrypt.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.k.Encrypt(ctx, plaintext)
+}
+
+// Decrypt implements driver.Keeper.Decrypt.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(cacheKey(ciphertext, nil, false), func() ([]byte, error) {
+		return k.k.Decrypt(ctx, ciphertext)
+	})
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return k.k.EncryptWithAAD(ctx, plaintext, aad)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	return k.decrypt(cacheKey(ciphertext, aad, true), func() ([]byte, error) {
+		return k.k.DecryptWithAAD(ctx, ciphertext, aad)
+	})
+}
+
+// decrypt returns the cached plaintext for sum, or calls dec and caches its
+// result.
+func (k *keeper) decrypt(sum [sha256.Size]byte, dec func() ([]byte, error)) ([]byte, error) {
+	if plaintext, ok := k.lookup(sum); ok {
+		return plaintext, nil
+	}
+	plaintext, err := dec()
+	if err != nil {
+		return nil, err
+	}
+	k.store(sum, plaintext)
+	return plaintext, nil
+}
+
+// cacheKey returns the hash that a Decrypt result for ciphertext, or a
+// DecryptWithAAD result for ciphertext and aad if withAAD is true, is cached
+// under. The two never share a hash, so a ciphertext passed to Decrypt
+// can't match a result cached for other associated data.
+func cacheKey(ciphertext, aad []byte, withAAD bool) [sha256.Size]byte {
+	h := sha256.New()
+	if !withAAD {
+		h.Write([]byte{0})
+		h.Write(ciphertext)
+	} else {
+		h.Write([]byte{1})
+		h.Write(binary.AppendUvarint(nil, uint64(len(ciphertext))))
+		h.Write(ciphertext)
+		h.Write(aad)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// lookup returns a copy of the cached plaintext for the ciphertext with
+// hash sum, if there is an unexpired one.
+func (k *keeper) lookup(sum [sha256.Size]byte) ([]byte, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	elem, ok := k.entries[sum]
+	if !ok {
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !k.now().Before(e.expires) {
+		k.lru.Remove(elem)
+		delete(k.entries, sum)
+		return nil, false
+	}
+	k.lru.MoveToFront(elem)
+	return append([]byte(nil), e.plaintext...), true
+}
+
+// store caches a copy of plaintext for the ciphertext with hash sum,
+// evicting the least recently used entries if the cache is full.
+func (k *keeper) store(sum [sha256.Size]byte, plaintext []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	e := &entry{sum: sum, plaintext: append([]byte(nil), plaintext...), expires: k.now().Add(k.opts.TTL)}
+	if elem, ok := k.entries[sum]; ok {
+		elem.Value = e
+		k.lru.MoveToFront(elem)
+		return
+	}
+	k.entries[sum] = k.lru.PushFront(e)
+	for k.lru.Len() > k.opts.MaxEntries {
+		oldest := k.lru.Back()
+		k.lru.Remove(oldest)
+		delete(k.entries, oldest.Value.(*entry).sum)
+	}
+}
+
+// GenerateDataKey implements driver.DataKeyGenerator.GenerateDataKey.
+func (k *keeper) GenerateDataKey(ctx context.Context) (plaintext, ciphertext []byte, err error) {
+	if k.opts.DataKeyTTL <= 0 {
+		return k.k.GenerateDataKey(ctx)
+	}
+	k.dataKeyMu.Lock()
+	defer k.dataKeyMu.Unlock()
+	dk := k.dataKey
+	if dk == nil || !k.now().Before(dk.expires) || (k.opts.DataKeyMaxUses > 0 && dk.uses >= k.opts.DataKeyMaxUses) {
+		// Holding dataKeyMu while generating a key makes concurrent callers
+		// wait for it rather than each generate their own; Decrypt only
+		// needs mu, so it isn't blocked.
+		plaintext, ciphertext, err := k.k.GenerateDataKey(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		dk = &dataKey{plaintext: plaintext, ciphertext: ciphertext, expires: k.now().Add(k.opts.DataKeyTTL)}
+		k.dataKey = dk
+	}
+	dk.uses++
+	return append([]byte(nil), dk.plaintext...), append([]byte(nil), dk.ciphertext...), nil
+}
+
+// Close implements driver.Keeper.Close. It drops the cache, but does not
+// close the underlying Keeper.
+func (k *keeper) Close() error {
+	k.mu.Lock()
+	k.lru.Init()
+	k.entries = map[[sha256.Size]byte]*list.Element{}
+	k.mu.Unlock()
+
+	k.dataKeyMu.Lock()
+	defer k.dataKeyMu.Unlock()
+	k.dataKey = nil
+	return nil
+}
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return k.k.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
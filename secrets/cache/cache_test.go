@@ -0,0 +1,335 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+func newLocalKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(sk)
+}
+
+type harness struct {
+	t *testing.T
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	return newKeeper(newLocalKeeper(h.t), nil), newKeeper(newLocalKeeper(h.t), nil), nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{t: t}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s string
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs expected to fail")
+	}
+	return nil
+}
+
+// countingKeeper is a driver.Keeper that counts calls to another Keeper.
+type countingKeeper struct {
+	k                 *secrets.Keeper
+	decrypts, dataKey int
+
+	// If generating is not nil, GenerateDataKey sends on it, then waits
+	// for release to be closed.
+	generating chan struct{}
+	release    chan struct{}
+}
+
+func (c *countingKeeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return c.k.Encrypt(ctx, plaintext)
+}
+
+func (c *countingKeeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	c.decrypts++
+	return c.k.Decrypt(ctx, ciphertext)
+}
+
+func (c *countingKeeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return c.k.EncryptWithAAD(ctx, plaintext, aad)
+}
+
+func (c *countingKeeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	c.decrypts++
+	return c.k.DecryptWithAAD(ctx, ciphertext, aad)
+}
+
+func (c *countingKeeper) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	c.dataKey++
+	if c.generating != nil {
+		c.generating <- struct{}{}
+		<-c.release
+	}
+	return c.k.GenerateDataKey(ctx)
+}
+
+func (c *countingKeeper) Close() error                           { return nil }
+func (c *countingKeeper) ErrorAs(err error, i interface{}) bool  { return false }
+func (c *countingKeeper) ErrorCode(err error) gcerrors.ErrorCode { return gcerrors.Code(err) }
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestKeeper(t *testing.T, opts *Options) (*keeper, *countingKeeper, *fakeClock) {
+	t.Helper()
+	counter := &countingKeeper{k: newLocalKeeper(t)}
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	k := newKeeper(secrets.NewKeeper(counter), opts)
+	k.now = clock.now
+	return k, counter, clock
+}
+
+func TestDecryptCached(t *testing.T) {
+	ctx := context.Background()
+	k, counter, clock := newTestKeeper(t, &Options{TTL: time.Minute})
+
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		got, err := k.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "hello" {
+			t.Errorf("got %q want %q", got, "hello")
+		}
+		// Modifying the result must not affect the cache.
+		got[0] = 'j'
+	}
+	if counter.decrypts != 1 {
+		t.Errorf("got %d decrypts, want 1", counter.decrypts)
+	}
+
+	clock.t = clock.t.Add(time.Minute)
+	if _, err := k.Decrypt(ctx, ciphertext); err != nil {
+		t.Fatal(err)
+	}
+	if counter.decrypts != 2 {
+		t.Errorf("after TTL: got %d decrypts, want 2", counter.decrypts)
+	}
+}
+
+func TestDecryptWithAADCached(t *testing.T) {
+	ctx := context.Background()
+	k, counter, _ := newTestKeeper(t, nil)
+
+	aad := []byte("row 1")
+	ciphertext, err := k.EncryptWithAAD(ctx, []byte("hello"), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err := k.DecryptWithAAD(ctx, ciphertext, aad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "hello" {
+			t.Errorf("got %q want %q", got, "hello")
+		}
+	}
+	if counter.decrypts != 1 {
+		t.Errorf("got %d decrypts, want 1", counter.decrypts)
+	}
+	// The cached result is only for the same associated data.
+	if _, err := k.DecryptWithAAD(ctx, ciphertext, []byte("row 2")); err == nil {
+		t.Error("with other associated data: got nil error, want error")
+	}
+	if _, err := k.Decrypt(ctx, ciphertext); err == nil {
+		t.Error("without associated data: got nil error, want error")
+	}
+	if counter.decrypts != 3 {
+		t.Errorf("got %d decrypts, want 3", counter.decrypts)
+	}
+}
+
+func TestDecryptErrorNotCached(t *testing.T) {
+	ctx := context.Background()
+	k, counter, _ := newTestKeeper(t, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := k.Decrypt(ctx, []byte("garbage")); err == nil {
+			t.Fatal("got nil error, want error")
+		}
+	}
+	if counter.decrypts != 2 {
+		t.Errorf("got %d decrypts, want 2", counter.decrypts)
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	k, counter, _ := newTestKeeper(t, &Options{MaxEntries: 2})
+
+	var ciphertexts [][]byte
+	for _, p := range []string{"a", "b", "c"} {
+		c, err := k.Encrypt(ctx, []byte(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ciphertexts = append(ciphertexts, c)
+	}
+	// Decrypt a and b, use a again, then c evicts b.
+	for _, i := range []int{0, 1, 0, 2} {
+		if _, err := k.Decrypt(ctx, ciphertexts[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if counter.decrypts != 3 {
+		t.Fatalf("got %d decrypts, want 3", counter.decrypts)
+	}
+	if _, err := k.Decrypt(ctx, ciphertexts[0]); err != nil {
+		t.Fatal(err)
+	}
+	if counter.decrypts != 3 {
+		t.Errorf("a was evicted: got %d decrypts, want 3", counter.decrypts)
+	}
+	if _, err := k.Decrypt(ctx, ciphertexts[1]); err != nil {
+		t.Fatal(err)
+	}
+	if counter.decrypts != 4 {
+		t.Errorf("b was not evicted: got %d decrypts, want 4", counter.decrypts)
+	}
+}
+
+func TestDataKeyNotReusedByDefault(t *testing.T) {
+	ctx := context.Background()
+	k, counter, _ := newTestKeeper(t, nil)
+
+	p1, _, err := k.GenerateDataKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, _, err := k.GenerateDataKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(p1, p2) || counter.dataKey != 2 {
+		t.Errorf("data key was reused: %d calls", counter.dataKey)
+	}
+}
+
+func TestDataKeyReuse(t *testing.T) {
+	ctx := context.Background()
+	k, counter, clock := newTestKeeper(t, &Options{DataKeyTTL: time.Minute, DataKeyMaxUses: 3})
+
+	var keys [][]byte
+	for i := 0; i < 4; i++ {
+		p, _, err := k.GenerateDataKey(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, p)
+	}
+	if !bytes.Equal(keys[0], keys[2]) || bytes.Equal(keys[2], keys[3]) {
+		t.Error("data key not reused exactly DataKeyMaxUses times")
+	}
+	if counter.dataKey != 2 {
+		t.Errorf("got %d GenerateDataKey calls, want 2", counter.dataKey)
+	}
+
+	clock.t = clock.t.Add(time.Minute)
+	p, _, err := k.GenerateDataKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(p, keys[3]) {
+		t.Error("data key reused after DataKeyTTL")
+	}
+}
+
+func TestDataKeyDoesNotBlockDecrypt(t *testing.T) {
+	ctx := context.Background()
+	k, counter, _ := newTestKeeper(t, &Options{DataKeyTTL: time.Minute})
+
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Decrypt(ctx, ciphertext); err != nil {
+		t.Fatal(err)
+	}
+
+	counter.generating = make(chan struct{})
+	counter.release = make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, _, err := k.GenerateDataKey(ctx)
+		done <- err
+	}()
+	<-counter.generating
+	// The data key is being generated; cached Decrypts still succeed.
+	if got, err := k.Decrypt(ctx, ciphertext); err != nil || string(got) != "hello" {
+		t.Errorf("got %q, %v want %q", got, err, "hello")
+	}
+	close(counter.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	underlying := newLocalKeeper(t)
+	k := NewKeeper(underlying, nil)
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The underlying Keeper is still open.
+	if _, err := underlying.Decrypt(ctx, ciphertext); err != nil {
+		t.Errorf("underlying Keeper: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chain provides a *secrets.Keeper for migrating ciphertexts
+// between Keepers, possibly backed by different providers. Use NewKeeper to
+// construct one from a primary Keeper and an ordered list of fallbacks.
+//
+// Encrypt always uses the primary Keeper, and its ciphertexts are exactly
+// those of the primary Keeper, so once migration is complete the chain can
+// be replaced by the primary Keeper alone. Decrypt tries the primary Keeper
+// first, then each fallback in order, and returns the first plaintext.
+//
+// For example, to move from a Vault key to a KMS key:
+//
+//	keeper := chain.NewKeeper(kmsKeeper, []*secrets.Keeper{vaultKeeper}, &chain.Options{
+//		OnFallback: func(ctx context.Context, i int) { fallbackCount.Add(1) },
+//	})
+//
+// EncryptWithAAD and DecryptWithAAD work the same way, passing the
+// associated data to each Keeper.
+//
+// Unlike package rotation, chain does not record which Keeper encrypted a
+// ciphertext, so decrypting old ciphertexts costs a failed call to each
+// Keeper before the one that succeeds.
+//
+// # As
+//
+// chain does not support any types for As; use the As functions of the
+// underlying Keepers.
+package chain // import "gocloud.dev/secrets/chain"
+
+import (
+	"context"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+// Options controls the behavior of the Keeper returned by NewKeeper.
+type Options struct {
+	// OnFallback, if not nil, is called after a fallback Keeper decrypts a
+	// ciphertext that the primary Keeper could not, with the index of the
+	// fallback. Applications can use it to track migration progress or to
+	// re-encrypt the data with the primary Keeper.
+	OnFallback func(ctx context.Context, i int)
+}
+
+// NewKeeper returns a *secrets.Keeper that encrypts with primary and
+// decrypts with primary or, failing that, the first of fallbacks that
+// succeeds. opts may be nil.
+//
+// Closing the returned Keeper does not close primary or fallbacks.
+func NewKeeper(primary *secrets.Keeper, fallbacks []*secrets.Keeper, opts *Options) *secrets.Keeper {
+	return secrets.NewKeeper(newKeeper(primary, fallbacks, opts))
+}
+
+func newKeeper(primary *secrets.Keeper, fallbacks []*secrets.Keeper, opts *Options) *keeper {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &keeper{primary: primary, fallbacks: fallbacks, opts: *opts}
+}
+
+// keeper implements driver.Keeper, driver.AADKeeper and
+// driver.DataKeyGenerator.
+type keeper struct {
+	primary   *secrets.Keeper
+	fallbacks []*secrets.Keeper
+	opts      Options
+}
+
+// Encrypt implements driver.Keeper.Encrypt.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.primary.Encrypt(ctx, plaintext)
+}
+
+// Decrypt implements driver.Keeper.Decrypt.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ctx, func(kp *secrets.Keeper) ([]byte, error) {
+		return kp.Decrypt(ctx, ciphertext)
+	})
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return k.primary.EncryptWithAAD(ctx, plaintext, aad)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	return k.decrypt(ctx, func(kp *secrets.Keeper) ([]byte, error) {
+		return kp.DecryptWithAAD(ctx, ciphertext, aad)
+	})
+}
+
+// decrypt decrypts using dec with the primary Keeper or, failing that, the
+// first of the fallbacks that succeeds.
+func (k *keeper) decrypt(ctx context.Context, dec func(*secrets.Keeper) ([]byte, error)) ([]byte, error) {
+	plaintext, err := dec(k.primary)
+	if err == nil {
+		return plaintext, nil
+	}
+	errs := []error{err}
+	for i, f := range k.fallbacks {
+		plaintext, err := dec(f)
+		if err == nil {
+			if k.opts.OnFallback != nil {
+				k.opts.OnFallback(ctx, i)
+			}
+			return plaintext, nil
+		}
+		errs = append(errs, err)
+	}
+	// A Keeper that failed for a reason other than the ciphertext, for
+	// example because its service is unavailable, might have been able to
+	// decrypt it; report that failure so that the caller can retry.
+	for _, err := range errs {
+		if c := gcerrors.Code(err); c != gcerrors.InvalidArgument && c != gcerrors.Unknown {
+			return nil, err
+		}
+	}
+	return nil, gcerr.Newf(gcerr.InvalidArgument, errs[0], "chain: no Keeper could decrypt the ciphertext")
+}
+
+// GenerateDataKey implements driver.DataKeyGenerator.GenerateDataKey, using
+// the primary Keeper.
+func (k *keeper) GenerateDataKey(ctx context.Context) (plaintext, ciphertext []byte, err error) {
+	return k.primary.GenerateDataKey(ctx)
+}
+
+// Close implements driver.Keeper.Close. It does not close the underlying
+// Keepers.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return false
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
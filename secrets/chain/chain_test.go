@@ -0,0 +1,213 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+func newLocalKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(sk)
+}
+
+type harness struct {
+	t *testing.T
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	k1 := newKeeper(newLocalKeeper(h.t), []*secrets.Keeper{newLocalKeeper(h.t)}, nil)
+	k2 := newKeeper(newLocalKeeper(h.t), []*secrets.Keeper{newLocalKeeper(h.t)}, nil)
+	return k1, k2, nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{t: t}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s string
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs expected to fail")
+	}
+	return nil
+}
+
+func TestMigration(t *testing.T) {
+	ctx := context.Background()
+	oldest, old, current := newLocalKeeper(t), newLocalKeeper(t), newLocalKeeper(t)
+
+	var fallbacks []int
+	k := NewKeeper(current, []*secrets.Keeper{old, oldest}, &Options{
+		OnFallback: func(ctx context.Context, i int) { fallbacks = append(fallbacks, i) },
+	})
+	defer k.Close()
+
+	const plaintext = "hello world"
+	for _, tc := range []struct {
+		name     string
+		encrypt  *secrets.Keeper
+		fallback int // -1 if no fallback is used
+	}{
+		{"current", current, -1},
+		{"old", old, 0},
+		{"oldest", oldest, 1},
+		{"chain", k, -1},
+	} {
+		fallbacks = nil
+		ciphertext, err := tc.encrypt.Encrypt(ctx, []byte(plaintext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := k.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if string(got) != plaintext {
+			t.Errorf("%s: got %q want %q", tc.name, got, plaintext)
+		}
+		if tc.fallback < 0 && len(fallbacks) != 0 {
+			t.Errorf("%s: got fallbacks %v, want none", tc.name, fallbacks)
+		}
+		if tc.fallback >= 0 && (len(fallbacks) != 1 || fallbacks[0] != tc.fallback) {
+			t.Errorf("%s: got fallbacks %v, want [%d]", tc.name, fallbacks, tc.fallback)
+		}
+	}
+
+	// Ciphertexts written by the chain are those of the primary Keeper.
+	ciphertext, err := k.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.Decrypt(ctx, ciphertext); err != nil {
+		t.Errorf("primary Keeper could not decrypt: %v", err)
+	}
+
+	if _, err := k.Decrypt(ctx, []byte("garbage")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v, want InvalidArgument", err)
+	}
+}
+
+func TestMigrationAAD(t *testing.T) {
+	ctx := context.Background()
+	old, current := newLocalKeeper(t), newLocalKeeper(t)
+
+	var fallbacks []int
+	k := NewKeeper(current, []*secrets.Keeper{old}, &Options{
+		OnFallback: func(ctx context.Context, i int) { fallbacks = append(fallbacks, i) },
+	})
+	defer k.Close()
+
+	const plaintext = "hello world"
+	aad := []byte("row 1")
+	ciphertext, err := old.EncryptWithAAD(ctx, []byte(plaintext), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := k.DecryptWithAAD(ctx, ciphertext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != plaintext {
+		t.Errorf("got %q want %q", got, plaintext)
+	}
+	if len(fallbacks) != 1 || fallbacks[0] != 0 {
+		t.Errorf("got fallbacks %v, want [0]", fallbacks)
+	}
+	if _, err := k.DecryptWithAAD(ctx, ciphertext, []byte("row 2")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("with other associated data: got error %v, want InvalidArgument", err)
+	}
+
+	// Ciphertexts written by the chain are those of the primary Keeper.
+	ciphertext, err = k.EncryptWithAAD(ctx, []byte(plaintext), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.DecryptWithAAD(ctx, ciphertext, aad); err != nil {
+		t.Errorf("primary Keeper could not decrypt: %v", err)
+	}
+}
+
+// unavailableKeeper is a driver.Keeper whose service does not respond.
+type unavailableKeeper struct{}
+
+var errUnavailable = gcerr.Newf(gcerr.DeadlineExceeded, nil, "service timed out")
+
+func (unavailableKeeper) Encrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return nil, errUnavailable
+}
+
+func (unavailableKeeper) Decrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return nil, errUnavailable
+}
+
+func (unavailableKeeper) Close() error                           { return nil }
+func (unavailableKeeper) ErrorAs(err error, i interface{}) bool  { return false }
+func (unavailableKeeper) ErrorCode(err error) gcerrors.ErrorCode { return gcerrors.Code(err) }
+
+func TestFallbackUnavailable(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(newLocalKeeper(t), []*secrets.Keeper{secrets.NewKeeper(unavailableKeeper{})}, nil)
+	defer k.Close()
+
+	// The ciphertext might belong to the unavailable Keeper, so its error
+	// is reported rather than InvalidArgument.
+	if _, err := k.Decrypt(ctx, []byte("garbage")); gcerrors.Code(err) != gcerrors.DeadlineExceeded {
+		t.Errorf("got error %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	primary := newLocalKeeper(t)
+	k := NewKeeper(primary, nil, nil)
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The primary Keeper is still open.
+	if _, err := primary.Decrypt(ctx, ciphertext); err != nil {
+		t.Errorf("primary Keeper: %v", err)
+	}
+}
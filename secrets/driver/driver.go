@@ -19,6 +19,7 @@ package driver // import "gocloud.dev/secrets/driver"
 
 import (
 	"context"
+	"crypto"
 
 	"gocloud.dev/gcerrors"
 )
@@ -49,3 +50,67 @@ type Keeper interface {
 	// by one of the other methods in this interface.
 	ErrorCode(error) gcerrors.ErrorCode
 }
+
+// Signer should be implemented by Keepers that hold an asymmetric signing
+// key. If a Keeper does not implement this interface, Keeper.Sign and
+// Keeper.Verify return an error with code Unimplemented.
+type Signer interface {
+	// Sign signs digest and returns the signature or an error.
+	// digest is the hash of the message, computed using the hash function
+	// that matches the key's signing algorithm.
+	Sign(ctx context.Context, digest []byte) ([]byte, error)
+
+	// Verify reports whether signature is a valid signature of digest.
+	// An invalid signature is not an error; Verify should return false and a
+	// nil error in that case.
+	Verify(ctx context.Context, digest, signature []byte) (bool, error)
+}
+
+// MACer should be implemented by Keepers that can compute message
+// authentication codes (for example, HMACs). If a Keeper does not implement
+// this interface, Keeper.MAC and Keeper.VerifyMAC return an error with code
+// Unimplemented.
+type MACer interface {
+	// MAC computes and returns the MAC of data, or an error.
+	MAC(ctx context.Context, data []byte) ([]byte, error)
+
+	// VerifyMAC reports whether mac is a valid MAC of data.
+	// A MAC that does not match is not an error; VerifyMAC should return
+	// false and a nil error in that case.
+	VerifyMAC(ctx context.Context, data, mac []byte) (bool, error)
+}
+
+// AADKeeper should be implemented by Keepers that can bind ciphertexts to
+// associated data. If a Keeper does not implement this interface,
+// Keeper.EncryptWithAAD and Keeper.DecryptWithAAD return an error with code
+// Unimplemented.
+type AADKeeper interface {
+	// EncryptWithAAD encrypts plaintext, authenticating aad along with it,
+	// and returns the ciphertext. aad is not stored in the ciphertext.
+	EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error)
+
+	// DecryptWithAAD decrypts a ciphertext returned by EncryptWithAAD and
+	// returns the plaintext. It must return an error if aad differs from
+	// the one passed to EncryptWithAAD.
+	DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error)
+}
+
+// PublicKeyer should be implemented by Keepers backed by an asymmetric key
+// whose public key can be exported. If a Keeper does not implement this
+// interface, Keeper.PublicKey returns an error with code Unimplemented.
+type PublicKeyer interface {
+	// PublicKey returns the public key of the Keeper's key, for example an
+	// *rsa.PublicKey or an *ecdsa.PublicKey.
+	PublicKey(ctx context.Context) (crypto.PublicKey, error)
+}
+
+// DataKeyGenerator should be implemented by Keepers whose service can
+// generate data keys for envelope encryption. If a Keeper does not implement
+// this interface, Keeper.GenerateDataKey generates the data key locally and
+// encrypts it with Keeper.Encrypt.
+type DataKeyGenerator interface {
+	// GenerateDataKey returns a new random 32-byte data key, both in
+	// plaintext and encrypted with the Keeper's key. The encrypted data key
+	// must be decryptable with Decrypt.
+	GenerateDataKey(ctx context.Context) (plaintext, ciphertext []byte, err error)
+}
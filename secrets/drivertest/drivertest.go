@@ -19,6 +19,7 @@ package drivertest // import "gocloud.dev/secrets/drivertest"
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"testing"
 
@@ -37,6 +38,34 @@ type Harness interface {
 	Close()
 }
 
+// SignerHarness describes the functionality test harnesses must provide to
+// run the Sign and Verify conformance tests.
+type SignerHarness interface {
+	Harness
+
+	// MakeSigningDriver returns a driver.Keeper backed by an asymmetric
+	// signing key. The returned Keeper must implement driver.Signer.
+	MakeSigningDriver(ctx context.Context) (driver.Keeper, error)
+}
+
+// SignerHarnessMaker describes functions that construct a SignerHarness.
+// It is called exactly once per test.
+type SignerHarnessMaker func(ctx context.Context, t *testing.T) (SignerHarness, error)
+
+// MACHarness describes the functionality test harnesses must provide to run
+// the MAC and VerifyMAC conformance tests.
+type MACHarness interface {
+	Harness
+
+	// MakeMACDriver returns a driver.Keeper backed by a MAC key.
+	// The returned Keeper must implement driver.MACer.
+	MakeMACDriver(ctx context.Context) (driver.Keeper, error)
+}
+
+// MACHarnessMaker describes functions that construct a MACHarness.
+// It is called exactly once per test.
+type MACHarnessMaker func(ctx context.Context, t *testing.T) (MACHarness, error)
+
 // HarnessMaker describes functions that construct a harness for running tests.
 // It is called exactly once per test.
 type HarnessMaker func(ctx context.Context, t *testing.T) (Harness, error)
@@ -131,6 +160,167 @@ func testEncryptDecrypt(t *testing.T, newHarness HarnessMaker) {
 	}
 }
 
+// RunSignerConformanceTests runs conformance tests for drivers that implement
+// driver.Signer. It is separate from RunConformanceTests because signing
+// requires an asymmetric key, which the keys used by RunConformanceTests
+// generally are not.
+func RunSignerConformanceTests(t *testing.T, newHarness SignerHarnessMaker) {
+	t.Helper()
+
+	t.Run("TestSignVerify", func(t *testing.T) {
+		testSignVerify(t, newHarness)
+	})
+}
+
+// testSignVerify tests that a signature produced by Sign is accepted by Verify,
+// and that it is rejected for a different digest.
+func testSignVerify(t *testing.T, newHarness SignerHarnessMaker) {
+	t.Helper()
+
+	ctx := context.Background()
+	harness, err := newHarness(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer harness.Close()
+
+	drv, err := harness.MakeSigningDriver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keeper := secrets.NewKeeper(drv)
+	defer keeper.Close()
+
+	digest := sha256.Sum256([]byte("I'm a message to be signed!"))
+	sig, err := keeper.Sign(ctx, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := keeper.Verify(ctx, digest[:], sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("Verify returned false for a signature produced by Sign")
+	}
+	other := sha256.Sum256([]byte("I'm a different message!"))
+	ok, err = keeper.Verify(ctx, other[:], sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("Verify returned true for a different digest")
+	}
+}
+
+// RunMACConformanceTests runs conformance tests for drivers that implement
+// driver.MACer.
+func RunMACConformanceTests(t *testing.T, newHarness MACHarnessMaker) {
+	t.Helper()
+
+	t.Run("TestMACVerifyMAC", func(t *testing.T) {
+		testMACVerifyMAC(t, newHarness)
+	})
+}
+
+// testMACVerifyMAC tests that a MAC produced by MAC is accepted by VerifyMAC,
+// and that it is rejected for different data.
+func testMACVerifyMAC(t *testing.T, newHarness MACHarnessMaker) {
+	t.Helper()
+
+	ctx := context.Background()
+	harness, err := newHarness(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer harness.Close()
+
+	drv, err := harness.MakeMACDriver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keeper := secrets.NewKeeper(drv)
+	defer keeper.Close()
+
+	data := []byte("I'm a message to be authenticated!")
+	mac, err := keeper.MAC(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := keeper.VerifyMAC(ctx, data, mac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("VerifyMAC returned false for a MAC produced by MAC")
+	}
+	ok, err = keeper.VerifyMAC(ctx, []byte("I'm a different message!"), mac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("VerifyMAC returned true for different data")
+	}
+}
+
+// RunAADConformanceTests runs conformance tests for drivers that implement
+// driver.AADKeeper. The Keepers returned by the harness's MakeDriver must
+// implement driver.AADKeeper.
+func RunAADConformanceTests(t *testing.T, newHarness HarnessMaker) {
+	t.Helper()
+
+	t.Run("TestEncryptDecryptWithAAD", func(t *testing.T) {
+		testEncryptDecryptWithAAD(t, newHarness)
+	})
+}
+
+// testEncryptDecryptWithAAD tests that a ciphertext produced by
+// EncryptWithAAD is decrypted by DecryptWithAAD with the same associated
+// data, and that decryption fails with different associated data.
+func testEncryptDecryptWithAAD(t *testing.T, newHarness HarnessMaker) {
+	t.Helper()
+
+	ctx := context.Background()
+	harness, err := newHarness(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer harness.Close()
+
+	drv, _, err := harness.MakeDriver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keeper := secrets.NewKeeper(drv)
+	defer keeper.Close()
+
+	msg := []byte("I'm a secret message!")
+	for _, aad := range [][]byte{[]byte("tenant-1/object-1"), nil} {
+		ciphertext, err := keeper.EncryptWithAAD(ctx, msg, aad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := keeper.DecryptWithAAD(ctx, ciphertext, aad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Errorf("got %q want %q", got, msg)
+		}
+	}
+
+	ciphertext, err := keeper.EncryptWithAAD(ctx, msg, []byte("tenant-1/object-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keeper.DecryptWithAAD(ctx, ciphertext, []byte("tenant-2/object-1")); err == nil {
+		t.Error("DecryptWithAAD succeeded with different associated data")
+	}
+	if _, err := keeper.DecryptWithAAD(ctx, ciphertext, nil); err == nil {
+		t.Error("DecryptWithAAD succeeded without associated data")
+	}
+}
+
 // testMultipleEncryptionsNotEqual tests that encrypting a plaintext multiple
 // times with the same key works, and that the encrypted bytes are different.
 func testMultipleEncryptionsNotEqual(t *testing.T, newHarness HarnessMaker) {
@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envelope provides a *secrets.Keeper that uses envelope encryption.
+// Use NewKeeper to construct one from another *secrets.Keeper.
+//
+// Each call to Encrypt gets a new data key from the underlying Keeper (see
+// secrets.Keeper.GenerateDataKey), encrypts the payload locally with
+// AES-256-GCM, and stores the encrypted data key alongside the ciphertext.
+// Decrypt asks the underlying Keeper to decrypt the data key, then decrypts
+// the payload locally. Only the small data key is sent to the key service,
+// so this is the usual way to encrypt large payloads, or many payloads,
+// with a cloud KMS.
+//
+// # Format
+//
+// Ciphertexts produced by Encrypt have the following layout:
+//   - a version byte, currently 1;
+//   - the length of the encrypted data key, as a uvarint;
+//   - the encrypted data key;
+//   - a 12-byte nonce;
+//   - the AES-256-GCM ciphertext and tag.
+//
+// When the underlying Keeper is a gocloud.dev/secrets/rotation Keeper, the
+// encrypted data key starts with the ID of the key that encrypted it; use
+// KeyID to read it, and Reencrypt to migrate ciphertexts to the primary key.
+//
+// Everything before the nonce is authenticated as additional data, followed
+// by the associated data passed to EncryptWithAAD, if any. Encrypt is
+// EncryptWithAAD with no associated data.
+//
+// # As
+//
+// envelope exposes the types of the underlying Keeper for As.
+package envelope // import "gocloud.dev/secrets/envelope"
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/rotation"
+)
+
+// version is the format version written by Encrypt.
+const version = 1
+
+// NewKeeper returns a *secrets.Keeper that encrypts payloads locally with
+// data keys protected by k. Closing the returned Keeper does not close k.
+func NewKeeper(k *secrets.Keeper) *secrets.Keeper {
+	return secrets.NewKeeper(&keeper{k: k})
+}
+
+// keeper implements driver.Keeper and driver.AADKeeper.
+type keeper struct {
+	k *secrets.Keeper
+}
+
+// Encrypt implements driver.Keeper.Encrypt.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.EncryptWithAAD(ctx, plaintext, nil)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	dataKey, encryptedKey, err := k.k.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, 1+binary.MaxVarintLen64+len(encryptedKey))
+	header = append(header, version)
+	header = binary.AppendUvarint(header, uint64(len(encryptedKey)))
+	header = append(header, encryptedKey...)
+
+	out := make([]byte, len(header)+aead.NonceSize(), len(header)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "envelope: failed to generate nonce")
+	}
+	return aead.Seal(out, nonce, plaintext, additionalData(header, aad)), nil
+}
+
+// Decrypt implements driver.Keeper.Decrypt.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.DecryptWithAAD(ctx, ciphertext, nil)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	header, encryptedKey, rest, err := parse(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := k.k.Decrypt(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, errMalformed
+	}
+	nonce, sealed := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, additionalData(header, aad))
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "envelope: failed to decrypt payload")
+	}
+	return plaintext, nil
+}
+
+// KeyID returns the ID of the key that encrypted the data key of ciphertext,
+// when the underlying Keeper is a gocloud.dev/secrets/rotation Keeper. It
+// returns false if ciphertext is malformed or its data key has no key ID.
+func KeyID(ciphertext []byte) (string, bool) {
+	_, encryptedKey, _, err := parse(ciphertext)
+	if err != nil {
+		return "", false
+	}
+	return rotation.KeyID(encryptedKey)
+}
+
+// Reencrypt returns ciphertext, encrypted by an envelope Keeper with the
+// associated data aad (nil for Encrypt), with its data key encrypted by the
+// rotation key primaryID. k is the envelope Keeper, wrapping a Keeper
+// returned by rotation.NewKeeper with that primary key. If the data key of
+// ciphertext is already encrypted with primaryID, Reencrypt returns
+// ciphertext as is, with false; otherwise it decrypts it with k and encrypts
+// it again with a new data key, returning true.
+func Reencrypt(ctx context.Context, k *secrets.Keeper, primaryID string, ciphertext, aad []byte) ([]byte, bool, error) {
+	if id, ok := KeyID(ciphertext); ok && id == primaryID {
+		return ciphertext, false, nil
+	}
+	plaintext, err := k.DecryptWithAAD(ctx, ciphertext, aad)
+	if err != nil {
+		return nil, false, err
+	}
+	ciphertext, err = k.EncryptWithAAD(ctx, plaintext, aad)
+	if err != nil {
+		return nil, false, err
+	}
+	return ciphertext, true, nil
+}
+
+// additionalData returns the GCM additional data for a ciphertext with
+// header and the caller's associated data aad.
+func additionalData(header, aad []byte) []byte {
+	if len(aad) == 0 {
+		return header
+	}
+	return append(append(make([]byte, 0, len(header)+len(aad)), header...), aad...)
+}
+
+// parse splits an envelope ciphertext into its authenticated header, the
+// encrypted data key within the header, and the nonce and sealed payload
+// that follow it.
+func parse(ciphertext []byte) (header, encryptedKey, rest []byte, err error) {
+	if len(ciphertext) == 0 {
+		return nil, nil, nil, errMalformed
+	}
+	if ciphertext[0] != version {
+		return nil, nil, nil, gcerr.Newf(gcerr.InvalidArgument, nil, "envelope: unsupported ciphertext version %d", ciphertext[0])
+	}
+	n, w := binary.Uvarint(ciphertext[1:])
+	if w <= 0 || n > uint64(len(ciphertext)-1-w) {
+		return nil, nil, nil, errMalformed
+	}
+	end := 1 + w + int(n)
+	return ciphertext[:end], ciphertext[1+w : end], ciphertext[end:], nil
+}
+
+func newAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "envelope: invalid data key")
+	}
+	return cipher.NewGCM(block)
+}
+
+var errMalformed = gcerr.Newf(gcerr.InvalidArgument, nil, "envelope: malformed ciphertext")
+
+// Close implements driver.Keeper.Close. It does not close the underlying Keeper.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return k.k.ErrorAs(err, i)
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
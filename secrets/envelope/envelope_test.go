@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+	"gocloud.dev/secrets/localsecrets"
+	"gocloud.dev/secrets/rotation"
+)
+
+type harness struct{}
+
+func newLocalKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(sk)
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	sk1, err := localsecrets.NewRandomKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	sk2, err := localsecrets.NewRandomKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &keeper{k: localsecrets.NewKeeper(sk1)}, &keeper{k: localsecrets.NewKeeper(sk2)}, nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+	drivertest.RunAADConformanceTests(t, newHarness)
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s string
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs expected to fail")
+	}
+	return nil
+}
+
+func TestLargePayload(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(newLocalKeeper(t))
+	defer k.Close()
+
+	plaintext := bytes.Repeat([]byte("0123456789abcdef"), 1<<16) // 1 MiB
+	ciphertext, err := k.Encrypt(ctx, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := k.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Error("decrypted payload does not match")
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(newLocalKeeper(t))
+	defer k.Close()
+
+	ciphertext, err := k.Encrypt(ctx, []byte("hello world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	badVersion := append([]byte(nil), ciphertext...)
+	badVersion[0] = 99
+
+	for _, tc := range []struct {
+		name       string
+		ciphertext []byte
+	}{
+		{"empty", nil},
+		{"bad version", badVersion},
+		{"truncated header", ciphertext[:2]},
+		{"missing nonce", ciphertext[:len(ciphertext)-12-len("hello world")-16]},
+		{"tampered payload", tampered},
+	} {
+		if _, err := k.Decrypt(ctx, tc.ciphertext); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%s: got error %v, want InvalidArgument", tc.name, err)
+		}
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	ctx := context.Background()
+	v1 := rotation.Key{ID: "v1", Keeper: newLocalKeeper(t)}
+	v2 := rotation.Key{ID: "v2", Keeper: newLocalKeeper(t)}
+	before, err := rotation.NewKeeper(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := rotation.NewKeeper(v2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kBefore, kAfter := NewKeeper(before), NewKeeper(after)
+	defer kBefore.Close()
+	defer kAfter.Close()
+
+	plaintext := []byte("hello world")
+	aad := []byte("record-1")
+	oldCiphertext, err := kBefore.EncryptWithAAD(ctx, plaintext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := KeyID(oldCiphertext); !ok || id != "v1" {
+		t.Errorf("KeyID: got %q, %v want %q, true", id, ok, "v1")
+	}
+
+	newCiphertext, changed, err := Reencrypt(ctx, kAfter, "v2", oldCiphertext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("got changed false for a v1 ciphertext, want true")
+	}
+	if id, ok := KeyID(newCiphertext); !ok || id != "v2" {
+		t.Errorf("KeyID: got %q, %v want %q, true", id, ok, "v2")
+	}
+	got, err := kAfter.DecryptWithAAD(ctx, newCiphertext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("got %q want %q", got, plaintext)
+	}
+	// The Keeper without v2 can no longer decrypt it.
+	if _, err := kBefore.DecryptWithAAD(ctx, newCiphertext, aad); err == nil {
+		t.Error("got nil error decrypting a v2 ciphertext without v2, want error")
+	}
+
+	// Ciphertexts with data keys encrypted by the primary key are returned
+	// as is.
+	same, changed, err := Reencrypt(ctx, kAfter, "v2", newCiphertext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || !bytes.Equal(same, newCiphertext) {
+		t.Errorf("got changed %v for a v2 ciphertext, want false and the same ciphertext", changed)
+	}
+
+	// Without a rotation Keeper, the data key has no key ID.
+	k := NewKeeper(newLocalKeeper(t))
+	defer k.Close()
+	ciphertext, err := k.Encrypt(ctx, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := KeyID(ciphertext); ok {
+		t.Errorf("KeyID: got %q, true want false", id)
+	}
+}
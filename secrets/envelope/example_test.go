@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/envelope"
+	_ "gocloud.dev/secrets/localsecrets"
+	"gocloud.dev/secrets/rotation"
+)
+
+func ExampleNewKeeper() {
+	ctx := context.Background()
+
+	// Open the Keeper that protects the data keys; typically a cloud KMS.
+	kms, err := secrets.OpenKeeper(ctx, "base64key://")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer kms.Close()
+
+	keeper := envelope.NewKeeper(kms)
+	defer keeper.Close()
+
+	// Large payloads are encrypted locally; only the data key goes to kms.
+	ciphertext, err := keeper.Encrypt(ctx, make([]byte, 1<<20))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := keeper.Decrypt(ctx, ciphertext); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleReencrypt() {
+	ctx := context.Background()
+
+	// Open the current and the previous key; typically two versions of a
+	// cloud KMS key.
+	current, err := secrets.OpenKeeper(ctx, "base64key://")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer current.Close()
+	previous, err := secrets.OpenKeeper(ctx, "base64key://")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer previous.Close()
+
+	// A ciphertext stored while "v1" was the primary key.
+	old, err := rotation.NewKeeper(rotation.Key{ID: "v1", Keeper: previous})
+	if err != nil {
+		log.Fatal(err)
+	}
+	oldKeeper := envelope.NewKeeper(old)
+	defer oldKeeper.Close()
+	stored, err := oldKeeper.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Data keys are now encrypted with the key "v2", and can be decrypted
+	// with "v1" or "v2".
+	rotating, err := rotation.NewKeeper(rotation.Key{ID: "v2", Keeper: current}, rotation.Key{ID: "v1", Keeper: previous})
+	if err != nil {
+		log.Fatal(err)
+	}
+	keeper := envelope.NewKeeper(rotating)
+	defer keeper.Close()
+
+	// Re-encrypt the stored ciphertext so that "v1" can be retired.
+	ciphertext, changed, err := envelope.Reencrypt(ctx, keeper, "v2", stored, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if changed {
+		// Store ciphertext in place of stored.
+		stored = ciphertext
+	}
+	id, _ := envelope.KeyID(stored)
+	fmt.Println(changed, id)
+
+	// Output:
+	// true v2
+}
@@ -29,13 +29,64 @@
 //
 // gcpkms exposes the following type for As:
 //   - Error: *google.golang.org/grpc/status.Status
+//
+// # Signing
+//
+// Keepers opened with the resource ID of an asymmetric signing key version
+// (see KeyVersionResourceID) support Sign and Verify. Sign uses Cloud KMS
+// AsymmetricSign; Verify fetches the public key once and checks signatures
+// locally, since Cloud KMS does not provide a verification API for
+// asymmetric keys.
+//
+// Verify supports the RSA_SIGN_PSS_*, RSA_SIGN_PKCS1_*, EC_SIGN_P256_SHA256
+// and EC_SIGN_P384_SHA384 algorithms. Keys using RSA_SIGN_RAW_PKCS1_*,
+// EC_SIGN_ED25519 or EC_SIGN_SECP256K1_SHA256 are not supported.
+//
+// # Asymmetric encryption
+//
+// Keepers opened with the resource ID of an asymmetric decryption key version
+// and KeeperOptions.Asymmetric set (or the "asymmetric=true" URL parameter)
+// encrypt locally with the key's public key using RSA-OAEP, since Cloud KMS
+// does not provide an encryption API for asymmetric keys, and decrypt with
+// AsymmetricDecrypt. Producers that only need to encrypt can instead use
+// localsecrets.NewPublicKeyKeeper with the key returned by
+// secrets.Keeper.PublicKey, and need no Cloud KMS credentials.
+//
+// # Raw encryption
+//
+// Keepers opened with the resource ID of a raw encryption key version (a key
+// with purpose RAW_ENCRYPT_DECRYPT, such as an AES_256_GCM key) and
+// KeeperOptions.Raw set (or the "raw=true" URL parameter) use Cloud KMS
+// RawEncrypt and RawDecrypt. Ciphertexts produced by Encrypt hold the
+// initialization vector and tag length in a short header, so that Decrypt
+// can pass them back to Cloud KMS.
+//
+// # Associated data
+//
+// EncryptWithAAD and DecryptWithAAD pass the associated data to Cloud KMS as
+// additional authenticated data. They are supported for symmetric and raw
+// encryption keys, but not for asymmetric ones.
+//
+// # MACs
+//
+// Keepers opened with the resource ID of a MAC signing key version (a key
+// with purpose MAC) support MAC and VerifyMAC, using Cloud KMS MacSign and
+// MacVerify.
 package gcpkms // import "gocloud.dev/secrets/gcpkms"
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"math"
 	"net/url"
 	"path"
+	"strconv"
 	"sync"
 
 	cloudkms "cloud.google.com/go/kms/apiv1"
@@ -108,7 +159,9 @@ const Scheme = "gcpkms"
 // The URL host+path are used as the key resource ID; see
 // https://cloud.google.com/kms/docs/object-hierarchy#key for more details.
 //
-// No query parameters are supported.
+// The following query parameters are supported:
+//   - asymmetric: Sets KeeperOptions.Asymmetric; e.g., "asymmetric=true".
+//   - raw: Sets KeeperOptions.Raw; e.g., "raw=true".
 type URLOpener struct {
 	// Client must be non-nil and be authenticated with "cloudkms" scope or equivalent.
 	Client *cloudkms.KeyManagementClient
@@ -119,10 +172,29 @@ type URLOpener struct {
 
 // OpenKeeperURL opens the GCP KMS URLs.
 func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
-	for param := range u.Query() {
-		return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
+	opts := o.Options
+	for param, vals := range u.Query() {
+		switch param {
+		case "asymmetric":
+			b, err := strconv.ParseBool(vals[0])
+			if err != nil {
+				return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q: %v", u, vals[0], param, err)
+			}
+			opts.Asymmetric = b
+		case "raw":
+			b, err := strconv.ParseBool(vals[0])
+			if err != nil {
+				return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q: %v", u, vals[0], param, err)
+			}
+			opts.Raw = b
+		default:
+			return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
+		}
 	}
-	return OpenKeeper(o.Client, path.Join(u.Host, u.Path), &o.Options), nil
+	if opts.Asymmetric && opts.Raw {
+		return nil, fmt.Errorf("open keeper %v: asymmetric and raw are mutually exclusive", u)
+	}
+	return OpenKeeper(o.Client, path.Join(u.Host, u.Path), &opts), nil
 }
 
 // OpenKeeper returns a *secrets.Keeper that uses Google Cloud KMS.
@@ -131,9 +203,13 @@ func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Kee
 // See https://cloud.google.com/kms/docs/object-hierarchy#key for more details.
 // See the package documentation for an example.
 func OpenKeeper(client *cloudkms.KeyManagementClient, keyResourceID string, opts *KeeperOptions) *secrets.Keeper {
+	if opts == nil {
+		opts = &KeeperOptions{}
+	}
 	return secrets.NewKeeper(&keeper{
 		keyResourceID: keyResourceID,
 		client:        client,
+		opts:          *opts,
 	})
 }
 
@@ -144,17 +220,63 @@ func KeyResourceID(projectID, location, keyRing, key string) string {
 		projectID, location, keyRing, key)
 }
 
+// KeyVersionResourceID constructs a key version resourceID for GCP KMS.
+// Asymmetric signing, asymmetric encryption, raw encryption and MACs require
+// a key version rather than a key.
+// See https://cloud.google.com/kms/docs/object-hierarchy#key_version for more details.
+func KeyVersionResourceID(projectID, location, keyRing, key, version string) string {
+	return fmt.Sprintf("%s/cryptoKeyVersions/%s", KeyResourceID(projectID, location, keyRing, key), version)
+}
+
 // keeper implements driver.Keeper.
 type keeper struct {
 	keyResourceID string
 	client        *cloudkms.KeyManagementClient
+	opts          KeeperOptions
+
+	// mu protects publicKey, which is loaded on first use.
+	// The public key of a key version never changes, so it is safe to cache.
+	mu        sync.Mutex
+	publicKey *publicKey
+}
+
+// publicKey is the public key of an asymmetric key version.
+type publicKey struct {
+	pub crypto.PublicKey
+	alg kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
 }
 
 // Decrypt decrypts the ciphertext using the key constructed from ki.
 func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ctx, ciphertext, nil)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if k.opts.Asymmetric {
+		return nil, errAsymmetricAAD
+	}
+	return k.decrypt(ctx, ciphertext, aad)
+}
+
+func (k *keeper) decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if k.opts.Asymmetric {
+		resp, err := k.client.AsymmetricDecrypt(ctx, &kmspb.AsymmetricDecryptRequest{
+			Name:       k.keyResourceID,
+			Ciphertext: ciphertext,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetPlaintext(), nil
+	}
+	if k.opts.Raw {
+		return k.rawDecrypt(ctx, ciphertext, aad)
+	}
 	req := &kmspb.DecryptRequest{
-		Name:       k.keyResourceID,
-		Ciphertext: ciphertext,
+		Name:                        k.keyResourceID,
+		Ciphertext:                  ciphertext,
+		AdditionalAuthenticatedData: aad,
 	}
 	resp, err := k.client.Decrypt(ctx, req)
 	if err != nil {
@@ -165,9 +287,32 @@ func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 
 // Encrypt encrypts the plaintext into a ciphertext.
 func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if k.opts.Asymmetric {
+		return k.encryptLocally(ctx, plaintext)
+	}
+	return k.encrypt(ctx, plaintext, nil)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD. Cloud KMS
+// supports associated data for symmetric and raw symmetric keys, but not
+// for asymmetric ones.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if k.opts.Asymmetric {
+		return nil, errAsymmetricAAD
+	}
+	return k.encrypt(ctx, plaintext, aad)
+}
+
+var errAsymmetricAAD = gcerr.Newf(gcerr.FailedPrecondition, nil, "gcpkms: associated data is not supported for asymmetric keys")
+
+func (k *keeper) encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if k.opts.Raw {
+		return k.rawEncrypt(ctx, plaintext, aad)
+	}
 	req := &kmspb.EncryptRequest{
-		Name:      k.keyResourceID,
-		Plaintext: plaintext,
+		Name:                        k.keyResourceID,
+		Plaintext:                   plaintext,
+		AdditionalAuthenticatedData: aad,
 	}
 	resp, err := k.client.Encrypt(ctx, req)
 	if err != nil {
@@ -176,6 +321,235 @@ func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
 	return resp.GetCiphertext(), nil
 }
 
+// Sign implements driver.Signer.Sign.
+func (k *keeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	d, err := toDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name:   k.keyResourceID,
+		Digest: d,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetSignature(), nil
+}
+
+// Verify implements driver.Signer.Verify.
+func (k *keeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	pk, err := k.loadPublicKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	hash, pss, err := verifyParams(pk.alg)
+	if err != nil {
+		return false, err
+	}
+	if len(digest) != hash.Size() {
+		return false, gcerr.Newf(gcerr.InvalidArgument, nil, "gcpkms: digest is %d bytes; key %q requires a %v digest", len(digest), k.keyResourceID, hash)
+	}
+	switch pub := pk.pub.(type) {
+	case *rsa.PublicKey:
+		if pss {
+			return rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil, nil
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, signature) == nil, nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(pub, digest, signature), nil
+	default:
+		return false, gcerr.Newf(gcerr.FailedPrecondition, nil, "gcpkms: unsupported public key type %T for %q", pub, k.keyResourceID)
+	}
+}
+
+// MAC implements driver.MACer.MAC.
+func (k *keeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	resp, err := k.client.MacSign(ctx, &kmspb.MacSignRequest{
+		Name: k.keyResourceID,
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetMac(), nil
+}
+
+// VerifyMAC implements driver.MACer.VerifyMAC.
+func (k *keeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	resp, err := k.client.MacVerify(ctx, &kmspb.MacVerifyRequest{
+		Name: k.keyResourceID,
+		Data: data,
+		Mac:  mac,
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetSuccess(), nil
+}
+
+// PublicKey implements driver.PublicKeyer.PublicKey.
+func (k *keeper) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	pk, err := k.loadPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pk.pub, nil
+}
+
+// encryptLocally encrypts plaintext with the public key of an asymmetric
+// decryption key version.
+func (k *keeper) encryptLocally(ctx context.Context, plaintext []byte) ([]byte, error) {
+	pk, err := k.loadPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := oaepHash(pk.alg)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := pk.pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "gcpkms: unsupported public key type %T for %q", pk.pub, k.keyResourceID)
+	}
+	return rsa.EncryptOAEP(hash.New(), rand.Reader, pub, plaintext, nil)
+}
+
+// rawEncrypt encrypts plaintext with a raw encryption key version. The
+// result is the length of the initialization vector as one byte, the
+// initialization vector, the tag length as one byte, and the ciphertext
+// returned by Cloud KMS.
+func (k *keeper) rawEncrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	resp, err := k.client.RawEncrypt(ctx, &kmspb.RawEncryptRequest{
+		Name:                        k.keyResourceID,
+		Plaintext:                   plaintext,
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, err
+	}
+	iv, tagLen := resp.GetInitializationVector(), resp.GetTagLength()
+	if len(iv) > math.MaxUint8 || tagLen < 0 || tagLen > math.MaxUint8 {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "gcpkms: unexpected initialization vector or tag length from RawEncrypt for %q", k.keyResourceID)
+	}
+	out := make([]byte, 0, 2+len(iv)+len(resp.GetCiphertext()))
+	out = append(out, byte(len(iv)))
+	out = append(out, iv...)
+	out = append(out, byte(tagLen))
+	return append(out, resp.GetCiphertext()...), nil
+}
+
+// rawDecrypt decrypts a ciphertext produced by rawEncrypt.
+func (k *keeper) rawDecrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || len(ciphertext) < 2+int(ciphertext[0]) {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "gcpkms: malformed raw ciphertext")
+	}
+	ivLen := int(ciphertext[0])
+	resp, err := k.client.RawDecrypt(ctx, &kmspb.RawDecryptRequest{
+		Name:                        k.keyResourceID,
+		InitializationVector:        ciphertext[1 : 1+ivLen],
+		TagLength:                   int32(ciphertext[1+ivLen]),
+		Ciphertext:                  ciphertext[2+ivLen:],
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPlaintext(), nil
+}
+
+// loadPublicKey returns the cached public key, fetching it if needed.
+func (k *keeper) loadPublicKey(ctx context.Context) (*publicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.publicKey != nil {
+		return k.publicKey, nil
+	}
+	resp, err := k.client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: k.keyResourceID})
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(resp.GetPem()))
+	if block == nil {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "gcpkms: failed to decode public key for %q", k.keyResourceID)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "gcpkms: failed to parse public key for %q", k.keyResourceID)
+	}
+	k.publicKey = &publicKey{pub: pub, alg: resp.GetAlgorithm()}
+	return k.publicKey, nil
+}
+
+// oaepHash returns the hash function used by the RSA-OAEP algorithm alg.
+func oaepHash(alg kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) (crypto.Hash, error) {
+	switch alg {
+	case kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA256,
+		kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA256,
+		kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA256:
+		return crypto.SHA256, nil
+	case kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA512:
+		return crypto.SHA512, nil
+	case kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA1,
+		kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA1,
+		kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA1:
+		return crypto.SHA1, nil
+	}
+	return 0, gcerr.Newf(gcerr.FailedPrecondition, nil, "gcpkms: Encrypt does not support keys with algorithm %v", alg)
+}
+
+// verifyParams returns the hash function and, for RSA keys, the padding
+// scheme used by alg.
+func verifyParams(alg kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) (hash crypto.Hash, pss bool, err error) {
+	switch alg {
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PSS_3072_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA256:
+		return crypto.SHA256, true, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA512:
+		return crypto.SHA512, true, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_3072_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA256,
+		kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256:
+		return crypto.SHA256, false, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA512:
+		return crypto.SHA512, false, nil
+	case kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384:
+		return crypto.SHA384, false, nil
+	}
+	return 0, false, gcerr.Newf(gcerr.FailedPrecondition, nil, "gcpkms: Verify does not support keys with algorithm %v", alg)
+}
+
+// digestHash returns the hash function that produced digest, based on its length.
+func digestHash(digest []byte) (crypto.Hash, error) {
+	switch len(digest) {
+	case crypto.SHA256.Size():
+		return crypto.SHA256, nil
+	case crypto.SHA384.Size():
+		return crypto.SHA384, nil
+	case crypto.SHA512.Size():
+		return crypto.SHA512, nil
+	}
+	return 0, gcerr.Newf(gcerr.InvalidArgument, nil, "gcpkms: digest is %d bytes; want a SHA-256, SHA-384 or SHA-512 digest", len(digest))
+}
+
+// toDigest wraps digest in the kmspb.Digest variant matching its hash function.
+func toDigest(digest []byte) (*kmspb.Digest, error) {
+	hash, err := digestHash(digest)
+	if err != nil {
+		return nil, err
+	}
+	switch hash {
+	case crypto.SHA256:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}}, nil
+	case crypto.SHA384:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha384{Sha384: digest}}, nil
+	default:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha512{Sha512: digest}}, nil
+	}
+}
+
 // Close implements driver.Keeper.Close.
 func (k *keeper) Close() error { return nil }
 
@@ -199,5 +573,15 @@ func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
 }
 
 // KeeperOptions controls Keeper behaviors.
-// It is provided for future extensibility.
-type KeeperOptions struct{}
+type KeeperOptions struct {
+	// Asymmetric indicates that the key is an asymmetric decryption key
+	// (purpose ASYMMETRIC_DECRYPT). The Keeper must then be opened with a key
+	// version resource ID; see KeyVersionResourceID.
+	Asymmetric bool
+
+	// Raw indicates that the key is a raw encryption key (purpose
+	// RAW_ENCRYPT_DECRYPT). The Keeper must then be opened with a key
+	// version resource ID; see KeyVersionResourceID. Raw and Asymmetric
+	// must not both be set.
+	Raw bool
+}
@@ -16,16 +16,27 @@ package gcpkms
 
 import (
 	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"net"
 	"testing"
 
 	cloudkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"gocloud.dev/secrets"
 	"gocloud.dev/secrets/driver"
 	"gocloud.dev/secrets/drivertest"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
@@ -48,8 +59,8 @@ type harness struct {
 }
 
 func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
-	return &keeper{KeyResourceID(project, location, keyRing, keyID1), h.client},
-		&keeper{KeyResourceID(project, location, keyRing, keyID2), h.client}, nil
+	return &keeper{keyResourceID: KeyResourceID(project, location, keyRing, keyID1), client: h.client},
+		&keeper{keyResourceID: KeyResourceID(project, location, keyRing, keyID2), client: h.client}, nil
 }
 
 func (h *harness) Close() {
@@ -121,6 +132,16 @@ func TestOpenKeeper(t *testing.T) {
 	}{
 		// OK.
 		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY", false},
+		// OK, setting asymmetric.
+		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY/cryptoKeyVersions/1?asymmetric=true", false},
+		// Invalid asymmetric.
+		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY/cryptoKeyVersions/1?asymmetric=maybe", true},
+		// OK, setting raw.
+		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY/cryptoKeyVersions/1?raw=true", false},
+		// Invalid raw.
+		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY/cryptoKeyVersions/1?raw=maybe", true},
+		// Both asymmetric and raw.
+		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY/cryptoKeyVersions/1?asymmetric=true&raw=true", true},
 		// Invalid query parameter.
 		{"gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY?param=val", true},
 	}
@@ -138,3 +159,199 @@ func TestOpenKeeper(t *testing.T) {
 		}
 	}
 }
+
+func TestDigestHash(t *testing.T) {
+	for _, tc := range []struct {
+		size     int
+		want     crypto.Hash
+		wantType interface{}
+	}{
+		{size: 32, want: crypto.SHA256, wantType: &kmspb.Digest_Sha256{}},
+		{size: 48, want: crypto.SHA384, wantType: &kmspb.Digest_Sha384{}},
+		{size: 64, want: crypto.SHA512, wantType: &kmspb.Digest_Sha512{}},
+		{size: 0},
+		{size: 20},
+	} {
+		digest := make([]byte, tc.size)
+		got, err := digestHash(digest)
+		if tc.want == 0 {
+			if gcerrors.Code(err) != gcerrors.InvalidArgument {
+				t.Errorf("digestHash(%d bytes): got error %v, want InvalidArgument", tc.size, err)
+			}
+			if _, err := toDigest(digest); gcerrors.Code(err) != gcerrors.InvalidArgument {
+				t.Errorf("toDigest(%d bytes): got error %v, want InvalidArgument", tc.size, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("digestHash(%d bytes): got %v, %v want %v", tc.size, got, err, tc.want)
+		}
+		d, err := toDigest(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotType, wantType := fmt.Sprintf("%T", d.GetDigest()), fmt.Sprintf("%T", tc.wantType); gotType != wantType {
+			t.Errorf("toDigest(%d bytes): got %s want %s", tc.size, gotType, wantType)
+		}
+	}
+}
+
+func TestVerifyParams(t *testing.T) {
+	for _, tc := range []struct {
+		alg     kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+		hash    crypto.Hash
+		pss     bool
+		wantErr bool
+	}{
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256, hash: crypto.SHA256, pss: true},
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA512, hash: crypto.SHA512, pss: true},
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_3072_SHA256, hash: crypto.SHA256},
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA512, hash: crypto.SHA512},
+		{alg: kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256, hash: crypto.SHA256},
+		{alg: kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384, hash: crypto.SHA384},
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_RAW_PKCS1_2048, wantErr: true},
+		{alg: kmspb.CryptoKeyVersion_EC_SIGN_ED25519, wantErr: true},
+		{alg: kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256, wantErr: true},
+		{alg: kmspb.CryptoKeyVersion_GOOGLE_SYMMETRIC_ENCRYPTION, wantErr: true},
+	} {
+		hash, pss, err := verifyParams(tc.alg)
+		if tc.wantErr {
+			if gcerrors.Code(err) != gcerrors.FailedPrecondition {
+				t.Errorf("%v: got error %v, want FailedPrecondition", tc.alg, err)
+			}
+			continue
+		}
+		if err != nil || hash != tc.hash || pss != tc.pss {
+			t.Errorf("%v: got %v, %v, %v want %v, %v", tc.alg, hash, pss, err, tc.hash, tc.pss)
+		}
+	}
+}
+
+func TestOAEPHash(t *testing.T) {
+	for _, tc := range []struct {
+		alg     kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+		want    crypto.Hash
+		wantErr bool
+	}{
+		{alg: kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA256, want: crypto.SHA256},
+		{alg: kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA256, want: crypto.SHA256},
+		{alg: kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA512, want: crypto.SHA512},
+		{alg: kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA1, want: crypto.SHA1},
+		{alg: kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256, wantErr: true},
+		{alg: kmspb.CryptoKeyVersion_GOOGLE_SYMMETRIC_ENCRYPTION, wantErr: true},
+	} {
+		got, err := oaepHash(tc.alg)
+		if tc.wantErr {
+			if gcerrors.Code(err) != gcerrors.FailedPrecondition {
+				t.Errorf("%v: got error %v, want FailedPrecondition", tc.alg, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%v: got %v, %v want %v", tc.alg, got, err, tc.want)
+		}
+	}
+}
+
+// rawKMSServer implements RawEncrypt and RawDecrypt for a single AES-256-GCM
+// key, as Cloud KMS does for keys with purpose RAW_ENCRYPT_DECRYPT.
+type rawKMSServer struct {
+	kmspb.UnimplementedKeyManagementServiceServer
+	aead cipher.AEAD
+}
+
+func (s *rawKMSServer) RawEncrypt(_ context.Context, req *kmspb.RawEncryptRequest) (*kmspb.RawEncryptResponse, error) {
+	iv := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	return &kmspb.RawEncryptResponse{
+		Ciphertext:           s.aead.Seal(nil, iv, req.GetPlaintext(), req.GetAdditionalAuthenticatedData()),
+		InitializationVector: iv,
+		TagLength:            int32(s.aead.Overhead()),
+	}, nil
+}
+
+func (s *rawKMSServer) RawDecrypt(_ context.Context, req *kmspb.RawDecryptRequest) (*kmspb.RawDecryptResponse, error) {
+	if int(req.GetTagLength()) != s.aead.Overhead() {
+		return nil, status.Error(codes.InvalidArgument, "bad tag length")
+	}
+	plaintext, err := s.aead.Open(nil, req.GetInitializationVector(), req.GetCiphertext(), req.GetAdditionalAuthenticatedData())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "decryption failed")
+	}
+	return &kmspb.RawDecryptResponse{Plaintext: plaintext}, nil
+}
+
+func TestRawEncryption(t *testing.T) {
+	ctx := context.Background()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	kmspb.RegisterKeyManagementServiceServer(srv, &rawKMSServer{aead: aead})
+	go srv.Serve(l)
+	defer srv.Stop()
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := cloudkms.NewKeyManagementClient(ctx, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	k := OpenKeeper(client, KeyVersionResourceID("p", "l", "r", "k", "1"), &KeeperOptions{Raw: true})
+	defer k.Close()
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := k.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q want %q", got, "hello")
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := k.Decrypt(ctx, ciphertext); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("tampered ciphertext: got error %v, want InvalidArgument", err)
+	}
+	if _, err := k.Decrypt(ctx, []byte{12}); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("truncated ciphertext: got error %v, want InvalidArgument", err)
+	}
+
+	ciphertext, err = k.EncryptWithAAD(ctx, []byte("hello"), []byte("tenant-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := k.DecryptWithAAD(ctx, ciphertext, []byte("tenant-1")); err != nil || string(got) != "hello" {
+		t.Errorf("DecryptWithAAD: got %q, %v want %q, nil", got, err, "hello")
+	}
+	if _, err := k.DecryptWithAAD(ctx, ciphertext, []byte("tenant-2")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("different associated data: got error %v, want InvalidArgument", err)
+	}
+
+	asym := OpenKeeper(client, KeyVersionResourceID("p", "l", "r", "k", "1"), &KeeperOptions{Asymmetric: true})
+	defer asym.Close()
+	if _, err := asym.EncryptWithAAD(ctx, []byte("hello"), []byte("tenant-1")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("asymmetric key: got error %v, want FailedPrecondition", err)
+	}
+}
@@ -16,6 +16,21 @@
 // Secrets Engine of Vault by Hashicorp.
 // Use OpenKeeper to construct a *secrets.Keeper.
 //
+// # Convergent encryption and key derivation
+//
+// For keys created with key derivation enabled, set KeeperOptions.Context;
+// it is sent with every request, and a different context selects a
+// different derived key. Keys that also have convergent encryption enabled
+// produce the same ciphertext for the same plaintext and context, which
+// allows ciphertexts to be compared or deduplicated but reveals when two
+// plaintexts are equal. Only use it where that is acceptable.
+//
+// # Associated data
+//
+// EncryptWithAAD and DecryptWithAAD send the associated data to Vault as
+// "associated_data". Vault only supports it for AEAD key types, such as
+// aes256-gcm96 and chacha20-poly1305.
+//
 // # URLs
 //
 // For secrets.OpenKeeper, hashivault registers for the scheme "hashivault".
@@ -38,10 +53,12 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"sync"
 
 	"github.com/hashicorp/vault/api"
 	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
 	"gocloud.dev/secrets"
 )
 
@@ -145,7 +162,14 @@ const Scheme = "hashivault"
 // The URL Host + Path are used as the keyID.
 //
 // The following query parameters are supported:
-// - engine: The secrets engine to use; defaults to "transit".
+//   - engine: The secrets engine to use; defaults to "transit".
+//   - signing_algorithm: Sets KeeperOptions.SigningAlgorithm.
+//   - mac_algorithm: Sets KeeperOptions.MACAlgorithm.
+//   - context: Sets KeeperOptions.Context to the bytes of the value.
+//   - convergent: Sets KeeperOptions.ConvergentEncryption; must be a
+//     boolean value.
+//   - key_version: Sets KeeperOptions.KeyVersion; must be a positive
+//     integer.
 type URLOpener struct {
 	// Client must be non-nil.
 	Client *api.Client
@@ -156,15 +180,37 @@ type URLOpener struct {
 
 // OpenKeeperURL opens the Keeper URL.
 func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
+	opts := o.Options
 	for param, vals := range u.Query() {
 		switch param {
 		case "engine":
-			o.Options.Engine = vals[0]
+			opts.Engine = vals[0]
+		case "signing_algorithm":
+			opts.SigningAlgorithm = vals[0]
+		case "mac_algorithm":
+			opts.MACAlgorithm = vals[0]
+		case "context":
+			opts.Context = []byte(vals[0])
+		case "convergent":
+			b, err := strconv.ParseBool(vals[0])
+			if err != nil {
+				return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q: %v", u, vals[0], param, err)
+			}
+			opts.ConvergentEncryption = b
+		case "key_version":
+			v, err := strconv.Atoi(vals[0])
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("open keeper %v: invalid value %q for query parameter %q; must be a positive integer", u, vals[0], param)
+			}
+			opts.KeyVersion = v
 		default:
 			return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
 		}
 	}
-	return OpenKeeper(o.Client, path.Join(u.Host, u.Path), &o.Options), nil
+	if opts.ConvergentEncryption && len(opts.Context) == 0 {
+		return nil, fmt.Errorf("open keeper %v: convergent encryption requires a context", u)
+	}
+	return OpenKeeper(o.Client, path.Join(u.Host, u.Path), &opts), nil
 }
 
 func newKeeper(client *api.Client, keyID string, opts *KeeperOptions) *keeper {
@@ -174,6 +220,9 @@ func newKeeper(client *api.Client, keyID string, opts *KeeperOptions) *keeper {
 	if opts.Engine == "" {
 		opts.Engine = "transit"
 	}
+	if opts.MACAlgorithm == "" {
+		opts.MACAlgorithm = "sha2-256"
+	}
 	return &keeper{
 		keyID:  keyID,
 		client: client,
@@ -197,11 +246,20 @@ type keeper struct {
 
 // Decrypt decrypts the ciphertext into a plaintext.
 func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ctx, ciphertext, nil)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	return k.decrypt(ctx, ciphertext, aad)
+}
+
+func (k *keeper) decrypt(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
 	out, err := k.client.Logical().Write(
 		path.Join(k.opts.Engine+"/decrypt", k.keyID),
-		map[string]interface{}{
+		withAAD(k.withContext(map[string]interface{}{
 			"ciphertext": string(ciphertext),
-		},
+		}), aad),
 	)
 	if err != nil {
 		return nil, err
@@ -211,11 +269,31 @@ func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 
 // Encrypt encrypts a plaintext into a ciphertext.
 func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.encrypt(ctx, plaintext, nil)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD. Vault only
+// supports associated data for AEAD key types, such as aes256-gcm96 and
+// chacha20-poly1305.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return k.encrypt(ctx, plaintext, aad)
+}
+
+func (k *keeper) encrypt(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if k.opts.ConvergentEncryption && len(k.opts.Context) == 0 {
+		return nil, errNoContext
+	}
+	params := withAAD(k.withKeyVersion(k.withContext(map[string]interface{}{
+		"plaintext": plaintext,
+	})), aad)
+	if k.opts.ConvergentEncryption {
+		// Only used if the key does not exist yet and is created by this
+		// request; existing keys keep the settings they were created with.
+		params["convergent_encryption"] = true
+	}
 	secret, err := k.client.Logical().Write(
 		path.Join(k.opts.Engine+"/encrypt", k.keyID),
-		map[string]interface{}{
-			"plaintext": plaintext,
-		},
+		params,
 	)
 	if err != nil {
 		return nil, err
@@ -223,6 +301,183 @@ func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
 	return []byte(secret.Data["ciphertext"].(string)), nil
 }
 
+// GenerateDataKey implements driver.DataKeyGenerator.GenerateDataKey using
+// the transit datakey endpoint.
+func (k *keeper) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	secret, err := k.client.Logical().Write(
+		path.Join(k.opts.Engine+"/datakey/plaintext", k.keyID),
+		k.withContext(map[string]interface{}{
+			"bits": 256,
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if secret == nil {
+		return nil, nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: empty response from datakey")
+	}
+	plaintext, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: datakey response has no plaintext")
+	}
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok {
+		return nil, nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: datakey response has no ciphertext")
+	}
+	key, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, []byte(ciphertext), nil
+}
+
+// Sign implements driver.Signer.Sign using the transit sign endpoint.
+// The digest is sent with "prehashed" set, so the key must be of a type
+// that supports signing prehashed input (e.g., ecdsa-p256 or rsa-2048).
+func (k *keeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	hash, err := hashAlgorithm(digest)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := k.client.Logical().Write(
+		path.Join(k.opts.Engine+"/sign", k.keyID, hash),
+		k.withKeyVersion(k.signParams(digest)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: empty response from sign")
+	}
+	signature, ok := secret.Data["signature"].(string)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: sign response has no signature")
+	}
+	return []byte(signature), nil
+}
+
+// Verify implements driver.Signer.Verify using the transit verify endpoint.
+func (k *keeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	hash, err := hashAlgorithm(digest)
+	if err != nil {
+		return false, err
+	}
+	params := k.signParams(digest)
+	params["signature"] = string(signature)
+	secret, err := k.client.Logical().Write(
+		path.Join(k.opts.Engine+"/verify", k.keyID, hash),
+		params,
+	)
+	if err != nil {
+		return false, err
+	}
+	if secret == nil {
+		return false, gcerr.Newf(gcerr.Internal, nil, "hashivault: empty response from verify")
+	}
+	valid, ok := secret.Data["valid"].(bool)
+	if !ok {
+		return false, gcerr.Newf(gcerr.Internal, nil, "hashivault: verify response has no validity result")
+	}
+	return valid, nil
+}
+
+func (k *keeper) signParams(digest []byte) map[string]interface{} {
+	params := map[string]interface{}{
+		"input":     digest,
+		"prehashed": true,
+	}
+	if k.opts.SigningAlgorithm != "" {
+		params["signature_algorithm"] = k.opts.SigningAlgorithm
+	}
+	return k.withContext(params)
+}
+
+// withContext adds the key derivation context, if any, to params.
+func (k *keeper) withContext(params map[string]interface{}) map[string]interface{} {
+	if len(k.opts.Context) > 0 {
+		params["context"] = k.opts.Context
+	}
+	return params
+}
+
+// withAAD adds the associated data, if any, to params. The Vault client
+// sends []byte values base64-encoded, as the transit API expects.
+func withAAD(params map[string]interface{}, aad []byte) map[string]interface{} {
+	if len(aad) > 0 {
+		params["associated_data"] = aad
+	}
+	return params
+}
+
+// withKeyVersion adds the key version, if any, to params. Only requests
+// that produce new output take a version; Vault reads the version used from
+// ciphertexts, signatures and MACs.
+func (k *keeper) withKeyVersion(params map[string]interface{}) map[string]interface{} {
+	if k.opts.KeyVersion > 0 {
+		params["key_version"] = k.opts.KeyVersion
+	}
+	return params
+}
+
+var errNoContext = gcerr.Newf(gcerr.InvalidArgument, nil, "hashivault: convergent encryption requires KeeperOptions.Context")
+
+// MAC implements driver.MACer.MAC using the transit hmac endpoint.
+func (k *keeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	secret, err := k.client.Logical().Write(
+		path.Join(k.opts.Engine+"/hmac", k.keyID, k.opts.MACAlgorithm),
+		k.withKeyVersion(map[string]interface{}{
+			"input": data,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: empty response from hmac")
+	}
+	mac, ok := secret.Data["hmac"].(string)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.Internal, nil, "hashivault: hmac response has no hmac")
+	}
+	return []byte(mac), nil
+}
+
+// VerifyMAC implements driver.MACer.VerifyMAC using the transit verify endpoint.
+func (k *keeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	secret, err := k.client.Logical().Write(
+		path.Join(k.opts.Engine+"/verify", k.keyID, k.opts.MACAlgorithm),
+		map[string]interface{}{
+			"input": data,
+			"hmac":  string(mac),
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	if secret == nil {
+		return false, gcerr.Newf(gcerr.Internal, nil, "hashivault: empty response from verify")
+	}
+	valid, ok := secret.Data["valid"].(bool)
+	if !ok {
+		return false, gcerr.Newf(gcerr.Internal, nil, "hashivault: verify response has no validity result")
+	}
+	return valid, nil
+}
+
+// hashAlgorithm returns the name Vault uses for the hash function that
+// produced digest, based on its length.
+func hashAlgorithm(digest []byte) (string, error) {
+	switch len(digest) {
+	case 32:
+		return "sha2-256", nil
+	case 48:
+		return "sha2-384", nil
+	case 64:
+		return "sha2-512", nil
+	}
+	return "", gcerr.Newf(gcerr.InvalidArgument, nil, "hashivault: digest is %d bytes; want a SHA-256, SHA-384 or SHA-512 digest", len(digest))
+}
+
 // Close implements driver.Keeper.Close.
 func (k *keeper) Close() error { return nil }
 
@@ -243,4 +498,32 @@ type KeeperOptions struct {
 	// Engine is the name of the secrets engine to use.
 	// It defaults to "transit".
 	Engine string
+
+	// SigningAlgorithm is the RSA signature algorithm used by Sign and
+	// Verify; either "pss" or "pkcs1v15". It is ignored for non-RSA keys.
+	// If empty, Vault's default ("pss") is used.
+	SigningAlgorithm string
+
+	// MACAlgorithm is the hash algorithm used by MAC and VerifyMAC, for
+	// example "sha2-256" or "sha2-512".
+	// It defaults to "sha2-256".
+	MACAlgorithm string
+
+	// Context is the key derivation context, required for keys created
+	// with key derivation enabled. It is sent with encrypt, decrypt, data
+	// key, sign and verify requests.
+	Context []byte
+
+	// ConvergentEncryption requests deterministic ciphertexts: the same
+	// plaintext and Context always encrypt to the same ciphertext. It
+	// requires Context, and takes effect only for keys that have convergent
+	// encryption enabled; if the key does not exist yet, Encrypt creates it
+	// with convergent encryption enabled.
+	ConvergentEncryption bool
+
+	// KeyVersion pins Encrypt, Sign and MAC to a version of the key, which
+	// must be at least the key's minimum encryption version. If zero, the
+	// latest version is used. Decryption and verification always use the
+	// version recorded in the input.
+	KeyVersion int
 }
@@ -16,13 +16,18 @@ package hashivault
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/vault/api"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/testing/setup"
 	"gocloud.dev/secrets"
 	"gocloud.dev/secrets/driver"
@@ -35,6 +40,8 @@ import (
 const (
 	keyID1     = "test-secrets"
 	keyID2     = "test-secrets2"
+	signingKey = "test-secrets-signing"
+	macKey     = "test-secrets-mac"
 	apiAddress = "http://127.0.0.1:8200"
 	testToken  = "faketoken"
 )
@@ -51,6 +58,20 @@ func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper,
 	return newKeeper(h.client, keyID1, nil), newKeeper(h.client, keyID2, nil), nil
 }
 
+func (h *harness) MakeSigningDriver(ctx context.Context) (driver.Keeper, error) {
+	if _, err := h.client.Logical().Write("transit/keys/"+signingKey, map[string]interface{}{"type": "ecdsa-p256"}); err != nil {
+		return nil, err
+	}
+	return newKeeper(h.client, signingKey, nil), nil
+}
+
+func (h *harness) MakeMACDriver(ctx context.Context) (driver.Keeper, error) {
+	if _, err := h.client.Logical().Write("transit/keys/"+macKey, map[string]interface{}{"type": "hmac"}); err != nil {
+		return nil, err
+	}
+	return newKeeper(h.client, macKey, nil), nil
+}
+
 func (h *harness) Close() {}
 
 func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
@@ -85,8 +106,26 @@ func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
 	}, nil
 }
 
+func newSignerHarness(ctx context.Context, t *testing.T) (drivertest.SignerHarness, error) {
+	h, err := newHarness(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return h.(*harness), nil
+}
+
+func newMACHarness(ctx context.Context, t *testing.T) (drivertest.MACHarness, error) {
+	h, err := newHarness(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return h.(*harness), nil
+}
+
 func TestConformance(t *testing.T) {
 	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+	drivertest.RunSignerConformanceTests(t, newSignerHarness)
+	drivertest.RunMACConformanceTests(t, newMACHarness)
 }
 
 type verifyAs struct{}
@@ -184,6 +223,20 @@ func TestOpenKeeper(t *testing.T) {
 		{"hashivault://mykey", false},
 		// OK, setting engine.
 		{"hashivault://mykey?engine=foo", false},
+		// OK, setting signing_algorithm.
+		{"hashivault://mykey?signing_algorithm=pkcs1v15", false},
+		// OK, setting mac_algorithm.
+		{"hashivault://mykey?mac_algorithm=sha2-512", false},
+		// OK, setting context and convergent.
+		{"hashivault://mykey?context=tenant1&convergent=true", false},
+		// Convergent without context.
+		{"hashivault://mykey?convergent=true", true},
+		// Invalid convergent.
+		{"hashivault://mykey?context=tenant1&convergent=maybe", true},
+		// OK, setting key_version.
+		{"hashivault://mykey?key_version=2", false},
+		// Invalid key_version.
+		{"hashivault://mykey?key_version=0", true},
 		// Invalid parameter.
 		{"hashivault://mykey?param=value", true},
 	}
@@ -253,3 +306,135 @@ func TestGetVaultConnectionDetails(t *testing.T) {
 		}
 	})
 }
+
+func TestHashAlgorithm(t *testing.T) {
+	for _, tc := range []struct {
+		size int
+		want string
+	}{
+		{32, "sha2-256"},
+		{48, "sha2-384"},
+		{64, "sha2-512"},
+		{0, ""},
+		{20, ""},
+	} {
+		got, err := hashAlgorithm(make([]byte, tc.size))
+		if tc.want == "" {
+			if gcerrors.Code(err) != gcerrors.InvalidArgument {
+				t.Errorf("hashAlgorithm(%d bytes): got error %v, want InvalidArgument", tc.size, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("hashAlgorithm(%d bytes): got %q, %v want %q", tc.size, got, err, tc.want)
+		}
+	}
+}
+
+// recordingServer is a fake Vault server that records the path and
+// parameters of the last request, and answers every request with data.
+type recordingServer struct {
+	data map[string]interface{}
+
+	path   string
+	params map[string]interface{}
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.path = r.URL.Path
+	s.params = nil
+	json.NewDecoder(r.Body).Decode(&s.params)
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": s.data})
+}
+
+func TestKeeperOptionParams(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingServer{data: map[string]interface{}{
+		"ciphertext": "vault:v2:abc",
+		"plaintext":  base64.StdEncoding.EncodeToString([]byte("hello")),
+		"hmac":       "vault:v2:mac",
+	}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	client, err := Dial(ctx, &Config{Token: testToken, APIConfig: api.Config{Address: srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := newKeeper(client, "my-key", &KeeperOptions{
+		Context:              []byte("tenant1"),
+		ConvergentEncryption: true,
+		KeyVersion:           2,
+	})
+	wantContext := base64.StdEncoding.EncodeToString([]byte("tenant1"))
+
+	if _, err := k.Encrypt(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.path != "/v1/transit/encrypt/my-key" {
+		t.Errorf("Encrypt: got path %q", rec.path)
+	}
+	if rec.params["context"] != wantContext || rec.params["convergent_encryption"] != true || rec.params["key_version"] != float64(2) {
+		t.Errorf("Encrypt: got params %v", rec.params)
+	}
+
+	if _, err := k.Decrypt(ctx, []byte("vault:v2:abc")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.params["context"] != wantContext {
+		t.Errorf("Decrypt: got params %v", rec.params)
+	}
+	if _, ok := rec.params["key_version"]; ok {
+		t.Errorf("Decrypt: got key_version in params %v", rec.params)
+	}
+
+	if _, err := k.MAC(ctx, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.params["key_version"] != float64(2) {
+		t.Errorf("MAC: got params %v", rec.params)
+	}
+}
+
+func TestAssociatedDataParams(t *testing.T) {
+	ctx := context.Background()
+	rec := &recordingServer{data: map[string]interface{}{
+		"ciphertext": "vault:v1:abc",
+		"plaintext":  base64.StdEncoding.EncodeToString([]byte("hello")),
+	}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	client, err := Dial(ctx, &Config{Token: testToken, APIConfig: api.Config{Address: srv.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := OpenKeeper(client, "my-key", nil)
+	defer k.Close()
+	wantAAD := base64.StdEncoding.EncodeToString([]byte("tenant1"))
+
+	if _, err := k.EncryptWithAAD(ctx, []byte("hello"), []byte("tenant1")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.params["associated_data"] != wantAAD {
+		t.Errorf("EncryptWithAAD: got params %v", rec.params)
+	}
+	if _, err := k.DecryptWithAAD(ctx, []byte("vault:v1:abc"), []byte("tenant1")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.params["associated_data"] != wantAAD {
+		t.Errorf("DecryptWithAAD: got params %v", rec.params)
+	}
+	if _, err := k.Encrypt(ctx, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rec.params["associated_data"]; ok {
+		t.Errorf("Encrypt: got associated_data in params %v", rec.params)
+	}
+}
+
+func TestConvergentEncryptionRequiresContext(t *testing.T) {
+	k := OpenKeeper(&api.Client{}, "my-key", &KeeperOptions{ConvergentEncryption: true})
+	defer k.Close()
+	if _, err := k.Encrypt(context.Background(), []byte("hello")); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localsecrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+	"golang.org/x/crypto/hkdf"
+)
+
+// eciesInfo is the HKDF info string used to derive the AES-GCM key and
+// nonce of an ECIES ciphertext from the shared secret. The ephemeral and
+// recipient public keys are appended to it.
+const eciesInfo = "gocloud.dev/secrets/localsecrets ECIES AES-256-GCM"
+
+// NewECIESPublicKeyKeeper returns a *secrets.Keeper that encrypts with the
+// given elliptic curve public key, for example an X25519 or P-256 key, using
+// ECIES. It cannot decrypt; Decrypt returns an error with code
+// FailedPrecondition.
+//
+// Each ciphertext is the public key of a new ephemeral key pair, followed by
+// the plaintext encrypted with AES-256-GCM under a key derived with
+// HKDF-SHA256 from the ECDH shared secret of the ephemeral key and pub.
+// The ciphertexts can be decrypted by NewECIESPrivateKeyKeeper with the
+// matching private key.
+func NewECIESPublicKeyKeeper(pub *ecdh.PublicKey) *secrets.Keeper {
+	return secrets.NewKeeper(&eciesKeeper{pub: pub})
+}
+
+// NewECIESPrivateKeyKeeper returns a *secrets.Keeper that encrypts and
+// decrypts with the given elliptic curve private key using ECIES, as
+// described for NewECIESPublicKeyKeeper.
+func NewECIESPrivateKeyKeeper(priv *ecdh.PrivateKey) *secrets.Keeper {
+	return secrets.NewKeeper(&eciesKeeper{pub: priv.PublicKey(), priv: priv})
+}
+
+// eciesKeeper implements driver.Keeper using ECIES.
+type eciesKeeper struct {
+	pub  *ecdh.PublicKey
+	priv *ecdh.PrivateKey // nil for keepers that can only encrypt
+}
+
+// Encrypt encrypts plaintext with the public key.
+func (k *eciesKeeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	eph, err := k.pub.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := eph.ECDH(k.pub)
+	if err != nil {
+		return nil, err
+	}
+	ephPub := eph.PublicKey().Bytes()
+	aead, nonce, err := eciesAEAD(shared, ephPub, k.pub.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(ephPub, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts ciphertext with the private key.
+func (k *eciesKeeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if k.priv == nil {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "localsecrets: Keeper has only a public key and cannot decrypt")
+	}
+	n := len(k.pub.Bytes())
+	if len(ciphertext) < n {
+		return nil, errECIESDecryption
+	}
+	ephPub, err := k.pub.Curve().NewPublicKey(ciphertext[:n])
+	if err != nil {
+		return nil, errECIESDecryption
+	}
+	shared, err := k.priv.ECDH(ephPub)
+	if err != nil {
+		return nil, errECIESDecryption
+	}
+	aead, nonce, err := eciesAEAD(shared, ciphertext[:n], k.pub.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext[n:], nil)
+	if err != nil {
+		return nil, errECIESDecryption
+	}
+	return plaintext, nil
+}
+
+var errECIESDecryption = gcerr.Newf(gcerr.InvalidArgument, nil, "localsecrets: ECIES decryption error")
+
+// eciesAEAD returns the AES-256-GCM cipher and nonce for an ECIES
+// ciphertext, derived from the ECDH shared secret and both public keys.
+// Since each ciphertext has its own ephemeral key, the key and nonce are
+// never reused.
+func eciesAEAD(shared, ephPub, pub []byte) (cipher.AEAD, []byte, error) {
+	info := append(append([]byte(eciesInfo), ephPub...), pub...)
+	key := make([]byte, 32+12)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, info), key); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, key[32:], nil
+}
+
+// PublicKey implements driver.PublicKeyer.PublicKey.
+func (k *eciesKeeper) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	return k.pub, nil
+}
+
+// Close implements driver.Keeper.Close.
+func (k *eciesKeeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *eciesKeeper) ErrorAs(err error, i interface{}) bool {
+	return false
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *eciesKeeper) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
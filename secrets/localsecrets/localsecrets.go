@@ -16,6 +16,13 @@
 // provided symmetric key.
 // Use NewKeeper to construct a *secrets.Keeper.
 //
+// NewPublicKeyKeeper and NewPrivateKeyKeeper construct Keepers that use a
+// locally provided RSA key with RSA-OAEP instead, and NewECIESPublicKeyKeeper
+// and NewECIESPrivateKeyKeeper construct Keepers that use an elliptic curve
+// key, such as X25519 or P-256, with ECIES. A Keeper with only the public key
+// can encrypt, for example to a cloud KMS asymmetric RSA key, without any
+// credentials.
+//
 // # URLs
 //
 // For secrets.OpenKeeper, localsecrets registers for the scheme "base64key".
@@ -23,6 +30,18 @@
 // see URLOpener.
 // See https://gocloud.dev/concepts/urls/ for background information.
 //
+// # MACs
+//
+// Keepers support MAC and VerifyMAC using HMAC-SHA256. The HMAC key is
+// derived from the secret key with HKDF-SHA256, so the same key material is
+// never used directly for both encryption and MACs.
+//
+// # Associated data
+//
+// Keepers created with NewKeeper support EncryptWithAAD and DecryptWithAAD
+// using XChaCha20-Poly1305 with a key derived from the secret key with
+// HKDF-SHA256.
+//
 // # As
 //
 // localsecrets does not support any types for As.
@@ -30,7 +49,10 @@ package localsecrets // import "gocloud.dev/secrets/localsecrets"
 
 import (
 	"context"
+	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -39,6 +61,8 @@ import (
 
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/secrets"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/nacl/secretbox"
 )
 
@@ -59,12 +83,35 @@ const (
 // Note that base64.URLEncoding should be used to avoid URL-unsafe character in the hostname.
 // If the URL host is empty (e.g., "base64key://"), a new random key is generated.
 //
-// No query parameters are supported.
+// Alternatively, the key can be derived from a passphrase with PassphraseKey,
+// using an empty URL host and the following query parameters:
+//   - passphrase: The passphrase.
+//   - salt: The salt, base64 encoded with base64.URLEncoding; required.
+//   - kdf: Sets KDFOptions.KDF; "argon2id" (the default) or "scrypt".
+//   - time, memory, threads: Set the Argon2id KDFOptions.
+//   - n, r, p: Set the scrypt KDFOptions.
+//
+// For example, "base64key://?passphrase=correct-horse&salt=c2FsdHNhbHRzYWx0c2FsdA==&kdf=scrypt".
+// Keep in mind that URLs are easily logged; prefer reading the passphrase from
+// a secret store in production.
+//
+// No other query parameters are supported.
 type URLOpener struct{}
 
 // OpenKeeperURL opens Keeper URLs.
 func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
-	for param := range u.Query() {
+	q := u.Query()
+	if q.Has("passphrase") {
+		if u.Host != "" {
+			return nil, fmt.Errorf("open keeper %v: a passphrase cannot be combined with a key", u)
+		}
+		sk, err := passphraseKeyFromURLParams(q)
+		if err != nil {
+			return nil, fmt.Errorf("open keeper %v: %v", u, err)
+		}
+		return NewKeeper(sk), nil
+	}
+	for param := range q {
 		return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
 	}
 	var sk [32]byte
@@ -165,6 +212,89 @@ func (k *keeper) Decrypt(ctx context.Context, message []byte) ([]byte, error) {
 	return decrypted, nil
 }
 
+// macKeyInfo is the HKDF info string used to derive the HMAC key from the
+// secret key.
+const macKeyInfo = "gocloud.dev/secrets/localsecrets HMAC-SHA256"
+
+// MAC implements driver.MACer.MAC using HMAC-SHA256.
+func (k *keeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	key, err := k.macKey()
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// VerifyMAC implements driver.MACer.VerifyMAC.
+func (k *keeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	want, err := k.MAC(ctx, data)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(mac, want), nil
+}
+
+// macKey derives the HMAC key from the secret key.
+func (k *keeper) macKey() ([]byte, error) {
+	return k.deriveKey(macKeyInfo, sha256.Size)
+}
+
+// deriveKey derives a subkey of size bytes from the secret key with
+// HKDF-SHA256, using info to separate its purpose from other subkeys.
+func (k *keeper) deriveKey(info string, size int) ([]byte, error) {
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, k.secretKey[:], nil, []byte(info)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// aadKeyInfo is the HKDF info string used to derive the
+// XChaCha20-Poly1305 key used with associated data from the secret key.
+const aadKeyInfo = "gocloud.dev/secrets/localsecrets XChaCha20-Poly1305"
+
+// aead returns the AEAD used by EncryptWithAAD and DecryptWithAAD.
+func (k *keeper) aead() (cipher.AEAD, error) {
+	key, err := k.deriveKey(aadKeyInfo, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD using
+// XChaCha20-Poly1305, since secretbox does not support associated data.
+// Like Encrypt, the ciphertext starts with a random 24-byte nonce.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("localsecrets: invalid message length (%d, expected at least %d)", len(ciphertext), aead.NonceSize())
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, errors.New("localsecrets: DecryptWithAAD failed")
+	}
+	return plaintext, nil
+}
+
 // Close implements driver.Keeper.Close.
 func (k *keeper) Close() error { return nil }
 
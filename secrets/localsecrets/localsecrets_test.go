@@ -16,6 +16,8 @@ package localsecrets
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"log"
 	"strings"
@@ -40,6 +42,14 @@ func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper,
 	return &keeper{secretKey: secret1}, &keeper{secretKey: secret2}, nil
 }
 
+func (h *harness) MakeMACDriver(ctx context.Context) (driver.Keeper, error) {
+	secret, err := NewRandomKey()
+	if err != nil {
+		return nil, err
+	}
+	return &keeper{secretKey: secret}, nil
+}
+
 func (h *harness) Close() {}
 
 func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
@@ -48,8 +58,14 @@ func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
 	return &harness{}, nil
 }
 
+func newMACHarness(ctx context.Context, t *testing.T) (drivertest.MACHarness, error) {
+	return &harness{}, nil
+}
+
 func TestConformance(t *testing.T) {
 	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+	drivertest.RunMACConformanceTests(t, newMACHarness)
+	drivertest.RunAADConformanceTests(t, newHarness)
 }
 
 type verifyAs struct{}
@@ -136,6 +152,22 @@ func TestOpenKeeper(t *testing.T) {
 		{"base64Key://UKcmEoZW7nKl0uPHr8yV__KJm0ANhiFz8PzDN-gYWq8=", false},
 		// Invalid parameter.
 		{"base64key://?param=value", true},
+		// OK, passphrase with argon2id.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&memory=1024&time=1", false},
+		// OK, passphrase with scrypt.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&kdf=scrypt&n=1024", false},
+		// Passphrase without salt.
+		{"base64key://?passphrase=secret", true},
+		// Passphrase with a key.
+		{"base64key://smGbjm71Nxd1Ig5FS0wj9SlbzAIrnolCz9bQQ6uAhl4=?passphrase=secret&salt=c2FsdHNhbHQ=", true},
+		// Unknown KDF.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&kdf=md5", true},
+		// scrypt parameter with argon2id.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&n=1024", true},
+		// Invalid scrypt N.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&kdf=scrypt&n=1000", true},
+		// Invalid parameter with passphrase.
+		{"base64key://?passphrase=secret&salt=c2FsdHNhbHQ=&param=value", true},
 	}
 
 	ctx := context.Background()
@@ -151,3 +183,56 @@ func TestOpenKeeper(t *testing.T) {
 		}
 	}
 }
+
+func TestMACKeyDerivation(t *testing.T) {
+	ctx := context.Background()
+	key1, err := NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello world")
+	mac1, err := NewKeeper(key1).MAC(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mac1) != sha256.Size {
+		t.Errorf("got MAC of %d bytes, want %d", len(mac1), sha256.Size)
+	}
+	// The HMAC key must not be the secret key itself.
+	h := hmac.New(sha256.New, key1[:])
+	h.Write(data)
+	if hmac.Equal(mac1, h.Sum(nil)) {
+		t.Error("MAC is keyed directly with the secret key, want a derived key")
+	}
+	ok, err := NewKeeper(key2).VerifyMAC(ctx, data, mac1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("VerifyMAC with a different key returned true")
+	}
+}
+
+func TestAADDiffersFromEncrypt(t *testing.T) {
+	ctx := context.Background()
+	key, err := NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := NewKeeper(key)
+	defer k.Close()
+
+	// Ciphertexts bound to associated data can't be decrypted without it,
+	// even by Decrypt.
+	ciphertext, err := k.EncryptWithAAD(ctx, []byte("hello"), []byte("tenant"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Decrypt(ctx, ciphertext); err == nil {
+		t.Error("Decrypt succeeded for a ciphertext from EncryptWithAAD")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localsecrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key derivation functions supported by PassphraseKey.
+const (
+	KDFArgon2id = "argon2id"
+	KDFScrypt   = "scrypt"
+)
+
+// minSaltSize is the minimum salt size accepted by PassphraseKey.
+const minSaltSize = 8
+
+// KDFOptions controls how PassphraseKey derives a key. Zero values select
+// the defaults, which follow the recommendations of RFC 9106 for Argon2id
+// and of the scrypt paper for interactive use.
+//
+// The same options, salt and passphrase always derive the same key; changing
+// any of them derives a different key, so they must be kept alongside the
+// data encrypted with it.
+type KDFOptions struct {
+	// KDF is the key derivation function, KDFArgon2id or KDFScrypt.
+	// It defaults to KDFArgon2id.
+	KDF string
+
+	// Time is the number of Argon2id passes. It defaults to 3.
+	Time uint32
+	// Memory is the Argon2id memory size in KiB. It defaults to 65536
+	// (64 MiB).
+	Memory uint32
+	// Threads is the Argon2id degree of parallelism. It defaults to 4.
+	Threads uint8
+
+	// N is the scrypt CPU/memory cost; it must be a power of two greater
+	// than 1. It defaults to 32768.
+	N int
+	// R is the scrypt block size. It defaults to 8.
+	R int
+	// P is the scrypt parallelization. It defaults to 1.
+	P int
+}
+
+// PassphraseKey derives secret key material suitable to be used as the
+// secret key argument to NewKeeper from a passphrase and a salt, using
+// Argon2id or scrypt. The salt must be at least 8 bytes; 16 random bytes
+// are recommended. opts may be nil to use the defaults.
+//
+// Deriving keys from passphrases is intended for development and
+// deployments without a key management service. Passphrases usually have
+// much less entropy than random keys, so use a long one.
+func PassphraseKey(passphrase string, salt []byte, opts *KDFOptions) ([32]byte, error) {
+	var sk [32]byte
+	if passphrase == "" {
+		return sk, errors.New("PassphraseKey: passphrase must not be empty")
+	}
+	if len(salt) < minSaltSize {
+		return sk, fmt.Errorf("PassphraseKey: salt is %d bytes, want at least %d bytes", len(salt), minSaltSize)
+	}
+	if opts == nil {
+		opts = &KDFOptions{}
+	}
+	switch opts.KDF {
+	case "", KDFArgon2id:
+		t, m, p := opts.Time, opts.Memory, opts.Threads
+		if t == 0 {
+			t = 3
+		}
+		if m == 0 {
+			m = 64 * 1024
+		}
+		if p == 0 {
+			p = 4
+		}
+		copy(sk[:], argon2.IDKey([]byte(passphrase), salt, t, m, p, uint32(len(sk))))
+	case KDFScrypt:
+		n, r, p := opts.N, opts.R, opts.P
+		if n == 0 {
+			n = 32768
+		}
+		if r == 0 {
+			r = 8
+		}
+		if p == 0 {
+			p = 1
+		}
+		key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, len(sk))
+		if err != nil {
+			return sk, fmt.Errorf("PassphraseKey: %v", err)
+		}
+		copy(sk[:], key)
+	default:
+		return sk, fmt.Errorf("PassphraseKey: unsupported KDF %q", opts.KDF)
+	}
+	return sk, nil
+}
+
+// passphraseKeyFromURLParams derives a key from the passphrase URL
+// parameters described in URLOpener.
+func passphraseKeyFromURLParams(q url.Values) ([32]byte, error) {
+	var sk [32]byte
+	var opts KDFOptions
+	var salt []byte
+	for param, vals := range q {
+		val := vals[0]
+		var err error
+		switch param {
+		case "passphrase":
+		case "salt":
+			salt, err = base64.URLEncoding.DecodeString(val)
+		case "kdf":
+			opts.KDF = val
+		case "time":
+			var v uint64
+			v, err = strconv.ParseUint(val, 10, 32)
+			opts.Time = uint32(v)
+		case "memory":
+			var v uint64
+			v, err = strconv.ParseUint(val, 10, 32)
+			opts.Memory = uint32(v)
+		case "threads":
+			var v uint64
+			v, err = strconv.ParseUint(val, 10, 8)
+			opts.Threads = uint8(v)
+		case "n":
+			opts.N, err = strconv.Atoi(val)
+		case "r":
+			opts.R, err = strconv.Atoi(val)
+		case "p":
+			opts.P, err = strconv.Atoi(val)
+		default:
+			return sk, fmt.Errorf("invalid query parameter %q", param)
+		}
+		if err != nil {
+			return sk, fmt.Errorf("invalid value %q for query parameter %q: %v", val, param, err)
+		}
+	}
+	if salt == nil {
+		return sk, errors.New("a passphrase requires a salt query parameter")
+	}
+	switch opts.KDF {
+	case KDFScrypt:
+		if opts.Time != 0 || opts.Memory != 0 || opts.Threads != 0 {
+			return sk, errors.New("time, memory and threads only apply to argon2id")
+		}
+	default:
+		if opts.N != 0 || opts.R != 0 || opts.P != 0 {
+			return sk, errors.New("n, r and p only apply to scrypt")
+		}
+	}
+	return PassphraseKey(q.Get("passphrase"), salt, &opts)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localsecrets
+
+import (
+	"context"
+	"testing"
+
+	"gocloud.dev/secrets"
+)
+
+// fastArgon2 and fastScrypt keep the tests quick; they are far too weak for
+// real use.
+var (
+	fastArgon2 = &KDFOptions{Time: 1, Memory: 1024, Threads: 1}
+	fastScrypt = &KDFOptions{KDF: KDFScrypt, N: 1024}
+)
+
+func TestPassphraseKey(t *testing.T) {
+	salt := []byte("saltsaltsalt")
+	for _, opts := range []*KDFOptions{fastArgon2, fastScrypt} {
+		k1, err := PassphraseKey("passphrase", salt, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k2, err := PassphraseKey("passphrase", salt, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k1 != k2 {
+			t.Errorf("%s: same inputs derived different keys", opts.KDF)
+		}
+		k3, err := PassphraseKey("passphrase", []byte("othersaltsalt"), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k1 == k3 {
+			t.Errorf("%s: different salts derived the same key", opts.KDF)
+		}
+		k4, err := PassphraseKey("other passphrase", salt, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k1 == k4 {
+			t.Errorf("%s: different passphrases derived the same key", opts.KDF)
+		}
+	}
+
+	a, _ := PassphraseKey("passphrase", salt, fastArgon2)
+	s, _ := PassphraseKey("passphrase", salt, fastScrypt)
+	if a == s {
+		t.Error("argon2id and scrypt derived the same key")
+	}
+}
+
+func TestPassphraseKeyErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		passphrase string
+		salt       []byte
+		opts       *KDFOptions
+	}{
+		{"empty passphrase", "", []byte("saltsaltsalt"), fastArgon2},
+		{"short salt", "passphrase", []byte("salt"), fastArgon2},
+		{"unknown KDF", "passphrase", []byte("saltsaltsalt"), &KDFOptions{KDF: "pbkdf1"}},
+		{"bad scrypt N", "passphrase", []byte("saltsaltsalt"), &KDFOptions{KDF: KDFScrypt, N: 1000}},
+	} {
+		if _, err := PassphraseKey(tc.passphrase, tc.salt, tc.opts); err == nil {
+			t.Errorf("%s: got nil error, want error", tc.name)
+		}
+	}
+}
+
+func TestPassphraseURL(t *testing.T) {
+	ctx := context.Background()
+	sk, err := PassphraseKey("correct horse", []byte("saltsaltsalt"), fastScrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := NewKeeper(sk)
+	defer k.Close()
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "c2FsdHNhbHRzYWx0" is "saltsaltsalt" in base64.
+	fromURL, err := secrets.OpenKeeper(ctx, "base64key://?passphrase=correct+horse&salt=c2FsdHNhbHRzYWx0&kdf=scrypt&n=1024")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromURL.Close()
+	got, err := fromURL.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q want %q", got, "hello")
+	}
+}
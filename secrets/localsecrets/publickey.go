@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localsecrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+// NewPublicKeyKeeper returns a *secrets.Keeper that encrypts with the given
+// RSA public key using RSA-OAEP, with hash used for both OAEP and MGF1.
+// It cannot decrypt; Decrypt returns an error with code FailedPrecondition.
+//
+// The ciphertexts it produces can be decrypted by a Keeper holding the
+// matching private key, for example an awskms Keeper using
+// "RSAES_OAEP_SHA_256" with crypto.SHA256, or a gcpkms Keeper for a
+// RSA_DECRYPT_OAEP_*_SHA256 key. Use secrets.Keeper.PublicKey to get the
+// public key from such a Keeper.
+func NewPublicKeyKeeper(pub *rsa.PublicKey, hash crypto.Hash) *secrets.Keeper {
+	return secrets.NewKeeper(&rsaKeeper{pub: pub, hash: hash})
+}
+
+// NewPrivateKeyKeeper returns a *secrets.Keeper that encrypts and decrypts
+// with the given RSA private key using RSA-OAEP, with hash used for both
+// OAEP and MGF1. It can decrypt ciphertexts produced by NewPublicKeyKeeper
+// for the matching public key.
+func NewPrivateKeyKeeper(priv *rsa.PrivateKey, hash crypto.Hash) *secrets.Keeper {
+	return secrets.NewKeeper(&rsaKeeper{pub: &priv.PublicKey, priv: priv, hash: hash})
+}
+
+// rsaKeeper implements driver.Keeper using RSA-OAEP.
+type rsaKeeper struct {
+	pub  *rsa.PublicKey
+	priv *rsa.PrivateKey // nil for keepers that can only encrypt
+	hash crypto.Hash
+}
+
+// Encrypt encrypts plaintext with the public key.
+func (k *rsaKeeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if !k.hash.Available() {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "localsecrets: hash function %v is not available", k.hash)
+	}
+	return rsa.EncryptOAEP(k.hash.New(), rand.Reader, k.pub, plaintext, nil)
+}
+
+// Decrypt decrypts ciphertext with the private key.
+func (k *rsaKeeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if k.priv == nil {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "localsecrets: Keeper has only a public key and cannot decrypt")
+	}
+	if !k.hash.Available() {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "localsecrets: hash function %v is not available", k.hash)
+	}
+	return rsa.DecryptOAEP(k.hash.New(), rand.Reader, k.priv, ciphertext, nil)
+}
+
+// PublicKey implements driver.PublicKeyer.PublicKey.
+func (k *rsaKeeper) PublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	return k.pub, nil
+}
+
+// Close implements driver.Keeper.Close.
+func (k *rsaKeeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *rsaKeeper) ErrorAs(err error, i interface{}) bool {
+	return false
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *rsaKeeper) ErrorCode(err error) gcerrors.ErrorCode {
+	if err == rsa.ErrDecryption || err == rsa.ErrMessageTooLong {
+		return gcerrors.InvalidArgument
+	}
+	return gcerrors.Unknown
+}
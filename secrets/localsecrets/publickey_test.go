@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localsecrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"gocloud.dev/gcerrors"
+)
+
+func TestPublicKeyKeeper(t *testing.T) {
+	ctx := context.Background()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKeeper := NewPrivateKeyKeeper(priv, crypto.SHA256)
+	defer privKeeper.Close()
+
+	// Get the public key from the private Keeper, as an offline producer would.
+	pub, err := privKeeper.PublicKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("got public key of type %T, want *rsa.PublicKey", pub)
+	}
+	pubKeeper := NewPublicKeyKeeper(rsaPub, crypto.SHA256)
+	defer pubKeeper.Close()
+
+	const plaintext = "hello world"
+	ciphertext, err := pubKeeper.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pubKeeper.Decrypt(ctx, ciphertext); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("Decrypt with public key: got error %v, want FailedPrecondition", err)
+	}
+	got, err := privKeeper.Decrypt(ctx, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != plaintext {
+		t.Errorf("got %q want %q", got, plaintext)
+	}
+
+	// A ciphertext encrypted with a different OAEP hash doesn't decrypt.
+	other := NewPublicKeyKeeper(rsaPub, crypto.SHA512)
+	defer other.Close()
+	ciphertext, err = other.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := privKeeper.Decrypt(ctx, ciphertext); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Decrypt with mismatched hash: got error %v, want InvalidArgument", err)
+	}
+}
+
+func TestECIESKeeper(t *testing.T) {
+	ctx := context.Background()
+	for _, curve := range []ecdh.Curve{ecdh.X25519(), ecdh.P256()} {
+		priv, err := curve.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privKeeper := NewECIESPrivateKeyKeeper(priv)
+		defer privKeeper.Close()
+
+		// Get the public key from the private Keeper, as an offline producer would.
+		pub, err := privKeeper.PublicKey(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecdhPub, ok := pub.(*ecdh.PublicKey)
+		if !ok {
+			t.Fatalf("%v: got public key of type %T, want *ecdh.PublicKey", curve, pub)
+		}
+		pubKeeper := NewECIESPublicKeyKeeper(ecdhPub)
+		defer pubKeeper.Close()
+
+		const plaintext = "hello world"
+		ciphertext, err := pubKeeper.Encrypt(ctx, []byte(plaintext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pubKeeper.Decrypt(ctx, ciphertext); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+			t.Errorf("%v: Decrypt with public key: got error %v, want FailedPrecondition", curve, err)
+		}
+		got, err := privKeeper.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != plaintext {
+			t.Errorf("%v: got %q want %q", curve, got, plaintext)
+		}
+
+		// Modified or truncated ciphertexts don't decrypt.
+		ciphertext[len(ciphertext)-1] ^= 1
+		for _, c := range [][]byte{ciphertext, ciphertext[:10]} {
+			if _, err := privKeeper.Decrypt(ctx, c); gcerrors.Code(err) != gcerrors.InvalidArgument {
+				t.Errorf("%v: Decrypt of bad ciphertext: got error %v, want InvalidArgument", curve, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11secrets_test
+
+import (
+	"context"
+	"log"
+
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/pkcs11secrets"
+)
+
+func ExampleOpenKeeper() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+
+	// Open a session with the token.
+	session, err := pkcs11secrets.Dial(&pkcs11secrets.Config{
+		ModulePath: "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel: "my-token",
+		PIN:        "1234",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer session.Close()
+
+	// Construct a *secrets.Keeper.
+	keeper := pkcs11secrets.OpenKeeper(session, "my-key", nil)
+	defer keeper.Close()
+}
+
+func Example_openFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/secrets/pkcs11secrets"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	keeper, err := secrets.OpenKeeper(ctx, "pkcs11://my-key")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer keeper.Close()
+}
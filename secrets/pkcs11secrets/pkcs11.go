@@ -0,0 +1,340 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkcs11secrets provides a secrets implementation backed by a
+// PKCS#11 token, such as a hardware security module (HSM), SoftHSM, AWS
+// CloudHSM or a YubiHSM. Encryption and decryption happen on the token, so
+// key material never leaves it.
+// Use OpenKeeper to construct a *secrets.Keeper.
+//
+// Keys are AES secret keys (CKO_SECRET_KEY objects) identified by their
+// CKA_LABEL, and are used with the CKM_AES_GCM mechanism; the token must
+// support it.
+//
+// This package requires cgo, since it loads the token's PKCS#11 library.
+//
+// # URLs
+//
+// For secrets.OpenKeeper, pkcs11secrets registers for the scheme "pkcs11".
+// The default URL opener will open a session using the environment variables
+// PKCS11_MODULE (the path to the PKCS#11 library), PKCS11_TOKEN (the label of
+// the token) and PKCS11_PIN (the user PIN).
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// pkcs11secrets exposes the following type for As:
+//   - Error: github.com/miekg/pkcs11.Error
+package pkcs11secrets // import "gocloud.dev/secrets/pkcs11secrets"
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+// Config is the authentication configuration for a PKCS#11 token.
+type Config struct {
+	// ModulePath is the path to the PKCS#11 library of the token, for
+	// example "/usr/lib/softhsm/libsofthsm2.so".
+	ModulePath string
+	// TokenLabel is the label of the token to use.
+	TokenLabel string
+	// PIN is the user PIN of the token.
+	PIN string
+}
+
+// Session is a logged-in session with a PKCS#11 token. It is safe for
+// concurrent use; operations on the token are serialized.
+type Session struct {
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+}
+
+// Dial loads the PKCS#11 library in cfg, and opens and logs in to a session
+// with the token labeled cfg.TokenLabel. Call Close when done with it.
+func Dial(cfg *Config) (*Session, error) {
+	if cfg == nil {
+		return nil, errors.New("no Config provided")
+	}
+	p := pkcs11.New(cfg.ModulePath)
+	if p == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 library %q", cfg.ModulePath)
+	}
+	s, err := openSession(p, cfg)
+	if err != nil {
+		p.Destroy()
+		return nil, err
+	}
+	return s, nil
+}
+
+func openSession(p *pkcs11.Ctx, cfg *Config) (*Session, error) {
+	if err := p.Initialize(); err != nil {
+		return nil, err
+	}
+	slots, err := p.GetSlotList(true)
+	if err != nil {
+		p.Finalize()
+		return nil, err
+	}
+	for _, slot := range slots {
+		info, err := p.GetTokenInfo(slot)
+		if err != nil || info.Label != cfg.TokenLabel {
+			continue
+		}
+		sh, err := p.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		if err != nil {
+			p.Finalize()
+			return nil, err
+		}
+		if err := p.Login(sh, pkcs11.CKU_USER, cfg.PIN); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+			p.CloseSession(sh)
+			p.Finalize()
+			return nil, err
+		}
+		return &Session{ctx: p, session: sh}, nil
+	}
+	p.Finalize()
+	return nil, fmt.Errorf("no PKCS#11 token labeled %q", cfg.TokenLabel)
+}
+
+// Close logs out, closes the session and unloads the PKCS#11 library.
+// Keepers using the Session must not be used afterwards.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx.Logout(s.session)
+	err := s.ctx.CloseSession(s.session)
+	if ferr := s.ctx.Finalize(); err == nil {
+		err = ferr
+	}
+	s.ctx.Destroy()
+	return err
+}
+
+func init() {
+	secrets.DefaultURLMux().RegisterKeeper(Scheme, new(defaultDialer))
+}
+
+// defaultDialer dials a default token based on the environment variables
+// PKCS11_MODULE, PKCS11_TOKEN and PKCS11_PIN.
+type defaultDialer struct {
+	init   sync.Once
+	opener *URLOpener
+	err    error
+}
+
+func (o *defaultDialer) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
+	o.init.Do(func() {
+		cfg := Config{
+			ModulePath: os.Getenv("PKCS11_MODULE"),
+			TokenLabel: os.Getenv("PKCS11_TOKEN"),
+			PIN:        os.Getenv("PKCS11_PIN"),
+		}
+		if cfg.ModulePath == "" {
+			o.err = errors.New("PKCS11_MODULE environment variable is not set")
+			return
+		}
+		s, err := Dial(&cfg)
+		if err != nil {
+			o.err = fmt.Errorf("failed to Dial default PKCS#11 token %q: %v", cfg.TokenLabel, err)
+			return
+		}
+		o.opener = &URLOpener{Session: s}
+	})
+	if o.err != nil {
+		return nil, fmt.Errorf("open keeper %v: %v", u, o.err)
+	}
+	return o.opener.OpenKeeperURL(ctx, u)
+}
+
+// Scheme is the URL scheme pkcs11secrets registers its URLOpener under on
+// secrets.DefaultMux.
+const Scheme = "pkcs11"
+
+// URLOpener opens PKCS#11 URLs like "pkcs11://mykey".
+//
+// The URL Host + Path are used as the label of the key.
+//
+// No query parameters are supported.
+type URLOpener struct {
+	// Session must be non-nil.
+	Session *Session
+
+	// Options specifies the options to pass to OpenKeeper.
+	Options KeeperOptions
+}
+
+// OpenKeeperURL opens the Keeper URL.
+func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
+	for param := range u.Query() {
+		return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
+	}
+	return OpenKeeper(o.Session, path.Join(u.Host, u.Path), &o.Options), nil
+}
+
+// KeeperOptions controls Keeper behaviors.
+// It is provided for future extensibility.
+type KeeperOptions struct{}
+
+// OpenKeeper returns a *secrets.Keeper that encrypts and decrypts on the
+// token of s, with the AES key labeled keyLabel. The key is looked up on
+// first use.
+// See the package documentation for an example.
+func OpenKeeper(s *Session, keyLabel string, opts *KeeperOptions) *secrets.Keeper {
+	return secrets.NewKeeper(&keeper{s: s, keyLabel: keyLabel})
+}
+
+// keeper implements driver.Keeper.
+type keeper struct {
+	s        *Session
+	keyLabel string
+
+	// key is the handle of the key, found on first use; protected by s.mu.
+	key   pkcs11.ObjectHandle
+	found bool
+}
+
+const (
+	ivSize  = 12
+	tagBits = 128
+)
+
+// findKey returns the handle of the key. s.mu must be held.
+func (k *keeper) findKey() (pkcs11.ObjectHandle, error) {
+	if k.found {
+		return k.key, nil
+	}
+	p, sh := k.s.ctx, k.s.session
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, k.keyLabel),
+	}
+	if err := p.FindObjectsInit(sh, template); err != nil {
+		return 0, err
+	}
+	handles, _, err := p.FindObjects(sh, 2)
+	if ferr := p.FindObjectsFinal(sh); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return 0, err
+	}
+	switch len(handles) {
+	case 0:
+		return 0, gcerr.Newf(gcerr.NotFound, nil, "pkcs11secrets: no secret key labeled %q", k.keyLabel)
+	case 1:
+		k.key, k.found = handles[0], true
+		return k.key, nil
+	default:
+		return 0, gcerr.Newf(gcerr.FailedPrecondition, nil, "pkcs11secrets: more than one secret key labeled %q", k.keyLabel)
+	}
+}
+
+// Encrypt encrypts the plaintext into a ciphertext, which consists of the
+// IV followed by the AES-GCM output of the token.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	k.s.mu.Lock()
+	defer k.s.mu.Unlock()
+	key, err := k.findKey()
+	if err != nil {
+		return nil, err
+	}
+	params := pkcs11.NewGCMParams(iv, nil, tagBits)
+	defer params.Free()
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := k.s.ctx.EncryptInit(k.s.session, mech, key); err != nil {
+		return nil, err
+	}
+	out, err := k.s.ctx.Encrypt(k.s.session, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	// Some tokens ignore the given IV and generate their own.
+	if actual := params.IV(); len(actual) == ivSize {
+		iv = actual
+	}
+	return append(iv, out...), nil
+}
+
+// Decrypt decrypts the ciphertext into a plaintext.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < ivSize+tagBits/8 {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "pkcs11secrets: ciphertext is %d bytes, want at least %d", len(ciphertext), ivSize+tagBits/8)
+	}
+	k.s.mu.Lock()
+	defer k.s.mu.Unlock()
+	key, err := k.findKey()
+	if err != nil {
+		return nil, err
+	}
+	params := pkcs11.NewGCMParams(ciphertext[:ivSize], nil, tagBits)
+	defer params.Free()
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := k.s.ctx.DecryptInit(k.s.session, mech, key); err != nil {
+		return nil, err
+	}
+	return k.s.ctx.Decrypt(k.s.session, ciphertext[ivSize:])
+}
+
+// Close implements driver.Keeper.Close. It does not close the Session.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	var perr pkcs11.Error
+	if !errors.As(err, &perr) {
+		return gcerrors.Unknown
+	}
+	if code, ok := errorCodeMap[perr]; ok {
+		return code
+	}
+	return gcerrors.Unknown
+}
+
+var errorCodeMap = map[pkcs11.Error]gcerrors.ErrorCode{
+	pkcs11.CKR_ENCRYPTED_DATA_INVALID:     gcerrors.InvalidArgument,
+	pkcs11.CKR_ENCRYPTED_DATA_LEN_RANGE:   gcerrors.InvalidArgument,
+	pkcs11.CKR_DATA_LEN_RANGE:             gcerrors.InvalidArgument,
+	pkcs11.CKR_KEY_HANDLE_INVALID:         gcerrors.NotFound,
+	pkcs11.CKR_KEY_FUNCTION_NOT_PERMITTED: gcerrors.PermissionDenied,
+	pkcs11.CKR_USER_NOT_LOGGED_IN:         gcerrors.PermissionDenied,
+	pkcs11.CKR_PIN_INCORRECT:              gcerrors.PermissionDenied,
+	pkcs11.CKR_MECHANISM_INVALID:          gcerrors.Unimplemented,
+	pkcs11.CKR_DEVICE_MEMORY:              gcerrors.ResourceExhausted,
+	pkcs11.CKR_HOST_MEMORY:                gcerrors.ResourceExhausted,
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+)
+
+// The conformance tests run against a token configured with the environment
+// variables PKCS11_MODULE, PKCS11_TOKEN and PKCS11_PIN, and are skipped if
+// PKCS11_MODULE is not set. With SoftHSM, for example:
+//
+//	softhsm2-util --init-token --free --label test --pin 1234 --so-pin 1234
+//	PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN=test PKCS11_PIN=1234 go test
+type harness struct {
+	s *Session
+}
+
+// generateKey creates a session AES-256 key labeled label on the token.
+func (h *harness) generateKey(label string) error {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	_, err := h.s.ctx.GenerateKey(h.s.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+			pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		})
+	return err
+}
+
+var keyCount int
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	var keepers []driver.Keeper
+	for i := 0; i < 2; i++ {
+		keyCount++
+		label := fmt.Sprintf("gocloud-test-%d", keyCount)
+		if err := h.generateKey(label); err != nil {
+			return nil, nil, err
+		}
+		keepers = append(keepers, &keeper{s: h.s, keyLabel: label})
+	}
+	return keepers[0], keepers[1], nil
+}
+
+func (h *harness) Close() {
+	h.s.Close()
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("Skipping PKCS#11 tests since PKCS11_MODULE is not set")
+	}
+	s, err := Dial(&Config{
+		ModulePath: module,
+		TokenLabel: os.Getenv("PKCS11_TOKEN"),
+		PIN:        os.Getenv("PKCS11_PIN"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &harness{s: s}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var perr pkcs11.Error
+	if !k.ErrorAs(err, &perr) {
+		return errors.New("Keeper.ErrorAs failed")
+	}
+	return nil
+}
+
+// PKCS#11-specific tests.
+
+func TestErrorCode(t *testing.T) {
+	k := &keeper{}
+	for _, tc := range []struct {
+		err  error
+		want gcerrors.ErrorCode
+	}{
+		{pkcs11.Error(pkcs11.CKR_ENCRYPTED_DATA_INVALID), gcerrors.InvalidArgument},
+		{pkcs11.Error(pkcs11.CKR_PIN_INCORRECT), gcerrors.PermissionDenied},
+		{pkcs11.Error(pkcs11.CKR_MECHANISM_INVALID), gcerrors.Unimplemented},
+		{pkcs11.Error(pkcs11.CKR_GENERAL_ERROR), gcerrors.Unknown},
+		{errors.New("other"), gcerrors.Unknown},
+	} {
+		if got := k.ErrorCode(tc.err); got != tc.want {
+			t.Errorf("%v: got %v want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestOpenKeeper(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"pkcs11://mykey", false},
+		// Invalid parameter.
+		{"pkcs11://mykey?param=value", true},
+	}
+
+	ctx := context.Background()
+	o := &URLOpener{}
+	for _, test := range tests {
+		u, err := url.Parse(test.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keeper, err := o.OpenKeeperURL(ctx, u)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = keeper.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
+
+func TestDefaultDialerRequiresModule(t *testing.T) {
+	t.Setenv("PKCS11_MODULE", "")
+	if _, err := secrets.OpenKeeper(context.Background(), "pkcs11://mykey"); err == nil {
+		t.Error("got nil error, want error when PKCS11_MODULE is not set")
+	}
+}
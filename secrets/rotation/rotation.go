@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rotation provides a *secrets.Keeper that supports zero-downtime
+// key rotation. Use NewKeeper to construct one from a primary key and any
+// number of previous keys.
+//
+// Encrypt always uses the primary key, and prefixes the ciphertext with the
+// primary key's ID. Decrypt uses the key whose ID is in the ciphertext. To
+// rotate, add a new primary key and keep the old one as a previous key until
+// all data encrypted with it has been re-encrypted or has expired.
+//
+// Use Reencrypt to migrate existing ciphertexts to the primary key before
+// removing a previous key. To encrypt large payloads, wrap the Keeper with
+// gocloud.dev/secrets/envelope, whose ciphertexts also record the key ID.
+//
+// EncryptWithAAD and DecryptWithAAD pass the associated data to the key's
+// Keeper, and write and read the same key ID header as Encrypt and Decrypt.
+//
+// Ciphertexts without a key ID header, such as those written directly with
+// one of the Keepers before it was wrapped, are decrypted by trying each key
+// in turn, starting with the primary key.
+//
+// # Format
+//
+// Ciphertexts produced by Encrypt have the following layout:
+//   - a version byte, currently 1;
+//   - the length of the key ID, as a uvarint;
+//   - the key ID;
+//   - the ciphertext produced by the key's Keeper.
+//
+// # As
+//
+// rotation does not support any types for As; use the As functions of the
+// underlying Keepers.
+package rotation // import "gocloud.dev/secrets/rotation"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+// version is the format version written by Encrypt.
+const version = 1
+
+// Key is a Keeper identified by a stable ID.
+type Key struct {
+	// ID identifies the key in ciphertexts. It must be non-empty and unique
+	// among the keys passed to NewKeeper, and must not change for as long as
+	// data encrypted with the key exists.
+	ID string
+	// Keeper performs encryption and decryption with the key.
+	Keeper *secrets.Keeper
+}
+
+// NewKeeper returns a *secrets.Keeper that encrypts with primary and
+// decrypts with primary or any of previous. It returns an error if a key ID
+// is empty or duplicated.
+//
+// Closing the returned Keeper does not close the Keepers of the keys.
+func NewKeeper(primary Key, previous ...Key) (*secrets.Keeper, error) {
+	keys := append([]Key{primary}, previous...)
+	byID := make(map[string]*secrets.Keeper, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("rotation: key ID must not be empty")
+		}
+		if key.Keeper == nil {
+			return nil, fmt.Errorf("rotation: Keeper for key %q must not be nil", key.ID)
+		}
+		if _, ok := byID[key.ID]; ok {
+			return nil, fmt.Errorf("rotation: duplicate key ID %q", key.ID)
+		}
+		byID[key.ID] = key.Keeper
+	}
+	return secrets.NewKeeper(&keeper{keys: keys, byID: byID}), nil
+}
+
+// keeper implements driver.Keeper and driver.AADKeeper.
+type keeper struct {
+	keys []Key // keys[0] is the primary key
+	byID map[string]*secrets.Keeper
+}
+
+// Encrypt implements driver.Keeper.Encrypt.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.encrypt(func(kp *secrets.Keeper) ([]byte, error) {
+		return kp.Encrypt(ctx, plaintext)
+	})
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	return k.encrypt(func(kp *secrets.Keeper) ([]byte, error) {
+		return kp.EncryptWithAAD(ctx, plaintext, aad)
+	})
+}
+
+// encrypt encrypts with the primary key's Keeper using enc, and prefixes the
+// result with the key ID header.
+func (k *keeper) encrypt(enc func(*secrets.Keeper) ([]byte, error)) ([]byte, error) {
+	primary := k.keys[0]
+	ciphertext, err := enc(primary.Keeper)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 1+binary.MaxVarintLen64+len(primary.ID)+len(ciphertext))
+	out = append(out, version)
+	out = binary.AppendUvarint(out, uint64(len(primary.ID)))
+	out = append(out, primary.ID...)
+	return append(out, ciphertext...), nil
+}
+
+// Decrypt implements driver.Keeper.Decrypt.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ciphertext, func(kp *secrets.Keeper, ciphertext []byte) ([]byte, error) {
+		return kp.Decrypt(ctx, ciphertext)
+	})
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	return k.decrypt(ciphertext, func(kp *secrets.Keeper, ciphertext []byte) ([]byte, error) {
+		return kp.DecryptWithAAD(ctx, ciphertext, aad)
+	})
+}
+
+// decrypt decrypts ciphertext using dec with the key whose ID is in its
+// header or, failing that, with each key in turn.
+func (k *keeper) decrypt(ciphertext []byte, dec func(*secrets.Keeper, []byte) ([]byte, error)) ([]byte, error) {
+	if id, rest, ok := parse(ciphertext); ok {
+		if kp := k.byID[id]; kp != nil {
+			return dec(kp, rest)
+		}
+	}
+	// No header, or an unknown key ID: try each key on the whole ciphertext.
+	var firstErr error
+	for _, key := range k.keys {
+		plaintext, err := dec(key.Keeper, ciphertext)
+		if err == nil {
+			return plaintext, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, gcerr.Newf(gcerr.InvalidArgument, firstErr, "rotation: no key could decrypt the ciphertext")
+}
+
+// KeyID returns the ID of the key that encrypted ciphertext, as recorded in
+// its header. It returns false if ciphertext has no key ID header.
+func KeyID(ciphertext []byte) (string, bool) {
+	id, _, ok := parse(ciphertext)
+	return id, ok
+}
+
+// Reencrypt returns ciphertext encrypted with the key primaryID, for
+// migrating data after a rotation. k is a Keeper returned by NewKeeper with
+// that primary key. If the header of ciphertext shows that it is already
+// encrypted with primaryID, Reencrypt returns it as is, with false;
+// otherwise it decrypts it with k and encrypts it again, returning true.
+//
+// For ciphertexts of an envelope Keeper, use envelope.Reencrypt.
+func Reencrypt(ctx context.Context, k *secrets.Keeper, primaryID string, ciphertext []byte) ([]byte, bool, error) {
+	if id, ok := KeyID(ciphertext); ok && id == primaryID {
+		return ciphertext, false, nil
+	}
+	plaintext, err := k.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	ciphertext, err = k.Encrypt(ctx, plaintext)
+	if err != nil {
+		return nil, false, err
+	}
+	return ciphertext, true, nil
+}
+
+// parse splits ciphertext into the key ID from its header and the
+// ciphertext of the key's Keeper.
+func parse(ciphertext []byte) (id string, rest []byte, ok bool) {
+	if len(ciphertext) == 0 || ciphertext[0] != version {
+		return "", nil, false
+	}
+	n, w := binary.Uvarint(ciphertext[1:])
+	if w <= 0 || n == 0 || n > uint64(len(ciphertext)-1-w) {
+		return "", nil, false
+	}
+	end := 1 + w + int(n)
+	return string(ciphertext[1+w : end]), ciphertext[end:], true
+}
+
+// Close implements driver.Keeper.Close. It does not close the key Keepers.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return false
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(err error) gcerrors.ErrorCode {
+	return gcerrors.Code(err)
+}
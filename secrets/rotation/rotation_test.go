@@ -0,0 +1,246 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+func newLocalKeeper(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(sk)
+}
+
+type harness struct {
+	t *testing.T
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	old := Key{ID: "old", Keeper: newLocalKeeper(h.t)}
+	k1 := &keeper{keys: []Key{{ID: "k1", Keeper: newLocalKeeper(h.t)}, old}}
+	k2 := &keeper{keys: []Key{{ID: "k2", Keeper: newLocalKeeper(h.t)}, old}}
+	for _, k := range []*keeper{k1, k2} {
+		k.byID = map[string]*secrets.Keeper{}
+		for _, key := range k.keys {
+			k.byID[key.ID] = key.Keeper
+		}
+	}
+	return k1, k2, nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{t: t}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s string
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs expected to fail")
+	}
+	return nil
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	v1 := Key{ID: "v1", Keeper: newLocalKeeper(t)}
+	v2 := Key{ID: "v2", Keeper: newLocalKeeper(t)}
+
+	before, err := NewKeeper(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer before.Close()
+	after, err := NewKeeper(v2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer after.Close()
+
+	const plaintext = "hello world"
+	oldCiphertext, err := before.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := KeyID(oldCiphertext); !ok || id != "v1" {
+		t.Errorf("KeyID: got %q, %v want %q, true", id, ok, "v1")
+	}
+	newCiphertext, err := after.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := KeyID(newCiphertext); !ok || id != "v2" {
+		t.Errorf("KeyID: got %q, %v want %q, true", id, ok, "v2")
+	}
+	// Ciphertext written by v1 before it was wrapped has no header.
+	legacyCiphertext, err := v1.Keeper.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, ciphertext := range map[string][]byte{
+		"old":    oldCiphertext,
+		"new":    newCiphertext,
+		"legacy": legacyCiphertext,
+	} {
+		got, err := after.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if string(got) != plaintext {
+			t.Errorf("%s: got %q want %q", name, got, plaintext)
+		}
+	}
+
+	// The old Keeper doesn't know about v2.
+	if _, err := before.Decrypt(ctx, newCiphertext); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("got error %v, want InvalidArgument", err)
+	}
+}
+
+func TestRotationAAD(t *testing.T) {
+	ctx := context.Background()
+	v1 := Key{ID: "v1", Keeper: newLocalKeeper(t)}
+	v2 := Key{ID: "v2", Keeper: newLocalKeeper(t)}
+	k, err := NewKeeper(v2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k.Close()
+
+	const plaintext = "hello world"
+	aad := []byte("row 1")
+	ciphertext, err := k.EncryptWithAAD(ctx, []byte(plaintext), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, rest, ok := parse(ciphertext)
+	if !ok || id != "v2" {
+		t.Fatalf("KeyID: got %q, %v want %q, true", id, ok, "v2")
+	}
+	// The rest of the ciphertext is the primary Keeper's, bound to aad.
+	if got, err := v2.Keeper.DecryptWithAAD(ctx, rest, aad); err != nil || string(got) != plaintext {
+		t.Errorf("primary Keeper: got %q, %v want %q", got, err, plaintext)
+	}
+	// Ciphertext written by v1 before it was wrapped has no header.
+	legacyCiphertext, err := v1.Keeper.EncryptWithAAD(ctx, []byte(plaintext), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, ciphertext := range map[string][]byte{
+		"new":    ciphertext,
+		"legacy": legacyCiphertext,
+	} {
+		got, err := k.DecryptWithAAD(ctx, ciphertext, aad)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if string(got) != plaintext {
+			t.Errorf("%s: got %q want %q", name, got, plaintext)
+		}
+		if _, err := k.DecryptWithAAD(ctx, ciphertext, []byte("row 2")); err == nil {
+			t.Errorf("%s: with other associated data: got nil error, want error", name)
+		}
+		if _, err := k.Decrypt(ctx, ciphertext); err == nil {
+			t.Errorf("%s: without associated data: got nil error, want error", name)
+		}
+	}
+}
+
+func TestReencrypt(t *testing.T) {
+	ctx := context.Background()
+	v1 := Key{ID: "v1", Keeper: newLocalKeeper(t)}
+	v2 := Key{ID: "v2", Keeper: newLocalKeeper(t)}
+	before, err := NewKeeper(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer before.Close()
+	after, err := NewKeeper(v2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer after.Close()
+
+	const plaintext = "hello world"
+	oldCiphertext, err := before.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCiphertext, changed, err := Reencrypt(ctx, after, "v2", oldCiphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("got changed false for a v1 ciphertext, want true")
+	}
+	if id, ok := KeyID(newCiphertext); !ok || id != "v2" {
+		t.Errorf("KeyID: got %q, %v want %q, true", id, ok, "v2")
+	}
+	if got, err := v2.Keeper.Decrypt(ctx, newCiphertext[1+1+len("v2"):]); err != nil || string(got) != plaintext {
+		t.Errorf("got %q, %v from v2, want %q", got, err, plaintext)
+	}
+
+	// Ciphertexts encrypted with the primary key are returned as is.
+	got, changed, err := Reencrypt(ctx, after, "v2", newCiphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || string(got) != string(newCiphertext) {
+		t.Errorf("got changed %v for a v2 ciphertext, want false and the same ciphertext", changed)
+	}
+}
+
+func TestNewKeeperErrors(t *testing.T) {
+	k := newLocalKeeper(t)
+	for _, tc := range []struct {
+		name     string
+		primary  Key
+		previous []Key
+	}{
+		{"empty ID", Key{Keeper: k}, nil},
+		{"nil Keeper", Key{ID: "a"}, nil},
+		{"duplicate ID", Key{ID: "a", Keeper: k}, []Key{{ID: "a", Keeper: k}}},
+	} {
+		if _, err := NewKeeper(tc.primary, tc.previous...); err == nil {
+			t.Errorf("%s: got nil error, want error", tc.name)
+		}
+	}
+}
@@ -13,19 +13,52 @@
 // limitations under the License.
 
 // Package secrets provides an easy and portable way to encrypt and decrypt
-// messages. Subpackages contain driver implementations of
+// messages. Keepers backed by an asymmetric key can also sign and verify
+// message digests, and Keepers backed by a MAC key can compute and verify
+// message authentication codes. Subpackages contain driver implementations of
 // secrets for supported services.
 //
+// Keepers backed by an asymmetric encryption key can export their public key
+// with PublicKey. Producers can then encrypt with
+// localsecrets.NewPublicKeyKeeper without credentials for the key service;
+// only the Keeper holding the private key can decrypt.
+//
+// Payloads too large to hold in memory can be encrypted with EncryptStream
+// and decrypted with DecryptStream, which encrypt locally in chunks with a
+// data key protected by the Keeper.
+//
+// Keepers whose key supports authenticated encryption with associated data
+// can bind ciphertexts to their context with EncryptWithAAD and
+// DecryptWithAAD.
+//
+// To audit key usage, use SetAuditFunc to report every call to a Keeper,
+// and WithAuditLabels to attach caller-supplied labels to the calls.
+//
 // See https://gocloud.dev/howto/secrets/ for a detailed how-to guide.
 //
+// # OpenTelemetry Integration
+//
+// This API reports the method calls below as OpenTelemetry spans, and as the
+// metric "gocdk.secrets.duration", to the global tracer and meter providers.
+// See https://pkg.go.dev/gocloud.dev/telemetry for the span and metric
+// names, and for configuring exporters.
+//   - Encrypt
+//   - Decrypt
+//   - EncryptWithAAD
+//   - DecryptWithAAD
+//   - Sign
+//   - Verify
+//   - MAC
+//   - VerifyMAC
+//   - GenerateDataKey
+//   - PublicKey
+//
 // # OpenCensus Integration
 //
 // OpenCensus supports tracing and metric collection for multiple languages and
 // backend providers. See https://opencensus.io.
 //
-// This API collects OpenCensus traces and metrics for the following methods:
-//   - Encrypt
-//   - Decrypt
+// This API also collects OpenCensus traces and metrics for the same methods.
 //
 // All trace and metric names begin with the package import path.
 // The traces add the method name.
@@ -43,12 +76,18 @@ package secrets // import "gocloud.dev/secrets"
 
 import (
 	"context"
+	"crypto"
+	"crypto/rand"
 	"net/url"
 	"sync"
+	"time"
 
+	"gocloud.dev/callopt"
+	"gocloud.dev/gcerrors"
 	"gocloud.dev/internal/gcerr"
 	"gocloud.dev/internal/oc"
 	"gocloud.dev/internal/openurl"
+	"gocloud.dev/internal/telemetry"
 	"gocloud.dev/secrets/driver"
 )
 
@@ -56,7 +95,9 @@ import (
 // found in driver subpackages.
 type Keeper struct {
 	k      driver.Keeper
-	tracer *oc.Tracer
+	tracer *telemetry.Tracer
+	// auditFunc is set via SetAuditFunc.
+	auditFunc func(context.Context, *AuditEvent)
 
 	// mu protects the closed variable.
 	// Read locks are kept to allow holding a read lock for long-running calls,
@@ -71,12 +112,8 @@ var NewKeeper = newKeeper
 // newKeeper creates a Keeper.
 func newKeeper(k driver.Keeper) *Keeper {
 	return &Keeper{
-		k: k,
-		tracer: &oc.Tracer{
-			Package:        pkgName,
-			Provider:       oc.ProviderName(k),
-			LatencyMeasure: latencyMeasure,
-		},
+		k:      k,
+		tracer: telemetry.NewTracer(pkgName, telemetry.ProviderName(k), latencyMeasure, telemetry.DriverAttributes(k)...),
 	}
 }
 
@@ -93,8 +130,10 @@ var (
 
 // Encrypt encrypts the plaintext and returns the cipher message.
 func (k *Keeper) Encrypt(ctx context.Context, plaintext []byte) (ciphertext []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = k.tracer.Start(ctx, "Encrypt")
-	defer func() { k.tracer.End(ctx, err) }()
+	defer func(start time.Time) { k.end(ctx, "Encrypt", start, err) }(time.Now())
 
 	k.mu.RLock()
 	defer k.mu.RUnlock()
@@ -111,8 +150,10 @@ func (k *Keeper) Encrypt(ctx context.Context, plaintext []byte) (ciphertext []by
 
 // Decrypt decrypts the ciphertext and returns the plaintext.
 func (k *Keeper) Decrypt(ctx context.Context, ciphertext []byte) (plaintext []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
 	ctx = k.tracer.Start(ctx, "Decrypt")
-	defer func() { k.tracer.End(ctx, err) }()
+	defer func(start time.Time) { k.end(ctx, "Decrypt", start, err) }(time.Now())
 
 	k.mu.RLock()
 	defer k.mu.RUnlock()
@@ -127,7 +168,277 @@ func (k *Keeper) Decrypt(ctx context.Context, ciphertext []byte) (plaintext []by
 	return b, nil
 }
 
-var errClosed = gcerr.Newf(gcerr.FailedPrecondition, nil, "secrets: Keeper has been closed")
+// EncryptWithAAD encrypts the plaintext and binds the ciphertext to aad, the
+// associated data, which is authenticated but not encrypted or stored in the
+// ciphertext. Use it to tie a ciphertext to its context, such as a tenant
+// ID or the name of the object holding it, so that a ciphertext copied to
+// another context fails to decrypt.
+//
+// The ciphertext must be decrypted with DecryptWithAAD and the same aad;
+// it may not be decryptable with Decrypt even if aad is empty.
+//
+// If the driver does not support associated data, EncryptWithAAD returns an
+// error for which gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) (ciphertext []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "EncryptWithAAD")
+	defer func(start time.Time) { k.end(ctx, "EncryptWithAAD", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, errClosed
+	}
+
+	a, ok := k.k.(driver.AADKeeper)
+	if !ok {
+		return nil, errAADUnimplemented
+	}
+	b, err := a.EncryptWithAAD(ctx, plaintext, aad)
+	if err != nil {
+		return nil, wrapError(k, err)
+	}
+	return b, nil
+}
+
+// DecryptWithAAD decrypts a ciphertext produced by EncryptWithAAD with the
+// same aad, and returns the plaintext. It returns an error if aad does not
+// match.
+//
+// If the driver does not support associated data, DecryptWithAAD returns an
+// error for which gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) (plaintext []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "DecryptWithAAD")
+	defer func(start time.Time) { k.end(ctx, "DecryptWithAAD", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, errClosed
+	}
+
+	a, ok := k.k.(driver.AADKeeper)
+	if !ok {
+		return nil, errAADUnimplemented
+	}
+	b, err := a.DecryptWithAAD(ctx, ciphertext, aad)
+	if err != nil {
+		return nil, wrapError(k, err)
+	}
+	return b, nil
+}
+
+// Sign signs digest using the Keeper's key and returns the signature.
+//
+// digest must be the hash of the message being signed, computed with the hash
+// function that matches the key's signing algorithm (for example, SHA-256 for
+// an ECDSA P-256 key). Drivers that need to know the signing algorithm take
+// it from their KeeperOptions.SigningAlgorithm field or the
+// "signing_algorithm" URL parameter; see the driver subpackages for details.
+//
+// If the driver does not support signing, Sign returns an error for which
+// gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) Sign(ctx context.Context, digest []byte) (signature []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "Sign")
+	defer func(start time.Time) { k.end(ctx, "Sign", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, errClosed
+	}
+
+	s, ok := k.k.(driver.Signer)
+	if !ok {
+		return nil, errSignUnimplemented
+	}
+	b, err := s.Sign(ctx, digest)
+	if err != nil {
+		return nil, wrapError(k, err)
+	}
+	return b, nil
+}
+
+// Verify reports whether signature is a valid signature of digest for the
+// Keeper's key. digest must be computed the same way as for Sign.
+//
+// A signature that does not match results in false and a nil error; a non-nil
+// error means that the signature could not be checked.
+//
+// If the driver does not support signing, Verify returns an error for which
+// gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) Verify(ctx context.Context, digest, signature []byte) (ok bool, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "Verify")
+	defer func(start time.Time) { k.end(ctx, "Verify", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return false, errClosed
+	}
+
+	s, isSigner := k.k.(driver.Signer)
+	if !isSigner {
+		return false, errSignUnimplemented
+	}
+	ok, err = s.Verify(ctx, digest, signature)
+	if err != nil {
+		return false, wrapError(k, err)
+	}
+	return ok, nil
+}
+
+// MAC computes a message authentication code for data using the Keeper's
+// key, for example to sign outgoing requests or webhooks.
+//
+// Drivers that need to know the MAC algorithm take it from their
+// KeeperOptions.MACAlgorithm field or the "mac_algorithm" URL parameter;
+// see the driver subpackages for details.
+//
+// If the driver does not support MACs, MAC returns an error for which
+// gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) MAC(ctx context.Context, data []byte) (mac []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "MAC")
+	defer func(start time.Time) { k.end(ctx, "MAC", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, errClosed
+	}
+
+	m, ok := k.k.(driver.MACer)
+	if !ok {
+		return nil, errMACUnimplemented
+	}
+	b, err := m.MAC(ctx, data)
+	if err != nil {
+		return nil, wrapError(k, err)
+	}
+	return b, nil
+}
+
+// VerifyMAC reports whether mac is a valid message authentication code of
+// data for the Keeper's key. The comparison is done in constant time where
+// the driver computes the MAC locally.
+//
+// A MAC that does not match results in false and a nil error; a non-nil
+// error means that the MAC could not be checked.
+//
+// If the driver does not support MACs, VerifyMAC returns an error for which
+// gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) VerifyMAC(ctx context.Context, data, mac []byte) (ok bool, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "VerifyMAC")
+	defer func(start time.Time) { k.end(ctx, "VerifyMAC", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return false, errClosed
+	}
+
+	m, isMACer := k.k.(driver.MACer)
+	if !isMACer {
+		return false, errMACUnimplemented
+	}
+	ok, err = m.VerifyMAC(ctx, data, mac)
+	if err != nil {
+		return false, wrapError(k, err)
+	}
+	return ok, nil
+}
+
+// DataKeySize is the size in bytes of the data keys returned by
+// GenerateDataKey, suitable for AES-256.
+const DataKeySize = 32
+
+// GenerateDataKey returns a new random data key of DataKeySize bytes, both in
+// plaintext and encrypted with the Keeper's key, for use in envelope
+// encryption: payloads are encrypted locally with the plaintext data key,
+// which is then discarded, and the encrypted data key is stored alongside
+// them. See package gocloud.dev/secrets/envelope.
+//
+// If the driver's service can generate data keys (for example, AWS KMS
+// GenerateDataKey), it is used; otherwise the data key is generated locally
+// and encrypted with Encrypt. Either way, Decrypt recovers the plaintext
+// data key from the encrypted one.
+func (k *Keeper) GenerateDataKey(ctx context.Context) (plaintext, ciphertext []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "GenerateDataKey")
+	defer func(start time.Time) { k.end(ctx, "GenerateDataKey", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, nil, errClosed
+	}
+
+	if g, ok := k.k.(driver.DataKeyGenerator); ok {
+		plaintext, ciphertext, err = g.GenerateDataKey(ctx)
+		if err != nil {
+			return nil, nil, wrapError(k, err)
+		}
+		return plaintext, ciphertext, nil
+	}
+	plaintext = make([]byte, DataKeySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, nil, gcerr.Newf(gcerr.Internal, err, "secrets: failed to generate data key")
+	}
+	ciphertext, err = k.k.Encrypt(ctx, plaintext)
+	if err != nil {
+		return nil, nil, wrapError(k, err)
+	}
+	return plaintext, ciphertext, nil
+}
+
+// PublicKey returns the public key of an asymmetric Keeper, for example an
+// *rsa.PublicKey. It can be used to encrypt or verify without access to the
+// key service; see localsecrets.NewPublicKeyKeeper.
+//
+// If the driver does not support exporting a public key, PublicKey returns an
+// error for which gcerrors.Code returns gcerrors.Unimplemented.
+func (k *Keeper) PublicKey(ctx context.Context) (pub crypto.PublicKey, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = k.tracer.Start(ctx, "PublicKey")
+	defer func(start time.Time) { k.end(ctx, "PublicKey", start, err) }(time.Now())
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.closed {
+		return nil, errClosed
+	}
+
+	p, ok := k.k.(driver.PublicKeyer)
+	if !ok {
+		return nil, errPublicKeyUnimplemented
+	}
+	pub, err = p.PublicKey(ctx)
+	if err != nil {
+		return nil, wrapError(k, err)
+	}
+	return pub, nil
+}
+
+var (
+	errClosed                 = gcerr.Newf(gcerr.FailedPrecondition, nil, "secrets: Keeper has been closed")
+	errSignUnimplemented      = gcerr.Newf(gcerr.Unimplemented, nil, "secrets: Keeper does not support signing")
+	errMACUnimplemented       = gcerr.Newf(gcerr.Unimplemented, nil, "secrets: Keeper does not support MACs")
+	errAADUnimplemented       = gcerr.Newf(gcerr.Unimplemented, nil, "secrets: Keeper does not support associated data")
+	errPublicKeyUnimplemented = gcerr.Newf(gcerr.Unimplemented, nil, "secrets: Keeper does not support exporting a public key")
+)
 
 // Close releases any resources used for the Keeper.
 func (k *Keeper) Close() error {
@@ -159,7 +470,11 @@ func wrapError(k *Keeper, err error) error {
 	if gcerr.DoNotWrap(err) {
 		return err
 	}
-	return gcerr.New(k.k.ErrorCode(err), err, 2, "secrets")
+	code := gcerrors.Code(err)
+	if code == gcerrors.Unknown {
+		code = k.k.ErrorCode(err)
+	}
+	return gcerr.New(code, err, 2, "secrets")
 }
 
 // KeeperURLOpener represents types that can open Keepers based on a URL.
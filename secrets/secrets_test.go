@@ -16,6 +16,11 @@ package secrets
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"errors"
 	"net/url"
 	"strings"
@@ -87,9 +92,283 @@ func TestKeeperIsClosed(t *testing.T) {
 	if _, err := k.Encrypt(ctx, nil); err != errClosed {
 		t.Error(err)
 	}
+	if _, err := k.Sign(ctx, nil); err != errClosed {
+		t.Error(err)
+	}
+	if _, err := k.Verify(ctx, nil, nil); err != errClosed {
+		t.Error(err)
+	}
+	if _, err := k.MAC(ctx, nil); err != errClosed {
+		t.Error(err)
+	}
+	if _, err := k.VerifyMAC(ctx, nil, nil); err != errClosed {
+		t.Error(err)
+	}
+	if _, err := k.PublicKey(ctx); err != errClosed {
+		t.Error(err)
+	}
+	if _, _, err := k.GenerateDataKey(ctx); err != errClosed {
+		t.Error(err)
+	}
 	if err := k.Close(); err != errClosed {
 		t.Error(err)
 	}
+	if gcerrors.Code(errClosed) != gcerrors.FailedPrecondition {
+		t.Errorf("got code %v for closed Keeper, want FailedPrecondition", gcerrors.Code(errClosed))
+	}
+}
+
+// signingKeeper is a driver.Keeper that also implements driver.Signer using
+// an in-memory ECDSA key.
+type signingKeeper struct {
+	erroringKeeper
+	key *ecdsa.PrivateKey
+}
+
+func (k *signingKeeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	return ecdsa.SignASN1(rand.Reader, k.key, digest)
+}
+
+func (k *signingKeeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	return ecdsa.VerifyASN1(&k.key.PublicKey, digest, signature), nil
+}
+
+func TestSignUnimplemented(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&erroringKeeper{})
+	defer k.Close()
+
+	if _, err := k.Sign(ctx, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("Sign: got error %v, want Unimplemented", err)
+	}
+	if _, err := k.Verify(ctx, nil, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("Verify: got error %v, want Unimplemented", err)
+	}
+}
+
+func TestAADUnimplemented(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&erroringKeeper{})
+	defer k.Close()
+
+	if _, err := k.EncryptWithAAD(ctx, nil, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("EncryptWithAAD: got error %v, want Unimplemented", err)
+	}
+	if _, err := k.DecryptWithAAD(ctx, nil, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("DecryptWithAAD: got error %v, want Unimplemented", err)
+	}
+}
+
+func TestPublicKeyUnimplemented(t *testing.T) {
+	k := NewKeeper(&erroringKeeper{})
+	defer k.Close()
+
+	if _, err := k.PublicKey(context.Background()); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("PublicKey: got error %v, want Unimplemented", err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := NewKeeper(&signingKeeper{key: key})
+	defer k.Close()
+
+	digest := sha256.Sum256([]byte("hello world"))
+	sig, err := k.Sign(ctx, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := k.Verify(ctx, digest[:], sig); err != nil || !ok {
+		t.Errorf("Verify: got %v, %v want true, nil", ok, err)
+	}
+	other := sha256.Sum256([]byte("goodbye world"))
+	if ok, err := k.Verify(ctx, other[:], sig); err != nil || ok {
+		t.Errorf("Verify with mismatched digest: got %v, %v want false, nil", ok, err)
+	}
+}
+
+// macKeeper is a driver.Keeper that also implements driver.MACer using
+// HMAC-SHA256.
+type macKeeper struct {
+	erroringKeeper
+	key []byte
+}
+
+func (k *macKeeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	h := hmac.New(sha256.New, k.key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+func (k *macKeeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	want, _ := k.MAC(ctx, data)
+	return hmac.Equal(mac, want), nil
+}
+
+func TestMACUnimplemented(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&erroringKeeper{})
+	defer k.Close()
+
+	if _, err := k.MAC(ctx, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("MAC: got error %v, want Unimplemented", err)
+	}
+	if _, err := k.VerifyMAC(ctx, nil, nil); gcerrors.Code(err) != gcerrors.Unimplemented {
+		t.Errorf("VerifyMAC: got error %v, want Unimplemented", err)
+	}
+}
+
+func TestMACVerifyMAC(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&macKeeper{key: []byte("secret")})
+	defer k.Close()
+
+	data := []byte("hello world")
+	mac, err := k.MAC(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := k.VerifyMAC(ctx, data, mac); err != nil || !ok {
+		t.Errorf("VerifyMAC: got %v, %v want true, nil", ok, err)
+	}
+	if ok, err := k.VerifyMAC(ctx, []byte("goodbye world"), mac); err != nil || ok {
+		t.Errorf("VerifyMAC with mismatched data: got %v, %v want false, nil", ok, err)
+	}
+}
+
+// reverseKeeper is a driver.Keeper whose "encryption" reverses its input.
+type reverseKeeper struct {
+	erroringKeeper
+}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func (k *reverseKeeper) Encrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return reverse(b), nil
+}
+
+func (k *reverseKeeper) Decrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return reverse(b), nil
+}
+
+// dataKeyKeeper is a reverseKeeper that also implements
+// driver.DataKeyGenerator.
+type dataKeyKeeper struct {
+	reverseKeeper
+	calls int
+}
+
+func (k *dataKeyKeeper) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	k.calls++
+	key := []byte("0123456789abcdef0123456789abcdef")
+	return key, reverse(key), nil
+}
+
+func TestGenerateDataKey(t *testing.T) {
+	ctx := context.Background()
+	dk := &dataKeyKeeper{}
+	for _, tc := range []struct {
+		name string
+		drv  driver.Keeper
+	}{
+		{"local generation", &reverseKeeper{}},
+		{"driver generation", dk},
+	} {
+		k := NewKeeper(tc.drv)
+		plaintext, ciphertext, err := k.GenerateDataKey(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(plaintext) != DataKeySize {
+			t.Errorf("%s: got data key of %d bytes, want %d", tc.name, len(plaintext), DataKeySize)
+		}
+		got, err := k.Decrypt(ctx, ciphertext)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !cmp.Equal(got, plaintext) {
+			t.Errorf("%s: decrypted data key %x, want %x", tc.name, got, plaintext)
+		}
+		k.Close()
+	}
+	if dk.calls != 1 {
+		t.Errorf("driver GenerateDataKey called %d times, want 1", dk.calls)
+	}
+}
+
+// codedKeeper returns errors that already carry an error code.
+type codedKeeper struct {
+	erroringKeeper
+}
+
+var errCoded = gcerr.Newf(gcerr.InvalidArgument, nil, "coded")
+
+func (k *codedKeeper) Decrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return nil, errCoded
+}
+
+func (k *codedKeeper) Encrypt(ctx context.Context, b []byte) ([]byte, error) {
+	return nil, errCoded
+}
+
+func (k *codedKeeper) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	return nil, errCoded
+}
+
+func (k *codedKeeper) Verify(ctx context.Context, digest, signature []byte) (bool, error) {
+	return false, errCoded
+}
+
+func (k *codedKeeper) MAC(ctx context.Context, data []byte) ([]byte, error) {
+	return nil, errCoded
+}
+
+func (k *codedKeeper) VerifyMAC(ctx context.Context, data, mac []byte) (bool, error) {
+	return false, errCoded
+}
+
+// TestErrorCodeIsKept tests that the code of a driver error that already has
+// one is kept, rather than replaced by the driver's ErrorCode.
+func TestErrorCodeIsKept(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&codedKeeper{})
+	defer k.Close()
+
+	check := func(method string, err error) {
+		t.Helper()
+		if got := gcerrors.Code(err); got != gcerrors.InvalidArgument {
+			t.Errorf("%s: got code %v, want InvalidArgument", method, got)
+		}
+	}
+	_, err := k.Encrypt(ctx, nil)
+	check("Encrypt", err)
+	_, err = k.Decrypt(ctx, nil)
+	check("Decrypt", err)
+	_, err = k.Sign(ctx, nil)
+	check("Sign", err)
+	_, err = k.Verify(ctx, nil, nil)
+	check("Verify", err)
+	_, err = k.MAC(ctx, nil)
+	check("MAC", err)
+	_, err = k.VerifyMAC(ctx, nil, nil)
+	check("VerifyMAC", err)
+
+	// Errors without a code still use the driver's ErrorCode.
+	k2 := NewKeeper(&erroringKeeper{})
+	defer k2.Close()
+	if _, err := k2.Encrypt(ctx, nil); gcerrors.Code(err) != gcerrors.Internal {
+		t.Errorf("Encrypt: got code %v, want Internal", gcerrors.Code(err))
+	}
 }
 
 func TestOpenCensus(t *testing.T) {
@@ -101,9 +380,11 @@ func TestOpenCensus(t *testing.T) {
 	defer k.Close()
 	k.Encrypt(ctx, nil)
 	k.Decrypt(ctx, nil)
+	k.PublicKey(ctx)
 	diff := octest.Diff(te.Spans(), te.Counts(), "gocloud.dev/secrets", "gocloud.dev/secrets", []octest.Call{
 		{Method: "Encrypt", Code: gcerrors.Internal},
 		{Method: "Decrypt", Code: gcerrors.Internal},
+		{Method: "PublicKey", Code: gcerrors.Unimplemented},
 	})
 	if diff != "" {
 		t.Error(diff)
@@ -0,0 +1,338 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awssecretsmanager provides a secretstore implementation backed by
+// AWS Secrets Manager (https://aws.amazon.com/secrets-manager), using AWS
+// SDK V2. Use OpenStore to construct a *secretstore.Store.
+//
+// Secret names are Secrets Manager secret names; ARNs are also accepted by
+// Get, Set and Delete. Set stores values that are valid UTF-8 as
+// SecretString, and other values as SecretBinary. Get returns whichever of
+// the two is set.
+//
+// By default, Delete schedules the secret for deletion after the Secrets
+// Manager default recovery window. While a secret is scheduled for deletion,
+// Get and Set fail with code FailedPrecondition; see Options to change the
+// recovery window or to delete immediately.
+//
+// # URLs
+//
+// For secretstore.OpenStore, awssecretsmanager registers for the scheme
+// "awssecretsmanager". The default URL opener will use the default AWS
+// credentials and configuration.
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// awssecretsmanager exposes the following types for As:
+//   - Store: *secretsmanager.Client
+//   - ListSecret: types.SecretListEntry
+//   - Error: any error type returned by the service, notably smithy.APIError
+package awssecretsmanager // import "gocloud.dev/secrets/secretstore/awssecretsmanager"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/google/wire"
+	gcaws "gocloud.dev/aws"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+// maxPageSize is the largest MaxResults accepted by ListSecrets.
+const maxPageSize = 100
+
+func init() {
+	secretstore.DefaultURLMux().RegisterStore(Scheme, new(URLOpener))
+}
+
+// Set holds Wire providers for this package.
+var Set = wire.NewSet(
+	Dial,
+	wire.Struct(new(URLOpener), "Options"),
+)
+
+// Dial gets an AWS Secrets Manager client using AWS SDK V2.
+func Dial(cfg aws.Config) *secretsmanager.Client {
+	return secretsmanager.NewFromConfig(cfg)
+}
+
+// Scheme is the URL scheme awssecretsmanager registers its URLOpener under on
+// secretstore.DefaultMux.
+const Scheme = "awssecretsmanager"
+
+// URLOpener opens AWS Secrets Manager URLs like "awssecretsmanager://".
+// The URL host and path must be empty.
+//
+// See gocloud.dev/aws/V2ConfigFromURLParams for supported query parameters
+// for overriding the aws.Config from the URL.
+//
+// In addition, the following URL parameters are supported:
+//   - recovery_window_days: Sets Options.RecoveryWindowInDays.
+//   - force_delete: Sets Options.ForceDeleteWithoutRecovery; must be a value
+//     accepted by strconv.ParseBool.
+type URLOpener struct {
+	// Options specifies the options to pass to OpenStore.
+	Options Options
+}
+
+// OpenStoreURL opens a Store based on u.
+func (o *URLOpener) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	if u.Host != "" || (u.Path != "" && u.Path != "/") {
+		return nil, fmt.Errorf("open store %v: URL host and path must be empty", u)
+	}
+	q := u.Query()
+	opts := o.Options
+	if s := q.Get("recovery_window_days"); s != "" {
+		q.Del("recovery_window_days")
+		d, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("open store %v: invalid recovery_window_days %q: %v", u, s, err)
+		}
+		opts.RecoveryWindowInDays = d
+	}
+	if s := q.Get("force_delete"); s != "" {
+		q.Del("force_delete")
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("open store %v: invalid force_delete %q: %v", u, s, err)
+		}
+		opts.ForceDeleteWithoutRecovery = b
+	}
+	cfg, err := gcaws.V2ConfigFromURLParams(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("open store %v: %v", u, err)
+	}
+	return OpenStore(Dial(cfg), &opts), nil
+}
+
+// Options controls Store behaviors.
+type Options struct {
+	// RecoveryWindowInDays is the number of days that Secrets Manager waits
+	// before it permanently deletes a secret removed with Delete.
+	// Secrets Manager accepts values from 7 to 30.
+	// 0 uses the Secrets Manager default of 30 days.
+	RecoveryWindowInDays int64
+
+	// ForceDeleteWithoutRecovery makes Delete remove secrets immediately,
+	// without a recovery window. It cannot be combined with
+	// RecoveryWindowInDays.
+	ForceDeleteWithoutRecovery bool
+}
+
+// api is the subset of *secretsmanager.Client used by store.
+type api interface {
+	GetSecretValue(context.Context, *secretsmanager.GetSecretValueInput, ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	PutSecretValue(context.Context, *secretsmanager.PutSecretValueInput, ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	CreateSecret(context.Context, *secretsmanager.CreateSecretInput, ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	DeleteSecret(context.Context, *secretsmanager.DeleteSecretInput, ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	ListSecrets(context.Context, *secretsmanager.ListSecretsInput, ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+}
+
+// OpenStore returns a *secretstore.Store that reads and writes secrets in
+// AWS Secrets Manager using client.
+func OpenStore(client *secretsmanager.Client, opts *Options) *secretstore.Store {
+	return secretstore.NewStore(openStore(client, opts))
+}
+
+func openStore(client api, opts *Options) *store {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &store{client: client, opts: *opts}
+}
+
+type store struct {
+	client api
+	opts   Options
+}
+
+// Get implements driver.Store.Get.
+func (s *store) Get(ctx context.Context, name string) ([]byte, error) {
+	out, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.SecretBinary != nil {
+		return out.SecretBinary, nil
+	}
+	return []byte(aws.ToString(out.SecretString)), nil
+}
+
+// Set implements driver.Store.Set.
+func (s *store) Set(ctx context.Context, name string, value []byte) error {
+	var str *string
+	var bin []byte
+	if utf8.Valid(value) {
+		str = aws.String(string(value))
+	} else {
+		bin = value
+	}
+	put := func() error {
+		_, err := s.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:     aws.String(name),
+			SecretString: str,
+			SecretBinary: bin,
+		})
+		return err
+	}
+	err := put()
+	var nf *types.ResourceNotFoundException
+	if !errors.As(err, &nf) {
+		return err
+	}
+	_, err = s.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: str,
+		SecretBinary: bin,
+	})
+	var exists *types.ResourceExistsException
+	if errors.As(err, &exists) {
+		// Another writer created the secret first; add our value as a new
+		// version.
+		return put()
+	}
+	return err
+}
+
+// Delete implements driver.Store.Delete.
+func (s *store) Delete(ctx context.Context, name string) error {
+	in := &secretsmanager.DeleteSecretInput{SecretId: aws.String(name)}
+	if s.opts.ForceDeleteWithoutRecovery {
+		in.ForceDeleteWithoutRecovery = aws.Bool(true)
+	} else if s.opts.RecoveryWindowInDays != 0 {
+		in.RecoveryWindowInDays = aws.Int64(s.opts.RecoveryWindowInDays)
+	}
+	_, err := s.client.DeleteSecret(ctx, in)
+	return err
+}
+
+// ListPaged implements driver.Store.ListPaged.
+func (s *store) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	in := &secretsmanager.ListSecretsInput{}
+	if opts.PageSize > 0 {
+		size := opts.PageSize
+		if size > maxPageSize {
+			size = maxPageSize
+		}
+		in.MaxResults = aws.Int32(int32(size))
+	}
+	if len(opts.PageToken) > 0 {
+		in.NextToken = aws.String(string(opts.PageToken))
+	}
+	if opts.Prefix != "" {
+		// The name filter is a case-insensitive prefix match; results are
+		// filtered again below for an exact match.
+		in.Filters = []types.Filter{{
+			Key:    types.FilterNameStringTypeName,
+			Values: []string{opts.Prefix},
+		}}
+	}
+	out, err := s.client.ListSecrets(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	page := &driver.ListPage{}
+	for _, entry := range out.SecretList {
+		entry := entry
+		name := aws.ToString(entry.Name)
+		if !strings.HasPrefix(name, opts.Prefix) {
+			continue
+		}
+		page.Secrets = append(page.Secrets, &driver.ListSecret{
+			Name: name,
+			AsFunc: func(i interface{}) bool {
+				p, ok := i.(*types.SecretListEntry)
+				if !ok {
+					return false
+				}
+				*p = entry
+				return true
+			},
+		})
+	}
+	if out.NextToken != nil {
+		page.NextPageToken = []byte(*out.NextToken)
+	}
+	return page, nil
+}
+
+// As implements driver.Store.As.
+func (s *store) As(i interface{}) bool {
+	p, ok := i.(**secretsmanager.Client)
+	if !ok {
+		return false
+	}
+	c, ok := s.client.(*secretsmanager.Client)
+	if !ok {
+		return false
+	}
+	*p = c
+	return true
+}
+
+// ErrorAs implements driver.Store.ErrorAs.
+func (s *store) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Store.ErrorCode.
+func (s *store) ErrorCode(err error) gcerrors.ErrorCode {
+	var (
+		notFound     *types.ResourceNotFoundException
+		exists       *types.ResourceExistsException
+		invalidReq   *types.InvalidRequestException
+		invalidParam *types.InvalidParameterException
+		invalidToken *types.InvalidNextTokenException
+		limit        *types.LimitExceededException
+		precondition *types.PreconditionNotMetException
+		internal     *types.InternalServiceError
+		decryption   *types.DecryptionFailure
+		encryption   *types.EncryptionFailure
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return gcerrors.NotFound
+	case errors.As(err, &exists):
+		return gcerrors.AlreadyExists
+	case errors.As(err, &invalidReq), errors.As(err, &precondition):
+		// InvalidRequestException is returned for operations on secrets
+		// that are scheduled for deletion, among others.
+		return gcerrors.FailedPrecondition
+	case errors.As(err, &invalidParam), errors.As(err, &invalidToken):
+		return gcerrors.InvalidArgument
+	case errors.As(err, &limit):
+		return gcerrors.ResourceExhausted
+	case errors.As(err, &internal), errors.As(err, &decryption), errors.As(err, &encryption):
+		return gcerrors.Internal
+	}
+	return gcerrors.Unknown
+}
+
+// Close implements driver.Store.Close.
+func (s *store) Close() error { return nil }
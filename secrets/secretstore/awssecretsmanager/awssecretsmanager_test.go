@@ -0,0 +1,239 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awssecretsmanager
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+	"gocloud.dev/secrets/secretstore/drivertest"
+)
+
+// fakeAPI is an in-memory implementation of api. Deleted secrets are
+// removed immediately, as with ForceDeleteWithoutRecovery.
+type fakeAPI struct {
+	mu      sync.Mutex
+	secrets map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func (f *fakeAPI) GetSecretValue(_ context.Context, in *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out, ok := f.secrets[aws.ToString(in.SecretId)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	return out, nil
+}
+
+func (f *fakeAPI) PutSecretValue(_ context.Context, in *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.ToString(in.SecretId)
+	if _, ok := f.secrets[name]; !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	f.secrets[name] = &secretsmanager.GetSecretValueOutput{Name: in.SecretId, SecretString: in.SecretString, SecretBinary: in.SecretBinary}
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (f *fakeAPI) CreateSecret(_ context.Context, in *secretsmanager.CreateSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.ToString(in.Name)
+	if _, ok := f.secrets[name]; ok {
+		return nil, &types.ResourceExistsException{Message: aws.String("exists")}
+	}
+	f.secrets[name] = &secretsmanager.GetSecretValueOutput{Name: in.Name, SecretString: in.SecretString, SecretBinary: in.SecretBinary}
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func (f *fakeAPI) DeleteSecret(_ context.Context, in *secretsmanager.DeleteSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.ToString(in.SecretId)
+	if _, ok := f.secrets[name]; !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	delete(f.secrets, name)
+	return &secretsmanager.DeleteSecretOutput{}, nil
+}
+
+func (f *fakeAPI) ListSecrets(_ context.Context, in *secretsmanager.ListSecretsInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for name := range f.secrets {
+		// Like the service, the name filter ignores case.
+		if len(in.Filters) > 0 && !strings.HasPrefix(strings.ToLower(name), strings.ToLower(in.Filters[0].Values[0])) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	start := 0
+	if in.NextToken != nil {
+		var err error
+		if start, err = strconv.Atoi(*in.NextToken); err != nil {
+			return nil, &types.InvalidNextTokenException{Message: aws.String("bad token")}
+		}
+	}
+	end := len(names)
+	if in.MaxResults != nil && start+int(*in.MaxResults) < end {
+		end = start + int(*in.MaxResults)
+	}
+	out := &secretsmanager.ListSecretsOutput{}
+	for _, name := range names[start:end] {
+		out.SecretList = append(out.SecretList, types.SecretListEntry{Name: aws.String(name)})
+	}
+	if end < len(names) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+type harness struct {
+	api *fakeAPI
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Store, error) {
+	return openStore(h.api, &Options{ForceDeleteWithoutRecovery: true}), nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{api: &fakeAPI{secrets: map[string]*secretsmanager.GetSecretValueOutput{}}}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (verifyAs) StoreCheck(s *secretstore.Store) error {
+	// The harness uses a fake client, so As for the client fails.
+	var c *secretsmanager.Client
+	if s.As(&c) {
+		return errors.New("Store.As succeeded for a fake client")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(s *secretstore.Store, err error) error {
+	var e smithy.APIError
+	if !s.ErrorAs(err, &e) {
+		return errors.New("Store.ErrorAs failed")
+	}
+	return nil
+}
+
+// AWS-specific tests.
+
+func TestSetBinary(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeAPI{secrets: map[string]*secretsmanager.GetSecretValueOutput{}}
+	s := secretstore.NewStore(openStore(f, nil))
+	defer s.Close()
+
+	for _, value := range [][]byte{[]byte("text"), {0xff, 0xfe, 0x00}} {
+		if err := s.Set(ctx, "name", value); err != nil {
+			t.Fatal(err)
+		}
+		raw := f.secrets["name"]
+		if gotString := raw.SecretString != nil; gotString != (value[0] != 0xff) {
+			t.Errorf("%q: got SecretString %v, SecretBinary %v", value, raw.SecretString, raw.SecretBinary)
+		}
+		got, err := s.Get(ctx, "name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(value) {
+			t.Errorf("got %q want %q", got, value)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	s := &store{}
+	for _, tc := range []struct {
+		err  error
+		want gcerrors.ErrorCode
+	}{
+		{&types.ResourceNotFoundException{}, gcerrors.NotFound},
+		{&types.ResourceExistsException{}, gcerrors.AlreadyExists},
+		{&types.InvalidRequestException{}, gcerrors.FailedPrecondition},
+		{&types.InvalidParameterException{}, gcerrors.InvalidArgument},
+		{&types.LimitExceededException{}, gcerrors.ResourceExhausted},
+		{&types.InternalServiceError{}, gcerrors.Internal},
+		{errors.New("other"), gcerrors.Unknown},
+	} {
+		if got := s.ErrorCode(tc.err); got != tc.want {
+			t.Errorf("%T: got %v want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"awssecretsmanager://", false},
+		// OK, setting region.
+		{"awssecretsmanager://?region=us-west-1", false},
+		// OK, setting delete options.
+		{"awssecretsmanager://?recovery_window_days=7", false},
+		{"awssecretsmanager://?force_delete=true", false},
+		// Invalid delete options.
+		{"awssecretsmanager://?recovery_window_days=week", true},
+		{"awssecretsmanager://?force_delete=maybe", true},
+		// Host is not allowed.
+		{"awssecretsmanager://mysecret", true},
+		// Invalid parameter.
+		{"awssecretsmanager://?param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		s, err := secretstore.OpenStore(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = s.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azurekeyvault provides a secretstore implementation backed by
+// Azure Key Vault secrets.
+// See https://docs.microsoft.com/en-us/azure/key-vault/key-vault-whatis for more information.
+// Use OpenStore to construct a *secretstore.Store.
+//
+// Key Vault secret names may only contain letters, digits and dashes.
+// Key Vault stores secret values as strings; Set stores values that are not
+// valid UTF-8 base64-encoded, marked with a content type, and Get decodes
+// them again.
+//
+// Delete soft-deletes secrets when the vault has soft-delete enabled, and a
+// soft-deleted name cannot be reused by Set until it is purged. Set
+// StoreOptions.PurgeOnDelete to purge secrets as part of Delete.
+//
+// Key Vault does not support resuming a listing, so each page returned by
+// List re-reads the listing from the beginning. Prefer the default page size,
+// which returns all secrets in one page.
+//
+// # URLs
+//
+// For secretstore.OpenStore, azurekeyvault registers for the scheme
+// "azurekeyvault".
+// The default URL opener will use azidentity.DefaultAzureCredential to get
+// credentials.
+//
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// azurekeyvault exposes the following types for As:
+//   - Store: *azsecrets.Client
+//   - ListSecret: *azsecrets.SecretItem
+//   - Error: *azcore.ResponseError
+package azurekeyvault // import "gocloud.dev/secrets/secretstore/azurekeyvault"
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/google/wire"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/useragent"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+// binaryContentType marks secrets whose value is base64-encoded binary data.
+const binaryContentType = "application/octet-stream; encoding=base64"
+
+// purgePollInterval is how often Delete retries purging a secret whose
+// deletion is still in progress.
+const purgePollInterval = time.Second
+
+// Map of HTTP Status Code to go-cloud ErrorCode
+var errorCodeMap = map[int]gcerrors.ErrorCode{
+	200: gcerrors.OK,
+	400: gcerrors.InvalidArgument,
+	401: gcerrors.PermissionDenied,
+	403: gcerrors.PermissionDenied,
+	404: gcerrors.NotFound,
+	408: gcerrors.DeadlineExceeded,
+	409: gcerrors.FailedPrecondition,
+	429: gcerrors.ResourceExhausted,
+	500: gcerrors.Internal,
+	501: gcerrors.Unimplemented,
+}
+
+func init() {
+	secretstore.DefaultURLMux().RegisterStore(Scheme, new(defaultDialer))
+}
+
+// Set holds Wire providers for this package.
+var Set = wire.NewSet(
+	wire.Struct(new(URLOpener), "ClientMaker"),
+)
+
+// ClientMakerT is the type of a function used to generate a Client.
+type ClientMakerT func(vaultURL string) (*azsecrets.Client, error)
+
+// defaultDialer dials Azure Key Vault using DefaultClientMaker.
+type defaultDialer struct{}
+
+func (o *defaultDialer) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	opener := &URLOpener{ClientMaker: DefaultClientMaker}
+	return opener.OpenStoreURL(ctx, u)
+}
+
+// Scheme is the URL scheme azurekeyvault registers its URLOpener under on
+// secretstore.DefaultMux.
+const Scheme = "azurekeyvault"
+
+// URLOpener opens Azure Key Vault URLs like
+// "azurekeyvault://{keyvault-name}.vault.azure.net".
+//
+// The "azurekeyvault" URL scheme is replaced with "https" to construct the
+// vault URL. The URL path must be empty.
+//
+// The following query parameters are supported:
+//   - purge_on_delete: Sets StoreOptions.PurgeOnDelete; must be a value
+//     accepted by strconv.ParseBool.
+type URLOpener struct {
+	// ClientMaker defaults to DefaultClientMaker.
+	ClientMaker ClientMakerT
+
+	// Options specifies the options to pass to OpenStore.
+	Options StoreOptions
+}
+
+// OpenStoreURL opens an Azure Key Vault Store based on u.
+func (o *URLOpener) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	q := u.Query()
+	opts := o.Options
+	if s := q.Get("purge_on_delete"); s != "" {
+		q.Del("purge_on_delete")
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("open store %v: invalid purge_on_delete %q: %v", u, s, err)
+		}
+		opts.PurgeOnDelete = b
+	}
+	for param := range q {
+		return nil, fmt.Errorf("open store %v: invalid query parameter %q", u, param)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("open store %v: URL path must be empty", u)
+	}
+	clientMaker := o.ClientMaker
+	if clientMaker == nil {
+		clientMaker = DefaultClientMaker
+	}
+	return OpenStore(clientMaker, "https://"+u.Host+"/", &opts)
+}
+
+// StoreOptions provides configuration options for a Store.
+type StoreOptions struct {
+	// PurgeOnDelete makes Delete permanently purge secrets after deleting
+	// them, so that their names can be reused immediately. It requires the
+	// purge permission, and fails for vaults with purge protection.
+	PurgeOnDelete bool
+}
+
+// DefaultClientMaker constructs a Key Vault secrets Client.
+// It uses credentials from azidentity.NewDefaultAzureCredential.
+func DefaultClientMaker(vaultURL string) (*azsecrets.Client, error) {
+	creds, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azsecrets.NewClient(vaultURL, creds, &azsecrets.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Telemetry: policy.TelemetryOptions{
+				ApplicationID: useragent.AzureUserAgentPrefix("secretstore"),
+			},
+		},
+	})
+}
+
+var vaultURLRE = regexp.MustCompile(`^https://.+\.vault\.(?:[a-z\d-.]+)/$`)
+
+// OpenStore returns a *secretstore.Store that reads and writes the secrets
+// of an Azure Key Vault.
+//
+// clientMaker is used to construct an azsecrets.Client.
+//
+// vaultURL is the vault URL like "https://{keyvault-name}.vault.azure.net/".
+func OpenStore(clientMaker ClientMakerT, vaultURL string, opts *StoreOptions) (*secretstore.Store, error) {
+	if !vaultURLRE.MatchString(vaultURL) {
+		return nil, fmt.Errorf("invalid vaultURL %q; must match %v", vaultURL, vaultURLRE)
+	}
+	client, err := clientMaker(vaultURL)
+	if err != nil {
+		return nil, err
+	}
+	return secretstore.NewStore(openStore(client, opts)), nil
+}
+
+// api is the subset of *azsecrets.Client used by store.
+type api interface {
+	GetSecret(ctx context.Context, name string, version string, options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+	SetSecret(ctx context.Context, name string, parameters azsecrets.SetSecretParameters, options *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error)
+	DeleteSecret(ctx context.Context, name string, options *azsecrets.DeleteSecretOptions) (azsecrets.DeleteSecretResponse, error)
+	PurgeDeletedSecret(ctx context.Context, name string, options *azsecrets.PurgeDeletedSecretOptions) (azsecrets.PurgeDeletedSecretResponse, error)
+	NewListSecretsPager(options *azsecrets.ListSecretsOptions) *runtime.Pager[azsecrets.ListSecretsResponse]
+}
+
+func openStore(client api, opts *StoreOptions) *store {
+	if opts == nil {
+		opts = &StoreOptions{}
+	}
+	return &store{client: client, opts: *opts}
+}
+
+type store struct {
+	client api
+	opts   StoreOptions
+}
+
+// Get implements driver.Store.Get.
+func (s *store) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.client.GetSecret(ctx, name, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	var value string
+	if resp.Value != nil {
+		value = *resp.Value
+	}
+	if resp.ContentType != nil && *resp.ContentType == binaryContentType {
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, gcerr.Newf(gcerr.Internal, err, "azurekeyvault: secret %q has content type %q but is not valid base64", name, binaryContentType)
+		}
+		return b, nil
+	}
+	return []byte(value), nil
+}
+
+// Set implements driver.Store.Set.
+func (s *store) Set(ctx context.Context, name string, value []byte) error {
+	params := azsecrets.SetSecretParameters{}
+	if utf8.Valid(value) {
+		v := string(value)
+		params.Value = &v
+	} else {
+		v := base64.StdEncoding.EncodeToString(value)
+		ct := binaryContentType
+		params.Value, params.ContentType = &v, &ct
+	}
+	_, err := s.client.SetSecret(ctx, name, params, nil)
+	return err
+}
+
+// Delete implements driver.Store.Delete.
+func (s *store) Delete(ctx context.Context, name string) error {
+	if _, err := s.client.DeleteSecret(ctx, name, nil); err != nil {
+		return err
+	}
+	if !s.opts.PurgeOnDelete {
+		return nil
+	}
+	// Deletion completes asynchronously; until it does, purging fails with
+	// 404 or 409.
+	for {
+		_, err := s.client.PurgeDeletedSecret(ctx, name, nil)
+		var re *azcore.ResponseError
+		if !errors.As(err, &re) || (re.StatusCode != http.StatusNotFound && re.StatusCode != http.StatusConflict) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(purgePollInterval):
+		}
+	}
+}
+
+// ListPaged implements driver.Store.ListPaged. The page token is the number
+// of matching secrets returned by previous pages.
+func (s *store) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	var skip int
+	if len(opts.PageToken) > 0 {
+		var err error
+		if skip, err = strconv.Atoi(string(opts.PageToken)); err != nil || skip < 0 {
+			return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "azurekeyvault: invalid page token %q", opts.PageToken)
+		}
+	}
+	page := &driver.ListPage{}
+	seen := 0
+	pager := s.client.NewListSecretsPager(nil)
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Value {
+			item := item
+			if item.ID == nil {
+				continue
+			}
+			name := item.ID.Name()
+			if !strings.HasPrefix(name, opts.Prefix) {
+				continue
+			}
+			seen++
+			if seen <= skip {
+				continue
+			}
+			if opts.PageSize > 0 && len(page.Secrets) == opts.PageSize {
+				// There is at least one more secret.
+				page.NextPageToken = []byte(strconv.Itoa(skip + opts.PageSize))
+				return page, nil
+			}
+			page.Secrets = append(page.Secrets, &driver.ListSecret{
+				Name: name,
+				AsFunc: func(i interface{}) bool {
+					p, ok := i.(**azsecrets.SecretItem)
+					if !ok {
+						return false
+					}
+					*p = item
+					return true
+				},
+			})
+		}
+	}
+	return page, nil
+}
+
+// As implements driver.Store.As.
+func (s *store) As(i interface{}) bool {
+	p, ok := i.(**azsecrets.Client)
+	if !ok {
+		return false
+	}
+	c, ok := s.client.(*azsecrets.Client)
+	if !ok {
+		return false
+	}
+	*p = c
+	return true
+}
+
+// ErrorAs implements driver.Store.ErrorAs.
+func (s *store) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Store.ErrorCode.
+func (s *store) ErrorCode(err error) gcerrors.ErrorCode {
+	var re *azcore.ResponseError
+	if !errors.As(err, &re) {
+		return gcerrors.Unknown
+	}
+	ec, ok := errorCodeMap[re.StatusCode]
+	if !ok {
+		return gcerrors.Unknown
+	}
+	return ec
+}
+
+// Close implements driver.Store.Close.
+func (s *store) Close() error { return nil }
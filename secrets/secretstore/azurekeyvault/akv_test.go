@@ -0,0 +1,274 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurekeyvault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+	"gocloud.dev/secrets/secretstore/drivertest"
+)
+
+const vaultURL = "https://myvault.vault.azure.net/"
+
+// fakeAPI is an in-memory implementation of api, returning two secrets per
+// list page. Deleted secrets are kept in a deleted state until purged.
+type fakeAPI struct {
+	mu      sync.Mutex
+	secrets map[string]azsecrets.SetSecretParameters
+	deleted map[string]bool
+	// purges counts PurgeDeletedSecret calls.
+	purges int
+	// conflicts is the number of PurgeDeletedSecret calls that fail as if
+	// the deletion were still in progress.
+	conflicts int
+}
+
+func notFound() error {
+	return &azcore.ResponseError{StatusCode: http.StatusNotFound}
+}
+
+func (f *fakeAPI) GetSecret(_ context.Context, name string, _ string, _ *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.secrets[name]
+	if !ok {
+		return azsecrets.GetSecretResponse{}, notFound()
+	}
+	var resp azsecrets.GetSecretResponse
+	resp.Value, resp.ContentType = p.Value, p.ContentType
+	return resp, nil
+}
+
+func (f *fakeAPI) SetSecret(_ context.Context, name string, params azsecrets.SetSecretParameters, _ *azsecrets.SetSecretOptions) (azsecrets.SetSecretResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.deleted[name] {
+		return azsecrets.SetSecretResponse{}, &azcore.ResponseError{StatusCode: http.StatusConflict}
+	}
+	f.secrets[name] = params
+	return azsecrets.SetSecretResponse{}, nil
+}
+
+func (f *fakeAPI) DeleteSecret(_ context.Context, name string, _ *azsecrets.DeleteSecretOptions) (azsecrets.DeleteSecretResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.secrets[name]; !ok {
+		return azsecrets.DeleteSecretResponse{}, notFound()
+	}
+	delete(f.secrets, name)
+	f.deleted[name] = true
+	return azsecrets.DeleteSecretResponse{}, nil
+}
+
+func (f *fakeAPI) PurgeDeletedSecret(_ context.Context, name string, _ *azsecrets.PurgeDeletedSecretOptions) (azsecrets.PurgeDeletedSecretResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.purges++
+	if f.conflicts > 0 {
+		f.conflicts--
+		return azsecrets.PurgeDeletedSecretResponse{}, &azcore.ResponseError{StatusCode: http.StatusConflict}
+	}
+	if !f.deleted[name] {
+		return azsecrets.PurgeDeletedSecretResponse{}, notFound()
+	}
+	delete(f.deleted, name)
+	return azsecrets.PurgeDeletedSecretResponse{}, nil
+}
+
+func (f *fakeAPI) NewListSecretsPager(_ *azsecrets.ListSecretsOptions) *runtime.Pager[azsecrets.ListSecretsResponse] {
+	f.mu.Lock()
+	var names []string
+	for name := range f.secrets {
+		names = append(names, name)
+	}
+	f.mu.Unlock()
+	sort.Strings(names)
+	next := 0
+	return runtime.NewPager(runtime.PagingHandler[azsecrets.ListSecretsResponse]{
+		More: func(azsecrets.ListSecretsResponse) bool {
+			return next < len(names)
+		},
+		Fetcher: func(context.Context, *azsecrets.ListSecretsResponse) (azsecrets.ListSecretsResponse, error) {
+			var resp azsecrets.ListSecretsResponse
+			for i := 0; i < 2 && next < len(names); i++ {
+				id := azsecrets.ID(vaultURL + "secrets/" + names[next])
+				resp.Value = append(resp.Value, &azsecrets.SecretItem{ID: &id})
+				next++
+			}
+			return resp, nil
+		},
+	})
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{secrets: map[string]azsecrets.SetSecretParameters{}, deleted: map[string]bool{}}
+}
+
+type harness struct {
+	api *fakeAPI
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Store, error) {
+	// Purge so that tests can reuse secret names.
+	return openStore(h.api, &StoreOptions{PurgeOnDelete: true}), nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{api: newFakeAPI()}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (verifyAs) StoreCheck(s *secretstore.Store) error {
+	// The harness uses a fake client, so As for the client fails.
+	var c *azsecrets.Client
+	if s.As(&c) {
+		return errors.New("Store.As succeeded for a fake client")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(s *secretstore.Store, err error) error {
+	var re *azcore.ResponseError
+	if !s.ErrorAs(err, &re) {
+		return errors.New("Store.ErrorAs failed")
+	}
+	return nil
+}
+
+// Key Vault-specific tests.
+
+func TestBinaryValue(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeAPI()
+	s := secretstore.NewStore(openStore(f, nil))
+	defer s.Close()
+
+	want := []byte{0xff, 0xfe, 0x00}
+	if err := s.Set(ctx, "name", want); err != nil {
+		t.Fatal(err)
+	}
+	if ct := f.secrets["name"].ContentType; ct == nil || *ct != binaryContentType {
+		t.Errorf("got content type %v, want %q", ct, binaryContentType)
+	}
+	got, err := s.Get(ctx, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeAPI()
+	s := secretstore.NewStore(openStore(f, nil))
+	defer s.Close()
+
+	if err := s.Set(ctx, "name", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "name"); err != nil {
+		t.Fatal(err)
+	}
+	if f.purges != 0 {
+		t.Errorf("got %d purges, want 0", f.purges)
+	}
+	if err := s.Set(ctx, "name", []byte("value")); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("Set of soft-deleted secret: got error %v, want FailedPrecondition", err)
+	}
+}
+
+func TestPurgeOnDelete(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeAPI()
+	f.conflicts = 1
+	s := secretstore.NewStore(openStore(f, &StoreOptions{PurgeOnDelete: true}))
+	defer s.Close()
+
+	if err := s.Set(ctx, "name", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "name"); err != nil {
+		t.Fatal(err)
+	}
+	// The first purge attempt conflicts, and Delete retries.
+	if f.purges != 2 {
+		t.Errorf("got %d purges, want 2", f.purges)
+	}
+	if err := s.Set(ctx, "name", []byte("value")); err != nil {
+		t.Errorf("Set after purge: %v", err)
+	}
+}
+
+func dummyClientMaker(vaultURL string) (*azsecrets.Client, error) {
+	return &azsecrets.Client{}, nil
+}
+
+func TestOpenStore(t *testing.T) {
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net", false},
+		// OK, setting purge_on_delete.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net?purge_on_delete=true", false},
+		// Invalid purge_on_delete.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net?purge_on_delete=maybe", true},
+		// Path is not allowed.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net/secrets/mysecret", true},
+		// Missing key vault name.
+		{"azurekeyvaultdummy:///vault.azure.net", true},
+		// Invalid query parameter.
+		{"azurekeyvaultdummy://mykeyvault.vault.azure.net?param=value", true},
+	}
+
+	secretstore.DefaultURLMux().RegisterStore(Scheme+"dummy", &URLOpener{ClientMaker: dummyClientMaker})
+	ctx := context.Background()
+	for _, test := range tests {
+		s, err := secretstore.OpenStore(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = s.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
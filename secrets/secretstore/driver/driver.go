@@ -0,0 +1,99 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package driver defines interfaces to be implemented by secretstore drivers,
+// which will be used by the secretstore package to interact with the
+// underlying services. Application code should use package secretstore.
+package driver // import "gocloud.dev/secrets/secretstore/driver"
+
+import (
+	"context"
+
+	"gocloud.dev/gcerrors"
+)
+
+// ListOptions sets options for listing secrets.
+type ListOptions struct {
+	// Prefix indicates that only secrets whose names begin with Prefix
+	// should be returned.
+	Prefix string
+	// PageSize sets the maximum number of secrets to be returned.
+	// 0 means no maximum; driver implementations should choose a reasonable
+	// max. It is guaranteed to be >= 0.
+	PageSize int
+	// PageToken may be filled in with the NextPageToken from a previous
+	// ListPaged call.
+	PageToken []byte
+}
+
+// ListSecret represents a secret returned from ListPaged.
+type ListSecret struct {
+	// Name is the name of the secret.
+	Name string
+	// AsFunc allows drivers to expose driver-specific types;
+	// see Store.As for more details.
+	// If not set, no driver-specific types are supported.
+	AsFunc func(interface{}) bool
+}
+
+// ListPage represents a page of results returned from ListPaged.
+type ListPage struct {
+	// Secrets is the slice of secrets found. If ListOptions.PageSize > 0,
+	// it should have at most ListOptions.PageSize entries.
+	Secrets []*ListSecret
+	// NextPageToken should be left empty unless there are more secrets
+	// to return. The value may be returned as ListOptions.PageToken on a
+	// subsequent ListPaged call, to fetch the next page of results.
+	NextPageToken []byte
+}
+
+// Store reads and writes named secret values in a secret storage service.
+type Store interface {
+	// Get returns the current value of the secret named name.
+	// If the secret does not exist, Get should return an error for which
+	// ErrorCode returns gcerrors.NotFound.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Set stores value as the current value of the secret named name,
+	// creating the secret if it does not exist. For services that keep
+	// versions, Set adds a new version.
+	Set(ctx context.Context, name string, value []byte) error
+
+	// Delete deletes the secret named name, including all of its versions.
+	// If the secret does not exist, Delete should return an error for which
+	// ErrorCode returns gcerrors.NotFound.
+	Delete(ctx context.Context, name string) error
+
+	// ListPaged lists the names of the secrets in the store.
+	// Secrets scheduled for deletion should not be returned.
+	ListPaged(ctx context.Context, opts *ListOptions) (*ListPage, error)
+
+	// As allows drivers to expose driver-specific types.
+	//
+	// See https://gocloud.dev/concepts/as/ for background information.
+	As(i interface{}) bool
+
+	// ErrorAs allows drivers to expose driver-specific types for returned
+	// errors.
+	//
+	// See https://gocloud.dev/concepts/as/ for background information.
+	ErrorAs(err error, i interface{}) bool
+
+	// ErrorCode should return a code that describes the error, which was returned
+	// by one of the other methods in this interface.
+	ErrorCode(error) gcerrors.ErrorCode
+
+	// Close releases any resources used for the Store.
+	Close() error
+}
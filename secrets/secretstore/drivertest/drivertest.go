@@ -0,0 +1,290 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drivertest provides a conformance test for implementations of
+// the secretstore driver.
+package drivertest // import "gocloud.dev/secrets/secretstore/drivertest"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+// Harness descibes the functionality test harnesses must provide to run
+// conformance tests.
+type Harness interface {
+	// MakeDriver returns a driver.Store. Secrets written through one
+	// driver.Store must be visible through the others returned by the same
+	// Harness.
+	MakeDriver(ctx context.Context) (driver.Store, error)
+
+	// Close is called when the test is complete.
+	Close()
+}
+
+// HarnessMaker describes functions that construct a harness for running tests.
+// It is called exactly once per test.
+type HarnessMaker func(ctx context.Context, t *testing.T) (Harness, error)
+
+// AsTest represents a test of As functionality.
+// The conformance test:
+// 1. Calls StoreCheck.
+// 2. Gets a secret that does not exist, and calls ErrorCheck with the error.
+type AsTest interface {
+	// Name returns a descriptive name for the test.
+	Name() string
+	// StoreCheck will be called to allow verification of Store.As.
+	StoreCheck(s *secretstore.Store) error
+	// ErrorCheck is called to allow verification of Store.ErrorAs.
+	ErrorCheck(s *secretstore.Store, err error) error
+}
+
+type verifyAsFailsOnNil struct{}
+
+func (v verifyAsFailsOnNil) Name() string {
+	return "verify As returns false when passed nil"
+}
+
+func (v verifyAsFailsOnNil) StoreCheck(s *secretstore.Store) error {
+	if s.As(nil) {
+		return errors.New("want Store.As to return false when passed nil")
+	}
+	return nil
+}
+
+func (v verifyAsFailsOnNil) ErrorCheck(s *secretstore.Store, err error) (ret error) {
+	defer func() {
+		if recover() == nil {
+			ret = errors.New("want ErrorAs to panic when passed nil")
+		}
+	}()
+	s.ErrorAs(err, nil)
+	return nil
+}
+
+// Secret names used by the conformance tests. They only use characters that
+// every supported service accepts.
+const (
+	missingName = "gocloud-test-missing"
+	setGetName  = "gocloud-test-setget"
+	deleteName  = "gocloud-test-delete"
+	listPrefix  = "gocloud-test-list-"
+)
+
+// RunConformanceTests runs conformance tests for driver implementations of
+// secretstore.
+func RunConformanceTests(t *testing.T, newHarness HarnessMaker, asTests []AsTest) {
+	t.Helper()
+
+	t.Run("TestGetNotFound", func(t *testing.T) {
+		withStore(t, newHarness, testGetNotFound)
+	})
+	t.Run("TestSetGet", func(t *testing.T) {
+		withStore(t, newHarness, testSetGet)
+	})
+	t.Run("TestDelete", func(t *testing.T) {
+		withStore(t, newHarness, testDelete)
+	})
+	t.Run("TestList", func(t *testing.T) {
+		withStore(t, newHarness, testList)
+	})
+	t.Run("TestListPaged", func(t *testing.T) {
+		testListPaged(t, newHarness)
+	})
+	asTests = append(asTests, verifyAsFailsOnNil{})
+	t.Run("TestAs", func(t *testing.T) {
+		for _, tc := range asTests {
+			if tc.Name() == "" {
+				t.Fatal("AsTest.Name is required")
+			}
+			t.Run(tc.Name(), func(t *testing.T) {
+				withStore(t, newHarness, func(t *testing.T, s *secretstore.Store) {
+					testAs(t, s, tc)
+				})
+			})
+		}
+	})
+}
+
+// withStore creates a harness and a Store and calls f with the Store.
+func withStore(t *testing.T, newHarness HarnessMaker, f func(*testing.T, *secretstore.Store)) {
+	t.Helper()
+
+	ctx := context.Background()
+	harness, err := newHarness(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer harness.Close()
+
+	drv, err := harness.MakeDriver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := secretstore.NewStore(drv)
+	defer s.Close()
+	f(t, s)
+}
+
+// testGetNotFound tests that getting a missing secret fails with NotFound.
+func testGetNotFound(t *testing.T, s *secretstore.Store) {
+	ctx := context.Background()
+	if _, err := s.Get(ctx, missingName); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+}
+
+// testSetGet tests that Set creates and then updates a secret.
+func testSetGet(t *testing.T, s *secretstore.Store) {
+	ctx := context.Background()
+	defer s.Delete(ctx, setGetName)
+
+	for _, want := range []string{"first value", "second value"} {
+		if err := s.Set(ctx, setGetName, []byte(want)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Get(ctx, setGetName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+}
+
+// testDelete tests that a deleted secret can't be read or deleted again.
+func testDelete(t *testing.T, s *secretstore.Store) {
+	ctx := context.Background()
+	if err := s.Set(ctx, deleteName, []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, deleteName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, deleteName); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("Get after Delete: got error %v, want NotFound", err)
+	}
+	if err := s.Delete(ctx, missingName); gcerrors.Code(err) != gcerrors.NotFound {
+		t.Errorf("Delete of missing secret: got error %v, want NotFound", err)
+	}
+}
+
+var listNames = []string{listPrefix + "a", listPrefix + "b", listPrefix + "c"}
+
+// createListSecrets creates the secrets named listNames, and returns a
+// function that deletes them.
+func createListSecrets(t *testing.T, s *secretstore.Store) func() {
+	ctx := context.Background()
+	for _, name := range listNames {
+		if err := s.Set(ctx, name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for _, name := range listNames {
+			s.Delete(ctx, name)
+		}
+	}
+}
+
+// testList tests that List returns the secrets matching a prefix.
+func testList(t *testing.T, s *secretstore.Store) {
+	ctx := context.Background()
+	defer createListSecrets(t, s)()
+
+	var got []string
+	iter := s.List(&secretstore.ListOptions{Prefix: listPrefix})
+	for {
+		ls, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ls.Name)
+	}
+	sort.Strings(got)
+	if diff := cmp.Diff(got, listNames); diff != "" {
+		t.Errorf("got -want +got\n%s", diff)
+	}
+}
+
+// testListPaged tests that the driver honors ListOptions.PageSize and
+// returns usable page tokens.
+func testListPaged(t *testing.T, newHarness HarnessMaker) {
+	ctx := context.Background()
+	harness, err := newHarness(ctx, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer harness.Close()
+
+	drv, err := harness.MakeDriver(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := secretstore.NewStore(drv)
+	defer s.Close()
+	defer createListSecrets(t, s)()
+
+	var got []string
+	opts := &driver.ListOptions{Prefix: listPrefix, PageSize: 1}
+	for {
+		page, err := drv.ListPaged(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Secrets) > 1 {
+			t.Fatalf("got %d secrets in a page, want at most 1", len(page.Secrets))
+		}
+		for _, ls := range page.Secrets {
+			if !strings.HasPrefix(ls.Name, listPrefix) {
+				t.Errorf("got secret %q, want prefix %q", ls.Name, listPrefix)
+			}
+			got = append(got, ls.Name)
+		}
+		if len(page.NextPageToken) == 0 {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+	sort.Strings(got)
+	if diff := cmp.Diff(got, listNames); diff != "" {
+		t.Errorf("got -want +got\n%s", diff)
+	}
+}
+
+func testAs(t *testing.T, s *secretstore.Store, tc AsTest) {
+	if err := tc.StoreCheck(s); err != nil {
+		t.Error(err)
+	}
+	_, err := s.Get(context.Background(), missingName)
+	if err == nil {
+		t.Fatal("got nil error from Get of missing secret, want non-nil")
+	}
+	if err := tc.ErrorCheck(s, err); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcpsecretmanager
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/testing/setup"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+	"gocloud.dev/secrets/secretstore/drivertest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const project = "my-project"
+
+// fakeServer is an in-memory implementation of the parts of the Secret
+// Manager API used by store.
+type fakeServer struct {
+	secretmanagerpb.UnimplementedSecretManagerServiceServer
+
+	mu sync.Mutex
+	// secrets maps secret resource names to their payload versions.
+	secrets map[string][]*secretmanagerpb.SecretPayload
+}
+
+func (f *fakeServer) AccessSecretVersion(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := strings.TrimSuffix(req.GetName(), "/versions/latest")
+	versions := f.secrets[name]
+	if len(versions) == 0 {
+		return nil, status.Errorf(codes.NotFound, "secret %s not found", name)
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{Name: req.GetName(), Payload: versions[len(versions)-1]}, nil
+}
+
+func (f *fakeServer) AddSecretVersion(_ context.Context, req *secretmanagerpb.AddSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions, ok := f.secrets[req.GetParent()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "secret %s not found", req.GetParent())
+	}
+	f.secrets[req.GetParent()] = append(versions, req.GetPayload())
+	return &secretmanagerpb.SecretVersion{}, nil
+}
+
+func (f *fakeServer) CreateSecret(_ context.Context, req *secretmanagerpb.CreateSecretRequest) (*secretmanagerpb.Secret, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := req.GetParent() + "/secrets/" + req.GetSecretId()
+	if _, ok := f.secrets[name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "secret %s exists", name)
+	}
+	f.secrets[name] = nil
+	return &secretmanagerpb.Secret{Name: name}, nil
+}
+
+func (f *fakeServer) DeleteSecret(_ context.Context, req *secretmanagerpb.DeleteSecretRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.secrets[req.GetName()]; !ok {
+		return nil, status.Errorf(codes.NotFound, "secret %s not found", req.GetName())
+	}
+	delete(f.secrets, req.GetName())
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeServer) ListSecrets(_ context.Context, req *secretmanagerpb.ListSecretsRequest) (*secretmanagerpb.ListSecretsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for name := range f.secrets {
+		if strings.HasPrefix(name, req.GetParent()+"/secrets/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	start := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if start, err = strconv.Atoi(req.GetPageToken()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "bad page token")
+		}
+	}
+	end := len(names)
+	if size := int(req.GetPageSize()); size > 0 && start+size < end {
+		end = start + size
+	}
+	resp := &secretmanagerpb.ListSecretsResponse{}
+	for _, name := range names[start:end] {
+		resp.Secrets = append(resp.Secrets, &secretmanagerpb.Secret{Name: name})
+	}
+	if end < len(names) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+// newFakeClient starts a fakeServer and returns a client connected to it.
+func newFakeClient(ctx context.Context, t *testing.T) (*fakeServer, *secretmanager.Client, func()) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeServer{secrets: map[string][]*secretmanagerpb.SecretPayload{}}
+	srv := grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(srv, fake)
+	go srv.Serve(l)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := secretmanager.NewClient(ctx, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, client, func() {
+		client.Close()
+		srv.Stop()
+	}
+}
+
+type harness struct {
+	client *secretmanager.Client
+	close  func()
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Store, error) {
+	return &store{client: h.client, parent: "projects/" + project}, nil
+}
+
+func (h *harness) Close() {
+	h.close()
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	_, client, done := newFakeClient(ctx, t)
+	return &harness{client: client, close: done}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (verifyAs) StoreCheck(s *secretstore.Store) error {
+	var c *secretmanager.Client
+	if !s.As(&c) {
+		return errors.New("Store.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(s *secretstore.Store, err error) error {
+	var st *status.Status
+	if !s.ErrorAs(err, &st) {
+		return errors.New("Store.ErrorAs failed")
+	}
+	return nil
+}
+
+// Secret Manager-specific tests.
+
+func TestChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	fake, client, done := newFakeClient(ctx, t)
+	defer done()
+	s := OpenStore(client, project, nil)
+	defer s.Close()
+
+	if err := s.Set(ctx, "name", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	fake.secrets["projects/"+project+"/secrets/name"][0].Data = []byte("corrupted")
+	fake.mu.Unlock()
+	if _, err := s.Get(ctx, "name"); gcerrors.Code(err) != gcerrors.Internal {
+		t.Errorf("got error %v, want Internal", err)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	cleanup := setup.FakeGCPDefaultCredentials(t)
+	defer cleanup()
+
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"gcpsecretmanager://projects/myproject", false},
+		// Missing project.
+		{"gcpsecretmanager://projects", true},
+		// Secret name is not allowed.
+		{"gcpsecretmanager://projects/myproject/secrets/mysecret", true},
+		// Invalid parameter.
+		{"gcpsecretmanager://projects/myproject?param=value", true},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		s, err := secretstore.OpenStore(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = s.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hashivault provides a secretstore implementation using the KV
+// version 2 secrets engine of Vault by Hashicorp.
+// Use OpenStore to construct a *secretstore.Store.
+//
+// Each secret is a KV entry that keeps the value under a single data key,
+// "value" by default. Values that are not valid UTF-8 are stored
+// base64-encoded, with an "encoding" data key set to "base64". Set writes a
+// new version of the entry, and Delete removes the entry with all of its
+// versions. Secret names may contain "/" to form a hierarchy below the
+// store's base path.
+//
+// # URLs
+//
+// For secretstore.OpenStore, hashivault registers for the scheme "hashivault".
+// The default URL opener will dial a Vault server using the environment variables
+// "VAULT_SERVER_URL" (or "VAULT_ADDR") and "VAULT_SERVER_TOKEN" (or "VAULT_TOKEN").
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// hashivault exposes the following types for As:
+//   - Store: *api.Client
+//   - Error: *api.ResponseError
+package hashivault // import "gocloud.dev/secrets/secretstore/hashivault"
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/hashicorp/vault/api"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+const (
+	defaultMount    = "secret"
+	defaultValueKey = "value"
+	defaultPageSize = 1000
+
+	// encodingKey and base64Encoding mark values stored base64-encoded.
+	encodingKey    = "encoding"
+	base64Encoding = "base64"
+)
+
+func init() {
+	secretstore.DefaultURLMux().RegisterStore(Scheme, new(defaultDialer))
+}
+
+// getVaultURL ensures that we check both VAULT_SERVER_URL and VAULT_ADDR environment
+// variables for the API address for vault. VAULT_SERVER_URL takes precedence over VAULT_ADDR.
+func getVaultURL() (string, error) {
+	serverURL := os.Getenv("VAULT_SERVER_URL")
+	if serverURL != "" {
+		return serverURL, nil
+	}
+
+	vaultAddr := os.Getenv("VAULT_ADDR")
+	if vaultAddr != "" {
+		return vaultAddr, nil
+	}
+
+	return "", errors.New("neither VAULT_SERVER_URL nor VAULT_ADDR environment variables are set")
+}
+
+// getVaultToken ensures that we check both VAULT_SERVER_TOKEN and VAULT_TOKEN environment
+// variables for the API token for vault. VAULT_SERVER_TOKEN takes precedence over VAULT_TOKEN.
+// If neither environment variables are found, then we return an empty string as token is not required.
+func getVaultToken() string {
+	serverToken := os.Getenv("VAULT_SERVER_TOKEN")
+	if serverToken != "" {
+		return serverToken
+	}
+
+	vaultToken := os.Getenv("VAULT_TOKEN")
+	if vaultToken != "" {
+		return vaultToken
+	}
+
+	return ""
+}
+
+// defaultDialer dials a default Vault server based on the environment variables
+// VAULT_SERVER_URL / VAULT_ADDR and VAULT_SERVER_TOKEN / VAULT_TOKEN
+type defaultDialer struct {
+	init   sync.Once
+	opener *URLOpener
+	err    error
+}
+
+func (o *defaultDialer) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	o.init.Do(func() {
+		serverURL, err := getVaultURL()
+		if err != nil {
+			o.err = err
+			return
+		}
+		client, err := api.NewClient(&api.Config{Address: serverURL})
+		if err != nil {
+			o.err = fmt.Errorf("failed to Dial default Vault server at %q: %v", serverURL, err)
+			return
+		}
+		if token := getVaultToken(); token != "" {
+			client.SetToken(token)
+		}
+		o.opener = &URLOpener{Client: client}
+	})
+	if o.err != nil {
+		return nil, fmt.Errorf("open store %v: %v", u, o.err)
+	}
+	return o.opener.OpenStoreURL(ctx, u)
+}
+
+// Scheme is the URL scheme hashivault registers its URLOpener under on
+// secretstore.DefaultMux.
+const Scheme = "hashivault"
+
+// URLOpener opens Vault URLs like "hashivault://secret/myapp".
+//
+// The URL Host is the mount path of the KV version 2 secrets engine, and the
+// optional URL Path is a base path that secret names are relative to.
+//
+// The following query parameters are supported:
+//   - value_key: Sets StoreOptions.ValueKey.
+type URLOpener struct {
+	// Client must be non-nil.
+	Client *api.Client
+
+	// Options specifies the options to pass to OpenStore.
+	Options StoreOptions
+}
+
+// OpenStoreURL opens the Store URL.
+func (o *URLOpener) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	opts := o.Options
+	for param, vals := range u.Query() {
+		switch param {
+		case "value_key":
+			opts.ValueKey = vals[0]
+		default:
+			return nil, fmt.Errorf("open store %v: invalid query parameter %q", u, param)
+		}
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("open store %v: URL host must be the KV mount path", u)
+	}
+	opts.Mount = u.Host
+	opts.BasePath = strings.Trim(u.Path, "/")
+	return OpenStore(o.Client, &opts), nil
+}
+
+// StoreOptions controls Store behaviors.
+type StoreOptions struct {
+	// Mount is the mount path of the KV version 2 secrets engine.
+	// It defaults to "secret".
+	Mount string
+
+	// BasePath is prepended to secret names, so that the Store only sees
+	// the secrets below it. It defaults to the root of the mount.
+	BasePath string
+
+	// ValueKey is the key of the KV data that holds the secret value.
+	// It defaults to "value".
+	ValueKey string
+}
+
+// OpenStore returns a *secretstore.Store that reads and writes secrets in the
+// KV version 2 secrets engine of Vault by Hashicorp.
+func OpenStore(client *api.Client, opts *StoreOptions) *secretstore.Store {
+	return secretstore.NewStore(newStore(client, opts))
+}
+
+func newStore(client *api.Client, opts *StoreOptions) *store {
+	if opts == nil {
+		opts = &StoreOptions{}
+	}
+	o := *opts
+	if o.Mount == "" {
+		o.Mount = defaultMount
+	}
+	if o.ValueKey == "" {
+		o.ValueKey = defaultValueKey
+	}
+	o.Mount = strings.Trim(o.Mount, "/")
+	o.BasePath = strings.Trim(o.BasePath, "/")
+	return &store{client: client, kv: client.KVv2(o.Mount), opts: o}
+}
+
+type store struct {
+	client *api.Client
+	kv     *api.KVv2
+	opts   StoreOptions
+}
+
+// secretPath returns the path of the named secret relative to the mount.
+func (s *store) secretPath(name string) (string, error) {
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", gcerr.Newf(gcerr.InvalidArgument, nil, "hashivault: invalid secret name %q", name)
+		}
+	}
+	if s.opts.BasePath == "" {
+		return name, nil
+	}
+	return s.opts.BasePath + "/" + name, nil
+}
+
+// Get implements driver.Store.Get.
+func (s *store) Get(ctx context.Context, name string) ([]byte, error) {
+	p, err := s.secretPath(name)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.kv.Get(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Data == nil {
+		// The latest version has been deleted or destroyed.
+		return nil, gcerr.Newf(gcerr.NotFound, nil, "hashivault: secret %q has no current version", name)
+	}
+	raw, ok := secret.Data[s.opts.ValueKey].(string)
+	if !ok {
+		return nil, gcerr.Newf(gcerr.FailedPrecondition, nil, "hashivault: secret %q has no string %q key", name, s.opts.ValueKey)
+	}
+	if secret.Data[encodingKey] == base64Encoding {
+		b, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, gcerr.Newf(gcerr.Internal, err, "hashivault: secret %q is marked base64 but does not decode", name)
+		}
+		return b, nil
+	}
+	return []byte(raw), nil
+}
+
+// Set implements driver.Store.Set.
+func (s *store) Set(ctx context.Context, name string, value []byte) error {
+	p, err := s.secretPath(name)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{}
+	if utf8.Valid(value) {
+		data[s.opts.ValueKey] = string(value)
+	} else {
+		data[s.opts.ValueKey] = base64.StdEncoding.EncodeToString(value)
+		data[encodingKey] = base64Encoding
+	}
+	_, err = s.kv.Put(ctx, p, data)
+	return err
+}
+
+// Delete implements driver.Store.Delete.
+func (s *store) Delete(ctx context.Context, name string) error {
+	p, err := s.secretPath(name)
+	if err != nil {
+		return err
+	}
+	// Deleting the metadata of a missing secret succeeds, so check first.
+	if _, err := s.kv.GetMetadata(ctx, p); err != nil {
+		return err
+	}
+	return s.kv.DeleteMetadata(ctx, p)
+}
+
+// ListPaged implements driver.Store.ListPaged. Secrets are returned in
+// lexicographical order of their names; the page token is the first name of
+// the next page.
+func (s *store) ListPaged(ctx context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	// Start at the deepest directory that the prefix names.
+	dir := ""
+	if i := strings.LastIndex(opts.Prefix, "/"); i >= 0 {
+		dir = opts.Prefix[:i+1]
+	}
+	var names []string
+	if err := s.walk(ctx, dir, opts.Prefix, &names); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	if len(opts.PageToken) > 0 {
+		i := sort.SearchStrings(names, string(opts.PageToken))
+		names = names[i:]
+	}
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	page := &driver.ListPage{}
+	if len(names) > pageSize {
+		page.NextPageToken = []byte(names[pageSize])
+		names = names[:pageSize]
+	}
+	for _, name := range names {
+		page.Secrets = append(page.Secrets, &driver.ListSecret{Name: name})
+	}
+	return page, nil
+}
+
+// walk appends the names of the secrets below dir that begin with prefix to
+// names, descending into subdirectories that may contain matches.
+func (s *store) walk(ctx context.Context, dir, prefix string, names *[]string) error {
+	resp, err := s.client.Logical().ListWithContext(ctx, path.Join(s.opts.Mount, "metadata", s.opts.BasePath, dir))
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	keys, _ := resp.Data["keys"].([]interface{})
+	for _, k := range keys {
+		key, ok := k.(string)
+		if !ok {
+			continue
+		}
+		name := dir + key
+		if strings.HasSuffix(key, "/") {
+			if strings.HasPrefix(name, prefix) || strings.HasPrefix(prefix, name) {
+				if err := s.walk(ctx, name, prefix, names); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(name, prefix) {
+			*names = append(*names, name)
+		}
+	}
+	return nil
+}
+
+// As implements driver.Store.As.
+func (s *store) As(i interface{}) bool {
+	p, ok := i.(**api.Client)
+	if !ok {
+		return false
+	}
+	*p = s.client
+	return true
+}
+
+// ErrorAs implements driver.Store.ErrorAs.
+func (s *store) ErrorAs(err error, i interface{}) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Store.ErrorCode.
+func (s *store) ErrorCode(err error) gcerrors.ErrorCode {
+	if errors.Is(err, api.ErrSecretNotFound) {
+		return gcerrors.NotFound
+	}
+	var re *api.ResponseError
+	if !errors.As(err, &re) {
+		return gcerrors.Unknown
+	}
+	switch re.StatusCode {
+	case http.StatusBadRequest:
+		return gcerrors.InvalidArgument
+	case http.StatusForbidden:
+		return gcerrors.PermissionDenied
+	case http.StatusNotFound:
+		return gcerrors.NotFound
+	case http.StatusTooManyRequests:
+		return gcerrors.ResourceExhausted
+	case http.StatusInternalServerError, http.StatusServiceUnavailable:
+		return gcerrors.Internal
+	}
+	return gcerrors.Unknown
+}
+
+// Close implements driver.Store.Close.
+func (s *store) Close() error { return nil }
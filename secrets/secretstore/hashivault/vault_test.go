@@ -0,0 +1,291 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashivault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+	"gocloud.dev/secrets/secretstore/drivertest"
+)
+
+const mount = "secret"
+
+// fakeVault is an in-memory implementation of the parts of the KV version 2
+// HTTP API used by store, for a secrets engine mounted at mount.
+type fakeVault struct {
+	mu sync.Mutex
+	// secrets maps paths below the mount to the data of their latest version.
+	secrets map[string]map[string]interface{}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func notFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/"+mount+"/")
+	if !ok {
+		notFound(w)
+		return
+	}
+	kind, p, _ := strings.Cut(rest, "/")
+	switch {
+	case kind == "data" && r.Method == http.MethodGet:
+		data, ok := f.secrets[p]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"data":     data,
+			"metadata": map[string]interface{}{"version": 1, "deletion_time": ""},
+		}})
+	case kind == "data" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{err.Error()}})
+			return
+		}
+		f.secrets[p] = body.Data
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"version": 1, "deletion_time": ""}})
+	case kind == "metadata" && (r.Method == "LIST" || r.URL.Query().Get("list") == "true"):
+		dir := strings.TrimSuffix(p, "/")
+		if dir != "" {
+			dir += "/"
+		}
+		seen := map[string]bool{}
+		var keys []string
+		for name := range f.secrets {
+			rest, ok := strings.CutPrefix(name, dir)
+			if !ok {
+				continue
+			}
+			if i := strings.Index(rest, "/"); i >= 0 {
+				rest = rest[:i+1]
+			}
+			if !seen[rest] {
+				seen[rest] = true
+				keys = append(keys, rest)
+			}
+		}
+		if len(keys) == 0 {
+			notFound(w)
+			return
+		}
+		sort.Strings(keys)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+	case kind == "metadata" && r.Method == http.MethodGet:
+		if _, ok := f.secrets[p]; !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"current_version": 1}})
+	case kind == "metadata" && r.Method == http.MethodDelete:
+		delete(f.secrets, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"errors": []string{"unsupported"}})
+	}
+}
+
+// newFakeClient starts a fakeVault and returns a client connected to it.
+func newFakeClient(t *testing.T) (*fakeVault, *api.Client, func()) {
+	t.Helper()
+
+	fake := &fakeVault{secrets: map[string]map[string]interface{}{}}
+	srv := httptest.NewServer(fake)
+	client, err := api.NewClient(&api.Config{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("faketoken")
+	return fake, client, srv.Close
+}
+
+type harness struct {
+	client *api.Client
+	close  func()
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Store, error) {
+	return newStore(h.client, &StoreOptions{BasePath: "gocloud"}), nil
+}
+
+func (h *harness) Close() {
+	h.close()
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	_, client, done := newFakeClient(t)
+	return &harness{client: client, close: done}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+}
+
+type verifyAs struct{}
+
+func (verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (verifyAs) StoreCheck(s *secretstore.Store) error {
+	var c *api.Client
+	if !s.As(&c) {
+		return errors.New("Store.As failed")
+	}
+	return nil
+}
+
+func (verifyAs) ErrorCheck(s *secretstore.Store, err error) error {
+	// Vault answers requests for missing secrets without an error response.
+	var re *api.ResponseError
+	if s.ErrorAs(err, &re) {
+		return errors.New("Store.ErrorAs succeeded for a missing secret")
+	}
+	return nil
+}
+
+// Vault-specific tests.
+
+func TestBinaryValue(t *testing.T) {
+	ctx := context.Background()
+	fake, client, done := newFakeClient(t)
+	defer done()
+	s := OpenStore(client, &StoreOptions{ValueKey: "v"})
+	defer s.Close()
+
+	want := []byte{0xff, 0xfe, 0x00}
+	if err := s.Set(ctx, "name", want); err != nil {
+		t.Fatal(err)
+	}
+	if enc := fake.secrets["name"][encodingKey]; enc != base64Encoding {
+		t.Errorf("got encoding %v, want %q", enc, base64Encoding)
+	}
+	if _, ok := fake.secrets["name"]["v"]; !ok {
+		t.Errorf("value not stored under value key %q", "v")
+	}
+	got, err := s.Get(ctx, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestNestedNames(t *testing.T) {
+	ctx := context.Background()
+	_, client, done := newFakeClient(t)
+	defer done()
+	s := OpenStore(client, nil)
+	defer s.Close()
+
+	for _, name := range []string{"app/db/password", "app/db/user", "app/token", "other"} {
+		if err := s.Set(ctx, name, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	iter := s.List(&secretstore.ListOptions{Prefix: "app/d"})
+	var got []string
+	for {
+		secret, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, secret.Name)
+	}
+	want := []string{"app/db/password", "app/db/user"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestInvalidName(t *testing.T) {
+	ctx := context.Background()
+	_, client, done := newFakeClient(t)
+	defer done()
+	s := OpenStore(client, nil)
+	defer s.Close()
+
+	for _, name := range []string{"/abs", "trailing/", "a//b", "a/../b"} {
+		if _, err := s.Get(ctx, name); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%q: got error %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	_, client, done := newFakeClient(t)
+	defer done()
+
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"hashivaultdummy://secret", false},
+		// OK, with a base path.
+		{"hashivaultdummy://secret/myapp/prod", false},
+		// OK, setting value_key.
+		{"hashivaultdummy://secret?value_key=password", false},
+		// Missing mount.
+		{"hashivaultdummy:///myapp", true},
+		// Invalid query parameter.
+		{"hashivaultdummy://secret?param=value", true},
+	}
+
+	secretstore.DefaultURLMux().RegisterStore(Scheme+"dummy", &URLOpener{Client: client})
+	ctx := context.Background()
+	for _, test := range tests {
+		s, err := secretstore.OpenStore(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = s.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
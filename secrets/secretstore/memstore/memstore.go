@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memstore provides an in-memory secretstore implementation,
+// intended for tests and local development.
+// Use OpenStore to construct a *secretstore.Store.
+//
+// # URLs
+//
+// For secretstore.OpenStore memstore registers for the scheme "mem".
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// memstore does not support any types for As.
+package memstore // import "gocloud.dev/secrets/secretstore/memstore"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets/secretstore"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+const defaultPageSize = 1000
+
+var errNotFound = errors.New("secret not found")
+
+func init() {
+	secretstore.DefaultURLMux().RegisterStore(Scheme, &URLOpener{})
+}
+
+// Scheme is the URL scheme memstore registers its URLOpener under on
+// secretstore.DefaultMux.
+const Scheme = "mem"
+
+// URLOpener opens URLs like "mem://". Each call opens a new, empty Store.
+//
+// No query parameters are supported.
+type URLOpener struct{}
+
+// OpenStoreURL opens a secretstore.Store based on u.
+func (*URLOpener) OpenStoreURL(ctx context.Context, u *url.URL) (*secretstore.Store, error) {
+	for param := range u.Query() {
+		return nil, fmt.Errorf("open store %v: invalid query parameter %q", u, param)
+	}
+	return OpenStore(nil), nil
+}
+
+// Options sets options for constructing a *secretstore.Store backed by
+// memory.
+type Options struct{}
+
+type store struct {
+	mu      sync.Mutex
+	secrets map[string][]byte
+}
+
+// OpenStore creates a *secretstore.Store backed by memory. The Store starts
+// out empty.
+func OpenStore(opts *Options) *secretstore.Store {
+	return secretstore.NewStore(openStore(opts))
+}
+
+func openStore(_ *Options) driver.Store {
+	return &store{secrets: map[string][]byte{}}
+}
+
+// Get implements driver.Store.Get.
+func (s *store) Get(_ context.Context, name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.secrets[name]
+	if !ok {
+		return nil, errNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// Set implements driver.Store.Set.
+func (s *store) Set(_ context.Context, name string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[name] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete implements driver.Store.Delete.
+func (s *store) Delete(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.secrets[name]; !ok {
+		return errNotFound
+	}
+	delete(s.secrets, name)
+	return nil
+}
+
+// ListPaged implements driver.Store.ListPaged. Secrets are returned in
+// lexicographical order of their names.
+func (s *store) ListPaged(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	s.mu.Lock()
+	var names []string
+	for name := range s.secrets {
+		if strings.HasPrefix(name, opts.Prefix) {
+			names = append(names, name)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+
+	// The page token is the first name to return.
+	if len(opts.PageToken) > 0 {
+		i := sort.SearchStrings(names, string(opts.PageToken))
+		names = names[i:]
+	}
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	page := &driver.ListPage{}
+	if len(names) > pageSize {
+		page.NextPageToken = []byte(names[pageSize])
+		names = names[:pageSize]
+	}
+	for _, name := range names {
+		page.Secrets = append(page.Secrets, &driver.ListSecret{Name: name})
+	}
+	return page, nil
+}
+
+// As implements driver.Store.As.
+func (s *store) As(i interface{}) bool { return false }
+
+// ErrorAs implements driver.Store.ErrorAs.
+func (s *store) ErrorAs(err error, i interface{}) bool { return false }
+
+// ErrorCode implements driver.Store.ErrorCode.
+func (s *store) ErrorCode(err error) gcerrors.ErrorCode {
+	if err == errNotFound {
+		return gcerrors.NotFound
+	}
+	return gcerrors.Unknown
+}
+
+// Close implements driver.Store.Close.
+func (s *store) Close() error { return nil }
@@ -0,0 +1,381 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secretstore provides an easy and portable way to read and write
+// named secret values kept in a secret storage service, such as AWS Secrets
+// Manager or GCP Secret Manager. Subpackages contain driver implementations
+// of secretstore for supported services.
+//
+// Unlike package secrets, which encrypts and decrypts data that the
+// application stores itself, a Store keeps the secret values in the service.
+//
+// # OpenTelemetry Integration
+//
+// This API reports the method calls below as OpenTelemetry spans, and as the
+// metric "gocdk.secrets.secretstore.duration", to the global tracer and meter providers.
+// See https://pkg.go.dev/gocloud.dev/telemetry for the span and metric
+// names, and for configuring exporters.
+//
+// # OpenCensus Integration
+//
+// OpenCensus supports tracing and metric collection for multiple languages and
+// backend providers. See https://opencensus.io.
+//
+// This API collects OpenCensus traces and metrics for the following methods:
+//   - Get
+//   - Set
+//   - Delete
+//   - ListPage
+//
+// All trace and metric names begin with the package import path.
+// The traces add the method name.
+// For example, "gocloud.dev/secrets/secretstore/Get".
+// The metrics are "completed_calls", a count of completed method calls by driver,
+// method and status (error code); and "latency", a distribution of method latency
+// by driver and method.
+// For example, "gocloud.dev/secrets/secretstore/latency".
+//
+// To enable trace collection in your application, see "Configure Exporter" at
+// https://opencensus.io/quickstart/go/tracing.
+// To enable metric collection in your application, see "Exporting stats" at
+// https://opencensus.io/quickstart/go/metrics.
+package secretstore // import "gocloud.dev/secrets/secretstore"
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+
+	"gocloud.dev/callopt"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/oc"
+	"gocloud.dev/internal/openurl"
+	"gocloud.dev/internal/telemetry"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+// Store reads and writes named secret values. To create a Store, use
+// constructors found in driver subpackages.
+type Store struct {
+	s      driver.Store
+	tracer *telemetry.Tracer
+
+	// mu protects the closed variable.
+	// Read locks are kept to allow holding a read lock for long-running calls,
+	// and thereby prevent closing until a call finishes.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewStore is intended for use by drivers only. Do not use in application code.
+var NewStore = newStore
+
+// newStore creates a Store.
+func newStore(s driver.Store) *Store {
+	return &Store{
+		s:      s,
+		tracer: telemetry.NewTracer(pkgName, telemetry.ProviderName(s), latencyMeasure, telemetry.DriverAttributes(s)...),
+	}
+}
+
+const pkgName = "gocloud.dev/secrets/secretstore"
+
+var (
+	latencyMeasure = oc.LatencyMeasure(pkgName)
+
+	// OpenCensusViews are predefined views for OpenCensus metrics.
+	// The views include counts and latency distributions for API method calls.
+	// See the example at https://godoc.org/go.opencensus.io/stats/view for usage.
+	OpenCensusViews = oc.Views(pkgName, latencyMeasure)
+)
+
+var (
+	errClosed    = gcerr.Newf(gcerr.FailedPrecondition, nil, "secretstore: Store has been closed")
+	errEmptyName = gcerr.Newf(gcerr.InvalidArgument, nil, "secretstore: secret name must not be empty")
+)
+
+// Get returns the current value of the secret named name.
+// If the secret does not exist, the returned error has code NotFound.
+func (s *Store) Get(ctx context.Context, name string) (value []byte, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = s.tracer.Start(ctx, "Get")
+	defer func() { s.tracer.End(ctx, err) }()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, errClosed
+	}
+	if name == "" {
+		return nil, errEmptyName
+	}
+
+	b, err := s.s.Get(ctx, name)
+	if err != nil {
+		return nil, wrapError(s, err)
+	}
+	return b, nil
+}
+
+// Set stores value as the current value of the secret named name, creating
+// the secret if it does not exist. For services that keep versions of
+// secrets, Set adds a new version and leaves the previous ones in place.
+func (s *Store) Set(ctx context.Context, name string, value []byte) (err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = s.tracer.Start(ctx, "Set")
+	defer func() { s.tracer.End(ctx, err) }()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errClosed
+	}
+	if name == "" {
+		return errEmptyName
+	}
+	return wrapError(s, s.s.Set(ctx, name, value))
+}
+
+// Delete deletes the secret named name, including all of its versions.
+// If the secret does not exist, the returned error has code NotFound.
+//
+// Some services keep deleted secrets in a recoverable state for a while;
+// see the driver documentation for details.
+func (s *Store) Delete(ctx context.Context, name string) (err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = s.tracer.Start(ctx, "Delete")
+	defer func() { s.tracer.End(ctx, err) }()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errClosed
+	}
+	if name == "" {
+		return errEmptyName
+	}
+	return wrapError(s, s.s.Delete(ctx, name))
+}
+
+// ListOptions sets options for listing secrets.
+type ListOptions struct {
+	// Prefix indicates that only secrets whose names begin with Prefix
+	// should be returned.
+	Prefix string
+}
+
+// ListSecret represents a single secret returned from List.
+type ListSecret struct {
+	// Name is the name of the secret.
+	Name string
+
+	asFunc func(interface{}) bool
+}
+
+// As converts i to driver-specific types.
+// See https://gocloud.dev/concepts/as/ for background information, the "As"
+// examples in this package for examples, and the driver package
+// documentation for the specific types supported for that driver.
+func (s *ListSecret) As(i interface{}) bool {
+	if s.asFunc == nil {
+		return false
+	}
+	return s.asFunc(i)
+}
+
+// ListIterator iterates over List results.
+type ListIterator struct {
+	s       *Store
+	opts    *driver.ListOptions
+	page    *driver.ListPage
+	nextIdx int
+}
+
+// Next returns a *ListSecret for the next secret. It returns (nil, io.EOF)
+// if there are no more.
+func (i *ListIterator) Next(ctx context.Context) (*ListSecret, error) {
+	if i.page != nil {
+		// We've already got a page of results.
+		if i.nextIdx < len(i.page.Secrets) {
+			// Next secret is in the page; return it.
+			ds := i.page.Secrets[i.nextIdx]
+			i.nextIdx++
+			return &ListSecret{Name: ds.Name, asFunc: ds.AsFunc}, nil
+		}
+		if len(i.page.NextPageToken) == 0 {
+			// Done with current page, and there are no more; return io.EOF.
+			return nil, io.EOF
+		}
+		// We need to load the next page.
+		i.opts.PageToken = i.page.NextPageToken
+	}
+	p, err := i.s.listPage(ctx, i.opts)
+	if err != nil {
+		return nil, err
+	}
+	i.page = p
+	i.nextIdx = 0
+	return i.Next(ctx)
+}
+
+// List returns a ListIterator that can be used to iterate over the secrets
+// in the store. The order of the results is driver-specific. The underlying
+// implementation fetches results in pages.
+//
+// A nil ListOptions is treated the same as the zero value.
+func (s *Store) List(opts *ListOptions) *ListIterator {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	return &ListIterator{s: s, opts: &driver.ListOptions{Prefix: opts.Prefix}}
+}
+
+func (s *Store) listPage(ctx context.Context, opts *driver.ListOptions) (page *driver.ListPage, err error) {
+	ctx, cancel := callopt.Context(ctx)
+	defer cancel()
+	ctx = s.tracer.Start(ctx, "ListPage")
+	defer func() { s.tracer.End(ctx, err) }()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, errClosed
+	}
+	p, err := s.s.ListPaged(ctx, opts)
+	if err != nil {
+		return nil, wrapError(s, err)
+	}
+	return p, nil
+}
+
+// As converts i to driver-specific types.
+// See https://gocloud.dev/concepts/as/ for background information, the "As"
+// examples in this package for examples, and the driver package
+// documentation for the specific types supported for that driver.
+func (s *Store) As(i interface{}) bool {
+	if i == nil {
+		return false
+	}
+	return s.s.As(i)
+}
+
+// ErrorAs converts i to driver-specific types. See
+// https://gocloud.dev/concepts/as/ for background information and the
+// driver package documentation for the specific types supported for
+// that driver.
+//
+// ErrorAs panics if i is nil or not a pointer.
+// ErrorAs returns false if err == nil.
+func (s *Store) ErrorAs(err error, i interface{}) bool {
+	return gcerr.ErrorAs(err, i, s.s.ErrorAs)
+}
+
+// Close releases any resources used for the Store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	prev := s.closed
+	s.closed = true
+	s.mu.Unlock()
+	if prev {
+		return errClosed
+	}
+	return wrapError(s, s.s.Close())
+}
+
+func wrapError(s *Store, err error) error {
+	if err == nil {
+		return nil
+	}
+	if gcerr.DoNotWrap(err) {
+		return err
+	}
+	code := gcerrors.Code(err)
+	if code == gcerrors.Unknown {
+		code = s.s.ErrorCode(err)
+	}
+	return gcerr.New(code, err, 2, "secretstore")
+}
+
+// StoreURLOpener represents types that can open Stores based on a URL.
+// The opener must not modify the URL argument. OpenStoreURL must be safe to
+// call from multiple goroutines.
+//
+// This interface is generally implemented by types in driver packages.
+type StoreURLOpener interface {
+	OpenStoreURL(ctx context.Context, u *url.URL) (*Store, error)
+}
+
+// URLMux is a URL opener multiplexer. It matches the scheme of the URLs
+// against a set of registered schemes and calls the opener that matches the
+// URL's scheme.
+// See https://gocloud.dev/concepts/urls/ for more information.
+//
+// The zero value is a multiplexer with no registered schemes.
+type URLMux struct {
+	schemes openurl.SchemeMap
+}
+
+// StoreSchemes returns a sorted slice of the registered Store schemes.
+func (mux *URLMux) StoreSchemes() []string { return mux.schemes.Schemes() }
+
+// ValidStoreScheme returns true iff scheme has been registered for Stores.
+func (mux *URLMux) ValidStoreScheme(scheme string) bool { return mux.schemes.ValidScheme(scheme) }
+
+// RegisterStore registers the opener with the given scheme. If an opener
+// already exists for the scheme, RegisterStore panics.
+func (mux *URLMux) RegisterStore(scheme string, opener StoreURLOpener) {
+	mux.schemes.Register("secretstore", "Store", scheme, opener)
+}
+
+// OpenStore calls OpenStoreURL with the URL parsed from urlstr.
+// OpenStore is safe to call from multiple goroutines.
+func (mux *URLMux) OpenStore(ctx context.Context, urlstr string) (*Store, error) {
+	opener, u, err := mux.schemes.FromString("Store", urlstr)
+	if err != nil {
+		return nil, err
+	}
+	return opener.(StoreURLOpener).OpenStoreURL(ctx, u)
+}
+
+// OpenStoreURL dispatches the URL to the opener that is registered with the
+// URL's scheme. OpenStoreURL is safe to call from multiple goroutines.
+func (mux *URLMux) OpenStoreURL(ctx context.Context, u *url.URL) (*Store, error) {
+	opener, err := mux.schemes.FromURL("Store", u)
+	if err != nil {
+		return nil, err
+	}
+	return opener.(StoreURLOpener).OpenStoreURL(ctx, u)
+}
+
+var defaultURLMux = new(URLMux)
+
+// DefaultURLMux returns the URLMux used by OpenStore.
+//
+// Driver packages can use this to register their StoreURLOpener on the mux.
+func DefaultURLMux() *URLMux {
+	return defaultURLMux
+}
+
+// OpenStore opens the Store identified by the URL given.
+// See the URLOpener documentation in driver subpackages for
+// details on supported URL formats, and https://gocloud.dev/concepts/urls
+// for more information.
+func OpenStore(ctx context.Context, urlstr string) (*Store, error) {
+	return defaultURLMux.OpenStore(ctx, urlstr)
+}
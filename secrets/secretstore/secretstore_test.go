@@ -0,0 +1,279 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/internal/testing/octest"
+	"gocloud.dev/secrets/secretstore/driver"
+)
+
+var errFake = errors.New("fake")
+
+type erroringStore struct {
+	driver.Store
+}
+
+func (s *erroringStore) Get(context.Context, string) ([]byte, error) { return nil, errFake }
+func (s *erroringStore) Set(context.Context, string, []byte) error   { return errFake }
+func (s *erroringStore) Delete(context.Context, string) error        { return errFake }
+func (s *erroringStore) ListPaged(context.Context, *driver.ListOptions) (*driver.ListPage, error) {
+	return nil, errFake
+}
+func (s *erroringStore) Close() error                       { return errFake }
+func (s *erroringStore) ErrorCode(error) gcerrors.ErrorCode { return gcerrors.Internal }
+
+func TestErrorsAreWrapped(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(&erroringStore{})
+
+	// verifyWrap ensures that err is wrapped exactly once.
+	verifyWrap := func(description string, err error) {
+		if err == nil {
+			t.Errorf("%s: got nil error, wanted non-nil", description)
+		} else if unwrapped, ok := err.(*gcerr.Error); !ok {
+			t.Errorf("%s: not wrapped: %v", description, err)
+		} else if du, ok := unwrapped.Unwrap().(*gcerr.Error); ok {
+			t.Errorf("%s: double wrapped: %v", description, du)
+		}
+		if s := err.Error(); !strings.HasPrefix(s, "secretstore ") {
+			t.Errorf("%s: Error() for wrapped error doesn't start with secretstore: prefix: %s", description, s)
+		}
+		if gcerrors.Code(err) != gcerrors.Internal {
+			t.Errorf("%s: got code %v, want Internal", description, gcerrors.Code(err))
+		}
+	}
+
+	_, err := s.Get(ctx, "name")
+	verifyWrap("Get", err)
+
+	err = s.Set(ctx, "name", nil)
+	verifyWrap("Set", err)
+
+	err = s.Delete(ctx, "name")
+	verifyWrap("Delete", err)
+
+	_, err = s.List(nil).Next(ctx)
+	verifyWrap("List", err)
+
+	err = s.Close()
+	verifyWrap("Close", err)
+}
+
+// TestStoreIsClosed tests that Store functions return an error when the
+// Store is closed.
+func TestStoreIsClosed(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(&erroringStore{})
+	s.Close()
+
+	if _, err := s.Get(ctx, "name"); err != errClosed {
+		t.Error(err)
+	}
+	if err := s.Set(ctx, "name", nil); err != errClosed {
+		t.Error(err)
+	}
+	if err := s.Delete(ctx, "name"); err != errClosed {
+		t.Error(err)
+	}
+	if _, err := s.List(nil).Next(ctx); err != errClosed {
+		t.Error(err)
+	}
+	if err := s.Close(); err != errClosed {
+		t.Error(err)
+	}
+}
+
+func TestEmptyName(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(&erroringStore{})
+	defer s.Close()
+
+	if _, err := s.Get(ctx, ""); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Get: got error %v, want InvalidArgument", err)
+	}
+	if err := s.Set(ctx, "", nil); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Set: got error %v, want InvalidArgument", err)
+	}
+	if err := s.Delete(ctx, ""); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Delete: got error %v, want InvalidArgument", err)
+	}
+}
+
+// pagedStore is a driver.Store that lists a fixed set of names, two per
+// page.
+type pagedStore struct {
+	erroringStore
+	names []string
+	calls int
+}
+
+func (s *pagedStore) ListPaged(_ context.Context, opts *driver.ListOptions) (*driver.ListPage, error) {
+	s.calls++
+	start := 0
+	if len(opts.PageToken) > 0 {
+		start = int(opts.PageToken[0])
+	}
+	page := &driver.ListPage{}
+	end := start + 2
+	if end < len(s.names) {
+		page.NextPageToken = []byte{byte(end)}
+	} else {
+		end = len(s.names)
+	}
+	for _, name := range s.names[start:end] {
+		page.Secrets = append(page.Secrets, &driver.ListSecret{Name: name})
+	}
+	return page, nil
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	want := []string{"a", "b", "c", "d", "e"}
+	ps := &pagedStore{names: want}
+	s := NewStore(ps)
+	defer s.Close()
+
+	var got []string
+	iter := s.List(nil)
+	for {
+		ls, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ls.Name)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("got -want +got\n%s", diff)
+	}
+	if ps.calls != 3 {
+		t.Errorf("got %d ListPaged calls, want 3", ps.calls)
+	}
+}
+
+func TestOpenCensus(t *testing.T) {
+	ctx := context.Background()
+	te := octest.NewTestExporter(OpenCensusViews)
+	defer te.Unregister()
+
+	s := NewStore(&erroringStore{})
+	defer s.Close()
+	s.Get(ctx, "name")
+	s.Set(ctx, "name", nil)
+	s.Delete(ctx, "name")
+	s.List(nil).Next(ctx)
+	diff := octest.Diff(te.Spans(), te.Counts(), "gocloud.dev/secrets/secretstore", "gocloud.dev/secrets/secretstore", []octest.Call{
+		{Method: "Get", Code: gcerrors.Internal},
+		{Method: "Set", Code: gcerrors.Internal},
+		{Method: "Delete", Code: gcerrors.Internal},
+		{Method: "ListPage", Code: gcerrors.Internal},
+	})
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestURLMux(t *testing.T) {
+	ctx := context.Background()
+
+	mux := new(URLMux)
+	fake := &fakeOpener{}
+	mux.RegisterStore("foo", fake)
+	mux.RegisterStore("err", fake)
+
+	if diff := cmp.Diff(mux.StoreSchemes(), []string{"err", "foo"}); diff != "" {
+		t.Errorf("Schemes: %s", diff)
+	}
+	if !mux.ValidStoreScheme("foo") || !mux.ValidStoreScheme("err") {
+		t.Errorf("ValidStoreScheme didn't return true for valid scheme")
+	}
+	if mux.ValidStoreScheme("foo2") || mux.ValidStoreScheme("http") {
+		t.Errorf("ValidStoreScheme didn't return false for invalid scheme")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{
+			name:    "empty URL",
+			wantErr: true,
+		},
+		{
+			name:    "unregistered scheme",
+			url:     "bar://mystore",
+			wantErr: true,
+		},
+		{
+			name:    "func returns error",
+			url:     "err://mystore",
+			wantErr: true,
+		},
+		{
+			name: "no query options",
+			url:  "foo://mystore",
+		},
+		{
+			name: "query options",
+			url:  "foo://mystore?aAa=bBb&cCc=dDd",
+		},
+		{
+			name: "using api scheme prefix",
+			url:  "secretstore+foo://mystore",
+		},
+		{
+			name: "using api+type scheme prefix",
+			url:  "secretstore+store+foo://mystore",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, gotErr := mux.OpenStore(ctx, tc.url)
+			if (gotErr != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want error %v", gotErr, tc.wantErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if got := fake.u.String(); got != tc.url {
+				t.Errorf("got %q want %q", got, tc.url)
+			}
+		})
+	}
+}
+
+type fakeOpener struct {
+	u *url.URL // last url passed to OpenStoreURL
+}
+
+func (o *fakeOpener) OpenStoreURL(ctx context.Context, u *url.URL) (*Store, error) {
+	if u.Scheme == "err" {
+		return nil, errors.New("fail")
+	}
+	o.u = u
+	return nil, nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"gocloud.dev/internal/gcerr"
+)
+
+// Streams written by EncryptStream have the following layout:
+//   - a version byte, currently 1;
+//   - the length of the encrypted data key, as a uvarint;
+//   - the encrypted data key;
+//   - a 7-byte random nonce prefix;
+//   - a sequence of AES-256-GCM sealed chunks, each holding streamChunkSize
+//     bytes of plaintext except the last, which holds fewer (possibly none).
+//
+// The nonce of chunk i is the nonce prefix, i as a big-endian uint32, and a
+// final byte that is 1 for the last chunk and 0 otherwise, so chunks cannot
+// be reordered, dropped or truncated without detection. Everything before
+// the chunks is authenticated as additional data of every chunk.
+const (
+	streamVersion         = 1
+	streamChunkSize       = 64 * 1024
+	streamNoncePrefixSize = 7
+)
+
+var errMalformedStream = gcerr.Newf(gcerr.InvalidArgument, nil, "secrets: malformed ciphertext stream")
+
+// EncryptStream returns a writer that encrypts the data written to it and
+// writes the ciphertext to w, so that payloads of any size can be encrypted
+// without holding them in memory. The caller must call Close on the returned
+// writer to write the final chunk; Close does not close w.
+//
+// EncryptStream uses envelope encryption: it gets a new data key with
+// GenerateDataKey, so the Keeper's key service is called once per stream,
+// and encrypts the data locally in chunks with AES-256-GCM. Decrypt the
+// result with DecryptStream.
+func (k *Keeper) EncryptStream(ctx context.Context, w io.Writer) (io.WriteCloser, error) {
+	dataKey, encryptedKey, err := k.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newStreamAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, 1+binary.MaxVarintLen64+len(encryptedKey)+streamNoncePrefixSize)
+	header = append(header, streamVersion)
+	header = binary.AppendUvarint(header, uint64(len(encryptedKey)))
+	header = append(header, encryptedKey...)
+	prefix := make([]byte, streamNoncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "secrets: failed to generate nonce")
+	}
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &streamWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		nonce:  newStreamNonce(prefix),
+		buf:    make([]byte, 0, streamChunkSize),
+	}, nil
+}
+
+// DecryptStream returns a reader that decrypts the ciphertext read from r,
+// which must have been written by EncryptStream. The Keeper is used once, to
+// decrypt the data key at the start of the stream.
+//
+// Each chunk is authenticated before any of its plaintext is returned, but
+// a stream that has been tampered with is only detected when the damaged
+// chunk is reached; callers must not act on the plaintext until Read has
+// returned io.EOF.
+func (k *Keeper) DecryptStream(ctx context.Context, r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	v, err := br.ReadByte()
+	if err != nil {
+		return nil, streamReadError(err)
+	}
+	if v != streamVersion {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "secrets: unsupported ciphertext stream version %d", v)
+	}
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, streamReadError(err)
+	}
+	// No driver produces encrypted data keys anywhere near this large; the
+	// limit keeps a corrupt length from causing a huge allocation.
+	if n > 1<<20 {
+		return nil, errMalformedStream
+	}
+	header := make([]byte, 0, 1+binary.MaxVarintLen64+int(n)+streamNoncePrefixSize)
+	header = append(header, v)
+	header = binary.AppendUvarint(header, n)
+	rest := make([]byte, int(n)+streamNoncePrefixSize)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, streamReadError(err)
+	}
+	header = append(header, rest...)
+	encryptedKey, prefix := rest[:n], rest[n:]
+
+	dataKey, err := k.Decrypt(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newStreamAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &streamReader{
+		r:         br,
+		aead:      aead,
+		header:    header,
+		nonce:     newStreamNonce(prefix),
+		buf:       make([]byte, 0, streamChunkSize+aead.Overhead()+1),
+		decrypted: make([]byte, 0, streamChunkSize),
+	}, nil
+}
+
+func newStreamAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, gcerr.Newf(gcerr.Internal, err, "secrets: invalid data key")
+	}
+	return cipher.NewGCM(block)
+}
+
+// streamReadError converts an error reading the start of a ciphertext
+// stream into one for which gcerrors.Code returns InvalidArgument if the
+// stream was too short.
+func streamReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errMalformedStream
+	}
+	return err
+}
+
+// streamNonce is the nonce of the current chunk of a stream.
+type streamNonce struct {
+	b       [12]byte
+	counter uint64
+}
+
+func newStreamNonce(prefix []byte) *streamNonce {
+	n := &streamNonce{}
+	copy(n.b[:], prefix)
+	return n
+}
+
+// next returns the nonce for the next chunk.
+func (n *streamNonce) next(last bool) ([]byte, error) {
+	if n.counter > math.MaxUint32 {
+		return nil, gcerr.Newf(gcerr.InvalidArgument, nil, "secrets: stream is too long")
+	}
+	binary.BigEndian.PutUint32(n.b[streamNoncePrefixSize:], uint32(n.counter))
+	n.b[len(n.b)-1] = 0
+	if last {
+		n.b[len(n.b)-1] = 1
+	}
+	n.counter++
+	return n.b[:], nil
+}
+
+// streamWriter is the io.WriteCloser returned by EncryptStream.
+type streamWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	nonce  *streamNonce
+	// buf holds plaintext that has not been sealed yet. A full chunk is
+	// only sealed once more data arrives, since the last chunk is sealed
+	// differently.
+	buf    []byte
+	sealed []byte
+	err    error
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(s.buf) == streamChunkSize {
+			if err := s.seal(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(s.buf[len(s.buf):streamChunkSize], p)
+		s.buf = s.buf[:len(s.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// seal encrypts and writes the buffered plaintext as the next chunk.
+func (s *streamWriter) seal(last bool) error {
+	nonce, err := s.nonce.next(last)
+	if err == nil {
+		s.sealed = s.aead.Seal(s.sealed[:0], nonce, s.buf, s.header)
+		_, err = s.w.Write(s.sealed)
+	}
+	if err != nil {
+		s.err = err
+		return err
+	}
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// Close writes the last chunk. It does not close the underlying writer.
+func (s *streamWriter) Close() error {
+	if s.err != nil {
+		if s.err == errStreamClosed {
+			return nil
+		}
+		return s.err
+	}
+	if err := s.seal(true); err != nil {
+		return err
+	}
+	s.err = errStreamClosed
+	return nil
+}
+
+var errStreamClosed = gcerr.Newf(gcerr.FailedPrecondition, nil, "secrets: write to closed stream")
+
+// streamReader is the io.Reader returned by DecryptStream.
+type streamReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	header []byte
+	nonce  *streamNonce
+	// buf holds ciphertext read ahead of the current chunk; reading one
+	// byte past a full chunk tells whether it is the last one.
+	buf []byte
+	// decrypted holds the plaintext of the current chunk; it is reused for
+	// each chunk.
+	decrypted []byte
+	// plaintext is the unread part of decrypted.
+	plaintext []byte
+	done      bool
+	err       error
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	for len(s.plaintext) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.done {
+			return 0, io.EOF
+		}
+		s.err = s.open()
+	}
+	n := copy(p, s.plaintext)
+	s.plaintext = s.plaintext[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk into s.plaintext.
+func (s *streamReader) open() error {
+	full := streamChunkSize + s.aead.Overhead()
+	n, err := io.ReadFull(s.r, s.buf[len(s.buf):full+1])
+	s.buf = s.buf[:len(s.buf)+n]
+	last := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	}
+	chunk := s.buf
+	if !last {
+		chunk = s.buf[:full]
+	}
+	nonce, err := s.nonce.next(last)
+	if err != nil {
+		return err
+	}
+	// open is only called once the previous plaintext has been consumed,
+	// so its buffer can be reused.
+	plaintext, err := s.aead.Open(s.decrypted[:0], nonce, chunk, s.header)
+	if err != nil {
+		return gcerr.Newf(gcerr.InvalidArgument, err, "secrets: failed to decrypt stream")
+	}
+	s.decrypted = plaintext
+	s.plaintext = plaintext
+	if last {
+		s.done = true
+		return nil
+	}
+	// Keep the read-ahead byte for the next chunk.
+	s.buf[0] = s.buf[full]
+	s.buf = s.buf[:1]
+	return nil
+}
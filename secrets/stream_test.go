@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"gocloud.dev/gcerrors"
+)
+
+func encryptStream(t *testing.T, k *Keeper, plaintext []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := k.EncryptStream(context.Background(), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write in uneven pieces to exercise chunk boundaries.
+	for p := plaintext; len(p) > 0; {
+		n := 1000
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&reverseKeeper{})
+	defer k.Close()
+
+	for _, size := range []int{0, 1, streamChunkSize - 1, streamChunkSize, streamChunkSize + 1, 3*streamChunkSize + 17} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatal(err)
+		}
+		ciphertext := encryptStream(t, k, plaintext)
+
+		r, err := k.DecryptStream(ctx, iotest.HalfReader(bytes.NewReader(ciphertext)))
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("size %d: decrypted stream does not match", size)
+		}
+	}
+}
+
+func TestStreamReusesPlaintextBuffer(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&reverseKeeper{})
+	defer k.Close()
+
+	ciphertext := encryptStream(t, k, make([]byte, 3*streamChunkSize))
+	r, err := k.DecryptStream(ctx, bytes.NewReader(ciphertext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr := r.(*streamReader)
+	p := make([]byte, streamChunkSize)
+	var first *byte
+	for i := 0; i < 3; i++ {
+		if _, err := io.ReadFull(r, p); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = &sr.decrypted[:1][0]
+		} else if &sr.decrypted[:1][0] != first {
+			t.Errorf("chunk %d: plaintext buffer was reallocated", i)
+		}
+	}
+}
+
+func TestStreamUsesDataKeyGenerator(t *testing.T) {
+	dk := &dataKeyKeeper{}
+	k := NewKeeper(dk)
+	defer k.Close()
+
+	encryptStream(t, k, []byte("hello"))
+	if dk.calls != 1 {
+		t.Errorf("got %d GenerateDataKey calls, want 1", dk.calls)
+	}
+}
+
+func TestStreamTampered(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&reverseKeeper{})
+	defer k.Close()
+
+	plaintext := bytes.Repeat([]byte("x"), 2*streamChunkSize+10)
+	ciphertext := encryptStream(t, k, plaintext)
+	sealedChunk := streamChunkSize + 16
+	headerSize := len(ciphertext) - 2*sealedChunk - (10 + 16)
+
+	flipped := append([]byte(nil), ciphertext...)
+	flipped[headerSize+sealedChunk+5] ^= 1
+	badKey := append([]byte(nil), ciphertext...)
+	badKey[headerSize-streamNoncePrefixSize-1] ^= 1
+	dropped := append(append([]byte(nil), ciphertext[:headerSize+sealedChunk]...), ciphertext[headerSize+2*sealedChunk:]...)
+
+	for _, tc := range []struct {
+		name       string
+		ciphertext []byte
+	}{
+		{"flipped bit", flipped},
+		{"changed data key", badKey},
+		{"dropped chunk", dropped},
+		{"truncated at chunk boundary", ciphertext[:headerSize+2*sealedChunk]},
+		{"truncated in chunk", ciphertext[:len(ciphertext)-1]},
+		{"header only", ciphertext[:headerSize]},
+	} {
+		r, err := k.DecryptStream(ctx, bytes.NewReader(tc.ciphertext))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if _, err := io.ReadAll(r); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%s: got error %v, want InvalidArgument", tc.name, err)
+		}
+	}
+
+	badVersion := append([]byte(nil), ciphertext...)
+	badVersion[0] = 99
+	for _, tc := range []struct {
+		name       string
+		ciphertext []byte
+	}{
+		{"empty", nil},
+		{"bad version", badVersion},
+		{"truncated header", ciphertext[:headerSize-1]},
+	} {
+		if _, err := k.DecryptStream(ctx, bytes.NewReader(tc.ciphertext)); gcerrors.Code(err) != gcerrors.InvalidArgument {
+			t.Errorf("%s: got error %v, want InvalidArgument", tc.name, err)
+		}
+	}
+}
+
+func TestStreamKeeperClosed(t *testing.T) {
+	ctx := context.Background()
+	k := NewKeeper(&reverseKeeper{})
+	ciphertext := encryptStream(t, k, []byte("hello"))
+	k.Close()
+
+	if _, err := k.EncryptStream(ctx, io.Discard); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("EncryptStream: got error %v, want FailedPrecondition", err)
+	}
+	if _, err := k.DecryptStream(ctx, bytes.NewReader(ciphertext)); gcerrors.Code(err) != gcerrors.FailedPrecondition {
+		t.Errorf("DecryptStream: got error %v, want FailedPrecondition", err)
+	}
+}
+
+func TestStreamWriteAfterClose(t *testing.T) {
+	k := NewKeeper(&reverseKeeper{})
+	defer k.Close()
+
+	w, err := k.EncryptStream(context.Background(), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after Close: got nil error, want error")
+	}
+}
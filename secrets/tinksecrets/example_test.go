@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tinksecrets_test
+
+import (
+	"context"
+	"log"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/tinksecrets"
+)
+
+func ExampleOpenKeeper() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	// Read the encrypted keyset from a bucket.
+	bucket, err := blob.OpenBucket(ctx, "gs://my-bucket")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bucket.Close()
+	data, err := bucket.ReadAll(ctx, "keyset.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Decrypt it with the KEK.
+	kek, err := secrets.OpenKeeper(ctx, "gcpkms://projects/MYPROJECT/locations/MYLOCATION/keyRings/MYKEYRING/cryptoKeys/MYKEY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer kek.Close()
+	h, err := tinksecrets.ReadKeyset(ctx, data, kek)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Construct a *secrets.Keeper.
+	keeper, err := tinksecrets.OpenKeeper(h, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer keeper.Close()
+}
+
+func Example_openFromURL() {
+	// PRAGMA: This example is used on gocloud.dev; PRAGMA comments adjust how it is shown and can be ignored.
+	// PRAGMA: On gocloud.dev, add a blank import: _ "gocloud.dev/secrets/tinksecrets"
+	// PRAGMA: On gocloud.dev, hide lines until the next blank line.
+	ctx := context.Background()
+
+	keeper, err := secrets.OpenKeeper(ctx, "tink:///path/to/keyset.json?kek=gcpkms%3A%2F%2Fprojects%2FMYPROJECT%2Flocations%2FMYLOCATION%2FkeyRings%2FMYKEYRING%2FcryptoKeys%2FMYKEY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer keeper.Close()
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tinksecrets provides a secrets implementation backed by a Tink
+// (https://developers.google.com/tink) AEAD keyset.
+// Use OpenKeeper to construct a *secrets.Keeper.
+//
+// Tink keysets support key rotation: Encrypt uses the keyset's primary key,
+// and Decrypt uses whichever key of the keyset encrypted the data. Ciphertexts
+// are compatible with Tink in other languages.
+//
+// Keysets are normally stored encrypted with a key encryption key (KEK), for
+// example in a blob.Bucket or a runtimevar.Variable. Use ReadKeyset to decrypt
+// a stored keyset with a KEK held by another *secrets.Keeper (for example,
+// one opened with gcpkms), and WriteKeyset to produce one.
+//
+// EncryptWithAAD and DecryptWithAAD pass their associated data to Tink, for
+// Keepers opened without KeeperOptions.AssociatedData.
+//
+// # URLs
+//
+// For secrets.OpenKeeper, tinksecrets registers for the scheme "tink".
+// To customize the URL opener, or for more details on the URL format,
+// see URLOpener.
+// See https://gocloud.dev/concepts/urls/ for background information.
+//
+// # As
+//
+// tinksecrets does not support any types for As.
+package tinksecrets // import "gocloud.dev/secrets/tinksecrets"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/internal/gcerr"
+	"gocloud.dev/secrets"
+)
+
+func init() {
+	secrets.DefaultURLMux().RegisterKeeper(Scheme, &URLOpener{})
+}
+
+// Scheme is the URL scheme tinksecrets registers its URLOpener under on
+// secrets.DefaultMux.
+const Scheme = "tink"
+
+// URLOpener opens Tink URLs like
+// "tink:///path/to/keyset.json?kek=gcpkms%3A%2F%2Fprojects%2F...".
+//
+// The URL path is the path of a file holding a keyset encrypted with a KEK,
+// in Tink's JSON or binary format.
+//
+// The following query parameters are supported:
+//   - kek (required): The URL of the Keeper holding the KEK, opened with
+//     secrets.OpenKeeper. It must be URL-escaped.
+type URLOpener struct {
+	// Options specifies the options to pass to OpenKeeper.
+	Options KeeperOptions
+}
+
+// OpenKeeperURL opens Keeper URLs.
+func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*secrets.Keeper, error) {
+	var kekURL string
+	for param, vals := range u.Query() {
+		switch param {
+		case "kek":
+			kekURL = vals[0]
+		default:
+			return nil, fmt.Errorf("open keeper %v: invalid query parameter %q", u, param)
+		}
+	}
+	if kekURL == "" {
+		return nil, fmt.Errorf("open keeper %v: the kek query parameter is required", u)
+	}
+	data, err := os.ReadFile(path.Join(u.Host, u.Path))
+	if err != nil {
+		return nil, fmt.Errorf("open keeper %v: %v", u, err)
+	}
+	kek, err := secrets.OpenKeeper(ctx, kekURL)
+	if err != nil {
+		return nil, fmt.Errorf("open keeper %v: failed to open KEK: %v", u, err)
+	}
+	defer kek.Close()
+	h, err := ReadKeyset(ctx, data, kek)
+	if err != nil {
+		return nil, fmt.Errorf("open keeper %v: %v", u, err)
+	}
+	return OpenKeeper(h, &o.Options)
+}
+
+// KeeperOptions controls Keeper behaviors.
+type KeeperOptions struct {
+	// AssociatedData is authenticated, but not encrypted, with every
+	// ciphertext. Decrypt fails unless it is given the same AssociatedData
+	// as Encrypt.
+	AssociatedData []byte
+}
+
+// OpenKeeper returns a *secrets.Keeper that uses the AEAD primitive of the
+// keyset h. It returns an error if h is not an AEAD keyset.
+// See the package documentation for an example.
+func OpenKeeper(h *keyset.Handle, opts *KeeperOptions) (*secrets.Keeper, error) {
+	if opts == nil {
+		opts = &KeeperOptions{}
+	}
+	a, err := aead.New(h)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.NewKeeper(&keeper{aead: a, opts: *opts}), nil
+}
+
+// ReadKeyset decrypts a keyset, in Tink's JSON or binary format, that was
+// encrypted with the KEK held by kek, as done by WriteKeyset or by Tink's
+// tinkey tool with a KMS master key.
+func ReadKeyset(ctx context.Context, data []byte, kek *secrets.Keeper) (*keyset.Handle, error) {
+	var r keyset.Reader
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		r = keyset.NewJSONReader(bytes.NewReader(data))
+	} else {
+		r = keyset.NewBinaryReader(bytes.NewReader(data))
+	}
+	return keyset.Read(r, &kekAEAD{ctx: ctx, k: kek})
+}
+
+// WriteKeyset encrypts h with the KEK held by kek and returns it in Tink's
+// JSON format, suitable for storing in a blob.Bucket or a
+// runtimevar.Variable.
+func WriteKeyset(ctx context.Context, h *keyset.Handle, kek *secrets.Keeper) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := h.Write(keyset.NewJSONWriter(&buf), &kekAEAD{ctx: ctx, k: kek}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// kekAEAD adapts a *secrets.Keeper to tink.AEAD for encrypting keysets.
+type kekAEAD struct {
+	ctx context.Context
+	k   *secrets.Keeper
+}
+
+var _ tink.AEAD = (*kekAEAD)(nil)
+
+var errAssociatedData = errors.New("tinksecrets: a KEK Keeper does not support associated data")
+
+func (a *kekAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) > 0 {
+		return nil, errAssociatedData
+	}
+	return a.k.Encrypt(a.ctx, plaintext)
+}
+
+func (a *kekAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) > 0 {
+		return nil, errAssociatedData
+	}
+	return a.k.Decrypt(a.ctx, ciphertext)
+}
+
+// keeper implements driver.Keeper and driver.AADKeeper.
+type keeper struct {
+	aead tink.AEAD
+	opts KeeperOptions
+}
+
+// Encrypt encrypts the plaintext with the keyset's primary key.
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.aead.Encrypt(plaintext, k.opts.AssociatedData)
+}
+
+// Decrypt decrypts the ciphertext with the key of the keyset that encrypted it.
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.decrypt(ciphertext, k.opts.AssociatedData)
+}
+
+// EncryptWithAAD implements driver.AADKeeper.EncryptWithAAD. aad is passed
+// to Tink as the associated data, so KeeperOptions.AssociatedData must not
+// be set.
+func (k *keeper) EncryptWithAAD(ctx context.Context, plaintext, aad []byte) ([]byte, error) {
+	if len(k.opts.AssociatedData) > 0 {
+		return nil, errBothAssociatedData
+	}
+	return k.aead.Encrypt(plaintext, aad)
+}
+
+// DecryptWithAAD implements driver.AADKeeper.DecryptWithAAD.
+func (k *keeper) DecryptWithAAD(ctx context.Context, ciphertext, aad []byte) ([]byte, error) {
+	if len(k.opts.AssociatedData) > 0 {
+		return nil, errBothAssociatedData
+	}
+	return k.decrypt(ciphertext, aad)
+}
+
+var errBothAssociatedData = gcerr.Newf(gcerr.FailedPrecondition, nil, "tinksecrets: EncryptWithAAD and DecryptWithAAD can't be used with KeeperOptions.AssociatedData")
+
+func (k *keeper) decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	plaintext, err := k.aead.Decrypt(ciphertext, associatedData)
+	if err != nil {
+		// Tink deliberately doesn't say why decryption failed.
+		return nil, gcerr.Newf(gcerr.InvalidArgument, err, "tinksecrets: failed to decrypt")
+	}
+	return plaintext, nil
+}
+
+// Close implements driver.Keeper.Close.
+func (k *keeper) Close() error { return nil }
+
+// ErrorAs implements driver.Keeper.ErrorAs.
+func (k *keeper) ErrorAs(err error, i interface{}) bool {
+	return false
+}
+
+// ErrorCode implements driver.ErrorCode.
+func (k *keeper) ErrorCode(error) gcerrors.ErrorCode { return gcerrors.Unknown }
@@ -0,0 +1,261 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tinksecrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/secrets"
+	"gocloud.dev/secrets/driver"
+	"gocloud.dev/secrets/drivertest"
+	"gocloud.dev/secrets/localsecrets"
+)
+
+type harness struct{}
+
+func newDriver() (*keeper, error) {
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		return nil, err
+	}
+	a, err := aead.New(h)
+	if err != nil {
+		return nil, err
+	}
+	return &keeper{aead: a}, nil
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.Keeper, driver.Keeper, error) {
+	k1, err := newDriver()
+	if err != nil {
+		return nil, nil, err
+	}
+	k2, err := newDriver()
+	if err != nil {
+		return nil, nil, err
+	}
+	return k1, k2, nil
+}
+
+func (h *harness) Close() {}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	return &harness{}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness, []drivertest.AsTest{verifyAs{}})
+	drivertest.RunAADConformanceTests(t, newHarness)
+}
+
+type verifyAs struct{}
+
+func (v verifyAs) Name() string {
+	return "verify As function"
+}
+
+func (v verifyAs) ErrorCheck(k *secrets.Keeper, err error) error {
+	var s string
+	if k.ErrorAs(err, &s) {
+		return errors.New("Keeper.ErrorAs expected to fail")
+	}
+	return nil
+}
+
+// Tink-specific tests.
+
+func newKEK(t *testing.T) *secrets.Keeper {
+	t.Helper()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return localsecrets.NewKeeper(sk)
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	kek := newKEK(t)
+	defer kek.Close()
+
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := WriteKeyset(ctx, h, kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err = ReadKeyset(ctx, stored, kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := OpenKeeper(h, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer before.Close()
+	const plaintext = "hello world"
+	oldCiphertext, err := before.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate: add a new primary key, keeping the old one.
+	m := keyset.NewManagerFromHandle(h)
+	id, err := m.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetPrimary(id); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := m.Handle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := OpenKeeper(rotated, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer after.Close()
+
+	got, err := after.Decrypt(ctx, oldCiphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != plaintext {
+		t.Errorf("got %q want %q", got, plaintext)
+	}
+	newCiphertext, err := after.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := before.Decrypt(ctx, newCiphertext); gcerrors.Code(err) != gcerrors.InvalidArgument {
+		t.Errorf("Decrypt with the old keyset: got error %v, want InvalidArgument", err)
+	}
+}
+
+func TestReadKeysetWrongKEK(t *testing.T) {
+	ctx := context.Background()
+	kek1, kek2 := newKEK(t), newKEK(t)
+	defer kek1.Close()
+	defer kek2.Close()
+
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := WriteKeyset(ctx, h, kek1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadKeyset(ctx, stored, kek2); err == nil {
+		t.Error("got nil error, want error reading a keyset with the wrong KEK")
+	}
+}
+
+func TestAssociatedData(t *testing.T) {
+	ctx := context.Background()
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1, err := OpenKeeper(h, &KeeperOptions{AssociatedData: []byte("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k1.Close()
+	k2, err := OpenKeeper(h, &KeeperOptions{AssociatedData: []byte("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k2.Close()
+
+	ciphertext, err := k1.Encrypt(ctx, []byte("hello world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k1.Decrypt(ctx, ciphertext); err != nil {
+		t.Error(err)
+	}
+	if _, err := k2.Decrypt(ctx, ciphertext); err == nil {
+		t.Error("got nil error, want error decrypting with different associated data")
+	}
+}
+
+func TestOpenKeeper(t *testing.T) {
+	ctx := context.Background()
+	sk, err := localsecrets.NewRandomKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kekURL := "base64key://" + base64.URLEncoding.EncodeToString(sk[:])
+	kek, err := secrets.OpenKeeper(ctx, kekURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kek.Close()
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := WriteKeyset(ctx, h, kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keysetFile := filepath.Join(t.TempDir(), "keyset.json")
+	if err := os.WriteFile(keysetFile, stored, 0600); err != nil {
+		t.Fatal(err)
+	}
+	kekParam := url.QueryEscape(kekURL)
+
+	tests := []struct {
+		URL     string
+		WantErr bool
+	}{
+		// OK.
+		{"tink://" + keysetFile + "?kek=" + kekParam, false},
+		// Missing kek.
+		{"tink://" + keysetFile, true},
+		// Wrong kek.
+		{"tink://" + keysetFile + "?kek=base64key%3A%2F%2F", true},
+		// Missing keyset file.
+		{"tink://" + keysetFile + ".missing?kek=" + kekParam, true},
+		// Invalid parameter.
+		{"tink://" + keysetFile + "?kek=" + kekParam + "&param=value", true},
+	}
+
+	for _, test := range tests {
+		keeper, err := secrets.OpenKeeper(ctx, test.URL)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%s: got error %v, want error %v", test.URL, err, test.WantErr)
+		}
+		if err == nil {
+			if err = keeper.Close(); err != nil {
+				t.Errorf("%s: got error during close: %v", test.URL, err)
+			}
+		}
+	}
+}
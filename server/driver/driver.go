@@ -18,6 +18,7 @@ package driver // import "gocloud.dev/server/driver"
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 )
 
@@ -49,3 +50,13 @@ type TLSServer interface {
 	// See http://go/godoc/net/http/#Server.ListenAndServeTLS.
 	ListenAndServeTLS(addr, certFile, keyFile string, h http.Handler) error
 }
+
+// TLSConfigServer is an optional interface for Server drivers, that adds
+// support for serving TLS with certificates provided by a tls.Config, for
+// example through its GetCertificate field.
+type TLSConfigServer interface {
+	// ListenAndServeTLSConfig is similar to Server.ListenAndServe, but
+	// should serve using TLS configured by config. The config will always
+	// be non-nil, and must not be modified.
+	ListenAndServeTLSConfig(addr string, config *tls.Config, h http.Handler) error
+}
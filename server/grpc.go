@@ -0,0 +1,381 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"gocloud.dev/server/health"
+	"gocloud.dev/server/requestlog"
+)
+
+// defaultHealthCheckInterval is the default for
+// GRPCOptions.HealthCheckInterval.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// GRPCServer is a preconfigured gRPC server with the same diagnostic hooks
+// as Server: it serves the standard gRPC health service backed by health
+// checks, the server reflection service, OpenTelemetry tracing and metrics,
+// and request logging, and shuts down gracefully.
+//
+// Register services with RegisterService, or by passing the GRPCServer to
+// generated RegisterXServer functions, before calling ListenAndServe or
+// Serve.
+type GRPCServer struct {
+	srv      *grpc.Server
+	health   *grpchealth.Server
+	checks   []health.Checker
+	interval time.Duration
+	reqlog   requestlog.Logger
+
+	mu   sync.Mutex
+	stop chan struct{} // closed by Shutdown to stop polling health checks
+}
+
+// GRPCOptions is the set of optional parameters for NewGRPC.
+type GRPCOptions struct {
+	// RequestLogger specifies the logger that will be used to log calls.
+	// Calls to the health and reflection services are not logged.
+	RequestLogger requestlog.Logger
+
+	// HealthChecks specifies the health checks that determine the status
+	// reported by the grpc.health.v1.Health service for the server as a
+	// whole (the empty service name).
+	HealthChecks []health.Checker
+
+	// HealthCheckInterval is how often HealthChecks are run to update the
+	// status sent to Watch callers. Check always runs them. It defaults to
+	// 10 seconds.
+	HealthCheckInterval time.Duration
+
+	// DisableReflection turns off the server reflection service, which is
+	// registered by default so that tools such as grpcurl can discover the
+	// server's services.
+	DisableReflection bool
+
+	// OTelOptions are passed to otelgrpc.NewServerHandler. By default, the
+	// global OpenTelemetry tracer and meter providers are used.
+	OTelOptions []otelgrpc.Option
+
+	// ServerOptions are additional options for the underlying *grpc.Server,
+	// for example credentials or interceptors. Interceptors given here run
+	// inside the request logging interceptor.
+	ServerOptions []grpc.ServerOption
+}
+
+// NewGRPC creates a new gRPC server. opts may be nil.
+func NewGRPC(opts *GRPCOptions) *GRPCServer {
+	if opts == nil {
+		opts = &GRPCOptions{}
+	}
+	s := &GRPCServer{
+		health:   grpchealth.NewServer(),
+		checks:   opts.HealthChecks,
+		interval: opts.HealthCheckInterval,
+		reqlog:   opts.RequestLogger,
+		stop:     make(chan struct{}),
+	}
+	if s.interval <= 0 {
+		s.interval = defaultHealthCheckInterval
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(opts.OTelOptions...)),
+	}
+	if s.reqlog != nil {
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(s.logUnary),
+			grpc.ChainStreamInterceptor(s.logStream),
+		)
+	}
+	serverOpts = append(serverOpts, opts.ServerOptions...)
+	s.srv = grpc.NewServer(serverOpts...)
+	healthpb.RegisterHealthServer(s.srv, &healthService{Server: s.health, s: s})
+	if !opts.DisableReflection {
+		reflection.Register(s.srv)
+	}
+	return s
+}
+
+// RegisterService implements grpc.ServiceRegistrar.
+func (s *GRPCServer) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.srv.RegisterService(desc, impl)
+}
+
+// Server returns the underlying *grpc.Server.
+func (s *GRPCServer) Server() *grpc.Server {
+	return s.srv
+}
+
+// ListenAndServe listens on the TCP network address addr and serves gRPC
+// requests until Shutdown is called.
+func (s *GRPCServer) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve serves gRPC requests on l until Shutdown is called.
+func (s *GRPCServer) Serve(l net.Listener) error {
+	s.updateHealth()
+	if len(s.checks) > 0 {
+		go s.pollHealth()
+	}
+	return s.srv.Serve(l)
+}
+
+// Shutdown gracefully shuts down the server: it reports NOT_SERVING from
+// the health service, stops accepting new connections and waits for
+// pending calls to finish. If ctx is done first, remaining calls are
+// cancelled and Shutdown returns ctx.Err().
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// checkHealth runs the health checks and returns the resulting status.
+func (s *GRPCServer) checkHealth() healthpb.HealthCheckResponse_ServingStatus {
+	for _, c := range s.checks {
+		if err := c.CheckHealth(); err != nil {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+// updateHealth runs the health checks and records the result for the
+// server as a whole. It does nothing after Shutdown.
+func (s *GRPCServer) updateHealth() healthpb.HealthCheckResponse_ServingStatus {
+	st := s.checkHealth()
+	// After Shutdown, the health server ignores status updates.
+	s.health.SetServingStatus("", st)
+	return st
+}
+
+// pollHealth calls updateHealth periodically until Shutdown is called.
+func (s *GRPCServer) pollHealth() {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			s.updateHealth()
+		}
+	}
+}
+
+// healthService is the grpc.health.v1.Health service. Check runs the
+// health checks for the server as a whole; everything else is served by
+// the embedded health server.
+type healthService struct {
+	*grpchealth.Server
+	s *GRPCServer
+}
+
+// Check implements healthpb.HealthServer.Check.
+func (h *healthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() == "" {
+		select {
+		case <-h.s.stop:
+		default:
+			h.s.updateHealth()
+		}
+	}
+	return h.Server.Check(ctx, req)
+}
+
+// isDiagnostic reports whether fullMethod belongs to the health or
+// reflection services, which are not logged.
+func isDiagnostic(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/grpc.health.v1.") || strings.HasPrefix(fullMethod, "/grpc.reflection.")
+}
+
+func (s *GRPCServer) logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isDiagnostic(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ent := newGRPCEntry(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	ent.RequestBodySize = messageSize(req)
+	ent.ResponseBodySize = messageSize(resp)
+	s.finishEntry(ent, err)
+	return resp, err
+}
+
+func (s *GRPCServer) logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isDiagnostic(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ent := newGRPCEntry(ss.Context(), info.FullMethod)
+	cs := &countingStream{ServerStream: ss}
+	err := handler(srv, cs)
+	ent.RequestBodySize = cs.received
+	ent.ResponseBodySize = cs.sent
+	s.finishEntry(ent, err)
+	return err
+}
+
+// newGRPCEntry returns a request log entry for a call to fullMethod.
+// gRPC calls are HTTP/2 POST requests to the method's path, so Request,
+// RequestMethod and RequestURL describe that request.
+func newGRPCEntry(ctx context.Context, fullMethod string) *requestlog.Entry {
+	header := http.Header{}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, vs := range md {
+		for _, v := range vs {
+			header.Add(k, v)
+		}
+	}
+	r := (&http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     header,
+		RequestURI: fullMethod,
+	}).WithContext(ctx)
+	ent := &requestlog.Entry{
+		Request:       r,
+		ReceivedTime:  time.Now(),
+		RequestMethod: r.Method,
+		RequestURL:    fullMethod,
+		UserAgent:     header.Get("User-Agent"),
+		Proto:         r.Proto,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.RemoteAddr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ent.RemoteIP = host
+		}
+	}
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		ent.TraceID = trace.TraceID(sc.TraceID())
+		ent.SpanID = trace.SpanID(sc.SpanID())
+	}
+	return ent
+}
+
+// finishEntry fills in the status and latency of ent and logs it.
+func (s *GRPCServer) finishEntry(ent *requestlog.Entry, err error) {
+	ent.Latency = time.Since(ent.ReceivedTime)
+	ent.Status = httpStatus(status.Code(err))
+	s.reqlog.Log(ent)
+}
+
+// httpStatus maps a gRPC status code to the HTTP status code that
+// requestlog.Entry.Status reports for it, following the mapping used by
+// Google APIs (https://cloud.google.com/apis/design/errors).
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// messageSize returns the encoded size of m if it is a protocol buffer
+// message, and 0 otherwise.
+func messageSize(m interface{}) int64 {
+	if pm, ok := m.(proto.Message); ok {
+		return int64(proto.Size(pm))
+	}
+	return 0
+}
+
+// countingStream is a grpc.ServerStream that counts the encoded size of the
+// messages sent and received.
+type countingStream struct {
+	grpc.ServerStream
+	sent, received int64
+}
+
+func (cs *countingStream) SendMsg(m interface{}) error {
+	err := cs.ServerStream.SendMsg(m)
+	if err == nil {
+		cs.sent += messageSize(m)
+	}
+	return err
+}
+
+func (cs *countingStream) RecvMsg(m interface{}) error {
+	err := cs.ServerStream.RecvMsg(m)
+	if err == nil {
+		cs.received += messageSize(m)
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	testgrpc "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/status"
+
+	"gocloud.dev/server/health"
+	"gocloud.dev/server/requestlog"
+)
+
+type testService struct {
+	testgrpc.UnimplementedTestServiceServer
+	block chan struct{} // if non-nil, EmptyCall waits for it to be closed
+}
+
+func (s *testService) EmptyCall(ctx context.Context, _ *testgrpc.Empty) (*testgrpc.Empty, error) {
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &testgrpc.Empty{}, nil
+}
+
+func (s *testService) UnaryCall(ctx context.Context, req *testgrpc.SimpleRequest) (*testgrpc.SimpleResponse, error) {
+	return nil, status.Error(codes.NotFound, "no such thing")
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []*requestlog.Entry
+}
+
+func (l *recordingLogger) Log(ent *requestlog.Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, ent)
+}
+
+// startGRPC serves s on a local port and returns a connection to it.
+func startGRPC(t *testing.T, s *GRPCServer) *grpc.ClientConn {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(l)
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCHealth(t *testing.T) {
+	check := new(toggleChecker)
+	s := NewGRPC(&GRPCOptions{HealthChecks: []health.Checker{check}})
+	conn := startGRPC(t, s)
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	checkStatus := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.GetStatus(); got != want {
+			t.Errorf("got status %v, want %v", got, want)
+		}
+	}
+	checkStatus(healthpb.HealthCheckResponse_SERVING)
+	check.set(errors.New("unhealthy"))
+	checkStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	check.set(nil)
+	checkStatus(healthpb.HealthCheckResponse_SERVING)
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type toggleChecker struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (c *toggleChecker) set(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func (c *toggleChecker) CheckHealth() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func TestGRPCReflection(t *testing.T) {
+	const reflectionService = "grpc.reflection.v1.ServerReflection"
+	if _, ok := NewGRPC(nil).Server().GetServiceInfo()[reflectionService]; !ok {
+		t.Errorf("reflection service not registered by default")
+	}
+	if _, ok := NewGRPC(&GRPCOptions{DisableReflection: true}).Server().GetServiceInfo()[reflectionService]; ok {
+		t.Errorf("reflection service registered with DisableReflection")
+	}
+}
+
+func TestGRPCRequestLog(t *testing.T) {
+	logger := new(recordingLogger)
+	s := NewGRPC(&GRPCOptions{RequestLogger: logger})
+	testgrpc.RegisterTestServiceServer(s, &testService{})
+	conn := startGRPC(t, s)
+	ctx := context.Background()
+
+	client := testgrpc.NewTestServiceClient(conn)
+	if _, err := client.EmptyCall(ctx, &testgrpc.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UnaryCall(ctx, &testgrpc.SimpleRequest{ResponseSize: 10}); status.Code(err) != codes.NotFound {
+		t.Fatalf("UnaryCall: got error %v, want NotFound", err)
+	}
+	// Health checks are not logged.
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if len(logger.entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(logger.entries))
+	}
+	for i, want := range []struct {
+		url     string
+		status  int
+		reqSize int64
+	}{
+		{"/grpc.testing.TestService/EmptyCall", http.StatusOK, 0},
+		{"/grpc.testing.TestService/UnaryCall", http.StatusNotFound, 2},
+	} {
+		ent := logger.entries[i]
+		if ent.RequestURL != want.url || ent.Status != want.status || ent.RequestBodySize != want.reqSize {
+			t.Errorf("entry %d: got URL %q, status %d, request size %d; want %q, %d, %d", i, ent.RequestURL, ent.Status, ent.RequestBodySize, want.url, want.status, want.reqSize)
+		}
+		if ent.RequestMethod != http.MethodPost || ent.Proto != "HTTP/2.0" || ent.RemoteIP == "" || ent.UserAgent == "" {
+			t.Errorf("entry %d: incomplete entry %+v", i, ent)
+		}
+	}
+}
+
+func TestGRPCShutdown(t *testing.T) {
+	svc := &testService{block: make(chan struct{})}
+	s := NewGRPC(nil)
+	testgrpc.RegisterTestServiceServer(s, svc)
+	conn := startGRPC(t, s)
+	ctx := context.Background()
+
+	callErr := make(chan error)
+	go func() {
+		_, err := testgrpc.NewTestServiceClient(conn).EmptyCall(ctx, &testgrpc.Empty{}, grpc.WaitForReady(true))
+		callErr <- err
+	}()
+	// Wait for the call to reach the server.
+	for {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		if err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Shutdown cancels the pending call once its context is done.
+	shutdownCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown: got error %v, want context.DeadlineExceeded", err)
+	}
+	if err := <-callErr; err == nil {
+		t.Error("pending call succeeded after forced shutdown, want error")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cdkhealth provides health checks for Go CDK resources: blob
+// buckets, docstore collections, and pubsub topics and subscriptions. Use
+// them as readiness checks in server.Options.HealthChecks. For SQL
+// databases, see sqlhealth.NewPinger.
+//
+// Checks that call the service take a timeout; wrap them with
+// health.NewChecker to also cache their results.
+package cdkhealth // import "gocloud.dev/server/health/cdkhealth"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/docstore"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/server/health"
+)
+
+// checkFunc is a health.Checker that calls f with a context that is done
+// after timeout, if timeout is positive.
+type checkFunc struct {
+	timeout time.Duration
+	f       func(ctx context.Context) error
+}
+
+// CheckHealth implements health.Checker.
+func (c *checkFunc) CheckHealth() error {
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return c.f(ctx)
+}
+
+// Bucket returns a check that b is accessible, using b.IsAccessible. If
+// timeout is positive, the check fails if b does not respond in time.
+func Bucket(b *blob.Bucket, timeout time.Duration) health.Checker {
+	return &checkFunc{timeout: timeout, f: func(ctx context.Context) error {
+		ok, err := b.IsAccessible(ctx)
+		if err != nil {
+			return fmt.Errorf("cdkhealth: checking bucket: %w", err)
+		}
+		if !ok {
+			return errors.New("cdkhealth: bucket is not accessible")
+		}
+		return nil
+	}}
+}
+
+// Collection returns a check that c can be queried, by reading at most one
+// document from it. An empty collection is healthy. If timeout is positive,
+// the check fails if c does not respond in time.
+func Collection(c *docstore.Collection, timeout time.Duration) health.Checker {
+	return &checkFunc{timeout: timeout, f: func(ctx context.Context) error {
+		iter := c.Query().Limit(1).Get(ctx)
+		defer iter.Stop()
+		err := iter.Next(ctx, map[string]interface{}{})
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("cdkhealth: querying collection: %w", err)
+		}
+		return nil
+	}}
+}
+
+// Topic returns a check that t can still send messages. It does not call
+// the service: it fails once t has been Shutdown.
+func Topic(t *pubsub.Topic) health.Checker {
+	return health.CheckerFunc(func() error {
+		if err := t.Err(); err != nil {
+			return fmt.Errorf("cdkhealth: topic: %w", err)
+		}
+		return nil
+	})
+}
+
+// Subscription returns a check that s can still receive messages. It does
+// not call the service: it fails once s has been Shutdown or has failed
+// with an error that Receive cannot recover from.
+func Subscription(s *pubsub.Subscription) health.Checker {
+	return health.CheckerFunc(func() error {
+		if err := s.Err(); err != nil {
+			return fmt.Errorf("cdkhealth: subscription: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdkhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/docstore/memdocstore"
+	"gocloud.dev/pubsub/mempubsub"
+)
+
+func TestBucket(t *testing.T) {
+	b := memblob.OpenBucket(nil)
+	check := Bucket(b, time.Second)
+	if err := check.CheckHealth(); err != nil {
+		t.Errorf("open bucket: got %v, want healthy", err)
+	}
+	b.Close()
+	if err := check.CheckHealth(); err == nil {
+		t.Error("closed bucket: got healthy, want error")
+	}
+}
+
+func TestCollection(t *testing.T) {
+	ctx := context.Background()
+	coll, err := memdocstore.OpenCollection("name", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := Collection(coll, time.Second)
+	if err := check.CheckHealth(); err != nil {
+		t.Errorf("empty collection: got %v, want healthy", err)
+	}
+	if err := coll.Create(ctx, map[string]interface{}{"name": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := check.CheckHealth(); err != nil {
+		t.Errorf("non-empty collection: got %v, want healthy", err)
+	}
+	coll.Close()
+	if err := check.CheckHealth(); err == nil {
+		t.Error("closed collection: got healthy, want error")
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	ctx := context.Background()
+	topic := mempubsub.NewTopic()
+	sub := mempubsub.NewSubscription(topic, time.Minute)
+	topicCheck, subCheck := Topic(topic), Subscription(sub)
+	if err := topicCheck.CheckHealth(); err != nil {
+		t.Errorf("topic: got %v, want healthy", err)
+	}
+	if err := subCheck.CheckHealth(); err != nil {
+		t.Errorf("subscription: got %v, want healthy", err)
+	}
+	sub.Shutdown(ctx)
+	topic.Shutdown(ctx)
+	if err := topicCheck.CheckHealth(); err == nil {
+		t.Error("shut down topic: got healthy, want error")
+	}
+	if err := subCheck.CheckHealth(); err == nil {
+		t.Error("shut down subscription: got healthy, want error")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	check := &checkFunc{timeout: 10 * time.Millisecond, f: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	if err := check.CheckHealth(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
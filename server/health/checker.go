@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CheckerOptions is the set of optional parameters for NewChecker.
+type CheckerOptions struct {
+	// Timeout is how long a check may run before it is reported as
+	// failing. The check keeps running in the background, and its result
+	// is used once it finishes. If zero, checks are not timed out.
+	Timeout time.Duration
+
+	// CacheDuration is how long the result of a check is reused before
+	// the check runs again. If zero, each call runs the check, although
+	// concurrent calls share a single run.
+	CacheDuration time.Duration
+}
+
+// NewChecker returns a Checker that runs c with a timeout and caches its
+// results, as configured by opts. Use it for checks of slow or flaky
+// dependencies, so that health probes answer quickly and a dependency
+// that is briefly degraded does not make every probe fail. opts may be
+// nil.
+func NewChecker(c Checker, opts *CheckerOptions) Checker {
+	if opts == nil {
+		opts = &CheckerOptions{}
+	}
+	return &cachingChecker{c: c, timeout: opts.Timeout, ttl: opts.CacheDuration}
+}
+
+type cachingChecker struct {
+	c       Checker
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	err     error
+	checked time.Time     // when err was last set; zero if never
+	running chan struct{} // closed when the current run finishes; nil if none
+}
+
+// CheckHealth implements Checker.
+func (cc *cachingChecker) CheckHealth() error {
+	cc.mu.Lock()
+	if !cc.checked.IsZero() && time.Since(cc.checked) < cc.ttl {
+		defer cc.mu.Unlock()
+		return cc.err
+	}
+	if cc.running == nil {
+		done := make(chan struct{})
+		cc.running = done
+		go func() {
+			err := cc.c.CheckHealth()
+			cc.mu.Lock()
+			cc.err = err
+			cc.checked = time.Now()
+			cc.running = nil
+			cc.mu.Unlock()
+			close(done)
+		}()
+	}
+	running := cc.running
+	cc.mu.Unlock()
+
+	if cc.timeout > 0 {
+		t := time.NewTimer(cc.timeout)
+		defer t.Stop()
+		select {
+		case <-running:
+		case <-t.C:
+			return fmt.Errorf("health: check timed out after %v", cc.timeout)
+		}
+	} else {
+		<-running
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.err
+}
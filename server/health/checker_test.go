@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingChecker counts its calls, and blocks each one until release is
+// closed if it is non-nil.
+type countingChecker struct {
+	mu      sync.Mutex
+	calls   int
+	err     error
+	release chan struct{}
+}
+
+func (c *countingChecker) CheckHealth() error {
+	c.mu.Lock()
+	c.calls++
+	err, release := c.err, c.release
+	c.mu.Unlock()
+	if release != nil {
+		<-release
+	}
+	return err
+}
+
+func (c *countingChecker) numCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func TestNewCheckerCache(t *testing.T) {
+	cc := &countingChecker{err: errors.New("down")}
+	c := NewChecker(cc, &CheckerOptions{CacheDuration: time.Hour})
+	for i := 0; i < 3; i++ {
+		if err := c.CheckHealth(); err == nil {
+			t.Fatal("got nil error, want the check's error")
+		}
+	}
+	if got := cc.numCalls(); got != 1 {
+		t.Errorf("check ran %d times, want 1", got)
+	}
+
+	// Without caching, every call runs the check.
+	c = NewChecker(cc, nil)
+	c.CheckHealth()
+	c.CheckHealth()
+	if got := cc.numCalls(); got != 3 {
+		t.Errorf("check ran %d times, want 3", got)
+	}
+}
+
+func TestNewCheckerTimeout(t *testing.T) {
+	cc := &countingChecker{release: make(chan struct{})}
+	c := NewChecker(cc, &CheckerOptions{Timeout: 10 * time.Millisecond, CacheDuration: time.Hour})
+	if err := c.CheckHealth(); err == nil {
+		t.Fatal("got nil error from a hung check, want timeout error")
+	}
+	// A second call waits for the same run rather than starting another.
+	if err := c.CheckHealth(); err == nil {
+		t.Fatal("got nil error from a hung check, want timeout error")
+	}
+	if got := cc.numCalls(); got != 1 {
+		t.Errorf("check ran %d times, want 1", got)
+	}
+
+	// Once the check finishes, its result is used.
+	close(cc.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.CheckHealth() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("check still failing after it finished")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+// Names of the standard groups of checks.
+const (
+	// Liveness is the group of checks that fail when the process cannot
+	// recover by itself and should be restarted. It should not include
+	// checks of other services.
+	Liveness = "live"
+
+	// Readiness is the group of checks that fail when the process should
+	// temporarily stop receiving traffic, for example because a database
+	// it needs is unreachable.
+	Readiness = "ready"
+)
+
+// Groups is a set of named groups of checks, each of which is served by its
+// own Handler. The zero value has no groups. Groups is safe for concurrent
+// use.
+//
+// server.Server serves the Liveness and Readiness groups at /healthz/live
+// and /healthz/ready, matching the probes that orchestrators like
+// Kubernetes expect.
+type Groups struct {
+	mu       sync.Mutex
+	handlers map[string]*Handler
+}
+
+// Add adds c to the named group, creating the group if needed.
+func (g *Groups) Add(group string, c Checker) {
+	g.Handler(group).Add(c)
+}
+
+// Handler returns the Handler for the named group, creating an empty group,
+// which is always healthy, if needed.
+func (g *Groups) Handler(group string) *Handler {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.handlers == nil {
+		g.handlers = make(map[string]*Handler)
+	}
+	h := g.handlers[group]
+	if h == nil {
+		h = new(Handler)
+		g.handlers[group] = h
+	}
+	return h
+}
+
+// Names returns the names of the groups, in sorted order.
+func (g *Groups) Names() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	names := make([]string, 0, len(g.handlers))
+	for name := range g.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroups(t *testing.T) {
+	var g Groups
+	if names := g.Names(); len(names) != 0 {
+		t.Errorf("zero Groups has groups %v", names)
+	}
+	down := &checker{err: errors.New("down")}
+	g.Add(Readiness, down)
+	g.Add(Liveness, &checker{})
+	g.Add("db", down)
+
+	if diff := cmp.Diff([]string{"db", Liveness, Readiness}, g.Names()); diff != "" {
+		t.Errorf("Names: (-want +got)\n%s", diff)
+	}
+	if err := g.Handler(Liveness).CheckHealth(); err != nil {
+		t.Errorf("liveness: got error %v, want nil", err)
+	}
+	if err := g.Handler(Readiness).CheckHealth(); err == nil {
+		t.Error("readiness: got nil error, want error")
+	}
+	down.set(nil)
+	if err := g.Handler(Readiness).CheckHealth(); err != nil {
+		t.Errorf("readiness after recovery: got error %v, want nil", err)
+	}
+	// Unknown groups are created empty, and healthy.
+	if err := g.Handler("other").CheckHealth(); err != nil {
+		t.Errorf("empty group: got error %v, want nil", err)
+	}
+}
@@ -18,28 +18,46 @@ package health // import "gocloud.dev/server/health"
 import (
 	"io"
 	"net/http"
+	"sync"
 )
 
 // Handler is an HTTP handler that reports on the success of an
 // aggregate of Checkers.  The zero value is always healthy.
 type Handler struct {
+	mu       sync.RWMutex
 	checkers []Checker
 }
 
-// Add adds a new check to the handler.
+// Add adds a new check to the handler. It is safe to call while the
+// handler is serving requests.
 func (h *Handler) Add(c Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.checkers = append(h.checkers, c)
 }
 
 // ServeHTTP returns 200 if it is healthy, 500 otherwise.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
-	for _, c := range h.checkers {
+	if err := h.CheckHealth(); err != nil {
+		writeUnhealthy(w)
+		return
+	}
+	writeHealthy(w)
+}
+
+// CheckHealth returns the error of the first of the handler's checks that
+// fails, or nil if they all succeed. A Handler is therefore itself a
+// Checker.
+func (h *Handler) CheckHealth() error {
+	h.mu.RLock()
+	checkers := h.checkers
+	h.mu.RUnlock()
+	for _, c := range checkers {
 		if err := c.CheckHealth(); err != nil {
-			writeUnhealthy(w)
-			return
+			return err
 		}
 	}
-	writeHealthy(w)
+	return nil
 }
 
 func writeHeaders(statusLen string, w http.ResponseWriter) {
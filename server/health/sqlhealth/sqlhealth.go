@@ -98,3 +98,28 @@ func (c *Checker) Stop() {
 	c.cancel()
 	<-c.stopped
 }
+
+// Pinger checks the health of a SQL database by pinging it each time it is
+// checked. Unlike Checker, which only reports when a database has first
+// become reachable, it reports when a database stops responding.
+type Pinger struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPinger returns a new Pinger for db. If timeout is positive, a check
+// fails if the ping does not return within timeout.
+func NewPinger(db *sql.DB, timeout time.Duration) *Pinger {
+	return &Pinger{db: db, timeout: timeout}
+}
+
+// CheckHealth pings the database, returning the error from the ping.
+func (p *Pinger) CheckHealth() error {
+	ctx := context.Background()
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return p.db.PingContext(ctx)
+}
@@ -21,11 +21,15 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"gocloud.dev/server/health"
 )
 
-var _ = health.Checker((*Checker)(nil))
+var (
+	_ = health.Checker((*Checker)(nil))
+	_ = health.Checker((*Pinger)(nil))
+)
 
 func TestCheck(t *testing.T) {
 	connector := new(stubConnector)
@@ -47,6 +51,25 @@ func TestCheck(t *testing.T) {
 	}
 }
 
+func TestPinger(t *testing.T) {
+	connector := new(stubConnector)
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	check := NewPinger(db, time.Second)
+	if err := check.CheckHealth(); err == nil {
+		t.Error("unhealthy database: got healthy")
+	}
+	connector.setHealthy(true)
+	if err := check.CheckHealth(); err != nil {
+		t.Errorf("healthy database: got %v", err)
+	}
+	connector.setHealthy(false)
+	if err := check.CheckHealth(); err == nil {
+		t.Error("database that became unhealthy: got healthy")
+	}
+}
+
 type stubConnector struct {
 	mu      sync.RWMutex
 	healthy bool
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package http3driver provides a server driver that serves HTTP/3 over QUIC
+// alongside HTTP/1.1 and HTTP/2 over TCP, using quic-go. Responses sent
+// over TCP carry an Alt-Svc header, so that clients that support HTTP/3
+// switch to it.
+//
+// HTTP/3 support is experimental.
+//
+// Use it as the Driver in server.Options:
+//
+//	srv := server.New(h, &server.Options{Driver: http3driver.New()})
+//	err := srv.ListenAndServeTLS(":443", "cert.pem", "key.pem")
+package http3driver // import "gocloud.dev/server/http3driver"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"gocloud.dev/server/driver"
+)
+
+var (
+	_ driver.Server          = (*Driver)(nil)
+	_ driver.TLSServer       = (*Driver)(nil)
+	_ driver.TLSConfigServer = (*Driver)(nil)
+)
+
+// Driver implements driver.Server, driver.TLSServer and
+// driver.TLSConfigServer. HTTP/3 requires TLS, so ListenAndServe serves
+// only HTTP/1.1 over TCP.
+type Driver struct {
+	// Server serves HTTP/1.1 and HTTP/2 over TCP. Its Addr, Handler and
+	// TLSConfig are set when the driver starts serving.
+	Server http.Server
+	// QUICServer serves HTTP/3 over UDP, on the same port number as Server.
+	// Its Addr, Handler and TLSConfig are set when the driver starts
+	// serving. Set its Port to advertise a different port in Alt-Svc
+	// headers, for example when behind a load balancer.
+	QUICServer http3.Server
+}
+
+// New creates a driver whose TCP server has the same timeouts as
+// server.NewDefaultDriver.
+func New() *Driver {
+	return &Driver{
+		Server: http.Server{
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  120 * time.Second,
+		},
+	}
+}
+
+// ListenAndServe sets the address and handler on the driver's http.Server,
+// then calls ListenAndServe on it. It does not serve HTTP/3.
+func (d *Driver) ListenAndServe(addr string, h http.Handler) error {
+	d.Server.Addr = addr
+	d.Server.Handler = h
+	return d.Server.ListenAndServe()
+}
+
+// ListenAndServeTLS serves HTTP/3 on UDP and HTTPS on TCP at addr, using the
+// certificate in certFile and keyFile.
+func (d *Driver) ListenAndServeTLS(addr, certFile, keyFile string, h http.Handler) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	return d.ListenAndServeTLSConfig(addr, &tls.Config{Certificates: []tls.Certificate{cert}}, h)
+}
+
+// ListenAndServeTLSConfig serves HTTP/3 on UDP and HTTPS on TCP at addr,
+// using config. It returns when either server stops; after Shutdown, it
+// waits for both and returns http.ErrServerClosed.
+func (d *Driver) ListenAndServeTLSConfig(addr string, config *tls.Config, h http.Handler) error {
+	if addr == "" {
+		addr = ":https"
+	}
+	udpConn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer udpConn.Close()
+	tcpListener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	d.QUICServer.Addr = addr
+	d.QUICServer.Handler = h
+	d.QUICServer.TLSConfig = http3.ConfigureTLSConfig(config)
+	d.Server.Addr = addr
+	d.Server.TLSConfig = config
+	d.Server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Advertise HTTP/3. This only fails if there is no port to advertise.
+		d.QUICServer.SetQUICHeaders(w.Header())
+		h.ServeHTTP(w, r)
+	})
+
+	tcpErr := make(chan error, 1)
+	quicErr := make(chan error, 1)
+	go func() { tcpErr <- d.Server.ServeTLS(tcpListener, "", "") }()
+	go func() { quicErr <- d.QUICServer.Serve(udpConn) }()
+
+	var other chan error
+	var stopOther func() error
+	select {
+	case err = <-tcpErr:
+		other, stopOther = quicErr, d.QUICServer.Close
+	case err = <-quicErr:
+		other, stopOther = tcpErr, d.Server.Close
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		// Shutdown was called; let it finish stopping the other server.
+		<-other
+		return http.ErrServerClosed
+	}
+	stopOther()
+	<-other
+	return err
+}
+
+// Shutdown gracefully shuts down both servers, waiting for active requests
+// to finish until ctx is done.
+func (d *Driver) Shutdown(ctx context.Context) error {
+	quicErr := make(chan error, 1)
+	go func() { quicErr <- d.QUICServer.Shutdown(ctx) }()
+	err := d.Server.Shutdown(ctx)
+	return errors.Join(err, <-quicErr)
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http3driver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+func TestListenAndServeTLSConfig(t *testing.T) {
+	// Borrow httptest's self-signed certificate.
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	cert := ts.TLS.Certificates[0]
+	ts.Close()
+
+	// Pick a UDP port, and hope the TCP port with the same number is free.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := conn.LocalAddr().String()
+	conn.Close()
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	})
+	d := New()
+	served := make(chan error, 1)
+	go func() {
+		served <- d.ListenAndServeTLSConfig(addr, &tls.Config{Certificates: []tls.Certificate{cert}}, h)
+	}()
+
+	clientTLS := &tls.Config{InsecureSkipVerify: true}
+	get := func(client *http.Client) (*http.Response, string, error) {
+		var err error
+		// Retry until the servers are listening.
+		for i := 0; i < 50; i++ {
+			var resp *http.Response
+			resp, err = client.Get("https://" + addr + "/")
+			if err == nil {
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				return resp, string(body), err
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return nil, "", err
+	}
+
+	tcpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+	resp, body, err := get(tcpClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(body, "HTTP/1") {
+		t.Errorf("TCP request protocol = %q, want HTTP/1.x", body)
+	}
+	if got := resp.Header.Get("Alt-Svc"); !strings.Contains(got, "h3=") {
+		t.Errorf("Alt-Svc = %q, want it to advertise h3", got)
+	}
+
+	rt := &http3.RoundTripper{TLSClientConfig: clientTLS}
+	defer rt.Close()
+	_, body, err = get(&http.Client{Transport: rt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "HTTP/3.0" {
+		t.Errorf("QUIC request protocol = %q, want HTTP/3.0", body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("ListenAndServeTLSConfig returned %v, want http.ErrServerClosed", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelserver provides the diagnostic hooks for a server using
+// OpenTelemetry, exporting traces and metrics with OTLP over gRPC.
+//
+// The exporters are configured with the standard OpenTelemetry environment
+// variables, for example OTEL_EXPORTER_OTLP_ENDPOINT and
+// OTEL_EXPORTER_OTLP_HEADERS, and the service is identified by
+// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES.
+package otelserver // import "gocloud.dev/server/otelserver"
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/wire"
+	"gocloud.dev/server"
+	"gocloud.dev/server/driver"
+	"gocloud.dev/server/requestlog"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Set is a Wire provider set that provides the diagnostic hooks for
+// *server.Server given a context.Context, which is used to create the
+// exporters. It replaces server.Set.
+var Set = wire.NewSet(
+	server.New,
+	wire.Struct(new(server.Options), "RequestLogger", "HealthChecks", "TracerProvider", "MeterProvider", "Driver"),
+	wire.Value(&server.DefaultDriver{}),
+	wire.Bind(new(driver.Server), new(*server.DefaultDriver)),
+	NewTracerProvider,
+	wire.Bind(new(trace.TracerProvider), new(*sdktrace.TracerProvider)),
+	NewMeterProvider,
+	wire.Bind(new(metric.MeterProvider), new(*sdkmetric.MeterProvider)),
+	NewRequestLogger,
+	wire.Bind(new(requestlog.Logger), new(*requestlog.NCSALogger)),
+)
+
+// NewTracerProvider returns a tracer provider that exports spans in batches
+// with OTLP over gRPC. Sampling follows the parent span, and samples new
+// traces unless OTEL_TRACES_SAMPLER says otherwise.
+//
+// The second return value is a Wire cleanup function that calls Shutdown
+// on the provider, flushing any pending spans and ignoring the error.
+func NewTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, func(), error) {
+	exp, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.Default()),
+	)
+	return tp, func() { tp.Shutdown(context.Background()) }, nil
+}
+
+// NewMeterProvider returns a meter provider that exports metrics
+// periodically with OTLP over gRPC.
+//
+// The second return value is a Wire cleanup function that calls Shutdown
+// on the provider, flushing any pending metrics and ignoring the error.
+func NewMeterProvider(ctx context.Context) (*sdkmetric.MeterProvider, func(), error) {
+	exp, err := otlpmetricgrpc.New(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)),
+		sdkmetric.WithResource(resource.Default()),
+	)
+	return mp, func() { mp.Shutdown(context.Background()) }, nil
+}
+
+// NewRequestLogger returns a request logger that sends entries to stdout.
+func NewRequestLogger() *requestlog.NCSALogger {
+	return requestlog.NewNCSALogger(os.Stdout, func(e error) { fmt.Fprintln(os.Stderr, e) })
+}
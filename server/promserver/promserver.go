@@ -0,0 +1,129 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promserver exposes a server's metrics for Prometheus to scrape.
+// The Exporter it provides serves the Go runtime and process metrics,
+// the metrics recorded with its OpenTelemetry MeterProvider, and the
+// OpenCensus views that are registered, such as those in ocgrpc or the
+// Go CDK's own packages.
+//
+// To serve the metrics at /metrics, alongside the standard HTTP server
+// metrics:
+//
+//	exp, err := promserver.New(nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer exp.Shutdown(context.Background())
+//	srv := server.New(h, &server.Options{
+//		MeterProvider:  exp.MeterProvider(),
+//		MetricsHandler: exp,
+//	})
+package promserver // import "gocloud.dev/server/promserver"
+
+import (
+	"context"
+	"net/http"
+
+	ocprom "contrib.go.opencensus.io/exporter/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Options is the set of optional parameters for New.
+type Options struct {
+	// Registry is the registry that metrics are collected into and served
+	// from. If nil, New creates one with the Go runtime and process
+	// collectors registered.
+	Registry *prometheus.Registry
+
+	// Namespace is prefixed to the names of the OpenTelemetry and
+	// OpenCensus metrics, separated by an underscore.
+	Namespace string
+
+	// DisableOpenCensus stops the registered OpenCensus views from being
+	// served.
+	DisableOpenCensus bool
+}
+
+// Exporter serves metrics in the Prometheus exposition format. It
+// implements http.Handler.
+type Exporter struct {
+	registry *prometheus.Registry
+	mp       *sdkmetric.MeterProvider
+	handler  http.Handler
+}
+
+// New returns a new Exporter. opts may be nil.
+func New(opts *Options) (*Exporter, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	reg := opts.Registry
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+		if err := reg.Register(collectors.NewGoCollector()); err != nil {
+			return nil, err
+		}
+		if err := reg.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+			return nil, err
+		}
+	}
+	reader, err := otelprom.New(otelprom.WithRegisterer(reg), otelprom.WithNamespace(opts.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DisableOpenCensus {
+		// The exporter registers a collector that reads the views when the
+		// registry is gathered.
+		if _, err := ocprom.NewExporter(ocprom.Options{Registry: reg, Namespace: opts.Namespace}); err != nil {
+			return nil, err
+		}
+	}
+	return &Exporter{
+		registry: reg,
+		mp: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+			sdkmetric.WithResource(resource.Default()),
+		),
+		handler: promhttp.HandlerFor(reg, promhttp.HandlerOpts{}),
+	}, nil
+}
+
+// MeterProvider returns the meter provider whose metrics the exporter
+// serves. Pass it as server.Options.MeterProvider to serve the standard
+// HTTP server metrics.
+func (e *Exporter) MeterProvider() *sdkmetric.MeterProvider {
+	return e.mp
+}
+
+// Registry returns the registry the exporter serves, for registering
+// additional Prometheus collectors.
+func (e *Exporter) Registry() *prometheus.Registry {
+	return e.registry
+}
+
+// ServeHTTP serves the metrics.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.handler.ServeHTTP(w, r)
+}
+
+// Shutdown shuts down the exporter's meter provider.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return e.mp.Shutdown(ctx)
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"gocloud.dev/server"
+)
+
+// testDriver records the handler it is asked to serve.
+type testDriver struct {
+	handler http.Handler
+}
+
+func (td *testDriver) ListenAndServe(addr string, h http.Handler) error {
+	td.handler = h
+	return nil
+}
+
+func (td *testDriver) Shutdown(ctx context.Context) error { return nil }
+
+func TestExporter(t *testing.T) {
+	exp, err := New(&Options{Namespace: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exp.Shutdown(context.Background())
+
+	m := stats.Int64("promserver_test/count", "A test count", stats.UnitDimensionless)
+	v := &view.View{Name: "promserver_test/count", Measure: m, Aggregation: view.Count()}
+	if err := view.Register(v); err != nil {
+		t.Fatal(err)
+	}
+	defer view.Unregister(v)
+	stats.Record(context.Background(), m.M(1))
+
+	td := new(testDriver)
+	srv := server.New(http.NotFoundHandler(), &server.Options{
+		Driver:         td,
+		MeterProvider:  exp.MeterProvider(),
+		MetricsHandler: exp,
+	})
+	if err := srv.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	td.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	// OpenCensus records measurements asynchronously, so scrape until the
+	// view has data.
+	const ocMetric = "test_promserver_test_count"
+	var body string
+	for i := 0; i < 100 && !strings.Contains(body, ocMetric); i++ {
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		rr := httptest.NewRecorder()
+		td.handler.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET /metrics: got status %d, want %d", rr.Code, http.StatusOK)
+		}
+		body = rr.Body.String()
+	}
+	for _, want := range []string{
+		"go_goroutines",             // Go runtime
+		"test_http_server_duration", // OpenTelemetry, from the server
+		ocMetric,                    // OpenCensus
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not include %q:\n%s", want, body)
+		}
+	}
+}
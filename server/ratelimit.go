@@ -0,0 +1,184 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitOptions configures request rate limiting with token buckets.
+// See Options.RateLimit.
+//
+// A request is rejected with status 429 (Too Many Requests) and a
+// Retry-After header if it exceeds either the global limit or its client's
+// limit. Health checks are never limited.
+type RateLimitOptions struct {
+	// Rate is the number of requests per second allowed across all
+	// clients. If zero, there is no global limit.
+	Rate float64
+	// Burst is the number of requests allowed at once across all clients,
+	// the size of the global token bucket. It defaults to Rate, rounded up.
+	Burst int
+
+	// PerClientRate is the number of requests per second allowed for each
+	// client. If zero, there is no per-client limit.
+	PerClientRate float64
+	// PerClientBurst is the number of requests each client may make at
+	// once. It defaults to PerClientRate, rounded up.
+	PerClientBurst int
+	// ClientKey returns the key identifying the client that sent r. It
+	// defaults to the IP address of r.RemoteAddr. Behind a proxy, use a
+	// function that reads the client address from a header the proxy sets.
+	ClientKey func(r *http.Request) string
+}
+
+// defaultBurst returns the burst to use for rate r if none is configured.
+func defaultBurst(r float64, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return int(math.Ceil(r))
+}
+
+// rateLimiter rejects requests that exceed its limits.
+type rateLimiter struct {
+	global *rate.Limiter // nil if there is no global limit
+
+	perClientRate  rate.Limit // 0 if there is no per-client limit
+	perClientBurst int
+	clientKey      func(*http.Request) string
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newRateLimiter(opts *RateLimitOptions) *rateLimiter {
+	rl := &rateLimiter{
+		clientKey: opts.ClientKey,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+	if opts.Rate > 0 {
+		rl.global = rate.NewLimiter(rate.Limit(opts.Rate), defaultBurst(opts.Rate, opts.Burst))
+	}
+	if opts.PerClientRate > 0 {
+		rl.perClientRate = rate.Limit(opts.PerClientRate)
+		rl.perClientBurst = defaultBurst(opts.PerClientRate, opts.PerClientBurst)
+	}
+	if rl.clientKey == nil {
+		rl.clientKey = remoteIP
+	}
+	return rl
+}
+
+// remoteIP returns the IP address of the client that sent r.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// wrap returns a handler that calls h for requests within the limits.
+func (rl *rateLimiter) wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay, ok := rl.allow(r, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// allow reports whether r is within the limits at now. If it is not, it
+// returns how long the client should wait before retrying.
+func (rl *rateLimiter) allow(r *http.Request, now time.Time) (time.Duration, bool) {
+	var reservations []*rate.Reservation
+	if rl.perClientRate > 0 {
+		reservations = append(reservations, rl.client(rl.clientKey(r), now).ReserveN(now, 1))
+	}
+	if rl.global != nil {
+		reservations = append(reservations, rl.global.ReserveN(now, 1))
+	}
+	var delay time.Duration
+	for _, res := range reservations {
+		if !res.OK() {
+			// The burst is zero; try again in a second.
+			delay = time.Second
+			continue
+		}
+		if d := res.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+	if delay == 0 {
+		return 0, true
+	}
+	// Return the tokens, so that rejected requests don't count against the
+	// limits.
+	for _, res := range reservations {
+		res.CancelAt(now)
+	}
+	return delay, false
+}
+
+// client returns the limiter for the client with the given key.
+func (rl *rateLimiter) client(key string, now time.Time) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.sweep(now)
+	c := rl.clients[key]
+	if c == nil {
+		c = &clientLimiter{limiter: rate.NewLimiter(rl.perClientRate, rl.perClientBurst)}
+		rl.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter
+}
+
+// sweep forgets clients whose token buckets have refilled, since a full
+// bucket behaves the same as a new one. It runs at most once a minute, or
+// as often as a bucket takes to refill if that is longer.
+func (rl *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(rl.perClientBurst) / float64(rl.perClientRate) * float64(time.Second))
+	interval := time.Minute
+	if refill > interval {
+		interval = refill
+	}
+	if now.Sub(rl.lastSweep) < interval {
+		return
+	}
+	rl.lastSweep = now
+	for key, c := range rl.clients {
+		if now.Sub(c.lastSeen) >= refill {
+			delete(rl.clients, key)
+		}
+	}
+}
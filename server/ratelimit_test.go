@@ -0,0 +1,114 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func requestFrom(addr string) *http.Request {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = addr
+	return r
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	rl := newRateLimiter(&RateLimitOptions{PerClientRate: 1, PerClientBurst: 2})
+	now := time.Now()
+	a, b := requestFrom("10.0.0.1:1234"), requestFrom("10.0.0.2:1234")
+
+	for i := 0; i < 2; i++ {
+		if _, ok := rl.allow(a, now); !ok {
+			t.Fatalf("request %d from a was rejected within its burst", i)
+		}
+	}
+	delay, ok := rl.allow(a, now)
+	if ok {
+		t.Fatal("request from a beyond its burst was allowed")
+	}
+	if delay <= 0 || delay > time.Second {
+		t.Errorf("got retry delay %v, want (0, 1s]", delay)
+	}
+	// Other clients, and other ports of the same client, have their own
+	// buckets.
+	if _, ok := rl.allow(b, now); !ok {
+		t.Error("request from b was rejected")
+	}
+	if _, ok := rl.allow(requestFrom("10.0.0.1:5678"), now); ok {
+		t.Error("request from a's IP address on another port was allowed")
+	}
+	// Rejected requests don't use up tokens, so a refills after a second.
+	if _, ok := rl.allow(a, now.Add(time.Second)); !ok {
+		t.Error("request from a was rejected after its bucket refilled")
+	}
+
+	// Clients with full buckets are forgotten.
+	rl.client("sweep", now.Add(time.Hour))
+	if _, ok := rl.clients["10.0.0.1"]; ok {
+		t.Error("idle client was not forgotten")
+	}
+}
+
+func TestRateLimitGlobal(t *testing.T) {
+	rl := newRateLimiter(&RateLimitOptions{Rate: 10, PerClientRate: 10, PerClientBurst: 10})
+	now := time.Now()
+	// The global burst defaults to the rate, so clients share 10 requests.
+	for i := 0; i < 10; i++ {
+		if _, ok := rl.allow(requestFrom("10.0.0.1:1234"), now); !ok {
+			t.Fatalf("request %d was rejected within the global burst", i)
+		}
+	}
+	if _, ok := rl.allow(requestFrom("10.0.0.2:1234"), now); ok {
+		t.Error("request beyond the global burst was allowed")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	td := new(testDriver)
+	s := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), &Options{
+		Driver: td,
+		RateLimit: &RateLimitOptions{
+			PerClientRate: 0.1,
+			ClientKey:     func(r *http.Request) string { return r.Header.Get("X-Client") },
+		},
+	})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	serve := func(path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("X-Client", "c")
+		rr := httptest.NewRecorder()
+		td.handler.ServeHTTP(rr, r)
+		return rr
+	}
+	if rr := serve("/"); rr.Code != http.StatusOK {
+		t.Fatalf("first request: got status %d, want %d", rr.Code, http.StatusOK)
+	}
+	rr := serve("/")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: got status %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("got Retry-After %q, want %q", got, "10")
+	}
+	// Health checks are not limited.
+	if rr := serve("/healthz/ready"); rr.Code != http.StatusOK {
+		t.Errorf("health check: got status %d, want %d", rr.Code, http.StatusOK)
+	}
+}
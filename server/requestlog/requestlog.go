@@ -18,6 +18,7 @@ package requestlog // import "gocloud.dev/server/requestlog"
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"io"
 	"net"
@@ -26,6 +27,7 @@ import (
 	"time"
 
 	"go.opencensus.io/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // Logger wraps the Log method.  Log must be safe to call from multiple
@@ -53,7 +55,7 @@ func NewHandler(log Logger, h http.Handler) *Handler {
 // Log after the handler returns.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
-	sc := trace.FromContext(r.Context()).SpanContext()
+	sc := spanContext(r.Context())
 	ent := &Entry{
 		Request:           cloneRequestWithoutBody(r),
 		ReceivedTime:      start,
@@ -94,6 +96,18 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.log.Log(ent)
 }
 
+// spanContext returns the span context of the current span in ctx. An
+// OpenTelemetry span takes precedence over an OpenCensus span.
+func spanContext(ctx context.Context) trace.SpanContext {
+	if osc := oteltrace.SpanContextFromContext(ctx); osc.IsValid() {
+		return trace.SpanContext{
+			TraceID: trace.TraceID(osc.TraceID()),
+			SpanID:  trace.SpanID(osc.SpanID()),
+		}
+	}
+	return trace.FromContext(ctx).SpanContext()
+}
+
 func cloneRequestWithoutBody(r *http.Request) *http.Request {
 	r = r.Clone(r.Context())
 	r.Body = nil
@@ -114,8 +128,11 @@ type Entry struct {
 	ResponseHeaderSize int64
 	ResponseBodySize   int64
 	Latency            time.Duration
-	TraceID            trace.TraceID
-	SpanID             trace.SpanID
+	// TraceID and SpanID identify the request's span. They are taken from
+	// the OpenTelemetry span in the request's context if there is one, and
+	// from the OpenCensus span otherwise.
+	TraceID trace.TraceID
+	SpanID  trace.SpanID
 
 	// Deprecated. This value is available by evaluating Request.Referer().
 	Referer string
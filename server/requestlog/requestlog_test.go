@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"go.opencensus.io/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 func TestHandler(t *testing.T) {
@@ -92,6 +93,28 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandlerOpenTelemetry(t *testing.T) {
+	osc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     oteltrace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+	// The OpenTelemetry span takes precedence over the OpenCensus span.
+	ctx, span := trace.StartSpan(context.Background(), "test")
+	defer span.End()
+	ctx = oteltrace.ContextWithSpanContext(ctx, osc)
+	r := httptest.NewRequest("GET", "/foo", nil).WithContext(ctx)
+
+	capture := new(captureLogger)
+	NewHandler(capture, http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), r)
+	if got, want := capture.ent.TraceID, trace.TraceID(osc.TraceID()); got != want {
+		t.Errorf("TraceID = %v; want %v", got, want)
+	}
+	if got, want := capture.ent.SpanID, trace.SpanID(osc.SpanID()); got != want {
+		t.Errorf("SpanID = %v; want %v", got, want)
+	}
+}
+
 type testSpanHandler struct {
 	h       http.Handler
 	spanCtx *trace.SpanContext
@@ -0,0 +1,196 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requestlog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opencensus.io/trace"
+)
+
+// redacted replaces the values of redacted fields.
+const redacted = "REDACTED"
+
+// A SlogLogger writes log entries as structured records to a *slog.Logger.
+// Each record has the entry's fields in an "httpRequest" group, and
+// "trace_id" and "span_id" attributes if the request was traced.
+//
+// Records are logged with the request's context, so slog handlers that
+// read the context see the request's span. To export entries as
+// OpenTelemetry logs with OTLP, use a logger whose handler is an
+// OpenTelemetry slog bridge, such as the one in
+// go.opentelemetry.io/contrib/bridges/otelslog.
+type SlogLogger struct {
+	l            *slog.Logger
+	msg          string
+	level        func(*Entry) slog.Level
+	headers      []string
+	redactFields map[string]bool
+	redactQuery  map[string]bool
+}
+
+// SlogOptions is the set of optional parameters for NewSlogLogger.
+type SlogOptions struct {
+	// Message is the message of each record. It defaults to "request".
+	Message string
+
+	// Level returns the level of the record for an entry. By default,
+	// entries with a 5xx status are logged at slog.LevelError, and others
+	// at slog.LevelInfo.
+	Level func(*Entry) slog.Level
+
+	// Headers lists request headers to log, in a "headers" group within
+	// "httpRequest".
+	Headers []string
+
+	// RedactFields lists fields whose values are replaced with "REDACTED":
+	// names of attributes in the "httpRequest" group, such as "remoteIp"
+	// or "userAgent", and names of headers in Headers, which are matched
+	// case-insensitively.
+	RedactFields []string
+
+	// RedactQueryParams lists query parameters whose values are replaced
+	// with "REDACTED" in "requestUrl", for example API keys or tokens.
+	RedactQueryParams []string
+}
+
+// NewSlogLogger returns a new logger that writes to l. opts may be nil.
+func NewSlogLogger(l *slog.Logger, opts *SlogOptions) *SlogLogger {
+	if opts == nil {
+		opts = &SlogOptions{}
+	}
+	sl := &SlogLogger{
+		l:            l,
+		msg:          opts.Message,
+		level:        opts.Level,
+		redactFields: make(map[string]bool),
+		redactQuery:  make(map[string]bool),
+	}
+	if sl.msg == "" {
+		sl.msg = "request"
+	}
+	if sl.level == nil {
+		sl.level = defaultLevel
+	}
+	for _, h := range opts.Headers {
+		sl.headers = append(sl.headers, http.CanonicalHeaderKey(h))
+	}
+	for _, f := range opts.RedactFields {
+		sl.redactFields[f] = true
+		sl.redactFields[http.CanonicalHeaderKey(f)] = true
+	}
+	for _, p := range opts.RedactQueryParams {
+		sl.redactQuery[p] = true
+	}
+	return sl
+}
+
+// NewJSONLogger returns a new logger that writes each entry to w as a JSON
+// object on its own line, using slog.NewJSONHandler. opts may be nil.
+func NewJSONLogger(w io.Writer, opts *SlogOptions) *SlogLogger {
+	return NewSlogLogger(slog.New(slog.NewJSONHandler(w, nil)), opts)
+}
+
+func defaultLevel(ent *Entry) slog.Level {
+	if ent.Status >= 500 {
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// Log writes a record for ent to its slog.Logger.
+func (l *SlogLogger) Log(ent *Entry) {
+	ctx := context.Background()
+	if ent.Request != nil {
+		ctx = ent.Request.Context()
+	}
+	level := l.level(ent)
+	if !l.l.Enabled(ctx, level) {
+		return
+	}
+	req := []slog.Attr{
+		l.str("requestMethod", ent.RequestMethod),
+		l.str("requestUrl", l.redactURL(ent.RequestURL)),
+		slog.Int64("requestSize", ent.RequestHeaderSize+ent.RequestBodySize),
+		slog.Int("status", ent.Status),
+		slog.Int64("responseSize", ent.ResponseHeaderSize+ent.ResponseBodySize),
+		slog.Duration("latency", ent.Latency),
+		l.str("userAgent", ent.UserAgent),
+		l.str("remoteIp", ent.RemoteIP),
+		l.str("serverIp", ent.ServerIP),
+		l.str("referer", ent.Referer),
+		l.str("protocol", ent.Proto),
+	}
+	if len(l.headers) > 0 && ent.Request != nil {
+		var headers []any
+		for _, h := range l.headers {
+			if v := ent.Request.Header.Values(h); len(v) > 0 {
+				headers = append(headers, l.str(h, strings.Join(v, ", ")))
+			}
+		}
+		req = append(req, slog.Group("headers", headers...))
+	}
+	attrs := []slog.Attr{
+		slog.Any("httpRequest", slog.GroupValue(req...)),
+		slog.Time("receivedTime", ent.ReceivedTime),
+	}
+	if ent.TraceID != (trace.TraceID{}) {
+		attrs = append(attrs,
+			slog.String("trace_id", ent.TraceID.String()),
+			slog.String("span_id", ent.SpanID.String()),
+		)
+	}
+	l.l.LogAttrs(ctx, level, l.msg, attrs...)
+}
+
+// str returns a string attribute, redacting its value if the key is in
+// RedactFields.
+func (l *SlogLogger) str(key, value string) slog.Attr {
+	if l.redactFields[key] && value != "" {
+		value = redacted
+	}
+	return slog.String(key, value)
+}
+
+// redactURL redacts the values of the query parameters in
+// RedactQueryParams from u.
+func (l *SlogLogger) redactURL(u string) string {
+	if len(l.redactQuery) == 0 || !strings.Contains(u, "?") {
+		return u
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		// Don't risk logging a secret from a URL we can't parse.
+		return redacted
+	}
+	q := parsed.Query()
+	changed := false
+	for p := range q {
+		if l.redactQuery[p] {
+			q[p] = []string{redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return u
+	}
+	parsed.RawQuery = q.Encode()
+	return parsed.String()
+}
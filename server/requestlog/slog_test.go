@@ -0,0 +1,108 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requestlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/trace"
+)
+
+func TestJSONLogger(t *testing.T) {
+	ctx, span := trace.StartSpan(context.Background(), "test")
+	defer span.End()
+	sc := trace.FromContext(ctx).SpanContext()
+	r := httptest.NewRequest("GET", "/foo?key=secret&page=2", nil)
+	r.Header.Set("X-Api-Key", "secret")
+	r.Header.Set("X-Request-Id", "abc")
+
+	buf := new(bytes.Buffer)
+	l := NewJSONLogger(buf, &SlogOptions{
+		Headers:           []string{"x-request-id", "X-Api-Key"},
+		RedactFields:      []string{"remoteIp", "x-api-key"},
+		RedactQueryParams: []string{"key"},
+	})
+	ent := &Entry{
+		Request:            r,
+		ReceivedTime:       time.Unix(1507914000, 0),
+		RequestMethod:      "GET",
+		RequestURL:         "/foo?key=secret&page=2",
+		RequestHeaderSize:  100,
+		RequestBodySize:    23,
+		Status:             503,
+		ResponseHeaderSize: 50,
+		ResponseBodySize:   7,
+		Latency:            time.Second,
+		UserAgent:          "test",
+		RemoteIP:           "12.34.56.78",
+		Proto:              "HTTP/1.1",
+		TraceID:            sc.TraceID,
+		SpanID:             sc.SpanID,
+	}
+	l.Log(ent)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal %q: %v", buf.String(), err)
+	}
+	delete(got, "time")
+	want := map[string]interface{}{
+		"level":        "ERROR",
+		"msg":          "request",
+		"receivedTime": "2017-10-13T17:00:00Z",
+		"trace_id":     sc.TraceID.String(),
+		"span_id":      sc.SpanID.String(),
+		"httpRequest": map[string]interface{}{
+			"requestMethod": "GET",
+			"requestUrl":    "/foo?key=REDACTED&page=2",
+			"requestSize":   float64(123),
+			"status":        float64(503),
+			"responseSize":  float64(57),
+			"latency":       float64(time.Second),
+			"userAgent":     "test",
+			"remoteIp":      "REDACTED",
+			"serverIp":      "",
+			"referer":       "",
+			"protocol":      "HTTP/1.1",
+			"headers": map[string]interface{}{
+				"X-Request-Id": "abc",
+				"X-Api-Key":    "REDACTED",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("record: (-want +got)\n%s", diff)
+	}
+}
+
+func TestSlogLoggerLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})), nil)
+	l.Log(&Entry{Status: 200})
+	if buf.Len() != 0 {
+		t.Errorf("entry below the handler's level was logged: %s", buf)
+	}
+	l.Log(&Entry{Status: 500})
+	if buf.Len() == 0 {
+		t.Error("entry with status 500 was not logged")
+	}
+}
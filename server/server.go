@@ -17,6 +17,7 @@ package server // import "gocloud.dev/server"
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"sync"
@@ -29,6 +30,12 @@ import (
 
 	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/trace"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // Set is a Wire provider set that produces a *Server given the fields of
@@ -46,11 +53,25 @@ type Server struct {
 	reqlog         requestlog.Logger
 	handler        http.Handler
 	wrappedHandler http.Handler
-	healthHandler  health.Handler
+	health         health.Groups
 	te             trace.Exporter
 	sampler        trace.Sampler
+	tp             oteltrace.TracerProvider
+	mp             metric.MeterProvider
+	propagators    propagation.TextMapPropagator
 	once           sync.Once
 	driver         driver.Server
+	drainTimeout   time.Duration
+	tlsConfig      *tls.Config
+	rateLimit      *RateLimitOptions
+	h2c            bool
+	metrics        http.Handler
+	inFlight       inFlightCounter
+
+	mu        sync.Mutex
+	draining  bool
+	preDrain  []ShutdownHook
+	postDrain []ShutdownHook
 }
 
 // Options is the set of optional parameters.
@@ -59,19 +80,73 @@ type Options struct {
 	RequestLogger requestlog.Logger
 
 	// HealthChecks specifies the health checks to be run when the
-	// /healthz/readiness endpoint is requested.
+	// /healthz/ready (or /healthz/readiness) endpoint is requested.
 	HealthChecks []health.Checker
 
-	// TraceExporter exports sampled trace spans.
+	// LivenessChecks specifies the health checks to be run when the
+	// /healthz/live (or /healthz/liveness) endpoint is requested. Without
+	// any, the endpoint always reports the server as healthy.
+	LivenessChecks []health.Checker
+
+	// HealthCheckGroups specifies additional named groups of health checks,
+	// each served at /healthz/<name>. Checks in the health.Liveness and
+	// health.Readiness groups are added to LivenessChecks and HealthChecks.
+	// Use health.NewChecker to give checks timeouts and cached results.
+	HealthCheckGroups map[string][]health.Checker
+
+	// TraceExporter exports sampled OpenCensus trace spans. It is ignored
+	// when the server uses OpenTelemetry.
 	TraceExporter trace.Exporter
 
 	// DefaultSamplingPolicy is a function that takes a
 	// trace.SamplingParameters struct and returns a true or false decision about
-	// whether it should be sampled and exported.
+	// whether it should be sampled and exported. It is ignored when the
+	// server uses OpenTelemetry.
 	DefaultSamplingPolicy trace.Sampler
 
+	// TracerProvider and MeterProvider switch the server from OpenCensus to
+	// OpenTelemetry: if either is set, requests are traced and the standard
+	// HTTP server metrics are recorded with OpenTelemetry, using the global
+	// provider for the one that is not set. See the otelserver package for
+	// providers that export with OTLP.
+	TracerProvider oteltrace.TracerProvider
+	MeterProvider  metric.MeterProvider
+
+	// Propagators extract the trace context from incoming requests when the
+	// server uses OpenTelemetry. It defaults to W3C Trace Context and
+	// Baggage.
+	Propagators propagation.TextMapPropagator
+
 	// Driver serves HTTP requests.
 	Driver driver.Server
+
+	// DrainTimeout bounds how long Shutdown waits for in-flight requests to
+	// finish before running the post-drain hooks. If it is zero, Shutdown
+	// waits until its context is done.
+	DrainTimeout time.Duration
+
+	// TLSConfig configures TLS for ListenAndServeTLS. Its GetCertificate
+	// field can provide certificates that change over time, for example
+	// from a *Certificate or from an autocert.Manager, whose TLSConfig
+	// method returns a suitable configuration. The Driver must implement
+	// driver.TLSConfigServer.
+	TLSConfig *tls.Config
+
+	// RateLimit, if set, limits the rate of requests, protecting the server
+	// from overload. Rejected requests are still logged and traced.
+	RateLimit *RateLimitOptions
+
+	// H2C enables HTTP/2 over cleartext TCP ("h2c") for ListenAndServe,
+	// for clients that connect with HTTP/2 prior knowledge or upgrade from
+	// HTTP/1.1. Use it for internal HTTP/2 traffic, such as gRPC-web
+	// behind a proxy that terminates TLS. Servers using TLS negotiate
+	// HTTP/2 without it.
+	H2C bool
+
+	// MetricsHandler, if set, is served at /metrics, for example to be
+	// scraped by Prometheus; see the promserver package. Like health
+	// checks, its requests are not logged, traced or rate limited.
+	MetricsHandler http.Handler
 }
 
 // New creates a new server. New(nil, nil) is the same as new(Server).
@@ -81,20 +156,37 @@ func New(h http.Handler, opts *Options) *Server {
 		srv.reqlog = opts.RequestLogger
 		srv.te = opts.TraceExporter
 		for _, c := range opts.HealthChecks {
-			srv.healthHandler.Add(c)
+			srv.health.Add(health.Readiness, c)
+		}
+		for _, c := range opts.LivenessChecks {
+			srv.health.Add(health.Liveness, c)
+		}
+		for group, checks := range opts.HealthCheckGroups {
+			for _, c := range checks {
+				srv.health.Add(group, c)
+			}
 		}
 		srv.sampler = opts.DefaultSamplingPolicy
+		srv.tp = opts.TracerProvider
+		srv.mp = opts.MeterProvider
+		srv.propagators = opts.Propagators
 		srv.driver = opts.Driver
+		srv.drainTimeout = opts.DrainTimeout
+		srv.tlsConfig = opts.TLSConfig
+		srv.rateLimit = opts.RateLimit
+		srv.h2c = opts.H2C
+		srv.metrics = opts.MetricsHandler
 	}
 	return srv
 }
 
 func (srv *Server) init() {
 	srv.once.Do(func() {
-		if srv.te != nil {
+		useOTel := srv.tp != nil || srv.mp != nil
+		if srv.te != nil && !useOTel {
 			trace.RegisterExporter(srv.te)
 		}
-		if srv.sampler != nil {
+		if srv.sampler != nil && !useOTel {
 			trace.ApplyConfig(trace.Config{DefaultSampler: srv.sampler})
 		}
 		if srv.driver == nil {
@@ -109,21 +201,68 @@ func (srv *Server) init() {
 		const healthPrefix = "/healthz/"
 
 		mux := http.NewServeMux()
-		mux.HandleFunc(healthPrefix+"liveness", health.HandleLive)
-		mux.Handle(healthPrefix+"readiness", &srv.healthHandler)
-		h := srv.handler
+		srv.health.Add(health.Readiness, health.CheckerFunc(srv.checkDraining))
+		srv.health.Handler(health.Liveness) // always served, even without checks
+		// The original endpoint names are aliases for the standard groups.
+		aliases := map[string]string{"liveness": health.Liveness, "readiness": health.Readiness}
+		for _, name := range srv.health.Names() {
+			mux.Handle(healthPrefix+name, srv.health.Handler(name))
+			delete(aliases, name)
+		}
+		for alias, group := range aliases {
+			mux.Handle(healthPrefix+alias, srv.health.Handler(group))
+		}
+		if srv.metrics != nil {
+			mux.Handle("/metrics", srv.metrics)
+		}
+		h := srv.trackInFlight(srv.handler)
+		if srv.rateLimit != nil {
+			h = newRateLimiter(srv.rateLimit).wrap(h)
+		}
 		if srv.reqlog != nil {
 			h = requestlog.NewHandler(srv.reqlog, h)
 		}
-		h = &ochttp.Handler{
-			Handler:          h,
-			IsPublicEndpoint: true,
+		if useOTel {
+			h = srv.otelHandler(h)
+		} else {
+			h = &ochttp.Handler{
+				Handler:          h,
+				IsPublicEndpoint: true,
+			}
 		}
 		mux.Handle("/", h)
 		srv.wrappedHandler = mux
+		if srv.h2c {
+			srv.wrappedHandler = h2c.NewHandler(mux, &http2.Server{})
+		}
 	})
 }
 
+// otelHandler wraps h with OpenTelemetry tracing and metrics. Like
+// ochttp.Handler, it names spans after the request path and treats the
+// server as a public endpoint, so incoming spans are linked rather than
+// used as parents.
+func (srv *Server) otelHandler(h http.Handler) http.Handler {
+	propagators := srv.propagators
+	if propagators == nil {
+		propagators = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+	opts := []otelhttp.Option{
+		otelhttp.WithPropagators(propagators),
+		otelhttp.WithPublicEndpoint(),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.URL.Path
+		}),
+	}
+	if srv.tp != nil {
+		opts = append(opts, otelhttp.WithTracerProvider(srv.tp))
+	}
+	if srv.mp != nil {
+		opts = append(opts, otelhttp.WithMeterProvider(srv.mp))
+	}
+	return otelhttp.NewHandler(h, "server", opts...)
+}
+
 // ListenAndServe is a wrapper to use wherever http.ListenAndServe is used.
 // It wraps the http.Handler provided to New with a handler that handles tracing and
 // request logging. If the handler is nil, then http.DefaultServeMux will be used.
@@ -137,7 +276,13 @@ func (srv *Server) ListenAndServe(addr string) error {
 // It wraps the http.Handler provided to New with a handler that handles tracing and
 // request logging. If the handler is nil, then http.DefaultServeMux will be used.
 // A configured Requestlogger will log all requests except HealthChecks.
+//
+// If Options.TLSConfig is set, certFile and keyFile may be empty; if they
+// are not, their certificate is added to the configuration's certificates.
 func (srv *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	if srv.tlsConfig != nil {
+		return srv.listenAndServeTLSConfig(addr, certFile, keyFile)
+	}
 	// Check if the driver implements the optional interface.
 	tlsDriver, ok := srv.driver.(driver.TLSServer)
 	if !ok {
@@ -147,12 +292,25 @@ func (srv *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
 	return tlsDriver.ListenAndServeTLS(addr, certFile, keyFile, srv.wrappedHandler)
 }
 
-// Shutdown gracefully shuts down the server without interrupting any active connections.
-func (srv *Server) Shutdown(ctx context.Context) error {
+func (srv *Server) listenAndServeTLSConfig(addr, certFile, keyFile string) error {
 	if srv.driver == nil {
-		return nil
+		srv.driver = NewDefaultDriver()
 	}
-	return srv.driver.Shutdown(ctx)
+	tlsDriver, ok := srv.driver.(driver.TLSConfigServer)
+	if !ok {
+		return fmt.Errorf("driver %T does not support ListenAndServeTLS with a TLS config", srv.driver)
+	}
+	config := srv.tlsConfig
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		config = config.Clone()
+		config.Certificates = append(config.Certificates, cert)
+	}
+	srv.init()
+	return tlsDriver.ListenAndServeTLSConfig(addr, config, srv.wrappedHandler)
 }
 
 // DefaultDriver implements the driver.Server interface. The zero value is a valid http.Server.
@@ -189,6 +347,15 @@ func (dd *DefaultDriver) ListenAndServeTLS(addr, certFile, keyFile string, h htt
 	return dd.Server.ListenAndServeTLS(certFile, keyFile)
 }
 
+// ListenAndServeTLSConfig sets the address, handler and TLS configuration on
+// DefaultDriver's http.Server, then calls ListenAndServeTLS on it.
+func (dd *DefaultDriver) ListenAndServeTLSConfig(addr string, config *tls.Config, h http.Handler) error {
+	dd.Server.Addr = addr
+	dd.Server.Handler = h
+	dd.Server.TLSConfig = config
+	return dd.Server.ListenAndServeTLS("", "")
+}
+
 // Shutdown gracefully shuts down the server without interrupting any active connections,
 // by calling Shutdown on DefaultDriver's http.Server
 func (dd *DefaultDriver) Shutdown(ctx context.Context) error {
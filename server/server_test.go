@@ -16,12 +16,20 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gocloud.dev/server/health"
 	"gocloud.dev/server/requestlog"
+	"golang.org/x/net/http2"
 )
 
 const (
@@ -126,6 +134,131 @@ func TestMiddleware(t *testing.T) {
 	}
 }
 
+func TestHealthGroups(t *testing.T) {
+	dbErr := errors.New("database unreachable")
+	td := new(testDriver)
+	s := New(http.NotFoundHandler(), &Options{
+		Driver:         td,
+		HealthChecks:   []health.Checker{health.CheckerFunc(func() error { return dbErr })},
+		LivenessChecks: []health.Checker{health.CheckerFunc(func() error { return nil })},
+		HealthCheckGroups: map[string][]health.Checker{
+			"startup":       {health.CheckerFunc(func() error { return nil })},
+			health.Liveness: {health.CheckerFunc(func() error { return nil })},
+		},
+	})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]int{
+		"/healthz/live":      http.StatusOK,
+		"/healthz/liveness":  http.StatusOK,
+		"/healthz/ready":     http.StatusInternalServerError,
+		"/healthz/readiness": http.StatusInternalServerError,
+		"/healthz/startup":   http.StatusOK,
+	} {
+		rr := httptest.NewRecorder()
+		td.handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != want {
+			t.Errorf("GET %s: got status %d, want %d", path, rr.Code, want)
+		}
+	}
+}
+
+func TestH2C(t *testing.T) {
+	td := new(testDriver)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	})
+	s := New(h, &Options{Driver: td, H2C: true})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(td.handler)
+	defer ts.Close()
+
+	// Connect with HTTP/2 prior knowledge, without TLS.
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != "HTTP/2.0" {
+		t.Errorf("got protocol %q, want HTTP/2.0", got)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	td := new(testDriver)
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "metrics")
+	})
+	s := New(http.NotFoundHandler(), &Options{Driver: td, MetricsHandler: metrics})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	td.handler.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rr.Body.String(); rr.Code != http.StatusOK || got != "metrics" {
+		t.Errorf("GET /metrics: got %d %q, want 200 %q", rr.Code, got, "metrics")
+	}
+}
+
+func TestOpenTelemetry(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	var logged *requestlog.Entry
+	tl := &testLogger{onLog: func(ent *requestlog.Entry) { logged = ent }}
+
+	td := new(testDriver)
+	s := New(http.NotFoundHandler(), &Options{Driver: td, RequestLogger: tl, TracerProvider: tp})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	// The incoming trace context is propagated with W3C Trace Context.
+	req := httptest.NewRequest("GET", "/foo", nil)
+	req.Header.Set("traceparent", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01")
+	td.handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Health checks are not traced.
+	td.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz/liveness", nil))
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if got, want := span.Name(), "/foo"; got != want {
+		t.Errorf("span name = %q, want %q", got, want)
+	}
+	// The server is a public endpoint, so the incoming span is a link rather
+	// than the parent.
+	if span.Parent().IsValid() {
+		t.Errorf("span has parent %v, want none", span.Parent())
+	}
+	if links := span.Links(); len(links) != 1 || links[0].SpanContext.TraceID().String() != "0102030405060708090a0b0c0d0e0f10" {
+		t.Errorf("span links = %v, want the incoming span", links)
+	}
+	if logged == nil {
+		t.Fatal("request was not logged")
+	}
+	if got, want := logged.TraceID.String(), span.SpanContext().TraceID().String(); got != want {
+		t.Errorf("logged TraceID = %s, want %s", got, want)
+	}
+	if got, want := logged.SpanID.String(), span.SpanContext().SpanID().String(); got != want {
+		t.Errorf("logged SpanID = %s, want %s", got, want)
+	}
+}
+
 type testDriverNoTLS string
 
 func (td *testDriverNoTLS) ListenAndServe(addr string, h http.Handler) error {
@@ -0,0 +1,175 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ShutdownHook is a function run by Server.Shutdown. See
+// Server.AddPreDrainHook and Server.AddPostDrainHook.
+type ShutdownHook func(ctx context.Context) error
+
+// errDraining is reported by the readiness check once Shutdown has started.
+var errDraining = errors.New("server is shutting down")
+
+// AddPreDrainHook adds a hook that Shutdown runs before it stops accepting
+// requests, after the /healthz/ready endpoint has started reporting the
+// server as unhealthy. Use it to deregister from load balancers or service
+// discovery, or to wait for them to notice the failing readiness check.
+//
+// Hooks run in the order they were added, and must be added before Shutdown
+// is called.
+func (srv *Server) AddPreDrainHook(hook ShutdownHook) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.preDrain = append(srv.preDrain, hook)
+}
+
+// AddPostDrainHook adds a hook that Shutdown runs once in-flight requests
+// have finished, or the drain timeout has passed. Use it to flush work that
+// requests may have queued, for example by calling Shutdown on a
+// *pubsub.Topic or Close on a *docstore.Collection.
+//
+// Hooks run in the order they were added, and must be added before Shutdown
+// is called.
+func (srv *Server) AddPostDrainHook(hook ShutdownHook) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.postDrain = append(srv.postDrain, hook)
+}
+
+// InFlight returns the number of requests the server is currently
+// handling, not counting health checks.
+func (srv *Server) InFlight() int {
+	return srv.inFlight.count()
+}
+
+// Shutdown gracefully shuts down the server without interrupting any
+// active connections:
+//
+//  1. /healthz/ready starts reporting the server as unhealthy;
+//  2. the pre-drain hooks run;
+//  3. the server stops accepting connections and waits for in-flight
+//     requests to finish, for at most Options.DrainTimeout if it is set;
+//  4. the post-drain hooks run.
+//
+// All hooks run even if an earlier step fails. Shutdown returns the errors
+// from every step, joined with errors.Join. If ctx is done before the
+// requests finish, step 3 fails with ctx.Err().
+func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.mu.Lock()
+	srv.draining = true
+	preDrain, postDrain := srv.preDrain, srv.postDrain
+	srv.mu.Unlock()
+
+	var errs []error
+	for _, hook := range preDrain {
+		errs = append(errs, hook(ctx))
+	}
+	errs = append(errs, srv.drain(ctx))
+	for _, hook := range postDrain {
+		errs = append(errs, hook(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// drain shuts down the driver and waits for in-flight requests to finish.
+func (srv *Server) drain(ctx context.Context) error {
+	if srv.drainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, srv.drainTimeout)
+		defer cancel()
+	}
+	if srv.driver != nil {
+		if err := srv.driver.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	// Drivers other than DefaultDriver may not wait for requests to finish.
+	return srv.inFlight.wait(ctx)
+}
+
+// checkDraining is the readiness check added by init.
+func (srv *Server) checkDraining() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.draining {
+		return errDraining
+	}
+	return nil
+}
+
+// trackInFlight wraps h so that its requests are counted by srv.inFlight.
+func (srv *Server) trackInFlight(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.inFlight.add()
+		defer srv.inFlight.done()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// inFlightCounter counts in-flight requests. The zero value is ready to
+// use.
+type inFlightCounter struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // closed when n drops to 0; nil if nobody is waiting
+}
+
+func (c *inFlightCounter) add() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}
+
+func (c *inFlightCounter) done() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n--
+	if c.n == 0 && c.idle != nil {
+		close(c.idle)
+		c.idle = nil
+	}
+}
+
+func (c *inFlightCounter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// wait waits until there are no in-flight requests or ctx is done.
+func (c *inFlightCounter) wait(ctx context.Context) error {
+	c.mu.Lock()
+	if c.n == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	if c.idle == nil {
+		c.idle = make(chan struct{})
+	}
+	idle := c.idle
+	c.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// drainDriver is a testDriver whose Shutdown succeeds without waiting for
+// requests to finish.
+type drainDriver struct {
+	testDriver
+}
+
+func (d *drainDriver) Shutdown(ctx context.Context) error { return nil }
+
+func TestShutdownHooks(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	td := new(drainDriver)
+	s := New(h, &Options{Driver: td})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	readiness := func() int {
+		rr := httptest.NewRecorder()
+		td.handler.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz/ready", nil))
+		return rr.Code
+	}
+	if got := readiness(); got != http.StatusOK {
+		t.Fatalf("readiness before Shutdown = %d, want %d", got, http.StatusOK)
+	}
+
+	go td.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-started
+	if got := s.InFlight(); got != 1 {
+		t.Errorf("InFlight() = %d, want 1", got)
+	}
+
+	var events []string
+	s.AddPreDrainHook(func(ctx context.Context) error {
+		events = append(events, "pre-drain 1")
+		if got := readiness(); got != http.StatusInternalServerError {
+			t.Errorf("readiness during Shutdown = %d, want %d", got, http.StatusInternalServerError)
+		}
+		// Let the in-flight request finish while Shutdown drains.
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+		}()
+		return nil
+	})
+	s.AddPreDrainHook(func(ctx context.Context) error {
+		events = append(events, "pre-drain 2")
+		return nil
+	})
+	s.AddPostDrainHook(func(ctx context.Context) error {
+		events = append(events, "post-drain")
+		if got := s.InFlight(); got != 0 {
+			t.Errorf("InFlight() after draining = %d, want 0", got)
+		}
+		return nil
+	})
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"pre-drain 1", "pre-drain 2", "post-drain"}, events); diff != "" {
+		t.Errorf("hooks: (-want +got)\n%s", diff)
+	}
+}
+
+func TestShutdownDrainTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	td := new(drainDriver)
+	s := New(h, &Options{Driver: td, DrainTimeout: 10 * time.Millisecond})
+	if err := s.ListenAndServe(":8080"); err != nil {
+		t.Fatal(err)
+	}
+	go td.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-started
+
+	hookErr := errors.New("flush failed")
+	postDrainCalled := false
+	s.AddPostDrainHook(func(ctx context.Context) error {
+		postDrainCalled = true
+		return hookErr
+	})
+	err := s.Shutdown(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown: got error %v, want it to include context.DeadlineExceeded", err)
+	}
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Shutdown: got error %v, want it to include the hook's error", err)
+	}
+	if !postDrainCalled {
+		t.Error("post-drain hook was not called after the drain timeout")
+	}
+}
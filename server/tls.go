@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"gocloud.dev/runtimevar"
+)
+
+// defaultReloadInterval is the default for CertificateOptions.ReloadInterval.
+const defaultReloadInterval = time.Minute
+
+// Certificate is a TLS certificate that is reloaded when its source
+// changes, so that rotated certificates are picked up without restarting
+// the server. Use its GetCertificate method in Options.TLSConfig:
+//
+//	cert, err := server.NewFileCertificate("cert.pem", "key.pem", nil)
+//	...
+//	srv := server.New(h, &server.Options{
+//		TLSConfig: &tls.Config{GetCertificate: cert.GetCertificate},
+//	})
+//	err = srv.ListenAndServeTLS(":443", "", "")
+type Certificate struct {
+	mu   sync.RWMutex
+	cert *tls.Certificate
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// CertificateOptions is the set of optional parameters for
+// NewFileCertificate and NewVariableCertificate.
+type CertificateOptions struct {
+	// ReloadInterval is how often NewFileCertificate checks its files for
+	// changes. It defaults to one minute. It is not used by
+	// NewVariableCertificate, which is notified of changes by its Variable.
+	ReloadInterval time.Duration
+
+	// ErrorHandler is called when a changed certificate cannot be loaded.
+	// The previous certificate stays in use. If nil, errors are ignored.
+	ErrorHandler func(error)
+}
+
+// NewFileCertificate loads a certificate from a pair of PEM-encoded files,
+// like tls.LoadX509KeyPair, and reloads it when either file's modification
+// time changes. opts may be nil.
+//
+// Call Close to stop watching the files.
+func NewFileCertificate(certFile, keyFile string, opts *CertificateOptions) (*Certificate, error) {
+	if opts == nil {
+		opts = &CertificateOptions{}
+	}
+	interval := opts.ReloadInterval
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	load := func() (*tls.Certificate, []time.Time, error) {
+		var mtimes []time.Time
+		for _, name := range []string{certFile, keyFile} {
+			fi, err := os.Stat(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			mtimes = append(mtimes, fi.ModTime())
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &cert, mtimes, nil
+	}
+	cert, mtimes, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c := newCertificate(cert)
+	ctx := c.start()
+	go func() {
+		defer close(c.done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			changed, err := filesChanged([]string{certFile, keyFile}, mtimes)
+			if err != nil {
+				handleCertError(opts, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			cert, newMtimes, err := load()
+			if err != nil {
+				// The files may be mid-rotation; try again next time.
+				handleCertError(opts, err)
+				continue
+			}
+			mtimes = newMtimes
+			c.set(cert)
+		}
+	}()
+	return c, nil
+}
+
+// filesChanged reports whether any of names has a modification time other
+// than the corresponding one in mtimes.
+func filesChanged(names []string, mtimes []time.Time) (bool, error) {
+	for i, name := range names {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return false, err
+		}
+		if !fi.ModTime().Equal(mtimes[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NewVariableCertificate loads a certificate from v, whose value must be a
+// []byte holding a PEM-encoded certificate chain followed by its
+// PEM-encoded private key, and reloads it whenever v changes. Use
+// runtimevar.BytesDecoder to open v. Variables backed by secret managers,
+// such as gcpsecretmanager and awssecretsmanager, keep private keys out of
+// the file system.
+//
+// NewVariableCertificate waits for v's first value until ctx is done. Call
+// Close to stop watching v; it does not close v, but it stops watching once
+// v is closed. opts may be nil.
+func NewVariableCertificate(ctx context.Context, v *runtimevar.Variable, opts *CertificateOptions) (*Certificate, error) {
+	if opts == nil {
+		opts = &CertificateOptions{}
+	}
+	snap, err := v.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificate(snap.Value)
+	if err != nil {
+		return nil, err
+	}
+	c := newCertificate(cert)
+	watchCtx := c.start()
+	go func() {
+		defer close(c.done)
+		last := snap.UpdateTime
+		for {
+			// Watch blocks until the value changes or watchCtx is done.
+			snap, err := v.Watch(watchCtx)
+			if watchCtx.Err() != nil || err == runtimevar.ErrClosed {
+				return
+			}
+			if err != nil {
+				handleCertError(opts, err)
+				continue
+			}
+			if snap.UpdateTime.Equal(last) {
+				continue
+			}
+			last = snap.UpdateTime
+			cert, err := parseCertificate(snap.Value)
+			if err != nil {
+				handleCertError(opts, err)
+				continue
+			}
+			c.set(cert)
+		}
+	}()
+	return c, nil
+}
+
+// parseCertificate parses a runtimevar value holding a PEM-encoded
+// certificate chain and private key.
+func parseCertificate(value interface{}) (*tls.Certificate, error) {
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("server: certificate variable has value of type %T, want []byte", value)
+	}
+	// tls.X509KeyPair skips blocks of the wrong type, so the same bytes can
+	// be passed for both the certificate and the key.
+	cert, err := tls.X509KeyPair(b, b)
+	if err != nil {
+		return nil, fmt.Errorf("server: invalid certificate variable: %w", err)
+	}
+	return &cert, nil
+}
+
+func newCertificate(cert *tls.Certificate) *Certificate {
+	return &Certificate{cert: cert, done: make(chan struct{})}
+}
+
+// start returns the context for the goroutine that reloads c, which must
+// close c.done when it returns.
+func (c *Certificate) start() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	return ctx
+}
+
+func (c *Certificate) set(cert *tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = cert
+}
+
+func handleCertError(opts *CertificateOptions, err error) {
+	if opts.ErrorHandler != nil {
+		opts.ErrorHandler(err)
+	}
+}
+
+// GetCertificate returns the current certificate. It has the signature of
+// tls.Config.GetCertificate.
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// Close stops reloading the certificate. GetCertificate keeps returning the
+// last certificate loaded.
+func (c *Certificate) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// NewAutocertManager returns an autocert.Manager that obtains certificates
+// for hosts from Let's Encrypt, accepting its terms of service, and caches
+// them in cacheDir so they survive restarts. email, which may be empty, is
+// given to Let's Encrypt to notify about problems with certificates.
+// Certificates are renewed automatically before they expire.
+//
+// Use the manager's TLSConfig method for Options.TLSConfig. Its default
+// TLS-ALPN-01 challenge requires the server to be reachable on port 443;
+// to use HTTP-01 challenges instead, also serve the manager's HTTPHandler
+// on port 80.
+func NewAutocertManager(cacheDir, email string, hosts ...string) (*autocert.Manager, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("server: NewAutocertManager requires at least one host")
+	}
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Email:      email,
+	}, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gocloud.dev/runtimevar"
+	"gocloud.dev/runtimevar/filevar"
+)
+
+// newTestCert returns a PEM-encoded self-signed certificate and private key
+// with the given common name.
+func newTestCert(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+// commonName returns the common name of the leaf certificate from c.
+func commonName(t *testing.T, c *Certificate) string {
+	t.Helper()
+	cert, err := c.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+// waitForCommonName waits until c's certificate has the given common name.
+func waitForCommonName(t *testing.T, c *Certificate, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for commonName(t, c) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("certificate was not reloaded: got %q, want %q", commonName(t, c), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func writeFile(t *testing.T, name string, data []byte, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(name, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM, keyPEM := newTestCert(t, "first")
+	mtime := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, certPEM, mtime)
+	writeFile(t, keyFile, keyPEM, mtime)
+
+	c, err := NewFileCertificate(certFile, keyFile, &CertificateOptions{ReloadInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commonName(t, c); got != "first" {
+		t.Errorf("got certificate %q, want %q", got, "first")
+	}
+
+	certPEM, keyPEM = newTestCert(t, "second")
+	writeFile(t, keyFile, keyPEM, mtime.Add(time.Second))
+	writeFile(t, certFile, certPEM, mtime.Add(time.Second))
+	waitForCommonName(t, c, "second")
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The certificate stays usable after Close.
+	if got := commonName(t, c); got != "second" {
+		t.Errorf("after Close, got certificate %q, want %q", got, "second")
+	}
+
+	if _, err := NewFileCertificate(filepath.Join(dir, "missing.pem"), keyFile, nil); err == nil {
+		t.Error("NewFileCertificate with a missing file: got nil error, want error")
+	}
+}
+
+func TestVariableCertificate(t *testing.T) {
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "cert.pem")
+	certPEM, keyPEM := newTestCert(t, "first")
+	writeFile(t, name, append(certPEM, keyPEM...), time.Now())
+
+	v, err := filevar.OpenVariable(name, runtimevar.BytesDecoder, &filevar.Options{WaitDuration: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	c, err := NewVariableCertificate(ctx, v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if got := commonName(t, c); got != "first" {
+		t.Errorf("got certificate %q, want %q", got, "first")
+	}
+
+	certPEM, keyPEM = newTestCert(t, "second")
+	writeFile(t, name, append(certPEM, keyPEM...), time.Now().Add(time.Second))
+	waitForCommonName(t, c, "second")
+}
+
+func TestVariableCertificateInvalid(t *testing.T) {
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "cert.pem")
+	certPEM, _ := newTestCert(t, "nokey")
+	writeFile(t, name, certPEM, time.Now())
+
+	v, err := filevar.OpenVariable(name, runtimevar.BytesDecoder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if _, err := NewVariableCertificate(ctx, v, nil); err == nil {
+		t.Error("got nil error for a variable without a private key, want error")
+	}
+}
+
+// tlsConfigDriver is a testDriver that supports TLS configs.
+type tlsConfigDriver struct {
+	testDriver
+	config *tls.Config
+}
+
+func (d *tlsConfigDriver) ListenAndServeTLSConfig(addr string, config *tls.Config, h http.Handler) error {
+	d.listenAndServeCalled = true
+	d.config = config
+	d.handler = h
+	return nil
+}
+
+func TestListenAndServeTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM, keyPEM := newTestCert(t, "static")
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+
+	config := &tls.Config{MinVersion: tls.VersionTLS13}
+	td := new(tlsConfigDriver)
+	s := New(http.NotFoundHandler(), &Options{Driver: td, TLSConfig: config})
+	if err := s.ListenAndServeTLS(":8443", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if td.config != config {
+		t.Errorf("driver got config %p, want %p", td.config, config)
+	}
+	if td.handler == nil {
+		t.Error("tlsConfigDriver must set handler, got nil")
+	}
+
+	// A certificate file pair is added to a copy of the config.
+	if err := s.ListenAndServeTLS(":8443", certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if len(td.config.Certificates) != 1 || td.config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("driver got config with %d certificates and MinVersion %x, want 1 and %x", len(td.config.Certificates), td.config.MinVersion, tls.VersionTLS13)
+	}
+	if len(config.Certificates) != 0 {
+		t.Error("ListenAndServeTLS modified Options.TLSConfig")
+	}
+
+	// Drivers must support TLS configs.
+	s = New(http.NotFoundHandler(), &Options{Driver: new(testDriver), TLSConfig: config})
+	if err := s.ListenAndServeTLS(":8443", "", ""); err == nil {
+		t.Error("got nil error from a driver without TLS config support, want error")
+	}
+}
+
+func TestNewAutocertManager(t *testing.T) {
+	if _, err := NewAutocertManager(t.TempDir(), ""); err == nil {
+		t.Error("got nil error with no hosts, want error")
+	}
+	m, err := NewAutocertManager(t.TempDir(), "admin@example.com", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.HostPolicy(context.Background(), "example.com"); err != nil {
+		t.Errorf("HostPolicy(example.com): %v", err)
+	}
+	if err := m.HostPolicy(context.Background(), "other.example.com"); err == nil {
+		t.Error("HostPolicy(other.example.com): got nil error, want error")
+	}
+}
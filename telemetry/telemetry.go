@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry configures OpenTelemetry for the portable types of the
+// Go Cloud Development Kit: blob, docstore, pubsub, runtimevar, secrets and
+// secretstore.
+//
+// The portable types report to the global OpenTelemetry tracer and meter
+// providers, so exporters are configured once per process, either with Init
+// or with otel.SetTracerProvider and otel.SetMeterProvider. They may be
+// configured after the portable types are created.
+//
+// # Spans
+//
+// Each method call that reaches the driver is a client span named by the
+// package and method, such as "gocloud.dev/blob.NewReader", with the
+// attributes:
+//   - gocdk.package: the package, such as "gocloud.dev/blob".
+//   - gocdk.method: the method, such as "NewReader".
+//   - gocdk.provider: the driver package, such as "gocloud.dev/blob/s3blob".
+//   - gocdk.status: the gcerrors.ErrorCode of the call, such as "OK".
+//   - error.type: for failed calls, the same as gocdk.status.
+//
+// Drivers add the OpenTelemetry semantic convention attributes of the
+// resource they call, where they apply:
+//   - db.system, db.collection.name and db.namespace: for docstore
+//     collections, such as "dynamodb" and the table name.
+//   - messaging.system, messaging.destination.name and
+//     messaging.destination.subscription.name: for pubsub topics and
+//     subscriptions, such as "gcp_pubsub" and the topic path.
+//
+// Calls that the portable type retries have a "retry" span event for each
+// failed attempt, with the gocdk.retry attribute, the number of the retry,
+// and the gocdk.status and error.type of the attempt.
+//
+// # Metrics
+//
+// The metrics are reported by a meter named by the package, and their names
+// start with "gocdk." and the package path after "gocloud.dev/":
+//   - gocdk.<package>.duration: a histogram of call durations in seconds,
+//     with the gocdk.method, gocdk.provider and gocdk.status attributes;
+//     for example, gocdk.blob.duration.
+//   - gocdk.<package>.retries: the number of retried calls, with the
+//     gocdk.method, gocdk.provider and gocdk.status attributes, the status
+//     of the failed attempt; for example, gocdk.pubsub.retries.
+//   - gocdk.blob.bytes_read and gocdk.blob.bytes_written: the bytes read
+//     and written by blob Readers and Writers, by gocdk.provider.
+//   - gocdk.runtimevar.value_changes: the number of changes of Variable
+//     values, by gocdk.provider.
+//   - gocdk.pubsub.dead_lettered and gocdk.pubsub.dead_letter_errors: the
+//     messages that Subscriptions returned by NewDeadLetterSubscription sent
+//     to their dead-letter topic, and failed to send, by gocdk.provider.
+//   - gocdk.docstore.awsdynamodb.throttles and
+//     gocdk.docstore.awsdynamodb.retries: the DynamoDB requests throttled and
+//     retried, by the rpc.method attribute, the DynamoDB operation.
+//
+// The OpenCensusViews of the portable types continue to report the same
+// calls to OpenCensus.
+package telemetry // import "gocloud.dev/telemetry"
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Options configures Init.
+type Options struct {
+	// SpanExporter exports spans in batches. If nil, spans are exported
+	// with OTLP over gRPC, configured by the standard OpenTelemetry
+	// environment variables, such as OTEL_EXPORTER_OTLP_ENDPOINT.
+	SpanExporter sdktrace.SpanExporter
+
+	// MetricReader collects metrics. If nil, metrics are exported
+	// periodically with OTLP over gRPC, configured by the standard
+	// OpenTelemetry environment variables.
+	MetricReader sdkmetric.Reader
+
+	// Resource describes the process. If nil, resource.Default() is used,
+	// which reads OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES.
+	Resource *resource.Resource
+}
+
+// Init creates tracer and meter providers with the exporters in opts, and
+// sets them as the global OpenTelemetry providers, along with a W3C trace
+// context and baggage propagator. A nil opts uses the defaults.
+//
+// Init should be called once, early in main. The returned function shuts
+// down the providers, flushing pending spans and metrics; call it before
+// the process exits.
+func Init(ctx context.Context, opts *Options) (shutdown func(context.Context) error, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	res := opts.Resource
+	if res == nil {
+		res = resource.Default()
+	}
+	spanExp := opts.SpanExporter
+	if spanExp == nil {
+		if spanExp, err = otlptracegrpc.New(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reader := opts.MetricReader
+	if reader == nil {
+		exp, err := otlpmetricgrpc.New(ctx)
+		if err != nil {
+			spanExp.Shutdown(ctx)
+			return nil, err
+		}
+		reader = sdkmetric.NewPeriodicReader(exp)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExp),
+		sdktrace.WithResource(res),
+	)
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}, nil
+}
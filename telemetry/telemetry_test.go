@@ -0,0 +1,103 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gocloud.dev/blob/memblob"
+	"gocloud.dev/telemetry"
+)
+
+// keptSpans is an in-memory span exporter whose spans survive Shutdown.
+type keptSpans struct{ *tracetest.InMemoryExporter }
+
+func (keptSpans) Shutdown(context.Context) error { return nil }
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+	spans := keptSpans{tracetest.NewInMemoryExporter()}
+	reader := sdkmetric.NewManualReader()
+	// The bucket is opened before Init, which must not matter.
+	b := memblob.OpenBucket(nil)
+	defer b.Close()
+	shutdown, err := telemetry.Init(ctx, &telemetry.Options{SpanExporter: spans, MetricReader: reader})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.WriteAll(ctx, "key", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadAll(ctx, "missing"); err == nil {
+		t.Fatal("ReadAll of missing key: got nil error")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]bool{}
+	for _, s := range spans.GetSpans() {
+		got[s.Name] = true
+		if s.Name != "gocloud.dev/blob.NewRangeReader" {
+			continue
+		}
+		if s.Status.Code != codes.Error {
+			t.Errorf("%s: got status %v, want Error", s.Name, s.Status.Code)
+		}
+		if !hasAttr(s.Attributes, attribute.String("gocdk.status", "NotFound")) ||
+			!hasAttr(s.Attributes, attribute.String("gocdk.provider", "gocloud.dev/blob/memblob")) {
+			t.Errorf("%s: got attributes %v, want status NotFound and provider memblob", s.Name, s.Attributes)
+		}
+	}
+	for _, name := range []string{"gocloud.dev/blob.NewWriter", "gocloud.dev/blob.NewRangeReader"} {
+		if !got[name] {
+			t.Errorf("no span named %s; got %v", name, got)
+		}
+	}
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	if h, ok := metrics["gocdk.blob.duration"].(metricdata.Histogram[float64]); !ok || len(h.DataPoints) != 2 {
+		t.Errorf("gocdk.blob.duration: got %+v, want a histogram for NewWriter and NewRangeReader", metrics["gocdk.blob.duration"])
+	}
+	if s, ok := metrics["gocdk.blob.bytes_written"].(metricdata.Sum[int64]); !ok || len(s.DataPoints) != 1 || s.DataPoints[0].Value != 5 {
+		t.Errorf("gocdk.blob.bytes_written: got %+v, want 5", metrics["gocdk.blob.bytes_written"])
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
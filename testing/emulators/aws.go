@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulators
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dyn "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The fake credentials that the AWS emulators are started with.
+const (
+	awsAccessKeyID     = "gocloud"
+	awsSecretAccessKey = "gocloud-secret"
+)
+
+// AWS is a server in a container that serves AWS APIs: DynamoDB Local,
+// MinIO or LocalStack.
+type AWS struct {
+	// Endpoint is the URL of the server, such as "http://127.0.0.1:49153".
+	Endpoint string
+	// Region is the region to make requests in.
+	Region string
+}
+
+// StartDynamoDB starts DynamoDB Local.
+func StartDynamoDB(ctx context.Context, t testing.TB) *AWS {
+	t.Helper()
+	c := start(ctx, t, &spec{
+		image: "amazon/dynamodb-local",
+		port:  "8000",
+		cmd:   []string{"-jar", "DynamoDBLocal.jar", "-inMemory", "-sharedDb"},
+		ready: httpReady("/"),
+	})
+	return newAWS(t, c)
+}
+
+// StartMinIO starts a MinIO server, which serves the S3 API.
+func StartMinIO(ctx context.Context, t testing.TB) *AWS {
+	t.Helper()
+	c := start(ctx, t, &spec{
+		image: "minio/minio",
+		port:  "9000",
+		env:   []string{"MINIO_ROOT_USER=" + awsAccessKeyID, "MINIO_ROOT_PASSWORD=" + awsSecretAccessKey},
+		cmd:   []string{"server", "/data"},
+		ready: httpReady("/minio/health/ready"),
+	})
+	return newAWS(t, c)
+}
+
+// StartLocalStack starts LocalStack, which serves the APIs of S3, DynamoDB,
+// SNS, SQS, KMS, Secrets Manager and Parameter Store, among others.
+func StartLocalStack(ctx context.Context, t testing.TB) *AWS {
+	t.Helper()
+	c := start(ctx, t, &spec{
+		image: "localstack/localstack",
+		port:  "4566",
+		ready: httpReady("/_localstack/health"),
+	})
+	return newAWS(t, c)
+}
+
+// newAWS returns an AWS for the server in c, and sets the fake credentials
+// in the environment so that the default URL openers use them.
+func newAWS(t testing.TB, c *container) *AWS {
+	t.Setenv("AWS_ACCESS_KEY_ID", awsAccessKeyID)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", awsSecretAccessKey)
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	return &AWS{Endpoint: "http://" + c.addr, Region: "us-east-1"}
+}
+
+// BucketURL returns the URL of an S3 bucket, for gocloud.dev/blob/s3blob.
+func (a *AWS) BucketURL(bucket string) string {
+	q := url.Values{
+		"awssdk":             {"v2"},
+		"endpoint":           {a.Endpoint},
+		"region":             {a.Region},
+		"hostname_immutable": {"true"},
+	}
+	return "s3://" + bucket + "?" + q.Encode()
+}
+
+// CollectionURL returns the URL of a DynamoDB table, for
+// gocloud.dev/docstore/awsdynamodb. sortKey may be empty.
+func (a *AWS) CollectionURL(table, partitionKey, sortKey string) string {
+	q := url.Values{
+		"endpoint":      {a.Endpoint},
+		"region":        {a.Region},
+		"partition_key": {partitionKey},
+		"allow_scans":   {"true"},
+	}
+	if sortKey != "" {
+		q.Set("sort_key", sortKey)
+	}
+	return "dynamodb://" + table + "?" + q.Encode()
+}
+
+// Session returns an AWS SDK V1 session for the server. S3 requests made
+// with it use path-style addressing, which the emulators require.
+func (a *AWS) Session() (*session.Session, error) {
+	return session.NewSession(&aws.Config{
+		Endpoint:         aws.String(a.Endpoint),
+		Region:           aws.String(a.Region),
+		Credentials:      credentials.NewStaticCredentials(awsAccessKeyID, awsSecretAccessKey, ""),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+}
+
+// Config returns an AWS SDK V2 config for the server. S3 clients made from
+// it must set s3.Options.UsePathStyle, which the emulators require.
+func (a *AWS) Config() awsv2.Config {
+	return awsv2.Config{
+		Region:       a.Region,
+		Credentials:  credentialsv2.NewStaticCredentialsProvider(awsAccessKeyID, awsSecretAccessKey, ""),
+		BaseEndpoint: awsv2.String(a.Endpoint),
+	}
+}
+
+// CreateBucket creates an S3 bucket.
+func (a *AWS) CreateBucket(ctx context.Context, t testing.TB, bucket string) {
+	t.Helper()
+	client := s3v2.NewFromConfig(a.Config(), func(o *s3v2.Options) {
+		o.UsePathStyle = true
+	})
+	if _, err := client.CreateBucket(ctx, &s3v2.CreateBucketInput{Bucket: awsv2.String(bucket)}); err != nil {
+		t.Fatalf("creating bucket %q: %v", bucket, err)
+	}
+}
+
+// CreateTable creates a DynamoDB table whose keys are strings. sortKey may
+// be empty.
+func (a *AWS) CreateTable(ctx context.Context, t testing.TB, table, partitionKey, sortKey string) {
+	t.Helper()
+	sess, err := a.Session()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := dyn.New(sess)
+	in := &dyn.CreateTableInput{
+		TableName:   aws.String(table),
+		BillingMode: aws.String(dyn.BillingModePayPerRequest),
+		AttributeDefinitions: []*dyn.AttributeDefinition{
+			{AttributeName: aws.String(partitionKey), AttributeType: aws.String(dyn.ScalarAttributeTypeS)},
+		},
+		KeySchema: []*dyn.KeySchemaElement{
+			{AttributeName: aws.String(partitionKey), KeyType: aws.String(dyn.KeyTypeHash)},
+		},
+	}
+	if sortKey != "" {
+		in.AttributeDefinitions = append(in.AttributeDefinitions,
+			&dyn.AttributeDefinition{AttributeName: aws.String(sortKey), AttributeType: aws.String(dyn.ScalarAttributeTypeS)})
+		in.KeySchema = append(in.KeySchema,
+			&dyn.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: aws.String(dyn.KeyTypeRange)})
+	}
+	if _, err := db.CreateTableWithContext(ctx, in); err != nil {
+		t.Fatalf("creating table %q: %v", table, err)
+	}
+	if err := db.WaitUntilTableExistsWithContext(ctx, &dyn.DescribeTableInput{TableName: aws.String(table)}); err != nil {
+		t.Fatalf("creating table %q: %v", table, err)
+	}
+}
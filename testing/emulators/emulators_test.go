@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulators
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/url"
+	"testing"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/s3blob"
+	"gocloud.dev/docstore"
+	_ "gocloud.dev/docstore/awsdynamodb"
+)
+
+type person struct {
+	ID   string
+	Name string
+}
+
+// testCollection writes and reads a document in coll.
+func testCollection(ctx context.Context, t *testing.T, coll *docstore.Collection) {
+	t.Helper()
+	defer coll.Close()
+	if err := coll.Put(ctx, &person{ID: "a", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	got := &person{ID: "a"}
+	if err := coll.Get(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Alice" {
+		t.Errorf("got name %q, want Alice", got.Name)
+	}
+}
+
+// testBucket writes and reads a blob in the bucket at bucketURL.
+func testBucket(ctx context.Context, t *testing.T, bucketURL string) {
+	t.Helper()
+	b, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if err := b.WriteAll(ctx, "key", []byte("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.ReadAll(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("got %q, want hello", got)
+	}
+}
+
+func TestDynamoDB(t *testing.T) {
+	ctx := context.Background()
+	dyn := StartDynamoDB(ctx, t)
+	dyn.CreateTable(ctx, t, "people", "ID", "")
+	coll, err := docstore.OpenCollection(ctx, dyn.CollectionURL("people", "ID", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCollection(ctx, t, coll)
+}
+
+func TestMinIO(t *testing.T) {
+	ctx := context.Background()
+	minio := StartMinIO(ctx, t)
+	minio.CreateBucket(ctx, t, "test-bucket")
+	testBucket(ctx, t, minio.BucketURL("test-bucket"))
+}
+
+func TestLocalStack(t *testing.T) {
+	ctx := context.Background()
+	ls := StartLocalStack(ctx, t)
+	ls.CreateBucket(ctx, t, "test-bucket")
+	testBucket(ctx, t, ls.BucketURL("test-bucket"))
+	ls.CreateTable(ctx, t, "people", "ID", "")
+	coll, err := docstore.OpenCollection(ctx, ls.CollectionURL("people", "ID", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCollection(ctx, t, coll)
+}
+
+func TestFirestore(t *testing.T) {
+	ctx := context.Background()
+	fs := StartFirestore(ctx, t)
+	u, err := url.Parse(fs.CollectionURL("people", "ID"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll, err := fs.Opener.OpenCollectionURL(ctx, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCollection(ctx, t, coll)
+}
+
+func TestRabbitMQ(t *testing.T) {
+	ctx := context.Background()
+	r := StartRabbitMQ(ctx, t)
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	r := StartRedis(ctx, t)
+	conn, err := net.Dial("tcp", r.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "+PONG\r\n" {
+		t.Errorf("got %q, want +PONG", got)
+	}
+}
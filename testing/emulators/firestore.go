@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Cloud Development Kit Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulators
+
+import (
+	"context"
+	"testing"
+
+	"gocloud.dev/docstore/gcpfirestore"
+)
+
+// FirestoreProject is the project of the Firestore emulator's collections.
+// The emulator accepts any project.
+const FirestoreProject = "gocloud-test"
+
+// Firestore is a Firestore emulator in a container.
+type Firestore struct {
+	// Host is the host:port of the emulator.
+	Host string
+	// Opener opens collections in the emulator.
+	Opener *gcpfirestore.URLOpener
+}
+
+// StartFirestore starts a Firestore emulator, and sets
+// FIRESTORE_EMULATOR_HOST so that gcpfirestore.Dial connects to it.
+//
+// The default URL opener of gcpfirestore dials once per process, so
+// CollectionURL works with docstore.OpenCollection only if the first
+// firestore URL that the process opens is in this emulator. Use Opener
+// to open collections in later emulators.
+func StartFirestore(ctx context.Context, t testing.TB) *Firestore {
+	t.Helper()
+	c := start(ctx, t, &spec{
+		image: "gcr.io/google.com/cloudsdktool/google-cloud-cli:emulators",
+		port:  "8080",
+		cmd:   []string{"gcloud", "emulators", "firestore", "start", "--host-port=0.0.0.0:8080"},
+		ready: httpReady("/"),
+	})
+	t.Setenv("FIRESTORE_EMULATOR_HOST", c.addr)
+	client, done, err := gcpfirestore.Dial(ctx, nil)
+	if err != nil {
+		t.Fatalf("dialing the Firestore emulator: %v", err)
+	}
+	t.Cleanup(done)
+	return &Firestore{Host: c.addr, Opener: &gcpfirestore.URLOpener{Client: client}}
+}
+
+// CollectionURL returns the URL of a collection whose documents are keyed
+// by nameField.
+func (f *Firestore) CollectionURL(collection, nameField string) string {
+	return "firestore://projects/" + FirestoreProject + "/databases/(default)/documents/" + collection + "?name_field=" + nameField
+}